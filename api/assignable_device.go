@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type AssignableDeviceState int
+
+const (
+	AssignableDeviceState_Host       AssignableDeviceState = 0
+	AssignableDeviceState_Dismounted AssignableDeviceState = 1
+	AssignableDeviceState_Assigned   AssignableDeviceState = 2
+)
+
+var AssignableDeviceState_name = map[AssignableDeviceState]string{
+	AssignableDeviceState_Host:       "Host",
+	AssignableDeviceState_Dismounted: "Dismounted",
+	AssignableDeviceState_Assigned:   "Assigned",
+}
+
+var AssignableDeviceState_value = map[string]AssignableDeviceState{
+	"host":       AssignableDeviceState_Host,
+	"dismounted": AssignableDeviceState_Dismounted,
+	"assigned":   AssignableDeviceState_Assigned,
+}
+
+func (x AssignableDeviceState) String() string {
+	return AssignableDeviceState_name[x]
+}
+
+func ToAssignableDeviceState(x string) AssignableDeviceState {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return AssignableDeviceState(integerValue)
+	}
+
+	return AssignableDeviceState_value[strings.ToLower(x)]
+}
+
+func (d *AssignableDeviceState) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *AssignableDeviceState) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = AssignableDeviceState(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToAssignableDeviceState(s)
+	return nil
+}
+
+func FlattenAssignableDevices(assignableDevices *[]AssignableDevice) []interface{} {
+	if assignableDevices == nil || len(*assignableDevices) < 1 {
+		return nil
+	}
+
+	flattenedAssignableDevices := make([]interface{}, 0)
+
+	for _, assignableDevice := range *assignableDevices {
+		flattenedAssignableDevice := make(map[string]interface{})
+		flattenedAssignableDevice["instance_id"] = assignableDevice.InstanceId
+		flattenedAssignableDevice["friendly_name"] = assignableDevice.FriendlyName
+		flattenedAssignableDevice["class"] = assignableDevice.Class
+		flattenedAssignableDevice["location_path"] = assignableDevice.LocationPath
+		flattenedAssignableDevice["state"] = assignableDevice.State.String()
+		flattenedAssignableDevice["vm_name"] = assignableDevice.VmName
+		flattenedAssignableDevices = append(flattenedAssignableDevices, flattenedAssignableDevice)
+	}
+
+	return flattenedAssignableDevices
+}
+
+type AssignableDevice struct {
+	InstanceId   string
+	FriendlyName string
+	Class        string
+	LocationPath string
+	State        AssignableDeviceState
+	VmName       string
+}
+
+type HypervAssignableDeviceClient interface {
+	GetAssignableDevices(ctx context.Context) (result []AssignableDevice, err error)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Names of HyperV objects, such as virtual machines and switches, and windows paths are case-insensitive, so they are
+// compared without case to avoid perpetual diffs when HyperV reports them in a different case than configured.
+
+// NormalizeWindowsPath returns the path in lower case with backslashes and without a trailing backslash, unless it is
+// the root of a drive, so equivalent paths can be compared
+func NormalizeWindowsPath(path string) string {
+	normalizedPath := strings.ToLower(strings.ReplaceAll(path, "/", `\`))
+	if len(normalizedPath) > 3 {
+		normalizedPath = strings.TrimRight(normalizedPath, `\`)
+	}
+
+	return normalizedPath
+}
+
+func EqualWindowsPaths(a string, b string) bool {
+	return NormalizeWindowsPath(a) == NormalizeWindowsPath(b)
+}
+
+func DiffSuppressCaseInsensitive(key, old, new string, d *schema.ResourceData) bool {
+	log.Printf("[DEBUG] '[%s]' Comparing old value '[%v]' with new value '[%v]' ", key, old, new)
+	return strings.EqualFold(old, new)
+}
+
+func DiffSuppressWindowsPath(key, old, new string, d *schema.ResourceData) bool {
+	log.Printf("[DEBUG] '[%s]' Comparing old value '[%v]' with new value '[%v]' ", key, old, new)
+	return EqualWindowsPaths(old, new)
+}
+
+// PreferConfiguredCase returns the values read from HyperV, using the case of the values already in the list or set
+// attribute of the resource where they only differ by case, as elements of lists and sets can not be diff suppressed
+func PreferConfiguredCase(d *schema.ResourceData, key string, values []string) []string {
+	configuredValues := make([]string, 0)
+	switch v := d.Get(key).(type) {
+	case []interface{}:
+		for _, configuredValue := range v {
+			configuredValues = append(configuredValues, configuredValue.(string))
+		}
+	case *schema.Set:
+		for _, configuredValue := range v.List() {
+			configuredValues = append(configuredValues, configuredValue.(string))
+		}
+	}
+
+	result := make([]string, 0)
+	for _, value := range values {
+		for _, configuredValue := range configuredValues {
+			if strings.EqualFold(value, configuredValue) || EqualWindowsPaths(value, configuredValue) {
+				value = configuredValue
+				break
+			}
+		}
+
+		result = append(result, value)
+	}
+
+	return result
+}
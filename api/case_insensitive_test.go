@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestNormalizeWindowsPath(t *testing.T) {
+	cases := map[string]string{
+		`C:\VMs\Web`:             `c:\vms\web`,
+		`C:\VMs\Web\`:            `c:\vms\web`,
+		`C:/VMs/Web`:             `c:\vms\web`,
+		`C:\`:                    `c:\`,
+		`\\Server\Share\VMs\\`:   `\\server\share\vms`,
+		`c:\ProgramData\Hyper-V`: `c:\programdata\hyper-v`,
+	}
+
+	for path, expected := range cases {
+		if actual := NormalizeWindowsPath(path); actual != expected {
+			t.Errorf("NormalizeWindowsPath(%q) = %q, expected %q", path, actual, expected)
+		}
+	}
+}
+
+func TestEqualWindowsPaths(t *testing.T) {
+	if !EqualWindowsPaths(`C:\VMs`, `c:\vms\`) {
+		t.Errorf("expected paths that only differ by case and trailing backslash to be equal")
+	}
+
+	if EqualWindowsPaths(`C:\VMs`, `C:\VMs2`) {
+		t.Errorf("expected different paths not to be equal")
+	}
+}
+
+func TestDiffSuppressCaseInsensitive(t *testing.T) {
+	if !DiffSuppressCaseInsensitive("name", "Web", "web", nil) {
+		t.Errorf("expected names that only differ by case to be suppressed")
+	}
+
+	if DiffSuppressCaseInsensitive("name", "web", "web2", nil) {
+		t.Errorf("expected different names not to be suppressed")
+	}
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+)
+
+type ClusterPlacement struct {
+	ClusterName                string
+	Node                       string
+	NodeAvailableMemoryBytes   int64
+	SharedVolumePath           string
+	SharedVolumeFreeSpaceBytes int64
+}
+
+type HypervClusterClient interface {
+	GetClusterPlacement(ctx context.Context, clusterName string, memoryRequiredBytes int64) (result ClusterPlacement, err error)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type ClusterGroupPriority int
+
+const (
+	ClusterGroupPriority_NoAutoStart ClusterGroupPriority = 0
+	ClusterGroupPriority_Low         ClusterGroupPriority = 1000
+	ClusterGroupPriority_Medium      ClusterGroupPriority = 2000
+	ClusterGroupPriority_High        ClusterGroupPriority = 3000
+)
+
+var ClusterGroupPriority_name = map[ClusterGroupPriority]string{
+	ClusterGroupPriority_NoAutoStart: "NoAutoStart",
+	ClusterGroupPriority_Low:         "Low",
+	ClusterGroupPriority_Medium:      "Medium",
+	ClusterGroupPriority_High:        "High",
+}
+
+var ClusterGroupPriority_value = map[string]ClusterGroupPriority{
+	"noautostart": ClusterGroupPriority_NoAutoStart,
+	"low":         ClusterGroupPriority_Low,
+	"medium":      ClusterGroupPriority_Medium,
+	"high":        ClusterGroupPriority_High,
+}
+
+func (x ClusterGroupPriority) String() string {
+	return ClusterGroupPriority_name[x]
+}
+
+func ToClusterGroupPriority(x string) ClusterGroupPriority {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return ClusterGroupPriority(integerValue)
+	}
+
+	return ClusterGroupPriority_value[strings.ToLower(x)]
+}
+
+func (d *ClusterGroupPriority) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *ClusterGroupPriority) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = ClusterGroupPriority(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToClusterGroupPriority(s)
+	return nil
+}
+
+type ClusterVmRole struct {
+	VmName          string
+	Name            string
+	PreferredOwners []string
+	Priority        ClusterGroupPriority
+	OwnerNode       string
+	State           string
+}
+
+type HypervClusterVmRoleClient interface {
+	CreateClusterVmRole(ctx context.Context, vmName string, name string, preferredOwners []string, priority ClusterGroupPriority) (result ClusterVmRole, err error)
+	GetClusterVmRole(ctx context.Context, name string) (result ClusterVmRole, err error)
+	UpdateClusterVmRole(ctx context.Context, name string, preferredOwners []string, priority ClusterGroupPriority) (err error)
+	DeleteClusterVmRole(ctx context.Context, name string) (err error)
+}
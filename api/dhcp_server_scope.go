@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DhcpServerScope is an IPv4 scope of the Windows DHCP Server role on the HyperV host machine, to hand out addresses
+// to virtual machines on internal and NAT switches. When SwitchName is set the DHCP server is bound to the host network
+// adapter of the switch, BoundSwitchNames are all the switches the DHCP server is bound to.
+type DhcpServerScope struct {
+	ScopeId              string
+	Name                 string
+	Description          string
+	StartRange           string
+	EndRange             string
+	SubnetMask           string
+	LeaseDurationSeconds int64
+	Router               string
+	DnsServers           []string
+	DnsDomain            string
+	SwitchName           string
+	BoundSwitchNames     []string
+}
+
+// IsBoundToSwitch returns whether the DHCP server hands out addresses on the host network adapter of the switch
+func (s *DhcpServerScope) IsBoundToSwitch(switchName string) bool {
+	for _, boundSwitchName := range s.BoundSwitchNames {
+		if strings.EqualFold(boundSwitchName, switchName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NetworkAddress returns the network address of the IPv4 address with the subnet mask, which DHCP uses as the id of
+// the scope the address is in
+func NetworkAddress(ipAddress string, subnetMask string) (string, error) {
+	ip := net.ParseIP(ipAddress).To4()
+	if ip == nil {
+		return "", fmt.Errorf("[ERROR][hyperv] %s is not a valid IPv4 address", ipAddress)
+	}
+
+	mask := net.ParseIP(subnetMask).To4()
+	if mask == nil {
+		return "", fmt.Errorf("[ERROR][hyperv] %s is not a valid IPv4 subnet mask", subnetMask)
+	}
+
+	return ip.Mask(net.IPv4Mask(mask[0], mask[1], mask[2], mask[3])).String(), nil
+}
+
+type HypervDhcpServerScopeClient interface {
+	CreateDhcpServerScope(ctx context.Context, dhcpServerScope DhcpServerScope) (result DhcpServerScope, err error)
+	GetDhcpServerScope(ctx context.Context, scopeId string) (result DhcpServerScope, err error)
+	UpdateDhcpServerScope(ctx context.Context, dhcpServerScope DhcpServerScope) (err error)
+	DeleteDhcpServerScope(ctx context.Context, scopeId string) (err error)
+}
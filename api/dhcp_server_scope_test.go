@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestNetworkAddress(t *testing.T) {
+	tests := []struct {
+		ipAddress  string
+		subnetMask string
+		expected   string
+	}{
+		{"192.168.100.100", "255.255.255.0", "192.168.100.0"},
+		{"10.1.2.3", "255.255.0.0", "10.1.0.0"},
+		{"172.16.5.129", "255.255.255.128", "172.16.5.128"},
+	}
+
+	for _, test := range tests {
+		actual, err := NetworkAddress(test.ipAddress, test.subnetMask)
+		if err != nil {
+			t.Errorf("Unable to get network address of %s/%s: %s", test.ipAddress, test.subnetMask, err)
+		}
+		if actual != test.expected {
+			t.Errorf("Expected network address of %s/%s to be %s but was %s", test.ipAddress, test.subnetMask, test.expected, actual)
+		}
+	}
+
+	_, err := NetworkAddress("fe80::1", "255.255.255.0")
+	if err == nil {
+		t.Errorf("Expected an error for an IPv6 address")
+	}
+}
+
+func TestDhcpServerScopeIsBoundToSwitch(t *testing.T) {
+	dhcpServerScope := DhcpServerScope{
+		BoundSwitchNames: []string{"Lab"},
+	}
+
+	if !dhcpServerScope.IsBoundToSwitch("lab") {
+		t.Errorf("Expected scope to be bound to lab")
+	}
+	if dhcpServerScope.IsBoundToSwitch("") {
+		t.Errorf("Expected scope not to be bound to an empty switch name")
+	}
+}
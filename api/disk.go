@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+)
+
+func FlattenDisks(disks *[]Disk) []interface{} {
+	if disks == nil || len(*disks) < 1 {
+		return nil
+	}
+
+	flattenedDisks := make([]interface{}, 0)
+
+	for _, disk := range *disks {
+		flattenedDisk := make(map[string]interface{})
+		flattenedDisk["number"] = disk.Number
+		flattenedDisk["friendly_name"] = disk.FriendlyName
+		flattenedDisk["serial_number"] = disk.SerialNumber
+		flattenedDisk["unique_id"] = disk.UniqueId
+		flattenedDisk["size_bytes"] = disk.SizeBytes
+		flattenedDisk["bus_type"] = disk.BusType
+		flattenedDisk["partition_style"] = disk.PartitionStyle
+		flattenedDisk["operational_status"] = disk.OperationalStatus
+		flattenedDisk["is_offline"] = disk.IsOffline
+		flattenedDisk["is_read_only"] = disk.IsReadOnly
+		flattenedDisk["is_boot"] = disk.IsBoot
+		flattenedDisk["is_system"] = disk.IsSystem
+		flattenedDisks = append(flattenedDisks, flattenedDisk)
+	}
+
+	return flattenedDisks
+}
+
+type Disk struct {
+	Number            int
+	FriendlyName      string
+	SerialNumber      string
+	UniqueId          string
+	SizeBytes         int64
+	BusType           string
+	PartitionStyle    string
+	OperationalStatus string
+	IsOffline         bool
+	IsReadOnly        bool
+	IsBoot            bool
+	IsSystem          bool
+}
+
+type HypervDiskClient interface {
+	GetPhysicalDisks(ctx context.Context, friendlyName string, offlineOnly bool) (result []Disk, err error)
+}
@@ -2,6 +2,11 @@ package api
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
 )
 
 type Dvd struct {
@@ -9,8 +14,127 @@ type Dvd struct {
 	Ip   string
 }
 
+// DvdNetworkConfig is the network configuration of the first ethernet adapter of the guest in a seed iso. Addresses
+// are in CIDR notation and can be IPv4, IPv6 or both for a dual stack guest. An Ip without Addresses is the original
+// single IPv4 address seed with a /16 prefix and the gateway and name server of the lab it was written for.
+type DvdNetworkConfig struct {
+	Ip          string
+	Addresses   []string
+	Gateway4    string
+	Gateway6    string
+	Dhcp4       bool
+	Dhcp6       bool
+	Nameservers []string
+}
+
+const (
+	legacyDvdGateway4   = "172.16.1.254"
+	legacyDvdNameserver = "172.16.14.27"
+)
+
+// Normalize returns the configuration with an Ip expanded to the addresses, gateway and name server it stands for
+func (c DvdNetworkConfig) Normalize() DvdNetworkConfig {
+	if c.Ip == "" || len(c.Addresses) > 0 {
+		return c
+	}
+
+	c.Addresses = []string{c.Ip + "/16"}
+	if c.Gateway4 == "" {
+		c.Gateway4 = legacyDvdGateway4
+	}
+	if len(c.Nameservers) == 0 {
+		c.Nameservers = []string{legacyDvdNameserver}
+	}
+
+	return c
+}
+
+func (c DvdNetworkConfig) Validate() error {
+	if c.Ip == "" && len(c.Addresses) == 0 && !c.Dhcp4 && !c.Dhcp6 {
+		return fmt.Errorf("[ERROR][hyperv] network configuration of seed must have an ip, addresses, dhcp4 or dhcp6")
+	}
+
+	hasIpv4Address := false
+	hasIpv6Address := false
+	for _, address := range c.Addresses {
+		ip, _, err := net.ParseCIDR(address)
+		if err != nil {
+			return fmt.Errorf("[ERROR][hyperv] address of seed must be in CIDR notation - was %s", address)
+		}
+
+		if ip.To4() != nil {
+			hasIpv4Address = true
+		} else {
+			hasIpv6Address = true
+		}
+	}
+
+	if c.Gateway4 != "" {
+		if ip := net.ParseIP(c.Gateway4); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("[ERROR][hyperv] gateway4 of seed must be an IPv4 address - was %s", c.Gateway4)
+		}
+		if !hasIpv4Address {
+			return fmt.Errorf("[ERROR][hyperv] gateway4 of seed requires a static IPv4 address in addresses")
+		}
+	}
+
+	if c.Gateway6 != "" {
+		if ip := net.ParseIP(c.Gateway6); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("[ERROR][hyperv] gateway6 of seed must be an IPv6 address - was %s", c.Gateway6)
+		}
+		if !hasIpv6Address {
+			return fmt.Errorf("[ERROR][hyperv] gateway6 of seed requires a static IPv6 address in addresses")
+		}
+	}
+
+	for _, nameserver := range c.Nameservers {
+		if ip := net.ParseIP(nameserver); ip == nil {
+			return fmt.Errorf("[ERROR][hyperv] nameserver of seed must be an IP address - was %s", nameserver)
+		}
+	}
+
+	return nil
+}
+
+// SplitIpAddresses splits the ip addresses reported by a guest into IPv4 and IPv6 addresses. The IPv6 link local
+// addresses every adapter has are left out, so that only addresses the guest can be reached on remain.
+func SplitIpAddresses(ipAddresses []string) (ipv4Addresses []string, ipv6Addresses []string) {
+	ipv4Addresses = make([]string, 0)
+	ipv6Addresses = make([]string, 0)
+
+	for _, ipAddress := range ipAddresses {
+		ip := net.ParseIP(ipAddress)
+		if ip == nil {
+			continue
+		}
+
+		if ip.To4() != nil {
+			ipv4Addresses = append(ipv4Addresses, ipAddress)
+		} else if !ip.IsLinkLocalUnicast() {
+			ipv6Addresses = append(ipv6Addresses, ipAddress)
+		}
+	}
+
+	return ipv4Addresses, ipv6Addresses
+}
+
+// EphemeralDvdTaskName returns the name of the scheduled task that deletes an ephemeral dvd, it is derived from the path
+// of the iso so that it can be found again to unregister it when the dvd is deleted. Paths are compared without case
+// like the rest of Windows.
+func EphemeralDvdTaskName(path string) string {
+	hash := sha1.Sum([]byte(strings.ToLower(path)))
+	return "ephemeral-dvd-" + hex.EncodeToString(hash[:])[:16]
+}
+
 type HypervDvdClient interface {
-	CreateDvd(ctx context.Context, path string, ip string) (err error)
+	CreateDvd(ctx context.Context, path string, networkConfig DvdNetworkConfig) (err error)
+	// CreateCloudInitDvd builds the NoCloud seed iso of cloud-init at the path of the seed, with the user-data,
+	// meta-data and network-config files in the root of a volume labelled cidata.
+	CreateCloudInitDvd(ctx context.Context, cloudInit VmCloudInit) (err error)
+	// CreateEphemeralDvdWatcher registers a scheduled task on the HyperV host machine that detaches the iso from all
+	// dvd drives and deletes it once the virtual machine reports its first heartbeat, or once the timeout in seconds
+	// has passed, so secrets in a seed iso do not stay on disk after provisioning.
+	CreateEphemeralDvdWatcher(ctx context.Context, path string, vmName string, timeout uint32) (err error)
 	DeleteDvd(ctx context.Context, path string) (err error)
 	GetDvd(ctx context.Context, path string, ip string) (result Dvd, err error)
 }
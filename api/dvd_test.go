@@ -0,0 +1,79 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEphemeralDvdTaskName(t *testing.T) {
+	taskName := EphemeralDvdTaskName(`C:\Iso\seed.iso`)
+
+	if !strings.HasPrefix(taskName, "ephemeral-dvd-") || len(taskName) != len("ephemeral-dvd-")+16 {
+		t.Errorf("Unexpected task name %s", taskName)
+	}
+
+	if EphemeralDvdTaskName(`c:\iso\SEED.iso`) != taskName {
+		t.Errorf("Expected paths to be compared without case")
+	}
+
+	if EphemeralDvdTaskName(`C:\Iso\other.iso`) == taskName {
+		t.Errorf("Expected different paths to have different task names")
+	}
+}
+
+func TestDvdNetworkConfigNormalize(t *testing.T) {
+	networkConfig := DvdNetworkConfig{Ip: "172.16.1.10"}.Normalize()
+
+	expected := DvdNetworkConfig{
+		Ip:          "172.16.1.10",
+		Addresses:   []string{"172.16.1.10/16"},
+		Gateway4:    "172.16.1.254",
+		Nameservers: []string{"172.16.14.27"},
+	}
+	if !reflect.DeepEqual(networkConfig, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, networkConfig)
+	}
+
+	dualStack := DvdNetworkConfig{Addresses: []string{"10.0.0.10/24", "fd00::10/64"}, Gateway6: "fd00::1"}
+	if !reflect.DeepEqual(dualStack.Normalize(), dualStack) {
+		t.Errorf("Expected addresses to be kept as they are, got %#v", dualStack.Normalize())
+	}
+}
+
+func TestDvdNetworkConfigValidate(t *testing.T) {
+	cases := []struct {
+		name          string
+		networkConfig DvdNetworkConfig
+		valid         bool
+	}{
+		{"ip", DvdNetworkConfig{Ip: "172.16.1.10"}, true},
+		{"dual stack", DvdNetworkConfig{Addresses: []string{"10.0.0.10/24", "fd00::10/64"}, Gateway4: "10.0.0.1", Gateway6: "fd00::1", Nameservers: []string{"10.0.0.1", "fd00::1"}}, true},
+		{"ipv6 only", DvdNetworkConfig{Addresses: []string{"fd00::10/64"}, Gateway6: "fd00::1"}, true},
+		{"dhcp6", DvdNetworkConfig{Dhcp6: true}, true},
+		{"nothing", DvdNetworkConfig{}, false},
+		{"address without prefix", DvdNetworkConfig{Addresses: []string{"fd00::10"}}, false},
+		{"ipv6 gateway4", DvdNetworkConfig{Addresses: []string{"10.0.0.10/24"}, Gateway4: "fd00::1"}, false},
+		{"ipv4 gateway6", DvdNetworkConfig{Addresses: []string{"fd00::10/64"}, Gateway6: "10.0.0.1"}, false},
+		{"gateway6 without ipv6 address", DvdNetworkConfig{Addresses: []string{"10.0.0.10/24"}, Gateway6: "fd00::1"}, false},
+		{"invalid nameserver", DvdNetworkConfig{Dhcp4: true, Nameservers: []string{"dns"}}, false},
+	}
+
+	for _, c := range cases {
+		if err := c.networkConfig.Normalize().Validate(); (err == nil) != c.valid {
+			t.Errorf("Expected %s to be valid %t, got %v", c.name, c.valid, err)
+		}
+	}
+}
+
+func TestSplitIpAddresses(t *testing.T) {
+	ipv4Addresses, ipv6Addresses := SplitIpAddresses([]string{"10.0.0.10", "fe80::215:5dff:fe00:1", "fd00::10", "2001:db8::10"})
+
+	if !reflect.DeepEqual(ipv4Addresses, []string{"10.0.0.10"}) {
+		t.Errorf("Unexpected IPv4 addresses %#v", ipv4Addresses)
+	}
+
+	if !reflect.DeepEqual(ipv6Addresses, []string{"fd00::10", "2001:db8::10"}) {
+		t.Errorf("Unexpected IPv6 addresses %#v", ipv6Addresses)
+	}
+}
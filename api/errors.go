@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors that a ScriptError can be matched against with errors.Is, so resources can branch on the kind of failure
+// instead of on the text of PowerShell messages.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrAccessDenied = errors.New("access denied")
+	ErrInUse        = errors.New("in use")
+	ErrTimeout      = errors.New("timeout")
+)
+
+const (
+	hresultFileNotFound     int32 = -2147024894 // 0x80070002
+	hresultPathNotFound     int32 = -2147024893 // 0x80070003
+	hresultAccessDenied     int32 = -2147024891 // 0x80070005
+	hresultSharingViolation int32 = -2147024864 // 0x80070020
+	hresultLockViolation    int32 = -2147024863 // 0x80070021
+	hresultBusy             int32 = -2147024726 // 0x800700AA
+	hresultTimeout          int32 = -2147023436 // 0x800705B4
+)
+
+// ScriptError is the error record of a script that failed on the HyperV host machine
+type ScriptError struct {
+	Operation        string
+	Category         string
+	Message          string
+	HResult          int32
+	ExceptionType    string
+	ScriptLineNumber int
+	ScriptLine       string
+	ScriptStackTrace string
+}
+
+// Error renders the message on the first line, so it can be used as the summary of a diagnostic, followed by the
+// details of the error record.
+func (e *ScriptError) Error() string {
+	var message strings.Builder
+
+	if e.Operation != "" {
+		message.WriteString(e.Operation)
+		message.WriteString(": ")
+	}
+	message.WriteString(e.Message)
+
+	message.WriteString(fmt.Sprintf("\nException: %s (category: %s, hresult: 0x%08X)", e.ExceptionType, e.Category, uint32(e.HResult)))
+
+	if e.ScriptLine != "" {
+		message.WriteString(fmt.Sprintf("\nAt line %d: %s", e.ScriptLineNumber, e.ScriptLine))
+	}
+
+	if e.ScriptStackTrace != "" {
+		message.WriteString("\nStack trace:\n")
+		message.WriteString(e.ScriptStackTrace)
+	}
+
+	return message.String()
+}
+
+// Kind returns which of ErrNotFound, ErrAccessDenied, ErrInUse or ErrTimeout the error is, or nil if it is none of
+// them.
+func (e *ScriptError) Kind() error {
+	switch {
+	case e.Category == "ObjectNotFound",
+		e.HResult == hresultFileNotFound,
+		e.HResult == hresultPathNotFound,
+		strings.HasSuffix(e.ExceptionType, ".ItemNotFoundException"):
+		return ErrNotFound
+	case e.Category == "PermissionDenied",
+		e.Category == "SecurityError",
+		e.HResult == hresultAccessDenied,
+		e.ExceptionType == "System.UnauthorizedAccessException":
+		return ErrAccessDenied
+	case e.Category == "ResourceBusy",
+		e.HResult == hresultSharingViolation,
+		e.HResult == hresultLockViolation,
+		e.HResult == hresultBusy:
+		return ErrInUse
+	case e.Category == "OperationTimeout",
+		e.HResult == hresultTimeout,
+		e.ExceptionType == "System.TimeoutException":
+		return ErrTimeout
+	default:
+		return nil
+	}
+}
+
+func (e *ScriptError) Is(target error) bool {
+	kind := e.Kind()
+	return kind != nil && kind == target
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestScriptErrorIs(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected error
+	}{
+		{`{"Category":"ObjectNotFound","Message":"Hyper-V was unable to find a virtual machine with name \"web_server_g2\".","HResult":-2146233087}`, ErrNotFound},
+		{`{"Category":"NotSpecified","Message":"Cannot find path","HResult":-2146233087,"ExceptionType":"System.Management.Automation.ItemNotFoundException"}`, ErrNotFound},
+		{`{"Category":"NotSpecified","Message":"Access is denied.","HResult":-2147024891}`, ErrAccessDenied},
+		{`{"Category":"WriteError","Message":"The process cannot access the file because it is being used by another process.","HResult":-2147024864}`, ErrInUse},
+		{`{"Category":"OperationTimeout","Message":"The operation has timed out.","HResult":-2146233083}`, ErrTimeout},
+		{`{"Category":"InvalidArgument","Message":"Cannot validate argument on parameter 'Name'.","HResult":-2146233087}`, nil},
+	}
+
+	for _, test := range tests {
+		var scriptError ScriptError
+		if err := json.Unmarshal([]byte(test.json), &scriptError); err != nil {
+			t.Fatalf("Unable to deserialize script error: %s", err.Error())
+		}
+
+		err := fmt.Errorf("unable to delete: %w", &scriptError)
+
+		for _, kind := range []error{ErrNotFound, ErrAccessDenied, ErrInUse, ErrTimeout} {
+			if errors.Is(err, kind) != (kind == test.expected) {
+				t.Errorf("Expected errors.Is(%q, %q) to be %t", scriptError.Message, kind, kind == test.expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+)
+
+func FlattenPartitionableGpus(partitionableGpus *[]PartitionableGpu) []interface{} {
+	if partitionableGpus == nil || len(*partitionableGpus) < 1 {
+		return nil
+	}
+
+	flattenedPartitionableGpus := make([]interface{}, 0)
+
+	for _, partitionableGpu := range *partitionableGpus {
+		flattenedPartitionableGpu := make(map[string]interface{})
+		flattenedPartitionableGpu["name"] = partitionableGpu.Name
+		flattenedPartitionableGpu["friendly_name"] = partitionableGpu.FriendlyName
+		flattenedPartitionableGpu["partition_count"] = partitionableGpu.PartitionCount
+		flattenedPartitionableGpu["valid_partition_counts"] = partitionableGpu.ValidPartitionCounts
+		flattenedPartitionableGpu["total_vram_bytes"] = partitionableGpu.TotalVramBytes
+		flattenedPartitionableGpu["available_vram_bytes"] = partitionableGpu.AvailableVramBytes
+		flattenedPartitionableGpu["min_partition_vram_bytes"] = partitionableGpu.MinPartitionVramBytes
+		flattenedPartitionableGpu["max_partition_vram_bytes"] = partitionableGpu.MaxPartitionVramBytes
+		flattenedPartitionableGpu["optimal_partition_vram_bytes"] = partitionableGpu.OptimalPartitionVramBytes
+		flattenedPartitionableGpus = append(flattenedPartitionableGpus, flattenedPartitionableGpu)
+	}
+
+	return flattenedPartitionableGpus
+}
+
+type PartitionableGpu struct {
+	Name                      string
+	FriendlyName              string
+	PartitionCount            int
+	ValidPartitionCounts      []int
+	TotalVramBytes            int64
+	AvailableVramBytes        int64
+	MinPartitionVramBytes     int64
+	MaxPartitionVramBytes     int64
+	OptimalPartitionVramBytes int64
+}
+
+type HypervGpuClient interface {
+	GetPartitionableGpus(ctx context.Context) (result []PartitionableGpu, err error)
+}
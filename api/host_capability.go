@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// generation2VmVersion is the first configuration version of virtual machines that supports generation 2 virtual
+// machines, i.e. Windows Server 2012 R2
+const generation2VmVersion = "5.0"
+
+type HostCapabilities struct {
+	ComputerName             string
+	HypervVersion            string
+	DefaultVmVersion         string
+	SupportedVmVersions      []string
+	SecureBootTemplates      []string
+	OscdimgAvailable         bool
+	ConvertToYamlAvailable   bool
+	AvmaSupported            bool
+	GuestStateIsolationTypes []string
+	ServerOperatingSystem    bool
+	HypervisorSchedulerType  string
+	LogicalProcessorCount    int
+}
+
+func (c *HostCapabilities) HasSecureBootTemplate(name string) bool {
+	for _, secureBootTemplate := range c.SecureBootTemplates {
+		if strings.EqualFold(secureBootTemplate, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *HostCapabilities) SupportsVmVersion(version string) bool {
+	for _, supportedVmVersion := range c.SupportedVmVersions {
+		if supportedVmVersion == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *HostCapabilities) SupportsGuestStateIsolationType(guestStateIsolationType GuestStateIsolationType) bool {
+	if guestStateIsolationType == GuestStateIsolationType_Disabled {
+		return true
+	}
+
+	for _, supportedGuestStateIsolationType := range c.GuestStateIsolationTypes {
+		if strings.EqualFold(supportedGuestStateIsolationType, guestStateIsolationType.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsCpuGroups is true when virtual machines can be pinned to logical processors of the HyperV host machine with
+// cpu groups. Cpu groups are only available on Windows Server, and the root scheduler of the hypervisor leaves the
+// scheduling of virtual processors to the host so it ignores them.
+func (c *HostCapabilities) SupportsCpuGroups() bool {
+	return c.ServerOperatingSystem && !strings.EqualFold(c.HypervisorSchedulerType, "Root")
+}
+
+// MaximumVmVersion returns the highest configuration version of virtual machines the HyperV host machine supports
+func (c *HostCapabilities) MaximumVmVersion() string {
+	maximumVmVersion := ""
+	for _, supportedVmVersion := range c.SupportedVmVersions {
+		if maximumVmVersion == "" || compareVmVersions(supportedVmVersion, maximumVmVersion) > 0 {
+			maximumVmVersion = supportedVmVersion
+		}
+	}
+
+	return maximumVmVersion
+}
+
+// SupportedGenerations returns the generations of virtual machines the HyperV host machine can create
+func (c *HostCapabilities) SupportedGenerations() []int {
+	generations := []int{1}
+	if maximumVmVersion := c.MaximumVmVersion(); maximumVmVersion != "" && compareVmVersions(maximumVmVersion, generation2VmVersion) >= 0 {
+		generations = append(generations, 2)
+	}
+
+	return generations
+}
+
+// compareVmVersions compares configuration versions of virtual machines e.g. 9.0 and 10.0 by their numbers, returning
+// a negative number when a is lower than b, zero when they are the same and a positive number when a is higher
+func compareVmVersions(a string, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := 0, 0
+		if i < len(aParts) {
+			aPart, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bPart, _ = strconv.Atoi(bParts[i])
+		}
+
+		if aPart != bPart {
+			return aPart - bPart
+		}
+	}
+
+	return 0
+}
+
+// HypervHostCapabilityClient returns the features of the HyperV host machine. The capabilities are only queried once
+// per provider run, so anything that changes them on the host must call InvalidateHostCapabilities.
+type HypervHostCapabilityClient interface {
+	GetHostCapabilities(ctx context.Context) (result HostCapabilities, err error)
+	InvalidateHostCapabilities()
+	RequireIsoTools(ctx context.Context) (err error)
+	RequireSecureBootTemplate(ctx context.Context, name string) (err error)
+	RequireAvma(ctx context.Context) (err error)
+	RequireGuestStateIsolationType(ctx context.Context, guestStateIsolationType GuestStateIsolationType) (err error)
+	RequireCpuGroups(ctx context.Context) (err error)
+}
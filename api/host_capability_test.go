@@ -0,0 +1,86 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestHostCapabilitiesMaximumVmVersion(t *testing.T) {
+	hostCapabilities := HostCapabilities{
+		SupportedVmVersions: []string{"5.0", "9.0", "10.0", "8.3", "11.0", "9.1"},
+	}
+
+	if actual := hostCapabilities.MaximumVmVersion(); actual != "11.0" {
+		t.Errorf("Expected maximum vm version 11.0 but was %s", actual)
+	}
+
+	hostCapabilities = HostCapabilities{}
+	if actual := hostCapabilities.MaximumVmVersion(); actual != "" {
+		t.Errorf("Expected no maximum vm version but was %s", actual)
+	}
+}
+
+func TestHostCapabilitiesSupportedGenerations(t *testing.T) {
+	tests := []struct {
+		supportedVmVersions []string
+		expected            []int
+	}{
+		{[]string{"5.0", "8.0", "9.0", "10.0"}, []int{1, 2}},
+		{[]string{"4.0"}, []int{1}},
+		{[]string{}, []int{1}},
+	}
+
+	for _, test := range tests {
+		hostCapabilities := HostCapabilities{
+			SupportedVmVersions: test.supportedVmVersions,
+		}
+
+		actual := hostCapabilities.SupportedGenerations()
+		if len(actual) != len(test.expected) || actual[len(actual)-1] != test.expected[len(test.expected)-1] {
+			t.Errorf("Expected generations %v for vm versions %v but was %v", test.expected, test.supportedVmVersions, actual)
+		}
+	}
+}
+
+func TestHostCapabilitiesSupportsGuestStateIsolationType(t *testing.T) {
+	hostCapabilities := HostCapabilities{
+		GuestStateIsolationTypes: []string{"Disabled", "TrustedLaunch"},
+	}
+
+	if !hostCapabilities.SupportsGuestStateIsolationType(ToGuestStateIsolationType("trustedlaunch")) {
+		t.Errorf("Expected trusted launch to be supported")
+	}
+
+	if hostCapabilities.SupportsGuestStateIsolationType(GuestStateIsolationType_SNP) {
+		t.Errorf("Expected SNP not to be supported")
+	}
+
+	hostCapabilities = HostCapabilities{}
+	if !hostCapabilities.SupportsGuestStateIsolationType(GuestStateIsolationType_Disabled) {
+		t.Errorf("Expected no guest state isolation to be supported on every host")
+	}
+}
+
+func TestHostCapabilitiesSupportsCpuGroups(t *testing.T) {
+	tests := []struct {
+		serverOperatingSystem   bool
+		hypervisorSchedulerType string
+		expected                bool
+	}{
+		{true, "Core", true},
+		{true, "Classic", true},
+		{true, "", true},
+		{true, "Root", false},
+		{false, "Core", false},
+	}
+
+	for _, test := range tests {
+		hostCapabilities := HostCapabilities{
+			ServerOperatingSystem:   test.serverOperatingSystem,
+			HypervisorSchedulerType: test.hypervisorSchedulerType,
+		}
+
+		if actual := hostCapabilities.SupportsCpuGroups(); actual != test.expected {
+			t.Errorf("Expected cpu groups supported %t for server %t with %q scheduler but was %t", test.expected, test.serverOperatingSystem, test.hypervisorSchedulerType, actual)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+)
+
+const (
+	HostCertificateDefaultPort          = 5986
+	HostCertificateDefaultValidityYears = 5
+)
+
+// HostCertificateSettings describes the certificate of the WinRM HTTPS listener of a HyperV host machine. When
+// PfxBase64 is empty a self-signed certificate is created for the Hostname and SubjectAlternativeNames, otherwise the
+// pfx of a certificate issued by a certificate authority is imported.
+type HostCertificateSettings struct {
+	Hostname                string
+	SubjectAlternativeNames []string
+	ValidityYears           int
+	PfxBase64               string
+	PfxPassword             string
+	Port                    int
+	FirewallRule            bool
+	FirewallRemoteAddresses []string
+}
+
+func (s *HostCertificateSettings) Validate() error {
+	if s.Hostname == "" {
+		return fmt.Errorf("[ERROR][hyperv] hostname of host certificate must be specified")
+	}
+
+	if s.PfxBase64 != "" {
+		if _, err := base64.StdEncoding.DecodeString(s.PfxBase64); err != nil {
+			return fmt.Errorf("[ERROR][hyperv] pfx of host certificate for %s is not valid base64: %s", s.Hostname, err)
+		}
+
+		if len(s.SubjectAlternativeNames) > 0 {
+			return fmt.Errorf("[ERROR][hyperv] subject alternative names of host certificate for %s can only be specified for a self-signed certificate", s.Hostname)
+		}
+	}
+
+	return nil
+}
+
+// HostCertificate is a certificate in the LocalMachine\My certificate store of the HyperV host machine together with
+// the WinRM HTTPS listener and the firewall rule that use it. Port is 0 when no WinRM HTTPS listener uses the
+// certificate and FirewallRule is false when the firewall rule of the listener is missing or disabled.
+type HostCertificate struct {
+	Thumbprint              string
+	Subject                 string
+	Issuer                  string
+	DnsNames                []string
+	NotAfter                string
+	SelfSigned              bool
+	CertificateBase64       string
+	Hostname                string
+	Port                    int
+	FirewallRule            bool
+	FirewallRemoteAddresses []string
+}
+
+// Pem returns the public certificate in the PEM format, so that it can be trusted by the provider with cacert_path
+func (c *HostCertificate) Pem() (string, error) {
+	if c.CertificateBase64 == "" {
+		return "", nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(c.CertificateBase64)
+	if err != nil {
+		return "", fmt.Errorf("[ERROR][hyperv] certificate %s is not valid base64: %s", c.Thumbprint, err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+// HostCertificateFirewallRuleNamePrefix is the start of the name of the firewall rules that allow WinRM HTTPS
+// connections, the port of the listener is appended to it
+const HostCertificateFirewallRuleNamePrefix = "terraform-provider-hyperv-winrm-https-"
+
+func HostCertificateFirewallRuleName(port int) string {
+	return fmt.Sprintf("%s%d", HostCertificateFirewallRuleNamePrefix, port)
+}
+
+type HypervHostCertificateClient interface {
+	CreateOrUpdateHostCertificate(ctx context.Context, thumbprint string, settings HostCertificateSettings) (result HostCertificate, err error)
+	GetHostCertificate(ctx context.Context, thumbprint string) (result HostCertificate, err error)
+	DeleteHostCertificate(ctx context.Context, thumbprint string) (err error)
+}
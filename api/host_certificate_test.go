@@ -0,0 +1,60 @@
+package api
+
+import (
+	"bytes"
+	"encoding/pem"
+	"testing"
+)
+
+func TestHostCertificateSettingsValidate(t *testing.T) {
+	settings := HostCertificateSettings{
+		Hostname:                "hyperv01.contoso.com",
+		SubjectAlternativeNames: []string{"hyperv01", "hyperv01.lab.contoso.com"},
+	}
+
+	if err := settings.Validate(); err != nil {
+		t.Errorf("Expected self-signed host certificate to be valid but was %s", err)
+	}
+
+	settings.PfxBase64 = "MIIK"
+	if err := settings.Validate(); err == nil {
+		t.Errorf("Expected subject alternative names of an imported host certificate to be invalid")
+	}
+
+	settings.SubjectAlternativeNames = nil
+	if err := settings.Validate(); err != nil {
+		t.Errorf("Expected imported host certificate to be valid but was %s", err)
+	}
+
+	settings.PfxBase64 = "not base64!"
+	if err := settings.Validate(); err == nil {
+		t.Errorf("Expected pfx that is not base64 to be invalid")
+	}
+
+	settings = HostCertificateSettings{}
+	if err := settings.Validate(); err == nil {
+		t.Errorf("Expected host certificate without hostname to be invalid")
+	}
+}
+
+func TestHostCertificatePem(t *testing.T) {
+	hostCertificate := HostCertificate{
+		Thumbprint:        "3C2E9A1F5B7D4E6A8C0B2D4F6A8C0E2B4D6F8A0C",
+		CertificateBase64: "MIIBAgMEBQ==",
+	}
+
+	certificatePem, err := hostCertificate.Pem()
+	if err != nil {
+		t.Fatalf("Unable to get pem of host certificate: %s", err)
+	}
+
+	block, _ := pem.Decode([]byte(certificatePem))
+	if block == nil || block.Type != "CERTIFICATE" || !bytes.Equal(block.Bytes, []byte{0x30, 0x82, 0x01, 0x02, 0x03, 0x04, 0x05}) {
+		t.Errorf("Unexpected pem of host certificate: %s", certificatePem)
+	}
+
+	hostCertificate.CertificateBase64 = ""
+	if certificatePem, _ := hostCertificate.Pem(); certificatePem != "" {
+		t.Errorf("Expected empty pem for a missing certificate but was %s", certificatePem)
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// MemoryReserveMaximumMb is the largest memory reserve of the root partition that is accepted, 64 GB is far more than
+// the root partition of a HyperV host machine ever needs
+const MemoryReserveMaximumMb = 65536
+
+// HostNumaSpanning are the settings of the HyperV host machine that decide how memory is handed out to virtual
+// machines. NumaSpanningEnabled lets virtual machines use memory of more than one NUMA node, it is applied when the
+// Hyper-V virtual machine management service starts. MemoryReserveMb is the memory in megabytes kept for the root
+// partition, 0 lets Hyper-V compute it, it is applied when the HyperV host machine starts.
+// NumaSpanningRestartPending and MemoryReserveRestartPending are read back, they are true when a setting was changed
+// by the provider and the service or the HyperV host machine has not been restarted since.
+type HostNumaSpanning struct {
+	ComputerName                string
+	NumaSpanningEnabled         bool
+	MemoryReserveMb             int
+	NumaSpanningRestartPending  bool
+	MemoryReserveRestartPending bool
+}
+
+func (h *HostNumaSpanning) Validate() error {
+	if h.MemoryReserveMb < 0 || h.MemoryReserveMb > MemoryReserveMaximumMb {
+		return fmt.Errorf("[ERROR][hyperv] memory reserve of host must be between 0 and %d megabytes", MemoryReserveMaximumMb)
+	}
+
+	return nil
+}
+
+// RestartPending is whether the HyperV host machine has to be restarted to apply all the settings, restarting it also
+// restarts the Hyper-V virtual machine management service
+func (h *HostNumaSpanning) RestartPending() bool {
+	return h.NumaSpanningRestartPending || h.MemoryReserveRestartPending
+}
+
+type HypervHostNumaSpanningClient interface {
+	GetHostNumaSpanning(ctx context.Context) (result HostNumaSpanning, err error)
+	UpdateHostNumaSpanning(ctx context.Context, hostNumaSpanning HostNumaSpanning) (err error)
+}
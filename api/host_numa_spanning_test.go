@@ -0,0 +1,56 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestHostNumaSpanningValidate(t *testing.T) {
+	tests := []struct {
+		memoryReserveMb int
+		valid           bool
+	}{
+		{0, true},
+		{4096, true},
+		{MemoryReserveMaximumMb, true},
+		{-1, false},
+		{MemoryReserveMaximumMb + 1, false},
+	}
+
+	for _, test := range tests {
+		hostNumaSpanning := HostNumaSpanning{
+			MemoryReserveMb: test.memoryReserveMb,
+		}
+
+		err := hostNumaSpanning.Validate()
+		if test.valid && err != nil {
+			t.Errorf("Expected memory reserve of %d megabytes to be valid but was %s", test.memoryReserveMb, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("Expected memory reserve of %d megabytes to be invalid", test.memoryReserveMb)
+		}
+	}
+}
+
+func TestHostNumaSpanningRestartPending(t *testing.T) {
+	tests := []struct {
+		numaSpanningRestartPending  bool
+		memoryReserveRestartPending bool
+		expected                    bool
+	}{
+		{false, false, false},
+		{true, false, true},
+		{false, true, true},
+		{true, true, true},
+	}
+
+	for _, test := range tests {
+		hostNumaSpanning := HostNumaSpanning{
+			NumaSpanningRestartPending:  test.numaSpanningRestartPending,
+			MemoryReserveRestartPending: test.memoryReserveRestartPending,
+		}
+
+		if actual := hostNumaSpanning.RestartPending(); actual != test.expected {
+			t.Errorf("Expected restart pending %t for %+v but was %t", test.expected, hostNumaSpanning, actual)
+		}
+	}
+}
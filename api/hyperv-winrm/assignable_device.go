@@ -0,0 +1,75 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getAssignableDevicesArgs struct {
+}
+
+var getAssignableDevicesTemplate = template.Must(template.New("GetAssignableDevices").Parse(`
+$ErrorActionPreference = 'Stop'
+
+$assignableDevicesObject = @()
+$locationPaths = @{}
+
+Get-VM | Get-VMAssignableDevice -ErrorAction SilentlyContinue | %{
+	$locationPaths[$_.LocationPath] = $true
+	$pnpDevice = Get-PnpDevice -InstanceId $_.InstanceID -ErrorAction SilentlyContinue
+	$assignableDevicesObject += @{
+		InstanceId=$_.InstanceID;
+		FriendlyName="$($pnpDevice.FriendlyName)";
+		Class="$($pnpDevice.Class)";
+		LocationPath=$_.LocationPath;
+		State='Assigned';
+		VmName=$_.VMName;
+	}
+}
+
+Get-VMHostAssignableDevice -ErrorAction SilentlyContinue | %{
+	$locationPaths[$_.LocationPath] = $true
+	$pnpDevice = Get-PnpDevice -InstanceId $_.InstanceID -ErrorAction SilentlyContinue
+	$assignableDevicesObject += @{
+		InstanceId=$_.InstanceID;
+		FriendlyName="$($pnpDevice.FriendlyName)";
+		Class="$($pnpDevice.Class)";
+		LocationPath=$_.LocationPath;
+		State='Dismounted';
+		VmName='';
+	}
+}
+
+Get-PnpDevice -PresentOnly | ?{ $_.InstanceId -like 'PCI\*' -and $_.Class -ne 'System' } | %{
+	$locationPath = @((Get-PnpDeviceProperty -InstanceId $_.InstanceId -KeyName 'DEVPKEY_Device_LocationPaths' -ErrorAction SilentlyContinue).Data) | ?{ $_ -like 'PCIROOT*' } | Select -First 1
+	if ($locationPath -and !$locationPaths.ContainsKey($locationPath)) {
+		$assignableDevicesObject += @{
+			InstanceId=$_.InstanceId;
+			FriendlyName="$($_.FriendlyName)";
+			Class="$($_.Class)";
+			LocationPath=$locationPath;
+			State='Host';
+			VmName='';
+		}
+	}
+}
+
+$assignableDevicesObject = @($assignableDevicesObject | Sort-Object -Property { $_.LocationPath })
+
+if ($assignableDevicesObject) {
+	$assignableDevices = ConvertTo-Json -InputObject $assignableDevicesObject
+	$assignableDevices
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetAssignableDevices(ctx context.Context) (result []api.AssignableDevice, err error) {
+	result = make([]api.AssignableDevice, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getAssignableDevicesTemplate, getAssignableDevicesArgs{}, &result)
+
+	return result, err
+}
@@ -1,6 +1,8 @@
 package hyperv_winrm
 
 import (
+	"sync"
+
 	"github.com/taliesins/terraform-provider-hyperv/api"
 	winrm_helper "github.com/taliesins/terraform-provider-hyperv/api/winrm-helper"
 )
@@ -12,5 +14,10 @@ func New(clientConfig *ClientConfig) (*api.Provider, error) {
 }
 
 type ClientConfig struct {
-	WinRmClient winrm_helper.Client
+	WinRmClient       winrm_helper.Client
+	WorkspaceRootPath string
+	RunId             string
+
+	hostCapabilitiesMutex sync.Mutex
+	hostCapabilities      *api.HostCapabilities
 }
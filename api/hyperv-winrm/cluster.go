@@ -0,0 +1,64 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getClusterPlacementArgs struct {
+	ClusterName         string
+	MemoryRequiredBytes int64
+}
+
+var getClusterPlacementTemplate = template.Must(template.New("GetClusterPlacement").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module FailoverClusters
+$memoryRequiredBytes = [int64]{{.MemoryRequiredBytes}}
+
+#the least loaded node is the node that is up with the most available memory
+$clusterNodeObject = Get-ClusterNode -Cluster '{{.ClusterName}}' | ?{ "$($_.State)" -eq 'Up' } | %{
+	$operatingSystem = Get-CimInstance -ClassName Win32_OperatingSystem -ComputerName $_.Name
+	[pscustomobject]@{
+		Name=$_.Name;
+		AvailableMemoryBytes=[int64]$operatingSystem.FreePhysicalMemory * 1024;
+	}
+} | ?{ $_.AvailableMemoryBytes -ge $memoryRequiredBytes } | Sort-Object -Property AvailableMemoryBytes -Descending | Select -First 1
+
+if (!$clusterNodeObject){
+	throw "No node in cluster {{.ClusterName}} is up with $memoryRequiredBytes bytes of memory available"
+}
+
+#the least loaded cluster shared volume is the online volume with the most free space
+$clusterSharedVolumeObject = Get-ClusterSharedVolume -Cluster '{{.ClusterName}}' | ?{ "$($_.State)" -eq 'Online' } | %{ $_.SharedVolumeInfo } | %{
+	[pscustomobject]@{
+		Path=$_.FriendlyVolumeName;
+		FreeSpaceBytes=[int64]$_.Partition.FreeSpace;
+	}
+} | Sort-Object -Property FreeSpaceBytes -Descending | Select -First 1
+
+if (!$clusterSharedVolumeObject){
+	throw "No cluster shared volume in cluster {{.ClusterName}} is online"
+}
+
+$clusterPlacementObject = @{
+	ClusterName='{{.ClusterName}}';
+	Node=$clusterNodeObject.Name;
+	NodeAvailableMemoryBytes=$clusterNodeObject.AvailableMemoryBytes;
+	SharedVolumePath=$clusterSharedVolumeObject.Path;
+	SharedVolumeFreeSpaceBytes=$clusterSharedVolumeObject.FreeSpaceBytes;
+}
+
+$clusterPlacement = ConvertTo-Json -InputObject $clusterPlacementObject
+$clusterPlacement
+`))
+
+func (c *ClientConfig) GetClusterPlacement(ctx context.Context, clusterName string, memoryRequiredBytes int64) (result api.ClusterPlacement, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getClusterPlacementTemplate, getClusterPlacementArgs{
+		ClusterName:         clusterName,
+		MemoryRequiredBytes: memoryRequiredBytes,
+	}, &result)
+
+	return result, err
+}
@@ -0,0 +1,166 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createClusterVmRoleArgs struct {
+	ClusterVmRoleJson string
+}
+
+var createClusterVmRoleTemplate = template.Must(template.New("CreateClusterVmRole").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module FailoverClusters
+$clusterVmRole = '{{.ClusterVmRoleJson}}' | ConvertFrom-Json
+$preferredOwners = @($clusterVmRole.PreferredOwners)
+
+$AddClusterVirtualMachineRoleArgs = @{}
+$AddClusterVirtualMachineRoleArgs.VMName=$clusterVmRole.VmName
+if ($clusterVmRole.Name) {
+	$AddClusterVirtualMachineRoleArgs.Name=$clusterVmRole.Name
+}
+
+$clusterGroup = Add-ClusterVirtualMachineRole @AddClusterVirtualMachineRoleArgs
+
+if ($preferredOwners) {
+	Set-ClusterOwnerNode -Group $clusterGroup.Name -Owners $preferredOwners | Out-Null
+}
+(Get-ClusterGroup -Name $clusterGroup.Name).Priority=[int]$clusterVmRole.Priority
+
+$clusterVmRoleObject = @{
+	VmName=$clusterVmRole.VmName;
+	Name=$clusterGroup.Name;
+}
+
+$clusterVmRole = ConvertTo-Json -InputObject $clusterVmRoleObject
+$clusterVmRole
+`))
+
+func (c *ClientConfig) CreateClusterVmRole(ctx context.Context, vmName string, name string, preferredOwners []string, priority api.ClusterGroupPriority) (result api.ClusterVmRole, err error) {
+	clusterVmRoleJson, err := json.Marshal(struct {
+		VmName          string
+		Name            string
+		PreferredOwners []string
+		Priority        int
+	}{
+		VmName:          vmName,
+		Name:            name,
+		PreferredOwners: preferredOwners,
+		Priority:        int(priority),
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, createClusterVmRoleTemplate, createClusterVmRoleArgs{
+		ClusterVmRoleJson: string(clusterVmRoleJson),
+	}, &result)
+
+	return result, err
+}
+
+type getClusterVmRoleArgs struct {
+	Name string
+}
+
+var getClusterVmRoleTemplate = template.Must(template.New("GetClusterVmRole").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module FailoverClusters
+$clusterGroupObject = Get-ClusterGroup -Name '{{.Name}}' -ErrorAction SilentlyContinue
+if (!$clusterGroupObject){
+	"{}"
+	return
+}
+
+$vmName = ""
+$clusterVmResource = $clusterGroupObject | Get-ClusterResource | ?{ $_.ResourceType.Name -eq 'Virtual Machine' } | Select -First 1
+if ($clusterVmResource){
+	$vmName = (Get-VM -ClusterObject $clusterVmResource -ErrorAction SilentlyContinue).Name
+}
+
+$clusterVmRoleObject = @{
+	VmName="$vmName";
+	Name=$clusterGroupObject.Name;
+	PreferredOwners=@(Get-ClusterOwnerNode -Group $clusterGroupObject.Name | %{ $_.OwnerNodes } | %{ $_.Name });
+	Priority=[int]$clusterGroupObject.Priority;
+	OwnerNode="$($clusterGroupObject.OwnerNode)";
+	State="$($clusterGroupObject.State)";
+}
+
+$clusterVmRole = ConvertTo-Json -InputObject $clusterVmRoleObject
+$clusterVmRole
+`))
+
+func (c *ClientConfig) GetClusterVmRole(ctx context.Context, name string) (result api.ClusterVmRole, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getClusterVmRoleTemplate, getClusterVmRoleArgs{
+		Name: name,
+	}, &result)
+
+	return result, err
+}
+
+type updateClusterVmRoleArgs struct {
+	ClusterVmRoleJson string
+}
+
+var updateClusterVmRoleTemplate = template.Must(template.New("UpdateClusterVmRole").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module FailoverClusters
+$clusterVmRole = '{{.ClusterVmRoleJson}}' | ConvertFrom-Json
+$preferredOwners = @($clusterVmRole.PreferredOwners)
+
+$clusterGroupObject = Get-ClusterGroup -Name $clusterVmRole.Name
+
+if ($preferredOwners) {
+	Set-ClusterOwnerNode -Group $clusterGroupObject.Name -Owners $preferredOwners | Out-Null
+} else {
+	Set-ClusterOwnerNode -Group $clusterGroupObject.Name -Owners @() | Out-Null
+}
+$clusterGroupObject.Priority=[int]$clusterVmRole.Priority
+`))
+
+func (c *ClientConfig) UpdateClusterVmRole(ctx context.Context, name string, preferredOwners []string, priority api.ClusterGroupPriority) (err error) {
+	clusterVmRoleJson, err := json.Marshal(struct {
+		Name            string
+		PreferredOwners []string
+		Priority        int
+	}{
+		Name:            name,
+		PreferredOwners: preferredOwners,
+		Priority:        int(priority),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateClusterVmRoleTemplate, updateClusterVmRoleArgs{
+		ClusterVmRoleJson: string(clusterVmRoleJson),
+	})
+
+	return err
+}
+
+type deleteClusterVmRoleArgs struct {
+	Name string
+}
+
+var deleteClusterVmRoleTemplate = template.Must(template.New("DeleteClusterVmRole").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module FailoverClusters
+#removes the role and its cluster resources from the cluster, the virtual machine itself is left on its owner node
+Get-ClusterGroup -Name '{{.Name}}' -ErrorAction SilentlyContinue | Remove-ClusterGroup -RemoveResources -Force
+`))
+
+func (c *ClientConfig) DeleteClusterVmRole(ctx context.Context, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteClusterVmRoleTemplate, deleteClusterVmRoleArgs{
+		Name: name,
+	})
+
+	return err
+}
@@ -0,0 +1,191 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// setDhcpServerScopeOptions sets the router, dns servers and dns domain of the scope in $dhcpServerScope, removing
+// the ones that are not set, and binds the DHCP server to the host network adapter of the switch
+const setDhcpServerScopeOptions = `
+$options = @(
+	@{ OptionId=3; Value=@($dhcpServerScope.Router | ?{ $_ }) },
+	@{ OptionId=6; Value=@($dhcpServerScope.DnsServers | ?{ $_ }) },
+	@{ OptionId=15; Value=@($dhcpServerScope.DnsDomain | ?{ $_ }) }
+)
+
+foreach ($option in $options) {
+	if ($option.Value.Length -gt 0) {
+		Set-DhcpServerv4OptionValue -ScopeId $scopeId -OptionId $option.OptionId -Value $option.Value -Force | Out-Null
+	} elseif (Get-DhcpServerv4OptionValue -ScopeId $scopeId -OptionId $option.OptionId -ErrorAction SilentlyContinue) {
+		Remove-DhcpServerv4OptionValue -ScopeId $scopeId -OptionId $option.OptionId | Out-Null
+	}
+}
+
+if ($dhcpServerScope.SwitchName) {
+	Set-DhcpServerv4Binding -InterfaceAlias "vEthernet ($($dhcpServerScope.SwitchName))" -BindingState $true | Out-Null
+}
+`
+
+type createDhcpServerScopeArgs struct {
+	DhcpServerScopeJson string
+}
+
+var createDhcpServerScopeTemplate = template.Must(template.New("CreateDhcpServerScope").Parse(`
+$ErrorActionPreference = 'Stop'
+$dhcpServerScope = '{{.DhcpServerScopeJson}}' | ConvertFrom-Json
+
+if (!(Get-Command Add-DhcpServerv4Scope -ErrorAction SilentlyContinue)) {
+	Install-WindowsFeature -Name DHCP -IncludeManagementTools | Out-Null
+	Add-DhcpServerSecurityGroup | Out-Null
+	Restart-Service DHCPServer
+	# tell server manager the post install configuration of the role is done
+	Set-ItemProperty -Path 'HKLM:\SOFTWARE\Microsoft\ServerManager\Roles\12' -Name ConfigurationState -Value 2
+}
+
+$AddDhcpServerv4ScopeArgs = @{}
+$AddDhcpServerv4ScopeArgs.Name=$dhcpServerScope.Name
+$AddDhcpServerv4ScopeArgs.Description=$dhcpServerScope.Description
+$AddDhcpServerv4ScopeArgs.StartRange=$dhcpServerScope.StartRange
+$AddDhcpServerv4ScopeArgs.EndRange=$dhcpServerScope.EndRange
+$AddDhcpServerv4ScopeArgs.SubnetMask=$dhcpServerScope.SubnetMask
+$AddDhcpServerv4ScopeArgs.LeaseDuration=New-TimeSpan -Seconds $dhcpServerScope.LeaseDurationSeconds
+$AddDhcpServerv4ScopeArgs.State='Active'
+
+$scopeId = "$((Add-DhcpServerv4Scope @AddDhcpServerv4ScopeArgs -PassThru).ScopeId)"
+` + setDhcpServerScopeOptions + `
+$dhcpServerScopeObject = @{
+	ScopeId=$scopeId;
+}
+
+$dhcpServerScope = ConvertTo-Json -InputObject $dhcpServerScopeObject
+$dhcpServerScope
+`))
+
+func (c *ClientConfig) CreateDhcpServerScope(ctx context.Context, dhcpServerScope api.DhcpServerScope) (result api.DhcpServerScope, err error) {
+	dhcpServerScopeJson, err := json.Marshal(dhcpServerScope)
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, createDhcpServerScopeTemplate, createDhcpServerScopeArgs{
+		DhcpServerScopeJson: string(dhcpServerScopeJson),
+	}, &result)
+
+	return result, err
+}
+
+type getDhcpServerScopeArgs struct {
+	ScopeId string
+}
+
+var getDhcpServerScopeTemplate = template.Must(template.New("GetDhcpServerScope").Parse(`
+$ErrorActionPreference = 'Stop'
+$scopeId = '{{.ScopeId}}'
+
+$dhcpServerScopeObject = $null
+if (Get-Command Get-DhcpServerv4Scope -ErrorAction SilentlyContinue) {
+	$dhcpServerScopeObject = Get-DhcpServerv4Scope -ScopeId $scopeId -ErrorAction SilentlyContinue | %{
+		$options = @(Get-DhcpServerv4OptionValue -ScopeId $scopeId -ErrorAction SilentlyContinue)
+		$router = $options | ?{ $_.OptionId -eq 3 } | Select -First 1
+		$dnsServers = $options | ?{ $_.OptionId -eq 6 } | Select -First 1
+		$dnsDomain = $options | ?{ $_.OptionId -eq 15 } | Select -First 1
+
+		# the scope does not know which switch it is for, so report the switches whose host network adapter the
+		# DHCP server is bound to
+		$boundSwitchNames = @(Get-DhcpServerv4Binding | %{
+			if ($_.BindingState -and $_.InterfaceAlias -match '^vEthernet \((.*)\)$') {
+				$Matches[1]
+			}
+		})
+
+		@{
+			ScopeId="$($_.ScopeId)";
+			Name=$_.Name;
+			Description="$($_.Description)";
+			StartRange="$($_.StartRange)";
+			EndRange="$($_.EndRange)";
+			SubnetMask="$($_.SubnetMask)";
+			LeaseDurationSeconds=[int64]$_.LeaseDuration.TotalSeconds;
+			Router=$(if ($router) { "$($router.Value | Select -First 1)" } else { "" });
+			DnsServers=@($(if ($dnsServers) { $dnsServers.Value } else { @() }));
+			DnsDomain=$(if ($dnsDomain) { "$($dnsDomain.Value | Select -First 1)" } else { "" });
+			BoundSwitchNames=$boundSwitchNames;
+		}
+	}
+}
+
+if ($dhcpServerScopeObject) {
+	$dhcpServerScope = ConvertTo-Json -InputObject $dhcpServerScopeObject
+	$dhcpServerScope
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetDhcpServerScope(ctx context.Context, scopeId string) (result api.DhcpServerScope, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getDhcpServerScopeTemplate, getDhcpServerScopeArgs{
+		ScopeId: scopeId,
+	}, &result)
+
+	return result, err
+}
+
+type updateDhcpServerScopeArgs struct {
+	DhcpServerScopeJson string
+}
+
+var updateDhcpServerScopeTemplate = template.Must(template.New("UpdateDhcpServerScope").Parse(`
+$ErrorActionPreference = 'Stop'
+$dhcpServerScope = '{{.DhcpServerScopeJson}}' | ConvertFrom-Json
+$scopeId = $dhcpServerScope.ScopeId
+
+$SetDhcpServerv4ScopeArgs = @{}
+$SetDhcpServerv4ScopeArgs.ScopeId=$scopeId
+$SetDhcpServerv4ScopeArgs.Name=$dhcpServerScope.Name
+$SetDhcpServerv4ScopeArgs.Description=$dhcpServerScope.Description
+$SetDhcpServerv4ScopeArgs.StartRange=$dhcpServerScope.StartRange
+$SetDhcpServerv4ScopeArgs.EndRange=$dhcpServerScope.EndRange
+$SetDhcpServerv4ScopeArgs.LeaseDuration=New-TimeSpan -Seconds $dhcpServerScope.LeaseDurationSeconds
+
+Set-DhcpServerv4Scope @SetDhcpServerv4ScopeArgs | Out-Null
+` + setDhcpServerScopeOptions + `
+`))
+
+func (c *ClientConfig) UpdateDhcpServerScope(ctx context.Context, dhcpServerScope api.DhcpServerScope) (err error) {
+	dhcpServerScopeJson, err := json.Marshal(dhcpServerScope)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateDhcpServerScopeTemplate, updateDhcpServerScopeArgs{
+		DhcpServerScopeJson: string(dhcpServerScopeJson),
+	})
+
+	return err
+}
+
+type deleteDhcpServerScopeArgs struct {
+	ScopeId string
+}
+
+var deleteDhcpServerScopeTemplate = template.Must(template.New("DeleteDhcpServerScope").Parse(`
+$ErrorActionPreference = 'Stop'
+
+if (Get-Command Get-DhcpServerv4Scope -ErrorAction SilentlyContinue) {
+	Get-DhcpServerv4Scope -ScopeId '{{.ScopeId}}' -ErrorAction SilentlyContinue | Remove-DhcpServerv4Scope -Force
+}
+`))
+
+func (c *ClientConfig) DeleteDhcpServerScope(ctx context.Context, scopeId string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteDhcpServerScopeTemplate, deleteDhcpServerScopeArgs{
+		ScopeId: scopeId,
+	})
+
+	return err
+}
@@ -0,0 +1,55 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getPhysicalDisksArgs struct {
+	FriendlyName string
+	OfflineOnly  bool
+}
+
+var getPhysicalDisksTemplate = template.Must(template.New("GetPhysicalDisks").Parse(`
+$ErrorActionPreference = 'Stop'
+$offlineOnly = ${{.OfflineOnly}}
+
+$disksObject = @(Get-Disk -FriendlyName '{{.FriendlyName}}' -ErrorAction SilentlyContinue | ?{ !$offlineOnly -or $_.IsOffline } | Sort-Object -Property Number | %{ @{
+	Number=$_.Number;
+	FriendlyName=$_.FriendlyName;
+	SerialNumber="$($_.SerialNumber)".Trim();
+	UniqueId=$_.UniqueId;
+	SizeBytes=[int64]$_.Size;
+	BusType="$($_.BusType)";
+	PartitionStyle="$($_.PartitionStyle)";
+	OperationalStatus="$($_.OperationalStatus)";
+	IsOffline=$_.IsOffline;
+	IsReadOnly=$_.IsReadOnly;
+	IsBoot=$_.IsBoot;
+	IsSystem=$_.IsSystem;
+}})
+
+if ($disksObject) {
+	$disks = ConvertTo-Json -InputObject $disksObject
+	$disks
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetPhysicalDisks(ctx context.Context, friendlyName string, offlineOnly bool) (result []api.Disk, err error) {
+	result = make([]api.Disk, 0)
+
+	if friendlyName == "" {
+		friendlyName = "*"
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getPhysicalDisksTemplate, getPhysicalDisksArgs{
+		FriendlyName: friendlyName,
+		OfflineOnly:  offlineOnly,
+	}, &result)
+
+	return result, err
+}
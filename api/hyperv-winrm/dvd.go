@@ -2,32 +2,47 @@ package hyperv_winrm
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"text/template"
 
 	"github.com/taliesins/terraform-provider-hyperv/api"
 )
 
 type createDvdArgs struct {
-	Path string
-	Ip   string
+	Path              string
+	NetworkConfigJson string
+	WorkspacePath     string
 }
 
 var createDvdTemplate = template.Must(template.New("CreateDvd").Parse(`
 $ErrorActionPreference = 'Stop'
 $path='{{.Path}}'
-$ip='{{.Ip}}'
+$networkConfig = '{{.NetworkConfigJson}}' | ConvertFrom-Json
+
+$ethernet = @{
+    "dhcp4"=$(if ($networkConfig.Dhcp4) { "yes" } else { "no" })
+    "dhcp6"=$(if ($networkConfig.Dhcp6) { "yes" } else { "no" })
+}
+if ($networkConfig.Addresses) {
+    $ethernet["addresses"] = @($networkConfig.Addresses)
+}
+if ($networkConfig.Gateway4) {
+    $ethernet["gateway4"] = $networkConfig.Gateway4
+}
+if ($networkConfig.Gateway6) {
+    $ethernet["gateway6"] = $networkConfig.Gateway6
+}
+if ($networkConfig.Nameservers) {
+    $ethernet["nameservers"] = @{
+        "addresses"=@($networkConfig.Nameservers)
+    }
+}
 
 $yamlContent = @{
     "network"=@{
         "ethernets"=@{
-            "eth0"=@{
-                "dhcp4"="no"
-                "gateway4"="172.16.1.254"
-                "addresses" = @("$ip/16")
-                "nameservers"=@{
-                    "addresses"=@("172.16.14.27")
-                }
-            }
+            "eth0"=$ethernet
         }
     }
 }
@@ -38,23 +53,161 @@ if (-not (Test-Path -Path $folderPath -PathType Container)){
     New-Item -ItemType Directory -Path $folderPath | Out-Null
 }
 
-$tmpPath = Split-Path -Path $folderPath -Parent
-$tmpPath += "\tmp"
+$tmpPath = Join-Path '{{.WorkspacePath}}' ([System.Guid]::NewGuid().ToString())
+New-Item -ItemType Directory -Force -Path $tmpPath | Out-Null
 
-if (-not (Test-Path -Path $tmpPath -PathType Container)){
-    New-Item -ItemType Directory -Path $tmpPath | Out-Null
+try {
+    $yamlContent | ConvertTo-Yaml | Out-File -FilePath "$tmpPath\network_settings.yaml" -Encoding UTF8
+    oscdimg -n -d -m $tmpPath $path
+} finally {
+    Remove-Item -LiteralPath $tmpPath -Force -Recurse -ErrorAction SilentlyContinue
 }
 
-$yamlContent | ConvertTo-Yaml | Out-File -FilePath "$tmpPath\network_settings.yaml" -Encoding UTF8 
-oscdimg -n -d -m $tmpPath $path
-Remove-Item -LiteralPath $tmpPath -Force -Recurse
-
 `))
 
-func (c *ClientConfig) CreateDvd(ctx context.Context, path string, ip string) (err error) {
+func (c *ClientConfig) CreateDvd(ctx context.Context, path string, networkConfig api.DvdNetworkConfig) (err error) {
+	networkConfigJson, err := json.Marshal(networkConfig.Normalize())
+
+	if err != nil {
+		return err
+	}
+
 	err = c.WinRmClient.RunFireAndForgetScript(ctx, createDvdTemplate, createDvdArgs{
-		Path: path,
-		Ip:   ip,
+		Path:              path,
+		NetworkConfigJson: string(networkConfigJson),
+		WorkspacePath:     c.WorkspacePath(),
+	})
+
+	return err
+}
+
+type createCloudInitDvdArgs struct {
+	Path                string
+	UserDataBase64      string `sensitive:"true"`
+	MetaDataBase64      string
+	NetworkConfigBase64 string
+	WorkspacePath       string
+}
+
+var createCloudInitDvdTemplate = template.Must(template.New("CreateCloudInitDvd").Parse(`
+$ErrorActionPreference = 'Stop'
+` + retryOnFileLockFunction + `
+$path='{{.Path}}'
+
+if (!(Get-Command oscdimg -ErrorAction SilentlyContinue)) {
+	throw "oscdimg is not available on $($env:COMPUTERNAME), install the Windows ADK deployment tools and add oscdimg to the path"
+}
+
+$folderPath = Split-Path -Path $path -Parent
+
+if (-not (Test-Path -Path $folderPath -PathType Container)){
+	New-Item -ItemType Directory -Path $folderPath | Out-Null
+}
+
+$tmpPath = Join-Path '{{.WorkspacePath}}' ([System.Guid]::NewGuid().ToString())
+New-Item -ItemType Directory -Force -Path $tmpPath | Out-Null
+
+try {
+	# cloud-init reads the files as they are, so they are written without a byte order mark
+	[System.IO.File]::WriteAllBytes("$tmpPath\user-data", [System.Convert]::FromBase64String('{{.UserDataBase64}}'))
+	[System.IO.File]::WriteAllBytes("$tmpPath\meta-data", [System.Convert]::FromBase64String('{{.MetaDataBase64}}'))
+	if ('{{.NetworkConfigBase64}}') {
+		[System.IO.File]::WriteAllBytes("$tmpPath\network-config", [System.Convert]::FromBase64String('{{.NetworkConfigBase64}}'))
+	}
+
+	# the seed is built next to the iso and swapped in, so a failed build leaves the previous seed in place
+	$seedPath = "$path.$([System.Guid]::NewGuid().ToString()).tmp"
+	oscdimg -n -d -m -lcidata $tmpPath $seedPath
+	if ($LASTEXITCODE -ne 0) {
+		throw "Unable to build cloud-init seed $($path): $LASTEXITCODE"
+	}
+	Invoke-RetryOnFileLock -ScriptBlock { param($source, $destination) Move-Item -LiteralPath $source -Destination $destination -Force } -ArgumentList $seedPath, $path
+} finally {
+	Remove-Item -LiteralPath $tmpPath -Force -Recurse -ErrorAction SilentlyContinue
+	if ($seedPath -and (Test-Path -LiteralPath $seedPath)) {
+		Remove-Item -LiteralPath $seedPath -Force
+	}
+}
+`))
+
+func (c *ClientConfig) CreateCloudInitDvd(ctx context.Context, cloudInit api.VmCloudInit) (err error) {
+	// the files are passed separately, they are yaml that could contain anything that would need escaping in the script
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createCloudInitDvdTemplate, createCloudInitDvdArgs{
+		Path:                cloudInit.Path,
+		UserDataBase64:      base64.StdEncoding.EncodeToString([]byte(cloudInit.UserData)),
+		MetaDataBase64:      base64.StdEncoding.EncodeToString([]byte(cloudInit.MetaData)),
+		NetworkConfigBase64: base64.StdEncoding.EncodeToString([]byte(cloudInit.NetworkConfig)),
+		WorkspacePath:       c.WorkspacePath(),
+	})
+
+	return err
+}
+
+// ephemeralDvdDirectory is where the scripts of the scheduled tasks that delete ephemeral dvds are stored on the HyperV
+// host machine, they have to outlive the workspace of a provider run. The tasks run as SYSTEM, so it is a protected
+// directory.
+const ephemeralDvdDirectory = `$ephemeralDvdDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\ephemeral-dvds'`
+
+type createEphemeralDvdWatcherArgs struct {
+	Path     string
+	VmName   string
+	Timeout  uint32
+	TaskName string
+}
+
+var createEphemeralDvdWatcherTemplate = template.Must(template.New("CreateEphemeralDvdWatcher").Parse(`
+$ErrorActionPreference = 'Stop'
+$path = '{{.Path}}'
+$vmName = '{{.VmName}}'
+$timeout = {{.Timeout}}
+$taskName = '{{.TaskName}}'
+` + protectedDirectoryFunctions + `
+` + ephemeralDvdDirectory + `
+
+Initialize-ProtectedDirectory -Path $ephemeralDvdDirectory
+
+$scriptPath = Join-Path $ephemeralDvdDirectory "$taskName.ps1"
+Assert-ProtectedFile -Path $scriptPath
+
+@'
+param($Path, $VmName, $Deadline, $TaskName)
+$ErrorActionPreference = 'Stop'
+
+while ([DateTime]::UtcNow.Ticks -lt [long]$Deadline) {
+	$vmObject = Get-VM -Name "$($VmName)*" -ErrorAction SilentlyContinue | ?{$_.Name -eq $VmName}
+	if ($vmObject -and "$($vmObject.Heartbeat)".StartsWith('Ok')) {
+		break
+	}
+	Start-Sleep -Seconds 10
+}
+
+Get-VM | Get-VMDvdDrive | ?{ $_.Path -eq $Path } | Set-VMDvdDrive -Path $null
+if (Test-Path -LiteralPath $Path) {
+	Remove-Item -LiteralPath $Path -Force
+}
+
+Unregister-ScheduledTask -TaskName $TaskName -TaskPath '\terraform-provider-hyperv\' -Confirm:$false
+Remove-Item -LiteralPath $PSCommandPath -Force
+'@ | Set-Content -LiteralPath $scriptPath -Encoding UTF8
+Set-ProtectedFileOwner -Path $scriptPath
+
+# the deadline is absolute, so that it does not start over when the task is started again after the host restarts
+$deadline = [DateTime]::UtcNow.AddSeconds($timeout).Ticks
+$argument = '-NoProfile -NonInteractive -ExecutionPolicy Bypass -File "' + $scriptPath + '" -Path "' + $path + '" -VmName "' + $vmName + '" -Deadline ' + $deadline + ' -TaskName "' + $taskName + '"'
+
+$action = New-ScheduledTaskAction -Execute 'powershell.exe' -Argument $argument
+$triggers = @((New-ScheduledTaskTrigger -Once -At (Get-Date).AddSeconds(10)), (New-ScheduledTaskTrigger -AtStartup))
+$principal = New-ScheduledTaskPrincipal -UserId 'SYSTEM' -LogonType ServiceAccount -RunLevel Highest
+$settings = New-ScheduledTaskSettingsSet -StartWhenAvailable -MultipleInstances IgnoreNew -ExecutionTimeLimit (New-TimeSpan -Seconds ($timeout + 600))
+Register-ScheduledTask -TaskName $taskName -TaskPath '\terraform-provider-hyperv\' -Action $action -Trigger $triggers -Principal $principal -Settings $settings -Force | Out-Null
+`))
+
+func (c *ClientConfig) CreateEphemeralDvdWatcher(ctx context.Context, path string, vmName string, timeout uint32) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createEphemeralDvdWatcherTemplate, createEphemeralDvdWatcherArgs{
+		Path:     path,
+		VmName:   vmName,
+		Timeout:  timeout,
+		TaskName: api.EphemeralDvdTaskName(path),
 	})
 
 	return err
@@ -92,25 +245,36 @@ func (c *ClientConfig) GetDvd(ctx context.Context, path string, ip string) (resu
 }
 
 type deleteDvdArgs struct {
-	Path string
+	Path     string
+	TaskName string
 }
 
 var deleteDvdTemplate = template.Must(template.New("DeleteDvd").Parse(`
 $ErrorActionPreference = 'Stop'
+` + retryOnFileLockFunction + `
+` + ephemeralDvdDirectory + `
+
+# an ephemeral dvd that is deleted before the vm booted must not leave its scheduled task behind
+Get-ScheduledTask -TaskPath '\terraform-provider-hyperv\' -TaskName '{{.TaskName}}' -ErrorAction SilentlyContinue | Unregister-ScheduledTask -Confirm:$false
+$scriptPath = Join-Path $ephemeralDvdDirectory '{{.TaskName}}.ps1'
+if (Test-Path -LiteralPath $scriptPath) {
+	Remove-Item -LiteralPath $scriptPath -Force
+}
 
 $targetDirectory = (split-path '{{.Path}}' -Parent)
 $targetName = (split-path '{{.Path}}' -Leaf)
 $targetName = $targetName.Substring(0,$targetName.LastIndexOf('.')).split('\')[-1]
 
 Get-ChildItem -Path $targetDirectory |?{$_.BaseName.StartsWith($targetName)} | %{
-	Remove-Item $_.FullName -Force
+	Invoke-RetryOnFileLock -ScriptBlock { param($fullName) Remove-Item $fullName -Force } -ArgumentList $_.FullName
 }
 
 `))
 
 func (c *ClientConfig) DeleteDvd(ctx context.Context, path string) (err error) {
 	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteDvdTemplate, deleteDvdArgs{
-		Path: path,
+		Path:     path,
+		TaskName: api.EphemeralDvdTaskName(path),
 	})
 
 	return err
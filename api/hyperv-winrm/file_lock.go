@@ -0,0 +1,41 @@
+package hyperv_winrm
+
+// retryOnFileLockFunction defines a function that runs a script block again, waiting longer each time, while it fails
+// because a file is in use. HyperV holds handles to virtual hard disks and isos for a while after a virtual machine
+// stopped, so deleting or resizing them straight away frequently fails with "file is in use by another process".
+// The HRESULTs are those of ERROR_SHARING_VIOLATION, ERROR_LOCK_VIOLATION and ERROR_USER_MAPPED_FILE.
+const retryOnFileLockFunction = `
+function Invoke-RetryOnFileLock {
+	param(
+		[Parameter(Mandatory=$true)][ScriptBlock]$ScriptBlock,
+		[object[]]$ArgumentList = @()
+	)
+
+	$fileLockHResults = @(-2147024864, -2147024863, -2147023672)
+	$maximumAttempts = 8
+	$delaySeconds = 1
+
+	for ($attempt = 1; ; $attempt++) {
+		try {
+			return & $ScriptBlock @ArgumentList
+		} catch {
+			$isFileLock = $false
+			$exception = $_.Exception
+			while ($exception) {
+				if (($fileLockHResults -contains $exception.HResult) -or ($exception.Message -match 'being used by another process')) {
+					$isFileLock = $true
+				}
+				$exception = $exception.InnerException
+			}
+
+			if (!$isFileLock -or $attempt -ge $maximumAttempts) {
+				throw
+			}
+
+			Write-Verbose "File is in use, retrying in $delaySeconds seconds (attempt $attempt of $maximumAttempts)"
+			Start-Sleep -Seconds $delaySeconds
+			$delaySeconds = [Math]::Min($delaySeconds * 2, 30)
+		}
+	}
+}
+`
@@ -0,0 +1,53 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getPartitionableGpusArgs struct {
+}
+
+var getPartitionableGpusTemplate = template.Must(template.New("GetPartitionableGpus").Parse(`
+$ErrorActionPreference = 'Stop'
+
+$partitionableGpusObject = @(Get-VMHostPartitionableGpu | Sort-Object -Property Name | %{
+	$instanceIdParts = $_.Name -split '#'
+	$friendlyName = ''
+	if ($instanceIdParts.Length -gt 2) {
+		$pnpDevice = Get-PnpDevice -InstanceId "PCI\$($instanceIdParts[1])\$($instanceIdParts[2])" -ErrorAction SilentlyContinue
+		if ($pnpDevice) {
+			$friendlyName = $pnpDevice.FriendlyName
+		}
+	}
+
+	@{
+		Name=$_.Name;
+		FriendlyName=$friendlyName;
+		PartitionCount=$_.PartitionCount;
+		ValidPartitionCounts=@($_.ValidPartitionCounts | %{ [int]$_ });
+		TotalVramBytes=[int64]$_.TotalVRAM;
+		AvailableVramBytes=[int64]$_.AvailableVRAM;
+		MinPartitionVramBytes=[int64]$_.MinPartitionVRAM;
+		MaxPartitionVramBytes=[int64]$_.MaxPartitionVRAM;
+		OptimalPartitionVramBytes=[int64]$_.OptimalPartitionVRAM;
+	}
+})
+
+if ($partitionableGpusObject) {
+	$partitionableGpus = ConvertTo-Json -InputObject $partitionableGpusObject
+	$partitionableGpus
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetPartitionableGpus(ctx context.Context) (result []api.PartitionableGpu, err error) {
+	result = make([]api.PartitionableGpu, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getPartitionableGpusTemplate, getPartitionableGpusArgs{}, &result)
+
+	return result, err
+}
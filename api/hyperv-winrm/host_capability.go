@@ -0,0 +1,169 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getHostCapabilitiesArgs struct {
+}
+
+var getHostCapabilitiesTemplate = template.Must(template.New("GetHostCapabilities").Parse(`
+$ErrorActionPreference = 'Stop'
+
+#New-VM only has the guest state isolation type from Windows Server 2025
+$guestStateIsolationTypes = @()
+$guestStateIsolationTypeParameter = (Get-Command New-VM).Parameters['GuestStateIsolationType']
+if ($guestStateIsolationTypeParameter) {
+	$guestStateIsolationTypeParameterType = $guestStateIsolationTypeParameter.ParameterType
+	if ($guestStateIsolationTypeParameterType.IsGenericType) {
+		$guestStateIsolationTypeParameterType = $guestStateIsolationTypeParameterType.GetGenericArguments()[0]
+	}
+
+	if ($guestStateIsolationTypeParameterType.IsEnum) {
+		$guestStateIsolationTypes = @([enum]::GetNames($guestStateIsolationTypeParameterType))
+	} else {
+		$guestStateIsolationTypes = @($guestStateIsolationTypeParameter.Attributes | ?{ $_ -is [System.Management.Automation.ValidateSetAttribute] } | %{ $_.ValidValues })
+	}
+}
+
+#the hypervisor logs the type of its scheduler when the HyperV host machine starts
+$hypervisorSchedulerType = ''
+$schedulerEvent = Get-WinEvent -FilterHashtable @{ProviderName='Microsoft-Windows-Hyper-V-Hypervisor'; Id=2} -MaxEvents 1 -ErrorAction SilentlyContinue
+if ($schedulerEvent) {
+	switch ([int]$schedulerEvent.Properties[0].Value) {
+		1 { $hypervisorSchedulerType = 'Classic' }
+		2 { $hypervisorSchedulerType = 'Classic' }
+		3 { $hypervisorSchedulerType = 'Core' }
+		4 { $hypervisorSchedulerType = 'Root' }
+	}
+}
+
+$operatingSystem = Get-CimInstance -ClassName Win32_OperatingSystem
+
+$hostCapabilitiesObject = @{
+	ComputerName=$env:COMPUTERNAME;
+	HypervVersion=(Get-Item -Path "$env:SystemRoot\System32\vmms.exe").VersionInfo.ProductVersion;
+	DefaultVmVersion="$((Get-VMHostSupportedVersion -Default).Version)";
+	SupportedVmVersions=@(Get-VMHostSupportedVersion | %{ "$($_.Version)" });
+	SecureBootTemplates=@(Get-CimInstance -Namespace root\virtualization\v2 -ClassName Msvm_SecureBootTemplate -ErrorAction SilentlyContinue | %{ $_.ElementName });
+	OscdimgAvailable=[bool](Get-Command oscdimg -ErrorAction SilentlyContinue);
+	ConvertToYamlAvailable=[bool](Get-Command ConvertTo-Yaml -ErrorAction SilentlyContinue);
+	AvmaSupported=$operatingSystem.Caption -match 'Datacenter';
+	GuestStateIsolationTypes=$guestStateIsolationTypes;
+	ServerOperatingSystem=$operatingSystem.ProductType -ne 1;
+	HypervisorSchedulerType=$hypervisorSchedulerType;
+	LogicalProcessorCount=(Get-VMHost).LogicalProcessorCount;
+}
+
+$hostCapabilities = ConvertTo-Json -InputObject $hostCapabilitiesObject
+$hostCapabilities
+`))
+
+func (c *ClientConfig) GetHostCapabilities(ctx context.Context) (result api.HostCapabilities, err error) {
+	c.hostCapabilitiesMutex.Lock()
+	defer c.hostCapabilitiesMutex.Unlock()
+
+	if c.hostCapabilities != nil {
+		return *c.hostCapabilities, nil
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getHostCapabilitiesTemplate, getHostCapabilitiesArgs{}, &result)
+	if err != nil {
+		return result, err
+	}
+
+	log.Printf("[DEBUG] Cached host capabilities: %+v", result)
+	c.hostCapabilities = &result
+
+	return result, nil
+}
+
+func (c *ClientConfig) InvalidateHostCapabilities() {
+	c.hostCapabilitiesMutex.Lock()
+	defer c.hostCapabilitiesMutex.Unlock()
+
+	c.hostCapabilities = nil
+}
+
+func (c *ClientConfig) RequireIsoTools(ctx context.Context) (err error) {
+	hostCapabilities, err := c.GetHostCapabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !hostCapabilities.OscdimgAvailable {
+		return fmt.Errorf("oscdimg is not available on %s, install the Windows ADK deployment tools and add oscdimg to the path", hostCapabilities.ComputerName)
+	}
+
+	if !hostCapabilities.ConvertToYamlAvailable {
+		return fmt.Errorf("ConvertTo-Yaml is not available on %s, install the powershell-yaml module", hostCapabilities.ComputerName)
+	}
+
+	return nil
+}
+
+func (c *ClientConfig) RequireSecureBootTemplate(ctx context.Context, name string) (err error) {
+	hostCapabilities, err := c.GetHostCapabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !hostCapabilities.HasSecureBootTemplate(name) {
+		return fmt.Errorf("secure boot template %s does not exist on %s, valid values to use are %s", name, hostCapabilities.ComputerName, strings.Join(hostCapabilities.SecureBootTemplates, ", "))
+	}
+
+	return nil
+}
+
+func (c *ClientConfig) RequireAvma(ctx context.Context) (err error) {
+	hostCapabilities, err := c.GetHostCapabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !hostCapabilities.AvmaSupported {
+		return fmt.Errorf("automatic virtual machine activation is not available on %s, it needs a Datacenter edition of Windows Server that is activated", hostCapabilities.ComputerName)
+	}
+
+	return nil
+}
+
+func (c *ClientConfig) RequireGuestStateIsolationType(ctx context.Context, guestStateIsolationType api.GuestStateIsolationType) (err error) {
+	hostCapabilities, err := c.GetHostCapabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(hostCapabilities.GuestStateIsolationTypes) == 0 && guestStateIsolationType != api.GuestStateIsolationType_Disabled {
+		return fmt.Errorf("guest state isolation is not available on %s, it needs Windows Server 2025 or later", hostCapabilities.ComputerName)
+	}
+
+	if !hostCapabilities.SupportsGuestStateIsolationType(guestStateIsolationType) {
+		return fmt.Errorf("guest state isolation type %s is not available on %s, valid values to use are %s", guestStateIsolationType, hostCapabilities.ComputerName, strings.Join(hostCapabilities.GuestStateIsolationTypes, ", "))
+	}
+
+	return nil
+}
+
+func (c *ClientConfig) RequireCpuGroups(ctx context.Context) (err error) {
+	hostCapabilities, err := c.GetHostCapabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !hostCapabilities.ServerOperatingSystem {
+		return fmt.Errorf("cpu groups are not available on %s, they need Windows Server", hostCapabilities.ComputerName)
+	}
+
+	if !hostCapabilities.SupportsCpuGroups() {
+		return fmt.Errorf("cpu groups are not available on %s, the %s scheduler of the hypervisor ignores them - use the core or classic scheduler", hostCapabilities.ComputerName, hostCapabilities.HypervisorSchedulerType)
+	}
+
+	return nil
+}
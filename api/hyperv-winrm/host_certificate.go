@@ -0,0 +1,212 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// getHostCertificate writes the certificate $thumbprint together with the WinRM HTTPS listener and the firewall rule
+// that use it as json, or "{}" when the certificate does not exist
+const getHostCertificate = `
+$certificate = Get-Item -LiteralPath "Cert:\LocalMachine\My\$thumbprint" -ErrorAction SilentlyContinue
+if (!$certificate) {
+	"{}"
+	return
+}
+
+$listener = Get-WSManInstance -ResourceURI winrm/config/Listener -Enumerate | ?{ $_.Transport -eq 'HTTPS' -and ($_.CertificateThumbprint -replace '\s', '') -eq $certificate.Thumbprint } | Select -First 1
+
+$hostname = ""
+$port = 0
+$firewallRule = $false
+$firewallRemoteAddresses = @()
+if ($listener) {
+	$hostname = $listener.Hostname
+	$port = [int]$listener.Port
+
+	$netFirewallRule = Get-NetFirewallRule -Name "$firewallRuleNamePrefix$port" -ErrorAction SilentlyContinue
+	if ($netFirewallRule) {
+		$firewallRule = "$($netFirewallRule.Enabled)" -eq 'True' -and "$($netFirewallRule.Action)" -eq 'Allow'
+		$firewallRemoteAddresses = @(($netFirewallRule | Get-NetFirewallAddressFilter).RemoteAddress | ?{ $_ -and $_ -ne 'Any' })
+	}
+}
+
+$hostCertificateObject = @{
+	Thumbprint=$certificate.Thumbprint;
+	Subject=$certificate.Subject;
+	Issuer=$certificate.Issuer;
+	DnsNames=@($certificate.DnsNameList | %{ $_.Unicode });
+	NotAfter=$certificate.NotAfter.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ');
+	SelfSigned=$certificate.Subject -eq $certificate.Issuer;
+	CertificateBase64=[Convert]::ToBase64String($certificate.RawData);
+	Hostname=$hostname;
+	Port=$port;
+	FirewallRule=$firewallRule;
+	FirewallRemoteAddresses=$firewallRemoteAddresses;
+}
+
+$hostCertificate = ConvertTo-Json -InputObject $hostCertificateObject
+$hostCertificate
+`
+
+type createOrUpdateHostCertificateArgs struct {
+	Thumbprint             string
+	FirewallRuleName       string
+	FirewallRuleNamePrefix string
+	SettingsJson           string
+	PfxBase64              string `sensitive:"true"`
+	PfxPasswordBase64      string `sensitive:"true"`
+}
+
+var createOrUpdateHostCertificateTemplate = template.Must(template.New("CreateOrUpdateHostCertificate").Parse(`
+$ErrorActionPreference = 'Stop'
+$settings = '{{.SettingsJson}}' | ConvertFrom-Json
+$thumbprint = '{{.Thumbprint}}'
+$firewallRuleName = '{{.FirewallRuleName}}'
+$firewallRuleNamePrefix = '{{.FirewallRuleNamePrefix}}'
+$pfxBase64 = '{{.PfxBase64}}'
+$pfxPasswordBase64 = '{{.PfxPasswordBase64}}'
+
+if ($thumbprint) {
+	$certificate = Get-Item -LiteralPath "Cert:\LocalMachine\My\$thumbprint"
+} elseif ($pfxBase64) {
+	$pfxPath = [System.IO.Path]::GetTempFileName()
+	try {
+		[System.IO.File]::WriteAllBytes($pfxPath, [Convert]::FromBase64String($pfxBase64))
+		if ($pfxPasswordBase64) {
+			$pfxPassword = ConvertTo-SecureString -String ([System.Text.Encoding]::UTF8.GetString([Convert]::FromBase64String($pfxPasswordBase64))) -AsPlainText -Force
+		} else {
+			$pfxPassword = New-Object System.Security.SecureString
+		}
+
+		#the pfx may hold the chain of the certificate authority as well, the certificate of the host has the private key
+		$certificate = Import-PfxCertificate -FilePath $pfxPath -CertStoreLocation Cert:\LocalMachine\My -Password $pfxPassword | ?{ $_.HasPrivateKey } | Select -First 1
+	} finally {
+		Remove-Item -LiteralPath $pfxPath -Force
+	}
+
+	if (!$certificate) {
+		throw "Pfx for $($settings.Hostname) does not contain a certificate with a private key"
+	}
+} else {
+	$dnsNames = @($settings.Hostname) + @($settings.SubjectAlternativeNames | ?{ $_ })
+	$certificate = New-SelfSignedCertificate -DnsName $dnsNames -CertStoreLocation Cert:\LocalMachine\My -NotAfter (Get-Date).AddYears($settings.ValidityYears) -KeyAlgorithm RSA -KeyLength 2048 -KeyUsage DigitalSignature,KeyEncipherment -TextExtension @('2.5.29.37={text}1.3.6.1.5.5.7.3.1')
+}
+$thumbprint = $certificate.Thumbprint
+
+$winRmService = Get-Service -Name WinRM
+if ($winRmService.StartType -ne 'Automatic') {
+	Set-Service -Name WinRM -StartupType Automatic
+}
+if ($winRmService.Status -ne 'Running') {
+	Start-Service -Name WinRM
+}
+
+#there can only be one HTTPS listener for an address, so the listener is replaced when any of its settings change
+$listener = Get-WSManInstance -ResourceURI winrm/config/Listener -SelectorSet @{Address='*';Transport='HTTPS'} -ErrorAction SilentlyContinue
+if (!$listener -or ($listener.CertificateThumbprint -replace '\s', '') -ne $thumbprint -or [int]$listener.Port -ne $settings.Port -or $listener.Hostname -ne $settings.Hostname) {
+	if ($listener) {
+		Remove-WSManInstance -ResourceURI winrm/config/Listener -SelectorSet @{Address='*';Transport='HTTPS'}
+	}
+
+	New-WSManInstance -ResourceURI winrm/config/Listener -SelectorSet @{Address='*';Transport='HTTPS'} -ValueSet @{Hostname=$settings.Hostname;CertificateThumbprint=$thumbprint;Port="$($settings.Port)"} | Out-Null
+}
+
+Get-NetFirewallRule -Name "$firewallRuleNamePrefix*" -ErrorAction SilentlyContinue | ?{ !$settings.FirewallRule -or $_.Name -ne $firewallRuleName } | Remove-NetFirewallRule
+
+if ($settings.FirewallRule) {
+	$remoteAddresses = @($settings.FirewallRemoteAddresses | ?{ $_ })
+	if (!$remoteAddresses) {
+		$remoteAddresses = @('Any')
+	}
+
+	if (Get-NetFirewallRule -Name $firewallRuleName -ErrorAction SilentlyContinue) {
+		Set-NetFirewallRule -Name $firewallRuleName -Enabled True -Action Allow -Direction Inbound -Protocol TCP -LocalPort $settings.Port -RemoteAddress $remoteAddresses
+	} else {
+		New-NetFirewallRule -Name $firewallRuleName -DisplayName "Windows Remote Management (HTTPS-In) $($settings.Port)" -Enabled True -Action Allow -Direction Inbound -Protocol TCP -LocalPort $settings.Port -RemoteAddress $remoteAddresses -Profile Any | Out-Null
+	}
+}
+` + getHostCertificate + `
+`))
+
+func (c *ClientConfig) CreateOrUpdateHostCertificate(ctx context.Context, thumbprint string, settings api.HostCertificateSettings) (result api.HostCertificate, err error) {
+	// the pfx and its password are passed separately, so that they are left out of the logged script
+	pfxBase64 := settings.PfxBase64
+	pfxPasswordBase64 := ""
+	if settings.PfxPassword != "" {
+		pfxPasswordBase64 = base64.StdEncoding.EncodeToString([]byte(settings.PfxPassword))
+	}
+	settings.PfxBase64 = ""
+	settings.PfxPassword = ""
+
+	settingsJson, err := json.Marshal(settings)
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, createOrUpdateHostCertificateTemplate, createOrUpdateHostCertificateArgs{
+		Thumbprint:             thumbprint,
+		FirewallRuleName:       api.HostCertificateFirewallRuleName(settings.Port),
+		FirewallRuleNamePrefix: api.HostCertificateFirewallRuleNamePrefix,
+		SettingsJson:           string(settingsJson),
+		PfxBase64:              pfxBase64,
+		PfxPasswordBase64:      pfxPasswordBase64,
+	}, &result)
+
+	return result, err
+}
+
+type getHostCertificateArgs struct {
+	Thumbprint             string
+	FirewallRuleNamePrefix string
+}
+
+var getHostCertificateTemplate = template.Must(template.New("GetHostCertificate").Parse(`
+$ErrorActionPreference = 'Stop'
+$thumbprint = '{{.Thumbprint}}'
+$firewallRuleNamePrefix = '{{.FirewallRuleNamePrefix}}'
+` + getHostCertificate + `
+`))
+
+func (c *ClientConfig) GetHostCertificate(ctx context.Context, thumbprint string) (result api.HostCertificate, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getHostCertificateTemplate, getHostCertificateArgs{
+		Thumbprint:             thumbprint,
+		FirewallRuleNamePrefix: api.HostCertificateFirewallRuleNamePrefix,
+	}, &result)
+
+	return result, err
+}
+
+type deleteHostCertificateArgs struct {
+	Thumbprint             string
+	FirewallRuleNamePrefix string
+}
+
+var deleteHostCertificateTemplate = template.Must(template.New("DeleteHostCertificate").Parse(`
+$ErrorActionPreference = 'Stop'
+$thumbprint = '{{.Thumbprint}}'
+$firewallRuleNamePrefix = '{{.FirewallRuleNamePrefix}}'
+
+Get-WSManInstance -ResourceURI winrm/config/Listener -Enumerate | ?{ $_.Transport -eq 'HTTPS' -and ($_.CertificateThumbprint -replace '\s', '') -eq $thumbprint } | %{
+	Get-NetFirewallRule -Name "$firewallRuleNamePrefix$($_.Port)" -ErrorAction SilentlyContinue | Remove-NetFirewallRule
+	Remove-WSManInstance -ResourceURI winrm/config/Listener -SelectorSet @{Address=$_.Address;Transport=$_.Transport}
+}
+
+if (Test-Path -LiteralPath "Cert:\LocalMachine\My\$thumbprint") {
+	Remove-Item -LiteralPath "Cert:\LocalMachine\My\$thumbprint" -DeleteKey
+}
+`))
+
+func (c *ClientConfig) DeleteHostCertificate(ctx context.Context, thumbprint string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteHostCertificateTemplate, deleteHostCertificateArgs{
+		Thumbprint:             thumbprint,
+		FirewallRuleNamePrefix: api.HostCertificateFirewallRuleNamePrefix,
+	})
+
+	return err
+}
@@ -0,0 +1,160 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// hostNumaSpanningFunctions are functions that read the settings of the HyperV host machine that only apply after a
+// restart. The settings the Hyper-V virtual machine management service and the HyperV host machine started with can
+// not be read back, so they are recorded when the provider changes a setting together with when it changed it, a
+// setting changed before the last start is applied already.
+const hostNumaSpanningFunctions = `
+$memoryReserveKey = 'HKLM:\SOFTWARE\Microsoft\Windows NT\CurrentVersion\Virtualization'
+$hostNumaSpanningRecordPath = Join-Path $env:ProgramData 'terraform-provider-hyperv\host-numa-spanning\settings.json'
+
+function Get-MemoryReserveMb {
+	$memoryReserve = Get-ItemProperty -Path $memoryReserveKey -Name MemoryReserve -ErrorAction SilentlyContinue
+	if ($memoryReserve) {
+		return [int]$memoryReserve.MemoryReserve
+	}
+
+	return 0
+}
+
+function Get-VmmsStartTicks {
+	$vmmsProcess = Get-CimInstance -ClassName Win32_Process -Filter "Name = 'vmms.exe'" | Select-Object -First 1
+	if ($vmmsProcess) {
+		return $vmmsProcess.CreationDate.ToUniversalTime().Ticks
+	}
+
+	return [DateTime]::UtcNow.Ticks
+}
+
+function Get-LastBootUpTicks {
+	return (Get-CimInstance -ClassName Win32_OperatingSystem).LastBootUpTime.ToUniversalTime().Ticks
+}
+
+function Get-HostNumaSpanningRecord {
+	if (Test-Path -LiteralPath $hostNumaSpanningRecordPath -PathType Leaf) {
+		return Get-Content -LiteralPath $hostNumaSpanningRecordPath -Raw | ConvertFrom-Json
+	}
+
+	return $null
+}
+`
+
+type getHostNumaSpanningArgs struct {
+}
+
+var getHostNumaSpanningTemplate = template.Must(template.New("GetHostNumaSpanning").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+` + hostNumaSpanningFunctions + `
+$vmHost = Get-VMHost
+$record = Get-HostNumaSpanningRecord
+$numaSpanningEnabled = [bool]$vmHost.NumaSpanningEnabled
+$memoryReserveMb = Get-MemoryReserveMb
+
+$numaSpanningRestartPending = $false
+if ($record -and $record.NumaSpanningChangedAt -gt (Get-VmmsStartTicks)) {
+	$numaSpanningRestartPending = [bool]$record.NumaSpanningEnabled -ne $numaSpanningEnabled
+}
+
+$memoryReserveRestartPending = $false
+if ($record -and $record.MemoryReserveChangedAt -gt (Get-LastBootUpTicks)) {
+	$memoryReserveRestartPending = [int]$record.MemoryReserveMb -ne $memoryReserveMb
+}
+
+$hostNumaSpanningObject = @{
+	ComputerName=$vmHost.ComputerName;
+	NumaSpanningEnabled=$numaSpanningEnabled;
+	MemoryReserveMb=$memoryReserveMb;
+	NumaSpanningRestartPending=$numaSpanningRestartPending;
+	MemoryReserveRestartPending=$memoryReserveRestartPending;
+}
+
+$hostNumaSpanning = ConvertTo-Json -InputObject $hostNumaSpanningObject
+$hostNumaSpanning
+`))
+
+func (c *ClientConfig) GetHostNumaSpanning(ctx context.Context) (result api.HostNumaSpanning, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getHostNumaSpanningTemplate, getHostNumaSpanningArgs{}, &result)
+
+	return result, err
+}
+
+type updateHostNumaSpanningArgs struct {
+	HostNumaSpanningJson string
+}
+
+var updateHostNumaSpanningTemplate = template.Must(template.New("UpdateHostNumaSpanning").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$hostNumaSpanning = '{{.HostNumaSpanningJson}}' | ConvertFrom-Json
+` + hostNumaSpanningFunctions + `
+$vmHost = Get-VMHost
+$record = Get-HostNumaSpanningRecord
+$memoryReserveMb = Get-MemoryReserveMb
+
+$recordObject = @{
+	NumaSpanningEnabled=[bool]$vmHost.NumaSpanningEnabled;
+	NumaSpanningChangedAt=[int64]0;
+	MemoryReserveMb=$memoryReserveMb;
+	MemoryReserveChangedAt=[int64]0;
+}
+if ($record) {
+	$recordObject.NumaSpanningEnabled=[bool]$record.NumaSpanningEnabled
+	$recordObject.NumaSpanningChangedAt=[int64]$record.NumaSpanningChangedAt
+	$recordObject.MemoryReserveMb=[int]$record.MemoryReserveMb
+	$recordObject.MemoryReserveChangedAt=[int64]$record.MemoryReserveChangedAt
+}
+
+if ([bool]$vmHost.NumaSpanningEnabled -ne $hostNumaSpanning.NumaSpanningEnabled) {
+	#the service keeps running with the setting it started with until it is restarted
+	if ($recordObject.NumaSpanningChangedAt -le (Get-VmmsStartTicks)) {
+		$recordObject.NumaSpanningEnabled=[bool]$vmHost.NumaSpanningEnabled
+	}
+	$recordObject.NumaSpanningChangedAt=[DateTime]::UtcNow.Ticks
+
+	Set-VMHost -NumaSpanningEnabled $hostNumaSpanning.NumaSpanningEnabled
+}
+
+if ($memoryReserveMb -ne $hostNumaSpanning.MemoryReserveMb) {
+	#the host machine keeps the reserve it started with until it is restarted
+	if ($recordObject.MemoryReserveChangedAt -le (Get-LastBootUpTicks)) {
+		$recordObject.MemoryReserveMb=$memoryReserveMb
+	}
+	$recordObject.MemoryReserveChangedAt=[DateTime]::UtcNow.Ticks
+
+	if ($hostNumaSpanning.MemoryReserveMb -gt 0) {
+		New-ItemProperty -Path $memoryReserveKey -Name MemoryReserve -Value $hostNumaSpanning.MemoryReserveMb -PropertyType DWord -Force | Out-Null
+	} else {
+		Remove-ItemProperty -Path $memoryReserveKey -Name MemoryReserve
+	}
+}
+
+$recordDirectory = Split-Path -Path $hostNumaSpanningRecordPath -Parent
+if (!(Test-Path -LiteralPath $recordDirectory -PathType Container)) {
+	New-Item -ItemType Directory -Path $recordDirectory | Out-Null
+}
+
+ConvertTo-Json -InputObject $recordObject | Set-Content -LiteralPath $hostNumaSpanningRecordPath -Encoding UTF8
+`))
+
+func (c *ClientConfig) UpdateHostNumaSpanning(ctx context.Context, hostNumaSpanning api.HostNumaSpanning) (err error) {
+	hostNumaSpanningJson, err := json.Marshal(hostNumaSpanning)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateHostNumaSpanningTemplate, updateHostNumaSpanningArgs{
+		HostNumaSpanningJson: string(hostNumaSpanningJson),
+	})
+
+	return err
+}
@@ -0,0 +1,73 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getIsoCatalogArgs struct {
+	Directory    string
+	Recurse      bool
+	NamePrefix   string
+	ChecksumType string
+}
+
+var getIsoCatalogTemplate = template.Must(template.New("GetIsoCatalog").Parse(`
+$ErrorActionPreference = 'Stop'
+$directory = '{{.Directory}}'
+$checksumType = '{{.ChecksumType}}'
+$isoCatalogChecksumDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\iso-catalog-checksums'
+
+if (!(Test-Path -LiteralPath $directory -PathType Container)) {
+	throw [System.Management.Automation.ItemNotFoundException]"Directory does not exist - $directory"
+}
+
+$sha1 = [System.Security.Cryptography.SHA1]::Create()
+$isos = @(Get-ChildItem -LiteralPath $directory -Filter '{{.NamePrefix}}*.iso' -File -Recurse:${{.Recurse}} | %{
+	#calculating the checksum of an iso takes a while, so it is kept until the iso is written to again
+	$checksumKey = "$($_.FullName.ToLowerInvariant())|$($_.Length)|$($_.LastWriteTimeUtc.Ticks)|$checksumType"
+	$checksumPath = Join-Path $isoCatalogChecksumDirectory "$(-join ($sha1.ComputeHash([System.Text.Encoding]::UTF8.GetBytes($checksumKey)) | %{ $_.ToString('x2') })).txt"
+
+	if (Test-Path -LiteralPath $checksumPath -PathType Leaf) {
+		$checksum = (Get-Content -LiteralPath $checksumPath -Raw).Trim()
+	} else {
+		$checksum = (Get-FileHash -LiteralPath $_.FullName -Algorithm $checksumType).Hash
+
+		if (!(Test-Path -LiteralPath $isoCatalogChecksumDirectory -PathType Container)) {
+			New-Item -ItemType Directory -Path $isoCatalogChecksumDirectory | Out-Null
+		}
+		Set-Content -LiteralPath $checksumPath -Value $checksum -Encoding UTF8
+	}
+
+	@{
+		Name=$_.BaseName;
+		Path=$_.FullName;
+		Size=$_.Length;
+		Checksum=$checksum;
+		ChecksumType=$checksumType;
+		LastWriteTime=$_.LastWriteTimeUtc.ToString('o');
+	}
+})
+
+if ($isos) {
+	$isoCatalog = ConvertTo-Json -InputObject $isos
+	$isoCatalog
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetIsoCatalog(ctx context.Context, directory string, recurse bool, namePrefix string, checksumType string) (result []api.IsoCatalogIso, err error) {
+	result = make([]api.IsoCatalogIso, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getIsoCatalogTemplate, getIsoCatalogArgs{
+		Directory:    directory,
+		Recurse:      recurse,
+		NamePrefix:   namePrefix,
+		ChecksumType: checksumType,
+	}, &result)
+
+	return result, err
+}
@@ -0,0 +1,174 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// isoLibraryDirectory is where the catalogs of the iso libraries are stored on the HyperV host machine, so that they
+// outlive the workspace of a provider run and can be looked up by name
+const isoLibraryDirectory = `$isoLibraryDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\iso-libraries'`
+
+type createOrUpdateIsoLibraryArgs struct {
+	IsoLibraryJson string
+}
+
+var createOrUpdateIsoLibraryTemplate = template.Must(template.New("CreateOrUpdateIsoLibrary").Parse(`
+$ErrorActionPreference = 'Stop'
+$isoLibrary = '{{.IsoLibraryJson}}' | ConvertFrom-Json
+` + isoLibraryDirectory + `
+
+if (!(Test-Path -LiteralPath $isoLibraryDirectory -PathType Container)) {
+	New-Item -ItemType Directory -Path $isoLibraryDirectory | Out-Null
+}
+
+if ($isoLibrary.CacheDirectory -and !(Test-Path -LiteralPath $isoLibrary.CacheDirectory -PathType Container)) {
+	New-Item -ItemType Directory -Path $isoLibrary.CacheDirectory | Out-Null
+}
+
+$isos = @($isoLibrary.Isos | ?{ $_ } | %{
+	$cachedPath = ""
+	if ($isoLibrary.CacheDirectory) {
+		#cached copies are named by checksum, so an iso is only copied to the HyperV host machine once however many
+		#libraries and dvd drives use it
+		$cachedPath = Join-Path $isoLibrary.CacheDirectory "$($_.Checksum)$([System.IO.Path]::GetExtension($_.Path))"
+		$cached = $false
+		if (Test-Path -LiteralPath $cachedPath -PathType Leaf) {
+			$cached = (Get-FileHash -LiteralPath $cachedPath -Algorithm $_.ChecksumType).Hash -eq $_.Checksum
+		}
+
+		if (!$cached) {
+			if (!(Test-Path -LiteralPath $_.Path -PathType Leaf)) {
+				throw [System.Management.Automation.ItemNotFoundException]"Iso does not exist - $($_.Path)"
+			}
+
+			#copy next to the cached copy and verify it before renaming it, so that a partial copy is never used
+			$copyPath = "$cachedPath.$([System.Guid]::NewGuid()).tmp"
+			Copy-Item -LiteralPath $_.Path -Destination $copyPath -Force
+			$fileHash = Get-FileHash -LiteralPath $copyPath -Algorithm $_.ChecksumType
+			if ($fileHash.Hash -ne $_.Checksum) {
+				Remove-Item -LiteralPath $copyPath -Force
+				throw "Checksum of iso $($_.Name) does not match, expected $($_.Checksum) but was $($fileHash.Hash) - $($_.Path)"
+			}
+			Move-Item -LiteralPath $copyPath -Destination $cachedPath -Force
+		}
+
+		$size = (Get-Item -LiteralPath $cachedPath).Length
+	} else {
+		if (!(Test-Path -LiteralPath $_.Path -PathType Leaf)) {
+			throw [System.Management.Automation.ItemNotFoundException]"Iso does not exist - $($_.Path)"
+		}
+
+		$fileHash = Get-FileHash -LiteralPath $_.Path -Algorithm $_.ChecksumType
+		if ($fileHash.Hash -ne $_.Checksum) {
+			throw "Checksum of iso $($_.Name) does not match, expected $($_.Checksum) but was $($fileHash.Hash) - $($_.Path)"
+		}
+
+		$size = (Get-Item -LiteralPath $_.Path).Length
+	}
+
+	@{
+		Name=$_.Name;
+		Path=$_.Path;
+		Checksum=$_.Checksum;
+		ChecksumType=$_.ChecksumType;
+		Size=$size;
+		CachedPath=$cachedPath;
+	}
+})
+
+$isoLibraryObject = @{
+	Name=$isoLibrary.Name;
+	Description=$isoLibrary.Description;
+	CacheDirectory="$($isoLibrary.CacheDirectory)";
+	Isos=$isos;
+}
+
+ConvertTo-Json -Depth 3 -InputObject $isoLibraryObject | Set-Content -LiteralPath (Join-Path $isoLibraryDirectory "$($isoLibrary.Name).json") -Encoding UTF8
+`))
+
+func (c *ClientConfig) CreateOrUpdateIsoLibrary(ctx context.Context, name string, description string, cacheDirectory string, isos []api.IsoLibraryIso) (err error) {
+	isoLibraryJson, err := json.Marshal(api.IsoLibrary{
+		Name:           name,
+		Description:    description,
+		CacheDirectory: cacheDirectory,
+		Isos:           isos,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateIsoLibraryTemplate, createOrUpdateIsoLibraryArgs{
+		IsoLibraryJson: string(isoLibraryJson),
+	})
+
+	return err
+}
+
+type getIsoLibraryArgs struct {
+	Name string
+}
+
+var getIsoLibraryTemplate = template.Must(template.New("GetIsoLibrary").Parse(`
+$ErrorActionPreference = 'Stop'
+` + isoLibraryDirectory + `
+$isoLibraryPath = Join-Path $isoLibraryDirectory '{{.Name}}.json'
+
+if (Test-Path -LiteralPath $isoLibraryPath -PathType Leaf) {
+	$isoLibrary = Get-Content -LiteralPath $isoLibraryPath -Raw | ConvertFrom-Json
+	$isoLibraryObject = @{
+		Name=$isoLibrary.Name;
+		Description=$isoLibrary.Description;
+		CacheDirectory="$($isoLibrary.CacheDirectory)";
+		Isos=@($isoLibrary.Isos | ?{ $_ } | %{
+			@{
+				Name=$_.Name;
+				Path=$_.Path;
+				Checksum=$_.Checksum;
+				ChecksumType=$_.ChecksumType;
+				Size=$_.Size;
+				CachedPath="$($_.CachedPath)";
+			}
+		});
+	}
+
+	$isoLibrary = ConvertTo-Json -Depth 3 -InputObject $isoLibraryObject
+	$isoLibrary
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetIsoLibrary(ctx context.Context, name string) (result api.IsoLibrary, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getIsoLibraryTemplate, getIsoLibraryArgs{
+		Name: name,
+	}, &result)
+
+	return result, err
+}
+
+type deleteIsoLibraryArgs struct {
+	Name string
+}
+
+var deleteIsoLibraryTemplate = template.Must(template.New("DeleteIsoLibrary").Parse(`
+$ErrorActionPreference = 'Stop'
+` + isoLibraryDirectory + `
+$isoLibraryPath = Join-Path $isoLibraryDirectory '{{.Name}}.json'
+
+if (Test-Path -LiteralPath $isoLibraryPath -PathType Leaf) {
+	Remove-Item -LiteralPath $isoLibraryPath -Force
+}
+`))
+
+func (c *ClientConfig) DeleteIsoLibrary(ctx context.Context, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteIsoLibraryTemplate, deleteIsoLibraryArgs{
+		Name: name,
+	})
+
+	return err
+}
@@ -0,0 +1,57 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getPhysicalNetAdaptersArgs struct {
+	Name   string
+	UpOnly bool
+}
+
+var getPhysicalNetAdaptersTemplate = template.Must(template.New("GetPhysicalNetAdapters").Parse(`
+$ErrorActionPreference = 'Stop'
+$upOnly = ${{.UpOnly}}
+
+$netAdaptersObject = @(Get-NetAdapter -Physical -Name '{{.Name}}' -ErrorAction SilentlyContinue | ?{ !$upOnly -or $_.Status -eq 'Up' } | %{
+	$sriov = Get-NetAdapterSriov -Name $_.Name -ErrorAction SilentlyContinue
+	$rdma = Get-NetAdapterRdma -Name $_.Name -ErrorAction SilentlyContinue
+	@{
+		Name=$_.Name;
+		InterfaceDescription=$_.InterfaceDescription;
+		InterfaceIndex=$_.ifIndex;
+		Status="$($_.Status)";
+		MacAddress=$_.MacAddress;
+		LinkSpeed=$_.LinkSpeed;
+		LinkSpeedBitsPerSecond=[int64]$_.ReceiveLinkSpeed;
+		SriovSupported=[bool]($sriov -and $sriov.SriovSupport -eq 'Supported');
+		RdmaCapable=[bool]$rdma;
+		RdmaEnabled=[bool]($rdma -and $rdma.Enabled);
+	}
+})
+
+if ($netAdaptersObject) {
+	$netAdapters = ConvertTo-Json -InputObject $netAdaptersObject
+	$netAdapters
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetPhysicalNetAdapters(ctx context.Context, name string, upOnly bool) (result []api.NetAdapter, err error) {
+	result = make([]api.NetAdapter, 0)
+
+	if name == "" {
+		name = "*"
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getPhysicalNetAdaptersTemplate, getPhysicalNetAdaptersArgs{
+		Name:   name,
+		UpOnly: upOnly,
+	}, &result)
+
+	return result, err
+}
@@ -0,0 +1,134 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createNetLbfoTeamArgs struct {
+	NetLbfoTeamJson string
+}
+
+var createNetLbfoTeamTemplate = template.Must(template.New("CreateNetLbfoTeam").Parse(`
+$ErrorActionPreference = 'Stop'
+$netLbfoTeam = '{{.NetLbfoTeamJson}}' | ConvertFrom-Json
+
+$NewNetLbfoTeamArgs = @{}
+$NewNetLbfoTeamArgs.Name=$netLbfoTeam.Name
+$NewNetLbfoTeamArgs.TeamMembers=[string[]]@($netLbfoTeam.TeamMembers)
+$NewNetLbfoTeamArgs.TeamingMode=$netLbfoTeam.TeamingMode
+$NewNetLbfoTeamArgs.LoadBalancingAlgorithm=$netLbfoTeam.LoadBalancingAlgorithm
+
+New-NetLbfoTeam @NewNetLbfoTeamArgs -Confirm:$false | Out-Null
+`))
+
+func (c *ClientConfig) CreateNetLbfoTeam(ctx context.Context, netLbfoTeam api.NetLbfoTeam) (err error) {
+	netLbfoTeamJson, err := json.Marshal(&netLbfoTeam)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createNetLbfoTeamTemplate, createNetLbfoTeamArgs{
+		NetLbfoTeamJson: string(netLbfoTeamJson),
+	})
+
+	return err
+}
+
+type getNetLbfoTeamArgs struct {
+	Name string
+}
+
+var getNetLbfoTeamTemplate = template.Must(template.New("GetNetLbfoTeam").Parse(`
+$ErrorActionPreference = 'Stop'
+
+$netLbfoTeamObject = Get-NetLbfoTeam -Name '{{.Name}}' -ErrorAction SilentlyContinue | %{
+	$primaryTeamNic = Get-NetLbfoTeamNic -Team $_.Name | ?{ $_.Primary } | Select -First 1
+	@{
+		Name=$_.Name;
+		TeamMembers=@(Get-NetLbfoTeamMember -Team $_.Name | %{ $_.Name });
+		TeamingMode="$($_.TeamingMode)";
+		LoadBalancingAlgorithm="$($_.LoadBalancingAlgorithm)";
+		NetAdapterName=$(if ($primaryTeamNic) { $primaryTeamNic.Name } else { $_.Name });
+		Status="$($_.Status)";
+	}
+}
+
+if ($netLbfoTeamObject) {
+	$netLbfoTeam = ConvertTo-Json -InputObject $netLbfoTeamObject
+	$netLbfoTeam
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetNetLbfoTeam(ctx context.Context, name string) (result api.NetLbfoTeam, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getNetLbfoTeamTemplate, getNetLbfoTeamArgs{
+		Name: name,
+	}, &result)
+
+	return result, err
+}
+
+type updateNetLbfoTeamArgs struct {
+	NetLbfoTeamJson string
+}
+
+var updateNetLbfoTeamTemplate = template.Must(template.New("UpdateNetLbfoTeam").Parse(`
+$ErrorActionPreference = 'Stop'
+$netLbfoTeam = '{{.NetLbfoTeamJson}}' | ConvertFrom-Json
+
+$currentTeamMembers = @(Get-NetLbfoTeamMember -Team $netLbfoTeam.Name | %{ $_.Name })
+$desiredTeamMembers = @($netLbfoTeam.TeamMembers)
+
+# add the new members before removing the old ones, so the team is never left without a member
+$desiredTeamMembers | ?{ $currentTeamMembers -notcontains $_ } | %{
+	Add-NetLbfoTeamMember -Name $_ -Team $netLbfoTeam.Name -Confirm:$false | Out-Null
+}
+$currentTeamMembers | ?{ $desiredTeamMembers -notcontains $_ } | %{
+	Remove-NetLbfoTeamMember -Name $_ -Team $netLbfoTeam.Name -Confirm:$false | Out-Null
+}
+
+$SetNetLbfoTeamArgs = @{}
+$SetNetLbfoTeamArgs.Name=$netLbfoTeam.Name
+$SetNetLbfoTeamArgs.TeamingMode=$netLbfoTeam.TeamingMode
+$SetNetLbfoTeamArgs.LoadBalancingAlgorithm=$netLbfoTeam.LoadBalancingAlgorithm
+
+Set-NetLbfoTeam @SetNetLbfoTeamArgs -Confirm:$false | Out-Null
+`))
+
+func (c *ClientConfig) UpdateNetLbfoTeam(ctx context.Context, netLbfoTeam api.NetLbfoTeam) (err error) {
+	netLbfoTeamJson, err := json.Marshal(&netLbfoTeam)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateNetLbfoTeamTemplate, updateNetLbfoTeamArgs{
+		NetLbfoTeamJson: string(netLbfoTeamJson),
+	})
+
+	return err
+}
+
+type deleteNetLbfoTeamArgs struct {
+	Name string
+}
+
+var deleteNetLbfoTeamTemplate = template.Must(template.New("DeleteNetLbfoTeam").Parse(`
+$ErrorActionPreference = 'Stop'
+
+Get-NetLbfoTeam -Name '{{.Name}}' -ErrorAction SilentlyContinue | Remove-NetLbfoTeam -Confirm:$false
+`))
+
+func (c *ClientConfig) DeleteNetLbfoTeam(ctx context.Context, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteNetLbfoTeamTemplate, deleteNetLbfoTeamArgs{
+		Name: name,
+	})
+
+	return err
+}
@@ -0,0 +1,233 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createNetNatArgs struct {
+	NetNatJson string
+}
+
+var createNetNatTemplate = template.Must(template.New("CreateNetNat").Parse(`
+$ErrorActionPreference = 'Stop'
+$netNat = '{{.NetNatJson}}' | ConvertFrom-Json
+
+if (Get-NetNat -Name $netNat.Name -ErrorAction SilentlyContinue){
+	throw "Nat already exists - $($netNat.Name)"
+}
+
+if ($netNat.SwitchName) {
+	$netAdapter = Get-NetAdapter -Name "vEthernet ($($netNat.SwitchName))"
+	$prefixLength = [int]($netNat.InternalIPInterfaceAddressPrefix -split '/')[1]
+
+	if (!(Get-NetIPAddress -InterfaceIndex $netAdapter.ifIndex -IPAddress $netNat.GatewayAddress -ErrorAction SilentlyContinue)) {
+		New-NetIPAddress -InterfaceIndex $netAdapter.ifIndex -IPAddress $netNat.GatewayAddress -PrefixLength $prefixLength | Out-Null
+	}
+}
+
+New-NetNat -Name $netNat.Name -InternalIPInterfaceAddressPrefix $netNat.InternalIPInterfaceAddressPrefix | Out-Null
+`))
+
+func (c *ClientConfig) CreateNetNat(
+	ctx context.Context,
+	name string,
+	internalIPInterfaceAddressPrefix string,
+	switchName string,
+	gatewayAddress string,
+) (err error) {
+	netNatJson, err := json.Marshal(api.NetNat{
+		Name:                             name,
+		InternalIPInterfaceAddressPrefix: internalIPInterfaceAddressPrefix,
+		SwitchName:                       switchName,
+		GatewayAddress:                   gatewayAddress,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createNetNatTemplate, createNetNatArgs{
+		NetNatJson: string(netNatJson),
+	})
+
+	return err
+}
+
+type getNetNatArgs struct {
+	Name string
+}
+
+var getNetNatTemplate = template.Must(template.New("GetNetNat").Parse(`
+$ErrorActionPreference = 'Stop'
+$netNatObject = Get-NetNat -Name '{{.Name}}' -ErrorAction SilentlyContinue | Select -First 1 | %{ @{
+	Name=$_.Name;
+	InternalIPInterfaceAddressPrefix=$_.InternalIPInterfaceAddressPrefix;
+}}
+
+if ($netNatObject){
+	$netNat = ConvertTo-Json -InputObject $netNatObject
+	$netNat
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetNetNat(ctx context.Context, name string) (result api.NetNat, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getNetNatTemplate, getNetNatArgs{
+		Name: name,
+	}, &result)
+
+	return result, err
+}
+
+type deleteNetNatArgs struct {
+	NetNatJson string
+}
+
+var deleteNetNatTemplate = template.Must(template.New("DeleteNetNat").Parse(`
+$ErrorActionPreference = 'Stop'
+$netNat = '{{.NetNatJson}}' | ConvertFrom-Json
+
+Get-NetNat -Name $netNat.Name -ErrorAction SilentlyContinue | Remove-NetNat -Confirm:$false
+
+if ($netNat.SwitchName -and $netNat.GatewayAddress) {
+	$netAdapter = Get-NetAdapter -Name "vEthernet ($($netNat.SwitchName))" -ErrorAction SilentlyContinue
+	if ($netAdapter) {
+		Get-NetIPAddress -InterfaceIndex $netAdapter.ifIndex -IPAddress $netNat.GatewayAddress -ErrorAction SilentlyContinue | Remove-NetIPAddress -Confirm:$false
+	}
+}
+`))
+
+func (c *ClientConfig) DeleteNetNat(ctx context.Context, name string, switchName string, gatewayAddress string) (err error) {
+	netNatJson, err := json.Marshal(api.NetNat{
+		Name:           name,
+		SwitchName:     switchName,
+		GatewayAddress: gatewayAddress,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteNetNatTemplate, deleteNetNatArgs{
+		NetNatJson: string(netNatJson),
+	})
+
+	return err
+}
+
+type createNetNatStaticMappingArgs struct {
+	NetNatStaticMappingJson string
+}
+
+var createNetNatStaticMappingTemplate = template.Must(template.New("CreateNetNatStaticMapping").Parse(`
+$ErrorActionPreference = 'Stop'
+$netNatStaticMapping = '{{.NetNatStaticMappingJson}}' | ConvertFrom-Json
+
+$AddNetNatStaticMappingArgs = @{}
+$AddNetNatStaticMappingArgs.NatName=$netNatStaticMapping.NatName
+$AddNetNatStaticMappingArgs.Protocol=$netNatStaticMapping.Protocol
+$AddNetNatStaticMappingArgs.ExternalIPAddress=$netNatStaticMapping.ExternalIPAddress
+$AddNetNatStaticMappingArgs.ExternalPort=$netNatStaticMapping.ExternalPort
+$AddNetNatStaticMappingArgs.InternalIPAddress=$netNatStaticMapping.InternalIPAddress
+$AddNetNatStaticMappingArgs.InternalPort=$netNatStaticMapping.InternalPort
+
+$netNatStaticMappingObject = Add-NetNatStaticMapping @AddNetNatStaticMappingArgs | %{ @{
+	NatName=$_.NatName;
+	StaticMappingId=$_.StaticMappingID;
+	Protocol="$($_.Protocol)";
+	ExternalIPAddress=$_.ExternalIPAddress;
+	ExternalPort=$_.ExternalPort;
+	InternalIPAddress=$_.InternalIPAddress;
+	InternalPort=$_.InternalPort;
+}}
+
+$netNatStaticMapping = ConvertTo-Json -InputObject $netNatStaticMappingObject
+$netNatStaticMapping
+`))
+
+func (c *ClientConfig) CreateNetNatStaticMapping(
+	ctx context.Context,
+	natName string,
+	protocol api.NetNatProtocol,
+	externalIPAddress string,
+	externalPort int,
+	internalIPAddress string,
+	internalPort int,
+) (result api.NetNatStaticMapping, err error) {
+	netNatStaticMappingJson, err := json.Marshal(api.NetNatStaticMapping{
+		NatName:           natName,
+		Protocol:          protocol,
+		ExternalIPAddress: externalIPAddress,
+		ExternalPort:      externalPort,
+		InternalIPAddress: internalIPAddress,
+		InternalPort:      internalPort,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, createNetNatStaticMappingTemplate, createNetNatStaticMappingArgs{
+		NetNatStaticMappingJson: string(netNatStaticMappingJson),
+	}, &result)
+
+	return result, err
+}
+
+type getNetNatStaticMappingArgs struct {
+	NatName         string
+	StaticMappingId int
+}
+
+var getNetNatStaticMappingTemplate = template.Must(template.New("GetNetNatStaticMapping").Parse(`
+$ErrorActionPreference = 'Stop'
+$netNatStaticMappingObject = Get-NetNatStaticMapping -NatName '{{.NatName}}' -ErrorAction SilentlyContinue | ?{$_.StaticMappingID -eq {{.StaticMappingId}} } | %{ @{
+	NatName=$_.NatName;
+	StaticMappingId=$_.StaticMappingID;
+	Protocol="$($_.Protocol)";
+	ExternalIPAddress=$_.ExternalIPAddress;
+	ExternalPort=$_.ExternalPort;
+	InternalIPAddress=$_.InternalIPAddress;
+	InternalPort=$_.InternalPort;
+}}
+
+if ($netNatStaticMappingObject){
+	$netNatStaticMapping = ConvertTo-Json -InputObject $netNatStaticMappingObject
+	$netNatStaticMapping
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetNetNatStaticMapping(ctx context.Context, natName string, staticMappingId int) (result api.NetNatStaticMapping, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getNetNatStaticMappingTemplate, getNetNatStaticMappingArgs{
+		NatName:         natName,
+		StaticMappingId: staticMappingId,
+	}, &result)
+
+	return result, err
+}
+
+type deleteNetNatStaticMappingArgs struct {
+	NatName         string
+	StaticMappingId int
+}
+
+var deleteNetNatStaticMappingTemplate = template.Must(template.New("DeleteNetNatStaticMapping").Parse(`
+$ErrorActionPreference = 'Stop'
+Get-NetNatStaticMapping -NatName '{{.NatName}}' -ErrorAction SilentlyContinue | ?{$_.StaticMappingID -eq {{.StaticMappingId}} } | Remove-NetNatStaticMapping -Confirm:$false
+`))
+
+func (c *ClientConfig) DeleteNetNatStaticMapping(ctx context.Context, natName string, staticMappingId int) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteNetNatStaticMappingTemplate, deleteNetNatStaticMappingArgs{
+		NatName:         natName,
+		StaticMappingId: staticMappingId,
+	})
+
+	return err
+}
@@ -0,0 +1,76 @@
+package hyperv_winrm
+
+// protectedDirectoryFunctions defines the functions for directories under ProgramData that hold scripts which scheduled
+// tasks run as SYSTEM. Every user can create files under ProgramData, so such a directory only allows Administrators
+// and SYSTEM with inheritance turned off. A file that was created by anyone else before the directory was protected
+// is refused rather than used, as its owner can still change it. Files written by the provider are owned by
+// Administrators, so that the scheduled task can check the owner of a file before it runs it.
+const protectedDirectoryFunctions = `
+function Initialize-ProtectedDirectory {
+	param(
+		[Parameter(Mandatory=$true)][string]$Path
+	)
+
+	if (Test-Path -LiteralPath $Path) {
+		if ((Get-Item -LiteralPath $Path -Force).Attributes -band [System.IO.FileAttributes]::ReparsePoint) {
+			throw "Directory must not be a link - $Path"
+		}
+	} else {
+		New-Item -ItemType Directory -Force -Path $Path | Out-Null
+	}
+
+	$administratorsSid = New-Object System.Security.Principal.SecurityIdentifier('S-1-5-32-544')
+	$systemSid = New-Object System.Security.Principal.SecurityIdentifier('S-1-5-18')
+
+	$acl = New-Object System.Security.AccessControl.DirectorySecurity
+	$acl.SetOwner($administratorsSid)
+	$acl.SetAccessRuleProtection($true, $false)
+	@($administratorsSid, $systemSid) | %{
+		$acl.AddAccessRule((New-Object System.Security.AccessControl.FileSystemAccessRule($_, 'FullControl', 'ContainerInherit, ObjectInherit', 'None', 'Allow')))
+	}
+	Set-Acl -LiteralPath $Path -AclObject $acl
+}
+
+function Assert-ProtectedFile {
+	param(
+		[Parameter(Mandatory=$true)][string]$Path
+	)
+
+	if (!(Test-Path -LiteralPath $Path)) {
+		return
+	}
+
+	$trustedOwners = @('S-1-5-32-544', 'S-1-5-18', [System.Security.Principal.WindowsIdentity]::GetCurrent().User.Value)
+	$owner = (Get-Acl -LiteralPath $Path).GetOwner([System.Security.Principal.SecurityIdentifier])
+	if ($trustedOwners -notcontains $owner.Value) {
+		throw "Refusing to use $Path as it is owned by $($owner.Translate([System.Security.Principal.NTAccount])), remove it after checking its content"
+	}
+}
+
+function Set-ProtectedFileOwner {
+	param(
+		[Parameter(Mandatory=$true)][string]$Path
+	)
+
+	$acl = Get-Acl -LiteralPath $Path
+	$acl.SetOwner((New-Object System.Security.Principal.SecurityIdentifier('S-1-5-32-544')))
+	Set-Acl -LiteralPath $Path -AclObject $acl
+}
+`
+
+// assertProtectedFileOwnerFunction defines the function a scheduled task runs before it uses a file from a protected
+// directory, only files owned by Administrators or SYSTEM are used
+const assertProtectedFileOwnerFunction = `
+function Assert-ProtectedFileOwner {
+	param(
+		[string[]]$Path
+	)
+
+	$Path | ?{ $_ -and (Test-Path -LiteralPath $_) } | %{
+		$owner = (Get-Acl -LiteralPath $_).GetOwner([System.Security.Principal.SecurityIdentifier])
+		if (@('S-1-5-32-544', 'S-1-5-18') -notcontains $owner.Value) {
+			throw "Refusing to use $_ as it is owned by $($owner.Translate([System.Security.Principal.NTAccount]))"
+		}
+	}
+}
+`
@@ -0,0 +1,202 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// setSmbShareAccess grants the access listed for the share in $smbShare and removes any other access, as
+// New-SmbShare gives everyone read access when no access is specified
+const setSmbShareAccess = `
+$desiredAccess = @()
+$desiredAccess += @($smbShare.FullAccess | ?{ $_ } | %{ @{ AccountName=$_; AccessControlType='Allow'; AccessRight='Full' } })
+$desiredAccess += @($smbShare.ChangeAccess | ?{ $_ } | %{ @{ AccountName=$_; AccessControlType='Allow'; AccessRight='Change' } })
+$desiredAccess += @($smbShare.ReadAccess | ?{ $_ } | %{ @{ AccountName=$_; AccessControlType='Allow'; AccessRight='Read' } })
+$desiredAccess += @($smbShare.NoAccess | ?{ $_ } | %{ @{ AccountName=$_; AccessControlType='Deny'; AccessRight='Full' } })
+
+$currentAccess = @(Get-SmbShareAccess @SmbShareArgs | %{ @{
+	AccountName=$_.AccountName;
+	AccessControlType="$($_.AccessControlType)";
+	AccessRight="$($_.AccessRight)";
+}})
+
+foreach ($access in $currentAccess) {
+	$desired = $desiredAccess | ?{ $_.AccountName -eq $access.AccountName -and $_.AccessControlType -eq $access.AccessControlType -and $_.AccessRight -eq $access.AccessRight }
+	if (!$desired) {
+		if ($access.AccessControlType -eq 'Deny') {
+			Unblock-SmbShareAccess @SmbShareArgs -AccountName $access.AccountName -Force | Out-Null
+		} else {
+			Revoke-SmbShareAccess @SmbShareArgs -AccountName $access.AccountName -Force | Out-Null
+		}
+	}
+}
+
+foreach ($access in $desiredAccess) {
+	$current = $currentAccess | ?{ $_.AccountName -eq $access.AccountName -and $_.AccessControlType -eq $access.AccessControlType -and $_.AccessRight -eq $access.AccessRight }
+	if (!$current) {
+		if ($access.AccessControlType -eq 'Deny') {
+			Block-SmbShareAccess @SmbShareArgs -AccountName $access.AccountName -Force | Out-Null
+		} else {
+			Grant-SmbShareAccess @SmbShareArgs -AccountName $access.AccountName -AccessRight $access.AccessRight -Force | Out-Null
+		}
+	}
+}
+`
+
+type createSmbShareArgs struct {
+	SmbShareJson string
+}
+
+var createSmbShareTemplate = template.Must(template.New("CreateSmbShare").Parse(`
+$ErrorActionPreference = 'Stop'
+$smbShare = '{{.SmbShareJson}}' | ConvertFrom-Json
+
+$SmbShareArgs = @{}
+$SmbShareArgs.Name=$smbShare.Name
+if ($smbShare.ScopeName) {
+	$SmbShareArgs.ScopeName=$smbShare.ScopeName
+}
+
+if (!(Test-Path -LiteralPath $smbShare.Path -PathType Container)) {
+	New-Item -ItemType Directory -Path $smbShare.Path -Force | Out-Null
+}
+
+$NewSmbShareArgs = @{}
+$NewSmbShareArgs.Path=$smbShare.Path
+$NewSmbShareArgs.Description=$smbShare.Description
+$NewSmbShareArgs.ContinuouslyAvailable=$smbShare.ContinuouslyAvailable
+$NewSmbShareArgs.EncryptData=$smbShare.EncryptData
+$NewSmbShareArgs.FolderEnumerationMode=$smbShare.FolderEnumerationMode
+
+New-SmbShare @SmbShareArgs @NewSmbShareArgs | Out-Null
+` + setSmbShareAccess + `
+`))
+
+func (c *ClientConfig) CreateSmbShare(ctx context.Context, smbShare api.SmbShare) (err error) {
+	smbShareJson, err := json.Marshal(smbShare)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createSmbShareTemplate, createSmbShareArgs{
+		SmbShareJson: string(smbShareJson),
+	})
+
+	return err
+}
+
+type getSmbShareArgs struct {
+	ScopeName string
+	Name      string
+}
+
+var getSmbShareTemplate = template.Must(template.New("GetSmbShare").Parse(`
+$ErrorActionPreference = 'Stop'
+$SmbShareArgs = @{}
+$SmbShareArgs.Name='{{.Name}}'
+$scopeName = '{{.ScopeName}}'
+if ($scopeName) {
+	$SmbShareArgs.ScopeName=$scopeName
+}
+
+$smbShareObject = Get-SmbShare @SmbShareArgs -ErrorAction SilentlyContinue | Select -First 1 | %{
+	$access = @(Get-SmbShareAccess -Name $_.Name -ScopeName $_.ScopeName)
+	@{
+		Name=$_.Name;
+		Path=$_.Path;
+		Description=$_.Description;
+		ScopeName=$(if ($_.ScopeName -eq '*') { '' } else { $_.ScopeName });
+		ContinuouslyAvailable=$_.ContinuouslyAvailable;
+		EncryptData=$_.EncryptData;
+		FolderEnumerationMode="$($_.FolderEnumerationMode)";
+		FullAccess=@($access | ?{ "$($_.AccessControlType)" -eq 'Allow' -and "$($_.AccessRight)" -eq 'Full' } | %{ $_.AccountName });
+		ChangeAccess=@($access | ?{ "$($_.AccessControlType)" -eq 'Allow' -and "$($_.AccessRight)" -eq 'Change' } | %{ $_.AccountName });
+		ReadAccess=@($access | ?{ "$($_.AccessControlType)" -eq 'Allow' -and "$($_.AccessRight)" -eq 'Read' } | %{ $_.AccountName });
+		NoAccess=@($access | ?{ "$($_.AccessControlType)" -eq 'Deny' } | %{ $_.AccountName });
+	}
+}
+
+if ($smbShareObject) {
+	$smbShare = ConvertTo-Json -InputObject $smbShareObject
+	$smbShare
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetSmbShare(ctx context.Context, scopeName string, name string) (result api.SmbShare, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getSmbShareTemplate, getSmbShareArgs{
+		ScopeName: scopeName,
+		Name:      name,
+	}, &result)
+
+	return result, err
+}
+
+type updateSmbShareArgs struct {
+	SmbShareJson string
+}
+
+var updateSmbShareTemplate = template.Must(template.New("UpdateSmbShare").Parse(`
+$ErrorActionPreference = 'Stop'
+$smbShare = '{{.SmbShareJson}}' | ConvertFrom-Json
+
+$SmbShareArgs = @{}
+$SmbShareArgs.Name=$smbShare.Name
+if ($smbShare.ScopeName) {
+	$SmbShareArgs.ScopeName=$smbShare.ScopeName
+}
+
+$SetSmbShareArgs = @{}
+$SetSmbShareArgs.Description=$smbShare.Description
+$SetSmbShareArgs.ContinuouslyAvailable=$smbShare.ContinuouslyAvailable
+$SetSmbShareArgs.EncryptData=$smbShare.EncryptData
+$SetSmbShareArgs.FolderEnumerationMode=$smbShare.FolderEnumerationMode
+
+Set-SmbShare @SmbShareArgs @SetSmbShareArgs -Force | Out-Null
+` + setSmbShareAccess + `
+`))
+
+func (c *ClientConfig) UpdateSmbShare(ctx context.Context, smbShare api.SmbShare) (err error) {
+	smbShareJson, err := json.Marshal(smbShare)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateSmbShareTemplate, updateSmbShareArgs{
+		SmbShareJson: string(smbShareJson),
+	})
+
+	return err
+}
+
+type deleteSmbShareArgs struct {
+	ScopeName string
+	Name      string
+}
+
+var deleteSmbShareTemplate = template.Must(template.New("DeleteSmbShare").Parse(`
+$ErrorActionPreference = 'Stop'
+$SmbShareArgs = @{}
+$SmbShareArgs.Name='{{.Name}}'
+$scopeName = '{{.ScopeName}}'
+if ($scopeName) {
+	$SmbShareArgs.ScopeName=$scopeName
+}
+
+Get-SmbShare @SmbShareArgs -ErrorAction SilentlyContinue | Remove-SmbShare -Force
+`))
+
+func (c *ClientConfig) DeleteSmbShare(ctx context.Context, scopeName string, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteSmbShareTemplate, deleteSmbShareArgs{
+		ScopeName: scopeName,
+		Name:      name,
+	})
+
+	return err
+}
@@ -0,0 +1,203 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createStorageQosPolicyArgs struct {
+	StorageQosPolicyJson string
+}
+
+var createStorageQosPolicyTemplate = template.Must(template.New("CreateStorageQosPolicy").Parse(`
+$ErrorActionPreference = 'Stop'
+$storageQosPolicy = '{{.StorageQosPolicyJson}}' | ConvertFrom-Json
+
+$CimSessionArgs = @{}
+if ($storageQosPolicy.ClusterName) {
+	$CimSessionArgs.CimSession=$storageQosPolicy.ClusterName
+}
+
+$NewStorageQosPolicyArgs = @{}
+$NewStorageQosPolicyArgs.Name=$storageQosPolicy.Name
+$NewStorageQosPolicyArgs.PolicyType=$storageQosPolicy.PolicyType
+$NewStorageQosPolicyArgs.MinimumIops=$storageQosPolicy.MinimumIops
+$NewStorageQosPolicyArgs.MaximumIops=$storageQosPolicy.MaximumIops
+$NewStorageQosPolicyArgs.MaximumIOBandwidth=$storageQosPolicy.MaximumIoBandwidthBytes
+
+$storageQosPolicyObject = New-StorageQosPolicy @NewStorageQosPolicyArgs @CimSessionArgs | %{ @{
+	PolicyId="$($_.PolicyId)";
+	Name=$_.Name;
+	PolicyType="$($_.PolicyType)";
+	MinimumIops=$_.MinimumIops;
+	MaximumIops=$_.MaximumIops;
+	MaximumIoBandwidthBytes=$_.MaximumIOBandwidth;
+}}
+
+$storageQosPolicy = ConvertTo-Json -InputObject $storageQosPolicyObject
+$storageQosPolicy
+`))
+
+func (c *ClientConfig) CreateStorageQosPolicy(ctx context.Context, clusterName string, name string, policyType api.StorageQosPolicyType, minimumIops uint64, maximumIops uint64, maximumIoBandwidthBytes uint64) (result api.StorageQosPolicy, err error) {
+	storageQosPolicyJson, err := json.Marshal(struct {
+		ClusterName             string
+		Name                    string
+		PolicyType              string
+		MinimumIops             uint64
+		MaximumIops             uint64
+		MaximumIoBandwidthBytes uint64
+	}{
+		ClusterName:             clusterName,
+		Name:                    name,
+		PolicyType:              policyType.String(),
+		MinimumIops:             minimumIops,
+		MaximumIops:             maximumIops,
+		MaximumIoBandwidthBytes: maximumIoBandwidthBytes,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, createStorageQosPolicyTemplate, createStorageQosPolicyArgs{
+		StorageQosPolicyJson: string(storageQosPolicyJson),
+	}, &result)
+
+	return result, err
+}
+
+type getStorageQosPolicyArgs struct {
+	ClusterName string
+	Filter      string
+	Value       string
+}
+
+var getStorageQosPolicyTemplate = template.Must(template.New("GetStorageQosPolicy").Parse(`
+$ErrorActionPreference = 'Stop'
+$CimSessionArgs = @{}
+$clusterName = '{{.ClusterName}}'
+if ($clusterName) {
+	$CimSessionArgs.CimSession=$clusterName
+}
+
+$GetStorageQosPolicyArgs = @{}
+$GetStorageQosPolicyArgs.{{.Filter}}='{{.Value}}'
+
+$storageQosPolicyObject = Get-StorageQosPolicy @GetStorageQosPolicyArgs @CimSessionArgs -ErrorAction SilentlyContinue | Select -First 1 | %{ @{
+	PolicyId="$($_.PolicyId)";
+	Name=$_.Name;
+	PolicyType="$($_.PolicyType)";
+	MinimumIops=$_.MinimumIops;
+	MaximumIops=$_.MaximumIops;
+	MaximumIoBandwidthBytes=$_.MaximumIOBandwidth;
+}}
+
+if ($storageQosPolicyObject){
+	$storageQosPolicy = ConvertTo-Json -InputObject $storageQosPolicyObject
+	$storageQosPolicy
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetStorageQosPolicyByName(ctx context.Context, clusterName string, name string) (result api.StorageQosPolicy, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getStorageQosPolicyTemplate, getStorageQosPolicyArgs{
+		ClusterName: clusterName,
+		Filter:      "Name",
+		Value:       name,
+	}, &result)
+
+	return result, err
+}
+
+func (c *ClientConfig) GetStorageQosPolicy(ctx context.Context, clusterName string, policyId string) (result api.StorageQosPolicy, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getStorageQosPolicyTemplate, getStorageQosPolicyArgs{
+		ClusterName: clusterName,
+		Filter:      "PolicyId",
+		Value:       policyId,
+	}, &result)
+
+	return result, err
+}
+
+type updateStorageQosPolicyArgs struct {
+	StorageQosPolicyJson string
+}
+
+var updateStorageQosPolicyTemplate = template.Must(template.New("UpdateStorageQosPolicy").Parse(`
+$ErrorActionPreference = 'Stop'
+$storageQosPolicy = '{{.StorageQosPolicyJson}}' | ConvertFrom-Json
+
+$CimSessionArgs = @{}
+if ($storageQosPolicy.ClusterName) {
+	$CimSessionArgs.CimSession=$storageQosPolicy.ClusterName
+}
+
+$storageQosPolicyObject = Get-StorageQosPolicy -PolicyId $storageQosPolicy.PolicyId @CimSessionArgs
+
+$SetStorageQosPolicyArgs = @{}
+$SetStorageQosPolicyArgs.MinimumIops=$storageQosPolicy.MinimumIops
+$SetStorageQosPolicyArgs.MaximumIops=$storageQosPolicy.MaximumIops
+$SetStorageQosPolicyArgs.MaximumIOBandwidth=$storageQosPolicy.MaximumIoBandwidthBytes
+if ($storageQosPolicyObject.Name -ne $storageQosPolicy.Name) {
+	$SetStorageQosPolicyArgs.NewName=$storageQosPolicy.Name
+}
+
+$storageQosPolicyObject | Set-StorageQosPolicy @SetStorageQosPolicyArgs
+`))
+
+func (c *ClientConfig) UpdateStorageQosPolicy(ctx context.Context, clusterName string, policyId string, name string, minimumIops uint64, maximumIops uint64, maximumIoBandwidthBytes uint64) (err error) {
+	storageQosPolicyJson, err := json.Marshal(struct {
+		ClusterName             string
+		PolicyId                string
+		Name                    string
+		MinimumIops             uint64
+		MaximumIops             uint64
+		MaximumIoBandwidthBytes uint64
+	}{
+		ClusterName:             clusterName,
+		PolicyId:                policyId,
+		Name:                    name,
+		MinimumIops:             minimumIops,
+		MaximumIops:             maximumIops,
+		MaximumIoBandwidthBytes: maximumIoBandwidthBytes,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateStorageQosPolicyTemplate, updateStorageQosPolicyArgs{
+		StorageQosPolicyJson: string(storageQosPolicyJson),
+	})
+
+	return err
+}
+
+type deleteStorageQosPolicyArgs struct {
+	ClusterName string
+	PolicyId    string
+}
+
+var deleteStorageQosPolicyTemplate = template.Must(template.New("DeleteStorageQosPolicy").Parse(`
+$ErrorActionPreference = 'Stop'
+$CimSessionArgs = @{}
+$clusterName = '{{.ClusterName}}'
+if ($clusterName) {
+	$CimSessionArgs.CimSession=$clusterName
+}
+
+Get-StorageQosPolicy -PolicyId '{{.PolicyId}}' @CimSessionArgs -ErrorAction SilentlyContinue | Remove-StorageQosPolicy -Confirm:$false
+`))
+
+func (c *ClientConfig) DeleteStorageQosPolicy(ctx context.Context, clusterName string, policyId string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteStorageQosPolicyTemplate, deleteStorageQosPolicyArgs{
+		ClusterName: clusterName,
+		PolicyId:    policyId,
+	})
+
+	return err
+}
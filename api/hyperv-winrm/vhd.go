@@ -2,6 +2,7 @@ package hyperv_winrm
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"text/template"
 
@@ -33,23 +34,9 @@ func (c *ClientConfig) VhdExists(ctx context.Context, path string) (result api.V
 	return result, err
 }
 
-type createOrUpdateVhdArgs struct {
-	Source     string
-	SourceVm   string
-	SourceDisk int
-	VhdJson    string
-}
-
-var createOrUpdateVhdTemplate = template.Must(template.New("CreateOrUpdateVhd").Parse(`
-$ErrorActionPreference = 'Stop'
-
-Import-Module Hyper-V
-$source='{{.Source}}'
-$sourceVm='{{.SourceVm}}'
-$sourceDisk={{.SourceDisk}}
-$vhd = '{{.VhdJson}}' | ConvertFrom-Json
-$vhdType = [Microsoft.Vhd.PowerShell.VhdType]$vhd.VhdType
-
+// vhdDownloadFunctions are functions that download a source url and expand the archives it downloads. Archives are
+// expanded in a directory of the workspace, so that a run that is killed does not leave them next to the virtual hard disk
+const vhdDownloadFunctions = `
 function Get-TarPath {
 	if (Get-Command "tar" -ErrorAction SilentlyContinue) {
 		return "tar"
@@ -77,13 +64,16 @@ function Expand-Downloads {
         [Parameter(Mandatory = $true, Position = 0)]
         [string]
         [Alias('Folder')]
-        $FolderPath
+        $FolderPath,
+        [Parameter(Mandatory = $true, Position = 1)]
+        [string]
+        $TempPath
     )
     process {
 		Push-Location $FolderPath
 
         get-item *.zip | % {
-			$tempPath = join-path $FolderPath "temp"
+			$tempPath = join-path $TempPath "temp-$([System.Guid]::NewGuid())"
 
 			$7zPath = Get-7ZipPath
 			if ($7zPath) {
@@ -112,7 +102,7 @@ function Expand-Downloads {
 			if (-not $7zPath) {
  				throw "7z.exe needed"
 			}
-			$tempPath = join-path $FolderPath "temp"
+			$tempPath = join-path $TempPath "temp-$([System.Guid]::NewGuid())"
 			$command = """$7zPath"" x ""$($_.FullName)"" -o""$tempPath""" 
 			& cmd.exe /C $command
 
@@ -131,7 +121,7 @@ function Expand-Downloads {
 			if (-not $tarPath) {
 				throw "tar.exe needed"
 			}
-			$tempPath = join-path $FolderPath "temp"
+			$tempPath = join-path $TempPath "temp-$([System.Guid]::NewGuid())"
 
 			if (!(Test-Path $tempPath)) {
 				New-Item -ItemType Directory -Force -Path $tempPath
@@ -203,7 +193,32 @@ function Test-Uri {
         $null -ne $testUri.AbsoluteURI -and $testUri.Scheme -match '[http|https]' -and ($testUri.ToString().ToLower().StartsWith("http://") -or $testUri.ToString().ToLower().StartsWith("https://"))
     }
 }
+`
+
+type createOrUpdateVhdArgs struct {
+	Source                     string
+	SourceVm                   string
+	SourceVmDiskIndex          int
+	SourceVmControllerLocation int
+	SourceDisk                 int
+	VhdJson                    string
+	WorkspacePath              string
+}
+
+var createOrUpdateVhdTemplate = template.Must(template.New("CreateOrUpdateVhd").Parse(`
+$ErrorActionPreference = 'Stop'
+` + retryOnFileLockFunction + `
+Import-Module Hyper-V
+$source='{{.Source}}'
+$sourceVm='{{.SourceVm}}'
+$sourceVmDiskIndex={{.SourceVmDiskIndex}}
+$sourceVmControllerLocation={{.SourceVmControllerLocation}}
+$sourceDisk={{.SourceDisk}}
+$vhd = '{{.VhdJson}}' | ConvertFrom-Json
+$vhdType = [Microsoft.Vhd.PowerShell.VhdType]$vhd.VhdType
+$tmpPath = Join-Path '{{.WorkspacePath}}' ([System.Guid]::NewGuid().ToString())
 
+` + vhdDownloadFunctions + `
 if (!(Test-Path -Path $vhd.Path)) {
     $pathDirectory = [System.IO.Path]::GetDirectoryName($vhd.Path)
     $pathFilename = [System.IO.Path]::GetFileName($vhd.Path)
@@ -212,7 +227,36 @@ if (!(Test-Path -Path $vhd.Path)) {
         New-Item -ItemType Directory -Force -Path $pathDirectory
     }
 
-    if ($sourceVm) {
+    if ($sourceVm -and ($sourceVmDiskIndex -ge 0 -or $sourceVmControllerLocation -ge 0)) {
+        #only the selected disk is cloned, in the order hard disk drives are attached to the vm
+        $hardDiskDrives = @(Get-VMHardDiskDrive -VMName $sourceVm | Sort-Object -Property ControllerType, ControllerNumber, ControllerLocation)
+        if ($sourceVmDiskIndex -ge 0) {
+            if ($sourceVmDiskIndex -ge $hardDiskDrives.Count) {
+                throw "Vm $sourceVm has $($hardDiskDrives.Count) hard disk drives, there is no disk with index $sourceVmDiskIndex"
+            }
+            $hardDiskDrive = $hardDiskDrives[$sourceVmDiskIndex]
+        } else {
+            $locationHardDiskDrives = @($hardDiskDrives | ?{ $_.ControllerLocation -eq $sourceVmControllerLocation })
+            if ($locationHardDiskDrives.Count -ne 1) {
+                throw "Vm $sourceVm has $($locationHardDiskDrives.Count) hard disk drives at controller location $sourceVmControllerLocation, use source_vm_disk_index to select one"
+            }
+            $hardDiskDrive = $locationHardDiskDrives[0]
+        }
+
+        if (!$hardDiskDrive.Path) {
+            throw "Hard disk drive $($hardDiskDrive.ControllerType) $($hardDiskDrive.ControllerNumber):$($hardDiskDrive.ControllerLocation) of vm $sourceVm is not a virtual hard disk"
+        }
+
+        #converting merges the checkpoints of the disk into the copy, like exporting the vm does
+        $sourceVmVhd = Get-VHD -Path $hardDiskDrive.Path
+        $sourceVmVhdType = $sourceVmVhd.VhdType
+        if ($sourceVmVhdType -eq [Microsoft.Vhd.PowerShell.VhdType]::Differencing) {
+            $sourceVmVhdType = [Microsoft.Vhd.PowerShell.VhdType]::Dynamic
+        }
+
+        Invoke-RetryOnFileLock -ScriptBlock { Convert-VHD -Path $hardDiskDrive.Path -DestinationPath $vhd.Path -VHDType $sourceVmVhdType }
+        Get-VHD -path $vhd.Path
+    } elseif ($sourceVm) {
         Export-VM -Name $sourceVm -Path $pathDirectory
         $targetName = (split-path $vhd.Path -Leaf)
         $targetName = $targetName.Substring(0,$targetName.LastIndexOf('.')).split('\')[-1]
@@ -232,10 +276,15 @@ if (!(Test-Path -Path $vhd.Path)) {
             Rename-Item -Path "$pathDirectory\$download" -NewName $pathFilename
         }
         else {
-            Copy-Item $source "$pathDirectory\$pathFilename" -Force
+            Invoke-RetryOnFileLock -ScriptBlock { Copy-Item $source "$pathDirectory\$pathFilename" -Force }
         }
 
-        Expand-Downloads -FolderPath $pathDirectory
+        New-Item -ItemType Directory -Force -Path $tmpPath | Out-Null
+        try {
+            Expand-Downloads -FolderPath $pathDirectory -TempPath $tmpPath
+        } finally {
+            Remove-Item -LiteralPath $tmpPath -Force -Recurse -ErrorAction SilentlyContinue
+        }
 
         Pop-Location
     } else {
@@ -283,7 +332,7 @@ if (!(Test-Path -Path $vhd.Path)) {
 }
 `))
 
-func (c *ClientConfig) CreateOrUpdateVhd(ctx context.Context, path string, source string, sourceVm string, sourceDisk int, vhdType api.VhdType, parentPath string, size uint64, blockSize uint32, logicalSectorSize uint32, physicalSectorSize uint32) (err error) {
+func (c *ClientConfig) CreateOrUpdateVhd(ctx context.Context, path string, source string, sourceVm string, sourceVmDiskIndex int, sourceVmControllerLocation int, sourceDisk int, vhdType api.VhdType, parentPath string, size uint64, blockSize uint32, logicalSectorSize uint32, physicalSectorSize uint32) (err error) {
 	vhdJson, err := json.Marshal(api.Vhd{
 		Path:               path,
 		VhdType:            vhdType,
@@ -299,10 +348,190 @@ func (c *ClientConfig) CreateOrUpdateVhd(ctx context.Context, path string, sourc
 	}
 
 	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVhdTemplate, createOrUpdateVhdArgs{
-		Source:     source,
-		SourceVm:   sourceVm,
-		SourceDisk: sourceDisk,
-		VhdJson:    string(vhdJson),
+		Source:                     source,
+		SourceVm:                   sourceVm,
+		SourceVmDiskIndex:          sourceVmDiskIndex,
+		SourceVmControllerLocation: sourceVmControllerLocation,
+		SourceDisk:                 sourceDisk,
+		VhdJson:                    string(vhdJson),
+		WorkspacePath:              c.WorkspacePath(),
+	})
+
+	return err
+}
+
+type createVhdFromWindowsImageArgs struct {
+	WindowsImageJson   string
+	UnattendBase64     string `sensitive:"true"`
+	VhdJson            string
+	AvmaKeyKvpItemName string
+}
+
+var createVhdFromWindowsImageTemplate = template.Must(template.New("CreateVhdFromWindowsImage").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$windowsImage = '{{.WindowsImageJson}}' | ConvertFrom-Json
+$unattendBase64 = '{{.UnattendBase64}}'
+$vhd = '{{.VhdJson}}' | ConvertFrom-Json
+$vhdType = [Microsoft.Vhd.PowerShell.VhdType]$vhd.VhdType
+
+if (Test-Path -Path $vhd.Path) {
+	return
+}
+
+if (!(Test-Path -LiteralPath $windowsImage.IsoPath -PathType Leaf)) {
+	throw [System.Management.Automation.ItemNotFoundException]"Iso does not exist - $($windowsImage.IsoPath)"
+}
+
+$pathDirectory = [System.IO.Path]::GetDirectoryName($vhd.Path)
+if (!(Test-Path $pathDirectory)) {
+	New-Item -ItemType Directory -Force -Path $pathDirectory | Out-Null
+}
+
+$isoMounted = $false
+$vhdMounted = $false
+$succeeded = $false
+try {
+	$diskImage = Mount-DiskImage -ImagePath $windowsImage.IsoPath -StorageType ISO -Access ReadOnly -PassThru
+	$isoMounted = $true
+	$isoDriveLetter = ($diskImage | Get-Volume).DriveLetter
+
+	$imageFile = @('install.wim', 'install.esd') | %{ "$($isoDriveLetter):\sources\$_" } | ?{ Test-Path -LiteralPath $_ } | Select-Object -First 1
+	if (!$imageFile) {
+		throw "Iso does not contain a Windows install image in \sources - $($windowsImage.IsoPath)"
+	}
+
+	$images = @(Get-WindowsImage -ImagePath $imageFile)
+	if ($windowsImage.ImageName) {
+		$image = $images | ?{ $_.ImageName -eq $windowsImage.ImageName } | Select-Object -First 1
+	} elseif ($windowsImage.ImageIndex -gt 0) {
+		$image = $images | ?{ $_.ImageIndex -eq $windowsImage.ImageIndex } | Select-Object -First 1
+	} else {
+		$image = $images | Select-Object -First 1
+	}
+	if (!$image) {
+		throw "Image $($windowsImage.ImageName)$(if ($windowsImage.ImageIndex -gt 0) { $windowsImage.ImageIndex }) does not exist in $($windowsImage.IsoPath), available images are: $(($images | %{ "$($_.ImageIndex) - $($_.ImageName)" }) -join ', ')"
+	}
+
+	$NewVhdArgs = @{}
+	$NewVhdArgs.Path = $vhd.Path
+	$NewVhdArgs.SizeBytes = $vhd.Size
+	if ($vhdType -eq [Microsoft.Vhd.PowerShell.VhdType]::Fixed) {
+		$NewVhdArgs.Fixed = $true
+	} else {
+		$NewVhdArgs.Dynamic = $true
+	}
+	if ($vhd.BlockSize -gt 0) {
+		$NewVhdArgs.BlockSizeBytes = $vhd.BlockSize
+	}
+	if ($vhd.PhysicalSectorSize -gt 0) {
+		$NewVhdArgs.PhysicalSectorSizeBytes = $vhd.PhysicalSectorSize
+	}
+	if ($vhd.LogicalSectorSize -gt 0) {
+		$NewVhdArgs.LogicalSectorSizeBytes = $vhd.LogicalSectorSize
+	}
+	New-VHD @NewVhdArgs | Out-Null
+
+	$disk = Mount-VHD -Path $vhd.Path -Passthru | Get-Disk
+	$vhdMounted = $true
+
+	if ($windowsImage.Generation -eq 2) {
+		Initialize-Disk -Number $disk.Number -PartitionStyle GPT
+		$systemPartition = New-Partition -DiskNumber $disk.Number -Size 100MB -GptType '{c12a7328-f81f-11d2-ba4b-00a0c93ec93b}'
+		$systemPartition | Format-Volume -FileSystem FAT32 -Force -Confirm:$false | Out-Null
+		New-Partition -DiskNumber $disk.Number -Size 16MB -GptType '{e3c9e316-0b5c-4db8-817d-f92df00215ae}' | Out-Null
+		$windowsPartition = New-Partition -DiskNumber $disk.Number -UseMaximumSize -GptType '{ebd0a0a2-b9e5-4433-87c0-68b6b72699c7}'
+		$firmware = 'UEFI'
+	} else {
+		Initialize-Disk -Number $disk.Number -PartitionStyle MBR
+		$systemPartition = New-Partition -DiskNumber $disk.Number -Size 350MB -MbrType IFS -IsActive
+		$systemPartition | Format-Volume -FileSystem NTFS -Force -Confirm:$false | Out-Null
+		$windowsPartition = New-Partition -DiskNumber $disk.Number -UseMaximumSize -MbrType IFS
+		$firmware = 'BIOS'
+	}
+	$windowsPartition | Format-Volume -FileSystem NTFS -Force -Confirm:$false | Out-Null
+
+	$systemPartition | Add-PartitionAccessPath -AssignDriveLetter
+	$windowsPartition | Add-PartitionAccessPath -AssignDriveLetter
+	$systemDrive = "$((Get-Partition -DiskNumber $disk.Number -PartitionNumber $systemPartition.PartitionNumber).DriveLetter):"
+	$windowsDrive = "$((Get-Partition -DiskNumber $disk.Number -PartitionNumber $windowsPartition.PartitionNumber).DriveLetter):"
+
+	Expand-WindowsImage -ImagePath $imageFile -Index $image.ImageIndex -ApplyPath "$windowsDrive\" | Out-Null
+
+	$windowsImage.DriverPaths | ?{ $_ } | %{
+		if (!(Test-Path -LiteralPath $_)) {
+			throw [System.Management.Automation.ItemNotFoundException]"Driver does not exist - $_"
+		}
+		Add-WindowsDriver -Path "$windowsDrive\" -Driver $_ -Recurse | Out-Null
+	}
+
+	#install the automatic virtual machine activation key that the HyperV host publishes with key value pair exchange
+	#when Windows setup completes, unless the image already has its own setup complete script
+	$setupScriptsPath = "$windowsDrive\Windows\Setup\Scripts"
+	if (!(Test-Path -LiteralPath "$setupScriptsPath\SetupComplete.cmd")) {
+		if (!(Test-Path -LiteralPath $setupScriptsPath)) {
+			New-Item -ItemType Directory -Force -Path $setupScriptsPath | Out-Null
+		}
+		Set-Content -LiteralPath "$setupScriptsPath\SetupComplete.cmd" -Encoding ASCII -Value @(
+			'@echo off',
+			'for /f "tokens=2*" %%a in (''reg query "HKLM\SOFTWARE\Microsoft\Virtual Machine\External" /v {{.AvmaKeyKvpItemName}} 2^>nul ^| find "{{.AvmaKeyKvpItemName}}"'') do cscript //nologo %windir%\system32\slmgr.vbs /ipk %%b'
+		)
+	}
+
+	if ($unattendBase64) {
+		$pantherPath = "$windowsDrive\Windows\Panther"
+		if (!(Test-Path -LiteralPath $pantherPath)) {
+			New-Item -ItemType Directory -Force -Path $pantherPath | Out-Null
+		}
+		[System.IO.File]::WriteAllBytes("$pantherPath\unattend.xml", [System.Convert]::FromBase64String($unattendBase64))
+	}
+
+	$bcdbootOutput = & "$env:SystemRoot\System32\bcdboot.exe" "$windowsDrive\Windows" /s $systemDrive /f $firmware 2>&1
+	if ($LASTEXITCODE -ne 0) {
+		throw "Unable to make $($vhd.Path) bootable: $bcdbootOutput"
+	}
+
+	$succeeded = $true
+} finally {
+	if ($vhdMounted) {
+		Dismount-VHD -Path $vhd.Path
+	}
+	if ($isoMounted) {
+		Dismount-DiskImage -ImagePath $windowsImage.IsoPath | Out-Null
+	}
+	if (!$succeeded -and (Test-Path -Path $vhd.Path)) {
+		Remove-Item -Path $vhd.Path -Force
+	}
+}
+`))
+
+func (c *ClientConfig) CreateVhdFromWindowsImage(ctx context.Context, path string, windowsImage api.WindowsImage, vhdType api.VhdType, size uint64, blockSize uint32, logicalSectorSize uint32, physicalSectorSize uint32) (err error) {
+	// the answer file is passed separately, it is xml that could contain anything that would need escaping in the script
+	unattendBase64 := base64.StdEncoding.EncodeToString([]byte(windowsImage.Unattend))
+	windowsImage.Unattend = ""
+
+	windowsImageJson, err := json.Marshal(windowsImage)
+	if err != nil {
+		return err
+	}
+
+	vhdJson, err := json.Marshal(api.Vhd{
+		Path:               path,
+		VhdType:            vhdType,
+		Size:               size,
+		BlockSize:          blockSize,
+		LogicalSectorSize:  logicalSectorSize,
+		PhysicalSectorSize: physicalSectorSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVhdFromWindowsImageTemplate, createVhdFromWindowsImageArgs{
+		WindowsImageJson:   string(windowsImageJson),
+		UnattendBase64:     unattendBase64,
+		VhdJson:            string(vhdJson),
+		AvmaKeyKvpItemName: api.AvmaKeyKvpItemName,
 	})
 
 	return err
@@ -315,9 +544,12 @@ type resizeVhdArgs struct {
 
 var resizeVhdTemplate = template.Must(template.New("ResizeVhd").Parse(`
 $ErrorActionPreference = 'Stop'
+` + retryOnFileLockFunction + `
 $vhd = Get-VHD -Path '{{.Path}}'
 if ($vhd.Size -ne {{.Size}}){
-	Resize-VHD -Path '{{.Path}}' -SizeBytes {{.Size}}
+	Invoke-RetryOnFileLock -ScriptBlock {
+		Resize-VHD -Path '{{.Path}}' -SizeBytes {{.Size}}
+	}
 }
 `))
 
@@ -330,12 +562,48 @@ func (c *ClientConfig) ResizeVhd(ctx context.Context, path string, size uint64)
 	return err
 }
 
+type setVhdParentPathArgs struct {
+	Path       string
+	ParentPath string
+}
+
+// the parent of a differencing disk can only be changed while the disk is not in use by a running virtual machine.
+// Set-VHD checks that the new parent has the identifier the differencing disk was created from, so a disk can only be
+// re-linked to a moved or copied parent and not to a different one.
+var setVhdParentPathTemplate = template.Must(template.New("SetVhdParentPath").Parse(`
+$ErrorActionPreference = 'Stop'
+` + retryOnFileLockFunction + `
+$vhd = Get-VHD -Path '{{.Path}}'
+if ($vhd.VhdType -ne [Microsoft.Vhd.PowerShell.VhdType]::Differencing) {
+	throw "Virtual hard disk {{.Path}} is not a differencing disk, so it has no parent to repair"
+}
+if ($vhd.ParentPath -ne '{{.ParentPath}}'){
+	if (!(Test-Path '{{.ParentPath}}')) {
+		throw "Parent virtual hard disk does not exist - {{.ParentPath}}"
+	}
+
+	Invoke-RetryOnFileLock -ScriptBlock {
+		Set-VHD -Path '{{.Path}}' -ParentPath '{{.ParentPath}}'
+	}
+}
+`))
+
+func (c *ClientConfig) SetVhdParentPath(ctx context.Context, path string, parentPath string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, setVhdParentPathTemplate, setVhdParentPathArgs{
+		Path:       path,
+		ParentPath: parentPath,
+	})
+
+	return err
+}
+
 type getVhdArgs struct {
 	Path string
 }
 
 var getVhdTemplate = template.Must(template.New("GetVhd").Parse(`
 $ErrorActionPreference = 'Stop'
+` + vhdDeletionProtectionFunctions + `
 $path='{{.Path}}'
 
 $vhdObject = $null
@@ -357,7 +625,17 @@ if (Test-Path $path) {
 		DiskIdentifier=$_.DiskIdentifier;
 		VhdType=$_.VhdType;
 		VhdFormat=$_.VhdFormat;
+		Healthy=$false;
+		HealthErrorMessage='';
+		DeletionProtection=(Test-VhdDeletionProtection -Path $path);
 	}}
+
+	# Test-VHD also checks the chain of parents of a differencing disk
+	try {
+		$vhdObject.Healthy = [bool](Test-VHD -Path $path)
+	} catch {
+		$vhdObject.HealthErrorMessage = $_.Exception.Message
+	}
 }
 
 if ($vhdObject){
@@ -377,19 +655,220 @@ func (c *ClientConfig) GetVhd(ctx context.Context, path string) (result api.Vhd,
 	return result, err
 }
 
+type getVhdParentChainArgs struct {
+	Path string
+}
+
+var getVhdParentChainTemplate = template.Must(template.New("GetVhdParentChain").Parse(`
+$ErrorActionPreference = 'Stop'
+$path='{{.Path}}'
+
+$vhdChainObject = @()
+$visitedPaths = @{}
+
+while ($path -and !$visitedPaths.ContainsKey($path)) {
+	$visitedPaths[$path] = $true
+
+	$vhdChainEntry = @{
+		Path=$path;
+		ParentPath='';
+		VhdType=0;
+		VhdFormat=0;
+		Exists=$false;
+		Valid=$false;
+		ErrorMessage='';
+	}
+	$vhdChainObject += $vhdChainEntry
+
+	if (!(Test-Path $path)) {
+		$vhdChainEntry.ErrorMessage = "Virtual hard disk does not exist - $path"
+		break
+	}
+	$vhdChainEntry.Exists = $true
+
+	try {
+		$vhd = Get-VHD -Path $path
+		$vhdChainEntry.ParentPath = "$($vhd.ParentPath)"
+		$vhdChainEntry.VhdType = $vhd.VhdType
+		$vhdChainEntry.VhdFormat = $vhd.VhdFormat
+	} catch {
+		$vhdChainEntry.ErrorMessage = $_.Exception.Message
+		break
+	}
+
+	try {
+		$vhdChainEntry.Valid = [bool](Test-VHD -Path $path)
+	} catch {
+		$vhdChainEntry.ErrorMessage = $_.Exception.Message
+	}
+
+	$path = $vhdChainEntry.ParentPath
+}
+
+if ($vhdChainObject) {
+	$vhdChain = ConvertTo-Json -InputObject $vhdChainObject
+	$vhdChain
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetVhdParentChain(ctx context.Context, path string) (result []api.VhdChainEntry, err error) {
+	result = make([]api.VhdChainEntry, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVhdParentChainTemplate, getVhdParentChainArgs{
+		Path: path,
+	}, &result)
+
+	return result, err
+}
+
+// vhdDeletionProtectionFunctions are functions for the records of the virtual hard disks that are protected from
+// deletion, a virtual hard disk has no place of its own to keep a marker so they are kept by the hash of its path
+const vhdDeletionProtectionFunctions = `
+$vhdDeletionProtectionDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\vhd-deletion-protections'
+
+function Get-VhdDeletionProtectionPath {
+	param([string]$Path)
+
+	$sha1 = [System.Security.Cryptography.SHA1]::Create()
+	$hash = $sha1.ComputeHash([System.Text.Encoding]::UTF8.GetBytes([System.IO.Path]::GetFullPath($Path).ToLowerInvariant()))
+	return Join-Path $vhdDeletionProtectionDirectory "$(-join ($hash | %{ $_.ToString('x2') })).txt"
+}
+
+function Test-VhdDeletionProtection {
+	param([string]$Path)
+
+	return Test-Path -LiteralPath (Get-VhdDeletionProtectionPath -Path $Path) -PathType Leaf
+}
+
+function Assert-VhdNotDeletionProtected {
+	param([string]$Path)
+
+	if (Test-VhdDeletionProtection -Path $Path) {
+		throw "Virtual hard disk $Path is protected from deletion, turn off deletion_protection of it first"
+	}
+}
+`
+
+type setVhdDeletionProtectionArgs struct {
+	Path               string
+	DeletionProtection bool
+}
+
+var setVhdDeletionProtectionTemplate = template.Must(template.New("SetVhdDeletionProtection").Parse(`
+$ErrorActionPreference = 'Stop'
+` + vhdDeletionProtectionFunctions + `
+$path = '{{.Path}}'
+$deletionProtectionPath = Get-VhdDeletionProtectionPath -Path $path
+
+if (${{.DeletionProtection}}) {
+	if (!(Test-Path -LiteralPath $vhdDeletionProtectionDirectory -PathType Container)) {
+		New-Item -ItemType Directory -Path $vhdDeletionProtectionDirectory | Out-Null
+	}
+
+	Set-Content -LiteralPath $deletionProtectionPath -Value $path -Encoding UTF8
+} elseif (Test-Path -LiteralPath $deletionProtectionPath -PathType Leaf) {
+	Remove-Item -LiteralPath $deletionProtectionPath -Force
+}
+`))
+
+func (c *ClientConfig) SetVhdDeletionProtection(ctx context.Context, path string, deletionProtection bool) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, setVhdDeletionProtectionTemplate, setVhdDeletionProtectionArgs{
+		Path:               path,
+		DeletionProtection: deletionProtection,
+	})
+
+	return err
+}
+
+type getVhdSourceVersionArgs struct {
+	Source string
+}
+
+var getVhdSourceVersionTemplate = template.Must(template.New("GetVhdSourceVersion").Parse(`
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'
+$response = Invoke-WebRequest -Uri '{{.Source}}' -Method Head -UseBasicParsing
+
+$vhdSourceVersionObject = @{
+	ETag="$($response.Headers['ETag'])";
+	LastModified="$($response.Headers['Last-Modified'])";
+}
+
+$vhdSourceVersion = ConvertTo-Json -InputObject $vhdSourceVersionObject
+$vhdSourceVersion
+`))
+
+func (c *ClientConfig) GetVhdSourceVersion(ctx context.Context, source string) (result api.VhdSourceVersion, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVhdSourceVersionTemplate, getVhdSourceVersionArgs{
+		Source: source,
+	}, &result)
+
+	return result, err
+}
+
+type updateVhdFromSourceArgs struct {
+	Path          string
+	Source        string
+	WorkspacePath string
+}
+
+var updateVhdFromSourceTemplate = template.Must(template.New("UpdateVhdFromSource").Parse(`
+$ErrorActionPreference = 'Stop'
+` + retryOnFileLockFunction + vhdDownloadFunctions + `
+$path = '{{.Path}}'
+$source = '{{.Source}}'
+$pathDirectory = [System.IO.Path]::GetDirectoryName($path)
+$pathFilename = [System.IO.Path]::GetFileName($path)
+
+#the image is downloaded to the workspace, so that a failed download leaves the current image in place
+$downloadDirectory = Join-Path '{{.WorkspacePath}}' ([System.Guid]::NewGuid().ToString())
+New-Item -ItemType Directory -Force -Path $downloadDirectory | Out-Null
+
+try {
+	Get-FileFromUri -Url $source -FolderPath $downloadDirectory
+	$download = Split-Path $source -Leaf
+	Rename-Item -Path "$downloadDirectory\$download" -NewName $pathFilename
+	Expand-Downloads -FolderPath $downloadDirectory -TempPath $downloadDirectory
+
+	$downloadPath = Join-Path $downloadDirectory $pathFilename
+	if (!(Test-Path -LiteralPath $downloadPath -PathType Leaf)) {
+		throw "Downloading $source did not result in $pathFilename"
+	}
+
+	Invoke-RetryOnFileLock -ScriptBlock { Move-Item -LiteralPath $downloadPath -Destination $path -Force }
+} finally {
+	Remove-Item -LiteralPath $downloadDirectory -Force -Recurse -ErrorAction SilentlyContinue
+}
+`))
+
+func (c *ClientConfig) UpdateVhdFromSource(ctx context.Context, path string, source string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVhdFromSourceTemplate, updateVhdFromSourceArgs{
+		Path:          path,
+		Source:        source,
+		WorkspacePath: c.WorkspacePath(),
+	})
+
+	return err
+}
+
 type deleteVhdArgs struct {
 	Path string
 }
 
 var deleteVhdTemplate = template.Must(template.New("DeleteVhd").Parse(`
 $ErrorActionPreference = 'Stop'
-
+` + retryOnFileLockFunction + vhdDeletionProtectionFunctions + `
 $targetDirectory = (split-path '{{.Path}}' -Parent)
 $targetName = (split-path '{{.Path}}' -Leaf)
 $targetName = $targetName.Substring(0,$targetName.LastIndexOf('.')).split('\')[-1]
 
-Get-ChildItem -Path $targetDirectory |?{$_.BaseName.StartsWith($targetName)} | %{
-	Remove-Item $_.FullName -Force
+$targetFiles = @(Get-ChildItem -Path $targetDirectory |?{$_.BaseName.StartsWith($targetName)})
+$targetFiles | %{ Assert-VhdNotDeletionProtected -Path $_.FullName }
+
+$targetFiles | %{
+	Invoke-RetryOnFileLock -ScriptBlock { param($fullName) Remove-Item $fullName -Force } -ArgumentList $_.FullName
 }
 `))
 
@@ -400,3 +879,41 @@ func (c *ClientConfig) DeleteVhd(ctx context.Context, path string) (err error) {
 
 	return err
 }
+
+type deleteVhdWithCheckpointsArgs struct {
+	Path string
+}
+
+var deleteVhdWithCheckpointsTemplate = template.Must(template.New("DeleteVhdWithCheckpoints").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+` + retryOnFileLockFunction + vhdDeletionProtectionFunctions + `
+$path = '{{.Path}}'
+
+$paths = @()
+while ($path -and (Test-Path -LiteralPath $path -PathType Leaf)) {
+	$paths += $path
+
+	#only the automatic virtual hard disks of checkpoints belong to the virtual machine, the parent of a differencing
+	#disk that was attached to it may be shared with other virtual machines
+	if ([System.IO.Path]::GetExtension($path) -ne '.avhdx' -and [System.IO.Path]::GetExtension($path) -ne '.avhd') {
+		break
+	}
+
+	$path = (Get-VHD -Path $path).ParentPath
+}
+
+$paths | %{ Assert-VhdNotDeletionProtected -Path $_ }
+
+$paths | %{
+	Invoke-RetryOnFileLock -ScriptBlock { param($fullName) Remove-Item -LiteralPath $fullName -Force } -ArgumentList $_
+}
+`))
+
+func (c *ClientConfig) DeleteVhdWithCheckpoints(ctx context.Context, path string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVhdWithCheckpointsTemplate, deleteVhdWithCheckpointsArgs{
+		Path: path,
+	})
+
+	return err
+}
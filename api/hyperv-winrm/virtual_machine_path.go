@@ -0,0 +1,90 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createVirtualMachinePathArgs struct {
+	VirtualMachinePathJson string
+}
+
+var createVirtualMachinePathTemplate = template.Must(template.New("CreateVirtualMachinePath").Parse(`
+$ErrorActionPreference = 'Stop'
+$virtualMachinePath = '{{.VirtualMachinePathJson}}' | ConvertFrom-Json
+
+@($virtualMachinePath.Path, $virtualMachinePath.VirtualMachinesPath, $virtualMachinePath.VirtualHardDisksPath, $virtualMachinePath.SnapshotsPath) | %{
+	if (!(Test-Path -LiteralPath $_ -PathType Container)) {
+		New-Item -ItemType Directory -Force -Path $_ | Out-Null
+	}
+}
+`))
+
+func (c *ClientConfig) CreateVirtualMachinePath(ctx context.Context, virtualMachinePath api.VirtualMachinePath) (err error) {
+	virtualMachinePathJson, err := json.Marshal(virtualMachinePath)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVirtualMachinePathTemplate, createVirtualMachinePathArgs{
+		VirtualMachinePathJson: string(virtualMachinePathJson),
+	})
+
+	return err
+}
+
+type virtualMachinePathExistsArgs struct {
+	VirtualMachinePathJson string
+}
+
+var virtualMachinePathExistsTemplate = template.Must(template.New("VirtualMachinePathExists").Parse(`
+$ErrorActionPreference = 'Stop'
+$virtualMachinePath = '{{.VirtualMachinePathJson}}' | ConvertFrom-Json
+
+$missingPaths = @(@($virtualMachinePath.Path, $virtualMachinePath.VirtualMachinesPath, $virtualMachinePath.VirtualHardDisksPath, $virtualMachinePath.SnapshotsPath) | ?{
+	!(Test-Path -LiteralPath $_ -PathType Container)
+})
+
+$exists = ConvertTo-Json -InputObject ($missingPaths.Length -eq 0)
+$exists
+`))
+
+func (c *ClientConfig) VirtualMachinePathExists(ctx context.Context, virtualMachinePath api.VirtualMachinePath) (result bool, err error) {
+	virtualMachinePathJson, err := json.Marshal(virtualMachinePath)
+
+	if err != nil {
+		return false, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, virtualMachinePathExistsTemplate, virtualMachinePathExistsArgs{
+		VirtualMachinePathJson: string(virtualMachinePathJson),
+	}, &result)
+
+	return result, err
+}
+
+type deleteVirtualMachinePathArgs struct {
+	Path string
+}
+
+var deleteVirtualMachinePathTemplate = template.Must(template.New("DeleteVirtualMachinePath").Parse(`
+$ErrorActionPreference = 'Stop'
+` + retryOnFileLockFunction + `
+$path = '{{.Path}}'
+
+if (Test-Path -LiteralPath $path -PathType Container) {
+	Invoke-RetryOnFileLock -ScriptBlock { param($path) Remove-Item -LiteralPath $path -Force -Recurse } -ArgumentList $path
+}
+`))
+
+func (c *ClientConfig) DeleteVirtualMachinePath(ctx context.Context, path string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVirtualMachinePathTemplate, deleteVirtualMachinePathArgs{
+		Path: path,
+	})
+
+	return err
+}
@@ -1,8 +1,10 @@
 package hyperv_winrm
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"text/template"
 
 	"github.com/taliesins/terraform-provider-hyperv/api"
@@ -34,7 +36,8 @@ func (c *ClientConfig) VmExists(ctx context.Context, name string) (result api.Vm
 }
 
 type createVmArgs struct {
-	VmJson string
+	VmJson                  string
+	GuestStateIsolationType string
 }
 
 var createVmTemplate = template.Must(template.New("CreateVm").Parse(`
@@ -65,6 +68,11 @@ if ($vm.Path) {
 	$NewVmArgs.Path = $vm.Path
 }
 
+#The guest state isolation type can only be chosen when the vm is created
+if ('{{.GuestStateIsolationType}}' -ne 'Disabled') {
+	$NewVmArgs.GuestStateIsolationType = '{{.GuestStateIsolationType}}'
+}
+
 New-Vm @NewVmArgs
 
 #Delete any auto-generated network adapter
@@ -112,6 +120,9 @@ if ($vm.StaticMemory) {
 
 Set-Vm @SetVmArgs
 
+if ($vm.MemoryResourcePoolName) {
+	Set-VMMemory -VMName $vm.Name -ResourcePoolName $vm.MemoryResourcePoolName
+}
 `))
 
 func (c *ClientConfig) CreateVm(
@@ -133,11 +144,13 @@ func (c *ClientConfig) CreateVm(
 	memoryMaximumBytes int64,
 	memoryMinimumBytes int64,
 	memoryStartupBytes int64,
+	memoryResourcePoolName string,
 	notes string,
 	processorCount int64,
 	smartPagingFilePath string,
 	snapshotFileLocation string,
 	staticMemory bool,
+	guestStateIsolationType api.GuestStateIsolationType,
 ) (err error) {
 	vmJson, err := json.Marshal(api.Vm{
 		Name:                                name,
@@ -157,11 +170,13 @@ func (c *ClientConfig) CreateVm(
 		MemoryMaximumBytes:                  memoryMaximumBytes,
 		MemoryMinimumBytes:                  memoryMinimumBytes,
 		MemoryStartupBytes:                  memoryStartupBytes,
+		MemoryResourcePoolName:              memoryResourcePoolName,
 		Notes:                               notes,
 		ProcessorCount:                      processorCount,
 		SmartPagingFilePath:                 smartPagingFilePath,
 		SnapshotFileLocation:                snapshotFileLocation,
 		StaticMemory:                        staticMemory,
+		GuestStateIsolationType:             guestStateIsolationType,
 	})
 
 	if err != nil {
@@ -169,7 +184,8 @@ func (c *ClientConfig) CreateVm(
 	}
 
 	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVmTemplate, createVmArgs{
-		VmJson: string(vmJson),
+		VmJson:                  string(vmJson),
+		GuestStateIsolationType: guestStateIsolationType.String(),
 	})
 
 	return err
@@ -199,11 +215,13 @@ $vmObject = Get-VM -Name '{{.Name}}*' -ErrorAction SilentlyContinue | ?{$_.Name
 	MemoryMaximumBytes=$_.MemoryMaximum;
 	MemoryMinimumBytes=$_.MemoryMinimum;
 	MemoryStartupBytes=$_.MemoryStartup;
+	MemoryResourcePoolName=$(Get-VMMemory -VM $_ | ?{ $_.ResourcePoolName -ne 'Primordial' } | %{ $_.ResourcePoolName });
 	Notes=$_.Notes;
 	ProcessorCount=$_.ProcessorCount;
 	SmartPagingFilePath=$_.SmartPagingFilePath;
 	SnapshotFileLocation=$_.SnapshotFileLocation;
 	StaticMemory=!$_.DynamicMemoryEnabled;
+	GuestStateIsolationType=$(if ($_.PSObject.Properties['GuestStateIsolationType'] -and $_.GuestStateIsolationType) { "$($_.GuestStateIsolationType)" } else { 'Disabled' });
 }}
 
 if ($vmObject) {
@@ -222,6 +240,175 @@ func (c *ClientConfig) GetVm(ctx context.Context, name string) (result api.Vm, e
 	return result, err
 }
 
+type getVmWithDevicesArgs struct {
+	Name                         string
+	GetVmScript                  string
+	GetVmProcessorScript         string
+	GetVmFirmwareScript          string
+	GetIntegrationServicesScript string
+	GetDvdDrivesScript           string
+	GetHardDiskDrivesScript      string
+	GetNetworkAdaptersScript     string
+	GetVmStatusScript            string
+	GetKvpHostItemsScript        string
+	GetVmMemoryNumaScript        string
+}
+
+// getVmWithDevicesTemplate runs the get scripts of the vm and its child devices in one go. Each script is run in its
+// own scope and its json output is passed back as is, so that it can be unmarshalled exactly like the individual calls.
+var getVmWithDevicesTemplate = template.Must(template.New("GetVmWithDevices").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.Name}}*' -ErrorAction SilentlyContinue | ?{$_.Name -eq '{{.Name}}' }
+
+if ($vmObject) {
+	$vmFirmware = ''
+	if ($vmObject.Generation -gt 1) {
+		$vmFirmware = & { {{.GetVmFirmwareScript}} } | Out-String
+	}
+
+	$vmWithDevicesObject = @{
+		Vm=& { {{.GetVmScript}} } | Out-String;
+		VmProcessor=& { {{.GetVmProcessorScript}} } | Out-String;
+		VmFirmware=$vmFirmware;
+		IntegrationServices=& { {{.GetIntegrationServicesScript}} } | Out-String;
+		DvdDrives=& { {{.GetDvdDrivesScript}} } | Out-String;
+		HardDiskDrives=& { {{.GetHardDiskDrivesScript}} } | Out-String;
+		NetworkAdapters=& { {{.GetNetworkAdaptersScript}} } | Out-String;
+		VmStatus=& { {{.GetVmStatusScript}} } | Out-String;
+		KvpHostItems=& { {{.GetKvpHostItemsScript}} } | Out-String;
+		VmMemoryNuma=& { {{.GetVmMemoryNumaScript}} } | Out-String;
+	}
+
+	$vmWithDevices = ConvertTo-Json -InputObject $vmWithDevicesObject
+	$vmWithDevices
+} else {
+	"{}"
+}
+`))
+
+type getVmWithDevicesResult struct {
+	Vm                  string
+	VmProcessor         string
+	VmFirmware          string
+	IntegrationServices string
+	DvdDrives           string
+	HardDiskDrives      string
+	NetworkAdapters     string
+	VmStatus            string
+	KvpHostItems        string
+	VmMemoryNuma        string
+}
+
+func renderScript(script *template.Template, args interface{}) (string, error) {
+	var scriptRendered bytes.Buffer
+	err := script.Execute(&scriptRendered, args)
+	if err != nil {
+		return "", err
+	}
+
+	return scriptRendered.String(), nil
+}
+
+func (c *ClientConfig) GetVmWithDevices(ctx context.Context, name string, networkAdaptersWaitForIps []api.VmNetworkAdapterWaitForIp) (result api.VmWithDevices, err error) {
+	args := getVmWithDevicesArgs{
+		Name: name,
+	}
+
+	scripts := []struct {
+		script *template.Template
+		args   interface{}
+		result *string
+	}{
+		{getVmTemplate, getVmArgs{Name: name}, &args.GetVmScript},
+		{getVmProcessorTemplate, getVmProcessorArgs{VmName: name}, &args.GetVmProcessorScript},
+		{getVmFirmwareTemplate, getVmFirmwareArgs{VmName: name}, &args.GetVmFirmwareScript},
+		{getVmIntegrationServicesTemplate, getVmIntegrationServicesArgs{VmName: name}, &args.GetIntegrationServicesScript},
+		{getVmDvdDrivesTemplate, getVmDvdDrivesArgs{VmName: name}, &args.GetDvdDrivesScript},
+		{getVmHardDiskDrivesTemplate, getVmHardDiskDrivesArgs{VmName: name}, &args.GetHardDiskDrivesScript},
+		{getVmNetworkAdaptersTemplate, getVmNetworkAdaptersArgs{VmName: name}, &args.GetNetworkAdaptersScript},
+		{getVmStatusTemplate, getVmStatusArgs{VmName: name}, &args.GetVmStatusScript},
+		{getVmKvpHostItemsTemplate, getVmKvpHostItemsArgs{VmName: name}, &args.GetKvpHostItemsScript},
+		{getVmMemoryNumaTemplate, getVmMemoryNumaArgs{VmName: name}, &args.GetVmMemoryNumaScript},
+	}
+
+	for _, script := range scripts {
+		*script.result, err = renderScript(script.script, script.args)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	var getVmWithDevicesResult getVmWithDevicesResult
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmWithDevicesTemplate, args, &getVmWithDevicesResult)
+	if err != nil {
+		return result, err
+	}
+
+	if getVmWithDevicesResult.Vm == "" {
+		return result, nil
+	}
+
+	var vmProcessor api.VmProcessor
+	var vmFirmware api.VmFirmware
+
+	results := []struct {
+		json   string
+		result interface{}
+	}{
+		{getVmWithDevicesResult.Vm, &result.Vm},
+		{getVmWithDevicesResult.VmProcessor, &vmProcessor},
+		{getVmWithDevicesResult.VmFirmware, &vmFirmware},
+		{getVmWithDevicesResult.IntegrationServices, &result.IntegrationServices},
+		{getVmWithDevicesResult.DvdDrives, &result.DvdDrives},
+		{getVmWithDevicesResult.HardDiskDrives, &result.HardDiskDrives},
+		{getVmWithDevicesResult.NetworkAdapters, &result.NetworkAdapters},
+		{getVmWithDevicesResult.VmStatus, &result.VmStatus},
+		{getVmWithDevicesResult.KvpHostItems, &result.KvpHostItems},
+		{getVmWithDevicesResult.VmMemoryNuma, &result.VmMemoryNuma},
+	}
+
+	for _, r := range results {
+		if r.json == "" {
+			continue
+		}
+
+		err = json.Unmarshal([]byte(r.json), r.result)
+		if err != nil {
+			return result, fmt.Errorf("unable to unmarshal result of getting vm with devices for %s: %s\n%s", name, err, r.json)
+		}
+	}
+
+	result.VmProcessors = []api.VmProcessor{vmProcessor}
+
+	result.VmFirmwares = c.GetNoVmFirmwares(ctx)
+	if getVmWithDevicesResult.VmFirmware != "" {
+		result.VmFirmwares = append(result.VmFirmwares, vmFirmware)
+	}
+
+	if result.IntegrationServices == nil {
+		result.IntegrationServices = make([]api.VmIntegrationService, 0)
+	}
+	if result.DvdDrives == nil {
+		result.DvdDrives = make([]api.VmDvdDrive, 0)
+	}
+	if result.HardDiskDrives == nil {
+		result.HardDiskDrives = make([]api.VmHardDiskDrive, 0)
+	}
+	if result.NetworkAdapters == nil {
+		result.NetworkAdapters = make([]api.VmNetworkAdapter, 0)
+	}
+	if result.KvpHostItems == nil {
+		result.KvpHostItems = make(map[string]string)
+	}
+	if result.VmMemoryNuma.PreferredNumaNodes == nil {
+		result.VmMemoryNuma.PreferredNumaNodes = make([]int, 0)
+	}
+
+	enrichVmNetworkAdaptersWaitForIps(result.NetworkAdapters, networkAdaptersWaitForIps)
+
+	return result, nil
+}
+
 type updateVmArgs struct {
 	VmJson string
 }
@@ -239,7 +426,7 @@ $allowUnverifiedPaths = $true #Not a property set on the vm object, skips valida
 $vmObject = Get-VM -Name "$($vm.Name)*" | ?{$_.Name -eq $vm.Name}
 
 if (!$vmObject){
-	throw "VM does not exist - $($vm.Name)"
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vm.Name)"
 }
 
 #Set static and dynamic properties can't be set at the same time, but we need the values to match terraforms state
@@ -280,6 +467,14 @@ if ($vm.StaticMemory) {
 }
 
 Set-Vm @SetVmArgs
+
+$memoryResourcePoolName = $vm.MemoryResourcePoolName
+if (!$memoryResourcePoolName) {
+	$memoryResourcePoolName = 'Primordial'
+}
+if ((Get-VMMemory -VMName $vm.Name).ResourcePoolName -ne $memoryResourcePoolName) {
+	Set-VMMemory -VMName $vm.Name -ResourcePoolName $memoryResourcePoolName
+}
 `))
 
 func (c *ClientConfig) UpdateVm(
@@ -300,6 +495,7 @@ func (c *ClientConfig) UpdateVm(
 	memoryMaximumBytes int64,
 	memoryMinimumBytes int64,
 	memoryStartupBytes int64,
+	memoryResourcePoolName string,
 	notes string,
 	processorCount int64,
 	smartPagingFilePath string,
@@ -323,6 +519,7 @@ func (c *ClientConfig) UpdateVm(
 		MemoryMaximumBytes:                  memoryMaximumBytes,
 		MemoryMinimumBytes:                  memoryMinimumBytes,
 		MemoryStartupBytes:                  memoryStartupBytes,
+		MemoryResourcePoolName:              memoryResourcePoolName,
 		Notes:                               notes,
 		ProcessorCount:                      processorCount,
 		SmartPagingFilePath:                 smartPagingFilePath,
@@ -341,18 +538,51 @@ func (c *ClientConfig) UpdateVm(
 	return err
 }
 
+type resizeVmMemoryArgs struct {
+	Name               string
+	MemoryStartupBytes int64
+}
+
+var resizeVmMemoryTemplate = template.Must(template.New("ResizeVmMemory").Parse(`
+$ErrorActionPreference = 'Stop'
+Get-VM -Name '{{.Name}}*' | ?{$_.Name -eq '{{.Name}}'} | Set-VMMemory -StartupBytes {{.MemoryStartupBytes}}
+`))
+
+func (c *ClientConfig) ResizeVmMemory(ctx context.Context, name string, memoryStartupBytes int64) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, resizeVmMemoryTemplate, resizeVmMemoryArgs{
+		Name:               name,
+		MemoryStartupBytes: memoryStartupBytes,
+	})
+
+	return err
+}
+
 type deleteVmArgs struct {
-	Name string
+	Name                           string
+	DeletionProtectionKvpItemName  string
+	DeletionProtectionKvpItemValue string
 }
 
 var deleteVmTemplate = template.Must(template.New("DeleteVm").Parse(`
 $ErrorActionPreference = 'Stop'
-Get-VM -Name '{{.Name}}*' | ?{$_.Name -eq '{{.Name}}'} | Remove-VM -force
+$vmObject = Get-VM -Name '{{.Name}}*' | ?{$_.Name -eq '{{.Name}}'}
+
+if (!$vmObject){
+	return
+}
+` + getVmKvpHostItems + `
+if ($hostExchangeItems['{{.DeletionProtectionKvpItemName}}'] -eq '{{.DeletionProtectionKvpItemValue}}') {
+	throw "VM {{.Name}} is protected from deletion by its {{.DeletionProtectionKvpItemName}} key value pair, turn off deletion_protection of it first"
+}
+
+$vmObject | Remove-VM -force
 `))
 
 func (c *ClientConfig) DeleteVm(ctx context.Context, name string) (err error) {
 	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmTemplate, deleteVmArgs{
-		Name: name,
+		Name:                           name,
+		DeletionProtectionKvpItemName:  api.DeletionProtectionKvpItemName,
+		DeletionProtectionKvpItemValue: api.DeletionProtectionKvpItemValue,
 	})
 
 	return err
@@ -0,0 +1,244 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// vmAffinityRuleDirectory is where the vm affinity rules are recorded on the HyperV host machine, so that a rule can be
+// read back by name whether it is enforced by a failover cluster or by the provider
+const vmAffinityRuleDirectory = `$vmAffinityRuleDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\vm-affinity-rules'`
+
+// vmAffinityRuleClusterGroups looks up the clustered virtual machine roles of the cluster of a rule by the name of
+// their virtual machine
+const vmAffinityRuleClusterGroups = `
+Import-Module FailoverClusters
+$clusterGroupsByVmName = @{}
+Get-ClusterGroup -Cluster $vmAffinityRule.ClusterName | ?{ "$($_.GroupType)" -eq 'VirtualMachine' } | %{
+	$clusterGroup = $_
+	$clusterGroup | Get-VM -ErrorAction SilentlyContinue | %{ $clusterGroupsByVmName[$_.Name] = $clusterGroup }
+}
+`
+
+type createOrUpdateVmAffinityRuleArgs struct {
+	VmAffinityRuleJson string
+}
+
+var createOrUpdateVmAffinityRuleTemplate = template.Must(template.New("CreateOrUpdateVmAffinityRule").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmAffinityRule = '{{.VmAffinityRuleJson}}' | ConvertFrom-Json
+$vmNames = @($vmAffinityRule.VmNames | ?{ $_ })
+` + vmAffinityRuleDirectory + `
+
+if ($vmAffinityRule.ClusterName) {
+` + vmAffinityRuleClusterGroups + `
+	$missingVmNames = @($vmNames | ?{ !$clusterGroupsByVmName.ContainsKey($_) })
+	if ($missingVmNames) {
+		throw "Vms $($missingVmNames -join ', ') are not clustered virtual machine roles in cluster $($vmAffinityRule.ClusterName)"
+	}
+
+	if ($vmAffinityRule.RuleType -eq 'AntiAffinity') {
+		#the cluster avoids placing cluster groups that share an anti-affinity class name on the same node
+		foreach ($vmName in @($clusterGroupsByVmName.Keys)) {
+			$clusterGroup = $clusterGroupsByVmName[$vmName]
+			$currentAntiAffinityClassNames = @($clusterGroup.AntiAffinityClassNames | ?{ $_ })
+			$antiAffinityClassNames = @($currentAntiAffinityClassNames | ?{ $_ -ne $vmAffinityRule.Name })
+			if ($vmNames -contains $vmName) {
+				$antiAffinityClassNames += $vmAffinityRule.Name
+			}
+
+			if ($antiAffinityClassNames.Count -ne $currentAntiAffinityClassNames.Count) {
+				$antiAffinityClassNameCollection = New-Object System.Collections.Specialized.StringCollection
+				$antiAffinityClassNames | %{ $antiAffinityClassNameCollection.Add($_) | Out-Null }
+				$clusterGroup.AntiAffinityClassNames = $antiAffinityClassNameCollection
+			}
+		}
+	} else {
+		$clusterAffinityRule = Get-ClusterAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name -ErrorAction SilentlyContinue
+		if (!$clusterAffinityRule) {
+			New-ClusterAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name -RuleType SameNode | Out-Null
+			$clusterAffinityRule = Get-ClusterAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name
+		}
+
+		$groupNames = @($vmNames | %{ $clusterGroupsByVmName[$_].Name })
+		$currentGroupNames = @($clusterAffinityRule.Groups | ?{ $_ })
+
+		$removedGroupNames = @($currentGroupNames | ?{ $groupNames -notcontains $_ })
+		if ($removedGroupNames) {
+			Remove-ClusterGroupFromAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name -Groups $removedGroupNames | Out-Null
+		}
+
+		$addedGroupNames = @($groupNames | ?{ $currentGroupNames -notcontains $_ })
+		if ($addedGroupNames) {
+			Add-ClusterGroupToAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name -Groups $addedGroupNames | Out-Null
+		}
+	}
+} else {
+	#a standalone host can not move virtual machines, so refuse a placement that breaks the rule
+	$vmNamesOnHost = @($vmNames | ?{ Get-VM -Name $_ -ErrorAction SilentlyContinue })
+	if ($vmAffinityRule.RuleType -eq 'AntiAffinity') {
+		if ($vmNamesOnHost.Count -gt 1) {
+			throw "Anti-affinity rule $($vmAffinityRule.Name) refuses to place vms $($vmNamesOnHost -join ', ') on the same host $env:COMPUTERNAME"
+		}
+	} else {
+		$vmNamesNotOnHost = @($vmNames | ?{ $vmNamesOnHost -notcontains $_ })
+		if ($vmNamesNotOnHost) {
+			throw "Affinity rule $($vmAffinityRule.Name) requires vms $($vmNamesNotOnHost -join ', ') to be on the host $env:COMPUTERNAME"
+		}
+	}
+}
+
+if (!(Test-Path -LiteralPath $vmAffinityRuleDirectory -PathType Container)) {
+	New-Item -ItemType Directory -Path $vmAffinityRuleDirectory | Out-Null
+}
+
+$vmAffinityRuleObject = @{
+	Name=$vmAffinityRule.Name;
+	RuleType=$vmAffinityRule.RuleType;
+	ClusterName=$vmAffinityRule.ClusterName;
+	VmNames=$vmNames;
+}
+
+ConvertTo-Json -InputObject $vmAffinityRuleObject | Set-Content -LiteralPath (Join-Path $vmAffinityRuleDirectory "$($vmAffinityRule.RuleType)-$($vmAffinityRule.Name).json") -Encoding UTF8
+`))
+
+func (c *ClientConfig) CreateOrUpdateVmAffinityRule(ctx context.Context, name string, ruleType api.VmAffinityRuleType, clusterName string, vmNames []string) (err error) {
+	vmAffinityRuleJson, err := json.Marshal(&api.VmAffinityRule{
+		Name:        name,
+		RuleType:    ruleType,
+		ClusterName: clusterName,
+		VmNames:     vmNames,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVmAffinityRuleTemplate, createOrUpdateVmAffinityRuleArgs{
+		VmAffinityRuleJson: string(vmAffinityRuleJson),
+	})
+
+	return err
+}
+
+type getVmAffinityRuleArgs struct {
+	Name     string
+	RuleType string
+}
+
+var getVmAffinityRuleTemplate = template.Must(template.New("GetVmAffinityRule").Parse(`
+$ErrorActionPreference = 'Stop'
+` + vmAffinityRuleDirectory + `
+$vmAffinityRulePath = Join-Path $vmAffinityRuleDirectory '{{.RuleType}}-{{.Name}}.json'
+
+if (!(Test-Path -LiteralPath $vmAffinityRulePath -PathType Leaf)) {
+	"{}"
+	return
+}
+
+$vmAffinityRule = Get-Content -LiteralPath $vmAffinityRulePath -Raw | ConvertFrom-Json
+
+if ($vmAffinityRule.ClusterName) {
+` + vmAffinityRuleClusterGroups + `
+	#read the members back from the cluster, so that roles removed from the rule outside of Terraform show as drift
+	if ($vmAffinityRule.RuleType -eq 'AntiAffinity') {
+		$vmNames = @($clusterGroupsByVmName.Keys | ?{ @($clusterGroupsByVmName[$_].AntiAffinityClassNames) -contains $vmAffinityRule.Name })
+	} else {
+		$clusterAffinityRule = Get-ClusterAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name -ErrorAction SilentlyContinue
+		$groupNames = @($clusterAffinityRule.Groups | ?{ $_ })
+		$vmNames = @($clusterGroupsByVmName.Keys | ?{ $groupNames -contains $clusterGroupsByVmName[$_].Name })
+	}
+
+	$placements = @($vmNames | %{
+		@{
+			VmName=$_;
+			Host="$($clusterGroupsByVmName[$_].OwnerNode.Name)";
+		}
+	})
+} else {
+	$vmNames = @($vmAffinityRule.VmNames | ?{ $_ })
+
+	$placements = @($vmNames | %{
+		$vmHost = ""
+		$vmObject = Get-VM -Name $_ -ErrorAction SilentlyContinue
+		if ($vmObject) {
+			$vmHost = $vmObject.ComputerName
+		}
+
+		@{
+			VmName=$_;
+			Host=$vmHost;
+		}
+	})
+}
+
+$vmAffinityRuleObject = @{
+	Name=$vmAffinityRule.Name;
+	RuleType=$vmAffinityRule.RuleType;
+	ClusterName="$($vmAffinityRule.ClusterName)";
+	VmNames=$vmNames;
+	Placements=$placements;
+}
+
+$vmAffinityRule = ConvertTo-Json -Depth 3 -InputObject $vmAffinityRuleObject
+$vmAffinityRule
+`))
+
+func (c *ClientConfig) GetVmAffinityRule(ctx context.Context, name string, ruleType api.VmAffinityRuleType) (result api.VmAffinityRule, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmAffinityRuleTemplate, getVmAffinityRuleArgs{
+		Name:     name,
+		RuleType: ruleType.String(),
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmAffinityRuleArgs struct {
+	Name     string
+	RuleType string
+}
+
+var deleteVmAffinityRuleTemplate = template.Must(template.New("DeleteVmAffinityRule").Parse(`
+$ErrorActionPreference = 'Stop'
+` + vmAffinityRuleDirectory + `
+$vmAffinityRulePath = Join-Path $vmAffinityRuleDirectory '{{.RuleType}}-{{.Name}}.json'
+
+if (!(Test-Path -LiteralPath $vmAffinityRulePath -PathType Leaf)) {
+	return
+}
+
+$vmAffinityRule = Get-Content -LiteralPath $vmAffinityRulePath -Raw | ConvertFrom-Json
+
+if ($vmAffinityRule.ClusterName) {
+	if ($vmAffinityRule.RuleType -eq 'AntiAffinity') {
+` + vmAffinityRuleClusterGroups + `
+		foreach ($clusterGroup in @($clusterGroupsByVmName.Values)) {
+			$antiAffinityClassNames = @($clusterGroup.AntiAffinityClassNames | ?{ $_ })
+			if ($antiAffinityClassNames -contains $vmAffinityRule.Name) {
+				$antiAffinityClassNameCollection = New-Object System.Collections.Specialized.StringCollection
+				$antiAffinityClassNames | ?{ $_ -ne $vmAffinityRule.Name } | %{ $antiAffinityClassNameCollection.Add($_) | Out-Null }
+				$clusterGroup.AntiAffinityClassNames = $antiAffinityClassNameCollection
+			}
+		}
+	} else {
+		Import-Module FailoverClusters
+		Get-ClusterAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name -ErrorAction SilentlyContinue | %{
+			Remove-ClusterAffinityRule -Cluster $vmAffinityRule.ClusterName -Name $vmAffinityRule.Name | Out-Null
+		}
+	}
+}
+
+Remove-Item -LiteralPath $vmAffinityRulePath -Force
+`))
+
+func (c *ClientConfig) DeleteVmAffinityRule(ctx context.Context, name string, ruleType api.VmAffinityRuleType) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmAffinityRuleTemplate, deleteVmAffinityRuleArgs{
+		Name:     name,
+		RuleType: ruleType.String(),
+	})
+
+	return err
+}
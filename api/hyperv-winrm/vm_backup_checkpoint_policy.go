@@ -0,0 +1,117 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// getVmReferencePoints reads the resilient change tracking reference points of $vmObject into $referencePoints, oldest
+// first
+const getVmReferencePoints = `
+$referencePoints = @(Get-CimInstance -Namespace 'root\virtualization\v2' -ClassName 'Msvm_VirtualSystemReferencePoint' -Filter "VirtualSystemIdentifier='$($vmObject.Id)'" | Sort-Object -Property CreationTime)
+`
+
+type createOrUpdateVmBackupCheckpointPolicyArgs struct {
+	VmBackupCheckpointPolicyJson string
+	BackupIntegrationServiceId   string
+}
+
+var createOrUpdateVmBackupCheckpointPolicyTemplate = template.Must(template.New("CreateOrUpdateVmBackupCheckpointPolicy").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmBackupCheckpointPolicy = '{{.VmBackupCheckpointPolicyJson}}' | ConvertFrom-Json
+$vmObject = Get-VM -Name "$($vmBackupCheckpointPolicy.VmName)*" | ?{$_.Name -eq $vmBackupCheckpointPolicy.VmName}
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmBackupCheckpointPolicy.VmName)"
+}
+
+$backupIntegrationService = Get-VMIntegrationService -VM $vmObject | ?{ $_.Id -like '*\{{.BackupIntegrationServiceId}}' }
+if ($vmBackupCheckpointPolicy.BackupIntegrationEnabled) {
+	$backupIntegrationService | ?{ !$_.Enabled } | Enable-VMIntegrationService
+} else {
+	$backupIntegrationService | ?{ $_.Enabled } | Disable-VMIntegrationService
+}
+
+Set-VM -VM $vmObject -CheckpointType ([Microsoft.HyperV.PowerShell.CheckpointType]$vmBackupCheckpointPolicy.CheckpointType) -AutomaticCheckpointsEnabled $vmBackupCheckpointPolicy.AutomaticCheckpointsEnabled
+
+if ($vmBackupCheckpointPolicy.ReferencePointRetention -gt 0) {
+` + getVmReferencePoints + `
+	$referencePointService = Get-CimInstance -Namespace 'root\virtualization\v2' -ClassName 'Msvm_VirtualSystemReferencePointService'
+	$referencePoints | Select-Object -First ([Math]::Max(0, $referencePoints.Count - $vmBackupCheckpointPolicy.ReferencePointRetention)) | %{
+		$result = Invoke-CimMethod -InputObject $referencePointService -MethodName DestroyReferencePoint -Arguments @{ AffectedReferencePoint=$_ }
+
+		#4096 means the reference point is removed by a job that has to be waited for
+		if ($result.ReturnValue -eq 4096) {
+			$job = Get-CimInstance -InputObject $result.Job
+			while (@(2, 3, 4) -contains $job.JobState) {
+				Start-Sleep -Milliseconds 500
+				$job = Get-CimInstance -InputObject $job
+			}
+
+			if ($job.JobState -ne 7) {
+				throw "Unable to remove reference point $($_.InstanceID) of VM $($vmBackupCheckpointPolicy.VmName) - $($job.ErrorDescription)"
+			}
+		} elseif ($result.ReturnValue -ne 0) {
+			throw "Unable to remove reference point $($_.InstanceID) of VM $($vmBackupCheckpointPolicy.VmName) - error $($result.ReturnValue)"
+		}
+	}
+}
+`))
+
+func (c *ClientConfig) CreateOrUpdateVmBackupCheckpointPolicy(ctx context.Context, vmBackupCheckpointPolicy api.VmBackupCheckpointPolicy) (err error) {
+	vmBackupCheckpointPolicyJson, err := json.Marshal(&vmBackupCheckpointPolicy)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVmBackupCheckpointPolicyTemplate, createOrUpdateVmBackupCheckpointPolicyArgs{
+		VmBackupCheckpointPolicyJson: string(vmBackupCheckpointPolicyJson),
+		BackupIntegrationServiceId:   api.VmBackupIntegrationServiceId,
+	})
+
+	return err
+}
+
+type getVmBackupCheckpointPolicyArgs struct {
+	VmName                     string
+	BackupIntegrationServiceId string
+}
+
+var getVmBackupCheckpointPolicyTemplate = template.Must(template.New("GetVmBackupCheckpointPolicy").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmObject = Get-VM -Name '{{.VmName}}*' -ErrorAction SilentlyContinue | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	"{}"
+	return
+}
+
+$backupIntegrationService = Get-VMIntegrationService -VM $vmObject | ?{ $_.Id -like '*\{{.BackupIntegrationServiceId}}' }
+` + getVmReferencePoints + `
+$vmBackupCheckpointPolicyObject = @{
+	VmName=$vmObject.Name;
+	CheckpointType=$vmObject.CheckpointType;
+	AutomaticCheckpointsEnabled=[bool]$vmObject.AutomaticCheckpointsEnabled;
+	BackupIntegrationEnabled=[bool]$backupIntegrationService.Enabled;
+	BackupIntegrationStatus="$($backupIntegrationService.PrimaryStatusDescription)";
+	ReferencePointCount=$referencePoints.Count;
+}
+
+$vmBackupCheckpointPolicy = ConvertTo-Json -InputObject $vmBackupCheckpointPolicyObject
+$vmBackupCheckpointPolicy
+`))
+
+func (c *ClientConfig) GetVmBackupCheckpointPolicy(ctx context.Context, vmName string) (result api.VmBackupCheckpointPolicy, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmBackupCheckpointPolicyTemplate, getVmBackupCheckpointPolicyArgs{
+		VmName:                     vmName,
+		BackupIntegrationServiceId: api.VmBackupIntegrationServiceId,
+	}, &result)
+
+	return result, err
+}
@@ -0,0 +1,109 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createVmCheckpointArgs struct {
+	VmName string
+	Name   string
+}
+
+var createVmCheckpointTemplate = template.Must(template.New("CreateVmCheckpoint").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+
+if ("$($vmObject.CheckpointType)" -eq 'Disabled') {
+	throw "Checkpoints are disabled for vm {{.VmName}}"
+}
+
+$vmCheckpoint = Checkpoint-VM -VM $vmObject -SnapshotName '{{.Name}}' -Passthru
+
+$vmCheckpointObject = @{
+	VmName=$vmCheckpoint.VMName;
+	Name=$vmCheckpoint.Name;
+	Id="$($vmCheckpoint.Id)";
+	CheckpointType="$($vmCheckpoint.SnapshotType)";
+	CreationTime=$vmCheckpoint.CreationTime.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ');
+	ParentCheckpointName="$($vmCheckpoint.ParentSnapshotName)";
+	ParentCheckpointId="$($vmCheckpoint.ParentSnapshotId)";
+}
+
+$vmCheckpoint = ConvertTo-Json -InputObject $vmCheckpointObject
+$vmCheckpoint
+`))
+
+func (c *ClientConfig) CreateVmCheckpoint(ctx context.Context, vmName string, name string) (result api.VmCheckpoint, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, createVmCheckpointTemplate, createVmCheckpointArgs{
+		VmName: vmName,
+		Name:   name,
+	}, &result)
+
+	return result, err
+}
+
+type getVmCheckpointsArgs struct {
+	VmName string
+}
+
+var getVmCheckpointsTemplate = template.Must(template.New("GetVmCheckpoints").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+
+$vmCheckpointsObject = @($vmObject | Get-VMSnapshot | Sort-Object -Property CreationTime | %{ @{
+	VmName=$_.VMName;
+	Name=$_.Name;
+	Id="$($_.Id)";
+	CheckpointType="$($_.SnapshotType)";
+	CreationTime=$_.CreationTime.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ');
+	ParentCheckpointName="$($_.ParentSnapshotName)";
+	ParentCheckpointId="$($_.ParentSnapshotId)";
+}})
+
+if ($vmCheckpointsObject) {
+	$vmCheckpoints = ConvertTo-Json -InputObject $vmCheckpointsObject
+	$vmCheckpoints
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetVmCheckpoints(ctx context.Context, vmName string) (result []api.VmCheckpoint, err error) {
+	result = make([]api.VmCheckpoint, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmCheckpointsTemplate, getVmCheckpointsArgs{
+		VmName: vmName,
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmCheckpointArgs struct {
+	VmName       string
+	CheckpointId string
+}
+
+var deleteVmCheckpointTemplate = template.Must(template.New("DeleteVmCheckpoint").Parse(`
+$ErrorActionPreference = 'Stop'
+Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' } | Get-VMSnapshot | ?{ "$($_.Id)" -eq '{{.CheckpointId}}' } | Remove-VMSnapshot
+`))
+
+func (c *ClientConfig) DeleteVmCheckpoint(ctx context.Context, vmName string, checkpointId string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmCheckpointTemplate, deleteVmCheckpointArgs{
+		VmName:       vmName,
+		CheckpointId: checkpointId,
+	})
+
+	return err
+}
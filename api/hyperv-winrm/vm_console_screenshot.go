@@ -0,0 +1,89 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getVmConsoleScreenshotArgs struct {
+	VmName string
+	Width  int
+	Height int
+}
+
+var getVmConsoleScreenshotTemplate = template.Must(template.New("GetVmConsoleScreenshot").Parse(`
+$ErrorActionPreference = 'Stop'
+Add-Type -AssemblyName System.Drawing
+$vmName = '{{.VmName}}'
+$width = {{.Width}}
+$height = {{.Height}}
+
+$vmObject = Get-VM -Name "$($vmName)*" | ?{$_.Name -eq $vmName}
+if (!$vmObject) {
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmName)"
+}
+
+$computerSystem = Get-CimInstance -Namespace root\virtualization\v2 -ClassName Msvm_ComputerSystem -Filter "Name='$($vmObject.Id)'"
+
+if ($width -eq 0 -or $height -eq 0) {
+	# the video head only exists while the vm is running and reports the resolution the guest is using
+	$videoHead = Get-CimAssociatedInstance -InputObject $computerSystem -ResultClassName Msvm_VideoHead | Select-Object -First 1
+	if (!$videoHead) {
+		throw "Unable to capture the console of VM $($vmName) as it has no video head, the vm must be running"
+	}
+	$width = [int]$videoHead.CurrentHorizontalResolution
+	$height = [int]$videoHead.CurrentVerticalResolution
+}
+
+$managementService = Get-CimInstance -Namespace root\virtualization\v2 -ClassName Msvm_VirtualSystemManagementService
+$settingData = Get-CimAssociatedInstance -InputObject $computerSystem -ResultClassName Msvm_VirtualSystemSettingData | ?{ $_.VirtualSystemType -eq 'Microsoft:Hyper-V:System:Realized' } | Select-Object -First 1
+
+$thumbnail = Invoke-CimMethod -InputObject $managementService -MethodName GetVirtualSystemThumbnailImage -Arguments @{
+	TargetSystem=$settingData;
+	WidthPixels=[uint16]$width;
+	HeightPixels=[uint16]$height;
+}
+if ($thumbnail.ReturnValue -ne 0) {
+	throw "Unable to capture the console of VM $($vmName), GetVirtualSystemThumbnailImage returned $($thumbnail.ReturnValue)"
+}
+
+# the image is returned as raw RGB565 pixels, rows are copied one at a time as the stride of a bitmap is padded
+$imageData = [byte[]]$thumbnail.ImageData
+$bitmap = New-Object System.Drawing.Bitmap -ArgumentList $width, $height, ([System.Drawing.Imaging.PixelFormat]::Format16bppRgb565)
+try {
+	$bitmapData = $bitmap.LockBits((New-Object System.Drawing.Rectangle -ArgumentList 0, 0, $width, $height), [System.Drawing.Imaging.ImageLockMode]::WriteOnly, $bitmap.PixelFormat)
+	for ($row = 0; $row -lt $height; $row++) {
+		[System.Runtime.InteropServices.Marshal]::Copy($imageData, $row * $width * 2, [IntPtr]($bitmapData.Scan0.ToInt64() + $row * $bitmapData.Stride), $width * 2)
+	}
+	$bitmap.UnlockBits($bitmapData)
+
+	$stream = New-Object System.IO.MemoryStream
+	$bitmap.Save($stream, [System.Drawing.Imaging.ImageFormat]::Png)
+	$png = [System.Convert]::ToBase64String($stream.ToArray())
+	$stream.Dispose()
+} finally {
+	$bitmap.Dispose()
+}
+
+$vmConsoleScreenshotObject = @{
+	VmName=$vmObject.Name;
+	Width=$width;
+	Height=$height;
+	Png=$png;
+}
+
+$vmConsoleScreenshot = ConvertTo-Json -InputObject $vmConsoleScreenshotObject
+$vmConsoleScreenshot
+`))
+
+func (c *ClientConfig) GetVmConsoleScreenshot(ctx context.Context, vmName string, width int, height int) (result api.VmConsoleScreenshot, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmConsoleScreenshotTemplate, getVmConsoleScreenshotArgs{
+		VmName: vmName,
+		Width:  width,
+		Height: height,
+	}, &result)
+
+	return result, err
+}
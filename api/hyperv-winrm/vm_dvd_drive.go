@@ -106,7 +106,7 @@ $vmDvdDrive = '{{.VmDvdDriveJson}}' | ConvertFrom-Json
 $vmDvdDrivesObject = @(Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' } | Get-VMDvdDrive -ControllerLocation {{.ControllerLocation}} -ControllerNumber {{.ControllerNumber}} )
 
 if (!$vmDvdDrivesObject){
-	throw "VM dvd drive does not exist - {{.ControllerLocation}} {{.ControllerNumber}}"
+	throw [System.Management.Automation.ItemNotFoundException]"VM dvd drive does not exist - {{.ControllerLocation}} {{.ControllerNumber}}"
 }
 
 $SetVmDvdDriveArgs = @{}
@@ -193,10 +193,9 @@ func (c *ClientConfig) CreateOrUpdateVmDvdDrives(ctx context.Context, vmName str
 		return err
 	}
 
-	currentDvdDrivesLength := len(currentDvdDrives)
-	desiredDvdDrivesLength := len(dvdDrives)
+	matches := api.MatchDvdDrives(currentDvdDrives, dvdDrives)
 
-	for i := currentDvdDrivesLength - 1; i > desiredDvdDrivesLength-1; i-- {
+	for _, i := range api.UnmatchedDevices(len(currentDvdDrives), matches) {
 		currentDvdDrive := currentDvdDrives[i]
 		err = c.DeleteVmDvdDrive(ctx, vmName, currentDvdDrive.ControllerNumber, currentDvdDrive.ControllerLocation)
 		if err != nil {
@@ -204,13 +203,12 @@ func (c *ClientConfig) CreateOrUpdateVmDvdDrives(ctx context.Context, vmName str
 		}
 	}
 
-	if currentDvdDrivesLength > desiredDvdDrivesLength {
-		currentDvdDrivesLength = desiredDvdDrivesLength
-	}
+	for i, dvdDrive := range dvdDrives {
+		if matches[i] == -1 {
+			continue
+		}
 
-	for i := 0; i <= currentDvdDrivesLength-1; i++ {
-		currentDvdDrive := currentDvdDrives[i]
-		dvdDrive := dvdDrives[i]
+		currentDvdDrive := currentDvdDrives[matches[i]]
 
 		err = c.UpdateVmDvdDrive(
 			ctx,
@@ -227,8 +225,11 @@ func (c *ClientConfig) CreateOrUpdateVmDvdDrives(ctx context.Context, vmName str
 		}
 	}
 
-	for i := currentDvdDrivesLength - 1 + 1; i <= desiredDvdDrivesLength-1; i++ {
-		dvdDrive := dvdDrives[i]
+	for i, dvdDrive := range dvdDrives {
+		if matches[i] != -1 {
+			continue
+		}
+
 		err = c.CreateVmDvdDrive(
 			ctx,
 			vmName,
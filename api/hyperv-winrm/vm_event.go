@@ -0,0 +1,106 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getVmEventsArgs struct {
+	VmEventQueryJson string
+}
+
+var getVmEventsTemplate = template.Must(template.New("GetVmEvents").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmEventQuery = '{{.VmEventQueryJson}}' | ConvertFrom-Json
+$vmNames = @($vmEventQuery.VmNames | ?{ $_ })
+
+$vmNamesById = @{}
+Get-VM | ?{ !$vmNames -or ($vmNames -contains $_.Name) } | %{ $vmNamesById["$($_.Id)"] = $_.Name }
+
+$missingVmNames = @($vmNames | ?{ @($vmNamesById.Values) -notcontains $_ })
+if ($missingVmNames) {
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($missingVmNames -join ', ')"
+}
+
+$filter = @{
+	LogName=@($vmEventQuery.LogNames);
+	Level=@($vmEventQuery.Levels);
+	StartTime=(Get-Date).AddMinutes(-$vmEventQuery.SinceMinutes);
+}
+
+#Get-WinEvent reports an error when no events match the filter
+$events = @(Get-WinEvent -FilterHashtable $filter -ErrorAction SilentlyContinue -ErrorVariable eventErrors)
+$eventErrors | ?{ $_.FullyQualifiedErrorId -notlike 'NoMatchingEventsFound*' } | select -First 1 | %{ throw $_ }
+
+$vmEventsObject = @($events | %{
+	$winEvent = $_
+
+	#the worker process and the management service name the virtual machine in the user data of an event, older
+	#events only mention its id in the message
+	$eventXml = [xml]$winEvent.ToXml()
+	$vmId = "$($eventXml.SelectSingleNode("//*[local-name()='VmId']").InnerText)".Trim('{', '}')
+	if (!$vmId -and $winEvent.Message) {
+		$vmId = @($vmNamesById.Keys | ?{ $winEvent.Message.IndexOf($_, [System.StringComparison]::OrdinalIgnoreCase) -ge 0 }) | select -First 1
+	}
+
+	$vmName = ''
+	if ($vmId) {
+		$vmName = $vmNamesById[$vmId]
+	}
+
+	if ($vmName) {
+		@{
+			VmName=$vmName;
+			VmId=$vmId;
+			LogName=$winEvent.LogName;
+			ProviderName=$winEvent.ProviderName;
+			EventId=$winEvent.Id;
+			Level=[int]$winEvent.Level;
+			TimeCreated=$winEvent.TimeCreated.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ');
+			Message="$($winEvent.Message)";
+		}
+	}
+} | select -First $vmEventQuery.MaxEvents)
+
+if ($vmEventsObject) {
+	$vmEvents = ConvertTo-Json -InputObject $vmEventsObject
+	$vmEvents
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetVmEvents(ctx context.Context, query api.VmEventQuery) (result []api.VmEvent, err error) {
+	logNames := query.LogNames
+	if len(logNames) == 0 {
+		logNames = api.DefaultVmEventLogNames
+	}
+
+	vmEventQueryJson, err := json.Marshal(struct {
+		VmNames      []string
+		LogNames     []string
+		Levels       []int
+		SinceMinutes int32
+		MaxEvents    int32
+	}{
+		VmNames:      query.VmNames,
+		LogNames:     logNames,
+		Levels:       query.Level.Levels(),
+		SinceMinutes: query.SinceMinutes,
+		MaxEvents:    query.MaxEvents,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmEventsTemplate, getVmEventsArgs{
+		VmEventQueryJson: string(vmEventQueryJson),
+	}, &result)
+
+	return result, err
+}
@@ -186,3 +186,31 @@ func (c *ClientConfig) CreateOrUpdateVmFirmwares(ctx context.Context, vmName str
 		vmFirmware.PauseAfterBootFailure,
 	)
 }
+
+type getSecureBootTemplatesArgs struct {
+}
+
+var getSecureBootTemplatesTemplate = template.Must(template.New("GetSecureBootTemplates").Parse(`
+$ErrorActionPreference = 'Stop'
+
+$secureBootTemplatesObject = @(Get-CimInstance -Namespace 'root\virtualization\v2' -ClassName 'Msvm_SecureBootTemplate' | Sort-Object -Property ElementName | %{ @{
+	Name=$_.ElementName;
+	Id="$($_.InstanceID)";
+	Description="$($_.Description)";
+}})
+
+if ($secureBootTemplatesObject) {
+	$secureBootTemplates = ConvertTo-Json -InputObject $secureBootTemplatesObject
+	$secureBootTemplates
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetSecureBootTemplates(ctx context.Context) (result []api.SecureBootTemplate, err error) {
+	result = make([]api.SecureBootTemplate, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getSecureBootTemplatesTemplate, getSecureBootTemplatesArgs{}, &result)
+
+	return result, err
+}
@@ -0,0 +1,181 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createVmGroupArgs struct {
+	VmGroupJson string
+}
+
+var createVmGroupTemplate = template.Must(template.New("CreateVmGroup").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmGroup = '{{.VmGroupJson}}' | ConvertFrom-Json
+
+$vmGroupObject = New-VMGroup -Name $vmGroup.Name -GroupType $vmGroup.GroupType
+
+foreach ($vmName in @($vmGroup.VmMembers)) {
+	$vmObject = Get-VM -Name "$vmName*" | ?{$_.Name -eq $vmName }
+	if (!$vmObject){
+		throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $vmName"
+	}
+	Add-VMGroupMember -VMGroup $vmGroupObject -VM $vmObject
+}
+
+foreach ($vmGroupName in @($vmGroup.VmGroupMembers)) {
+	$vmGroupMemberObject = Get-VMGroup -Name $vmGroupName -ErrorAction SilentlyContinue | Select -First 1
+	if (!$vmGroupMemberObject){
+		throw [System.Management.Automation.ItemNotFoundException]"VM group does not exist - $vmGroupName"
+	}
+	Add-VMGroupMember -VMGroup $vmGroupObject -VMGroupMember $vmGroupMemberObject
+}
+`))
+
+func (c *ClientConfig) CreateVmGroup(ctx context.Context, name string, groupType api.VMGroupType, vmMembers []string, vmGroupMembers []string) (err error) {
+	vmGroupJson, err := json.Marshal(struct {
+		Name           string
+		GroupType      string
+		VmMembers      []string
+		VmGroupMembers []string
+	}{
+		Name:           name,
+		GroupType:      groupType.String(),
+		VmMembers:      vmMembers,
+		VmGroupMembers: vmGroupMembers,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVmGroupTemplate, createVmGroupArgs{
+		VmGroupJson: string(vmGroupJson),
+	})
+
+	return err
+}
+
+type getVmGroupArgs struct {
+	Name string
+}
+
+var getVmGroupTemplate = template.Must(template.New("GetVmGroup").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmGroupObject = Get-VMGroup -Name '{{.Name}}' -ErrorAction SilentlyContinue | Select -First 1 | %{ @{
+	Name=$_.Name;
+	Id="$($_.InstanceId)";
+	GroupType="$($_.GroupType)";
+	VmMembers=@($_.VMMembers | %{ $_.Name });
+	VmGroupMembers=@($_.VMGroupMembers | %{ $_.Name });
+}}
+
+if ($vmGroupObject){
+	$vmGroup = ConvertTo-Json -InputObject $vmGroupObject
+	$vmGroup
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVmGroup(ctx context.Context, name string) (result api.VmGroup, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmGroupTemplate, getVmGroupArgs{
+		Name: name,
+	}, &result)
+
+	return result, err
+}
+
+type updateVmGroupArgs struct {
+	VmGroupJson string
+}
+
+var updateVmGroupTemplate = template.Must(template.New("UpdateVmGroup").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmGroup = '{{.VmGroupJson}}' | ConvertFrom-Json
+$vmMembers = @($vmGroup.VmMembers)
+$vmGroupMembers = @($vmGroup.VmGroupMembers)
+
+$vmGroupObject = Get-VMGroup -Name $vmGroup.Name -ErrorAction SilentlyContinue | Select -First 1
+if (!$vmGroupObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM group does not exist - $($vmGroup.Name)"
+}
+
+foreach ($vmObject in @($vmGroupObject.VMMembers)) {
+	if ($vmMembers -notcontains $vmObject.Name) {
+		Remove-VMGroupMember -VMGroup $vmGroupObject -VM $vmObject
+	}
+}
+
+foreach ($vmName in $vmMembers) {
+	if (!(@($vmGroupObject.VMMembers) | ?{ $_.Name -eq $vmName })) {
+		$vmObject = Get-VM -Name "$vmName*" | ?{$_.Name -eq $vmName }
+		if (!$vmObject){
+			throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $vmName"
+		}
+		Add-VMGroupMember -VMGroup $vmGroupObject -VM $vmObject
+	}
+}
+
+foreach ($vmGroupMemberObject in @($vmGroupObject.VMGroupMembers)) {
+	if ($vmGroupMembers -notcontains $vmGroupMemberObject.Name) {
+		Remove-VMGroupMember -VMGroup $vmGroupObject -VMGroupMember $vmGroupMemberObject
+	}
+}
+
+foreach ($vmGroupName in $vmGroupMembers) {
+	if (!(@($vmGroupObject.VMGroupMembers) | ?{ $_.Name -eq $vmGroupName })) {
+		$vmGroupMemberObject = Get-VMGroup -Name $vmGroupName -ErrorAction SilentlyContinue | Select -First 1
+		if (!$vmGroupMemberObject){
+			throw [System.Management.Automation.ItemNotFoundException]"VM group does not exist - $vmGroupName"
+		}
+		Add-VMGroupMember -VMGroup $vmGroupObject -VMGroupMember $vmGroupMemberObject
+	}
+}
+`))
+
+func (c *ClientConfig) UpdateVmGroup(ctx context.Context, name string, vmMembers []string, vmGroupMembers []string) (err error) {
+	vmGroupJson, err := json.Marshal(struct {
+		Name           string
+		VmMembers      []string
+		VmGroupMembers []string
+	}{
+		Name:           name,
+		VmMembers:      vmMembers,
+		VmGroupMembers: vmGroupMembers,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVmGroupTemplate, updateVmGroupArgs{
+		VmGroupJson: string(vmGroupJson),
+	})
+
+	return err
+}
+
+type deleteVmGroupArgs struct {
+	Name string
+}
+
+var deleteVmGroupTemplate = template.Must(template.New("DeleteVmGroup").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+#removing a group leaves its members as they are
+Get-VMGroup -Name '{{.Name}}' -ErrorAction SilentlyContinue | Remove-VMGroup -Force
+`))
+
+func (c *ClientConfig) DeleteVmGroup(ctx context.Context, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmGroupTemplate, deleteVmGroupArgs{
+		Name: name,
+	})
+
+	return err
+}
@@ -9,6 +9,7 @@ import (
 )
 
 type createVmHardDiskDriveArgs struct {
+	ControllerType      string
 	VmHardDiskDriveJson string
 }
 
@@ -16,6 +17,22 @@ var createVmHardDiskDriveTemplate = template.Must(template.New("CreateVmHardDisk
 $ErrorActionPreference = 'Stop'
 Import-Module Hyper-V
 $vmHardDiskDrive = '{{.VmHardDiskDriveJson}}' | ConvertFrom-Json
+$vmObject = Get-VM -Name "$($vmHardDiskDrive.VmName)*" | ?{$_.Name -eq $vmHardDiskDrive.VmName}
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmHardDiskDrive.VmName)"
+}
+
+#a running vm only takes hard disk drives on scsi controllers it already has, controllers can not be added while it runs
+if ($vmObject.State -ne 'Off') {
+	if ('{{.ControllerType}}' -ne 'Scsi') {
+		throw "VM $($vmObject.Name) must be off to add a hard disk drive to ide controller $($vmHardDiskDrive.ControllerNumber), it is $($vmObject.State)"
+	}
+
+	if (!(Get-VMScsiController -VM $vmObject -ControllerNumber $vmHardDiskDrive.ControllerNumber -ErrorAction SilentlyContinue)) {
+		throw "VM $($vmObject.Name) must be off to add a hard disk drive to scsi controller $($vmHardDiskDrive.ControllerNumber) as it does not have that controller, it is $($vmObject.State)"
+	}
+}
 
 $NewVmHardDiskDriveArgs = @{
 	VmName=$vmHardDiskDrive.VmName
@@ -75,6 +92,7 @@ func (c *ClientConfig) CreateVmHardDiskDrive(
 	}
 
 	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVmHardDiskDriveTemplate, createVmHardDiskDriveArgs{
+		ControllerType:      controllerType.String(),
 		VmHardDiskDriveJson: string(vmHardDiskDriveJson),
 	})
 
@@ -87,19 +105,29 @@ type getVmHardDiskDrivesArgs struct {
 
 var getVmHardDiskDrivesTemplate = template.Must(template.New("GetVmHardDiskDrives").Parse(`
 $ErrorActionPreference = 'Stop'
-$vmHardDiskDrivesObject = @(Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' } | Get-VMHardDiskDrive | %{ @{
-	ControllerType=$_.ControllerType;
-	ControllerNumber=$_.ControllerNumber;
-	ControllerLocation=$_.ControllerLocation;
-	Path=$_.Path;
-	DiskNumber=if ($_.DiskNumber -eq $null) { 4294967295 } else { $_.DiskNumber };
-	ResourcePoolName=$_.PoolName;
-	SupportPersistentReservations=$_.SupportPersistentReservations;
-	MaximumIops=$_.MaximumIops;
-	MinimumIops=$_.MinimumIops;
-	QosPolicyId=$_.QosPolicyId;	
-	OverrideCacheAttributes=$_.WriteHardeningMethod;
-}})
+$vmHardDiskDrivesObject = @(Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' } | Get-VMHardDiskDrive | %{
+	#a vm with checkpoints writes to automatic differencing disks, the disk that was attached is the first parent that
+	#is not one
+	$basePath = $_.Path
+	while ($basePath -and [System.IO.Path]::GetExtension($basePath) -match '^\.avhdx?$') {
+		$basePath = (Get-VHD -Path $basePath).ParentPath
+	}
+
+	@{
+		ControllerType=$_.ControllerType;
+		ControllerNumber=$_.ControllerNumber;
+		ControllerLocation=$_.ControllerLocation;
+		Path=$_.Path;
+		BasePath=$basePath;
+		DiskNumber=if ($_.DiskNumber -eq $null) { 4294967295 } else { $_.DiskNumber };
+		ResourcePoolName=$_.PoolName;
+		SupportPersistentReservations=$_.SupportPersistentReservations;
+		MaximumIops=$_.MaximumIops;
+		MinimumIops=$_.MinimumIops;
+		QosPolicyId=$_.QosPolicyId;	
+		OverrideCacheAttributes=$_.WriteHardeningMethod;
+	}
+})
 
 if ($vmHardDiskDrivesObject) {
 	$vmHardDiskDrives = ConvertTo-Json -InputObject $vmHardDiskDrivesObject
@@ -131,10 +159,10 @@ $ErrorActionPreference = 'Stop'
 Import-Module Hyper-V
 $vmHardDiskDrive = '{{.VmHardDiskDriveJson}}' | ConvertFrom-Json
 
-$vmHardDiskDrivesObject = @(Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' } | Get-VMHardDiskDrive -ControllerLocation {{.ControllerLocation}} -ControllerNumber {{.ControllerNumber}} )
+$vmHardDiskDrivesObject = @(Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' } | Get-VMHardDiskDrive -ControllerType $vmHardDiskDrive.ControllerType -ControllerLocation {{.ControllerLocation}} -ControllerNumber {{.ControllerNumber}} )
 
 if (!$vmHardDiskDrivesObject){
-	throw "VM hard disk drive does not exist - {{.ControllerLocation}} {{.ControllerNumber}}"
+	throw [System.Management.Automation.ItemNotFoundException]"VM hard disk drive does not exist - {{.ControllerLocation}} {{.ControllerNumber}}"
 }
 
 $SetVmHardDiskDriveArgs = @{}
@@ -144,7 +172,10 @@ $SetVmHardDiskDriveArgs.ControllerLocation=$vmHardDiskDrivesObject.ControllerLoc
 $SetVmHardDiskDriveArgs.ControllerNumber=$vmHardDiskDrivesObject.ControllerNumber
 $SetVmHardDiskDriveArgs.ToControllerLocation=$vmHardDiskDrive.ControllerLocation
 $SetVmHardDiskDriveArgs.ToControllerNumber=$vmHardDiskDrive.ControllerNumber
-$SetVmHardDiskDriveArgs.Path=$vmHardDiskDrive.Path
+#setting the path of a vm with checkpoints to the disk it is already attached to would drop its differencing disks
+if ($vmHardDiskDrive.Path -ne $vmHardDiskDrivesObject.Path) {
+	$SetVmHardDiskDriveArgs.Path=$vmHardDiskDrive.Path
+}
 if ($vmHardDiskDrive.DiskNumber -lt 4294967295){
 	$SetVmHardDiskDriveArgs.DiskNumber=$vmHardDiskDrive.DiskNumber
 }
@@ -214,19 +245,34 @@ func (c *ClientConfig) UpdateVmHardDiskDrive(
 
 type deleteVmHardDiskDriveArgs struct {
 	VmName             string
+	ControllerType     string
 	ControllerNumber   int32
 	ControllerLocation int32
 }
 
 var deleteVmHardDiskDriveTemplate = template.Must(template.New("DeleteVmHardDiskDrive").Parse(`
 $ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmObject = Get-VM -Name '{{.VmName}}*' -ErrorAction SilentlyContinue | ?{$_.Name -eq '{{.VmName}}' }
 
-@(Get-VMHardDiskDrive -VmName '{{.VmName}}' -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}}) | Remove-VMHardDiskDrive
+if (!$vmObject){
+	return
+}
+
+#ide and scsi controllers are numbered separately, so the controller type tells which hard disk drive to remove
+$vmHardDiskDrivesObject = @(Get-VMHardDiskDrive -VM $vmObject -ControllerType {{.ControllerType}} -ControllerNumber {{.ControllerNumber}} -ControllerLocation {{.ControllerLocation}})
+
+if ($vmHardDiskDrivesObject -and $vmObject.State -ne 'Off' -and '{{.ControllerType}}' -ne 'Scsi') {
+	throw "VM $($vmObject.Name) must be off to remove the hard disk drive from ide controller {{.ControllerNumber}}, it is $($vmObject.State)"
+}
+
+$vmHardDiskDrivesObject | Remove-VMHardDiskDrive
 `))
 
-func (c *ClientConfig) DeleteVmHardDiskDrive(ctx context.Context, vmname string, controllerNumber int32, controllerLocation int32) (err error) {
+func (c *ClientConfig) DeleteVmHardDiskDrive(ctx context.Context, vmname string, controllerType api.ControllerType, controllerNumber int32, controllerLocation int32) (err error) {
 	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmHardDiskDriveTemplate, deleteVmHardDiskDriveArgs{
 		VmName:             vmname,
+		ControllerType:     controllerType.String(),
 		ControllerNumber:   controllerNumber,
 		ControllerLocation: controllerLocation,
 	})
@@ -240,24 +286,27 @@ func (c *ClientConfig) CreateOrUpdateVmHardDiskDrives(ctx context.Context, vmNam
 		return err
 	}
 
-	currentHardDiskDrivesLength := len(currentHardDiskDrives)
-	desiredHardDiskDrivesLength := len(hardDiskDrives)
+	matches := api.MatchHardDiskDrives(currentHardDiskDrives, hardDiskDrives)
 
-	for i := currentHardDiskDrivesLength - 1; i > desiredHardDiskDrivesLength-1; i-- {
+	for _, i := range api.UnmatchedDevices(len(currentHardDiskDrives), matches) {
 		currentHardDiskDrive := currentHardDiskDrives[i]
-		err = c.DeleteVmHardDiskDrive(ctx, vmName, currentHardDiskDrive.ControllerNumber, currentHardDiskDrive.ControllerLocation)
+		err = c.DeleteVmHardDiskDrive(ctx, vmName, currentHardDiskDrive.ControllerType, currentHardDiskDrive.ControllerNumber, currentHardDiskDrive.ControllerLocation)
 		if err != nil {
 			return err
 		}
 	}
 
-	if currentHardDiskDrivesLength > desiredHardDiskDrivesLength {
-		currentHardDiskDrivesLength = desiredHardDiskDrivesLength
-	}
+	for i, hardDiskDrive := range hardDiskDrives {
+		if matches[i] == -1 {
+			continue
+		}
 
-	for i := 0; i <= currentHardDiskDrivesLength-1; i++ {
-		currentHardDiskDrive := currentHardDiskDrives[i]
-		hardDiskDrive := hardDiskDrives[i]
+		currentHardDiskDrive := currentHardDiskDrives[matches[i]]
+
+		// a hard disk drive that is left as it is can stay attached while hard disk drives are hot plugged next to it
+		if api.SameHardDiskDrive(currentHardDiskDrive, hardDiskDrive) {
+			continue
+		}
 
 		err = c.UpdateVmHardDiskDrive(
 			ctx,
@@ -267,7 +316,7 @@ func (c *ClientConfig) CreateOrUpdateVmHardDiskDrives(ctx context.Context, vmNam
 			hardDiskDrive.ControllerType,
 			hardDiskDrive.ControllerNumber,
 			hardDiskDrive.ControllerLocation,
-			hardDiskDrive.Path,
+			api.HardDiskDrivePathToSet(currentHardDiskDrive, hardDiskDrive),
 			hardDiskDrive.DiskNumber,
 			hardDiskDrive.ResourcePoolName,
 			hardDiskDrive.SupportPersistentReservations,
@@ -281,8 +330,11 @@ func (c *ClientConfig) CreateOrUpdateVmHardDiskDrives(ctx context.Context, vmNam
 		}
 	}
 
-	for i := currentHardDiskDrivesLength - 1 + 1; i <= desiredHardDiskDrivesLength-1; i++ {
-		hardDiskDrive := hardDiskDrives[i]
+	for i, hardDiskDrive := range hardDiskDrives {
+		if matches[i] != -1 {
+			continue
+		}
+
 		err = c.CreateVmHardDiskDrive(
 			ctx,
 			vmName,
@@ -0,0 +1,238 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getVMHostArgs struct {
+}
+
+var getVMHostTemplate = template.Must(template.New("GetVMHost").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmHostObject = Get-VMHost | %{ @{
+	ComputerName=$_.ComputerName;
+	VirtualHardDiskPath=$_.VirtualHardDiskPath;
+	VirtualMachinePath=$_.VirtualMachinePath;
+	NumaSpanningEnabled=$_.NumaSpanningEnabled;
+	EnableEnhancedSessionMode=$_.EnableEnhancedSessionMode;
+	ResourceMeteringSaveIntervalHours=[int]$_.ResourceMeteringSaveInterval.TotalHours;
+	MacAddressMinimum=$_.MacAddressMinimum;
+	MacAddressMaximum=$_.MacAddressMaximum;
+}}
+
+if ($vmHostObject){
+	$vmHost = ConvertTo-Json -InputObject $vmHostObject
+	$vmHost
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVMHost(ctx context.Context) (result api.VmHost, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMHostTemplate, getVMHostArgs{}, &result)
+
+	return result, err
+}
+
+type updateVMHostArgs struct {
+	VmHostJson string
+}
+
+var updateVMHostTemplate = template.Must(template.New("UpdateVMHost").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmHost = '{{.VmHostJson}}' | ConvertFrom-Json
+
+$SetVmHostArgs = @{}
+if ($vmHost.VirtualHardDiskPath) {
+	$SetVmHostArgs.VirtualHardDiskPath=$vmHost.VirtualHardDiskPath
+}
+if ($vmHost.VirtualMachinePath) {
+	$SetVmHostArgs.VirtualMachinePath=$vmHost.VirtualMachinePath
+}
+$SetVmHostArgs.NumaSpanningEnabled=$vmHost.NumaSpanningEnabled
+$SetVmHostArgs.EnableEnhancedSessionMode=$vmHost.EnableEnhancedSessionMode
+if ($vmHost.ResourceMeteringSaveIntervalHours -gt 0) {
+	$SetVmHostArgs.ResourceMeteringSaveInterval=New-TimeSpan -Hours $vmHost.ResourceMeteringSaveIntervalHours
+}
+if ($vmHost.MacAddressMinimum) {
+	$SetVmHostArgs.MacAddressMinimum=$vmHost.MacAddressMinimum
+}
+if ($vmHost.MacAddressMaximum) {
+	$SetVmHostArgs.MacAddressMaximum=$vmHost.MacAddressMaximum
+}
+
+Set-VMHost @SetVmHostArgs
+`))
+
+func (c *ClientConfig) UpdateVMHost(
+	ctx context.Context,
+	virtualHardDiskPath string,
+	virtualMachinePath string,
+	numaSpanningEnabled bool,
+	enableEnhancedSessionMode bool,
+	resourceMeteringSaveIntervalHours int,
+	macAddressMinimum string,
+	macAddressMaximum string,
+) (err error) {
+	vmHostJson, err := json.Marshal(api.VmHost{
+		VirtualHardDiskPath:               virtualHardDiskPath,
+		VirtualMachinePath:                virtualMachinePath,
+		NumaSpanningEnabled:               numaSpanningEnabled,
+		EnableEnhancedSessionMode:         enableEnhancedSessionMode,
+		ResourceMeteringSaveIntervalHours: resourceMeteringSaveIntervalHours,
+		MacAddressMinimum:                 macAddressMinimum,
+		MacAddressMaximum:                 macAddressMaximum,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVMHostTemplate, updateVMHostArgs{
+		VmHostJson: string(vmHostJson),
+	})
+
+	return err
+}
+
+type getVMHostInfoArgs struct {
+}
+
+var getVMHostInfoTemplate = template.Must(template.New("GetVMHostInfo").Parse(`
+$ErrorActionPreference = 'Stop'
+$operatingSystem = Get-CimInstance -ClassName Win32_OperatingSystem
+$vmHostInfoObject = Get-VMHost | %{ @{
+	ComputerName=$_.ComputerName;
+	OsName=$operatingSystem.Caption;
+	OsVersion=$operatingSystem.Version;
+	OsBuildNumber=$operatingSystem.BuildNumber;
+	HypervVersion=(Get-Item -Path "$env:SystemRoot\System32\vmms.exe").VersionInfo.ProductVersion;
+	DefaultVmVersion="$((Get-VMHostSupportedVersion -Default).Version)";
+	LogicalProcessorCount=$_.LogicalProcessorCount;
+	MemoryCapacityBytes=[int64]$_.MemoryCapacity;
+	MemoryAvailableBytes=[int64]$operatingSystem.FreePhysicalMemory * 1024;
+	VirtualHardDiskPath=$_.VirtualHardDiskPath;
+	VirtualMachinePath=$_.VirtualMachinePath;
+	NumaSpanningEnabled=$_.NumaSpanningEnabled;
+	NumaNodes=@(Get-VMHostNumaNode | %{ @{
+		NodeId=$_.NodeId;
+		LogicalProcessorCount=@($_.ProcessorsAvailability).Count;
+		MemoryTotalBytes=[int64]$_.MemoryTotal * 1MB;
+		MemoryAvailableBytes=[int64]$_.MemoryAvailable * 1MB;
+	}});
+	IovSupport=$_.IovSupport;
+	IovSupportReasons=@($_.IovSupportReasons);
+}}
+
+if ($vmHostInfoObject){
+	$vmHostInfo = ConvertTo-Json -InputObject $vmHostInfoObject -Depth 3
+	$vmHostInfo
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVMHostInfo(ctx context.Context) (result api.VmHostInfo, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMHostInfoTemplate, getVMHostInfoArgs{}, &result)
+
+	return result, err
+}
+
+type getVMHostLiveMigrationArgs struct {
+}
+
+var getVMHostLiveMigrationTemplate = template.Must(template.New("GetVMHostLiveMigration").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmHostLiveMigrationObject = Get-VMHost | %{ @{
+	VirtualMachineMigrationEnabled=$_.VirtualMachineMigrationEnabled;
+	VirtualMachineMigrationAuthenticationType="$($_.VirtualMachineMigrationAuthenticationType)";
+	MaximumVirtualMachineMigrations=$_.MaximumVirtualMachineMigrations;
+	VirtualMachineMigrationPerformanceOption="$($_.VirtualMachineMigrationPerformanceOption)";
+	UseAnyNetworkForMigration=$_.UseAnyNetworkForMigration;
+	MigrationNetworks=@(Get-VMMigrationNetwork | Sort-Object Priority | %{ $_.Subnet });
+}}
+
+if ($vmHostLiveMigrationObject){
+	$vmHostLiveMigration = ConvertTo-Json -InputObject $vmHostLiveMigrationObject
+	$vmHostLiveMigration
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVMHostLiveMigration(ctx context.Context) (result api.VmHostLiveMigration, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMHostLiveMigrationTemplate, getVMHostLiveMigrationArgs{}, &result)
+
+	return result, err
+}
+
+type updateVMHostLiveMigrationArgs struct {
+	VmHostLiveMigrationJson string
+}
+
+var updateVMHostLiveMigrationTemplate = template.Must(template.New("UpdateVMHostLiveMigration").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmHostLiveMigration = '{{.VmHostLiveMigrationJson}}' | ConvertFrom-Json
+$migrationNetworks = @($vmHostLiveMigration.MigrationNetworks)
+
+if ($vmHostLiveMigration.VirtualMachineMigrationEnabled) {
+	Enable-VMMigration
+} else {
+	Disable-VMMigration
+}
+
+$SetVmHostArgs = @{}
+$SetVmHostArgs.VirtualMachineMigrationAuthenticationType=[Microsoft.HyperV.PowerShell.MigrationAuthenticationType]$vmHostLiveMigration.VirtualMachineMigrationAuthenticationType
+$SetVmHostArgs.MaximumVirtualMachineMigrations=$vmHostLiveMigration.MaximumVirtualMachineMigrations
+$SetVmHostArgs.VirtualMachineMigrationPerformanceOption=[Microsoft.HyperV.PowerShell.VMMigrationPerformance]$vmHostLiveMigration.VirtualMachineMigrationPerformanceOption
+$SetVmHostArgs.UseAnyNetworkForMigration=$vmHostLiveMigration.UseAnyNetworkForMigration
+
+Set-VMHost @SetVmHostArgs
+
+Get-VMMigrationNetwork | ?{ $migrationNetworks -notcontains $_.Subnet } | %{ Remove-VMMigrationNetwork -Subnet $_.Subnet }
+
+$priority = 0
+foreach ($migrationNetwork in $migrationNetworks) {
+	if (Get-VMMigrationNetwork | ?{ $_.Subnet -eq $migrationNetwork }) {
+		Set-VMMigrationNetwork -Subnet $migrationNetwork -Priority $priority
+	} else {
+		Add-VMMigrationNetwork -Subnet $migrationNetwork -Priority $priority
+	}
+	$priority++
+}
+`))
+
+func (c *ClientConfig) UpdateVMHostLiveMigration(
+	ctx context.Context,
+	virtualMachineMigrationEnabled bool,
+	virtualMachineMigrationAuthenticationType api.VMMigrationAuthenticationType,
+	maximumVirtualMachineMigrations int,
+	virtualMachineMigrationPerformanceOption api.VMMigrationPerformance,
+	useAnyNetworkForMigration bool,
+	migrationNetworks []string,
+) (err error) {
+	vmHostLiveMigrationJson, err := json.Marshal(api.VmHostLiveMigration{
+		VirtualMachineMigrationEnabled:            virtualMachineMigrationEnabled,
+		VirtualMachineMigrationAuthenticationType: virtualMachineMigrationAuthenticationType,
+		MaximumVirtualMachineMigrations:           maximumVirtualMachineMigrations,
+		VirtualMachineMigrationPerformanceOption:  virtualMachineMigrationPerformanceOption,
+		UseAnyNetworkForMigration:                 useAnyNetworkForMigration,
+		MigrationNetworks:                         migrationNetworks,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVMHostLiveMigrationTemplate, updateVMHostLiveMigrationArgs{
+		VmHostLiveMigrationJson: string(vmHostLiveMigrationJson),
+	})
+
+	return err
+}
@@ -0,0 +1,85 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getVmInfosArgs struct {
+	Name string
+	Id   string
+}
+
+var getVmInfosTemplate = template.Must(template.New("GetVmInfos").Parse(`
+$ErrorActionPreference = 'Stop'
+$name = '{{.Name}}'
+$id = '{{.Id}}'
+
+if ($id) {
+	$vmObjects = @(Get-VM -Id $id -ErrorAction SilentlyContinue)
+} elseif ($name) {
+	$vmObjects = @(Get-VM -Name "$name*" -ErrorAction SilentlyContinue | ?{$_.Name -eq $name })
+} else {
+	$vmObjects = @(Get-VM)
+}
+
+$vmInfosObject = @($vmObjects | %{ @{
+	Name=$_.Name;
+	Id="$($_.Id)";
+	Generation=$_.Generation;
+	Version="$($_.Version)";
+	State="$($_.State)";
+	Path=$_.Path;
+	Notes=$_.Notes;
+	ProcessorCount=$_.ProcessorCount;
+	DynamicMemory=$_.DynamicMemoryEnabled;
+	MemoryStartupBytes=$_.MemoryStartup;
+	MemoryAssignedBytes=$_.MemoryAssigned;
+	UptimeSeconds=[int64]$_.Uptime.TotalSeconds;
+	Heartbeat="$($_.Heartbeat)";
+	NetworkAdapters=@($_.NetworkAdapters | %{ @{
+		Name=$_.Name;
+		SwitchName=$_.SwitchName;
+		MacAddress=$_.MacAddress;
+		IpAddresses=@($_.IPAddresses);
+	}});
+	HardDiskDrivePaths=@($_.HardDrives | %{ $_.Path });
+	IntegrationServices=@($_ | Get-VMIntegrationService | %{ @{
+		Name=$_.Name;
+		Enabled=$_.Enabled;
+		PrimaryStatusDescription="$($_.PrimaryStatusDescription)";
+	}});
+}})
+
+if ($vmInfosObject) {
+	$vmInfos = ConvertTo-Json -InputObject $vmInfosObject -Depth 5
+	$vmInfos
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetVmInfo(ctx context.Context, name string, id string) (result api.VmInfo, err error) {
+	vmInfos := make([]api.VmInfo, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmInfosTemplate, getVmInfosArgs{
+		Name: name,
+		Id:   id,
+	}, &vmInfos)
+
+	if err != nil || len(vmInfos) < 1 {
+		return result, err
+	}
+
+	return vmInfos[0], err
+}
+
+func (c *ClientConfig) GetVmInfos(ctx context.Context) (result []api.VmInfo, err error) {
+	result = make([]api.VmInfo, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmInfosTemplate, getVmInfosArgs{}, &result)
+
+	return result, err
+}
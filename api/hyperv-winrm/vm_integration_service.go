@@ -88,3 +88,143 @@ func (c *ClientConfig) CreateOrUpdateVmIntegrationServices(ctx context.Context,
 
 	return nil
 }
+
+type getVmGuestIntegrationInfoArgs struct {
+	VmName string
+}
+
+var getVmGuestIntegrationInfoTemplate = template.Must(template.New("GetVmGuestIntegrationInfo").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+
+$guestExchangeItems = @{}
+$vmComputerSystem = Get-CimInstance -Namespace 'root\virtualization\v2' -ClassName 'Msvm_ComputerSystem' -Filter "Name='$($vmObject.Id)'"
+if ($vmComputerSystem) {
+	$kvpExchangeComponent = Get-CimAssociatedInstance -InputObject $vmComputerSystem -ResultClassName 'Msvm_KvpExchangeComponent' -ErrorAction SilentlyContinue
+	if ($kvpExchangeComponent) {
+		$kvpExchangeComponent.GuestIntrinsicExchangeItems | ?{ $_ } | %{
+			$exchangeItem = ([xml]$_).INSTANCE.PROPERTY
+			$exchangeItemName = ($exchangeItem | ?{ $_.NAME -eq 'Name' }).VALUE
+			$exchangeItemData = ($exchangeItem | ?{ $_.NAME -eq 'Data' }).VALUE
+			$guestExchangeItems[$exchangeItemName] = $exchangeItemData
+		}
+	}
+}
+
+$vmGuestIntegrationInfoObject = @{
+	VmName=$vmObject.Name;
+	OsName="$($guestExchangeItems['OSName'])";
+	OsVersion="$($guestExchangeItems['OSVersion'])";
+	OsBuildNumber="$($guestExchangeItems['OSBuildNumber'])";
+	FullyQualifiedDomainName="$($guestExchangeItems['FullyQualifiedDomainName'])";
+	IntegrationServicesVersion="$($guestExchangeItems['IntegrationServicesVersion'])";
+	IntegrationServices=@($vmObject | Get-VMIntegrationService | %{ @{
+		Name=$_.Name;
+		Enabled=$_.Enabled;
+		PrimaryStatusDescription="$($_.PrimaryStatusDescription)";
+	}});
+}
+
+$vmGuestIntegrationInfo = ConvertTo-Json -InputObject $vmGuestIntegrationInfoObject -Depth 3
+$vmGuestIntegrationInfo
+`))
+
+func (c *ClientConfig) GetVmGuestIntegrationInfo(ctx context.Context, vmName string) (result api.VmGuestIntegrationInfo, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmGuestIntegrationInfoTemplate, getVmGuestIntegrationInfoArgs{
+		VmName: vmName,
+	}, &result)
+
+	return result, err
+}
+
+type getVmGuestTimeSyncArgs struct {
+	VmName                          string
+	TimeSyncIntegrationServiceId    string
+	KvpExchangeIntegrationServiceId string
+}
+
+var getVmGuestTimeSyncTemplate = template.Must(template.New("GetVmGuestTimeSync").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmObject = Get-VM -Name '{{.VmName}}*' -ErrorAction SilentlyContinue | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	"{}"
+	return
+}
+
+$timeSyncIntegrationService = Get-VMIntegrationService -VM $vmObject | ?{ $_.Id -like '*\{{.TimeSyncIntegrationServiceId}}' }
+$kvpExchangeIntegrationService = Get-VMIntegrationService -VM $vmObject | ?{ $_.Id -like '*\{{.KvpExchangeIntegrationServiceId}}' }
+
+$fullyQualifiedDomainName = ''
+$vmComputerSystem = Get-CimInstance -Namespace 'root\virtualization\v2' -ClassName 'Msvm_ComputerSystem' -Filter "Name='$($vmObject.Id)'"
+if ($vmComputerSystem) {
+	$kvpExchangeComponent = Get-CimAssociatedInstance -InputObject $vmComputerSystem -ResultClassName 'Msvm_KvpExchangeComponent' -ErrorAction SilentlyContinue
+	if ($kvpExchangeComponent) {
+		$kvpExchangeComponent.GuestIntrinsicExchangeItems | ?{ $_ } | %{
+			$exchangeItem = ([xml]$_).INSTANCE.PROPERTY
+			if (($exchangeItem | ?{ $_.NAME -eq 'Name' }).VALUE -eq 'FullyQualifiedDomainName') {
+				$fullyQualifiedDomainName = ($exchangeItem | ?{ $_.NAME -eq 'Data' }).VALUE
+			}
+		}
+	}
+}
+
+$vmGuestTimeSyncObject = @{
+	VmName=$vmObject.Name;
+	Enabled=[bool]$timeSyncIntegrationService.Enabled;
+	FullyQualifiedDomainName="$fullyQualifiedDomainName";
+	KvpExchangeEnabled=[bool]$kvpExchangeIntegrationService.Enabled;
+	Status="$($timeSyncIntegrationService.PrimaryStatusDescription)";
+}
+
+$vmGuestTimeSync = ConvertTo-Json -InputObject $vmGuestTimeSyncObject
+$vmGuestTimeSync
+`))
+
+func (c *ClientConfig) GetVmGuestTimeSync(ctx context.Context, vmName string) (result api.VmGuestTimeSync, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmGuestTimeSyncTemplate, getVmGuestTimeSyncArgs{
+		VmName:                          vmName,
+		TimeSyncIntegrationServiceId:    api.VmTimeSyncIntegrationServiceId,
+		KvpExchangeIntegrationServiceId: api.VmKvpExchangeIntegrationServiceId,
+	}, &result)
+
+	return result, err
+}
+
+type setVmGuestTimeSyncEnabledArgs struct {
+	VmName                       string
+	Enabled                      bool
+	TimeSyncIntegrationServiceId string
+}
+
+var setVmGuestTimeSyncEnabledTemplate = template.Must(template.New("SetVmGuestTimeSyncEnabled").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+
+$timeSyncIntegrationService = Get-VMIntegrationService -VM $vmObject | ?{ $_.Id -like '*\{{.TimeSyncIntegrationServiceId}}' }
+if (${{.Enabled}}) {
+	$timeSyncIntegrationService | ?{ !$_.Enabled } | Enable-VMIntegrationService
+} else {
+	$timeSyncIntegrationService | ?{ $_.Enabled } | Disable-VMIntegrationService
+}
+`))
+
+func (c *ClientConfig) SetVmGuestTimeSyncEnabled(ctx context.Context, vmName string, enabled bool) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, setVmGuestTimeSyncEnabledTemplate, setVmGuestTimeSyncEnabledArgs{
+		VmName:                       vmName,
+		Enabled:                      enabled,
+		TimeSyncIntegrationServiceId: api.VmTimeSyncIntegrationServiceId,
+	})
+
+	return err
+}
@@ -0,0 +1,138 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+)
+
+// getVmKvpHostItems reads the key value pairs published by the host to the guest of $vmObject into $hostExchangeItems
+const getVmKvpHostItems = `
+$hostExchangeItems = @{}
+$vmSettingData = Get-CimInstance -Namespace 'root\virtualization\v2' -ClassName 'Msvm_VirtualSystemSettingData' -Filter "VirtualSystemIdentifier='$($vmObject.Id)' and VirtualSystemType='Microsoft:Hyper-V:System:Realized'"
+$kvpExchangeComponentSettingData = Get-CimAssociatedInstance -InputObject $vmSettingData -ResultClassName 'Msvm_KvpExchangeComponentSettingData' -ErrorAction SilentlyContinue
+if ($kvpExchangeComponentSettingData) {
+	$kvpExchangeComponentSettingData.HostExchangeItems | ?{ $_ } | %{
+		$exchangeItem = ([xml]$_).INSTANCE.PROPERTY
+		$exchangeItemName = ($exchangeItem | ?{ $_.NAME -eq 'Name' }).VALUE
+		$exchangeItemData = ($exchangeItem | ?{ $_.NAME -eq 'Data' }).VALUE
+		$hostExchangeItems[$exchangeItemName] = "$exchangeItemData"
+	}
+}
+`
+
+// invokeVmKvpItemsMethod calls the method $kvpMethod of the virtual system management service for the key value pair
+// $kvpDataItem of $vmObject and waits for the job it starts
+const invokeVmKvpItemsMethod = `
+$virtualSystemManagementService = Get-WmiObject -Namespace 'root\virtualization\v2' -Class 'Msvm_VirtualSystemManagementService'
+$vmComputerSystem = Get-WmiObject -Namespace 'root\virtualization\v2' -Class 'Msvm_ComputerSystem' -Filter "Name='$($vmObject.Id)'"
+$result = $virtualSystemManagementService.$kvpMethod($vmComputerSystem, @($kvpDataItem.PSBase.GetText(1)))
+
+if ($result.ReturnValue -eq 4096) {
+	$job = [WMI]$result.Job
+	while ($job.JobState -eq 3 -or $job.JobState -eq 4) {
+		Start-Sleep -Milliseconds 500
+		$job.Get()
+	}
+
+	if ($job.JobState -ne 7) {
+		throw "Unable to $kvpMethod $($kvpDataItem.Name) of VM $($vmObject.Name): $($job.ErrorDescription)"
+	}
+} elseif ($result.ReturnValue -ne 0) {
+	throw "Unable to $kvpMethod $($kvpDataItem.Name) of VM $($vmObject.Name): $($result.ReturnValue)"
+}
+`
+
+type getVmKvpHostItemsArgs struct {
+	VmName string
+}
+
+var getVmKvpHostItemsTemplate = template.Must(template.New("GetVmKvpHostItems").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+` + getVmKvpHostItems + `
+$hostExchangeItems = ConvertTo-Json -InputObject $hostExchangeItems
+$hostExchangeItems
+`))
+
+func (c *ClientConfig) GetVmKvpHostItems(ctx context.Context, vmName string) (result map[string]string, err error) {
+	result = make(map[string]string)
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmKvpHostItemsTemplate, getVmKvpHostItemsArgs{
+		VmName: vmName,
+	}, &result)
+
+	return result, err
+}
+
+type setVmKvpHostItemArgs struct {
+	VmName string
+	Name   string
+	Value  string
+}
+
+var setVmKvpHostItemTemplate = template.Must(template.New("SetVmKvpHostItem").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+` + getVmKvpHostItems + `
+if ($hostExchangeItems.ContainsKey('{{.Name}}') -and $hostExchangeItems['{{.Name}}'] -ceq '{{.Value}}') {
+	return
+}
+
+$kvpMethod = if ($hostExchangeItems.ContainsKey('{{.Name}}')) { 'ModifyKvpItems' } else { 'AddKvpItems' }
+$kvpDataItem = ([WMIClass]'root\virtualization\v2:Msvm_KvpExchangeDataItem').CreateInstance()
+$kvpDataItem.Name = '{{.Name}}'
+$kvpDataItem.Data = '{{.Value}}'
+$kvpDataItem.Source = 0
+` + invokeVmKvpItemsMethod + `
+`))
+
+func (c *ClientConfig) SetVmKvpHostItem(ctx context.Context, vmName string, name string, value string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, setVmKvpHostItemTemplate, setVmKvpHostItemArgs{
+		VmName: vmName,
+		Name:   name,
+		Value:  value,
+	})
+
+	return err
+}
+
+type removeVmKvpHostItemArgs struct {
+	VmName string
+	Name   string
+}
+
+var removeVmKvpHostItemTemplate = template.Must(template.New("RemoveVmKvpHostItem").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	return
+}
+` + getVmKvpHostItems + `
+if (!$hostExchangeItems.ContainsKey('{{.Name}}')) {
+	return
+}
+
+$kvpMethod = 'RemoveKvpItems'
+$kvpDataItem = ([WMIClass]'root\virtualization\v2:Msvm_KvpExchangeDataItem').CreateInstance()
+$kvpDataItem.Name = '{{.Name}}'
+$kvpDataItem.Data = ''
+$kvpDataItem.Source = 0
+` + invokeVmKvpItemsMethod + `
+`))
+
+func (c *ClientConfig) RemoveVmKvpHostItem(ctx context.Context, vmName string, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, removeVmKvpHostItemTemplate, removeVmKvpHostItemArgs{
+		VmName: vmName,
+		Name:   name,
+	})
+
+	return err
+}
@@ -0,0 +1,114 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// getVmMemoryNuma reads the memory NUMA settings of $vmObject into $vmMemoryNumaObject. The NUMA nodes the memory is
+// preferably allocated from are the host resources of its memory setting data, the id of a NUMA node is the number
+// its device id ends with.
+const getVmMemoryNuma = `
+$vmSettingData = Get-CimInstance -Namespace 'root\virtualization\v2' -ClassName 'Msvm_VirtualSystemSettingData' -Filter "VirtualSystemIdentifier='$($vmObject.Id)' and VirtualSystemType='Microsoft:Hyper-V:System:Realized'"
+$memorySettingData = Get-CimAssociatedInstance -InputObject $vmSettingData -ResultClassName 'Msvm_MemorySettingData' | Select-Object -First 1
+
+$vmMemoryNumaObject = @{
+	MaximumAmountPerNumaNodeBytes=[int64](Get-VMMemory -VM $vmObject).MaximumAmountPerNumaNodeBytes;
+	PreferredNumaNodes=@($memorySettingData.HostResource | %{ if ($_ -match 'Msvm_NumaNode.*DeviceID="[^"]*?(\d+)"') { [int]$Matches[1] } } | Sort-Object -Unique);
+}
+`
+
+type getVmMemoryNumaArgs struct {
+	VmName string
+}
+
+var getVmMemoryNumaTemplate = template.Must(template.New("GetVmMemoryNuma").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+` + getVmMemoryNuma + `
+$vmMemoryNuma = ConvertTo-Json -InputObject $vmMemoryNumaObject
+$vmMemoryNuma
+`))
+
+func (c *ClientConfig) GetVmMemoryNuma(ctx context.Context, vmName string) (result api.VmMemoryNuma, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmMemoryNumaTemplate, getVmMemoryNumaArgs{
+		VmName: vmName,
+	}, &result)
+
+	return result, err
+}
+
+type setVmMemoryNumaArgs struct {
+	VmName           string
+	VmMemoryNumaJson string
+}
+
+// the NUMA layout of the memory can only be changed while the virtual machine is turned off
+var setVmMemoryNumaTemplate = template.Must(template.New("SetVmMemoryNuma").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmMemoryNuma = '{{.VmMemoryNumaJson}}' | ConvertFrom-Json
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - {{.VmName}}"
+}
+
+$hostNumaNodes = @(Get-VMHostNumaNode)
+$preferredNumaNodes = @($vmMemoryNuma.PreferredNumaNodes | ?{ $_ -ne $null } | %{ [int]$_ })
+$missingNumaNodes = @($preferredNumaNodes | ?{ @($hostNumaNodes.NodeId) -notcontains $_ })
+if ($missingNumaNodes.Length -gt 0) {
+	throw "The HyperV host machine has no NUMA node $($missingNumaNodes -join ', '), its NUMA nodes are $(@($hostNumaNodes.NodeId) -join ', ')"
+}
+
+$maximumAmountPerNumaNodeBytes = [int64]$vmMemoryNuma.MaximumAmountPerNumaNodeBytes
+if ($maximumAmountPerNumaNodeBytes -eq 0) {
+	$maximumAmountPerNumaNodeBytes = [int64]($hostNumaNodes | Measure-Object -Property MemoryTotal -Maximum).Maximum * 1MB
+}
+if ((Get-VMMemory -VM $vmObject).MaximumAmountPerNumaNodeBytes -ne $maximumAmountPerNumaNodeBytes) {
+	Set-VMMemory -VM $vmObject -MaximumAmountPerNumaNodeBytes $maximumAmountPerNumaNodeBytes
+}
+
+$virtualSystemManagementService = Get-WmiObject -Namespace 'root\virtualization\v2' -Class 'Msvm_VirtualSystemManagementService'
+$vmSettingData = Get-WmiObject -Namespace 'root\virtualization\v2' -Class 'Msvm_VirtualSystemSettingData' -Filter "VirtualSystemIdentifier='$($vmObject.Id)' and VirtualSystemType='Microsoft:Hyper-V:System:Realized'"
+$memorySettingData = $vmSettingData.GetRelated('Msvm_MemorySettingData') | Select-Object -First 1
+$numaNodePaths = @(Get-WmiObject -Namespace 'root\virtualization\v2' -Class 'Msvm_NumaNode' | %{ if ($_.DeviceID -match '(\d+)$' -and $preferredNumaNodes -contains [int]$Matches[1]) { $_.__PATH } })
+$memorySettingData.HostResource = [string[]]$numaNodePaths
+
+$result = $virtualSystemManagementService.ModifyResourceSettings(@($memorySettingData.PSBase.GetText(1)))
+if ($result.ReturnValue -eq 4096) {
+	$job = [WMI]$result.Job
+	while ($job.JobState -eq 3 -or $job.JobState -eq 4) {
+		Start-Sleep -Milliseconds 500
+		$job.Get()
+	}
+
+	if ($job.JobState -ne 7) {
+		throw "Unable to set the preferred NUMA nodes of the memory of VM $($vmObject.Name): $($job.ErrorDescription)"
+	}
+} elseif ($result.ReturnValue -ne 0) {
+	throw "Unable to set the preferred NUMA nodes of the memory of VM $($vmObject.Name): $($result.ReturnValue)"
+}
+`))
+
+func (c *ClientConfig) SetVmMemoryNuma(ctx context.Context, vmName string, vmMemoryNuma api.VmMemoryNuma) (err error) {
+	vmMemoryNumaJson, err := json.Marshal(vmMemoryNuma)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, setVmMemoryNumaTemplate, setVmMemoryNumaArgs{
+		VmName:           vmName,
+		VmMemoryNumaJson: string(vmMemoryNumaJson),
+	})
+
+	return err
+}
@@ -0,0 +1,144 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type moveVmArgs struct {
+	VmMigrationJson string
+}
+
+var moveVmTemplate = template.Must(template.New("MoveVm").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmMigration = '{{.VmMigrationJson}}' | ConvertFrom-Json
+
+$vmObject = Get-VM -Name "$($vmMigration.VmName)*" | ?{$_.Name -eq $vmMigration.VmName}
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmMigration.VmName)"
+}
+
+$MoveVmArgs = @{}
+$MoveVmArgs.VM=$vmObject
+$MoveVmArgs.DestinationHost=$vmMigration.DestinationHost
+if ($vmMigration.IncludeStorage) {
+	$MoveVmArgs.IncludeStorage=$true
+	$MoveVmArgs.DestinationStoragePath=$vmMigration.DestinationStoragePath
+}
+
+Move-VM @MoveVmArgs
+`))
+
+func (c *ClientConfig) MoveVm(ctx context.Context, vmName string, destinationHost string, includeStorage bool, destinationStoragePath string) (err error) {
+	vmMigrationJson, err := json.Marshal(api.VmMigration{
+		VmName:                 vmName,
+		DestinationHost:        destinationHost,
+		IncludeStorage:         includeStorage,
+		DestinationStoragePath: destinationStoragePath,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, moveVmTemplate, moveVmArgs{
+		VmMigrationJson: string(vmMigrationJson),
+	})
+
+	return err
+}
+
+type moveVmStorageArgs struct {
+	VmMigrationJson string
+}
+
+var moveVmStorageTemplate = template.Must(template.New("MoveVmStorage").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmMigration = '{{.VmMigrationJson}}' | ConvertFrom-Json
+
+$vmObject = Get-VM -Name "$($vmMigration.VmName)*" | ?{$_.Name -eq $vmMigration.VmName}
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmMigration.VmName)"
+}
+
+Move-VMStorage -VM $vmObject -DestinationStoragePath $vmMigration.DestinationStoragePath
+`))
+
+func (c *ClientConfig) MoveVmStorage(ctx context.Context, vmName string, destinationStoragePath string) (err error) {
+	vmMigrationJson, err := json.Marshal(api.VmMigration{
+		VmName:                 vmName,
+		DestinationStoragePath: destinationStoragePath,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, moveVmStorageTemplate, moveVmStorageArgs{
+		VmMigrationJson: string(vmMigrationJson),
+	})
+
+	return err
+}
+
+type getVmMigrationStatusArgs struct {
+	VmName string
+}
+
+var getVmMigrationStatusTemplate = template.Must(template.New("GetVmMigrationStatus").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+
+$vmMigrationStatusObject = @{
+	Exists=[bool]$vmObject;
+	ComputerName=$(if($vmObject){$vmObject.ComputerName}else{""});
+	Path=$(if($vmObject){$vmObject.Path}else{""});
+}
+
+$vmMigrationStatus = ConvertTo-Json -InputObject $vmMigrationStatusObject
+$vmMigrationStatus
+`))
+
+func (c *ClientConfig) GetVmMigrationStatus(ctx context.Context, vmName string) (result api.VmMigrationStatus, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmMigrationStatusTemplate, getVmMigrationStatusArgs{
+		VmName: vmName,
+	}, &result)
+
+	return result, err
+}
+
+type testVmMigrationDestinationHostArgs struct {
+	DestinationHost string
+}
+
+var testVmMigrationDestinationHostTemplate = template.Must(template.New("TestVmMigrationDestinationHost").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmMigrationDestinationHostObject = @{
+	Reachable=$false;
+	Error="";
+	SourceVirtualMachineMigrationEnabled=(Get-VMHost).VirtualMachineMigrationEnabled;
+}
+
+try {
+	Test-WSMan -ComputerName '{{.DestinationHost}}' | Out-Null
+	$vmMigrationDestinationHostObject.Reachable=$true
+} catch {
+	$vmMigrationDestinationHostObject.Error=$_.Exception.Message
+}
+
+$vmMigrationDestinationHost = ConvertTo-Json -InputObject $vmMigrationDestinationHostObject
+$vmMigrationDestinationHost
+`))
+
+func (c *ClientConfig) TestVmMigrationDestinationHost(ctx context.Context, destinationHost string) (result api.VmMigrationDestinationHost, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, testVmMigrationDestinationHostTemplate, testVmMigrationDestinationHostArgs{
+		DestinationHost: destinationHost,
+	}, &result)
+
+	return result, err
+}
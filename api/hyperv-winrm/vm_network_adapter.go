@@ -3,6 +3,7 @@ package hyperv_winrm
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"text/template"
 
 	"github.com/taliesins/terraform-provider-hyperv/api"
@@ -268,17 +269,26 @@ func (c *ClientConfig) GetVmNetworkAdapters(ctx context.Context, vmName string,
 		VmName: vmName,
 	}, &result)
 
-	// Enrich network adapter with config settings that are not stored in hyperv
+	// adapters are addressed by their position in the list of adapters of the vm when they are updated or removed
+	for networkAdapterIndex := range result {
+		result[networkAdapterIndex].Index = networkAdapterIndex
+	}
+
+	enrichVmNetworkAdaptersWaitForIps(result, networkAdaptersWaitForIps)
+
+	return result, err
+}
+
+// Enrich network adapter with config settings that are not stored in hyperv
+func enrichVmNetworkAdaptersWaitForIps(networkAdapters []api.VmNetworkAdapter, networkAdaptersWaitForIps []api.VmNetworkAdapterWaitForIp) {
 	for _, networkAdapterWaitForIps := range networkAdaptersWaitForIps {
-		for networkAdapterIndex, networkAdapter := range result {
-			if networkAdapterWaitForIps.Name == networkAdapter.Name {
-				result[networkAdapterIndex].WaitForIps = networkAdapterWaitForIps.WaitForIps
+		for networkAdapterIndex, networkAdapter := range networkAdapters {
+			if strings.EqualFold(networkAdapterWaitForIps.Name, networkAdapter.Name) {
+				networkAdapters[networkAdapterIndex].WaitForIps = networkAdapterWaitForIps.WaitForIps
 				break
 			}
 		}
 	}
-
-	return result, err
 }
 
 type waitForVmNetworkAdaptersIpsArgs struct {
@@ -348,7 +358,8 @@ function Wait-ForNetworkAdapterIps($Name, $Timeout, $PollPeriod, $VmNetworkAdapt
 
                 $VmNetworkAdaptersToWaitForIps | ?{$_.WaitForIps} | %{
                     $name = $_.Name
-                    $ipAddresses = @($vmObject.NetworkAdapters | ?{$_.Name -eq $name} | %{$_.IPAddresses} |?{$_})
+                    # an IPv6 only guest reports its link local address before it has an address it can be reached on
+                    $ipAddresses = @($vmObject.NetworkAdapters | ?{$_.Name -eq $name} | %{$_.IPAddresses} |?{$_ -and $_ -notlike 'fe80:*'})
 
                     if ((!($ipAddresses)) -or ($ipAddresses -contains '0.0.0.0')){
                         $waitForIp = $true
@@ -368,7 +379,7 @@ function Wait-ForNetworkAdapterIps($Name, $Timeout, $PollPeriod, $VmNetworkAdapt
 	$timer.Stop()
 
 	if ($timer.Elapsed.TotalSeconds -gt $Timeout) {
-		throw 'Timeout while waiting for vm $($Name) to read network adapter ips'
+		throw [System.TimeoutException]'Timeout while waiting for vm $($Name) to read network adapter ips'
 	} 
 }
 
@@ -380,7 +391,7 @@ $timeout = {{.Timeout}}
 $pollPeriod = {{.PollPeriod}}
 
 if (!$vmObject){
-	throw "VM does not exist - $($vmName)"
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmName)"
 }
 
 Wait-ForNetworkAdapterIps -Name $vmName -Timeout $timeout -PollPeriod $pollPeriod -VmNetworkAdaptersToWaitForIps $vmNetworkAdaptersToWaitForIps
@@ -438,7 +449,7 @@ $macAddressSpoofing = [Microsoft.HyperV.PowerShell.OnOffState]$vmNetworkAdapter.
 $vmNetworkAdaptersObject = @(Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' } | Get-VMNetworkAdapter)[{{.Index}}]
 
 if (!$vmNetworkAdaptersObject){
-	throw "VM network adapter does not exist - {{.Index}}"
+	throw [System.Management.Automation.ItemNotFoundException]"VM network adapter does not exist - {{.Index}}"
 }
 
 if ($vmNetworkAdapter.SwitchName) {
@@ -654,24 +665,26 @@ func (c *ClientConfig) CreateOrUpdateVmNetworkAdapters(ctx context.Context, vmNa
 		return err
 	}
 
-	currentNetworkAdaptersLength := len(currentNetworkAdapters)
-	desiredNetworkAdaptersLength := len(networkAdapters)
+	matches := api.MatchNetworkAdapters(currentNetworkAdapters, networkAdapters)
 
-	for i := currentNetworkAdaptersLength - 1; i > desiredNetworkAdaptersLength-1; i-- {
-		currentNetworkAdapter := currentNetworkAdapters[i]
-		err = c.DeleteVmNetworkAdapter(ctx, vmName, currentNetworkAdapter.Index)
-		if err != nil {
-			return err
+	// the type of an adapter can not be changed, so the adapter is removed and created again
+	networkAdapters = append([]api.VmNetworkAdapter{}, networkAdapters...)
+	for i, networkAdapter := range networkAdapters {
+		if matches[i] == -1 || currentNetworkAdapters[matches[i]].IsLegacy == networkAdapter.IsLegacy {
+			continue
 		}
-	}
 
-	if currentNetworkAdaptersLength > desiredNetworkAdaptersLength {
-		currentNetworkAdaptersLength = desiredNetworkAdaptersLength
+		networkAdapters[i] = api.RecreateNetworkAdapter(currentNetworkAdapters[matches[i]], networkAdapter)
+		matches[i] = -1
 	}
 
-	for i := 0; i <= currentNetworkAdaptersLength-1; i++ {
-		currentNetworkAdapter := currentNetworkAdapters[i]
-		networkAdapter := networkAdapters[i]
+	// adapters are updated before any are removed, as removing an adapter changes the index of the adapters after it
+	for i, networkAdapter := range networkAdapters {
+		if matches[i] == -1 {
+			continue
+		}
+
+		currentNetworkAdapter := currentNetworkAdapters[matches[i]]
 		err = c.UpdateVmNetworkAdapter(
 			ctx,
 			vmName,
@@ -720,8 +733,20 @@ func (c *ClientConfig) CreateOrUpdateVmNetworkAdapters(ctx context.Context, vmNa
 		}
 	}
 
-	for i := currentNetworkAdaptersLength - 1 + 1; i <= desiredNetworkAdaptersLength-1; i++ {
-		networkAdapter := networkAdapters[i]
+	unmatchedNetworkAdapters := api.UnmatchedDevices(len(currentNetworkAdapters), matches)
+	for i := len(unmatchedNetworkAdapters) - 1; i >= 0; i-- {
+		currentNetworkAdapter := currentNetworkAdapters[unmatchedNetworkAdapters[i]]
+		err = c.DeleteVmNetworkAdapter(ctx, vmName, currentNetworkAdapter.Index)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, networkAdapter := range networkAdapters {
+		if matches[i] != -1 {
+			continue
+		}
+
 		err = c.CreateVmNetworkAdapter(
 			ctx,
 			vmName,
@@ -0,0 +1,183 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createOrUpdateVmNetworkAdapterTeamMappingArgs struct {
+	VmNetworkAdapterTeamMappingJson string
+}
+
+var createOrUpdateVmNetworkAdapterTeamMappingTemplate = template.Must(template.New("CreateOrUpdateVmNetworkAdapterTeamMapping").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmNetworkAdapterTeamMapping = '{{.VmNetworkAdapterTeamMappingJson}}' | ConvertFrom-Json
+
+$SetVmNetworkAdapterTeamMappingArgs = @{}
+$SetVmNetworkAdapterTeamMappingArgs.VMNetworkAdapterName=$vmNetworkAdapterTeamMapping.NetworkAdapterName
+$SetVmNetworkAdapterTeamMappingArgs.PhysicalNetAdapterName=$vmNetworkAdapterTeamMapping.PhysicalNetworkAdapterName
+if ($vmNetworkAdapterTeamMapping.ManagementOs) {
+	$SetVmNetworkAdapterTeamMappingArgs.ManagementOS=$true
+	if ($vmNetworkAdapterTeamMapping.SwitchName) {
+		$SetVmNetworkAdapterTeamMappingArgs.SwitchName=$vmNetworkAdapterTeamMapping.SwitchName
+	}
+} else {
+	$vmObject = Get-VM -Name "$($vmNetworkAdapterTeamMapping.VmName)*" | ?{$_.Name -eq $vmNetworkAdapterTeamMapping.VmName}
+	if (!$vmObject){
+		throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmNetworkAdapterTeamMapping.VmName)"
+	}
+	$SetVmNetworkAdapterTeamMappingArgs.VMName=$vmNetworkAdapterTeamMapping.VmName
+}
+
+Set-VMNetworkAdapterTeamMapping @SetVmNetworkAdapterTeamMappingArgs
+`))
+
+func (c *ClientConfig) CreateOrUpdateVmNetworkAdapterTeamMapping(
+	ctx context.Context,
+	managementOs bool,
+	vmName string,
+	switchName string,
+	networkAdapterName string,
+	physicalNetworkAdapterName string,
+) (err error) {
+	vmNetworkAdapterTeamMappingJson, err := json.Marshal(api.VmNetworkAdapterTeamMapping{
+		ManagementOs:               managementOs,
+		VmName:                     vmName,
+		SwitchName:                 switchName,
+		NetworkAdapterName:         networkAdapterName,
+		PhysicalNetworkAdapterName: physicalNetworkAdapterName,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVmNetworkAdapterTeamMappingTemplate, createOrUpdateVmNetworkAdapterTeamMappingArgs{
+		VmNetworkAdapterTeamMappingJson: string(vmNetworkAdapterTeamMappingJson),
+	})
+
+	return err
+}
+
+type getVmNetworkAdapterTeamMappingArgs struct {
+	VmNetworkAdapterTeamMappingJson string
+}
+
+var getVmNetworkAdapterTeamMappingTemplate = template.Must(template.New("GetVmNetworkAdapterTeamMapping").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmNetworkAdapterTeamMapping = '{{.VmNetworkAdapterTeamMappingJson}}' | ConvertFrom-Json
+
+$GetVmNetworkAdapterTeamMappingArgs = @{}
+$GetVmNetworkAdapterTeamMappingArgs.VMNetworkAdapterName=$vmNetworkAdapterTeamMapping.NetworkAdapterName
+if ($vmNetworkAdapterTeamMapping.ManagementOs) {
+	$GetVmNetworkAdapterTeamMappingArgs.ManagementOS=$true
+	if ($vmNetworkAdapterTeamMapping.SwitchName) {
+		$GetVmNetworkAdapterTeamMappingArgs.SwitchName=$vmNetworkAdapterTeamMapping.SwitchName
+	}
+} else {
+	$vmObject = Get-VM -Name "$($vmNetworkAdapterTeamMapping.VmName)*" | ?{$_.Name -eq $vmNetworkAdapterTeamMapping.VmName}
+	if (!$vmObject){
+		"{}"
+		return
+	}
+	$GetVmNetworkAdapterTeamMappingArgs.VMName=$vmNetworkAdapterTeamMapping.VmName
+}
+
+$vmNetworkAdapterTeamMappingObject = Get-VMNetworkAdapterTeamMapping @GetVmNetworkAdapterTeamMappingArgs | Select -First 1 | %{ @{
+	ManagementOs=$_.ParentAdapter.IsManagementOs;
+	VmName=$_.ParentAdapter.VMName;
+	SwitchName=$_.ParentAdapter.SwitchName;
+	NetworkAdapterName=$_.ParentAdapter.Name;
+	PhysicalNetworkAdapterName=$_.NetAdapterName;
+}}
+
+if ($vmNetworkAdapterTeamMappingObject){
+	$vmNetworkAdapterTeamMapping = ConvertTo-Json -InputObject $vmNetworkAdapterTeamMappingObject
+	$vmNetworkAdapterTeamMapping
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVmNetworkAdapterTeamMapping(
+	ctx context.Context,
+	managementOs bool,
+	vmName string,
+	switchName string,
+	networkAdapterName string,
+) (result api.VmNetworkAdapterTeamMapping, err error) {
+	vmNetworkAdapterTeamMappingJson, err := json.Marshal(api.VmNetworkAdapterTeamMapping{
+		ManagementOs:       managementOs,
+		VmName:             vmName,
+		SwitchName:         switchName,
+		NetworkAdapterName: networkAdapterName,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmNetworkAdapterTeamMappingTemplate, getVmNetworkAdapterTeamMappingArgs{
+		VmNetworkAdapterTeamMappingJson: string(vmNetworkAdapterTeamMappingJson),
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmNetworkAdapterTeamMappingArgs struct {
+	VmNetworkAdapterTeamMappingJson string
+}
+
+var deleteVmNetworkAdapterTeamMappingTemplate = template.Must(template.New("DeleteVmNetworkAdapterTeamMapping").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmNetworkAdapterTeamMapping = '{{.VmNetworkAdapterTeamMappingJson}}' | ConvertFrom-Json
+
+$RemoveVmNetworkAdapterTeamMappingArgs = @{}
+$RemoveVmNetworkAdapterTeamMappingArgs.VMNetworkAdapterName=$vmNetworkAdapterTeamMapping.NetworkAdapterName
+if ($vmNetworkAdapterTeamMapping.ManagementOs) {
+	$RemoveVmNetworkAdapterTeamMappingArgs.ManagementOS=$true
+	if ($vmNetworkAdapterTeamMapping.SwitchName) {
+		$RemoveVmNetworkAdapterTeamMappingArgs.SwitchName=$vmNetworkAdapterTeamMapping.SwitchName
+	}
+} else {
+	$vmObject = Get-VM -Name "$($vmNetworkAdapterTeamMapping.VmName)*" | ?{$_.Name -eq $vmNetworkAdapterTeamMapping.VmName}
+	if (!$vmObject){
+		return
+	}
+	$RemoveVmNetworkAdapterTeamMappingArgs.VMName=$vmNetworkAdapterTeamMapping.VmName
+}
+
+if (Get-VMNetworkAdapterTeamMapping @RemoveVmNetworkAdapterTeamMappingArgs) {
+	Remove-VMNetworkAdapterTeamMapping @RemoveVmNetworkAdapterTeamMappingArgs
+}
+`))
+
+func (c *ClientConfig) DeleteVmNetworkAdapterTeamMapping(
+	ctx context.Context,
+	managementOs bool,
+	vmName string,
+	switchName string,
+	networkAdapterName string,
+) (err error) {
+	vmNetworkAdapterTeamMappingJson, err := json.Marshal(api.VmNetworkAdapterTeamMapping{
+		ManagementOs:       managementOs,
+		VmName:             vmName,
+		SwitchName:         switchName,
+		NetworkAdapterName: networkAdapterName,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmNetworkAdapterTeamMappingTemplate, deleteVmNetworkAdapterTeamMappingArgs{
+		VmNetworkAdapterTeamMappingJson: string(vmNetworkAdapterTeamMappingJson),
+	})
+
+	return err
+}
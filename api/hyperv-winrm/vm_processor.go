@@ -37,6 +37,29 @@ if ($vmProcessor.MaximumCountPerNumaSocket -eq 0){
 $SetVMProcessorArgs.MaximumCountPerNumaSocket=$vmProcessor.MaximumCountPerNumaSocket
 $SetVMProcessorArgs.EnableHostResourceProtection=$vmProcessor.EnableHostResourceProtection
 $SetVMProcessorArgs.ExposeVirtualizationExtensions=$vmProcessor.ExposeVirtualizationExtensions
+if ($vmProcessor.ResourcePoolName) {
+	$SetVMProcessorArgs.ResourcePoolName=$vmProcessor.ResourcePoolName
+} else {
+	$SetVMProcessorArgs.ResourcePoolName='Primordial'
+}
+
+# these settings are only supported by newer versions of HyperV, so they are only set when the host knows about them
+$setVMProcessorParameters = (Get-Command Set-VMProcessor).Parameters
+if ($setVMProcessorParameters.ContainsKey('EnableLegacyApicMode')) {
+	$SetVMProcessorArgs.EnableLegacyApicMode=$vmProcessor.EnableLegacyApicMode
+} elseif ($vmProcessor.EnableLegacyApicMode) {
+	throw "Legacy APIC mode is not supported by this version of HyperV"
+}
+if ($setVMProcessorParameters.ContainsKey('L3CacheWays')) {
+	$SetVMProcessorArgs.L3CacheWays=$vmProcessor.L3CacheWays
+} elseif ($vmProcessor.L3CacheWays) {
+	throw "L3 cache ways are not supported by this version of HyperV"
+}
+if ($setVMProcessorParameters.ContainsKey('Perfmon')) {
+	$SetVMProcessorArgs.Perfmon=[string[]]@($vmProcessor.Perfmon | ?{ $_ })
+} elseif (@($vmProcessor.Perfmon | ?{ $_ }).Length -gt 0) {
+	throw "Performance monitoring features are not supported by this version of HyperV"
+}
 
 Set-VMProcessor @SetVMProcessorArgs
 `))
@@ -54,6 +77,10 @@ func (c *ClientConfig) CreateOrUpdateVmProcessor(
 	maximumCountPerNumaSocket int32,
 	enableHostResourceProtection bool,
 	exposeVirtualizationExtensions bool,
+	resourcePoolName string,
+	enableLegacyApicMode bool,
+	l3CacheWays int32,
+	perfmon []string,
 ) (err error) {
 	vmProcessorJson, err := json.Marshal(api.VmProcessor{
 		VmName:                           vmName,
@@ -67,6 +94,10 @@ func (c *ClientConfig) CreateOrUpdateVmProcessor(
 		MaximumCountPerNumaSocket:                    maximumCountPerNumaSocket,
 		EnableHostResourceProtection:                 enableHostResourceProtection,
 		ExposeVirtualizationExtensions:               exposeVirtualizationExtensions,
+		ResourcePoolName:                             resourcePoolName,
+		EnableLegacyApicMode:                         enableLegacyApicMode,
+		L3CacheWays:                                  l3CacheWays,
+		Perfmon:                                      perfmon,
 	})
 
 	if err != nil {
@@ -98,6 +129,10 @@ $vmProcessorObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
 	MaximumCountPerNumaSocket=$_.MaximumCountPerNumaSocket
 	EnableHostResourceProtection=$_.EnableHostResourceProtection
 	ExposeVirtualizationExtensions=$_.ExposeVirtualizationExtensions
+	ResourcePoolName=$(if ($_.ResourcePoolName -ne 'Primordial') { $_.ResourcePoolName } else { "" })
+	EnableLegacyApicMode=[bool]$_.EnableLegacyApicMode
+	L3CacheWays=[int]$_.L3CacheWays
+	Perfmon=@($_.Perfmon | %{ "$_" -split ',\s*' } | ?{ $_ -and $_ -ne 'None' } | %{ $_.ToLower() })
 }}
 
 if ($vmProcessorObject) {
@@ -146,5 +181,9 @@ func (c *ClientConfig) CreateOrUpdateVmProcessors(ctx context.Context, vmName st
 		vmProcessor.MaximumCountPerNumaNode,
 		vmProcessor.MaximumCountPerNumaSocket,
 		vmProcessor.EnableHostResourceProtection,
-		vmProcessor.ExposeVirtualizationExtensions)
+		vmProcessor.ExposeVirtualizationExtensions,
+		vmProcessor.ResourcePoolName,
+		vmProcessor.EnableLegacyApicMode,
+		vmProcessor.L3CacheWays,
+		vmProcessor.Perfmon)
 }
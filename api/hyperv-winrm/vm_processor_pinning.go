@@ -0,0 +1,210 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// vmProcessorPinningDirectory is where the processor pinnings of virtual machines are recorded on the HyperV host
+// machine by the id of the virtual machine, as CpuGroups.exe can not tell which cpu groups were created by the provider
+const vmProcessorPinningDirectory = `$vmProcessorPinningDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\vm-processor-pinnings'`
+
+// cpuGroupsFunctions are functions that run CpuGroups.exe at $cpuGroupsPath, it reports failures with its exit code
+const cpuGroupsFunctions = `
+function Invoke-CpuGroups {
+	param([string[]]$Arguments)
+
+	$output = & $cpuGroupsPath @Arguments 2>&1 | Out-String
+	if ($LASTEXITCODE -ne 0) {
+		throw "CpuGroups.exe $($Arguments -join ' ') failed with exit code $LASTEXITCODE - $output"
+	}
+
+	return $output
+}
+
+function Get-VmCpuGroupId {
+	param([string]$VmName)
+
+	$output = Invoke-CpuGroups -Arguments @('GetVmGroup', "/VmName:$VmName")
+	$cpuGroupIds = @([regex]::Matches($output, '[0-9A-Fa-f]{8}-([0-9A-Fa-f]{4}-){3}[0-9A-Fa-f]{12}') | %{ $_.Value.ToUpper() } | ?{ $_ -ne '00000000-0000-0000-0000-000000000000' })
+	if ($cpuGroupIds) {
+		return $cpuGroupIds[0]
+	}
+
+	return ''
+}
+`
+
+type createOrUpdateVmProcessorPinningArgs struct {
+	VmProcessorPinningJson string
+	CpuGroupId             string
+	GroupAffinity          string
+	CpuCap                 int
+}
+
+var createOrUpdateVmProcessorPinningTemplate = template.Must(template.New("CreateOrUpdateVmProcessorPinning").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmProcessorPinning = '{{.VmProcessorPinningJson}}' | ConvertFrom-Json
+$cpuGroupsPath = $vmProcessorPinning.CpuGroupsPath
+$cpuGroupId = '{{.CpuGroupId}}'
+` + vmProcessorPinningDirectory + cpuGroupsFunctions + `
+$vmObject = Get-VM -Name "$($vmProcessorPinning.VmName)*" | ?{$_.Name -eq $vmProcessorPinning.VmName}
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmProcessorPinning.VmName)"
+}
+
+$recordPath = Join-Path $vmProcessorPinningDirectory "$($vmObject.Id).json"
+$record = $null
+if (Test-Path -LiteralPath $recordPath -PathType Leaf) {
+	$record = Get-Content -LiteralPath $recordPath -Raw | ConvertFrom-Json
+}
+
+$cpuGroups = Invoke-CpuGroups -Arguments @('GetGroups')
+if ($cpuGroups -notmatch [regex]::Escape($cpuGroupId)) {
+	Invoke-CpuGroups -Arguments @('CreateGroup', "/GroupId:$cpuGroupId", '/GroupAffinity:{{.GroupAffinity}}') | Out-Null
+}
+Invoke-CpuGroups -Arguments @('SetGroupProperty', "/GroupId:$cpuGroupId", '/CpuCap:{{.CpuCap}}') | Out-Null
+
+$currentCpuGroupId = Get-VmCpuGroupId -VmName $vmProcessorPinning.VmName
+if ($currentCpuGroupId -ne $cpuGroupId) {
+	if ($vmObject.State -ne 'Off') {
+		throw "VM $($vmProcessorPinning.VmName) must be off to pin its virtual processors to other logical processors, it is $($vmObject.State)"
+	}
+
+	Invoke-CpuGroups -Arguments @('SetVmGroup', "/VmName:$($vmProcessorPinning.VmName)", "/GroupId:$cpuGroupId") | Out-Null
+}
+
+if (!(Test-Path -LiteralPath $vmProcessorPinningDirectory -PathType Container)) {
+	New-Item -ItemType Directory -Path $vmProcessorPinningDirectory | Out-Null
+}
+
+$vmProcessorPinningObject = @{
+	VmName=$vmProcessorPinning.VmName;
+	LogicalProcessors=@($vmProcessorPinning.LogicalProcessors);
+	CpuCapPercent=$vmProcessorPinning.CpuCapPercent;
+	CpuGroupId=$cpuGroupId;
+}
+
+ConvertTo-Json -InputObject $vmProcessorPinningObject | Set-Content -LiteralPath $recordPath -Encoding UTF8
+
+#the cpu group of the previous logical processors is no longer used by the virtual machine
+if ($record -and $record.CpuGroupId -and $record.CpuGroupId -ne $cpuGroupId) {
+	Invoke-CpuGroups -Arguments @('DeleteGroup', "/GroupId:$($record.CpuGroupId)") | Out-Null
+}
+`))
+
+func (c *ClientConfig) CreateOrUpdateVmProcessorPinning(ctx context.Context, vmProcessorPinning api.VmProcessorPinning) (err error) {
+	vmProcessorPinningJson, err := json.Marshal(vmProcessorPinning)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVmProcessorPinningTemplate, createOrUpdateVmProcessorPinningArgs{
+		VmProcessorPinningJson: string(vmProcessorPinningJson),
+		CpuGroupId:             vmProcessorPinning.DesiredCpuGroupId(),
+		GroupAffinity:          vmProcessorPinning.GroupAffinity(),
+		CpuCap:                 vmProcessorPinning.CpuCap(),
+	})
+
+	return err
+}
+
+type getVmProcessorPinningArgs struct {
+	VmName        string
+	CpuGroupsPath string
+}
+
+var getVmProcessorPinningTemplate = template.Must(template.New("GetVmProcessorPinning").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$cpuGroupsPath = '{{.CpuGroupsPath}}'
+` + vmProcessorPinningDirectory + cpuGroupsFunctions + `
+$vmObject = Get-VM -Name '{{.VmName}}*' -ErrorAction SilentlyContinue | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	"{}"
+	return
+}
+
+$recordPath = Join-Path $vmProcessorPinningDirectory "$($vmObject.Id).json"
+if (!(Test-Path -LiteralPath $recordPath -PathType Leaf)) {
+	"{}"
+	return
+}
+
+$record = Get-Content -LiteralPath $recordPath -Raw | ConvertFrom-Json
+
+#the cpu group is read back from the virtual machine, so that a virtual machine moved out of it shows as drift
+$vmProcessorPinningObject = @{
+	VmName=$vmObject.Name;
+	LogicalProcessors=@($record.LogicalProcessors);
+	CpuCapPercent=$record.CpuCapPercent;
+	CpuGroupId=Get-VmCpuGroupId -VmName $vmObject.Name;
+}
+
+$vmProcessorPinning = ConvertTo-Json -InputObject $vmProcessorPinningObject
+$vmProcessorPinning
+`))
+
+func (c *ClientConfig) GetVmProcessorPinning(ctx context.Context, vmName string, cpuGroupsPath string) (result api.VmProcessorPinning, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmProcessorPinningTemplate, getVmProcessorPinningArgs{
+		VmName:        vmName,
+		CpuGroupsPath: cpuGroupsPath,
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmProcessorPinningArgs struct {
+	VmName        string
+	CpuGroupsPath string
+}
+
+var deleteVmProcessorPinningTemplate = template.Must(template.New("DeleteVmProcessorPinning").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$cpuGroupsPath = '{{.CpuGroupsPath}}'
+` + vmProcessorPinningDirectory + cpuGroupsFunctions + `
+$vmObject = Get-VM -Name '{{.VmName}}*' -ErrorAction SilentlyContinue | ?{$_.Name -eq '{{.VmName}}' }
+
+if (!$vmObject){
+	return
+}
+
+$recordPath = Join-Path $vmProcessorPinningDirectory "$($vmObject.Id).json"
+if (!(Test-Path -LiteralPath $recordPath -PathType Leaf)) {
+	return
+}
+
+$record = Get-Content -LiteralPath $recordPath -Raw | ConvertFrom-Json
+
+if ((Get-VmCpuGroupId -VmName $vmObject.Name) -eq $record.CpuGroupId) {
+	if ($vmObject.State -ne 'Off') {
+		throw "VM $($vmObject.Name) must be off to unpin its virtual processors, it is $($vmObject.State)"
+	}
+
+	Invoke-CpuGroups -Arguments @('SetVmGroup', "/VmName:$($vmObject.Name)", '/GroupId:00000000-0000-0000-0000-000000000000') | Out-Null
+}
+
+$cpuGroups = Invoke-CpuGroups -Arguments @('GetGroups')
+if ($cpuGroups -match [regex]::Escape($record.CpuGroupId)) {
+	Invoke-CpuGroups -Arguments @('DeleteGroup', "/GroupId:$($record.CpuGroupId)") | Out-Null
+}
+
+Remove-Item -LiteralPath $recordPath -Force
+`))
+
+func (c *ClientConfig) DeleteVmProcessorPinning(ctx context.Context, vmName string, cpuGroupsPath string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmProcessorPinningTemplate, deleteVmProcessorPinningArgs{
+		VmName:        vmName,
+		CpuGroupsPath: cpuGroupsPath,
+	})
+
+	return err
+}
@@ -0,0 +1,430 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createVMReplicationArgs struct {
+	VmReplicationJson string
+}
+
+var createVMReplicationTemplate = template.Must(template.New("CreateVMReplication").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmReplication = '{{.VmReplicationJson}}' | ConvertFrom-Json
+
+$vmObject = Get-VM -Name "$($vmReplication.VmName)*" | ?{$_.Name -eq $vmReplication.VmName}
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmReplication.VmName)"
+}
+
+$EnableVmReplicationArgs = @{}
+$EnableVmReplicationArgs.VM=$vmObject
+$EnableVmReplicationArgs.ReplicaServerName=$vmReplication.ReplicaServerName
+$EnableVmReplicationArgs.ReplicaServerPort=$vmReplication.ReplicaServerPort
+$EnableVmReplicationArgs.AuthenticationType=$vmReplication.AuthenticationType
+if ($vmReplication.CertificateThumbprint) {
+	$EnableVmReplicationArgs.CertificateThumbprint=$vmReplication.CertificateThumbprint
+}
+$EnableVmReplicationArgs.ReplicationFrequencySec=$vmReplication.ReplicationFrequencySec
+$EnableVmReplicationArgs.RecoveryHistory=$vmReplication.RecoveryHistory
+if ($vmReplication.RecoveryHistory -gt 0 -and $vmReplication.VssSnapshotFrequencyHour -gt 0) {
+	$EnableVmReplicationArgs.VSSSnapshotFrequencyHour=$vmReplication.VssSnapshotFrequencyHour
+}
+$EnableVmReplicationArgs.CompressionEnabled=$vmReplication.CompressionEnabled
+
+Enable-VMReplication @EnableVmReplicationArgs
+
+$StartVmInitialReplicationArgs = @{}
+$StartVmInitialReplicationArgs.VM=$vmObject
+if ($vmReplication.InitialReplicationStartTime) {
+	$StartVmInitialReplicationArgs.InitialReplicationStartTime=[DateTime]::Parse($vmReplication.InitialReplicationStartTime)
+}
+
+Start-VMInitialReplication @StartVmInitialReplicationArgs
+`))
+
+func (c *ClientConfig) CreateVMReplication(
+	ctx context.Context,
+	vmName string,
+	replicaServerName string,
+	replicaServerPort int,
+	authenticationType api.VMReplicationAuthenticationType,
+	certificateThumbprint string,
+	replicationFrequencySec int,
+	recoveryHistory int,
+	vssSnapshotFrequencyHour int,
+	compressionEnabled bool,
+	initialReplicationStartTime string,
+) (err error) {
+	vmReplicationJson, err := json.Marshal(api.VmReplication{
+		VmName:                      vmName,
+		ReplicaServerName:           replicaServerName,
+		ReplicaServerPort:           replicaServerPort,
+		AuthenticationType:          authenticationType,
+		CertificateThumbprint:       certificateThumbprint,
+		ReplicationFrequencySec:     replicationFrequencySec,
+		RecoveryHistory:             recoveryHistory,
+		VssSnapshotFrequencyHour:    vssSnapshotFrequencyHour,
+		CompressionEnabled:          compressionEnabled,
+		InitialReplicationStartTime: initialReplicationStartTime,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVMReplicationTemplate, createVMReplicationArgs{
+		VmReplicationJson: string(vmReplicationJson),
+	})
+
+	return err
+}
+
+type getVMReplicationArgs struct {
+	VmName string
+}
+
+var getVMReplicationTemplate = template.Must(template.New("GetVMReplication").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+if (!$vmObject){
+	"{}"
+	return
+}
+
+$vmReplicationObject = Get-VMReplication -VM $vmObject -ErrorAction SilentlyContinue | ?{ "$($_.Mode)" -eq 'Primary' } | Select -First 1 | %{ @{
+	VmName=$_.VMName;
+	ReplicaServerName=$_.ReplicaServer;
+	ReplicaServerPort=$_.ReplicaPort;
+	AuthenticationType="$($_.AuthType)";
+	CertificateThumbprint=$_.CertificateThumbprint;
+	ReplicationFrequencySec=$_.FrequencySec;
+	RecoveryHistory=$_.RecoveryHistory;
+	VssSnapshotFrequencyHour=$_.VSSSnapshotFrequencyHour;
+	CompressionEnabled=$_.CompressionEnabled;
+	PrimaryServerName=$_.PrimaryServer;
+	State="$($_.State)";
+	Health="$($_.Health)";
+}}
+
+if ($vmReplicationObject){
+	$vmReplication = ConvertTo-Json -InputObject $vmReplicationObject
+	$vmReplication
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVMReplication(ctx context.Context, vmName string) (result api.VmReplication, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMReplicationTemplate, getVMReplicationArgs{
+		VmName: vmName,
+	}, &result)
+
+	return result, err
+}
+
+type updateVMReplicationArgs struct {
+	VmReplicationJson string
+}
+
+var updateVMReplicationTemplate = template.Must(template.New("UpdateVMReplication").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmReplication = '{{.VmReplicationJson}}' | ConvertFrom-Json
+
+$vmObject = Get-VM -Name "$($vmReplication.VmName)*" | ?{$_.Name -eq $vmReplication.VmName}
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmReplication.VmName)"
+}
+
+$SetVmReplicationArgs = @{}
+$SetVmReplicationArgs.VM=$vmObject
+$SetVmReplicationArgs.ReplicaServerName=$vmReplication.ReplicaServerName
+$SetVmReplicationArgs.ReplicaServerPort=$vmReplication.ReplicaServerPort
+$SetVmReplicationArgs.AuthenticationType=$vmReplication.AuthenticationType
+if ($vmReplication.CertificateThumbprint) {
+	$SetVmReplicationArgs.CertificateThumbprint=$vmReplication.CertificateThumbprint
+}
+$SetVmReplicationArgs.ReplicationFrequencySec=$vmReplication.ReplicationFrequencySec
+$SetVmReplicationArgs.RecoveryHistory=$vmReplication.RecoveryHistory
+if ($vmReplication.RecoveryHistory -gt 0 -and $vmReplication.VssSnapshotFrequencyHour -gt 0) {
+	$SetVmReplicationArgs.VSSSnapshotFrequencyHour=$vmReplication.VssSnapshotFrequencyHour
+}
+$SetVmReplicationArgs.CompressionEnabled=$vmReplication.CompressionEnabled
+
+Set-VMReplication @SetVmReplicationArgs
+`))
+
+func (c *ClientConfig) UpdateVMReplication(
+	ctx context.Context,
+	vmName string,
+	replicaServerName string,
+	replicaServerPort int,
+	authenticationType api.VMReplicationAuthenticationType,
+	certificateThumbprint string,
+	replicationFrequencySec int,
+	recoveryHistory int,
+	vssSnapshotFrequencyHour int,
+	compressionEnabled bool,
+) (err error) {
+	vmReplicationJson, err := json.Marshal(api.VmReplication{
+		VmName:                   vmName,
+		ReplicaServerName:        replicaServerName,
+		ReplicaServerPort:        replicaServerPort,
+		AuthenticationType:       authenticationType,
+		CertificateThumbprint:    certificateThumbprint,
+		ReplicationFrequencySec:  replicationFrequencySec,
+		RecoveryHistory:          recoveryHistory,
+		VssSnapshotFrequencyHour: vssSnapshotFrequencyHour,
+		CompressionEnabled:       compressionEnabled,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVMReplicationTemplate, updateVMReplicationArgs{
+		VmReplicationJson: string(vmReplicationJson),
+	})
+
+	return err
+}
+
+type deleteVMReplicationArgs struct {
+	VmName string
+}
+
+var deleteVMReplicationTemplate = template.Must(template.New("DeleteVMReplication").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmObject = Get-VM -Name '{{.VmName}}*' | ?{$_.Name -eq '{{.VmName}}' }
+if (!$vmObject){
+	return
+}
+
+if (Get-VMReplication -VM $vmObject -ErrorAction SilentlyContinue) {
+	Remove-VMReplication -VM $vmObject
+}
+`))
+
+func (c *ClientConfig) DeleteVMReplication(ctx context.Context, vmName string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVMReplicationTemplate, deleteVMReplicationArgs{
+		VmName: vmName,
+	})
+
+	return err
+}
+
+type getVMReplicationServerArgs struct {
+}
+
+var getVMReplicationServerTemplate = template.Must(template.New("GetVMReplicationServer").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmReplicationServerObject = Get-VMReplicationServer | %{ @{
+	ReplicationEnabled=$_.RepEnabled;
+	AllowedAuthenticationType="$($_.AllowedAuthType)";
+	KerberosAuthenticationPort=$_.KerbAuthPort;
+	CertificateAuthenticationPort=$_.CertAuthPort;
+	CertificateThumbprint=$_.CertificateThumbprint;
+	ReplicationAllowedFromAnyServer=$_.AllowAnyServer;
+	DefaultStorageLocation=$_.DefaultStorageLocation;
+}}
+
+if ($vmReplicationServerObject){
+	$vmReplicationServer = ConvertTo-Json -InputObject $vmReplicationServerObject
+	$vmReplicationServer
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVMReplicationServer(ctx context.Context) (result api.VmReplicationServer, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMReplicationServerTemplate, getVMReplicationServerArgs{}, &result)
+
+	return result, err
+}
+
+type updateVMReplicationServerArgs struct {
+	VmReplicationServerJson string
+}
+
+var updateVMReplicationServerTemplate = template.Must(template.New("UpdateVMReplicationServer").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmReplicationServer = '{{.VmReplicationServerJson}}' | ConvertFrom-Json
+
+$SetVmReplicationServerArgs = @{}
+$SetVmReplicationServerArgs.ReplicationEnabled=$vmReplicationServer.ReplicationEnabled
+if ($vmReplicationServer.ReplicationEnabled) {
+	$SetVmReplicationServerArgs.AllowedAuthenticationType=$vmReplicationServer.AllowedAuthenticationType
+	$SetVmReplicationServerArgs.KerberosAuthenticationPort=$vmReplicationServer.KerberosAuthenticationPort
+	$SetVmReplicationServerArgs.CertificateAuthenticationPort=$vmReplicationServer.CertificateAuthenticationPort
+	if ($vmReplicationServer.CertificateThumbprint) {
+		$SetVmReplicationServerArgs.CertificateThumbprint=$vmReplicationServer.CertificateThumbprint
+	}
+	$SetVmReplicationServerArgs.ReplicationAllowedFromAnyServer=$vmReplicationServer.ReplicationAllowedFromAnyServer
+	if ($vmReplicationServer.DefaultStorageLocation) {
+		$SetVmReplicationServerArgs.DefaultStorageLocation=$vmReplicationServer.DefaultStorageLocation
+	}
+}
+
+Set-VMReplicationServer @SetVmReplicationServerArgs -Force
+`))
+
+func (c *ClientConfig) UpdateVMReplicationServer(
+	ctx context.Context,
+	replicationEnabled bool,
+	allowedAuthenticationType api.VMReplicationServerAuthenticationType,
+	kerberosAuthenticationPort int,
+	certificateAuthenticationPort int,
+	certificateThumbprint string,
+	replicationAllowedFromAnyServer bool,
+	defaultStorageLocation string,
+) (err error) {
+	vmReplicationServerJson, err := json.Marshal(api.VmReplicationServer{
+		ReplicationEnabled:              replicationEnabled,
+		AllowedAuthenticationType:       allowedAuthenticationType,
+		KerberosAuthenticationPort:      kerberosAuthenticationPort,
+		CertificateAuthenticationPort:   certificateAuthenticationPort,
+		CertificateThumbprint:           certificateThumbprint,
+		ReplicationAllowedFromAnyServer: replicationAllowedFromAnyServer,
+		DefaultStorageLocation:          defaultStorageLocation,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVMReplicationServerTemplate, updateVMReplicationServerArgs{
+		VmReplicationServerJson: string(vmReplicationServerJson),
+	})
+
+	return err
+}
+
+type createVMReplicationAuthorizationEntryArgs struct {
+	VmReplicationAuthorizationEntryJson string
+}
+
+var createVMReplicationAuthorizationEntryTemplate = template.Must(template.New("CreateVMReplicationAuthorizationEntry").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmReplicationAuthorizationEntry = '{{.VmReplicationAuthorizationEntryJson}}' | ConvertFrom-Json
+
+if (Get-VMReplicationAuthorizationEntry -AllowedPrimaryServer $vmReplicationAuthorizationEntry.AllowedPrimaryServer -ErrorAction SilentlyContinue) {
+	throw "Replication authorization entry already exists - $($vmReplicationAuthorizationEntry.AllowedPrimaryServer)"
+}
+
+$NewVmReplicationAuthorizationEntryArgs = @{}
+$NewVmReplicationAuthorizationEntryArgs.AllowedPrimaryServer=$vmReplicationAuthorizationEntry.AllowedPrimaryServer
+$NewVmReplicationAuthorizationEntryArgs.ReplicaStorageLocation=$vmReplicationAuthorizationEntry.ReplicaStorageLocation
+$NewVmReplicationAuthorizationEntryArgs.TrustGroup=$vmReplicationAuthorizationEntry.TrustGroup
+
+New-VMReplicationAuthorizationEntry @NewVmReplicationAuthorizationEntryArgs | Out-Null
+`))
+
+func (c *ClientConfig) CreateVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string, replicaStorageLocation string, trustGroup string) (err error) {
+	vmReplicationAuthorizationEntryJson, err := json.Marshal(api.VmReplicationAuthorizationEntry{
+		AllowedPrimaryServer:   allowedPrimaryServer,
+		ReplicaStorageLocation: replicaStorageLocation,
+		TrustGroup:             trustGroup,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVMReplicationAuthorizationEntryTemplate, createVMReplicationAuthorizationEntryArgs{
+		VmReplicationAuthorizationEntryJson: string(vmReplicationAuthorizationEntryJson),
+	})
+
+	return err
+}
+
+type getVMReplicationAuthorizationEntryArgs struct {
+	AllowedPrimaryServer string
+}
+
+var getVMReplicationAuthorizationEntryTemplate = template.Must(template.New("GetVMReplicationAuthorizationEntry").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmReplicationAuthorizationEntryObject = Get-VMReplicationAuthorizationEntry -AllowedPrimaryServer '{{.AllowedPrimaryServer}}' -ErrorAction SilentlyContinue | Select -First 1 | %{ @{
+	AllowedPrimaryServer=$_.AllowedPrimaryServer;
+	ReplicaStorageLocation=$_.ReplicaStorageLocation;
+	TrustGroup=$_.TrustGroup;
+}}
+
+if ($vmReplicationAuthorizationEntryObject){
+	$vmReplicationAuthorizationEntry = ConvertTo-Json -InputObject $vmReplicationAuthorizationEntryObject
+	$vmReplicationAuthorizationEntry
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string) (result api.VmReplicationAuthorizationEntry, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMReplicationAuthorizationEntryTemplate, getVMReplicationAuthorizationEntryArgs{
+		AllowedPrimaryServer: allowedPrimaryServer,
+	}, &result)
+
+	return result, err
+}
+
+type updateVMReplicationAuthorizationEntryArgs struct {
+	VmReplicationAuthorizationEntryJson string
+}
+
+var updateVMReplicationAuthorizationEntryTemplate = template.Must(template.New("UpdateVMReplicationAuthorizationEntry").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmReplicationAuthorizationEntry = '{{.VmReplicationAuthorizationEntryJson}}' | ConvertFrom-Json
+
+$SetVmReplicationAuthorizationEntryArgs = @{}
+$SetVmReplicationAuthorizationEntryArgs.AllowedPrimaryServer=$vmReplicationAuthorizationEntry.AllowedPrimaryServer
+$SetVmReplicationAuthorizationEntryArgs.ReplicaStorageLocation=$vmReplicationAuthorizationEntry.ReplicaStorageLocation
+$SetVmReplicationAuthorizationEntryArgs.TrustGroup=$vmReplicationAuthorizationEntry.TrustGroup
+
+Set-VMReplicationAuthorizationEntry @SetVmReplicationAuthorizationEntryArgs
+`))
+
+func (c *ClientConfig) UpdateVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string, replicaStorageLocation string, trustGroup string) (err error) {
+	vmReplicationAuthorizationEntryJson, err := json.Marshal(api.VmReplicationAuthorizationEntry{
+		AllowedPrimaryServer:   allowedPrimaryServer,
+		ReplicaStorageLocation: replicaStorageLocation,
+		TrustGroup:             trustGroup,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVMReplicationAuthorizationEntryTemplate, updateVMReplicationAuthorizationEntryArgs{
+		VmReplicationAuthorizationEntryJson: string(vmReplicationAuthorizationEntryJson),
+	})
+
+	return err
+}
+
+type deleteVMReplicationAuthorizationEntryArgs struct {
+	AllowedPrimaryServer string
+}
+
+var deleteVMReplicationAuthorizationEntryTemplate = template.Must(template.New("DeleteVMReplicationAuthorizationEntry").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+if (Get-VMReplicationAuthorizationEntry -AllowedPrimaryServer '{{.AllowedPrimaryServer}}' -ErrorAction SilentlyContinue) {
+	Remove-VMReplicationAuthorizationEntry -AllowedPrimaryServer '{{.AllowedPrimaryServer}}'
+}
+`))
+
+func (c *ClientConfig) DeleteVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVMReplicationAuthorizationEntryTemplate, deleteVMReplicationAuthorizationEntryArgs{
+		AllowedPrimaryServer: allowedPrimaryServer,
+	})
+
+	return err
+}
@@ -0,0 +1,165 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createVmResourcePoolArgs struct {
+	VmResourcePoolJson string
+}
+
+var createVmResourcePoolTemplate = template.Must(template.New("CreateVmResourcePool").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmResourcePool = '{{.VmResourcePoolJson}}' | ConvertFrom-Json
+$paths = @($vmResourcePool.Paths)
+
+$NewVMResourcePoolArgs = @{}
+$NewVMResourcePoolArgs.Name=$vmResourcePool.Name
+$NewVMResourcePoolArgs.ResourcePoolType=$vmResourcePool.ResourcePoolType
+if ($paths) {
+	$NewVMResourcePoolArgs.Paths=$paths
+}
+
+New-VMResourcePool @NewVMResourcePoolArgs | Out-Null
+
+if ($vmResourcePool.ResourceMeteringEnabled) {
+	Enable-VMResourceMetering -ResourcePoolName $vmResourcePool.Name -ResourcePoolType $vmResourcePool.ResourcePoolType
+}
+`))
+
+func (c *ClientConfig) CreateVmResourcePool(ctx context.Context, name string, resourcePoolType api.VMResourcePoolType, paths []string, resourceMeteringEnabled bool) (err error) {
+	vmResourcePoolJson, err := json.Marshal(struct {
+		Name                    string
+		ResourcePoolType        string
+		Paths                   []string
+		ResourceMeteringEnabled bool
+	}{
+		Name:                    name,
+		ResourcePoolType:        resourcePoolType.String(),
+		Paths:                   paths,
+		ResourceMeteringEnabled: resourceMeteringEnabled,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVmResourcePoolTemplate, createVmResourcePoolArgs{
+		VmResourcePoolJson: string(vmResourcePoolJson),
+	})
+
+	return err
+}
+
+type getVmResourcePoolArgs struct {
+	Name             string
+	ResourcePoolType string
+}
+
+var getVmResourcePoolTemplate = template.Must(template.New("GetVmResourcePool").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmResourcePoolObject = Get-VMResourcePool -Name '{{.Name}}' -ResourcePoolType '{{.ResourcePoolType}}' -ErrorAction SilentlyContinue | Select -First 1 | %{ @{
+	Name=$_.Name;
+	ResourcePoolType="$($_.ResourcePoolType)";
+	Paths=@(if (@('VHD', 'ISO', 'VFD') -contains "$($_.ResourcePoolType)") { Get-VMStoragePath -ResourcePoolName $_.Name -ResourcePoolType $_.ResourcePoolType | %{ $_.Path } });
+	ResourceMeteringEnabled=$_.ResourceMeteringEnabled;
+}}
+
+if ($vmResourcePoolObject){
+	$vmResourcePool = ConvertTo-Json -InputObject $vmResourcePoolObject
+	$vmResourcePool
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVmResourcePool(ctx context.Context, name string, resourcePoolType api.VMResourcePoolType) (result api.VmResourcePool, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmResourcePoolTemplate, getVmResourcePoolArgs{
+		Name:             name,
+		ResourcePoolType: resourcePoolType.String(),
+	}, &result)
+
+	return result, err
+}
+
+type updateVmResourcePoolArgs struct {
+	VmResourcePoolJson string
+}
+
+var updateVmResourcePoolTemplate = template.Must(template.New("UpdateVmResourcePool").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmResourcePool = '{{.VmResourcePoolJson}}' | ConvertFrom-Json
+$paths = @($vmResourcePool.Paths)
+
+$vmResourcePoolObject = Get-VMResourcePool -Name $vmResourcePool.Name -ResourcePoolType $vmResourcePool.ResourcePoolType
+
+if (@('VHD', 'ISO', 'VFD') -contains $vmResourcePool.ResourcePoolType) {
+	$existingPaths = @(Get-VMStoragePath -ResourcePoolName $vmResourcePool.Name -ResourcePoolType $vmResourcePool.ResourcePoolType | %{ $_.Path })
+
+	$pathsToAdd = @($paths | ?{ $existingPaths -notcontains $_ })
+	if ($pathsToAdd) {
+		Add-VMStoragePath -ResourcePoolName $vmResourcePool.Name -ResourcePoolType $vmResourcePool.ResourcePoolType -Path $pathsToAdd
+	}
+
+	$pathsToRemove = @($existingPaths | ?{ $paths -notcontains $_ })
+	if ($pathsToRemove) {
+		Remove-VMStoragePath -ResourcePoolName $vmResourcePool.Name -ResourcePoolType $vmResourcePool.ResourcePoolType -Path $pathsToRemove
+	}
+}
+
+if ($vmResourcePool.ResourceMeteringEnabled -and !$vmResourcePoolObject.ResourceMeteringEnabled) {
+	Enable-VMResourceMetering -ResourcePoolName $vmResourcePool.Name -ResourcePoolType $vmResourcePool.ResourcePoolType
+} elseif (!$vmResourcePool.ResourceMeteringEnabled -and $vmResourcePoolObject.ResourceMeteringEnabled) {
+	Disable-VMResourceMetering -ResourcePoolName $vmResourcePool.Name -ResourcePoolType $vmResourcePool.ResourcePoolType
+}
+`))
+
+func (c *ClientConfig) UpdateVmResourcePool(ctx context.Context, name string, resourcePoolType api.VMResourcePoolType, paths []string, resourceMeteringEnabled bool) (err error) {
+	vmResourcePoolJson, err := json.Marshal(struct {
+		Name                    string
+		ResourcePoolType        string
+		Paths                   []string
+		ResourceMeteringEnabled bool
+	}{
+		Name:                    name,
+		ResourcePoolType:        resourcePoolType.String(),
+		Paths:                   paths,
+		ResourceMeteringEnabled: resourceMeteringEnabled,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVmResourcePoolTemplate, updateVmResourcePoolArgs{
+		VmResourcePoolJson: string(vmResourcePoolJson),
+	})
+
+	return err
+}
+
+type deleteVmResourcePoolArgs struct {
+	Name             string
+	ResourcePoolType string
+}
+
+var deleteVmResourcePoolTemplate = template.Must(template.New("DeleteVmResourcePool").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+Get-VMResourcePool -Name '{{.Name}}' -ResourcePoolType '{{.ResourcePoolType}}' -ErrorAction SilentlyContinue | Remove-VMResourcePool
+`))
+
+func (c *ClientConfig) DeleteVmResourcePool(ctx context.Context, name string, resourcePoolType api.VMResourcePoolType) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmResourcePoolTemplate, deleteVmResourcePoolArgs{
+		Name:             name,
+		ResourcePoolType: resourcePoolType.String(),
+	})
+
+	return err
+}
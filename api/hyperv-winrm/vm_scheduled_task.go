@@ -0,0 +1,238 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// vmScheduledTaskDirectory is where the definitions of the scheduled tasks of virtual machines are recorded on the
+// HyperV host machine, next to the runner script the task scheduler starts and the script of tasks with a Script action.
+// The tasks run as SYSTEM, so it is a protected directory.
+const vmScheduledTaskDirectory = `$vmScheduledTaskDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\vm-scheduled-tasks'`
+
+// vmScheduledTaskRunner is the script the task scheduler runs, it reads the definition of the task when it starts so
+// that an update of the definition does not need the task to be registered again
+const vmScheduledTaskRunner = `
+param($RecordPath, $ScriptPath)
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+` + assertProtectedFileOwnerFunction + `
+Assert-ProtectedFileOwner -Path $RecordPath, $ScriptPath
+
+$vmScheduledTask = Get-Content -LiteralPath $RecordPath -Raw | ConvertFrom-Json
+$vmObject = Get-VM -Name "$($vmScheduledTask.VmName)*" | ?{$_.Name -eq $vmScheduledTask.VmName}
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmScheduledTask.VmName)"
+}
+
+switch ($vmScheduledTask.Action) {
+	'Checkpoint' {
+		$prefix = "$($vmScheduledTask.Name)-"
+		Checkpoint-VM -VM $vmObject -SnapshotName "$prefix$(Get-Date -Format 'yyyyMMddHHmmss')"
+
+		#only checkpoints taken by this task are pruned
+		Get-VMSnapshot -VM $vmObject | ?{ $_.Name.StartsWith($prefix) -and $_.Name.Length -eq ($prefix.Length + 14) } | Sort-Object CreationTime -Descending | Select-Object -Skip $vmScheduledTask.CheckpointRetention | Remove-VMSnapshot
+	}
+	'OptimizeVhd' {
+		#a virtual hard disk can only be compacted while it is not attached to a running vm
+		if ($vmObject.State -ne 'Off') {
+			Write-Warning "VM $($vmScheduledTask.VmName) is $($vmObject.State), so its virtual hard disks are not optimized"
+			return
+		}
+
+		Get-VMHardDiskDrive -VM $vmObject | ?{ $_.Path -and (Test-Path -LiteralPath $_.Path) } | %{
+			Optimize-VHD -Path $_.Path -Mode Full
+		}
+	}
+	'Start' {
+		if ($vmObject.State -ne 'Running') {
+			Start-VM -VM $vmObject
+		}
+	}
+	'Stop' {
+		if ($vmObject.State -ne 'Off') {
+			Stop-VM -VM $vmObject -Force
+		}
+	}
+	'Script' {
+		$scriptBlock = [ScriptBlock]::Create((Get-Content -LiteralPath $ScriptPath -Raw))
+		& $scriptBlock -VmName $vmScheduledTask.VmName
+	}
+}
+`
+
+type createOrUpdateVmScheduledTaskArgs struct {
+	VmScheduledTaskJson string
+	ScriptBase64        string
+	TaskName            string
+}
+
+var createOrUpdateVmScheduledTaskTemplate = template.Must(template.New("CreateOrUpdateVmScheduledTask").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmScheduledTask = '{{.VmScheduledTaskJson}}' | ConvertFrom-Json
+$taskName = '{{.TaskName}}'
+` + protectedDirectoryFunctions + `
+` + vmScheduledTaskDirectory + `
+
+$vmObject = Get-VM -Name "$($vmScheduledTask.VmName)*" | ?{$_.Name -eq $vmScheduledTask.VmName}
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmScheduledTask.VmName)"
+}
+
+Initialize-ProtectedDirectory -Path $vmScheduledTaskDirectory
+
+$runnerPath = Join-Path $vmScheduledTaskDirectory 'runner.ps1'
+$recordPath = Join-Path $vmScheduledTaskDirectory "$taskName.json"
+$scriptPath = Join-Path $vmScheduledTaskDirectory "$taskName-script.ps1"
+@($runnerPath, $recordPath, $scriptPath) | %{ Assert-ProtectedFile -Path $_ }
+
+@'
+` + vmScheduledTaskRunner + `
+'@ | Set-Content -LiteralPath $runnerPath -Encoding UTF8
+Set-ProtectedFileOwner -Path $runnerPath
+
+if ($vmScheduledTask.Action -eq 'Script') {
+	[System.IO.File]::WriteAllBytes($scriptPath, [System.Convert]::FromBase64String('{{.ScriptBase64}}'))
+	Set-ProtectedFileOwner -Path $scriptPath
+} elseif (Test-Path -LiteralPath $scriptPath) {
+	Remove-Item -LiteralPath $scriptPath -Force
+}
+
+ConvertTo-Json -InputObject $vmScheduledTask | Set-Content -LiteralPath $recordPath -Encoding UTF8
+Set-ProtectedFileOwner -Path $recordPath
+
+switch ($vmScheduledTask.Frequency) {
+	'Daily' {
+		$trigger = New-ScheduledTaskTrigger -Daily -At $vmScheduledTask.At
+	}
+	'Weekly' {
+		$trigger = New-ScheduledTaskTrigger -Weekly -DaysOfWeek @($vmScheduledTask.DaysOfWeek) -At $vmScheduledTask.At
+	}
+	'AtStartup' {
+		$trigger = New-ScheduledTaskTrigger -AtStartup
+	}
+}
+
+$argument = '-NoProfile -NonInteractive -ExecutionPolicy Bypass -File "' + $runnerPath + '" -RecordPath "' + $recordPath + '" -ScriptPath "' + $scriptPath + '"'
+$action = New-ScheduledTaskAction -Execute 'powershell.exe' -Argument $argument
+$principal = New-ScheduledTaskPrincipal -UserId 'SYSTEM' -LogonType ServiceAccount -RunLevel Highest
+$settings = New-ScheduledTaskSettingsSet -StartWhenAvailable -MultipleInstances IgnoreNew -Disable:(!$vmScheduledTask.Enabled)
+$description = "$($vmScheduledTask.Action) of VM $($vmScheduledTask.VmName) managed by Terraform as $($vmScheduledTask.Name)"
+Register-ScheduledTask -TaskName $taskName -TaskPath '\terraform-provider-hyperv\' -Description $description -Action $action -Trigger $trigger -Principal $principal -Settings $settings -Force | Out-Null
+`))
+
+func (c *ClientConfig) CreateOrUpdateVmScheduledTask(ctx context.Context, vmScheduledTask api.VmScheduledTask) (err error) {
+	script := vmScheduledTask.Script
+	vmScheduledTask.Script = ""
+	if vmScheduledTask.DaysOfWeek == nil {
+		vmScheduledTask.DaysOfWeek = []string{}
+	}
+
+	vmScheduledTaskJson, err := json.Marshal(&vmScheduledTask)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVmScheduledTaskTemplate, createOrUpdateVmScheduledTaskArgs{
+		VmScheduledTaskJson: string(vmScheduledTaskJson),
+		ScriptBase64:        base64.StdEncoding.EncodeToString([]byte(script)),
+		TaskName:            api.VmScheduledTaskName(vmScheduledTask.VmName, vmScheduledTask.Name),
+	})
+
+	return err
+}
+
+type getVmScheduledTaskArgs struct {
+	TaskName string
+}
+
+var getVmScheduledTaskTemplate = template.Must(template.New("GetVmScheduledTask").Parse(`
+$ErrorActionPreference = 'Stop'
+$taskName = '{{.TaskName}}'
+` + vmScheduledTaskDirectory + `
+$recordPath = Join-Path $vmScheduledTaskDirectory "$taskName.json"
+$scriptPath = Join-Path $vmScheduledTaskDirectory "$taskName-script.ps1"
+
+#a task that was unregistered outside of Terraform is registered again
+$task = Get-ScheduledTask -TaskPath '\terraform-provider-hyperv\' -TaskName $taskName -ErrorAction SilentlyContinue
+if (!$task -or !(Test-Path -LiteralPath $recordPath -PathType Leaf)) {
+	"{}"
+	return
+}
+
+$vmScheduledTask = Get-Content -LiteralPath $recordPath -Raw | ConvertFrom-Json
+$taskInfo = Get-ScheduledTaskInfo -InputObject $task
+
+$script = ''
+if (Test-Path -LiteralPath $scriptPath -PathType Leaf) {
+	$script = [System.IO.File]::ReadAllText($scriptPath)
+}
+
+#the task scheduler reports a time in 1999 for a task that has not run yet
+$lastRunTime = ''
+if ($taskInfo.LastRunTime -and $taskInfo.LastRunTime.Year -ge 2000) {
+	$lastRunTime = $taskInfo.LastRunTime.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+}
+$nextRunTime = ''
+if ($taskInfo.NextRunTime) {
+	$nextRunTime = $taskInfo.NextRunTime.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+}
+
+$vmScheduledTaskObject = @{
+	VmName=$vmScheduledTask.VmName;
+	Name=$vmScheduledTask.Name;
+	Action=$vmScheduledTask.Action;
+	Script=$script;
+	CheckpointRetention=$vmScheduledTask.CheckpointRetention;
+	Frequency=$vmScheduledTask.Frequency;
+	At="$($vmScheduledTask.At)";
+	DaysOfWeek=@($vmScheduledTask.DaysOfWeek | ?{ $_ });
+	Enabled="$($task.State)" -ne 'Disabled';
+	LastRunTime=$lastRunTime;
+	NextRunTime=$nextRunTime;
+	LastTaskResult=[long]$taskInfo.LastTaskResult;
+}
+
+$vmScheduledTask = ConvertTo-Json -InputObject $vmScheduledTaskObject
+$vmScheduledTask
+`))
+
+func (c *ClientConfig) GetVmScheduledTask(ctx context.Context, vmName string, name string) (result api.VmScheduledTask, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmScheduledTaskTemplate, getVmScheduledTaskArgs{
+		TaskName: api.VmScheduledTaskName(vmName, name),
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmScheduledTaskArgs struct {
+	TaskName string
+}
+
+var deleteVmScheduledTaskTemplate = template.Must(template.New("DeleteVmScheduledTask").Parse(`
+$ErrorActionPreference = 'Stop'
+$taskName = '{{.TaskName}}'
+` + vmScheduledTaskDirectory + `
+
+Get-ScheduledTask -TaskPath '\terraform-provider-hyperv\' -TaskName $taskName -ErrorAction SilentlyContinue | Unregister-ScheduledTask -Confirm:$false
+
+@("$taskName.json", "$taskName-script.ps1") | %{ Join-Path $vmScheduledTaskDirectory $_ } | ?{ Test-Path -LiteralPath $_ } | %{
+	Remove-Item -LiteralPath $_ -Force
+}
+`))
+
+func (c *ClientConfig) DeleteVmScheduledTask(ctx context.Context, vmName string, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmScheduledTaskTemplate, deleteVmScheduledTaskArgs{
+		TaskName: api.VmScheduledTaskName(vmName, name),
+	})
+
+	return err
+}
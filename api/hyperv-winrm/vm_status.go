@@ -17,7 +17,15 @@ $ErrorActionPreference = 'Stop'
 $vmName = '{{.VmName}}'
 
 $vmStateObject = Get-VM -Name "$($vmName)*" | ?{$_.Name -eq $vmName } | %{ @{
+	Id="$($_.Id)";
 	State=$_.State;
+	Status=$_.Status;
+	UptimeSeconds=[int64]$_.Uptime.TotalSeconds;
+	CpuUsage=$_.CPUUsage;
+	MemoryAssigned=$_.MemoryAssigned;
+	MemoryDemand=$_.MemoryDemand;
+	Heartbeat="$($_.Heartbeat)";
+	IntegrationServicesState="$($_.IntegrationServicesState)";
 }}
 
 if ($vmStateObject) {
@@ -100,7 +108,7 @@ function Wait-IsInFinalTransitionState($Name, $Timeout, $PollPeriod){
 	$timer.Stop()
 
 	if ($timer.Elapsed.TotalSeconds -gt $Timeout) {
-		throw 'Timeout while waiting for vm $($Name) to reach final transition state'
+		throw [System.TimeoutException]'Timeout while waiting for vm $($Name) to reach final transition state'
 	} 
 }
 
@@ -113,7 +121,7 @@ $timeout = {{.Timeout}}
 $pollPeriod = {{.PollPeriod}}
 
 if (!$vmObject){
-	throw "VM does not exist - $($vmName)"
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmName)"
 }
 
 if ($vmObject.State -ne $state) {
@@ -182,3 +190,91 @@ func (c *ClientConfig) UpdateVmStatus(
 
 	return err
 }
+
+type turnOffVmArgs struct {
+	VmName     string
+	Timeout    uint32
+	PollPeriod uint32
+}
+
+var turnOffVmTemplate = template.Must(template.New("TurnOffVm").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmName = '{{.VmName}}'
+$timeout = {{.Timeout}}
+$pollPeriod = {{.PollPeriod}}
+
+$vmObject = Get-VM -Name "$($vmName)*" | ?{$_.Name -eq $vmName}
+if (!$vmObject) {
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmName)"
+}
+
+if ($vmObject.State -eq [Microsoft.HyperV.PowerShell.VMState]::Off) {
+	return
+}
+
+Stop-VM -Name $vmName -TurnOff -Force
+
+$timer = [Diagnostics.Stopwatch]::StartNew()
+while ((Get-VM -Name "$($vmName)*" | ?{$_.Name -eq $vmName}).State -ne [Microsoft.HyperV.PowerShell.VMState]::Off) {
+	if ($timer.Elapsed.TotalSeconds -gt $timeout) {
+		throw [System.TimeoutException]"Timeout while waiting for vm $($vmName) to turn off"
+	}
+
+	Start-Sleep -Seconds $pollPeriod
+}
+$timer.Stop()
+`))
+
+func (c *ClientConfig) TurnOffVm(ctx context.Context, vmName string, timeout uint32, pollPeriod uint32) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, turnOffVmTemplate, turnOffVmArgs{
+		VmName:     vmName,
+		Timeout:    timeout,
+		PollPeriod: pollPeriod,
+	})
+
+	return err
+}
+
+type waitForVmHeartbeatArgs struct {
+	VmName     string
+	Timeout    uint32
+	PollPeriod uint32
+}
+
+var waitForVmHeartbeatTemplate = template.Must(template.New("WaitForVmHeartbeat").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmName = '{{.VmName}}'
+$timeout = {{.Timeout}}
+$pollPeriod = {{.PollPeriod}}
+
+$timer = [Diagnostics.Stopwatch]::StartNew()
+while ($true) {
+	$vmObject = Get-VM -Name "$($vmName)*" | ?{$_.Name -eq $vmName}
+	if (!$vmObject) {
+		throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($vmName)"
+	}
+
+	if ("$($vmObject.Heartbeat)".StartsWith('Ok')) {
+		break
+	}
+
+	if ($timer.Elapsed.TotalSeconds -gt $timeout) {
+		throw [System.TimeoutException]"Timeout while waiting for vm $($vmName) to report a heartbeat, heartbeat is $($vmObject.Heartbeat)"
+	}
+
+	Start-Sleep -Seconds $pollPeriod
+}
+$timer.Stop()
+`))
+
+func (c *ClientConfig) WaitForVmHeartbeat(ctx context.Context, vmName string, timeout uint32, pollPeriod uint32) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, waitForVmHeartbeatTemplate, waitForVmHeartbeatArgs{
+		VmName:     vmName,
+		Timeout:    timeout,
+		PollPeriod: pollPeriod,
+	})
+
+	return err
+}
@@ -37,72 +37,256 @@ type createVMSwitchArgs struct {
 	VmSwitchJson string
 }
 
+// newVMSwitchFunction defines New-VMSwitchFromSettings, which creates the switch described by $vmSwitch. It is shared
+// by the create and recreate scripts.
+const newVMSwitchFunction = `
+function New-VMSwitchFromSettings {
+	param(
+		[Parameter(Mandatory=$true)]$vmSwitch
+	)
+
+	$minimumBandwidthMode = [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]$vmSwitch.BandwidthReservationMode
+	$switchType = [Microsoft.HyperV.PowerShell.VMSwitchType]$vmSwitch.SwitchType
+	$NetAdapterNames = @($vmSwitch.NetAdapterNames)
+	#when EnablePacketDirect=true it seems to throw an exception if EnableIov=true or EnableEmbeddedTeaming=true
+
+	$NewVmSwitchArgs = @{}
+	$NewVmSwitchArgs.Name=$vmSwitch.Name
+	$NewVmSwitchArgs.MinimumBandwidthMode=$minimumBandwidthMode
+	$NewVmSwitchArgs.EnableEmbeddedTeaming=$vmSwitch.EmbeddedTeamingEnabled
+	$NewVmSwitchArgs.EnableIov=$vmSwitch.IovEnabled
+	$NewVmSwitchArgs.EnablePacketDirect=$vmSwitch.PacketDirectEnabled
+
+	#when a vlan is required for the management os adapter, add the adapter after the vlan can be set so the host does not lose connectivity on a tagged uplink
+	$addManagementOsAdapterWithVlan = $NetAdapterNames -and $vmSwitch.AllowManagementOS -and $vmSwitch.ManagementOsVlanId -gt 0
+
+	if ($NetAdapterNames) {
+		$NewVmSwitchArgs.AllowManagementOS=$vmSwitch.AllowManagementOS -and !$addManagementOsAdapterWithVlan
+		$NewVmSwitchArgs.NetAdapterName=$NetAdapterNames
+	} else {
+		$NewVmSwitchArgs.SwitchType=$switchType
+		#not used unless interface is specified
+		#-AllowManagementOS $vmSwitch.AllowManagementOS
+	}
+	New-VMSwitch @NewVmSwitchArgs
+
+	if ($addManagementOsAdapterWithVlan) {
+		Add-VMNetworkAdapter -ManagementOS -Name $vmSwitch.Name -SwitchName $vmSwitch.Name -Passthru | Set-VMNetworkAdapterVlan -Access -VlanId $vmSwitch.ManagementOsVlanId
+	} elseif ($vmSwitch.ManagementOsVlanId -gt 0) {
+		Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $vmSwitch.Name -Access -VlanId $vmSwitch.ManagementOsVlanId
+	}
+
+	$switchObject = Get-VMSwitch -Name "$($vmSwitch.Name)" | ?{$_.Name -eq $vmSwitch.Name}
+
+	if (!$switchObject){
+		throw [System.Management.Automation.ItemNotFoundException]"Switch does not exist - $($vmSwitch.Name)"
+	}
+
+	if ($vmSwitch.EmbeddedTeamingEnabled) {
+		$SetVmSwitchTeamArgs = @{}
+		$SetVmSwitchTeamArgs.Name=$vmSwitch.Name
+		$SetVmSwitchTeamArgs.TeamingMode=[Microsoft.HyperV.PowerShell.VMSwitchTeamingMode]$vmSwitch.TeamingMode
+		if ($vmSwitch.LoadBalancingAlgorithm) {
+			$SetVmSwitchTeamArgs.LoadBalancingAlgorithm=[Microsoft.HyperV.PowerShell.VMSwitchLoadBalancingAlgorithm]$vmSwitch.LoadBalancingAlgorithm
+		}
+
+		Set-VMSwitchTeam @SetVmSwitchTeamArgs
+	}
+
+	$SetVmSwitchArgs = @{}
+	$SetVmSwitchArgs.Name=$vmSwitch.Name
+	$SetVmSwitchArgs.Notes=$vmSwitch.Notes
+	if (($minimumBandwidthMode -eq [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]::Absolute) -and $vmSwitch.DefaultFlowMinimumBandwidthAbsolute -gt 0 -and $switchObject.DefaultFlowMinimumBandwidthAbsolute -ne $vmSwitch.DefaultFlowMinimumBandwidthAbsolute) {
+		$SetVmSwitchArgs.DefaultFlowMinimumBandwidthAbsolute=$vmSwitch.DefaultFlowMinimumBandwidthAbsolute
+	}
+	if ((($minimumBandwidthMode -eq [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]::Weight) -or (($minimumBandwidthMode -eq [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]::Default) -and (-not ($vmSwitch.IovEnabled)))) -and $switchObject.DefaultFlowMinimumBandwidthWeight -ne $vmSwitch.DefaultFlowMinimumBandwidthWeight) {
+		$SetVmSwitchArgs.DefaultFlowMinimumBandwidthWeight=$vmSwitch.DefaultFlowMinimumBandwidthWeight
+	}
+	$SetVmSwitchArgs.DefaultQueueVmmqEnabled=$vmSwitch.DefaultQueueVmmqEnabled
+	$SetVmSwitchArgs.DefaultQueueVmmqQueuePairs=$vmSwitch.DefaultQueueVmmqQueuePairs
+	$SetVmSwitchArgs.DefaultQueueVrssEnabled=$vmSwitch.DefaultQueueVrssEnabled
+
+	Set-VMSwitch @SetVmSwitchArgs
+}
+`
+
 var createVMSwitchTemplate = template.Must(template.New("CreateVMSwitch").Parse(`
 $ErrorActionPreference = 'Stop'
 Import-Module Hyper-V
 $vmSwitch = '{{.VmSwitchJson}}' | ConvertFrom-Json
-$minimumBandwidthMode = [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]$vmSwitch.BandwidthReservationMode
-$switchType = [Microsoft.HyperV.PowerShell.VMSwitchType]$vmSwitch.SwitchType
-$NetAdapterNames = @($vmSwitch.NetAdapterNames)
-#when EnablePacketDirect=true it seems to throw an exception if EnableIov=true or EnableEmbeddedTeaming=true
 
 $switchObject = Get-VMSwitch -Name "$($vmSwitch.Name)*" | ?{$_.Name -eq $vmSwitch.Name}
 
 if ($switchObject){
 	throw "Switch already exists - $($vmSwitch.Name)"
 }
+` + newVMSwitchFunction + `
+New-VMSwitchFromSettings -VmSwitch $vmSwitch
+`))
+
+func (c *ClientConfig) CreateVMSwitch(
+	ctx context.Context,
+	name string,
+	notes string,
+	allowManagementOS bool,
+	managementOsVlanId int,
+	embeddedTeamingEnabled bool,
+	iovEnabled bool,
+	packetDirectEnabled bool,
+	bandwidthReservationMode api.VMSwitchBandwidthMode,
+	switchType api.VMSwitchType,
+	netAdapterNames []string,
+	loadBalancingAlgorithm api.VMSwitchLoadBalancingAlgorithm,
+	teamingMode api.VMSwitchTeamingMode,
+	defaultFlowMinimumBandwidthAbsolute int64,
+	defaultFlowMinimumBandwidthWeight int64,
+	defaultQueueVmmqEnabled bool,
+	defaultQueueVmmqQueuePairs int32,
+	defaultQueueVrssEnabled bool,
+) (err error) {
+	vmSwitchJson, err := json.Marshal(api.VmSwitch{
+		Name:                                name,
+		Notes:                               notes,
+		AllowManagementOS:                   allowManagementOS,
+		ManagementOsVlanId:                  managementOsVlanId,
+		EmbeddedTeamingEnabled:              embeddedTeamingEnabled,
+		IovEnabled:                          iovEnabled,
+		PacketDirectEnabled:                 packetDirectEnabled,
+		BandwidthReservationMode:            bandwidthReservationMode,
+		SwitchType:                          switchType,
+		NetAdapterNames:                     netAdapterNames,
+		LoadBalancingAlgorithm:              loadBalancingAlgorithm,
+		TeamingMode:                         teamingMode,
+		DefaultFlowMinimumBandwidthAbsolute: defaultFlowMinimumBandwidthAbsolute,
+		DefaultFlowMinimumBandwidthWeight:   defaultFlowMinimumBandwidthWeight,
+		DefaultQueueVmmqEnabled:             defaultQueueVmmqEnabled,
+		DefaultQueueVmmqQueuePairs:          defaultQueueVmmqQueuePairs,
+		DefaultQueueVrssEnabled:             defaultQueueVrssEnabled,
+	})
 
-$NewVmSwitchArgs = @{}
-$NewVmSwitchArgs.Name=$vmSwitch.Name
-$NewVmSwitchArgs.MinimumBandwidthMode=$minimumBandwidthMode
-$NewVmSwitchArgs.EnableEmbeddedTeaming=$vmSwitch.EmbeddedTeamingEnabled
-$NewVmSwitchArgs.EnableIov=$vmSwitch.IovEnabled
-$NewVmSwitchArgs.EnablePacketDirect=$vmSwitch.PacketDirectEnabled
+	if err != nil {
+		return err
+	}
 
-if ($NetAdapterNames) {
-	$NewVmSwitchArgs.AllowManagementOS=$vmSwitch.AllowManagementOS
-	$NewVmSwitchArgs.NetAdapterName=$NetAdapterNames
-} else {
-	$NewVmSwitchArgs.SwitchType=$switchType
-	#not used unless interface is specified
-	#-AllowManagementOS $vmSwitch.AllowManagementOS
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVMSwitchTemplate, createVMSwitchArgs{
+		VmSwitchJson: string(vmSwitchJson),
+	})
+
+	return err
 }
-New-VMSwitch @NewVmSwitchArgs
 
-$switchObject = Get-VMSwitch -Name "$($vmSwitch.Name)" | ?{$_.Name -eq $vmSwitch.Name}
+type recreateVMSwitchArgs struct {
+	VmSwitchJson string
+}
+
+var recreateVMSwitchTemplate = template.Must(template.New("RecreateVMSwitch").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmSwitch = '{{.VmSwitchJson}}' | ConvertFrom-Json
+
+$switchObject = Get-VMSwitch -Name "$($vmSwitch.Name)*" | ?{$_.Name -eq $vmSwitch.Name}
 
 if (!$switchObject){
-	throw "Switch does not exist - $($vmSwitch.Name)"
+	throw [System.Management.Automation.ItemNotFoundException]"Switch does not exist - $($vmSwitch.Name)"
 }
 
-$SetVmSwitchArgs = @{}
-$SetVmSwitchArgs.Name=$vmSwitch.Name
-$SetVmSwitchArgs.Notes=$vmSwitch.Notes
-if (($minimumBandwidthMode -eq [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]::Absolute) -and $switchObject.DefaultFlowMinimumBandwidthAbsolute -ne $vmSwitch.DefaultFlowMinimumBandwidthAbsolute) {
-	$SetVmSwitchArgs.DefaultFlowMinimumBandwidthAbsolute=$vmSwitch.DefaultFlowMinimumBandwidthAbsolute
+#capture the settings of the switch, so that it can be created again when the new switch can not be created
+$previousVmSwitch = [PSCustomObject]@{
+	Name=$switchObject.Name;
+	Notes=$switchObject.Notes;
+	AllowManagementOS=$switchObject.AllowManagementOS;
+	ManagementOsVlanId=$(if($switchObject.AllowManagementOS){[int](Get-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $switchObject.Name -ErrorAction SilentlyContinue | Select -First 1).AccessVlanId}else{0});
+	EmbeddedTeamingEnabled=$switchObject.EmbeddedTeamingEnabled;
+	IovEnabled=$switchObject.IovEnabled;
+	PacketDirectEnabled=$switchObject.PacketDirectEnabled;
+	BandwidthReservationMode=$switchObject.BandwidthReservationMode;
+	SwitchType=$switchObject.SwitchType;
+	NetAdapterNames=@(if($switchObject.NetAdapterInterfaceDescriptions){@(Get-NetAdapter -InterfaceDescription $switchObject.NetAdapterInterfaceDescriptions | %{$_.Name})});
+	LoadBalancingAlgorithm=$(if($switchObject.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $switchObject.Name).LoadBalancingAlgorithm)"}else{""});
+	TeamingMode=$(if($switchObject.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $switchObject.Name).TeamingMode)"}else{""});
+	DefaultFlowMinimumBandwidthAbsolute=$switchObject.DefaultFlowMinimumBandwidthAbsolute;
+	DefaultFlowMinimumBandwidthWeight=$switchObject.DefaultFlowMinimumBandwidthWeight;
+	DefaultQueueVmmqEnabled=$switchObject.DefaultQueueVmmqEnabledRequested;
+	DefaultQueueVmmqQueuePairs=$switchObject.DefaultQueueVmmqQueuePairsRequested;
+	DefaultQueueVrssEnabled=$switchObject.DefaultQueueVrssEnabledRequested;
 }
-if ((($minimumBandwidthMode -eq [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]::Weight) -or (($minimumBandwidthMode -eq [Microsoft.HyperV.PowerShell.VMSwitchBandwidthMode]::Default) -and (-not ($vmSwitch.IovEnabled)))) -and $switchObject.DefaultFlowMinimumBandwidthWeight -ne $vmSwitch.DefaultFlowMinimumBandwidthWeight) {
-	$SetVmSwitchArgs.DefaultFlowMinimumBandwidthWeight=$vmSwitch.DefaultFlowMinimumBandwidthWeight
+
+#capture the static ip configuration of the management os adapter so that it can be restored after the switch has been recreated
+$managementOsAdapterName = "vEthernet ($($vmSwitch.Name))"
+$managementOsAdapter = Get-NetAdapter -Name $managementOsAdapterName -ErrorAction SilentlyContinue
+$managementOsIpAddresses = @()
+$managementOsRoutes = @()
+$managementOsDnsServers = @()
+if ($managementOsAdapter) {
+	$managementOsIpAddresses = @(Get-NetIPAddress -InterfaceIndex $managementOsAdapter.ifIndex -PrefixOrigin Manual -ErrorAction SilentlyContinue | %{ @{ IPAddress=$_.IPAddress; PrefixLength=$_.PrefixLength } })
+	$managementOsRoutes = @(Get-NetRoute -InterfaceIndex $managementOsAdapter.ifIndex -DestinationPrefix '0.0.0.0/0','::/0' -ErrorAction SilentlyContinue | %{ @{ DestinationPrefix=$_.DestinationPrefix; NextHop=$_.NextHop; RouteMetric=$_.RouteMetric } })
+	$managementOsDnsServers = @(Get-DnsClientServerAddress -InterfaceIndex $managementOsAdapter.ifIndex -ErrorAction SilentlyContinue | %{ $_.ServerAddresses })
 }
-$SetVmSwitchArgs.DefaultQueueVmmqEnabled=$vmSwitch.DefaultQueueVmmqEnabled
-$SetVmSwitchArgs.DefaultQueueVmmqQueuePairs=$vmSwitch.DefaultQueueVmmqQueuePairs
-$SetVmSwitchArgs.DefaultQueueVrssEnabled=$vmSwitch.DefaultQueueVrssEnabled
 
-Set-VMSwitch @SetVmSwitchArgs
+#capture the virtual machine network adapters connected to the switch so that they can be reconnected
+$connectedVmNetworkAdapters = @(Get-VMNetworkAdapter -VMName * -ErrorAction SilentlyContinue | ?{$_.SwitchName -eq $vmSwitch.Name})
+` + newVMSwitchFunction + `
+function Restore-VMSwitchConnections {
+	if ($managementOsIpAddresses) {
+		$managementOsAdapter = Get-NetAdapter -Name $managementOsAdapterName -ErrorAction SilentlyContinue
+		if ($managementOsAdapter) {
+			foreach ($managementOsIpAddress in $managementOsIpAddresses) {
+				if (!(Get-NetIPAddress -InterfaceIndex $managementOsAdapter.ifIndex -IPAddress $managementOsIpAddress.IPAddress -ErrorAction SilentlyContinue)) {
+					New-NetIPAddress -InterfaceIndex $managementOsAdapter.ifIndex -IPAddress $managementOsIpAddress.IPAddress -PrefixLength $managementOsIpAddress.PrefixLength | Out-Null
+				}
+			}
+			foreach ($managementOsRoute in $managementOsRoutes) {
+				if (!(Get-NetRoute -InterfaceIndex $managementOsAdapter.ifIndex -DestinationPrefix $managementOsRoute.DestinationPrefix -NextHop $managementOsRoute.NextHop -ErrorAction SilentlyContinue)) {
+					New-NetRoute -InterfaceIndex $managementOsAdapter.ifIndex -DestinationPrefix $managementOsRoute.DestinationPrefix -NextHop $managementOsRoute.NextHop -RouteMetric $managementOsRoute.RouteMetric | Out-Null
+				}
+			}
+			if ($managementOsDnsServers) {
+				Set-DnsClientServerAddress -InterfaceIndex $managementOsAdapter.ifIndex -ServerAddresses $managementOsDnsServers
+			}
+		}
+	}
+
+	foreach ($connectedVmNetworkAdapter in $connectedVmNetworkAdapters) {
+		Connect-VMNetworkAdapter -VMNetworkAdapter $connectedVmNetworkAdapter -SwitchName $vmSwitch.Name
+	}
+}
 
+Remove-VMSwitch -Name $vmSwitch.Name -Force
+
+try {
+	New-VMSwitchFromSettings -VmSwitch $vmSwitch
+	Restore-VMSwitchConnections
+} catch {
+	$recreateError = $_
+
+	#the host must not be left without the switch and the network configuration of its management os adapter, so the
+	#previous switch is created again before the error is reported
+	try {
+		Get-VMSwitch -Name "$($vmSwitch.Name)*" | ?{$_.Name -eq $vmSwitch.Name} | Remove-VMSwitch -Force
+		New-VMSwitchFromSettings -VmSwitch $previousVmSwitch | Out-Null
+		Restore-VMSwitchConnections
+	} catch {
+		throw "Unable to recreate switch $($vmSwitch.Name): $recreateError. Creating the previous switch again failed as well: $_"
+	}
+
+	throw $recreateError
+}
 `))
 
-func (c *ClientConfig) CreateVMSwitch(
+func (c *ClientConfig) RecreateVMSwitch(
 	ctx context.Context,
 	name string,
 	notes string,
 	allowManagementOS bool,
+	managementOsVlanId int,
 	embeddedTeamingEnabled bool,
 	iovEnabled bool,
 	packetDirectEnabled bool,
 	bandwidthReservationMode api.VMSwitchBandwidthMode,
 	switchType api.VMSwitchType,
 	netAdapterNames []string,
+	loadBalancingAlgorithm api.VMSwitchLoadBalancingAlgorithm,
+	teamingMode api.VMSwitchTeamingMode,
 	defaultFlowMinimumBandwidthAbsolute int64,
 	defaultFlowMinimumBandwidthWeight int64,
 	defaultQueueVmmqEnabled bool,
@@ -113,12 +297,15 @@ func (c *ClientConfig) CreateVMSwitch(
 		Name:                                name,
 		Notes:                               notes,
 		AllowManagementOS:                   allowManagementOS,
+		ManagementOsVlanId:                  managementOsVlanId,
 		EmbeddedTeamingEnabled:              embeddedTeamingEnabled,
 		IovEnabled:                          iovEnabled,
 		PacketDirectEnabled:                 packetDirectEnabled,
 		BandwidthReservationMode:            bandwidthReservationMode,
 		SwitchType:                          switchType,
 		NetAdapterNames:                     netAdapterNames,
+		LoadBalancingAlgorithm:              loadBalancingAlgorithm,
+		TeamingMode:                         teamingMode,
 		DefaultFlowMinimumBandwidthAbsolute: defaultFlowMinimumBandwidthAbsolute,
 		DefaultFlowMinimumBandwidthWeight:   defaultFlowMinimumBandwidthWeight,
 		DefaultQueueVmmqEnabled:             defaultQueueVmmqEnabled,
@@ -130,7 +317,7 @@ func (c *ClientConfig) CreateVMSwitch(
 		return err
 	}
 
-	err = c.WinRmClient.RunFireAndForgetScript(ctx, createVMSwitchTemplate, createVMSwitchArgs{
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, recreateVMSwitchTemplate, recreateVMSwitchArgs{
 		VmSwitchJson: string(vmSwitchJson),
 	})
 
@@ -144,15 +331,19 @@ type getVMSwitchArgs struct {
 var getVMSwitchTemplate = template.Must(template.New("GetVMSwitch").Parse(`
 $ErrorActionPreference = 'Stop'
 $vmSwitchObject = Get-VMSwitch -Name '{{.Name}}*' | ?{$_.Name -eq '{{.Name}}' } | %{ @{
+	Id="$($_.Id)";
 	Name=$_.Name;
 	Notes=$_.Notes;
 	AllowManagementOS=$_.AllowManagementOS;
+	ManagementOsVlanId=$(if($_.AllowManagementOS){[int](Get-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $_.Name -ErrorAction SilentlyContinue | Select -First 1).AccessVlanId}else{0});
 	EmbeddedTeamingEnabled=$_.EmbeddedTeamingEnabled;
 	IovEnabled=$_.IovEnabled;
 	PacketDirectEnabled=$_.PacketDirectEnabled;
 	BandwidthReservationMode=$_.BandwidthReservationMode;
 	SwitchType=$_.SwitchType;
 	NetAdapterNames=@(if($_.NetAdapterInterfaceDescriptions){@(Get-NetAdapter -InterfaceDescription $_.NetAdapterInterfaceDescriptions | %{$_.Name})});
+	LoadBalancingAlgorithm=$(if($_.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $_.Name).LoadBalancingAlgorithm)"}else{""});
+	TeamingMode=$(if($_.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $_.Name).TeamingMode)"}else{""});
 	DefaultFlowMinimumBandwidthAbsolute=$_.DefaultFlowMinimumBandwidthAbsolute;
 	DefaultFlowMinimumBandwidthWeight=$_.DefaultFlowMinimumBandwidthWeight;
 	DefaultQueueVmmqEnabled=$_.DefaultQueueVmmqEnabledRequested;
@@ -176,6 +367,91 @@ func (c *ClientConfig) GetVMSwitch(ctx context.Context, name string) (result api
 	return result, err
 }
 
+type getVMSwitchesArgs struct {
+}
+
+var getVMSwitchesTemplate = template.Must(template.New("GetVMSwitches").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmSwitchesObject = @(Get-VMSwitch | %{ @{
+	Id="$($_.Id)";
+	Name=$_.Name;
+	Notes=$_.Notes;
+	AllowManagementOS=$_.AllowManagementOS;
+	ManagementOsVlanId=$(if($_.AllowManagementOS){[int](Get-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $_.Name -ErrorAction SilentlyContinue | Select -First 1).AccessVlanId}else{0});
+	EmbeddedTeamingEnabled=$_.EmbeddedTeamingEnabled;
+	IovEnabled=$_.IovEnabled;
+	PacketDirectEnabled=$_.PacketDirectEnabled;
+	BandwidthReservationMode=$_.BandwidthReservationMode;
+	SwitchType=$_.SwitchType;
+	NetAdapterNames=@(if($_.NetAdapterInterfaceDescriptions){@(Get-NetAdapter -InterfaceDescription $_.NetAdapterInterfaceDescriptions | %{$_.Name})});
+	LoadBalancingAlgorithm=$(if($_.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $_.Name).LoadBalancingAlgorithm)"}else{""});
+	TeamingMode=$(if($_.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $_.Name).TeamingMode)"}else{""});
+	DefaultFlowMinimumBandwidthAbsolute=$_.DefaultFlowMinimumBandwidthAbsolute;
+	DefaultFlowMinimumBandwidthWeight=$_.DefaultFlowMinimumBandwidthWeight;
+	DefaultQueueVmmqEnabled=$_.DefaultQueueVmmqEnabledRequested;
+	DefaultQueueVmmqQueuePairs=$_.DefaultQueueVmmqQueuePairsRequested;
+	DefaultQueueVrssEnabled=$_.DefaultQueueVrssEnabledRequested;
+}})
+
+if ($vmSwitchesObject) {
+	$vmSwitches = ConvertTo-Json -InputObject $vmSwitchesObject
+	$vmSwitches
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetVMSwitches(ctx context.Context) (result []api.VmSwitch, err error) {
+	result = make([]api.VmSwitch, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMSwitchesTemplate, getVMSwitchesArgs{}, &result)
+
+	return result, err
+}
+
+type getDefaultVMSwitchArgs struct {
+	Id string
+}
+
+var getDefaultVMSwitchTemplate = template.Must(template.New("GetDefaultVMSwitch").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmSwitchObject = Get-VMSwitch -Id '{{.Id}}' -ErrorAction SilentlyContinue | %{ @{
+	Id="$($_.Id)";
+	Name=$_.Name;
+	Notes=$_.Notes;
+	AllowManagementOS=$_.AllowManagementOS;
+	ManagementOsVlanId=$(if($_.AllowManagementOS){[int](Get-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $_.Name -ErrorAction SilentlyContinue | Select -First 1).AccessVlanId}else{0});
+	EmbeddedTeamingEnabled=$_.EmbeddedTeamingEnabled;
+	IovEnabled=$_.IovEnabled;
+	PacketDirectEnabled=$_.PacketDirectEnabled;
+	BandwidthReservationMode=$_.BandwidthReservationMode;
+	SwitchType=$_.SwitchType;
+	NetAdapterNames=@(if($_.NetAdapterInterfaceDescriptions){@(Get-NetAdapter -InterfaceDescription $_.NetAdapterInterfaceDescriptions | %{$_.Name})});
+	LoadBalancingAlgorithm=$(if($_.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $_.Name).LoadBalancingAlgorithm)"}else{""});
+	TeamingMode=$(if($_.EmbeddedTeamingEnabled){"$((Get-VMSwitchTeam -Name $_.Name).TeamingMode)"}else{""});
+	DefaultFlowMinimumBandwidthAbsolute=$_.DefaultFlowMinimumBandwidthAbsolute;
+	DefaultFlowMinimumBandwidthWeight=$_.DefaultFlowMinimumBandwidthWeight;
+	DefaultQueueVmmqEnabled=$_.DefaultQueueVmmqEnabledRequested;
+	DefaultQueueVmmqQueuePairs=$_.DefaultQueueVmmqQueuePairsRequested;
+	DefaultQueueVrssEnabled=$_.DefaultQueueVrssEnabledRequested;
+}}
+
+if ($vmSwitchObject){
+	$vmSwitch = ConvertTo-Json -InputObject $vmSwitchObject
+	$vmSwitch
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetDefaultVMSwitch(ctx context.Context) (result api.VmSwitch, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getDefaultVMSwitchTemplate, getDefaultVMSwitchArgs{
+		Id: api.DefaultVMSwitchId,
+	}, &result)
+
+	return result, err
+}
+
 type updateVMSwitchArgs struct {
 	VmSwitchJson string
 }
@@ -192,7 +468,7 @@ $NetAdapterNames = @($vmSwitch.NetAdapterNames)
 $switchObject = Get-VMSwitch -Name "$($vmSwitch.Name)*" | ?{$_.Name -eq $vmSwitch.Name}
 
 if (!$switchObject){
-	throw "Switch does not exist - $($vmSwitch.Name)"
+	throw [System.Management.Automation.ItemNotFoundException]"Switch does not exist - $($vmSwitch.Name)"
 }
 
 $SetVmSwitchArgs = @{}
@@ -200,7 +476,20 @@ $SetVmSwitchArgs.Name=$vmSwitch.Name
 $SetVmSwitchArgs.Notes=$vmSwitch.Notes
 if ($NetAdapterNames) {
 	$SetVmSwitchArgs.AllowManagementOS=$vmSwitch.AllowManagementOS
-	$SetVmSwitchArgs.NetAdapterName=$NetAdapterNames
+	if ($switchObject.EmbeddedTeamingEnabled) {
+		#team members of a switch embedded teaming (SET) switch can only be changed via the switch team
+		$SetVmSwitchTeamArgs = @{}
+		$SetVmSwitchTeamArgs.Name=$vmSwitch.Name
+		$SetVmSwitchTeamArgs.NetAdapterName=$NetAdapterNames
+		$SetVmSwitchTeamArgs.TeamingMode=[Microsoft.HyperV.PowerShell.VMSwitchTeamingMode]$vmSwitch.TeamingMode
+		if ($vmSwitch.LoadBalancingAlgorithm) {
+			$SetVmSwitchTeamArgs.LoadBalancingAlgorithm=[Microsoft.HyperV.PowerShell.VMSwitchLoadBalancingAlgorithm]$vmSwitch.LoadBalancingAlgorithm
+		}
+
+		Set-VMSwitchTeam @SetVmSwitchTeamArgs
+	} else {
+		$SetVmSwitchArgs.NetAdapterName=$NetAdapterNames
+	}
 	#Updates not supported on:
 	#-EnableEmbeddedTeaming $vmSwitch.EmbeddedTeamingEnabled
 	#-EnableIov $vmSwitch.IovEnabled
@@ -229,6 +518,17 @@ $SetVmSwitchArgs.DefaultQueueVmmqQueuePairs=$vmSwitch.DefaultQueueVmmqQueuePairs
 $SetVmSwitchArgs.DefaultQueueVrssEnabled=$vmSwitch.DefaultQueueVrssEnabled
 
 Set-VMSwitch @SetVmSwitchArgs
+
+if ($vmSwitch.AllowManagementOS) {
+	$managementOsVlan = Get-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $vmSwitch.Name -ErrorAction SilentlyContinue | Select -First 1
+	if ($managementOsVlan -and ([int]$managementOsVlan.AccessVlanId -ne $vmSwitch.ManagementOsVlanId)) {
+		if ($vmSwitch.ManagementOsVlanId -gt 0) {
+			Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $vmSwitch.Name -Access -VlanId $vmSwitch.ManagementOsVlanId
+		} else {
+			Set-VMNetworkAdapterVlan -ManagementOS -VMNetworkAdapterName $vmSwitch.Name -Untagged
+		}
+	}
+}
 `))
 
 func (c *ClientConfig) UpdateVMSwitch(
@@ -236,12 +536,15 @@ func (c *ClientConfig) UpdateVMSwitch(
 	name string,
 	notes string,
 	allowManagementOS bool,
+	managementOsVlanId int,
 	// embeddedTeamingEnabled bool,
 	// iovEnabled bool,
 	// packetDirectEnabled bool,
 	// bandwidthReservationMode api.VMSwitchBandwidthMode,
 	switchType api.VMSwitchType,
 	netAdapterNames []string,
+	loadBalancingAlgorithm api.VMSwitchLoadBalancingAlgorithm,
+	teamingMode api.VMSwitchTeamingMode,
 	defaultFlowMinimumBandwidthAbsolute int64,
 	defaultFlowMinimumBandwidthWeight int64,
 	defaultQueueVmmqEnabled bool,
@@ -249,15 +552,18 @@ func (c *ClientConfig) UpdateVMSwitch(
 	defaultQueueVrssEnabled bool,
 ) (err error) {
 	vmSwitchJson, err := json.Marshal(api.VmSwitch{
-		Name:              name,
-		Notes:             notes,
-		AllowManagementOS: allowManagementOS,
+		Name:               name,
+		Notes:              notes,
+		AllowManagementOS:  allowManagementOS,
+		ManagementOsVlanId: managementOsVlanId,
 		//EmbeddedTeamingEnabled:embeddedTeamingEnabled,
 		//IovEnabled:iovEnabled,
 		//PacketDirectEnabled:packetDirectEnabled,
 		//BandwidthReservationMode:bandwidthReservationMode,
 		SwitchType:                          switchType,
 		NetAdapterNames:                     netAdapterNames,
+		LoadBalancingAlgorithm:              loadBalancingAlgorithm,
+		TeamingMode:                         teamingMode,
 		DefaultFlowMinimumBandwidthAbsolute: defaultFlowMinimumBandwidthAbsolute,
 		DefaultFlowMinimumBandwidthWeight:   defaultFlowMinimumBandwidthWeight,
 		DefaultQueueVmmqEnabled:             defaultQueueVmmqEnabled,
@@ -292,3 +598,88 @@ func (c *ClientConfig) DeleteVMSwitch(ctx context.Context, name string) (err err
 
 	return err
 }
+
+type getVMSwitchNetworkAdaptersArgs struct {
+	Name string
+}
+
+var getVMSwitchNetworkAdaptersTemplate = template.Must(template.New("GetVMSwitchNetworkAdapters").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmNetworkAdaptersObject = @(Get-VMNetworkAdapter -VMName * | ?{$_.SwitchName -eq '{{.Name}}'} | %{ @{
+	VmName=$_.VMName;
+	Name=$_.Name;
+}})
+
+if ($vmNetworkAdaptersObject) {
+	$vmNetworkAdapters = ConvertTo-Json -InputObject $vmNetworkAdaptersObject
+	$vmNetworkAdapters
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetVMSwitchNetworkAdapters(ctx context.Context, name string) (result []api.VmSwitchNetworkAdapter, err error) {
+	result = make([]api.VmSwitchNetworkAdapter, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMSwitchNetworkAdaptersTemplate, getVMSwitchNetworkAdaptersArgs{
+		Name: name,
+	}, &result)
+
+	return result, err
+}
+
+type disconnectVMSwitchNetworkAdaptersArgs struct {
+	Name string
+}
+
+var disconnectVMSwitchNetworkAdaptersTemplate = template.Must(template.New("DisconnectVMSwitchNetworkAdapters").Parse(`
+$ErrorActionPreference = 'Stop'
+Get-VMNetworkAdapter -VMName * | ?{$_.SwitchName -eq '{{.Name}}'} | Disconnect-VMNetworkAdapter
+`))
+
+func (c *ClientConfig) DisconnectVMSwitchNetworkAdapters(ctx context.Context, name string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, disconnectVMSwitchNetworkAdaptersTemplate, disconnectVMSwitchNetworkAdaptersArgs{
+		Name: name,
+	})
+
+	return err
+}
+
+type getVMSwitchIovSupportArgs struct {
+	NetAdapterNamesJson string
+}
+
+var getVMSwitchIovSupportTemplate = template.Must(template.New("GetVMSwitchIovSupport").Parse(`
+$ErrorActionPreference = 'Stop'
+$netAdapterNames = @('{{.NetAdapterNamesJson}}' | ConvertFrom-Json)
+$vmHost = Get-VMHost
+
+$netAdapters = @($netAdapterNames | %{
+	$netAdapterName = $_
+	$netAdapterSriov = Get-NetAdapterSriov -Name $netAdapterName -ErrorAction SilentlyContinue
+	@{
+		Name=$netAdapterName;
+		SriovSupport=$(if($netAdapterSriov){"$($netAdapterSriov.SriovSupport)"}else{"NotSupported"});
+	}
+})
+
+$vmSwitchIovSupport = ConvertTo-Json -InputObject @{
+	HostIovSupport=$vmHost.IovSupport;
+	HostIovSupportReasons=@($vmHost.IovSupportReasons);
+	NetAdapters=$netAdapters;
+}
+$vmSwitchIovSupport
+`))
+
+func (c *ClientConfig) GetVMSwitchIovSupport(ctx context.Context, netAdapterNames []string) (result api.VmSwitchIovSupport, err error) {
+	netAdapterNamesJson, err := json.Marshal(netAdapterNames)
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMSwitchIovSupportTemplate, getVMSwitchIovSupportArgs{
+		NetAdapterNamesJson: string(netAdapterNamesJson),
+	}, &result)
+
+	return result, err
+}
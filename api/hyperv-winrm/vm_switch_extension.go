@@ -0,0 +1,161 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type getVMSwitchExtensionArgs struct {
+	SwitchName string
+	Name       string
+}
+
+var getVMSwitchExtensionTemplate = template.Must(template.New("GetVMSwitchExtension").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmSwitchObject = Get-VMSwitch -Name '{{.SwitchName}}*' | ?{$_.Name -eq '{{.SwitchName}}' }
+
+$vmSwitchExtensionObject = $null
+if ($vmSwitchObject){
+	$vmSwitchExtensionObject = Get-VMSwitchExtension -VMSwitch $vmSwitchObject | ?{$_.Name -eq '{{.Name}}' } | Select -First 1 | %{ @{
+		SwitchName=$_.SwitchName;
+		Name=$_.Name;
+		Id=$_.Id;
+		Vendor=$_.Vendor;
+		Version=$_.Version;
+		ExtensionType="$($_.ExtensionType)";
+		Enabled=$_.Enabled;
+		Running=$_.Running;
+	}}
+}
+
+if ($vmSwitchExtensionObject){
+	$vmSwitchExtension = ConvertTo-Json -InputObject $vmSwitchExtensionObject
+	$vmSwitchExtension
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVMSwitchExtension(ctx context.Context, switchName string, name string) (result api.VmSwitchExtension, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMSwitchExtensionTemplate, getVMSwitchExtensionArgs{
+		SwitchName: switchName,
+		Name:       name,
+	}, &result)
+
+	return result, err
+}
+
+type getVMSwitchExtensionsArgs struct {
+	SwitchName string
+}
+
+var getVMSwitchExtensionsTemplate = template.Must(template.New("GetVMSwitchExtensions").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmSwitchObject = Get-VMSwitch -Name '{{.SwitchName}}*' | ?{$_.Name -eq '{{.SwitchName}}' }
+
+if (!$vmSwitchObject){
+	throw [System.Management.Automation.ItemNotFoundException]"Switch does not exist - {{.SwitchName}}"
+}
+
+$vmSwitchExtensionsObject = @(Get-VMSwitchExtension -VMSwitch $vmSwitchObject | %{ @{
+	SwitchName=$_.SwitchName;
+	Name=$_.Name;
+	Id=$_.Id;
+	Vendor=$_.Vendor;
+	Version=$_.Version;
+	ExtensionType="$($_.ExtensionType)";
+	Enabled=$_.Enabled;
+	Running=$_.Running;
+}})
+
+if ($vmSwitchExtensionsObject) {
+	$vmSwitchExtensions = ConvertTo-Json -InputObject $vmSwitchExtensionsObject
+	$vmSwitchExtensions
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetVMSwitchExtensions(ctx context.Context, switchName string) (result []api.VmSwitchExtension, err error) {
+	result = make([]api.VmSwitchExtension, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVMSwitchExtensionsTemplate, getVMSwitchExtensionsArgs{
+		SwitchName: switchName,
+	}, &result)
+
+	return result, err
+}
+
+type getAllVMSwitchExtensionsArgs struct {
+}
+
+var getAllVMSwitchExtensionsTemplate = template.Must(template.New("GetAllVMSwitchExtensions").Parse(`
+$ErrorActionPreference = 'Stop'
+
+$vmSwitchExtensionsObject = @(Get-VMSwitch | Get-VMSwitchExtension | Sort-Object -Property SwitchName, Name | %{ @{
+	SwitchName=$_.SwitchName;
+	Name=$_.Name;
+	Id=$_.Id;
+	Vendor=$_.Vendor;
+	Version=$_.Version;
+	ExtensionType="$($_.ExtensionType)";
+	Enabled=$_.Enabled;
+	Running=$_.Running;
+}})
+
+if ($vmSwitchExtensionsObject) {
+	$vmSwitchExtensions = ConvertTo-Json -InputObject $vmSwitchExtensionsObject
+	$vmSwitchExtensions
+} else {
+	"[]"
+}
+`))
+
+func (c *ClientConfig) GetAllVMSwitchExtensions(ctx context.Context) (result []api.VmSwitchExtension, err error) {
+	result = make([]api.VmSwitchExtension, 0)
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getAllVMSwitchExtensionsTemplate, getAllVMSwitchExtensionsArgs{}, &result)
+
+	return result, err
+}
+
+type updateVMSwitchExtensionArgs struct {
+	SwitchName string
+	Name       string
+	Enabled    bool
+}
+
+var updateVMSwitchExtensionTemplate = template.Must(template.New("UpdateVMSwitchExtension").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$vmSwitchObject = Get-VMSwitch -Name '{{.SwitchName}}*' | ?{$_.Name -eq '{{.SwitchName}}' }
+
+if (!$vmSwitchObject){
+	throw [System.Management.Automation.ItemNotFoundException]"Switch does not exist - {{.SwitchName}}"
+}
+
+$vmSwitchExtensionObject = Get-VMSwitchExtension -VMSwitch $vmSwitchObject | ?{$_.Name -eq '{{.Name}}' } | Select -First 1
+
+if (!$vmSwitchExtensionObject){
+	throw [System.Management.Automation.ItemNotFoundException]"Switch extension does not exist - {{.Name}}"
+}
+
+$enabled = ${{.Enabled}}
+if ($enabled -and !$vmSwitchExtensionObject.Enabled){
+	Enable-VMSwitchExtension -VMSwitch $vmSwitchObject -Name $vmSwitchExtensionObject.Name
+} elseif (!$enabled -and $vmSwitchExtensionObject.Enabled){
+	Disable-VMSwitchExtension -VMSwitch $vmSwitchObject -Name $vmSwitchExtensionObject.Name
+}
+`))
+
+func (c *ClientConfig) UpdateVMSwitchExtension(ctx context.Context, switchName string, name string, enabled bool) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, updateVMSwitchExtensionTemplate, updateVMSwitchExtensionArgs{
+		SwitchName: switchName,
+		Name:       name,
+		Enabled:    enabled,
+	})
+
+	return err
+}
@@ -0,0 +1,149 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// getVmSwitchAddresses reads the IPv4 addresses of the host network adapter of the switch $switchName into
+// $switchAddresses
+const getVmSwitchAddresses = `
+$switchAddresses = @(Get-NetIPAddress -InterfaceAlias "vEthernet ($switchName)" -AddressFamily IPv4 -ErrorAction SilentlyContinue | %{ "$($_.IPAddress)" })
+`
+
+type createOrUpdateVmSwitchNatDnsForwardingArgs struct {
+	VmSwitchNatDnsForwardingJson string
+}
+
+var createOrUpdateVmSwitchNatDnsForwardingTemplate = template.Must(template.New("CreateOrUpdateVmSwitchNatDnsForwarding").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmSwitchNatDnsForwarding = '{{.VmSwitchNatDnsForwardingJson}}' | ConvertFrom-Json
+$switchName = $vmSwitchNatDnsForwarding.SwitchName
+` + getVmSwitchAddresses + `
+if ($switchAddresses.Length -eq 0) {
+	throw "The host network adapter of switch $switchName has no IPv4 address to answer DNS queries on"
+}
+
+$forwarders = @($vmSwitchNatDnsForwarding.Forwarders | ?{ $_ })
+if ($forwarders.Length -eq 0) {
+	# like internet connection sharing, forward to the DNS servers the host uses itself
+	$forwarders = @(Get-DnsClientServerAddress -AddressFamily IPv4 | %{ $_.ServerAddresses } | ?{ $_ -and $_ -notlike '127.*' -and $switchAddresses -notcontains $_ } | Select -Unique)
+}
+if ($forwarders.Length -eq 0) {
+	throw "The HyperV host machine has no DNS servers to forward the DNS queries of switch $switchName to"
+}
+
+if (!(Get-Command Get-DnsServerSetting -ErrorAction SilentlyContinue)) {
+	Install-WindowsFeature -Name DNS -IncludeManagementTools | Out-Null
+	$dnsServerSetting = Get-DnsServerSetting -All
+	# a new DNS server listens on all the addresses of the host, it should only answer the guests
+	$dnsServerSetting.ListeningIPAddress = @()
+} else {
+	$dnsServerSetting = Get-DnsServerSetting -All
+}
+
+Set-DnsServerForwarder -IPAddress $forwarders -Timeout $vmSwitchNatDnsForwarding.ForwardingTimeout -UseRootHint $true | Out-Null
+
+$listenAddresses = @($dnsServerSetting.ListeningIPAddress | %{ "$_" })
+$missingSwitchAddresses = @($switchAddresses | ?{ $listenAddresses -notcontains $_ })
+if ($missingSwitchAddresses.Length -gt 0) {
+	$dnsServerSetting.ListeningIPAddress = [string[]]($listenAddresses + $missingSwitchAddresses)
+	Set-DnsServerSetting -InputObject $dnsServerSetting | Out-Null
+	Restart-Service DNS
+}
+`))
+
+func (c *ClientConfig) CreateOrUpdateVmSwitchNatDnsForwarding(ctx context.Context, vmSwitchNatDnsForwarding api.VmSwitchNatDnsForwarding) (err error) {
+	vmSwitchNatDnsForwardingJson, err := json.Marshal(vmSwitchNatDnsForwarding)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVmSwitchNatDnsForwardingTemplate, createOrUpdateVmSwitchNatDnsForwardingArgs{
+		VmSwitchNatDnsForwardingJson: string(vmSwitchNatDnsForwardingJson),
+	})
+
+	return err
+}
+
+type getVmSwitchNatDnsForwardingArgs struct {
+	SwitchName string
+}
+
+var getVmSwitchNatDnsForwardingTemplate = template.Must(template.New("GetVmSwitchNatDnsForwarding").Parse(`
+$ErrorActionPreference = 'Stop'
+$switchName = '{{.SwitchName}}'
+
+$vmSwitchNatDnsForwardingObject = $null
+if (Get-Command Get-DnsServerSetting -ErrorAction SilentlyContinue) {
+` + getVmSwitchAddresses + `
+	if ($switchAddresses.Length -gt 0) {
+		$dnsServerSetting = Get-DnsServerSetting -All
+		$dnsServerForwarder = Get-DnsServerForwarder
+
+		$vmSwitchNatDnsForwardingObject = @{
+			SwitchName=$switchName;
+			SwitchAddresses=$switchAddresses;
+			ListenAddresses=@($dnsServerSetting.ListeningIPAddress | %{ "$_" });
+			Forwarders=@($dnsServerForwarder.IPAddress | %{ "$_" });
+			ForwardingTimeout=[int]$dnsServerForwarder.Timeout;
+		}
+	}
+}
+
+if ($vmSwitchNatDnsForwardingObject) {
+	$vmSwitchNatDnsForwarding = ConvertTo-Json -InputObject $vmSwitchNatDnsForwardingObject
+	$vmSwitchNatDnsForwarding
+} else {
+	"{}"
+}
+`))
+
+func (c *ClientConfig) GetVmSwitchNatDnsForwarding(ctx context.Context, switchName string) (result api.VmSwitchNatDnsForwarding, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmSwitchNatDnsForwardingTemplate, getVmSwitchNatDnsForwardingArgs{
+		SwitchName: switchName,
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmSwitchNatDnsForwardingArgs struct {
+	SwitchName string
+}
+
+// the forwarders are left in place as the DNS server may answer the queries of other switches. A DNS server without
+// listen addresses listens on all the addresses of the host, so it is left listening on the loopback address instead.
+var deleteVmSwitchNatDnsForwardingTemplate = template.Must(template.New("DeleteVmSwitchNatDnsForwarding").Parse(`
+$ErrorActionPreference = 'Stop'
+$switchName = '{{.SwitchName}}'
+
+if (!(Get-Command Get-DnsServerSetting -ErrorAction SilentlyContinue)) {
+	return
+}
+` + getVmSwitchAddresses + `
+$dnsServerSetting = Get-DnsServerSetting -All
+$listenAddresses = @($dnsServerSetting.ListeningIPAddress | %{ "$_" })
+$remainingListenAddresses = @($listenAddresses | ?{ $switchAddresses -notcontains $_ })
+
+if ($listenAddresses.Length -gt 0 -and $remainingListenAddresses.Length -ne $listenAddresses.Length) {
+	if ($remainingListenAddresses.Length -eq 0) {
+		$remainingListenAddresses = @('127.0.0.1')
+	}
+
+	$dnsServerSetting.ListeningIPAddress = [string[]]$remainingListenAddresses
+	Set-DnsServerSetting -InputObject $dnsServerSetting | Out-Null
+	Restart-Service DNS
+}
+`))
+
+func (c *ClientConfig) DeleteVmSwitchNatDnsForwarding(ctx context.Context, switchName string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmSwitchNatDnsForwardingTemplate, deleteVmSwitchNatDnsForwardingArgs{
+		SwitchName: switchName,
+	})
+
+	return err
+}
@@ -0,0 +1,178 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// getVmTpmStateBackupPaths reads the guardian of the HyperV host machine into $guardian and the paths of the pfx files
+// its certificates are exported to in $guardianPath into $guardianSigningPath and $guardianEncryptionPath. A host that
+// is not guarded by a host guardian service protects the virtual TPMs with the local UntrustedGuardian.
+const getVmTpmStateBackupPaths = `
+$guardian = $null
+if (Get-Command Get-HgsGuardian -ErrorAction SilentlyContinue) {
+	$guardian = Get-HgsGuardian -Name 'UntrustedGuardian' -ErrorAction SilentlyContinue
+}
+$guardianSigningPath = ''
+$guardianEncryptionPath = ''
+if ($guardian -and $guardianPath) {
+	$guardianSigningPath = Join-Path $guardianPath "$($guardian.Name)-signing.pfx"
+	$guardianEncryptionPath = Join-Path $guardianPath "$($guardian.Name)-encryption.pfx"
+}
+`
+
+// getKeyProtectorHash is a function that returns the sha256 hash of a key protector, so it can be compared without
+// being sent back
+const getKeyProtectorHash = `
+function Get-KeyProtectorHash {
+	param([byte[]]$KeyProtector)
+
+	$sha256 = [System.Security.Cryptography.SHA256]::Create()
+	try {
+		return [BitConverter]::ToString($sha256.ComputeHash($KeyProtector)) -replace '-', ''
+	} finally {
+		$sha256.Dispose()
+	}
+}
+`
+
+type createOrUpdateVmTpmStateBackupArgs struct {
+	SettingsJson           string
+	GuardianPasswordBase64 string `sensitive:"true"`
+}
+
+var createOrUpdateVmTpmStateBackupTemplate = template.Must(template.New("CreateOrUpdateVmTpmStateBackup").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+$settings = '{{.SettingsJson}}' | ConvertFrom-Json
+$guardianPath = $settings.GuardianPath
+` + getVmTpmStateBackupPaths + `
+$vmObject = Get-VM -Name "$($settings.VmName)*" | ?{$_.Name -eq $settings.VmName}
+
+if (!$vmObject){
+	throw [System.Management.Automation.ItemNotFoundException]"VM does not exist - $($settings.VmName)"
+}
+
+if (!(Get-VMSecurity -VM $vmObject).TpmEnabled) {
+	throw "VM $($settings.VmName) has no virtual TPM, so it has no key protector to back up"
+}
+
+$keyProtector = [byte[]](Get-VMKeyProtector -VM $vmObject)
+$pathDirectory = [System.IO.Path]::GetDirectoryName($settings.Path)
+if (!(Test-Path $pathDirectory)) {
+	New-Item -ItemType Directory -Force -Path $pathDirectory | Out-Null
+}
+[System.IO.File]::WriteAllBytes($settings.Path, $keyProtector)
+
+if ($guardianPath) {
+	if (!$guardian) {
+		throw "The HyperV host machine has no UntrustedGuardian to export, the virtual TPM of VM $($settings.VmName) is protected by a host guardian service"
+	}
+
+	if (!(Test-Path $guardianPath)) {
+		New-Item -ItemType Directory -Force -Path $guardianPath | Out-Null
+	}
+
+	$guardianPassword = ConvertTo-SecureString -String ([System.Text.Encoding]::UTF8.GetString([Convert]::FromBase64String('{{.GuardianPasswordBase64}}'))) -AsPlainText -Force
+	Export-PfxCertificate -Cert "Cert:\LocalMachine\Shielded VM Local Certificates\$($guardian.SigningCertificate.Thumbprint)" -FilePath $guardianSigningPath -Password $guardianPassword -Force | Out-Null
+	Export-PfxCertificate -Cert "Cert:\LocalMachine\Shielded VM Local Certificates\$($guardian.EncryptionCertificate.Thumbprint)" -FilePath $guardianEncryptionPath -Password $guardianPassword -Force | Out-Null
+}
+`))
+
+func (c *ClientConfig) CreateOrUpdateVmTpmStateBackup(ctx context.Context, settings api.VmTpmStateBackupSettings) (err error) {
+	// the password is passed separately, so that it is left out of the logged script
+	guardianPasswordBase64 := base64.StdEncoding.EncodeToString([]byte(settings.GuardianPassword))
+	settings.GuardianPassword = ""
+
+	settingsJson, err := json.Marshal(settings)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createOrUpdateVmTpmStateBackupTemplate, createOrUpdateVmTpmStateBackupArgs{
+		SettingsJson:           string(settingsJson),
+		GuardianPasswordBase64: guardianPasswordBase64,
+	})
+
+	return err
+}
+
+type getVmTpmStateBackupArgs struct {
+	VmName       string
+	Path         string
+	GuardianPath string
+}
+
+var getVmTpmStateBackupTemplate = template.Must(template.New("GetVmTpmStateBackup").Parse(`
+$ErrorActionPreference = 'Stop'
+$path = '{{.Path}}'
+$guardianPath = '{{.GuardianPath}}'
+` + getVmTpmStateBackupPaths + getKeyProtectorHash + `
+$tpmEnabled = $false
+$keyProtectorHash = ''
+$vmObject = Get-VM -Name '{{.VmName}}*' -ErrorAction SilentlyContinue | ?{$_.Name -eq '{{.VmName}}' }
+if ($vmObject) {
+	$tpmEnabled = [bool](Get-VMSecurity -VM $vmObject).TpmEnabled
+	if ($tpmEnabled) {
+		$keyProtectorHash = Get-KeyProtectorHash -KeyProtector ([byte[]](Get-VMKeyProtector -VM $vmObject))
+	}
+}
+
+$backupKeyProtectorHash = ''
+if (Test-Path $path) {
+	$backupKeyProtectorHash = Get-KeyProtectorHash -KeyProtector ([System.IO.File]::ReadAllBytes($path))
+}
+
+$vmTpmStateBackupObject = @{
+	VmName='{{.VmName}}';
+	TpmEnabled=$tpmEnabled;
+	KeyProtectorHash=$keyProtectorHash;
+	BackupKeyProtectorHash=$backupKeyProtectorHash;
+	GuardianName="$($guardian.Name)";
+	GuardianSigningThumbprint="$($guardian.SigningCertificate.Thumbprint)";
+	GuardianEncryptionThumbprint="$($guardian.EncryptionCertificate.Thumbprint)";
+	GuardianBackupExists=[bool]($guardianSigningPath -and (Test-Path $guardianSigningPath) -and (Test-Path $guardianEncryptionPath));
+}
+
+$vmTpmStateBackup = ConvertTo-Json -InputObject $vmTpmStateBackupObject
+$vmTpmStateBackup
+`))
+
+func (c *ClientConfig) GetVmTpmStateBackup(ctx context.Context, vmName string, path string, guardianPath string) (result api.VmTpmStateBackup, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmTpmStateBackupTemplate, getVmTpmStateBackupArgs{
+		VmName:       vmName,
+		Path:         path,
+		GuardianPath: guardianPath,
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmTpmStateBackupArgs struct {
+	Path         string
+	GuardianPath string
+}
+
+var deleteVmTpmStateBackupTemplate = template.Must(template.New("DeleteVmTpmStateBackup").Parse(`
+$ErrorActionPreference = 'Stop'
+$path = '{{.Path}}'
+$guardianPath = '{{.GuardianPath}}'
+` + getVmTpmStateBackupPaths + `
+@($path, $guardianSigningPath, $guardianEncryptionPath) | ?{ $_ -and (Test-Path $_) } | %{
+	Remove-Item $_ -Force
+}
+`))
+
+func (c *ClientConfig) DeleteVmTpmStateBackup(ctx context.Context, path string, guardianPath string) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmTpmStateBackupTemplate, deleteVmTpmStateBackupArgs{
+		Path:         path,
+		GuardianPath: guardianPath,
+	})
+
+	return err
+}
@@ -0,0 +1,322 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"encoding/json"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+type createVmwareMigrationArgs struct {
+	SettingsJson  string
+	WorkspacePath string
+}
+
+var createVmwareMigrationTemplate = template.Must(template.New("CreateVmwareMigration").Parse(`
+$ErrorActionPreference = 'Stop'
+$settings = '{{.SettingsJson}}' | ConvertFrom-Json
+$ovfNamespace = 'http://schemas.dmtf.org/ovf/envelope/1'
+
+function Get-TarPath {
+	if (Get-Command "tar" -ErrorAction SilentlyContinue) {
+		return "tar"
+	} elseif (test-path "$env:SystemRoot\system32\tar.exe") {
+		return "$env:SystemRoot\system32\tar.exe"
+	} else {
+		return ""
+	}
+}
+
+function Get-ConverterPath {
+	if ($settings.ConverterPath) {
+		if (!(Test-Path $settings.ConverterPath)) {
+			throw [System.Management.Automation.ItemNotFoundException]"Converter does not exist - $($settings.ConverterPath)"
+		}
+		return @{ Converter=$settings.Converter; Path=$settings.ConverterPath }
+	}
+
+	if ($settings.Converter -ne 'StarWind') {
+		$qemuImg = Get-Command "qemu-img" -ErrorAction SilentlyContinue
+		if ($qemuImg) {
+			return @{ Converter='QemuImg'; Path=$qemuImg.Source }
+		} elseif (Test-Path "$env:ProgramFiles\qemu\qemu-img.exe") {
+			return @{ Converter='QemuImg'; Path="$env:ProgramFiles\qemu\qemu-img.exe" }
+		}
+	}
+
+	if ($settings.Converter -ne 'QemuImg') {
+		$starWind = "$env:ProgramFiles\StarWind Software\StarWind V2V Converter\V2V_ConverterConsole.exe"
+		if (Test-Path $starWind) {
+			return @{ Converter='StarWind'; Path=$starWind }
+		}
+	}
+
+	throw "Neither qemu-img nor StarWind V2V Converter is installed on the HyperV host machine to convert $($settings.SourcePath)"
+}
+
+function Get-OvfItemValue {
+	param($Item, [string]$Name)
+
+	$node = $Item.SelectSingleNode("*[local-name()='$Name']")
+	if ($node) {
+		return $node.InnerText
+	}
+	return ''
+}
+
+# the settings of the virtual machine are read from the ovf descriptor of an ova or ovf, and from the vmx next to a vmdk
+function Read-VmwareSource {
+	param([string]$SourcePath)
+
+	$source = @{
+		Name=[System.IO.Path]::GetFileNameWithoutExtension($SourcePath);
+		Firmware='Bios';
+		ProcessorCount=[int64]1;
+		MemoryStartupBytes=[int64]536870912;
+		NetworkAdapterNames=@();
+		DiskPaths=@();
+	}
+	$sourceFolder = [System.IO.Path]::GetDirectoryName($SourcePath)
+
+	if ([System.IO.Path]::GetExtension($SourcePath) -eq '.ovf') {
+		[xml]$ovf = Get-Content -LiteralPath $SourcePath -Raw
+
+		$virtualSystem = $ovf.SelectSingleNode("//*[local-name()='VirtualSystem']")
+		if ($virtualSystem.GetAttribute('id', $ovfNamespace)) {
+			$source.Name = $virtualSystem.GetAttribute('id', $ovfNamespace)
+		}
+
+		$files = @{}
+		foreach ($file in $ovf.SelectNodes("//*[local-name()='References']/*[local-name()='File']")) {
+			$files[$file.GetAttribute('id', $ovfNamespace)] = $file.GetAttribute('href', $ovfNamespace)
+		}
+		$disks = @{}
+		foreach ($disk in $ovf.SelectNodes("//*[local-name()='DiskSection']/*[local-name()='Disk']")) {
+			$disks["ovf:/disk/$($disk.GetAttribute('diskId', $ovfNamespace))"] = $files[$disk.GetAttribute('fileRef', $ovfNamespace)]
+		}
+
+		foreach ($item in $ovf.SelectNodes("//*[local-name()='VirtualHardwareSection']/*[local-name()='Item']")) {
+			switch (Get-OvfItemValue -Item $item -Name 'ResourceType') {
+				'3' {
+					$source.ProcessorCount = [int64](Get-OvfItemValue -Item $item -Name 'VirtualQuantity')
+				}
+				'4' {
+					$memoryUnitBytes = [int64]1048576
+					if ((Get-OvfItemValue -Item $item -Name 'AllocationUnits') -match '2\^(\d+)') {
+						$memoryUnitBytes = [int64][Math]::Pow(2, [int]$Matches[1])
+					}
+					$source.MemoryStartupBytes = [int64](Get-OvfItemValue -Item $item -Name 'VirtualQuantity') * $memoryUnitBytes
+				}
+				'10' {
+					$source.NetworkAdapterNames += Get-OvfItemValue -Item $item -Name 'ElementName'
+				}
+				'17' {
+					$diskFile = $disks[(Get-OvfItemValue -Item $item -Name 'HostResource')]
+					if ($diskFile) {
+						$source.DiskPaths += Join-Path $sourceFolder $diskFile
+					}
+				}
+			}
+		}
+
+		if ($ovf.SelectSingleNode("//*[local-name()='Config'][@*[local-name()='key']='firmware'][@*[local-name()='value']='efi']")) {
+			$source.Firmware = 'Uefi'
+		}
+	} else {
+		$source.DiskPaths += $SourcePath
+
+		$vmxPath = [System.IO.Path]::ChangeExtension($SourcePath, '.vmx')
+		if (Test-Path $vmxPath) {
+			$vmx = @{}
+			foreach ($line in Get-Content -LiteralPath $vmxPath) {
+				if ($line -match '^\s*([^=\s]+)\s*=\s*"(.*)"\s*$') {
+					$vmx[$Matches[1].ToLower()] = $Matches[2]
+				}
+			}
+
+			if ($vmx['displayname']) {
+				$source.Name = $vmx['displayname']
+			}
+			if ($vmx['numvcpus']) {
+				$source.ProcessorCount = [int64]$vmx['numvcpus']
+			}
+			if ($vmx['memsize']) {
+				$source.MemoryStartupBytes = [int64]$vmx['memsize'] * 1048576
+			}
+			if ($vmx['firmware'] -eq 'efi') {
+				$source.Firmware = 'Uefi'
+			}
+			foreach ($key in $vmx.Keys | ?{ $_ -match '^ethernet\d+\.present$' -and $vmx[$_] -eq 'TRUE' } | Sort-Object) {
+				$source.NetworkAdapterNames += "Network Adapter $($source.NetworkAdapterNames.Count + 1)"
+			}
+		}
+	}
+
+	if ($source.DiskPaths.Count -eq 0) {
+		throw "VMware virtual machine has no disks to convert - $SourcePath"
+	}
+
+	return $source
+}
+
+function Convert-VmwareDisk {
+	param($Converter, [string]$SourceDiskPath, [string]$DestinationDiskPath)
+
+	if ($Converter.Converter -eq 'StarWind') {
+		& $Converter.Path convert in_file_name="$SourceDiskPath" out_file_name="$DestinationDiskPath" out_file_type=ft_vhdx_growable
+	} else {
+		& $Converter.Path convert -O vhdx -o subformat=dynamic "$SourceDiskPath" "$DestinationDiskPath"
+	}
+
+	if ($LASTEXITCODE -ne 0 -or !(Test-Path $DestinationDiskPath)) {
+		throw "Unable to convert $SourceDiskPath to $($DestinationDiskPath) with $($Converter.Converter): $LASTEXITCODE"
+	}
+}
+
+if (!(Test-Path $settings.SourcePath)) {
+	throw [System.Management.Automation.ItemNotFoundException]"VMware virtual machine does not exist - $($settings.SourcePath)"
+}
+
+if (!(Test-Path $settings.DestinationPath)) {
+	New-Item -ItemType Directory -Force -Path $settings.DestinationPath | Out-Null
+}
+
+$converter = Get-ConverterPath
+$extractPath = ''
+$hardDiskPaths = @()
+try {
+	$sourcePath = $settings.SourcePath
+
+	# an ova is a tar of the ovf descriptor and the disks, so it is extracted to the workspace first
+	if ([System.IO.Path]::GetExtension($sourcePath) -eq '.ova') {
+		$tarPath = Get-TarPath
+		if (-not $tarPath) {
+			throw "tar.exe is not available on $($env:COMPUTERNAME) to extract $($settings.SourcePath)"
+		}
+
+		$extractPath = Join-Path '{{.WorkspacePath}}' ([System.Guid]::NewGuid().ToString())
+		New-Item -ItemType Directory -Force -Path $extractPath | Out-Null
+		& $tarPath -xf "$sourcePath" -C "$extractPath"
+		if ($LASTEXITCODE -ne 0) {
+			throw "Unable to extract $($sourcePath): $LASTEXITCODE"
+		}
+
+		$sourcePath = (Get-ChildItem -Path $extractPath -Filter *.ovf | Select-Object -First 1).FullName
+		if (!$sourcePath) {
+			throw "Ova does not contain an ovf descriptor - $($settings.SourcePath)"
+		}
+	}
+
+	$source = Read-VmwareSource -SourcePath $sourcePath
+
+	foreach ($diskPath in $source.DiskPaths) {
+		$hardDiskPath = Join-Path $settings.DestinationPath "$([System.IO.Path]::GetFileNameWithoutExtension($diskPath)).vhdx"
+		if (Test-Path $hardDiskPath) {
+			throw "A virtual hard disk already exists at $hardDiskPath - move it or choose another destination path"
+		}
+
+		# the disk is recorded before it is converted, so that a conversion that fails partway does not leave it behind
+		$hardDiskPaths += $hardDiskPath
+		Convert-VmwareDisk -Converter $converter -SourceDiskPath $diskPath -DestinationDiskPath $hardDiskPath
+	}
+} catch {
+	# disks that were converted, or partly converted, before the failure are removed, so that the migration can be retried
+	$hardDiskPaths | ?{ Test-Path $_ } | %{ Remove-Item $_ -Force }
+	throw
+} finally {
+	if ($extractPath -and (Test-Path $extractPath)) {
+		Remove-Item $extractPath -Force -Recurse
+	}
+}
+
+$vmwareMigrationObject = @{
+	Name=$source.Name;
+	Firmware=$source.Firmware;
+	ProcessorCount=$source.ProcessorCount;
+	MemoryStartupBytes=$source.MemoryStartupBytes;
+	NetworkAdapterNames=@($source.NetworkAdapterNames);
+	HardDiskPaths=@($hardDiskPaths);
+}
+
+$vmwareMigration = ConvertTo-Json -InputObject $vmwareMigrationObject
+$vmwareMigration
+`))
+
+func (c *ClientConfig) CreateVmwareMigration(ctx context.Context, settings api.VmwareMigrationSettings) (result api.VmwareMigration, err error) {
+	settingsJson, err := json.Marshal(struct {
+		SourcePath      string
+		DestinationPath string
+		Converter       string
+		ConverterPath   string
+	}{
+		SourcePath:      settings.SourcePath,
+		DestinationPath: settings.DestinationPath,
+		Converter:       settings.Converter.String(),
+		ConverterPath:   settings.ConverterPath,
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, createVmwareMigrationTemplate, createVmwareMigrationArgs{
+		SettingsJson:  string(settingsJson),
+		WorkspacePath: c.WorkspacePath(),
+	}, &result)
+
+	return result, err
+}
+
+type getVmwareMigrationHardDiskPathsArgs struct {
+	HardDiskPathsJson string
+}
+
+var getVmwareMigrationHardDiskPathsTemplate = template.Must(template.New("GetVmwareMigrationHardDiskPaths").Parse(`
+$ErrorActionPreference = 'Stop'
+$hardDiskPaths = '{{.HardDiskPathsJson}}' | ConvertFrom-Json
+
+$existingHardDiskPaths = ConvertTo-Json -InputObject @($hardDiskPaths | ?{ Test-Path $_ })
+$existingHardDiskPaths
+`))
+
+func (c *ClientConfig) GetVmwareMigrationHardDiskPaths(ctx context.Context, hardDiskPaths []string) (result []string, err error) {
+	hardDiskPathsJson, err := json.Marshal(hardDiskPaths)
+
+	if err != nil {
+		return result, err
+	}
+
+	err = c.WinRmClient.RunScriptWithResult(ctx, getVmwareMigrationHardDiskPathsTemplate, getVmwareMigrationHardDiskPathsArgs{
+		HardDiskPathsJson: string(hardDiskPathsJson),
+	}, &result)
+
+	return result, err
+}
+
+type deleteVmwareMigrationArgs struct {
+	HardDiskPathsJson string
+}
+
+var deleteVmwareMigrationTemplate = template.Must(template.New("DeleteVmwareMigration").Parse(`
+$ErrorActionPreference = 'Stop'
+$hardDiskPaths = '{{.HardDiskPathsJson}}' | ConvertFrom-Json
+
+$hardDiskPaths | ?{ Test-Path $_ } | %{
+	Remove-Item $_ -Force
+}
+`))
+
+func (c *ClientConfig) DeleteVmwareMigration(ctx context.Context, hardDiskPaths []string) (err error) {
+	hardDiskPathsJson, err := json.Marshal(hardDiskPaths)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteVmwareMigrationTemplate, deleteVmwareMigrationArgs{
+		HardDiskPathsJson: string(hardDiskPathsJson),
+	})
+
+	return err
+}
@@ -0,0 +1,255 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"text/template"
+	"time"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// getWindowsFeature writes the feature $name as json, or "{}" when there is no such feature. Windows Server has the
+// ServerManager cmdlets for its roles and features, a Windows client only has optional features.
+const getWindowsFeature = `
+$windowsFeatureObject = $null
+if (Get-Command Get-WindowsFeature -ErrorAction SilentlyContinue) {
+	$windowsFeature = Get-WindowsFeature -Name $name | Select -First 1
+	if ($windowsFeature) {
+		$windowsFeatureObject = @{
+			Name=$windowsFeature.Name;
+			DisplayName=$windowsFeature.DisplayName;
+			InstallState="$($windowsFeature.InstallState)";
+			RestartNeeded=$restartNeeded;
+		}
+	}
+} else {
+	$windowsFeature = Get-WindowsOptionalFeature -Online -FeatureName $name -ErrorAction SilentlyContinue | Select -First 1
+	if ($windowsFeature) {
+		$installState = switch ("$($windowsFeature.State)") {
+			'Enabled' { 'Installed' }
+			'EnablePending' { 'InstallPending' }
+			'DisablePending' { 'UninstallPending' }
+			'DisabledWithPayloadRemoved' { 'Removed' }
+			default { 'Available' }
+		}
+
+		$windowsFeatureObject = @{
+			Name=$windowsFeature.FeatureName;
+			DisplayName=$windowsFeature.DisplayName;
+			InstallState=$installState;
+			RestartNeeded=$restartNeeded;
+		}
+	}
+}
+
+if ($windowsFeatureObject) {
+	$windowsFeature = ConvertTo-Json -InputObject $windowsFeatureObject
+	$windowsFeature
+} else {
+	"{}"
+}
+`
+
+type installWindowsFeatureArgs struct {
+	Name                   string
+	IncludeAllSubFeatures  bool
+	IncludeManagementTools bool
+	Source                 string
+}
+
+var installWindowsFeatureTemplate = template.Must(template.New("InstallWindowsFeature").Parse(`
+$ErrorActionPreference = 'Stop'
+$name = '{{.Name}}'
+$source = '{{.Source}}'
+
+if (Get-Command Install-WindowsFeature -ErrorAction SilentlyContinue) {
+	$installWindowsFeatureArgs = @{
+		Name=$name;
+		IncludeAllSubFeature=${{.IncludeAllSubFeatures}};
+		IncludeManagementTools=${{.IncludeManagementTools}};
+	}
+	if ($source) {
+		$installWindowsFeatureArgs.Source = $source
+	}
+
+	$result = Install-WindowsFeature @installWindowsFeatureArgs
+	if (!$result.Success) {
+		throw "Unable to install windows feature $($name): $($result.ExitCode)"
+	}
+	$restartNeeded = "$($result.RestartNeeded)" -ne 'No'
+} else {
+	#a Windows client has no management tools to add, -All enables the features the optional feature depends on
+	$enableWindowsOptionalFeatureArgs = @{
+		Online=$true;
+		FeatureName=$name;
+		All=${{.IncludeAllSubFeatures}};
+		NoRestart=$true;
+	}
+	if ($source) {
+		$enableWindowsOptionalFeatureArgs.Source = $source
+		$enableWindowsOptionalFeatureArgs.LimitAccess = $true
+	}
+
+	$result = Enable-WindowsOptionalFeature @enableWindowsOptionalFeatureArgs
+	$restartNeeded = [bool]$result.RestartNeeded
+}
+` + getWindowsFeature + `
+`))
+
+func (c *ClientConfig) InstallWindowsFeature(ctx context.Context, name string, includeAllSubFeatures bool, includeManagementTools bool, source string) (result api.WindowsFeature, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, installWindowsFeatureTemplate, installWindowsFeatureArgs{
+		Name:                   name,
+		IncludeAllSubFeatures:  includeAllSubFeatures,
+		IncludeManagementTools: includeManagementTools,
+		Source:                 source,
+	}, &result)
+
+	if err != nil {
+		return result, err
+	}
+
+	// installing the Hyper-V role or its management tools changes what the host supports
+	c.InvalidateHostCapabilities()
+
+	return result, nil
+}
+
+type getWindowsFeatureArgs struct {
+	Name string
+}
+
+var getWindowsFeatureTemplate = template.Must(template.New("GetWindowsFeature").Parse(`
+$ErrorActionPreference = 'Stop'
+$name = '{{.Name}}'
+$restartNeeded = $false
+` + getWindowsFeature + `
+`))
+
+func (c *ClientConfig) GetWindowsFeature(ctx context.Context, name string) (result api.WindowsFeature, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, getWindowsFeatureTemplate, getWindowsFeatureArgs{
+		Name: name,
+	}, &result)
+
+	return result, err
+}
+
+type uninstallWindowsFeatureArgs struct {
+	Name string
+}
+
+var uninstallWindowsFeatureTemplate = template.Must(template.New("UninstallWindowsFeature").Parse(`
+$ErrorActionPreference = 'Stop'
+$name = '{{.Name}}'
+
+if (Get-Command Uninstall-WindowsFeature -ErrorAction SilentlyContinue) {
+	$result = Uninstall-WindowsFeature -Name $name
+	if (!$result.Success) {
+		throw "Unable to uninstall windows feature $($name): $($result.ExitCode)"
+	}
+	$restartNeeded = "$($result.RestartNeeded)" -ne 'No'
+} else {
+	$result = Disable-WindowsOptionalFeature -Online -FeatureName $name -NoRestart
+	$restartNeeded = [bool]$result.RestartNeeded
+}
+` + getWindowsFeature + `
+`))
+
+func (c *ClientConfig) UninstallWindowsFeature(ctx context.Context, name string) (result api.WindowsFeature, err error) {
+	err = c.WinRmClient.RunScriptWithResult(ctx, uninstallWindowsFeatureTemplate, uninstallWindowsFeatureArgs{
+		Name: name,
+	}, &result)
+
+	if err != nil {
+		return result, err
+	}
+
+	c.InvalidateHostCapabilities()
+
+	return result, nil
+}
+
+type hostBoot struct {
+	LastBootUpTime string
+	Ready          bool
+	RestartPending bool
+}
+
+type getHostBootArgs struct {
+}
+
+// the host is ready once the Hyper-V virtual machine management service is running again, when it is installed. A
+// restart is pending when a shutdown was initiated since the host booted (event 1074) and it was not aborted (event
+// 1075), e.g. by a windows feature change in another provider run.
+var getHostBootTemplate = template.Must(template.New("GetHostBoot").Parse(`
+$ErrorActionPreference = 'Stop'
+$vmmsService = Get-Service -Name vmms -ErrorAction SilentlyContinue
+$lastBootUpTime = (Get-CimInstance -ClassName Win32_OperatingSystem).LastBootUpTime
+$lastShutdownEvent = Get-WinEvent -FilterHashtable @{LogName='System'; ProviderName='User32'; Id=@(1074, 1075); StartTime=$lastBootUpTime} -MaxEvents 1 -ErrorAction SilentlyContinue
+
+$hostBootObject = @{
+	LastBootUpTime=$lastBootUpTime.ToUniversalTime().ToString('o');
+	Ready=!$vmmsService -or "$($vmmsService.Status)" -eq 'Running';
+	RestartPending=$lastShutdownEvent -and $lastShutdownEvent.Id -eq 1074;
+}
+
+$hostBoot = ConvertTo-Json -InputObject $hostBootObject
+$hostBoot
+`))
+
+type restartHostArgs struct {
+}
+
+// shutdown.exe restarts the host after a delay, so that the script returns before the connection is dropped. It fails
+// with ERROR_SHUTDOWN_IS_SCHEDULED (1190) when a shutdown is already scheduled, which restarts the host as well.
+var restartHostTemplate = template.Must(template.New("RestartHost").Parse(`
+$ErrorActionPreference = 'Stop'
+& shutdown.exe /r /t 10 /d p:2:4 /c "Restart by terraform-provider-hyperv to complete a windows feature change"
+if ($LASTEXITCODE -eq 1190) {
+	Write-Warning "A shutdown of $($env:COMPUTERNAME) is already scheduled"
+} elseif ($LASTEXITCODE -ne 0) {
+	throw "Unable to restart $($env:COMPUTERNAME): $LASTEXITCODE"
+}
+`))
+
+func (c *ClientConfig) RestartHost(ctx context.Context, timeout uint32, pollPeriod uint32) (err error) {
+	var bootBeforeRestart hostBoot
+	err = c.WinRmClient.RunScriptWithResult(ctx, getHostBootTemplate, getHostBootArgs{}, &bootBeforeRestart)
+	if err != nil {
+		return err
+	}
+
+	if bootBeforeRestart.RestartPending {
+		log.Printf("[INFO][hyperv] HyperV host machine is already restarting, waiting for it instead of restarting it again")
+	} else {
+		err = c.WinRmClient.RunFireAndForgetScript(ctx, restartHostTemplate, restartHostArgs{})
+		if err != nil {
+			return err
+		}
+	}
+
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(pollPeriod) * time.Second):
+		}
+
+		// the host does not answer while it restarts, so errors are expected until it is back
+		var boot hostBoot
+		err = c.WinRmClient.RunScriptWithResult(ctx, getHostBootTemplate, getHostBootArgs{}, &boot)
+		if err != nil {
+			log.Printf("[DEBUG] Waiting for the HyperV host machine to restart: %s", err)
+		} else if boot.LastBootUpTime != bootBeforeRestart.LastBootUpTime && boot.Ready {
+			log.Printf("[INFO][hyperv] HyperV host machine restarted at %s", boot.LastBootUpTime)
+			c.InvalidateHostCapabilities()
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("[ERROR][hyperv] timed out after %d seconds waiting for the HyperV host machine to restart", timeout)
+		}
+	}
+}
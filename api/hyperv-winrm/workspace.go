@@ -0,0 +1,69 @@
+package hyperv_winrm
+
+import (
+	"context"
+	"text/template"
+	"time"
+)
+
+// StaleWorkspaceAge is how old the workspace of another provider run has to be before it is treated as leaked, e.g.
+// because the provider was killed, and removed when a new workspace is created.
+var StaleWorkspaceAge = 24 * time.Hour
+
+type createWorkspaceArgs struct {
+	WorkspaceRootPath        string
+	WorkspacePath            string
+	StaleWorkspaceAgeMinutes int
+}
+
+var createWorkspaceTemplate = template.Must(template.New("CreateWorkspace").Parse(`
+$ErrorActionPreference = 'Stop'
+$workspaceRootPath = '{{.WorkspaceRootPath}}'
+$workspacePath = '{{.WorkspacePath}}'
+
+if (Test-Path -Path $workspaceRootPath -PathType Container) {
+	$staleWorkspaceTime = (Get-Date).AddMinutes(-{{.StaleWorkspaceAgeMinutes}})
+	Get-ChildItem -Path $workspaceRootPath -Directory | ?{ $_.LastWriteTime -lt $staleWorkspaceTime } | %{
+		Remove-Item -LiteralPath $_.FullName -Force -Recurse -ErrorAction SilentlyContinue
+	}
+}
+
+New-Item -ItemType Directory -Force -Path $workspacePath | Out-Null
+`))
+
+func (c *ClientConfig) CreateWorkspace(ctx context.Context) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, createWorkspaceTemplate, createWorkspaceArgs{
+		WorkspaceRootPath:        c.WorkspaceRootPath,
+		WorkspacePath:            c.WorkspacePath(),
+		StaleWorkspaceAgeMinutes: int(StaleWorkspaceAge.Minutes()),
+	})
+
+	return err
+}
+
+type deleteWorkspaceArgs struct {
+	WorkspacePath string
+}
+
+var deleteWorkspaceTemplate = template.Must(template.New("DeleteWorkspace").Parse(`
+$ErrorActionPreference = 'Stop'
+$workspacePath = '{{.WorkspacePath}}'
+
+if (Test-Path -Path $workspacePath -PathType Container) {
+	Remove-Item -LiteralPath $workspacePath -Force -Recurse
+}
+`))
+
+func (c *ClientConfig) DeleteWorkspace(ctx context.Context) (err error) {
+	err = c.WinRmClient.RunFireAndForgetScript(ctx, deleteWorkspaceTemplate, deleteWorkspaceArgs{
+		WorkspacePath: c.WorkspacePath(),
+	})
+
+	return err
+}
+
+// WorkspacePath is the directory of this provider run on the HyperV host machine. Scripts create a uniquely named
+// directory inside of it for their temporary files and remove it when they are done.
+func (c *ClientConfig) WorkspacePath() string {
+	return c.WorkspaceRootPath + `\` + c.RunId
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// IsoCatalogIso is an iso file found in a directory of the HyperV host machine. Name is the file name without its
+// extension. LastWriteTime is in the round trip format, so that times compare as strings.
+type IsoCatalogIso struct {
+	Name          string
+	Path          string
+	Size          int64
+	Checksum      string
+	ChecksumType  string
+	LastWriteTime string
+}
+
+// MatchesIsoCatalogName is true when the iso is named after the logical name, i.e. its name is the logical name or
+// starts with it followed by a separator, so that `ubuntu-22.04` matches `ubuntu-22.04.3-live-server-amd64` but not
+// `ubuntu-22.040`
+func (i *IsoCatalogIso) MatchesIsoCatalogName(name string) bool {
+	if name == "" {
+		return true
+	}
+
+	lowerIsoName := strings.ToLower(i.Name)
+	lowerName := strings.ToLower(name)
+	if !strings.HasPrefix(lowerIsoName, lowerName) {
+		return false
+	}
+
+	if len(lowerIsoName) == len(lowerName) {
+		return true
+	}
+
+	return strings.ContainsRune("-_. ", rune(lowerIsoName[len(lowerName)]))
+}
+
+// SelectIsoCatalogIsos returns the isos named after the logical name, latest first. Isos are ordered by their names
+// with the numbers in them compared as numbers, so that `ubuntu-22.04.10` is later than `ubuntu-22.04.9`, and isos
+// with the same name by the time they were last written.
+func SelectIsoCatalogIsos(isos []IsoCatalogIso, name string) []IsoCatalogIso {
+	selectedIsos := make([]IsoCatalogIso, 0)
+	for _, iso := range isos {
+		if iso.MatchesIsoCatalogName(name) {
+			selectedIsos = append(selectedIsos, iso)
+		}
+	}
+
+	sort.SliceStable(selectedIsos, func(i, j int) bool {
+		if compared := compareNaturally(selectedIsos[i].Name, selectedIsos[j].Name); compared != 0 {
+			return compared > 0
+		}
+
+		return selectedIsos[i].LastWriteTime > selectedIsos[j].LastWriteTime
+	})
+
+	return selectedIsos
+}
+
+// compareNaturally compares strings without case, comparing runs of digits as numbers
+func compareNaturally(a string, b string) int {
+	a = strings.ToLower(a)
+	b = strings.ToLower(b)
+
+	for a != "" && b != "" {
+		aChunk, aRest := splitNaturalChunk(a)
+		bChunk, bRest := splitNaturalChunk(b)
+
+		aNumber, aErr := strconv.ParseUint(aChunk, 10, 64)
+		bNumber, bErr := strconv.ParseUint(bChunk, 10, 64)
+		if aErr == nil && bErr == nil {
+			if aNumber != bNumber {
+				if aNumber > bNumber {
+					return 1
+				}
+				return -1
+			}
+		} else if aChunk != bChunk {
+			return strings.Compare(aChunk, bChunk)
+		}
+
+		a = aRest
+		b = bRest
+	}
+
+	return strings.Compare(a, b)
+}
+
+// splitNaturalChunk splits off the leading run of digits or of other characters
+func splitNaturalChunk(s string) (string, string) {
+	isDigit := unicode.IsDigit(rune(s[0]))
+	end := 1
+	for end < len(s) && unicode.IsDigit(rune(s[end])) == isDigit {
+		end++
+	}
+
+	return s[:end], s[end:]
+}
+
+func FlattenIsoCatalogIsos(isos []IsoCatalogIso) []interface{} {
+	flattenedIsos := make([]interface{}, 0)
+
+	for _, iso := range isos {
+		flattenedIso := make(map[string]interface{})
+		flattenedIso["name"] = iso.Name
+		flattenedIso["path"] = iso.Path
+		flattenedIso["size"] = iso.Size
+		flattenedIso["checksum"] = iso.Checksum
+		flattenedIso["checksum_type"] = iso.ChecksumType
+		flattenedIso["last_write_time"] = iso.LastWriteTime
+		flattenedIsos = append(flattenedIsos, flattenedIso)
+	}
+
+	return flattenedIsos
+}
+
+// HypervIsoCatalogClient finds the iso files in a directory of the HyperV host machine. Only the isos whose name
+// starts with namePrefix are returned, so that only their checksums are calculated.
+type HypervIsoCatalogClient interface {
+	GetIsoCatalog(ctx context.Context, directory string, recurse bool, namePrefix string, checksumType string) (result []IsoCatalogIso, err error)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestIsoCatalogIsoMatchesIsoCatalogName(t *testing.T) {
+	cases := []struct {
+		isoName  string
+		name     string
+		expected bool
+	}{
+		{"ubuntu-22.04.3-live-server-amd64", "ubuntu-22.04", true},
+		{"Ubuntu-22.04", "ubuntu-22.04", true},
+		{"ubuntu-22.040-live-server-amd64", "ubuntu-22.04", false},
+		{"ubuntu-20.04.6-live-server-amd64", "ubuntu-22.04", false},
+		{"windows_server_2022", "", true},
+	}
+
+	for _, c := range cases {
+		iso := IsoCatalogIso{Name: c.isoName}
+		if actual := iso.MatchesIsoCatalogName(c.name); actual != c.expected {
+			t.Errorf("Expected %s to match %q %t, got %t", c.isoName, c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestSelectIsoCatalogIsos(t *testing.T) {
+	isos := []IsoCatalogIso{
+		{Name: "ubuntu-22.04.9-live-server-amd64", LastWriteTime: "2026-01-01T00:00:00.0000000Z"},
+		{Name: "ubuntu-20.04.6-live-server-amd64", LastWriteTime: "2026-03-01T00:00:00.0000000Z"},
+		{Name: "ubuntu-22.04.10-live-server-amd64", LastWriteTime: "2026-02-01T00:00:00.0000000Z"},
+		{Name: "ubuntu-22.04", Path: `C:\iso\old\ubuntu-22.04.iso`, LastWriteTime: "2025-01-01T00:00:00.0000000Z"},
+		{Name: "ubuntu-22.04", Path: `C:\iso\new\ubuntu-22.04.iso`, LastWriteTime: "2025-06-01T00:00:00.0000000Z"},
+	}
+
+	selectedIsos := SelectIsoCatalogIsos(isos, "ubuntu-22.04")
+
+	expected := []string{
+		"ubuntu-22.04.10-live-server-amd64",
+		"ubuntu-22.04.9-live-server-amd64",
+		`C:\iso\new\ubuntu-22.04.iso`,
+		`C:\iso\old\ubuntu-22.04.iso`,
+	}
+	if len(selectedIsos) != len(expected) {
+		t.Fatalf("Expected %d isos, got %+v", len(expected), selectedIsos)
+	}
+
+	for i, iso := range selectedIsos {
+		actual := iso.Name
+		if iso.Path != "" {
+			actual = iso.Path
+		}
+
+		if actual != expected[i] {
+			t.Errorf("Expected iso %d to be %s, got %s", i, expected[i], actual)
+		}
+	}
+}
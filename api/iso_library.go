@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var IsoChecksumType_value = map[string]string{
+	"sha1":   "SHA1",
+	"sha256": "SHA256",
+	"sha384": "SHA384",
+	"sha512": "SHA512",
+	"md5":    "MD5",
+}
+
+type IsoLibraryIso struct {
+	Name         string
+	Path         string
+	Checksum     string
+	ChecksumType string
+	Size         int64
+	CachedPath   string
+}
+
+// DvdDrivePath returns the path dvd drives insert the iso from, the copy in the cache directory of the library when
+// the library caches its isos on the HyperV host machine
+func (i *IsoLibraryIso) DvdDrivePath() string {
+	if i.CachedPath != "" {
+		return i.CachedPath
+	}
+
+	return i.Path
+}
+
+type IsoLibrary struct {
+	Name           string
+	Description    string
+	CacheDirectory string
+	Isos           []IsoLibraryIso
+}
+
+// Iso returns the iso of the library with the name, names are compared without case like the rest of HyperV
+func (l *IsoLibrary) Iso(name string) (IsoLibraryIso, bool) {
+	for _, iso := range l.Isos {
+		if strings.EqualFold(iso.Name, name) {
+			return iso, true
+		}
+	}
+
+	return IsoLibraryIso{}, false
+}
+
+func ExpandIsoLibraryIsos(d *schema.ResourceData) ([]IsoLibraryIso, error) {
+	expandedIsos := make([]IsoLibraryIso, 0)
+
+	if v, ok := d.GetOk("iso"); ok {
+		for _, iso := range v.([]interface{}) {
+			iso, ok := iso.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("[ERROR][hyperv] iso should be a Hash - was '%+v'", iso)
+			}
+
+			expandedIso := IsoLibraryIso{
+				Name:         iso["name"].(string),
+				Path:         iso["path"].(string),
+				Checksum:     strings.ToUpper(iso["checksum"].(string)),
+				ChecksumType: IsoChecksumType_value[strings.ToLower(iso["checksum_type"].(string))],
+			}
+
+			if _, found := (&IsoLibrary{Isos: expandedIsos}).Iso(expandedIso.Name); found {
+				return nil, fmt.Errorf("[ERROR][hyperv] iso names must be unique in an iso library - %s", expandedIso.Name)
+			}
+
+			expandedIsos = append(expandedIsos, expandedIso)
+		}
+	}
+
+	return expandedIsos, nil
+}
+
+func FlattenIsoLibraryIsos(isos []IsoLibraryIso) []interface{} {
+	flattenedIsos := make([]interface{}, 0)
+
+	for _, iso := range isos {
+		flattenedIso := make(map[string]interface{})
+		flattenedIso["name"] = iso.Name
+		flattenedIso["path"] = iso.Path
+		flattenedIso["checksum"] = iso.Checksum
+		flattenedIso["checksum_type"] = iso.ChecksumType
+		flattenedIso["size"] = iso.Size
+		flattenedIso["cached_path"] = iso.CachedPath
+		flattenedIsos = append(flattenedIsos, flattenedIso)
+	}
+
+	return flattenedIsos
+}
+
+// HypervIsoLibraryClient manages catalogs of iso files on the HyperV host machine. A catalog only records the path and
+// checksum of each iso, the iso files themselves are left where they are unless the catalog has a cache directory to
+// copy them to.
+type HypervIsoLibraryClient interface {
+	CreateOrUpdateIsoLibrary(ctx context.Context, name string, description string, cacheDirectory string, isos []IsoLibraryIso) (err error)
+	GetIsoLibrary(ctx context.Context, name string) (result IsoLibrary, err error)
+	DeleteIsoLibrary(ctx context.Context, name string) (err error)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDeserializeIsoLibrary(t *testing.T) {
+	var isoLibraryJson = `
+{
+    "Name":  "images",
+    "Description":  "Installation media",
+    "Isos":  [
+        {
+            "Name":  "ubuntu-22.04",
+            "Path":  "C:\\Iso\\ubuntu-22.04.iso",
+            "Checksum":  "A4ACFDA10B18DA50E2EC50CCAF860D7F20B389DF8765611142305C0E911D16FD",
+            "ChecksumType":  "SHA256",
+            "Size":  2133391360
+        }
+    ]
+}
+`
+
+	var isoLibrary IsoLibrary
+	err := json.Unmarshal([]byte(isoLibraryJson), &isoLibrary)
+	if err != nil {
+		t.Errorf("Unable to deserialize iso library: %s", err.Error())
+	}
+
+	if len(isoLibrary.Isos) != 1 || isoLibrary.Isos[0].Size != 2133391360 {
+		t.Errorf("Unexpected isos: %+v", isoLibrary.Isos)
+	}
+}
+
+func TestIsoLibraryIso(t *testing.T) {
+	isoLibrary := IsoLibrary{
+		Name: "images",
+		Isos: []IsoLibraryIso{
+			{Name: "ubuntu-22.04", Path: `C:\Iso\ubuntu-22.04.iso`},
+			{Name: "windows-server-2022", Path: `C:\Iso\windows_server_2022.iso`},
+		},
+	}
+
+	iso, found := isoLibrary.Iso("Windows-Server-2022")
+	if !found || iso.Path != `C:\Iso\windows_server_2022.iso` {
+		t.Errorf("Expected to find windows-server-2022 but was %+v, %t", iso, found)
+	}
+
+	_, found = isoLibrary.Iso("debian-12")
+	if found {
+		t.Errorf("Expected not to find debian-12")
+	}
+}
+
+func TestIsoLibraryIsoDvdDrivePath(t *testing.T) {
+	iso := IsoLibraryIso{
+		Name: "ubuntu-22.04",
+		Path: `\\fileserver\iso\ubuntu-22.04.iso`,
+	}
+
+	if iso.DvdDrivePath() != iso.Path {
+		t.Errorf("Expected the path of an iso that is not cached but was %s", iso.DvdDrivePath())
+	}
+
+	iso.CachedPath = `C:\IsoCache\A4ACFDA10B18DA50E2EC50CCAF860D7F20B389DF8765611142305C0E911D16FD.iso`
+	if iso.DvdDrivePath() != iso.CachedPath {
+		t.Errorf("Expected the cached path of a cached iso but was %s", iso.DvdDrivePath())
+	}
+}
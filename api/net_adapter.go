@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+)
+
+func FlattenNetAdapters(netAdapters *[]NetAdapter) []interface{} {
+	if netAdapters == nil || len(*netAdapters) < 1 {
+		return nil
+	}
+
+	flattenedNetAdapters := make([]interface{}, 0)
+
+	for _, netAdapter := range *netAdapters {
+		flattenedNetAdapter := make(map[string]interface{})
+		flattenedNetAdapter["name"] = netAdapter.Name
+		flattenedNetAdapter["interface_description"] = netAdapter.InterfaceDescription
+		flattenedNetAdapter["interface_index"] = netAdapter.InterfaceIndex
+		flattenedNetAdapter["status"] = netAdapter.Status
+		flattenedNetAdapter["mac_address"] = netAdapter.MacAddress
+		flattenedNetAdapter["link_speed"] = netAdapter.LinkSpeed
+		flattenedNetAdapter["link_speed_bits_per_second"] = netAdapter.LinkSpeedBitsPerSecond
+		flattenedNetAdapter["sriov_supported"] = netAdapter.SriovSupported
+		flattenedNetAdapter["rdma_capable"] = netAdapter.RdmaCapable
+		flattenedNetAdapter["rdma_enabled"] = netAdapter.RdmaEnabled
+		flattenedNetAdapters = append(flattenedNetAdapters, flattenedNetAdapter)
+	}
+
+	return flattenedNetAdapters
+}
+
+type NetAdapter struct {
+	Name                   string
+	InterfaceDescription   string
+	InterfaceIndex         int
+	Status                 string
+	MacAddress             string
+	LinkSpeed              string
+	LinkSpeedBitsPerSecond int64
+	SriovSupported         bool
+	RdmaCapable            bool
+	RdmaEnabled            bool
+}
+
+type HypervNetAdapterClient interface {
+	GetPhysicalNetAdapters(ctx context.Context, name string, upOnly bool) (result []NetAdapter, err error)
+}
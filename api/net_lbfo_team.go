@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+)
+
+var NetLbfoTeamLoadBalancingAlgorithm_value = map[string]string{
+	"transportports": "TransportPorts",
+	"ipaddresses":    "IPAddresses",
+	"macaddresses":   "MacAddresses",
+	"hypervport":     "HyperVPort",
+	"dynamic":        "Dynamic",
+}
+
+// NetLbfoTeam is a load balancing and failover (LBFO) team of network adapters. External switches can be bound to the
+// team on hosts that do not support switch embedded teaming (SET).
+type NetLbfoTeam struct {
+	Name                   string
+	TeamMembers            []string
+	TeamingMode            VMSwitchTeamingMode
+	LoadBalancingAlgorithm string
+	NetAdapterName         string
+	Status                 string
+}
+
+type HypervNetLbfoTeamClient interface {
+	CreateNetLbfoTeam(ctx context.Context, netLbfoTeam NetLbfoTeam) (err error)
+	GetNetLbfoTeam(ctx context.Context, name string) (result NetLbfoTeam, err error)
+	UpdateNetLbfoTeam(ctx context.Context, netLbfoTeam NetLbfoTeam) (err error)
+	DeleteNetLbfoTeam(ctx context.Context, name string) (err error)
+}
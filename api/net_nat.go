@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type NetNatProtocol int
+
+const (
+	NetNatProtocol_TCP NetNatProtocol = 6
+	NetNatProtocol_UDP NetNatProtocol = 17
+)
+
+var NetNatProtocol_name = map[NetNatProtocol]string{
+	NetNatProtocol_TCP: "TCP",
+	NetNatProtocol_UDP: "UDP",
+}
+
+var NetNatProtocol_value = map[string]NetNatProtocol{
+	"tcp": NetNatProtocol_TCP,
+	"udp": NetNatProtocol_UDP,
+}
+
+func (x NetNatProtocol) String() string {
+	return NetNatProtocol_name[x]
+}
+
+func ToNetNatProtocol(x string) NetNatProtocol {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return NetNatProtocol(integerValue)
+	}
+
+	return NetNatProtocol_value[strings.ToLower(x)]
+}
+
+func (d *NetNatProtocol) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *NetNatProtocol) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = NetNatProtocol(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToNetNatProtocol(s)
+	return nil
+}
+
+type NetNat struct {
+	Name                             string
+	InternalIPInterfaceAddressPrefix string
+	SwitchName                       string
+	GatewayAddress                   string
+}
+
+type NetNatStaticMapping struct {
+	NatName           string
+	StaticMappingId   int
+	Protocol          NetNatProtocol
+	ExternalIPAddress string
+	ExternalPort      int
+	InternalIPAddress string
+	InternalPort      int
+}
+
+type HypervNetNatClient interface {
+	CreateNetNat(
+		ctx context.Context,
+		name string,
+		internalIPInterfaceAddressPrefix string,
+		switchName string,
+		gatewayAddress string,
+	) (err error)
+	GetNetNat(ctx context.Context, name string) (result NetNat, err error)
+	DeleteNetNat(ctx context.Context, name string, switchName string, gatewayAddress string) (err error)
+	CreateNetNatStaticMapping(
+		ctx context.Context,
+		natName string,
+		protocol NetNatProtocol,
+		externalIPAddress string,
+		externalPort int,
+		internalIPAddress string,
+		internalPort int,
+	) (result NetNatStaticMapping, err error)
+	GetNetNatStaticMapping(ctx context.Context, natName string, staticMappingId int) (result NetNatStaticMapping, err error)
+	DeleteNetNatStaticMapping(ctx context.Context, natName string, staticMappingId int) (err error)
+}
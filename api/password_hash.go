@@ -0,0 +1,127 @@
+package api
+
+import (
+	"crypto/sha512"
+	"strconv"
+)
+
+const (
+	Sha512CryptDefaultRounds = 5000
+	Sha512CryptMinimumRounds = 1000
+	Sha512CryptMaximumRounds = 999999999
+	Sha512CryptMaximumSalt   = 16
+)
+
+const sha512CryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// sha512CryptByteOrder is the order the bytes of the final digest are encoded in, three bytes at a time
+var sha512CryptByteOrder = [][3]int{
+	{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4}, {47, 5, 26}, {6, 27, 48},
+	{28, 49, 7}, {50, 8, 29}, {9, 30, 51}, {31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13},
+	{56, 14, 35}, {15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19}, {62, 20, 41},
+}
+
+// Sha512Crypt returns the SHA-512 crypt ($6$) hash of a password, the format cloud-init and /etc/shadow expect for
+// the passwords of linux users. The salt is truncated to 16 characters and the rounds are clamped to the range the
+// format allows, the rounds are only part of the hash when they are not the default.
+func Sha512Crypt(password string, salt string, rounds int) string {
+	if len(salt) > Sha512CryptMaximumSalt {
+		salt = salt[:Sha512CryptMaximumSalt]
+	}
+	if rounds < Sha512CryptMinimumRounds {
+		rounds = Sha512CryptMinimumRounds
+	}
+	if rounds > Sha512CryptMaximumRounds {
+		rounds = Sha512CryptMaximumRounds
+	}
+
+	p := []byte(password)
+	s := []byte(salt)
+
+	alternate := sha512.New()
+	alternate.Write(p)
+	alternate.Write(s)
+	alternate.Write(p)
+	alternateSum := alternate.Sum(nil)
+
+	a := sha512.New()
+	a.Write(p)
+	a.Write(s)
+	i := len(p)
+	for ; i > 64; i -= 64 {
+		a.Write(alternateSum)
+	}
+	a.Write(alternateSum[:i])
+	for i = len(p); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			a.Write(alternateSum)
+		} else {
+			a.Write(p)
+		}
+	}
+	c := a.Sum(nil)
+
+	dp := sha512.New()
+	for i = 0; i < len(p); i++ {
+		dp.Write(p)
+	}
+	pSequence := repeatDigest(dp.Sum(nil), len(p))
+
+	ds := sha512.New()
+	for i = 0; i < 16+int(c[0]); i++ {
+		ds.Write(s)
+	}
+	sSequence := repeatDigest(ds.Sum(nil), len(s))
+
+	for i = 0; i < rounds; i++ {
+		h := sha512.New()
+		if i&1 != 0 {
+			h.Write(pSequence)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(sSequence)
+		}
+		if i%7 != 0 {
+			h.Write(pSequence)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(pSequence)
+		}
+		c = h.Sum(nil)
+	}
+
+	result := []byte("$6$")
+	if rounds != Sha512CryptDefaultRounds {
+		result = append(result, "rounds="+strconv.Itoa(rounds)+"$"...)
+	}
+	result = append(result, s...)
+	result = append(result, '$')
+	for _, order := range sha512CryptByteOrder {
+		result = appendSha512CryptBase64(result, uint(c[order[0]])<<16|uint(c[order[1]])<<8|uint(c[order[2]]), 4)
+	}
+	result = appendSha512CryptBase64(result, uint(c[63]), 2)
+
+	return string(result)
+}
+
+func repeatDigest(digest []byte, length int) []byte {
+	sequence := make([]byte, 0, length)
+	for len(sequence)+len(digest) <= length {
+		sequence = append(sequence, digest...)
+	}
+
+	return append(sequence, digest[:length-len(sequence)]...)
+}
+
+func appendSha512CryptBase64(result []byte, value uint, length int) []byte {
+	for i := 0; i < length; i++ {
+		result = append(result, sha512CryptAlphabet[value&0x3f])
+		value >>= 6
+	}
+
+	return result
+}
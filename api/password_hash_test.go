@@ -0,0 +1,26 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestSha512Crypt(t *testing.T) {
+	// test vectors from the specification of the SHA-512 crypt format, rounds below the minimum are raised to it
+	testCases := []struct {
+		password string
+		salt     string
+		rounds   int
+		expected string
+	}{
+		{"Hello world!", "saltstring", 5000, "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+		{"Hello world!", "saltstringsaltstring", 10000, "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+		{"we have a short salt string but not a short password", "roundstoolow", 10, "$6$rounds=1000$roundstoolow$yjTuW7RnC.d35QcVTFIb6uvh/7IQ1.GFtFN3i/.jwmeWEhzjf4uD/OPCb4jRl6atJGYhLst8IyR6YAtTrriMU1"},
+	}
+
+	for _, testCase := range testCases {
+		actual := Sha512Crypt(testCase.password, testCase.salt, testCase.rounds)
+		if actual != testCase.expected {
+			t.Errorf("Expected %s but was %s", testCase.expected, actual)
+		}
+	}
+}
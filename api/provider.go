@@ -1,17 +1,56 @@
 package api
 
 type Client interface {
+	HypervAssignableDeviceClient
+	HypervClusterClient
+	HypervClusterVmRoleClient
+	HypervDhcpServerScopeClient
+	HypervDiskClient
 	HypervDvdClient
+	HypervGpuClient
+	HypervHostCertificateClient
+	HypervHostCapabilityClient
+	HypervHostNumaSpanningClient
+	HypervIsoCatalogClient
+	HypervIsoLibraryClient
+	HypervNetAdapterClient
+	HypervNetLbfoTeamClient
+	HypervNetNatClient
+	HypervSmbShareClient
+	HypervStorageQosPolicyClient
 	HypervVhdClient
+	HypervVirtualMachinePathClient
+	HypervVmAffinityRuleClient
+	HypervVmBackupCheckpointPolicyClient
 	HypervVmClient
+	HypervVmCheckpointClient
+	HypervVmConsoleScreenshotClient
 	HypervVmDvdDriveClient
+	HypervVmEventClient
 	HypervVmFirmwareClient
+	HypervVmGroupClient
 	HypervVmHardDiskDriveClient
+	HypervVmHostClient
+	HypervVmInfoClient
 	HypervVmIntegrationServiceClient
+	HypervVmKvpClient
+	HypervVmMemoryNumaClient
+	HypervVmMigrationClient
 	HypervVmNetworkAdapterClient
+	HypervVmNetworkAdapterTeamMappingClient
 	HypervVmProcessorClient
+	HypervVmProcessorPinningClient
+	HypervVmReplicationClient
+	HypervVmResourcePoolClient
+	HypervVmScheduledTaskClient
 	HypervVmStatusClient
 	HypervVmSwitchClient
+	HypervVmSwitchExtensionClient
+	HypervVmSwitchNatDnsForwardingClient
+	HypervVmTpmStateBackupClient
+	HypervVmwareMigrationClient
+	HypervWindowsFeatureClient
+	HypervWorkspaceClient
 }
 
 type Provider struct {
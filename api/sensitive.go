@@ -0,0 +1,58 @@
+package api
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The SDK does not support write-only attributes, so secrets like passwords and answer files would end up in the state
+// in plain text. Attributes holding secrets use HashSensitiveValue as their StateFunc instead, so only a hash of the
+// secret is stored and a changed secret is still planned as the configured value is hashed the same way. As d.Get then
+// returns the hash, the secret itself has to be read from the configuration with GetSensitiveValue.
+
+// HashSensitiveValue returns the hash of a secret that is stored in the state instead of the secret itself
+func HashSensitiveValue(v interface{}) string {
+	value, ok := v.(string)
+	if !ok || value == "" {
+		return ""
+	}
+
+	hash := sha512.Sum512([]byte(value))
+	return "sha512:" + hex.EncodeToString(hash[:])
+}
+
+// GetSensitiveValue returns the configured secret of an attribute whose state only holds the hash of the secret. The
+// path is the names of the attribute and the blocks it is nested in, with the index of the block after its name e.g.
+// GetSensitiveValue(d, "windows_image", 0, "unattend").
+func GetSensitiveValue(d *schema.ResourceData, path ...interface{}) string {
+	v := d.GetRawConfig()
+	for _, step := range path {
+		if v.IsNull() || !v.IsKnown() {
+			return ""
+		}
+
+		switch step := step.(type) {
+		case string:
+			if !v.Type().IsObjectType() || !v.Type().HasAttribute(step) {
+				return ""
+			}
+			v = v.GetAttr(step)
+		case int:
+			if !v.CanIterateElements() || v.LengthInt() <= step {
+				return ""
+			}
+			v = v.Index(cty.NumberIntVal(int64(step)))
+		default:
+			return ""
+		}
+	}
+
+	if v.IsNull() || !v.IsKnown() || !v.Type().Equals(cty.String) {
+		return ""
+	}
+
+	return v.AsString()
+}
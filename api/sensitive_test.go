@@ -0,0 +1,26 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashSensitiveValue(t *testing.T) {
+	hash := HashSensitiveValue("P@ssw0rd")
+
+	if !strings.HasPrefix(hash, "sha512:") || len(hash) != len("sha512:")+128 {
+		t.Errorf("Unexpected hash %s", hash)
+	}
+
+	if strings.Contains(hash, "P@ssw0rd") {
+		t.Errorf("Expected the secret not to be part of the hash")
+	}
+
+	if HashSensitiveValue("P@ssw0rd") != hash {
+		t.Errorf("Expected the same secret to have the same hash")
+	}
+
+	if HashSensitiveValue("") != "" {
+		t.Errorf("Expected an empty secret to have an empty hash")
+	}
+}
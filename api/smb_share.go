@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+)
+
+var SmbShareFolderEnumerationMode_value = map[string]string{
+	"unrestricted": "Unrestricted",
+	"accessbased":  "AccessBased",
+}
+
+type SmbShare struct {
+	Name                  string
+	Path                  string
+	Description           string
+	ScopeName             string
+	ContinuouslyAvailable bool
+	EncryptData           bool
+	FolderEnumerationMode string
+	FullAccess            []string
+	ChangeAccess          []string
+	ReadAccess            []string
+	NoAccess              []string
+}
+
+// HypervSmbShareClient manages SMB shares on the HyperV host machine or on a scale-out file server, to hold the files
+// of virtual machines and iso libraries. The access of a share is managed as a whole, accounts not listed lose their
+// access to the share.
+type HypervSmbShareClient interface {
+	CreateSmbShare(ctx context.Context, smbShare SmbShare) (err error)
+	GetSmbShare(ctx context.Context, scopeName string, name string) (result SmbShare, err error)
+	UpdateSmbShare(ctx context.Context, smbShare SmbShare) (err error)
+	DeleteSmbShare(ctx context.Context, scopeName string, name string) (err error)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type StorageQosPolicyType int
+
+const (
+	StorageQosPolicyType_Aggregated StorageQosPolicyType = 1
+	StorageQosPolicyType_Dedicated  StorageQosPolicyType = 2
+)
+
+var StorageQosPolicyType_name = map[StorageQosPolicyType]string{
+	StorageQosPolicyType_Aggregated: "Aggregated",
+	StorageQosPolicyType_Dedicated:  "Dedicated",
+}
+
+var StorageQosPolicyType_value = map[string]StorageQosPolicyType{
+	"aggregated": StorageQosPolicyType_Aggregated,
+	"dedicated":  StorageQosPolicyType_Dedicated,
+}
+
+func (x StorageQosPolicyType) String() string {
+	return StorageQosPolicyType_name[x]
+}
+
+func ToStorageQosPolicyType(x string) StorageQosPolicyType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return StorageQosPolicyType(integerValue)
+	}
+
+	return StorageQosPolicyType_value[strings.ToLower(x)]
+}
+
+func (d *StorageQosPolicyType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *StorageQosPolicyType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = StorageQosPolicyType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToStorageQosPolicyType(s)
+	return nil
+}
+
+type StorageQosPolicy struct {
+	PolicyId                string
+	Name                    string
+	PolicyType              StorageQosPolicyType
+	MinimumIops             uint64
+	MaximumIops             uint64
+	MaximumIoBandwidthBytes uint64
+}
+
+type HypervStorageQosPolicyClient interface {
+	CreateStorageQosPolicy(ctx context.Context, clusterName string, name string, policyType StorageQosPolicyType, minimumIops uint64, maximumIops uint64, maximumIoBandwidthBytes uint64) (result StorageQosPolicy, err error)
+	GetStorageQosPolicyByName(ctx context.Context, clusterName string, name string) (result StorageQosPolicy, err error)
+	GetStorageQosPolicy(ctx context.Context, clusterName string, policyId string) (result StorageQosPolicy, err error)
+	UpdateStorageQosPolicy(ctx context.Context, clusterName string, policyId string, name string, minimumIops uint64, maximumIops uint64, maximumIoBandwidthBytes uint64) (err error)
+	DeleteStorageQosPolicy(ctx context.Context, clusterName string, policyId string) (err error)
+}
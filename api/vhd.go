@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -147,12 +148,100 @@ type Vhd struct {
 	DiskIdentifier          string
 	VhdType                 VhdType
 	VhdFormat               VhdFormat
+	Healthy                 bool
+	HealthErrorMessage      string
+	DeletionProtection      bool
+}
+
+// CheckHealth returns an error when Test-VHD found the virtual hard disk or one of its parents to be missing or
+// corrupt, so that a broken disk is reported when it is refreshed rather than when a virtual machine boots from it
+func (v *Vhd) CheckHealth() error {
+	if v.Path == "" || v.Healthy {
+		return nil
+	}
+
+	if v.HealthErrorMessage == "" {
+		return fmt.Errorf("virtual hard disk %s failed validation", v.Path)
+	}
+
+	return fmt.Errorf("virtual hard disk %s failed validation: %s", v.Path, v.HealthErrorMessage)
+}
+
+// VhdSourceVersion is the version of the image at a source url of a virtual hard disk, as reported by the web server
+// in the ETag and Last-Modified headers of the url
+type VhdSourceVersion struct {
+	ETag         string
+	LastModified string
+}
+
+// ImageVersion is the ETag of the image, or its Last-Modified date when the web server does not report an ETag. It is
+// empty when the web server reports neither, so the image can not be tracked.
+func (v *VhdSourceVersion) ImageVersion() string {
+	if v.ETag != "" {
+		return v.ETag
+	}
+
+	return v.LastModified
+}
+
+// IsVhdSourceUrl is true when the source of a virtual hard disk is downloaded from a web server rather than copied
+// from a path
+func IsVhdSourceUrl(source string) bool {
+	lowerSource := strings.ToLower(source)
+	return strings.HasPrefix(lowerSource, "http://") || strings.HasPrefix(lowerSource, "https://")
+}
+
+type VhdChainEntry struct {
+	Path         string
+	ParentPath   string
+	VhdType      VhdType
+	VhdFormat    VhdFormat
+	Exists       bool
+	Valid        bool
+	ErrorMessage string
+}
+
+func FlattenVhdChainEntries(vhdChainEntries *[]VhdChainEntry) []interface{} {
+	if vhdChainEntries == nil || len(*vhdChainEntries) < 1 {
+		return nil
+	}
+
+	flattenedVhdChainEntries := make([]interface{}, 0)
+
+	for _, vhdChainEntry := range *vhdChainEntries {
+		flattenedVhdChainEntry := make(map[string]interface{})
+		flattenedVhdChainEntry["path"] = vhdChainEntry.Path
+		flattenedVhdChainEntry["parent_path"] = vhdChainEntry.ParentPath
+		flattenedVhdChainEntry["vhd_type"] = vhdChainEntry.VhdType.String()
+		flattenedVhdChainEntry["vhd_format"] = vhdChainEntry.VhdFormat.String()
+		flattenedVhdChainEntry["exists"] = vhdChainEntry.Exists
+		flattenedVhdChainEntry["valid"] = vhdChainEntry.Valid
+		flattenedVhdChainEntry["error_message"] = vhdChainEntry.ErrorMessage
+		flattenedVhdChainEntries = append(flattenedVhdChainEntries, flattenedVhdChainEntry)
+	}
+
+	return flattenedVhdChainEntries
 }
 
 type HypervVhdClient interface {
 	VhdExists(ctx context.Context, path string) (result VhdExists, err error)
-	CreateOrUpdateVhd(ctx context.Context, path string, source string, sourceVm string, sourceDisk int, vhdType VhdType, parentPath string, size uint64, blockSize uint32, logicalSectorSize uint32, physicalSectorSize uint32) (err error)
+	CreateOrUpdateVhd(ctx context.Context, path string, source string, sourceVm string, sourceVmDiskIndex int, sourceVmControllerLocation int, sourceDisk int, vhdType VhdType, parentPath string, size uint64, blockSize uint32, logicalSectorSize uint32, physicalSectorSize uint32) (err error)
+	CreateVhdFromWindowsImage(ctx context.Context, path string, windowsImage WindowsImage, vhdType VhdType, size uint64, blockSize uint32, logicalSectorSize uint32, physicalSectorSize uint32) (err error)
 	ResizeVhd(ctx context.Context, path string, size uint64) (err error)
+	SetVhdParentPath(ctx context.Context, path string, parentPath string) (err error)
 	GetVhd(ctx context.Context, path string) (result Vhd, err error)
+	GetVhdParentChain(ctx context.Context, path string) (result []VhdChainEntry, err error)
+	// SetVhdDeletionProtection records on the HyperV host machine whether the virtual hard disk at path is protected
+	// from deletion, DeleteVhd and DeleteVhdWithCheckpoints refuse to delete it while it is
+	SetVhdDeletionProtection(ctx context.Context, path string, deletionProtection bool) (err error)
+	// GetVhdSourceVersion asks the web server of the source url of a virtual hard disk from the HyperV host machine for
+	// the version of the image
+	GetVhdSourceVersion(ctx context.Context, source string) (result VhdSourceVersion, err error)
+	// UpdateVhdFromSource downloads the source url of the virtual hard disk at path again and replaces the virtual hard
+	// disk with it once the download completed
+	UpdateVhdFromSource(ctx context.Context, path string, source string) (err error)
 	DeleteVhd(ctx context.Context, path string) (err error)
+	// DeleteVhdWithCheckpoints deletes the virtual hard disk at path, and when it is the automatic virtual hard disk of
+	// a checkpoint also the ones it is based on, down to the virtual hard disk that was attached to the virtual machine
+	DeleteVhdWithCheckpoints(ctx context.Context, path string) (err error)
 }
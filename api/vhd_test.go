@@ -0,0 +1,63 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestVhdCheckHealth(t *testing.T) {
+	cases := []struct {
+		vhd      Vhd
+		expected string
+	}{
+		{Vhd{}, ""},
+		{Vhd{Path: `C:\vhd\child.vhdx`, Healthy: true}, ""},
+		{Vhd{Path: `C:\vhd\child.vhdx`}, `virtual hard disk C:\vhd\child.vhdx failed validation`},
+		{Vhd{Path: `C:\vhd\child.vhdx`, HealthErrorMessage: "The chain of virtual hard disks is broken."}, `virtual hard disk C:\vhd\child.vhdx failed validation: The chain of virtual hard disks is broken.`},
+	}
+
+	for _, c := range cases {
+		err := c.vhd.CheckHealth()
+		actual := ""
+		if err != nil {
+			actual = err.Error()
+		}
+
+		if actual != c.expected {
+			t.Errorf("Expected health check of %+v to return %q, got %q", c.vhd, c.expected, actual)
+		}
+	}
+}
+
+func TestVhdSourceVersionImageVersion(t *testing.T) {
+	cases := []struct {
+		name     string
+		version  VhdSourceVersion
+		expected string
+	}{
+		{"etag", VhdSourceVersion{ETag: `"5f2b-1a"`, LastModified: "Wed, 21 Oct 2026 07:28:00 GMT"}, `"5f2b-1a"`},
+		{"last modified", VhdSourceVersion{LastModified: "Wed, 21 Oct 2026 07:28:00 GMT"}, "Wed, 21 Oct 2026 07:28:00 GMT"},
+		{"untracked", VhdSourceVersion{}, ""},
+	}
+
+	for _, c := range cases {
+		if actual := c.version.ImageVersion(); actual != c.expected {
+			t.Errorf("Expected image version of %s to be %q, got %q", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestIsVhdSourceUrl(t *testing.T) {
+	cases := map[string]bool{
+		"https://images.contoso.com/ubuntu.vhdx": true,
+		"HTTP://images.contoso.com/ubuntu.box":   true,
+		`c:\images\ubuntu.vhdx`:                  false,
+		`\\server\share\ubuntu.vhdx`:             false,
+		"":                                       false,
+	}
+
+	for source, expected := range cases {
+		if actual := IsVhdSourceUrl(source); actual != expected {
+			t.Errorf("Expected %q to be a url %t, got %t", source, expected, actual)
+		}
+	}
+}
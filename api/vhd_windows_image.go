@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// WindowsImage describes how to build a vhd from the install image of a Windows iso. The image in the install.wim or
+// install.esd of the iso is applied to the vhd, which leaves Windows generalized (as if sysprep had been run) so that
+// it is specialized with the unattend answer file the first time a vm boots from it.
+type WindowsImage struct {
+	IsoPath     string
+	ImageIndex  int
+	ImageName   string
+	Unattend    string
+	DriverPaths []string
+	Generation  int
+}
+
+// Validate checks the settings that can not be checked by the schema, the unattend answer file must be well-formed xml
+// as Windows setup silently ignores an answer file it can not parse.
+func (w *WindowsImage) Validate() error {
+	if w.ImageIndex > 0 && w.ImageName != "" {
+		return fmt.Errorf("[ERROR][hyperv] only one of image_index or image_name can be specified for windows image %s", w.IsoPath)
+	}
+
+	if w.Generation != 1 && w.Generation != 2 {
+		return fmt.Errorf("[ERROR][hyperv] generation of windows image %s must be 1 or 2 - was %d", w.IsoPath, w.Generation)
+	}
+
+	if w.Unattend != "" {
+		decoder := xml.NewDecoder(strings.NewReader(w.Unattend))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("[ERROR][hyperv] unattend of windows image %s is not valid xml: %s", w.IsoPath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func ExpandWindowsImage(d *schema.ResourceData) (*WindowsImage, error) {
+	v, ok := d.GetOk("windows_image")
+	if !ok {
+		return nil, nil
+	}
+
+	windowsImages := v.([]interface{})
+	if len(windowsImages) < 1 || windowsImages[0] == nil {
+		return nil, nil
+	}
+
+	windowsImage, ok := windowsImages[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("[ERROR][hyperv] windows_image should be a Hash - was '%+v'", windowsImages[0])
+	}
+
+	driverPaths := make([]string, 0)
+	for _, driverPath := range windowsImage["driver_paths"].([]interface{}) {
+		driverPaths = append(driverPaths, driverPath.(string))
+	}
+
+	expandedWindowsImage := &WindowsImage{
+		IsoPath:     windowsImage["iso_path"].(string),
+		ImageIndex:  windowsImage["image_index"].(int),
+		ImageName:   windowsImage["image_name"].(string),
+		Unattend:    GetSensitiveValue(d, "windows_image", 0, "unattend"),
+		DriverPaths: driverPaths,
+		Generation:  windowsImage["generation"].(int),
+	}
+
+	return expandedWindowsImage, expandedWindowsImage.Validate()
+}
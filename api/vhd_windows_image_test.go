@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestWindowsImageValidate(t *testing.T) {
+	windowsImage := WindowsImage{
+		IsoPath:    `C:\Iso\windows_server_2022.iso`,
+		ImageName:  "Windows Server 2022 Standard (Desktop Experience)",
+		Generation: 2,
+		Unattend:   `<?xml version="1.0" encoding="utf-8"?><unattend xmlns="urn:schemas-microsoft-com:unattend"><settings pass="oobeSystem"/></unattend>`,
+	}
+
+	if err := windowsImage.Validate(); err != nil {
+		t.Errorf("Expected windows image to be valid but was %s", err)
+	}
+
+	windowsImage.ImageIndex = 2
+	if err := windowsImage.Validate(); err == nil {
+		t.Errorf("Expected image_index and image_name to conflict")
+	}
+
+	windowsImage.ImageIndex = 0
+	windowsImage.Generation = 3
+	if err := windowsImage.Validate(); err == nil {
+		t.Errorf("Expected generation 3 to be invalid")
+	}
+
+	windowsImage.Generation = 1
+	windowsImage.Unattend = `<unattend><settings pass="oobeSystem"></unattend>`
+	if err := windowsImage.Validate(); err == nil {
+		t.Errorf("Expected malformed unattend to be invalid")
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"strings"
+)
+
+// VirtualMachinePath is the folder layout HyperV uses for a virtual machine, so the configuration, virtual hard disks,
+// checkpoints and seed isos of the virtual machine live together in one folder
+type VirtualMachinePath struct {
+	Path                 string
+	VirtualMachinesPath  string
+	VirtualHardDisksPath string
+	SnapshotsPath        string
+}
+
+// NewVirtualMachinePath returns the folder layout for the virtual machine with the name in the root path, which is
+// the same layout New-VM creates when it is given the root path as its path
+func NewVirtualMachinePath(rootPath string, name string) VirtualMachinePath {
+	path := strings.TrimRight(strings.ReplaceAll(rootPath, "/", `\`), `\`) + `\` + name
+
+	return VirtualMachinePath{
+		Path:                 path,
+		VirtualMachinesPath:  path + `\Virtual Machines`,
+		VirtualHardDisksPath: path + `\Virtual Hard Disks`,
+		SnapshotsPath:        path + `\Snapshots`,
+	}
+}
+
+// SplitVirtualMachinePath returns the root path and name of the folder of a virtual machine
+func SplitVirtualMachinePath(path string) (rootPath string, name string) {
+	path = strings.TrimRight(strings.ReplaceAll(path, "/", `\`), `\`)
+	index := strings.LastIndex(path, `\`)
+	if index < 0 {
+		return "", path
+	}
+
+	rootPath = path[:index]
+	if strings.HasSuffix(rootPath, ":") {
+		rootPath += `\`
+	}
+
+	return rootPath, path[index+1:]
+}
+
+type HypervVirtualMachinePathClient interface {
+	CreateVirtualMachinePath(ctx context.Context, virtualMachinePath VirtualMachinePath) (err error)
+	VirtualMachinePathExists(ctx context.Context, virtualMachinePath VirtualMachinePath) (result bool, err error)
+	DeleteVirtualMachinePath(ctx context.Context, path string) (err error)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestNewVirtualMachinePath(t *testing.T) {
+	virtualMachinePath := NewVirtualMachinePath(`D:\Hyper-V\`, "web01")
+
+	if virtualMachinePath.Path != `D:\Hyper-V\web01` {
+		t.Errorf("Unexpected path: %s", virtualMachinePath.Path)
+	}
+	if virtualMachinePath.VirtualMachinesPath != `D:\Hyper-V\web01\Virtual Machines` {
+		t.Errorf("Unexpected virtual machines path: %s", virtualMachinePath.VirtualMachinesPath)
+	}
+	if virtualMachinePath.VirtualHardDisksPath != `D:\Hyper-V\web01\Virtual Hard Disks` {
+		t.Errorf("Unexpected virtual hard disks path: %s", virtualMachinePath.VirtualHardDisksPath)
+	}
+	if virtualMachinePath.SnapshotsPath != `D:\Hyper-V\web01\Snapshots` {
+		t.Errorf("Unexpected snapshots path: %s", virtualMachinePath.SnapshotsPath)
+	}
+}
+
+func TestSplitVirtualMachinePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		rootPath string
+		name     string
+	}{
+		{`D:\Hyper-V\web01`, `D:\Hyper-V`, "web01"},
+		{`D:\web01\`, `D:\`, "web01"},
+		{`\\server\share\web01`, `\\server\share`, "web01"},
+	}
+
+	for _, test := range tests {
+		rootPath, name := SplitVirtualMachinePath(test.path)
+		if rootPath != test.rootPath || name != test.name {
+			t.Errorf("Expected %s to split into %s and %s but was %s and %s", test.path, test.rootPath, test.name, rootPath, name)
+		}
+	}
+}
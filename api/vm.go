@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -282,6 +283,80 @@ func (d *OnOffState) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// GuestStateIsolationType is how the guest state of a virtual machine is isolated from the HyperV host machine. Trusted
+// launch runs a paravisor in the virtual machine that protects its firmware, secure boot and TPM state, the other types
+// need the virtualization based security of the guest or the memory encryption of AMD SEV-SNP or Intel TDX processors.
+type GuestStateIsolationType int
+
+const (
+	GuestStateIsolationType_Disabled      GuestStateIsolationType = 0
+	GuestStateIsolationType_TrustedLaunch GuestStateIsolationType = 1
+	GuestStateIsolationType_VBS           GuestStateIsolationType = 2
+	GuestStateIsolationType_SNP           GuestStateIsolationType = 3
+	GuestStateIsolationType_TDX           GuestStateIsolationType = 4
+)
+
+var GuestStateIsolationType_name = map[GuestStateIsolationType]string{
+	GuestStateIsolationType_Disabled:      "Disabled",
+	GuestStateIsolationType_TrustedLaunch: "TrustedLaunch",
+	GuestStateIsolationType_VBS:           "VBS",
+	GuestStateIsolationType_SNP:           "SNP",
+	GuestStateIsolationType_TDX:           "TDX",
+}
+
+var GuestStateIsolationType_value = map[string]GuestStateIsolationType{
+	"disabled":      GuestStateIsolationType_Disabled,
+	"trustedlaunch": GuestStateIsolationType_TrustedLaunch,
+	"vbs":           GuestStateIsolationType_VBS,
+	"snp":           GuestStateIsolationType_SNP,
+	"tdx":           GuestStateIsolationType_TDX,
+}
+
+func (x GuestStateIsolationType) String() string {
+	return GuestStateIsolationType_name[x]
+}
+
+func ToGuestStateIsolationType(x string) GuestStateIsolationType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return GuestStateIsolationType(integerValue)
+	}
+	return GuestStateIsolationType_value[strings.ToLower(x)]
+}
+
+func (d *GuestStateIsolationType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *GuestStateIsolationType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = GuestStateIsolationType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToGuestStateIsolationType(s)
+	return nil
+}
+
+// CheckGuestStateIsolation returns an error when the guest state of a virtual machine of the generation can not be
+// isolated, as only generation 2 virtual machines have the firmware the paravisor runs in
+func CheckGuestStateIsolation(guestStateIsolationType GuestStateIsolationType, generation int) error {
+	if guestStateIsolationType != GuestStateIsolationType_Disabled && generation != 2 {
+		return fmt.Errorf("[ERROR][hyperv] guest_state_isolation_type %s needs a generation 2 virtual machine", guestStateIsolationType)
+	}
+
+	return nil
+}
+
 type VmExists struct {
 	Exists bool
 }
@@ -304,14 +379,62 @@ type Vm struct {
 	MemoryMaximumBytes                  int64
 	MemoryMinimumBytes                  int64
 	MemoryStartupBytes                  int64
+	MemoryResourcePoolName              string
 	Notes                               string
 	ProcessorCount                      int64
 	SmartPagingFilePath                 string
 	SnapshotFileLocation                string
 	StaticMemory                        bool
+	GuestStateIsolationType             GuestStateIsolationType
 	// ParentCheckpointName				string  this will allow us to set the checkpoint to use
 }
 
+// VmWithDevices is a virtual machine together with all of its child devices, so that a machine instance can be
+// refreshed with a single round trip to the HyperV host machine
+type VmWithDevices struct {
+	Vm                  Vm
+	VmProcessors        []VmProcessor
+	VmFirmwares         []VmFirmware
+	IntegrationServices []VmIntegrationService
+	DvdDrives           []VmDvdDrive
+	HardDiskDrives      []VmHardDiskDrive
+	NetworkAdapters     []VmNetworkAdapter
+	VmStatus            VmStatus
+	KvpHostItems        map[string]string
+	VmMemoryNuma        VmMemoryNuma
+}
+
+const (
+	// VmResizeMethod_None means there are no changes to the memory or processor count of the virtual machine
+	VmResizeMethod_None = "None"
+	// VmResizeMethod_HotAdd means the changes are applied while the virtual machine keeps running
+	VmResizeMethod_HotAdd = "HotAdd"
+	// VmResizeMethod_Offline means the virtual machine is turned off to apply the changes and then returned to its state
+	VmResizeMethod_Offline = "Offline"
+)
+
+// GetVmResizeMethod returns how changes to the memory and processor count of a virtual machine are applied. HyperV can
+// only add memory to a running generation 2 virtual machine with static memory, it can not change the processor count
+// of a running virtual machine at all. Memory is only hot added, as removing memory from a running guest fails when the
+// guest can not release it.
+func GetVmResizeMethod(generation int, dynamicMemory bool, oldMemoryStartupBytes int64, newMemoryStartupBytes int64, processorCountChanged bool) string {
+	memoryStartupBytesChanged := oldMemoryStartupBytes != newMemoryStartupBytes
+
+	if !memoryStartupBytesChanged && !processorCountChanged {
+		return VmResizeMethod_None
+	}
+
+	if processorCountChanged {
+		return VmResizeMethod_Offline
+	}
+
+	if generation > 1 && !dynamicMemory && newMemoryStartupBytes > oldMemoryStartupBytes {
+		return VmResizeMethod_HotAdd
+	}
+
+	return VmResizeMethod_Offline
+}
+
 type HypervVmClient interface {
 	VmExists(ctx context.Context, name string) (result VmExists, err error)
 	CreateVm(
@@ -333,14 +456,17 @@ type HypervVmClient interface {
 		memoryMaximumBytes int64,
 		memoryMinimumBytes int64,
 		memoryStartupBytes int64,
+		memoryResourcePoolName string,
 		notes string,
 		processorCount int64,
 		smartPagingFilePath string,
 		snapshotFileLocation string,
 		staticMemory bool,
+		guestStateIsolationType GuestStateIsolationType,
 	) (err error)
 
 	GetVm(ctx context.Context, name string) (result Vm, err error)
+	GetVmWithDevices(ctx context.Context, name string, networkAdaptersWaitForIps []VmNetworkAdapterWaitForIp) (result VmWithDevices, err error)
 
 	UpdateVm(
 		ctx context.Context,
@@ -361,6 +487,7 @@ type HypervVmClient interface {
 		memoryMaximumBytes int64,
 		memoryMinimumBytes int64,
 		memoryStartupBytes int64,
+		memoryResourcePoolName string,
 		notes string,
 		processorCount int64,
 		smartPagingFilePath string,
@@ -368,5 +495,9 @@ type HypervVmClient interface {
 		staticMemory bool,
 	) (err error)
 
+	// ResizeVmMemory changes the startup memory of a virtual machine without turning it off, it fails when the
+	// virtual machine or its guest does not support hot adding memory
+	ResizeVmMemory(ctx context.Context, name string, memoryStartupBytes int64) (err error)
+
 	DeleteVm(ctx context.Context, name string) (err error)
 }
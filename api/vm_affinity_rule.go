@@ -0,0 +1,146 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type VmAffinityRuleType int
+
+const (
+	VmAffinityRuleType_AntiAffinity VmAffinityRuleType = 0
+	VmAffinityRuleType_Affinity     VmAffinityRuleType = 1
+)
+
+var VmAffinityRuleType_name = map[VmAffinityRuleType]string{
+	VmAffinityRuleType_AntiAffinity: "AntiAffinity",
+	VmAffinityRuleType_Affinity:     "Affinity",
+}
+
+var VmAffinityRuleType_value = map[string]VmAffinityRuleType{
+	"antiaffinity": VmAffinityRuleType_AntiAffinity,
+	"affinity":     VmAffinityRuleType_Affinity,
+}
+
+func (x VmAffinityRuleType) String() string {
+	return VmAffinityRuleType_name[x]
+}
+
+func ToVmAffinityRuleType(x string) VmAffinityRuleType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VmAffinityRuleType(integerValue)
+	}
+
+	return VmAffinityRuleType_value[strings.ToLower(x)]
+}
+
+func (d *VmAffinityRuleType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VmAffinityRuleType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VmAffinityRuleType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVmAffinityRuleType(s)
+	return nil
+}
+
+// VmAffinityRulePlacement is the host a virtual machine of an affinity rule runs on, the host is empty when the
+// virtual machine could not be found
+type VmAffinityRulePlacement struct {
+	VmName string
+	Host   string
+}
+
+// VmAffinityRule groups virtual machines that must run on different hosts (anti-affinity) or on the same host
+// (affinity). On a failover cluster the rule is handed to the cluster, anti-affinity as an anti-affinity class name of
+// the cluster groups of the virtual machines and affinity as a cluster affinity rule. On a standalone HyperV host
+// machine the provider refuses to place virtual machines of the rule on the host in a way that breaks it.
+type VmAffinityRule struct {
+	Name        string
+	RuleType    VmAffinityRuleType
+	ClusterName string
+	VmNames     []string
+	Placements  []VmAffinityRulePlacement
+}
+
+func (r *VmAffinityRule) Validate() error {
+	vmNames := make(map[string]bool)
+	for _, vmName := range r.VmNames {
+		if vmNames[strings.ToLower(vmName)] {
+			return fmt.Errorf("[ERROR][hyperv] vm names must be unique in %s rule %s - %s", r.RuleType.String(), r.Name, vmName)
+		}
+		vmNames[strings.ToLower(vmName)] = true
+	}
+
+	if len(r.VmNames) < 2 {
+		return fmt.Errorf("[ERROR][hyperv] %s rule %s must have at least 2 vm names", r.RuleType.String(), r.Name)
+	}
+
+	return nil
+}
+
+// Violations describes each way the current placement of the virtual machines breaks the rule. Virtual machines that
+// could not be found are ignored for anti-affinity, as they can not share a host, but break affinity.
+func (r *VmAffinityRule) Violations() []string {
+	violations := make([]string, 0)
+
+	vmNamesByHost := make(map[string][]string)
+	hosts := make([]string, 0)
+	for _, placement := range r.Placements {
+		if placement.Host == "" {
+			if r.RuleType == VmAffinityRuleType_Affinity {
+				violations = append(violations, fmt.Sprintf("vm %s could not be found", placement.VmName))
+			}
+			continue
+		}
+
+		host := strings.ToLower(placement.Host)
+		if _, found := vmNamesByHost[host]; !found {
+			hosts = append(hosts, host)
+		}
+		vmNamesByHost[host] = append(vmNamesByHost[host], placement.VmName)
+	}
+
+	switch r.RuleType {
+	case VmAffinityRuleType_AntiAffinity:
+		for _, host := range hosts {
+			if len(vmNamesByHost[host]) > 1 {
+				violations = append(violations, fmt.Sprintf("vms %s run on the same host %s", strings.Join(vmNamesByHost[host], ", "), host))
+			}
+		}
+	case VmAffinityRuleType_Affinity:
+		if len(hosts) > 1 {
+			placements := make([]string, 0)
+			for _, host := range hosts {
+				placements = append(placements, fmt.Sprintf("%s on %s", strings.Join(vmNamesByHost[host], ", "), host))
+			}
+			violations = append(violations, fmt.Sprintf("vms run on different hosts - %s", strings.Join(placements, "; ")))
+		}
+	}
+
+	return violations
+}
+
+type HypervVmAffinityRuleClient interface {
+	CreateOrUpdateVmAffinityRule(ctx context.Context, name string, ruleType VmAffinityRuleType, clusterName string, vmNames []string) (err error)
+	GetVmAffinityRule(ctx context.Context, name string, ruleType VmAffinityRuleType) (result VmAffinityRule, err error)
+	DeleteVmAffinityRule(ctx context.Context, name string, ruleType VmAffinityRuleType) (err error)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDeserializeVmAffinityRule(t *testing.T) {
+	var vmAffinityRuleJson = `
+{
+    "Name":  "web",
+    "RuleType":  "AntiAffinity",
+    "ClusterName":  "cluster01",
+    "VmNames":  [
+        "web01",
+        "web02"
+    ],
+    "Placements":  [
+        {
+            "VmName":  "web01",
+            "Host":  "node01"
+        },
+        {
+            "VmName":  "web02",
+            "Host":  "node02"
+        }
+    ]
+}
+`
+
+	var vmAffinityRule VmAffinityRule
+	err := json.Unmarshal([]byte(vmAffinityRuleJson), &vmAffinityRule)
+	if err != nil {
+		t.Errorf("Unable to deserialize vm affinity rule: %s", err.Error())
+	}
+
+	if vmAffinityRule.RuleType != VmAffinityRuleType_AntiAffinity || len(vmAffinityRule.Placements) != 2 {
+		t.Errorf("Unexpected vm affinity rule: %+v", vmAffinityRule)
+	}
+}
+
+func TestVmAffinityRuleValidate(t *testing.T) {
+	vmAffinityRule := VmAffinityRule{
+		Name:    "web",
+		VmNames: []string{"web01", "web02"},
+	}
+
+	if err := vmAffinityRule.Validate(); err != nil {
+		t.Errorf("Expected vm affinity rule to be valid but was %s", err)
+	}
+
+	vmAffinityRule.VmNames = []string{"web01", "WEB01"}
+	if err := vmAffinityRule.Validate(); err == nil {
+		t.Errorf("Expected duplicate vm names to be invalid")
+	}
+
+	vmAffinityRule.VmNames = []string{"web01"}
+	if err := vmAffinityRule.Validate(); err == nil {
+		t.Errorf("Expected a single vm name to be invalid")
+	}
+}
+
+func TestVmAffinityRuleViolations(t *testing.T) {
+	antiAffinityRule := VmAffinityRule{
+		Name:     "web",
+		RuleType: VmAffinityRuleType_AntiAffinity,
+		Placements: []VmAffinityRulePlacement{
+			{VmName: "web01", Host: "node01"},
+			{VmName: "web02", Host: "node02"},
+			{VmName: "web03", Host: ""},
+		},
+	}
+
+	if violations := antiAffinityRule.Violations(); len(violations) != 0 {
+		t.Errorf("Expected no violations but was %v", violations)
+	}
+
+	antiAffinityRule.Placements[1].Host = "NODE01"
+	if violations := antiAffinityRule.Violations(); len(violations) != 1 {
+		t.Errorf("Expected vms on the same host to violate anti-affinity but was %v", violations)
+	}
+
+	affinityRule := VmAffinityRule{
+		Name:     "database",
+		RuleType: VmAffinityRuleType_Affinity,
+		Placements: []VmAffinityRulePlacement{
+			{VmName: "sql01", Host: "node01"},
+			{VmName: "app01", Host: "node01"},
+		},
+	}
+
+	if violations := affinityRule.Violations(); len(violations) != 0 {
+		t.Errorf("Expected no violations but was %v", violations)
+	}
+
+	affinityRule.Placements[1].Host = "node02"
+	if violations := affinityRule.Violations(); len(violations) != 1 {
+		t.Errorf("Expected vms on different hosts to violate affinity but was %v", violations)
+	}
+
+	affinityRule.Placements[1].Host = ""
+	if violations := affinityRule.Violations(); len(violations) != 1 {
+		t.Errorf("Expected a missing vm to violate affinity but was %v", violations)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// VmBackupIntegrationServiceId is the id of the integration service that lets backup software take application
+// consistent checkpoints through the volume shadow copy service of the guest. Its name differs between versions of
+// Windows, e.g. `VSS` or `Backup (volume shadow copy)`, so it is looked up by id.
+const VmBackupIntegrationServiceId = "6C09BB55-D683-4DA0-8931-C9BF705F6480"
+
+// VmBackupCheckpointPolicy is what backup software needs of a virtual machine. ReferencePointRetention is the number of
+// the newest resilient change tracking reference points that are kept, older ones are removed. Reference points are
+// created by backup software to take incremental backups, so they are not removed when ReferencePointRetention is 0.
+// BackupIntegrationStatus and ReferencePointCount are read back from the virtual machine.
+type VmBackupCheckpointPolicy struct {
+	VmName                      string
+	CheckpointType              CheckpointType
+	AutomaticCheckpointsEnabled bool
+	BackupIntegrationEnabled    bool
+	ReferencePointRetention     int32
+	BackupIntegrationStatus     string
+	ReferencePointCount         int32
+}
+
+func (p *VmBackupCheckpointPolicy) Validate() error {
+	if p.VmName == "" {
+		return fmt.Errorf("[ERROR][hyperv] vm name of backup checkpoint policy must be specified")
+	}
+
+	// a production checkpoint of a running vm is taken through the volume shadow copy service of the guest
+	if p.CheckpointType == CheckpointType_ProductionOnly && !p.BackupIntegrationEnabled {
+		return fmt.Errorf("[ERROR][hyperv] checkpoint type %s of vm %s requires the backup integration service to be enabled", p.CheckpointType.String(), p.VmName)
+	}
+
+	if p.ReferencePointRetention < 0 {
+		return fmt.Errorf("[ERROR][hyperv] reference point retention of vm %s must not be negative", p.VmName)
+	}
+
+	return nil
+}
+
+// HasReferencePointsToPrune is true when the virtual machine has more reference points than are retained
+func (p *VmBackupCheckpointPolicy) HasReferencePointsToPrune() bool {
+	return p.ReferencePointRetention > 0 && p.ReferencePointCount > p.ReferencePointRetention
+}
+
+type HypervVmBackupCheckpointPolicyClient interface {
+	CreateOrUpdateVmBackupCheckpointPolicy(ctx context.Context, vmBackupCheckpointPolicy VmBackupCheckpointPolicy) (err error)
+	GetVmBackupCheckpointPolicy(ctx context.Context, vmName string) (result VmBackupCheckpointPolicy, err error)
+}
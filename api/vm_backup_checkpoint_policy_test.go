@@ -0,0 +1,48 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestVmBackupCheckpointPolicyValidate(t *testing.T) {
+	policy := VmBackupCheckpointPolicy{VmName: "web", CheckpointType: CheckpointType_ProductionOnly, BackupIntegrationEnabled: true}
+	if err := policy.Validate(); err != nil {
+		t.Errorf("Expected production only checkpoints with backup integration to be valid, got %s", err)
+	}
+
+	policy.BackupIntegrationEnabled = false
+	if err := policy.Validate(); err == nil {
+		t.Errorf("Expected production only checkpoints without backup integration to be invalid")
+	}
+
+	policy.CheckpointType = CheckpointType_Standard
+	if err := policy.Validate(); err != nil {
+		t.Errorf("Expected standard checkpoints without backup integration to be valid, got %s", err)
+	}
+
+	policy.VmName = ""
+	if err := policy.Validate(); err == nil {
+		t.Errorf("Expected a policy without a vm name to be invalid")
+	}
+}
+
+func TestVmBackupCheckpointPolicyHasReferencePointsToPrune(t *testing.T) {
+	cases := []struct {
+		name      string
+		retention int32
+		count     int32
+		expected  bool
+	}{
+		{"unmanaged", 0, 10, false},
+		{"none", 2, 0, false},
+		{"retained", 2, 2, false},
+		{"excess", 2, 3, true},
+	}
+
+	for _, c := range cases {
+		policy := VmBackupCheckpointPolicy{ReferencePointRetention: c.retention, ReferencePointCount: c.count}
+		if actual := policy.HasReferencePointsToPrune(); actual != c.expected {
+			t.Errorf("Expected %s reference points to be pruned %t, got %t", c.name, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// UpdateCheckpointNamePrefix starts the name of the checkpoints taken before a machine instance is updated, so that
+// they can be told apart from the checkpoints taken by hand when they are pruned
+const UpdateCheckpointNamePrefix = "terraform-before-update-"
+
+func UpdateCheckpointName(now time.Time) string {
+	return UpdateCheckpointNamePrefix + now.UTC().Format("20060102T150405Z")
+}
+
+// UpdateCheckpointsToPrune returns the checkpoints taken before updates that are older than the newest retention of
+// them, the checkpoints must be sorted from oldest to newest. No checkpoints are pruned when retention is 0.
+func UpdateCheckpointsToPrune(vmCheckpoints []VmCheckpoint, retention int) []VmCheckpoint {
+	vmCheckpointsToPrune := make([]VmCheckpoint, 0)
+	if retention < 1 {
+		return vmCheckpointsToPrune
+	}
+
+	updateVmCheckpoints := make([]VmCheckpoint, 0)
+	for _, vmCheckpoint := range vmCheckpoints {
+		if strings.HasPrefix(vmCheckpoint.Name, UpdateCheckpointNamePrefix) {
+			updateVmCheckpoints = append(updateVmCheckpoints, vmCheckpoint)
+		}
+	}
+
+	if len(updateVmCheckpoints) > retention {
+		vmCheckpointsToPrune = append(vmCheckpointsToPrune, updateVmCheckpoints[:len(updateVmCheckpoints)-retention]...)
+	}
+
+	return vmCheckpointsToPrune
+}
+
+func FlattenVmCheckpoints(vmCheckpoints *[]VmCheckpoint) []interface{} {
+	if vmCheckpoints == nil || len(*vmCheckpoints) < 1 {
+		return nil
+	}
+
+	flattenedVmCheckpoints := make([]interface{}, 0)
+
+	for _, vmCheckpoint := range *vmCheckpoints {
+		flattenedVmCheckpoint := make(map[string]interface{})
+		flattenedVmCheckpoint["name"] = vmCheckpoint.Name
+		flattenedVmCheckpoint["checkpoint_id"] = vmCheckpoint.Id
+		flattenedVmCheckpoint["checkpoint_type"] = vmCheckpoint.CheckpointType
+		flattenedVmCheckpoint["creation_time"] = vmCheckpoint.CreationTime
+		flattenedVmCheckpoint["parent_checkpoint_name"] = vmCheckpoint.ParentCheckpointName
+		flattenedVmCheckpoint["parent_checkpoint_id"] = vmCheckpoint.ParentCheckpointId
+		flattenedVmCheckpoints = append(flattenedVmCheckpoints, flattenedVmCheckpoint)
+	}
+
+	return flattenedVmCheckpoints
+}
+
+type VmCheckpoint struct {
+	VmName               string
+	Name                 string
+	Id                   string
+	CheckpointType       string
+	CreationTime         string
+	ParentCheckpointName string
+	ParentCheckpointId   string
+}
+
+type HypervVmCheckpointClient interface {
+	CreateVmCheckpoint(ctx context.Context, vmName string, name string) (result VmCheckpoint, err error)
+	GetVmCheckpoints(ctx context.Context, vmName string) (result []VmCheckpoint, err error)
+	DeleteVmCheckpoint(ctx context.Context, vmName string, checkpointId string) (err error)
+}
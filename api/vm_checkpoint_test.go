@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUpdateCheckpointName(t *testing.T) {
+	name := UpdateCheckpointName(time.Date(2023, 5, 17, 8, 30, 0, 0, time.FixedZone("UTC+2", 2*60*60)))
+	if name != "terraform-before-update-20230517T063000Z" {
+		t.Errorf("Unexpected update checkpoint name %s", name)
+	}
+}
+
+func TestUpdateCheckpointsToPrune(t *testing.T) {
+	vmCheckpoints := []VmCheckpoint{
+		{Name: "terraform-before-update-20230501T000000Z", Id: "1"},
+		{Name: "before upgrade", Id: "2"},
+		{Name: "terraform-before-update-20230502T000000Z", Id: "3"},
+		{Name: "terraform-before-update-20230503T000000Z", Id: "4"},
+	}
+
+	if vmCheckpointsToPrune := UpdateCheckpointsToPrune(vmCheckpoints, 0); len(vmCheckpointsToPrune) != 0 {
+		t.Errorf("Expected no checkpoints to be pruned without retention but was %+v", vmCheckpointsToPrune)
+	}
+
+	vmCheckpointsToPrune := UpdateCheckpointsToPrune(vmCheckpoints, 1)
+	if len(vmCheckpointsToPrune) != 2 || vmCheckpointsToPrune[0].Id != "1" || vmCheckpointsToPrune[1].Id != "3" {
+		t.Errorf("Expected the older update checkpoints to be pruned but was %+v", vmCheckpointsToPrune)
+	}
+
+	if vmCheckpointsToPrune := UpdateCheckpointsToPrune(vmCheckpoints, 3); len(vmCheckpointsToPrune) != 0 {
+		t.Errorf("Expected no checkpoints to be pruned within retention but was %+v", vmCheckpointsToPrune)
+	}
+}
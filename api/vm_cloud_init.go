@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// VmCloudInit is a NoCloud seed for cloud-init that is built as an iso with the volume label cidata and attached to a
+// dvd drive of the virtual machine at ControllerNumber and ControllerLocation. MetaData defaults to an instance id and
+// hostname of the name of the virtual machine, and NetworkConfig is left out of the seed when it is empty.
+type VmCloudInit struct {
+	Path               string
+	UserData           string
+	MetaData           string
+	NetworkConfig      string
+	ControllerNumber   int
+	ControllerLocation int
+}
+
+// DefaultCloudInitMetaData is the meta data of a seed that does not specify any. cloud-init only runs once per
+// instance id, so a virtual machine is provisioned once for its name.
+func DefaultCloudInitMetaData(vmName string) string {
+	return fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", vmName, vmName)
+}
+
+func ExpandVmCloudInit(d *schema.ResourceData, vmName string) (*VmCloudInit, error) {
+	v, ok := d.GetOk("cloud_init")
+	if !ok {
+		return nil, nil
+	}
+
+	cloudInits := v.([]interface{})
+	if len(cloudInits) < 1 || cloudInits[0] == nil {
+		return nil, nil
+	}
+
+	cloudInit, ok := cloudInits[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("[ERROR][hyperv] cloud_init should be a Hash - was '%+v'", cloudInits[0])
+	}
+
+	expandedCloudInit := &VmCloudInit{
+		Path:               cloudInit["path"].(string),
+		UserData:           GetSensitiveValue(d, "cloud_init", 0, "user_data"),
+		MetaData:           cloudInit["meta_data"].(string),
+		NetworkConfig:      cloudInit["network_config"].(string),
+		ControllerNumber:   cloudInit["controller_number"].(int),
+		ControllerLocation: cloudInit["controller_location"].(int),
+	}
+
+	if expandedCloudInit.MetaData == "" {
+		expandedCloudInit.MetaData = DefaultCloudInitMetaData(vmName)
+	}
+
+	return expandedCloudInit, nil
+}
+
+// DvdDrive is the dvd drive the seed is attached to
+func (c *VmCloudInit) DvdDrive() VmDvdDrive {
+	return VmDvdDrive{
+		ControllerNumber:   c.ControllerNumber,
+		ControllerLocation: c.ControllerLocation,
+		Path:               c.Path,
+	}
+}
+
+// WithoutDvdDrive returns the dvd drives other than the one the seed is attached to, so the dvd drive of the seed
+// stays out of dvd_drives
+func (c *VmCloudInit) WithoutDvdDrive(dvdDrives []VmDvdDrive) []VmDvdDrive {
+	if c == nil {
+		return dvdDrives
+	}
+
+	otherDvdDrives := make([]VmDvdDrive, 0, len(dvdDrives))
+	for _, dvdDrive := range dvdDrives {
+		if dvdDrive.ControllerNumber == c.ControllerNumber && dvdDrive.ControllerLocation == c.ControllerLocation {
+			continue
+		}
+		otherDvdDrives = append(otherDvdDrives, dvdDrive)
+	}
+
+	return otherDvdDrives
+}
+
+// CheckVmCloudInit checks that the dvd drive of the seed does not take the controller location of another drive. Dvd
+// drives are attached to the ide controllers of a generation 1 virtual machine and the scsi controllers of a
+// generation 2 virtual machine, which they share with the hard disk drives.
+func CheckVmCloudInit(cloudInit *VmCloudInit, generation int, dvdDrives []VmDvdDrive, hardDiskDrives []VmHardDiskDrive) error {
+	if cloudInit == nil {
+		return nil
+	}
+
+	for _, dvdDrive := range dvdDrives {
+		if dvdDrive.ControllerNumber == cloudInit.ControllerNumber && dvdDrive.ControllerLocation == cloudInit.ControllerLocation {
+			return fmt.Errorf("[ERROR][hyperv] dvd drive of cloud_init is at the same controller location as a dvd drive in dvd_drives - %d %d", cloudInit.ControllerNumber, cloudInit.ControllerLocation)
+		}
+	}
+
+	controllerType := ControllerType_Scsi
+	if generation == 1 {
+		controllerType = ControllerType_Ide
+	}
+
+	for _, hardDiskDrive := range hardDiskDrives {
+		if hardDiskDrive.ControllerType == controllerType && int(hardDiskDrive.ControllerNumber) == cloudInit.ControllerNumber && int(hardDiskDrive.ControllerLocation) == cloudInit.ControllerLocation {
+			return fmt.Errorf("[ERROR][hyperv] dvd drive of cloud_init is at the same controller location as a hard disk drive in hard_disk_drives - %s %d %d", controllerType, cloudInit.ControllerNumber, cloudInit.ControllerLocation)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVmCloudInitWithoutDvdDrive(t *testing.T) {
+	cloudInit := &VmCloudInit{Path: `C:\Iso\web-cidata.iso`, ControllerNumber: 0, ControllerLocation: 1}
+	dvdDrives := []VmDvdDrive{
+		{ControllerNumber: 0, ControllerLocation: 1, Path: `C:\Iso\web-cidata.iso`},
+		{ControllerNumber: 1, ControllerLocation: 0, Path: `C:\Iso\tools.iso`},
+	}
+
+	expected := []VmDvdDrive{dvdDrives[1]}
+	if actual := cloudInit.WithoutDvdDrive(dvdDrives); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, actual)
+	}
+
+	var noCloudInit *VmCloudInit
+	if actual := noCloudInit.WithoutDvdDrive(dvdDrives); !reflect.DeepEqual(actual, dvdDrives) {
+		t.Errorf("Expected all dvd drives without cloud init, got %#v", actual)
+	}
+}
+
+func TestCheckVmCloudInit(t *testing.T) {
+	cloudInit := &VmCloudInit{ControllerNumber: 0, ControllerLocation: 1}
+
+	cases := []struct {
+		name           string
+		generation     int
+		dvdDrives      []VmDvdDrive
+		hardDiskDrives []VmHardDiskDrive
+		valid          bool
+	}{
+		{"free", 2, []VmDvdDrive{{ControllerNumber: 0, ControllerLocation: 2}}, []VmHardDiskDrive{{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0}}, true},
+		{"dvd drive", 2, []VmDvdDrive{{ControllerNumber: 0, ControllerLocation: 1}}, nil, false},
+		{"scsi hard disk drive", 2, nil, []VmHardDiskDrive{{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1}}, false},
+		{"scsi hard disk drive of generation 1", 1, nil, []VmHardDiskDrive{{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1}}, true},
+		{"ide hard disk drive of generation 1", 1, nil, []VmHardDiskDrive{{ControllerType: ControllerType_Ide, ControllerNumber: 0, ControllerLocation: 1}}, false},
+	}
+
+	for _, c := range cases {
+		if err := CheckVmCloudInit(cloudInit, c.generation, c.dvdDrives, c.hardDiskDrives); (err == nil) != c.valid {
+			t.Errorf("Expected %s to be valid %t, got %v", c.name, c.valid, err)
+		}
+	}
+
+	if err := CheckVmCloudInit(nil, 2, []VmDvdDrive{{ControllerNumber: 0, ControllerLocation: 1}}, nil); err != nil {
+		t.Errorf("Expected no cloud init to be valid, got %s", err)
+	}
+}
+
+func TestDefaultCloudInitMetaData(t *testing.T) {
+	expected := "instance-id: web\nlocal-hostname: web\n"
+	if actual := DefaultCloudInitMetaData("web"); actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"context"
+)
+
+type VmConsoleScreenshot struct {
+	VmName string
+	Width  int
+	Height int
+	// Png is the screenshot as a base64 encoded png image
+	Png string
+}
+
+// HypervVmConsoleScreenshotClient captures what is shown on the console of a virtual machine, so that boot failures can
+// be debugged without access to the HyperV manager.
+type HypervVmConsoleScreenshotClient interface {
+	GetVmConsoleScreenshot(ctx context.Context, vmName string, width int, height int) (result VmConsoleScreenshot, err error)
+}
@@ -0,0 +1,75 @@
+package api
+
+// Devices of a virtual machine, such as hard disk drives, dvd drives and network adapters, are declared as lists, but
+// HyperV does not always return them in the same order. So devices are matched by their identity (controller location
+// or name) instead of their index in the list, to avoid reordering them and replacing devices that have not changed.
+
+// matchDevices returns, for each desired device, the index of the current device it matches or -1 if a new device has
+// to be created. Devices are first matched by identity, then any remaining devices are paired by their position.
+func matchDevices(currentLength int, desiredLength int, sameIdentity func(currentIndex int, desiredIndex int) bool) []int {
+	matches := make([]int, desiredLength)
+	matched := make([]bool, currentLength)
+
+	for desiredIndex := 0; desiredIndex < desiredLength; desiredIndex++ {
+		matches[desiredIndex] = -1
+		for currentIndex := 0; currentIndex < currentLength; currentIndex++ {
+			if !matched[currentIndex] && sameIdentity(currentIndex, desiredIndex) {
+				matches[desiredIndex] = currentIndex
+				matched[currentIndex] = true
+				break
+			}
+		}
+	}
+
+	currentIndex := 0
+	for desiredIndex := 0; desiredIndex < desiredLength; desiredIndex++ {
+		if matches[desiredIndex] != -1 {
+			continue
+		}
+
+		for currentIndex < currentLength && matched[currentIndex] {
+			currentIndex++
+		}
+
+		if currentIndex >= currentLength {
+			break
+		}
+
+		matches[desiredIndex] = currentIndex
+		matched[currentIndex] = true
+	}
+
+	return matches
+}
+
+// UnmatchedDevices returns the indexes of the current devices that are not matched by any desired device
+func UnmatchedDevices(currentLength int, matches []int) []int {
+	matched := make([]bool, currentLength)
+	for _, currentIndex := range matches {
+		if currentIndex != -1 {
+			matched[currentIndex] = true
+		}
+	}
+
+	unmatched := make([]int, 0)
+	for currentIndex := 0; currentIndex < currentLength; currentIndex++ {
+		if !matched[currentIndex] {
+			unmatched = append(unmatched, currentIndex)
+		}
+	}
+
+	return unmatched
+}
+
+// orderDevices returns the indexes of the current devices in the order of the desired devices they match, followed
+// by the current devices that do not match any desired device
+func orderDevices(currentLength int, matches []int) []int {
+	order := make([]int, 0, currentLength)
+	for _, currentIndex := range matches {
+		if currentIndex != -1 {
+			order = append(order, currentIndex)
+		}
+	}
+
+	return append(order, UnmatchedDevices(currentLength, matches)...)
+}
@@ -18,20 +18,22 @@ func ExpandDvdDrives(d *schema.ResourceData) ([]VmDvdDrive, error) {
 				return nil, fmt.Errorf("[ERROR][hyperv] dvd_drives should be a Hash - was '%+v'", dvdDrive)
 			}
 
-			expandedDvdDrive := VmDvdDrive{
-				ControllerNumber:   dvdDrive["controller_number"].(int),
-				ControllerLocation: dvdDrive["controller_location"].(int),
-				Path:               dvdDrive["path"].(string),
-				ResourcePoolName:   dvdDrive["resource_pool_name"].(string),
-			}
-
-			expandedDvdDrives = append(expandedDvdDrives, expandedDvdDrive)
+			expandedDvdDrives = append(expandedDvdDrives, ExpandDvdDrive(dvdDrive))
 		}
 	}
 
 	return expandedDvdDrives, nil
 }
 
+func ExpandDvdDrive(dvdDrive map[string]interface{}) VmDvdDrive {
+	return VmDvdDrive{
+		ControllerNumber:   dvdDrive["controller_number"].(int),
+		ControllerLocation: dvdDrive["controller_location"].(int),
+		Path:               dvdDrive["path"].(string),
+		ResourcePoolName:   dvdDrive["resource_pool_name"].(string),
+	}
+}
+
 func FlattenDvdDrives(dvdDrives *[]VmDvdDrive) []interface{} {
 	if dvdDrives == nil || len(*dvdDrives) < 1 {
 		return nil
@@ -40,17 +42,42 @@ func FlattenDvdDrives(dvdDrives *[]VmDvdDrive) []interface{} {
 	flattenedDvdDrives := make([]interface{}, 0)
 
 	for _, dvdDrive := range *dvdDrives {
-		flattenedDvdDrive := make(map[string]interface{})
-		flattenedDvdDrive["controller_number"] = dvdDrive.ControllerNumber
-		flattenedDvdDrive["controller_location"] = dvdDrive.ControllerLocation
-		flattenedDvdDrive["path"] = dvdDrive.Path
-		flattenedDvdDrive["resource_pool_name"] = dvdDrive.ResourcePoolName
-		flattenedDvdDrives = append(flattenedDvdDrives, flattenedDvdDrive)
+		flattenedDvdDrives = append(flattenedDvdDrives, FlattenDvdDrive(dvdDrive))
 	}
 
 	return flattenedDvdDrives
 }
 
+func FlattenDvdDrive(dvdDrive VmDvdDrive) map[string]interface{} {
+	flattenedDvdDrive := make(map[string]interface{})
+	flattenedDvdDrive["controller_number"] = dvdDrive.ControllerNumber
+	flattenedDvdDrive["controller_location"] = dvdDrive.ControllerLocation
+	flattenedDvdDrive["path"] = dvdDrive.Path
+	flattenedDvdDrive["resource_pool_name"] = dvdDrive.ResourcePoolName
+	return flattenedDvdDrive
+}
+
+// MatchDvdDrives returns, for each desired dvd drive, the index of the current dvd drive attached to the same
+// controller location or -1 if the dvd drive has to be created
+func MatchDvdDrives(currentDvdDrives []VmDvdDrive, desiredDvdDrives []VmDvdDrive) []int {
+	return matchDevices(len(currentDvdDrives), len(desiredDvdDrives), func(currentIndex int, desiredIndex int) bool {
+		currentDvdDrive := currentDvdDrives[currentIndex]
+		desiredDvdDrive := desiredDvdDrives[desiredIndex]
+		return currentDvdDrive.ControllerNumber == desiredDvdDrive.ControllerNumber &&
+			currentDvdDrive.ControllerLocation == desiredDvdDrive.ControllerLocation
+	})
+}
+
+// OrderDvdDrives returns the dvd drives in the order of the configured dvd drives they match
+func OrderDvdDrives(dvdDrives []VmDvdDrive, configuredDvdDrives []VmDvdDrive) []VmDvdDrive {
+	orderedDvdDrives := make([]VmDvdDrive, 0, len(dvdDrives))
+	for _, index := range orderDevices(len(dvdDrives), MatchDvdDrives(dvdDrives, configuredDvdDrives)) {
+		orderedDvdDrives = append(orderedDvdDrives, dvdDrives[index])
+	}
+
+	return orderedDvdDrives
+}
+
 type VmDvdDrive struct {
 	VmName             string
 	ControllerNumber   int
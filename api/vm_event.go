@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type VmEventLevel int
+
+const (
+	VmEventLevel_Critical    VmEventLevel = 1
+	VmEventLevel_Error       VmEventLevel = 2
+	VmEventLevel_Warning     VmEventLevel = 3
+	VmEventLevel_Information VmEventLevel = 4
+)
+
+var VmEventLevel_name = map[VmEventLevel]string{
+	VmEventLevel_Critical:    "Critical",
+	VmEventLevel_Error:       "Error",
+	VmEventLevel_Warning:     "Warning",
+	VmEventLevel_Information: "Information",
+}
+
+var VmEventLevel_value = map[string]VmEventLevel{
+	"critical":    VmEventLevel_Critical,
+	"error":       VmEventLevel_Error,
+	"warning":     VmEventLevel_Warning,
+	"information": VmEventLevel_Information,
+}
+
+func (x VmEventLevel) String() string {
+	return VmEventLevel_name[x]
+}
+
+func ToVmEventLevel(x string) VmEventLevel {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VmEventLevel(integerValue)
+	}
+	return VmEventLevel_value[strings.ToLower(x)]
+}
+
+func (d *VmEventLevel) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VmEventLevel) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VmEventLevel(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVmEventLevel(s)
+	return nil
+}
+
+// Levels are the levels of the event log that are at least as severe as the level, as the event log numbers them
+// from Critical as 1 to Information as 4
+func (x VmEventLevel) Levels() []int {
+	levels := make([]int, 0)
+	for level := VmEventLevel_Critical; level <= x && level <= VmEventLevel_Information; level++ {
+		levels = append(levels, int(level))
+	}
+
+	return levels
+}
+
+// DefaultVmEventLogNames are the admin logs the worker processes of the virtual machines and the virtual machine
+// management service report failures in
+var DefaultVmEventLogNames = []string{
+	"Microsoft-Windows-Hyper-V-Worker-Admin",
+	"Microsoft-Windows-Hyper-V-VMMS-Admin",
+}
+
+// VmEventQuery selects the events of the HyperV host machine about virtual machines. Events of all virtual machines are
+// selected when VmNames is empty. Level is the least severe level that is selected, SinceMinutes is how far back in
+// time events are selected and at most MaxEvents of the newest events are returned.
+type VmEventQuery struct {
+	VmNames      []string
+	LogNames     []string
+	Level        VmEventLevel
+	SinceMinutes int32
+	MaxEvents    int32
+}
+
+func FlattenVmEvents(vmEvents *[]VmEvent) []interface{} {
+	if vmEvents == nil || len(*vmEvents) < 1 {
+		return nil
+	}
+
+	flattenedVmEvents := make([]interface{}, 0)
+
+	for _, vmEvent := range *vmEvents {
+		flattenedVmEvent := make(map[string]interface{})
+		flattenedVmEvent["vm_name"] = vmEvent.VmName
+		flattenedVmEvent["vm_id"] = vmEvent.VmId
+		flattenedVmEvent["log_name"] = vmEvent.LogName
+		flattenedVmEvent["provider_name"] = vmEvent.ProviderName
+		flattenedVmEvent["event_id"] = vmEvent.EventId
+		flattenedVmEvent["level"] = vmEvent.Level.String()
+		flattenedVmEvent["time_created"] = vmEvent.TimeCreated
+		flattenedVmEvent["message"] = vmEvent.Message
+		flattenedVmEvents = append(flattenedVmEvents, flattenedVmEvent)
+	}
+
+	return flattenedVmEvents
+}
+
+type VmEvent struct {
+	VmName       string
+	VmId         string
+	LogName      string
+	ProviderName string
+	EventId      int32
+	Level        VmEventLevel
+	TimeCreated  string
+	Message      string
+}
+
+type HypervVmEventClient interface {
+	GetVmEvents(ctx context.Context, query VmEventQuery) (result []VmEvent, err error)
+}
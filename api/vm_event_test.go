@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestVmEventLevelLevels(t *testing.T) {
+	cases := []struct {
+		level    VmEventLevel
+		expected []int
+	}{
+		{VmEventLevel_Critical, []int{1}},
+		{VmEventLevel_Error, []int{1, 2}},
+		{VmEventLevel_Warning, []int{1, 2, 3}},
+		{VmEventLevel_Information, []int{1, 2, 3, 4}},
+	}
+
+	for _, c := range cases {
+		if actual := c.level.Levels(); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Expected levels of %s to be %v, got %v", c.level.String(), c.expected, actual)
+		}
+	}
+}
+
+func TestVmEventUnmarshalLevel(t *testing.T) {
+	var vmEvents []VmEvent
+	err := json.Unmarshal([]byte(`[{"VmName":"web","EventId":18590,"Level":1},{"VmName":"db","EventId":12010,"Level":"Error"}]`), &vmEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vmEvents[0].Level != VmEventLevel_Critical || vmEvents[1].Level != VmEventLevel_Error {
+		t.Errorf("Unexpected levels %s and %s", vmEvents[0].Level.String(), vmEvents[1].Level.String())
+	}
+}
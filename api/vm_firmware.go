@@ -334,6 +334,30 @@ func FlattenVmFirmwares(vmFirmwares *[]VmFirmware) []interface{} {
 	return flattenedVmFirmwares
 }
 
+type SecureBootTemplate struct {
+	Name        string
+	Id          string
+	Description string
+}
+
+func FlattenSecureBootTemplates(secureBootTemplates *[]SecureBootTemplate) []interface{} {
+	if secureBootTemplates == nil || len(*secureBootTemplates) < 1 {
+		return nil
+	}
+
+	flattenedSecureBootTemplates := make([]interface{}, 0)
+
+	for _, secureBootTemplate := range *secureBootTemplates {
+		flattenedSecureBootTemplate := make(map[string]interface{})
+		flattenedSecureBootTemplate["name"] = secureBootTemplate.Name
+		flattenedSecureBootTemplate["template_id"] = secureBootTemplate.Id
+		flattenedSecureBootTemplate["description"] = secureBootTemplate.Description
+		flattenedSecureBootTemplates = append(flattenedSecureBootTemplates, flattenedSecureBootTemplate)
+	}
+
+	return flattenedSecureBootTemplates
+}
+
 type HypervVmFirmwareClient interface {
 	CreateOrUpdateVmFirmware(
 		ctx context.Context,
@@ -349,4 +373,82 @@ type HypervVmFirmwareClient interface {
 	GetNoVmFirmwares(ctx context.Context) (result []VmFirmware)
 	GetVmFirmwares(ctx context.Context, vmName string) (result []VmFirmware, err error)
 	CreateOrUpdateVmFirmwares(ctx context.Context, vmName string, vmFirmwares []VmFirmware) (err error)
+	GetSecureBootTemplates(ctx context.Context) (result []SecureBootTemplate, err error)
+}
+
+// NetworkBootVmFirmware returns the firmware to boot a generation 2 virtual machine from a network adapter first, e.g.
+// for a PXE install. The network adapter is moved to the front of the boot order of the firmware and the rest of the
+// boot order is kept, so the virtual machine can still boot from its disks once the install is done.
+func NetworkBootVmFirmware(vmFirmware VmFirmware, networkAdapterName string, disableSecureBoot bool) VmFirmware {
+	bootOrders := []Gen2BootOrder{
+		{
+			Type:               Gen2BootType_NetworkAdapter,
+			NetworkAdapterName: networkAdapterName,
+			ControllerNumber:   -1,
+			ControllerLocation: -1,
+		},
+	}
+
+	for _, bootOrder := range vmFirmware.BootOrders {
+		if bootOrder.Type == Gen2BootType_NetworkAdapter && strings.EqualFold(bootOrder.NetworkAdapterName, networkAdapterName) {
+			continue
+		}
+
+		bootOrders = append(bootOrders, bootOrder)
+	}
+
+	vmFirmware.BootOrders = bootOrders
+	if disableSecureBoot {
+		vmFirmware.EnableSecureBoot = OnOffState_Off
+	}
+
+	return vmFirmware
+}
+
+type VmBootFromNetwork struct {
+	NetworkAdapterName string
+	DisableSecureBoot  bool
+	FirstBootTimeout   uint32
+}
+
+func ExpandVmBootFromNetwork(bootFromNetworks []interface{}) (*VmBootFromNetwork, error) {
+	if len(bootFromNetworks) < 1 || bootFromNetworks[0] == nil {
+		return nil, nil
+	}
+
+	bootFromNetwork, ok := bootFromNetworks[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("[ERROR][hyperv] boot_from_network should be a Hash - was '%+v'", bootFromNetworks[0])
+	}
+
+	return &VmBootFromNetwork{
+		NetworkAdapterName: bootFromNetwork["network_adapter_name"].(string),
+		DisableSecureBoot:  bootFromNetwork["disable_secure_boot"].(bool),
+		FirstBootTimeout:   uint32(bootFromNetwork["first_boot_timeout"].(int)),
+	}, nil
+}
+
+// CheckVmBootFromNetwork checks that a virtual machine can boot from the network when it is created. Only the firmware
+// of a generation 2 virtual machine has a boot order for network adapters, and the boot order can only be reverted
+// once the virtual machine has been started and booted.
+func CheckVmBootFromNetwork(bootFromNetwork *VmBootFromNetwork, generation int, state VmState, networkAdapters []VmNetworkAdapter) error {
+	if bootFromNetwork == nil {
+		return nil
+	}
+
+	if generation < 2 {
+		return fmt.Errorf("[ERROR][hyperv] boot_from_network is only supported by generation 2 virtual machines")
+	}
+
+	if state != VmState_Running {
+		return fmt.Errorf("[ERROR][hyperv] boot_from_network requires state to be %s so the boot order can be reverted after the first boot - was %s", VmState_Running, state)
+	}
+
+	for _, networkAdapter := range networkAdapters {
+		if strings.EqualFold(networkAdapter.Name, bootFromNetwork.NetworkAdapterName) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("[ERROR][hyperv] boot_from_network network adapter %s is not one of the network_adaptors of the virtual machine", bootFromNetwork.NetworkAdapterName)
 }
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestNetworkBootVmFirmware(t *testing.T) {
+	vmFirmware := VmFirmware{
+		VmName: "web_server",
+		BootOrders: []Gen2BootOrder{
+			{Type: Gen2BootType_HardDiskDrive, Path: `C:\VMs\web_server.vhdx`, ControllerNumber: 0, ControllerLocation: 0},
+			{Type: Gen2BootType_NetworkAdapter, NetworkAdapterName: "Provisioning", ControllerNumber: -1, ControllerLocation: -1},
+			{Type: Gen2BootType_NetworkAdapter, NetworkAdapterName: "Production", ControllerNumber: -1, ControllerLocation: -1},
+		},
+		EnableSecureBoot:             OnOffState_On,
+		SecureBootTemplate:           "MicrosoftUEFICertificateAuthority",
+		PreferredNetworkBootProtocol: IPProtocolPreference_IPv6,
+	}
+
+	networkBootVmFirmware := NetworkBootVmFirmware(vmFirmware, "provisioning", false)
+
+	if len(networkBootVmFirmware.BootOrders) != 3 {
+		t.Fatalf("Expected the network adapter to be moved instead of added but was %+v", networkBootVmFirmware.BootOrders)
+	}
+	if networkBootVmFirmware.BootOrders[0].Type != Gen2BootType_NetworkAdapter || networkBootVmFirmware.BootOrders[0].NetworkAdapterName != "provisioning" {
+		t.Errorf("Expected to boot from the network adapter first but was %+v", networkBootVmFirmware.BootOrders[0])
+	}
+	if networkBootVmFirmware.BootOrders[1].Type != Gen2BootType_HardDiskDrive || networkBootVmFirmware.BootOrders[2].NetworkAdapterName != "Production" {
+		t.Errorf("Expected the rest of the boot order to be kept but was %+v", networkBootVmFirmware.BootOrders)
+	}
+	if networkBootVmFirmware.EnableSecureBoot != OnOffState_On {
+		t.Errorf("Expected secure boot to be kept on")
+	}
+	if networkBootVmFirmware.PreferredNetworkBootProtocol != IPProtocolPreference_IPv6 {
+		t.Errorf("Expected the preferred network boot protocol to be kept for an IPv6 network boot")
+	}
+	if vmFirmware.BootOrders[0].Type != Gen2BootType_HardDiskDrive {
+		t.Errorf("Expected the boot order of the original firmware to be unchanged but was %+v", vmFirmware.BootOrders)
+	}
+
+	networkBootVmFirmware = NetworkBootVmFirmware(vmFirmware, "Provisioning", true)
+	if networkBootVmFirmware.EnableSecureBoot != OnOffState_Off {
+		t.Errorf("Expected secure boot to be turned off")
+	}
+}
+
+func TestCheckVmBootFromNetwork(t *testing.T) {
+	networkAdapters := []VmNetworkAdapter{
+		{Name: "Provisioning"},
+	}
+	bootFromNetwork := &VmBootFromNetwork{
+		NetworkAdapterName: "provisioning",
+	}
+
+	if err := CheckVmBootFromNetwork(nil, 1, VmState_Off, nil); err != nil {
+		t.Errorf("Expected no error when not booting from the network but was %s", err)
+	}
+	if err := CheckVmBootFromNetwork(bootFromNetwork, 2, VmState_Running, networkAdapters); err != nil {
+		t.Errorf("Expected to boot from the network but was %s", err)
+	}
+	if err := CheckVmBootFromNetwork(bootFromNetwork, 1, VmState_Running, networkAdapters); err == nil {
+		t.Errorf("Expected generation 1 to be unsupported")
+	}
+	if err := CheckVmBootFromNetwork(bootFromNetwork, 2, VmState_Off, networkAdapters); err == nil {
+		t.Errorf("Expected state Off to be unsupported")
+	}
+	if err := CheckVmBootFromNetwork(&VmBootFromNetwork{NetworkAdapterName: "Production"}, 2, VmState_Running, networkAdapters); err == nil {
+		t.Errorf("Expected an unknown network adapter to be unsupported")
+	}
+}
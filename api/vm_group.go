@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type VMGroupType int
+
+const (
+	VMGroupType_VMCollectionType         VMGroupType = 0
+	VMGroupType_ManagementCollectionType VMGroupType = 1
+)
+
+var VMGroupType_name = map[VMGroupType]string{
+	VMGroupType_VMCollectionType:         "VMCollectionType",
+	VMGroupType_ManagementCollectionType: "ManagementCollectionType",
+}
+
+var VMGroupType_value = map[string]VMGroupType{
+	"vmcollectiontype":         VMGroupType_VMCollectionType,
+	"managementcollectiontype": VMGroupType_ManagementCollectionType,
+}
+
+func (x VMGroupType) String() string {
+	return VMGroupType_name[x]
+}
+
+func ToVMGroupType(x string) VMGroupType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMGroupType(integerValue)
+	}
+
+	return VMGroupType_value[strings.ToLower(x)]
+}
+
+func (d *VMGroupType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMGroupType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMGroupType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMGroupType(s)
+	return nil
+}
+
+type VmGroup struct {
+	Name           string
+	Id             string
+	GroupType      VMGroupType
+	VmMembers      []string
+	VmGroupMembers []string
+}
+
+type HypervVmGroupClient interface {
+	CreateVmGroup(ctx context.Context, name string, groupType VMGroupType, vmMembers []string, vmGroupMembers []string) (err error)
+	GetVmGroup(ctx context.Context, name string) (result VmGroup, err error)
+	UpdateVmGroup(ctx context.Context, name string, vmMembers []string, vmGroupMembers []string) (err error)
+	DeleteVmGroup(ctx context.Context, name string) (err error)
+}
@@ -130,16 +130,24 @@ func DiffSuppressVmHardDiskPath(key, old, new string, d *schema.ResourceData) bo
 		return true
 	}
 
-	if new == old {
+	if EqualWindowsPaths(new, old) {
 		return true
 	}
 
+	// A vm with checkpoints is attached to an automatic differencing disk, the configured path is the disk they are
+	// based on
+	if basePathKey := strings.TrimSuffix(key, "path") + "base_path"; basePathKey != key {
+		if basePath, ok := d.Get(basePathKey).(string); ok && basePath != "" && EqualWindowsPaths(new, basePath) {
+			return true
+		}
+	}
+
 	// Ignore snapshots otherwise it will change from "c:\\vhdx\\web_server_g2_B63C9D15-F9A3-4F63-A896-FFD80BC7754C.avhdx" -> "c:\\vhdx\\web_server_g2.vhdx"
 	oldExtension := strings.ToLower(filepath.Ext(old))
 	newExtension := strings.ToLower(filepath.Ext(new))
 	if oldExtension == ".avhdx" && newExtension == ".vhdx" {
 		newName := new[0 : len(new)-len(newExtension)]
-		return strings.HasPrefix(old, newName+"_")
+		return strings.HasPrefix(strings.ToLower(old), strings.ToLower(newName)+"_")
 	}
 
 	return false
@@ -157,18 +165,9 @@ func ExpandHardDiskDrives(d *schema.ResourceData) ([]VmHardDiskDrive, error) {
 				return nil, fmt.Errorf("[ERROR][hyperv] hard_disk_drives should be a Hash - was '%+v'", hardDiskDrive)
 			}
 
-			expandedHardDiskDrive := VmHardDiskDrive{
-				ControllerType:                ToControllerType(hardDiskDrive["controller_type"].(string)),
-				ControllerNumber:              int32(hardDiskDrive["controller_number"].(int)),
-				ControllerLocation:            int32(hardDiskDrive["controller_location"].(int)),
-				Path:                          hardDiskDrive["path"].(string),
-				DiskNumber:                    uint32(hardDiskDrive["disk_number"].(int)),
-				ResourcePoolName:              hardDiskDrive["resource_pool_name"].(string),
-				SupportPersistentReservations: hardDiskDrive["support_persistent_reservations"].(bool),
-				MaximumIops:                   uint64(hardDiskDrive["maximum_iops"].(int)),
-				MinimumIops:                   uint64(hardDiskDrive["minimum_iops"].(int)),
-				QosPolicyId:                   hardDiskDrive["qos_policy_id"].(string),
-				OverrideCacheAttributes:       ToCacheAttributes(hardDiskDrive["override_cache_attributes"].(string)),
+			expandedHardDiskDrive, err := ExpandHardDiskDrive(hardDiskDrive)
+			if err != nil {
+				return nil, err
 			}
 
 			expandedHardDiskDrives = append(expandedHardDiskDrives, expandedHardDiskDrive)
@@ -178,6 +177,36 @@ func ExpandHardDiskDrives(d *schema.ResourceData) ([]VmHardDiskDrive, error) {
 	return expandedHardDiskDrives, nil
 }
 
+func ExpandHardDiskDrive(hardDiskDrive map[string]interface{}) (VmHardDiskDrive, error) {
+	expandedHardDiskDrive := VmHardDiskDrive{
+		ControllerType:                ToControllerType(hardDiskDrive["controller_type"].(string)),
+		ControllerNumber:              int32(hardDiskDrive["controller_number"].(int)),
+		ControllerLocation:            int32(hardDiskDrive["controller_location"].(int)),
+		Path:                          hardDiskDrive["path"].(string),
+		DiskNumber:                    uint32(hardDiskDrive["disk_number"].(int)),
+		ResourcePoolName:              hardDiskDrive["resource_pool_name"].(string),
+		SupportPersistentReservations: hardDiskDrive["support_persistent_reservations"].(bool),
+		MaximumIops:                   uint64(hardDiskDrive["maximum_iops"].(int)),
+		MinimumIops:                   uint64(hardDiskDrive["minimum_iops"].(int)),
+		QosPolicyId:                   hardDiskDrive["qos_policy_id"].(string),
+		OverrideCacheAttributes:       ToCacheAttributes(hardDiskDrive["override_cache_attributes"].(string)),
+	}
+
+	if basePath, ok := hardDiskDrive["base_path"].(string); ok {
+		expandedHardDiskDrive.BasePath = basePath
+	}
+
+	if expandedHardDiskDrive.MaximumIops > 0 && expandedHardDiskDrive.MinimumIops > expandedHardDiskDrive.MaximumIops {
+		return expandedHardDiskDrive, fmt.Errorf("[ERROR][hyperv] hard_disk_drives minimum_iops (%d) must not be greater than maximum_iops (%d) - %s", expandedHardDiskDrive.MinimumIops, expandedHardDiskDrive.MaximumIops, expandedHardDiskDrive.Path)
+	}
+
+	if expandedHardDiskDrive.QosPolicyId != "" && expandedHardDiskDrive.QosPolicyId != "00000000-0000-0000-0000-000000000000" && (expandedHardDiskDrive.MaximumIops > 0 || expandedHardDiskDrive.MinimumIops > 0) {
+		return expandedHardDiskDrive, fmt.Errorf("[ERROR][hyperv] hard_disk_drives qos_policy_id can not be used together with minimum_iops or maximum_iops - %s", expandedHardDiskDrive.Path)
+	}
+
+	return expandedHardDiskDrive, nil
+}
+
 func FlattenHardDiskDrives(hardDiskDrives *[]VmHardDiskDrive) []interface{} {
 	if hardDiskDrives == nil || len(*hardDiskDrives) < 1 {
 		return nil
@@ -186,30 +215,112 @@ func FlattenHardDiskDrives(hardDiskDrives *[]VmHardDiskDrive) []interface{} {
 	flattenedHardDiskDrives := make([]interface{}, 0)
 
 	for _, hardDiskDrive := range *hardDiskDrives {
-		flattenedHardDiskDrive := make(map[string]interface{})
-		flattenedHardDiskDrive["controller_type"] = hardDiskDrive.ControllerType.String()
-		flattenedHardDiskDrive["controller_number"] = hardDiskDrive.ControllerNumber
-		flattenedHardDiskDrive["controller_location"] = hardDiskDrive.ControllerLocation
-		flattenedHardDiskDrive["path"] = hardDiskDrive.Path
-		flattenedHardDiskDrive["disk_number"] = hardDiskDrive.DiskNumber
-		flattenedHardDiskDrive["resource_pool_name"] = hardDiskDrive.ResourcePoolName
-		flattenedHardDiskDrive["support_persistent_reservations"] = hardDiskDrive.SupportPersistentReservations
-		flattenedHardDiskDrive["maximum_iops"] = hardDiskDrive.MaximumIops
-		flattenedHardDiskDrive["minimum_iops"] = hardDiskDrive.MinimumIops
-		flattenedHardDiskDrive["qos_policy_id"] = hardDiskDrive.QosPolicyId
-		flattenedHardDiskDrive["override_cache_attributes"] = hardDiskDrive.OverrideCacheAttributes.String()
-		flattenedHardDiskDrives = append(flattenedHardDiskDrives, flattenedHardDiskDrive)
+		flattenedHardDiskDrives = append(flattenedHardDiskDrives, FlattenHardDiskDrive(hardDiskDrive))
 	}
 
 	return flattenedHardDiskDrives
 }
 
+func FlattenHardDiskDrive(hardDiskDrive VmHardDiskDrive) map[string]interface{} {
+	flattenedHardDiskDrive := make(map[string]interface{})
+	flattenedHardDiskDrive["controller_type"] = hardDiskDrive.ControllerType.String()
+	flattenedHardDiskDrive["controller_number"] = hardDiskDrive.ControllerNumber
+	flattenedHardDiskDrive["controller_location"] = hardDiskDrive.ControllerLocation
+	flattenedHardDiskDrive["path"] = hardDiskDrive.Path
+	flattenedHardDiskDrive["base_path"] = hardDiskDrive.BasePath
+	flattenedHardDiskDrive["disk_number"] = hardDiskDrive.DiskNumber
+	flattenedHardDiskDrive["resource_pool_name"] = hardDiskDrive.ResourcePoolName
+	flattenedHardDiskDrive["support_persistent_reservations"] = hardDiskDrive.SupportPersistentReservations
+	flattenedHardDiskDrive["maximum_iops"] = hardDiskDrive.MaximumIops
+	flattenedHardDiskDrive["minimum_iops"] = hardDiskDrive.MinimumIops
+	flattenedHardDiskDrive["qos_policy_id"] = hardDiskDrive.QosPolicyId
+	flattenedHardDiskDrive["override_cache_attributes"] = hardDiskDrive.OverrideCacheAttributes.String()
+	return flattenedHardDiskDrive
+}
+
+// MatchHardDiskDrives returns, for each desired hard disk drive, the index of the current hard disk drive attached to
+// the same controller location or -1 if the hard disk drive has to be created
+func MatchHardDiskDrives(currentHardDiskDrives []VmHardDiskDrive, desiredHardDiskDrives []VmHardDiskDrive) []int {
+	return matchDevices(len(currentHardDiskDrives), len(desiredHardDiskDrives), func(currentIndex int, desiredIndex int) bool {
+		currentHardDiskDrive := currentHardDiskDrives[currentIndex]
+		desiredHardDiskDrive := desiredHardDiskDrives[desiredIndex]
+		return currentHardDiskDrive.ControllerType == desiredHardDiskDrive.ControllerType &&
+			currentHardDiskDrive.ControllerNumber == desiredHardDiskDrive.ControllerNumber &&
+			currentHardDiskDrive.ControllerLocation == desiredHardDiskDrive.ControllerLocation
+	})
+}
+
+// HardDiskDrivesHotPluggable returns whether the current hard disk drives can be changed to the desired hard disk
+// drives while the virtual machine runs, which is the case when hard disk drives are only added to or removed from scsi
+// controllers. Hard disk drives on ide controllers, and changes to hard disk drives that stay attached, need the
+// virtual machine to be off.
+func HardDiskDrivesHotPluggable(currentHardDiskDrives []VmHardDiskDrive, desiredHardDiskDrives []VmHardDiskDrive) bool {
+	matches := MatchHardDiskDrives(currentHardDiskDrives, desiredHardDiskDrives)
+
+	for _, i := range UnmatchedDevices(len(currentHardDiskDrives), matches) {
+		if currentHardDiskDrives[i].ControllerType != ControllerType_Scsi {
+			return false
+		}
+	}
+
+	for i, desiredHardDiskDrive := range desiredHardDiskDrives {
+		if matches[i] == -1 {
+			if desiredHardDiskDrive.ControllerType != ControllerType_Scsi {
+				return false
+			}
+			continue
+		}
+
+		if !SameHardDiskDrive(currentHardDiskDrives[matches[i]], desiredHardDiskDrive) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SameHardDiskDrive returns whether the current hard disk drive already has the settings of the desired hard disk
+// drive, so that it does not have to be updated
+func SameHardDiskDrive(currentHardDiskDrive VmHardDiskDrive, desiredHardDiskDrive VmHardDiskDrive) bool {
+	currentHardDiskDrive.VmName = desiredHardDiskDrive.VmName
+	if strings.EqualFold(HardDiskDrivePathToSet(currentHardDiskDrive, desiredHardDiskDrive), currentHardDiskDrive.Path) {
+		currentHardDiskDrive.Path = desiredHardDiskDrive.Path
+	}
+	currentHardDiskDrive.BasePath = desiredHardDiskDrive.BasePath
+
+	return currentHardDiskDrive == desiredHardDiskDrive
+}
+
+// HardDiskDrivePathToSet returns the path to update the current hard disk drive to. When the desired path is the disk
+// the checkpoints of the virtual machine are based on, the current hard disk drive is kept attached to its automatic
+// differencing disk, as attaching the base disk would drop the differencing disks and invalidate the checkpoints.
+func HardDiskDrivePathToSet(currentHardDiskDrive VmHardDiskDrive, desiredHardDiskDrive VmHardDiskDrive) string {
+	if currentHardDiskDrive.BasePath != "" && EqualWindowsPaths(currentHardDiskDrive.BasePath, desiredHardDiskDrive.Path) {
+		return currentHardDiskDrive.Path
+	}
+
+	return desiredHardDiskDrive.Path
+}
+
+// OrderHardDiskDrives returns the hard disk drives in the order of the configured hard disk drives they match
+func OrderHardDiskDrives(hardDiskDrives []VmHardDiskDrive, configuredHardDiskDrives []VmHardDiskDrive) []VmHardDiskDrive {
+	orderedHardDiskDrives := make([]VmHardDiskDrive, 0, len(hardDiskDrives))
+	for _, index := range orderDevices(len(hardDiskDrives), MatchHardDiskDrives(hardDiskDrives, configuredHardDiskDrives)) {
+		orderedHardDiskDrives = append(orderedHardDiskDrives, hardDiskDrives[index])
+	}
+
+	return orderedHardDiskDrives
+}
+
 type VmHardDiskDrive struct {
-	VmName                        string
-	ControllerType                ControllerType
-	ControllerNumber              int32
-	ControllerLocation            int32
-	Path                          string
+	VmName             string
+	ControllerType     ControllerType
+	ControllerNumber   int32
+	ControllerLocation int32
+	Path               string
+	// BasePath is the disk the automatic differencing disk in Path is based on when the vm has checkpoints, otherwise
+	// it is the same as Path
+	BasePath                      string
 	DiskNumber                    uint32
 	ResourcePoolName              string
 	SupportPersistentReservations bool
@@ -255,6 +366,6 @@ type HypervVmHardDiskDriveClient interface {
 		qosPolicyId string,
 		overrideCacheAttributes CacheAttributes,
 	) (err error)
-	DeleteVmHardDiskDrive(ctx context.Context, vmname string, controllerNumber int32, controllerLocation int32) (err error)
+	DeleteVmHardDiskDrive(ctx context.Context, vmname string, controllerType ControllerType, controllerNumber int32, controllerLocation int32) (err error)
 	CreateOrUpdateVmHardDiskDrives(ctx context.Context, vmName string, hardDiskDrives []VmHardDiskDrive) (err error)
 }
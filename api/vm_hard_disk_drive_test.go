@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestSerializeVmHardDiskDrive(t *testing.T) {
@@ -111,3 +113,147 @@ func TestDeserializeVmHardDiskDrives(t *testing.T) {
 		t.Errorf("Path does not match")
 	}
 }
+
+func TestOrderVmHardDiskDrives(t *testing.T) {
+	hardDiskDrives := []VmHardDiskDrive{
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1, Path: "data.vhdx"},
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: "os.vhdx"},
+	}
+	configuredHardDiskDrives := []VmHardDiskDrive{
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0},
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1},
+	}
+
+	orderedHardDiskDrives := OrderHardDiskDrives(hardDiskDrives, configuredHardDiskDrives)
+	if orderedHardDiskDrives[0].Path != "os.vhdx" || orderedHardDiskDrives[1].Path != "data.vhdx" {
+		t.Errorf("Hard disk drives are not in the configured order: %+v", orderedHardDiskDrives)
+	}
+}
+
+func TestMatchVmHardDiskDrivesFallsBackToPosition(t *testing.T) {
+	currentHardDiskDrives := []VmHardDiskDrive{
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0},
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1},
+	}
+	desiredHardDiskDrives := []VmHardDiskDrive{
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 2},
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0},
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 3},
+	}
+
+	matches := MatchHardDiskDrives(currentHardDiskDrives, desiredHardDiskDrives)
+	expectedMatches := []int{1, 0, -1}
+	for i, expectedMatch := range expectedMatches {
+		if matches[i] != expectedMatch {
+			t.Errorf("Expected hard disk drive %d to match %d but matched %d", i, expectedMatch, matches[i])
+		}
+	}
+}
+
+func TestHardDiskDrivesHotPluggable(t *testing.T) {
+	currentHardDiskDrives := []VmHardDiskDrive{
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+		{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1, Path: `C:\vhds\data.vhdx`},
+	}
+
+	tests := []struct {
+		name                  string
+		desiredHardDiskDrives []VmHardDiskDrive
+		expected              bool
+	}{
+		{"unchanged", []VmHardDiskDrive{
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1, Path: `C:\VHDs\data.vhdx`},
+		}, true},
+		{"scsi added", []VmHardDiskDrive{
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1, Path: `C:\vhds\data.vhdx`},
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 2, Path: `C:\vhds\logs.vhdx`},
+		}, true},
+		{"scsi removed", []VmHardDiskDrive{
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+		}, true},
+		{"ide added", []VmHardDiskDrive{
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1, Path: `C:\vhds\data.vhdx`},
+			{ControllerType: ControllerType_Ide, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\logs.vhdx`},
+		}, false},
+		{"path changed", []VmHardDiskDrive{
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 1, Path: `C:\vhds\other.vhdx`},
+		}, false},
+		{"moved", []VmHardDiskDrive{
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+			{ControllerType: ControllerType_Scsi, ControllerNumber: 0, ControllerLocation: 2, Path: `C:\vhds\data.vhdx`},
+		}, false},
+	}
+
+	for _, test := range tests {
+		if actual := HardDiskDrivesHotPluggable(currentHardDiskDrives, test.desiredHardDiskDrives); actual != test.expected {
+			t.Errorf("Expected hot pluggable %t for %s but was %t", test.expected, test.name, actual)
+		}
+	}
+
+	ideHardDiskDrives := []VmHardDiskDrive{
+		{ControllerType: ControllerType_Ide, ControllerNumber: 0, ControllerLocation: 0, Path: `C:\vhds\os.vhdx`},
+	}
+	if HardDiskDrivesHotPluggable(ideHardDiskDrives, nil) {
+		t.Errorf("Expected removing an ide hard disk drive not to be hot pluggable")
+	}
+}
+
+func TestHardDiskDriveOfVmWithCheckpoint(t *testing.T) {
+	currentHardDiskDrive := VmHardDiskDrive{
+		ControllerType:     ControllerType_Scsi,
+		ControllerNumber:   0,
+		ControllerLocation: 0,
+		Path:               `C:\vhds\os_B63C9D15-F9A3-4F63-A896-FFD80BC7754C.avhdx`,
+		BasePath:           `C:\vhds\os.vhdx`,
+	}
+	desiredHardDiskDrive := VmHardDiskDrive{
+		ControllerType:     ControllerType_Scsi,
+		ControllerNumber:   0,
+		ControllerLocation: 0,
+		Path:               `C:\VHDs\os.vhdx`,
+	}
+
+	if !SameHardDiskDrive(currentHardDiskDrive, desiredHardDiskDrive) {
+		t.Errorf("Expected the base disk of the checkpoints to match the attached differencing disk")
+	}
+
+	if path := HardDiskDrivePathToSet(currentHardDiskDrive, desiredHardDiskDrive); path != currentHardDiskDrive.Path {
+		t.Errorf("Expected the hard disk drive to stay attached to %s but was updated to %s", currentHardDiskDrive.Path, path)
+	}
+
+	desiredHardDiskDrive.MaximumIops = 1000
+	if SameHardDiskDrive(currentHardDiskDrive, desiredHardDiskDrive) {
+		t.Errorf("Expected a change to maximum_iops to update the hard disk drive")
+	}
+
+	if path := HardDiskDrivePathToSet(currentHardDiskDrive, desiredHardDiskDrive); path != currentHardDiskDrive.Path {
+		t.Errorf("Expected the hard disk drive to stay attached to %s when only maximum_iops changes but was updated to %s", currentHardDiskDrive.Path, path)
+	}
+
+	desiredHardDiskDrive.Path = `C:\vhds\other.vhdx`
+	if path := HardDiskDrivePathToSet(currentHardDiskDrive, desiredHardDiskDrive); path != desiredHardDiskDrive.Path {
+		t.Errorf("Expected the hard disk drive to be updated to %s but was %s", desiredHardDiskDrive.Path, path)
+	}
+}
+
+func TestDiffSuppressVmHardDiskPathOfVmWithCheckpoint(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"path":      {Type: schema.TypeString, Optional: true},
+		"base_path": {Type: schema.TypeString, Computed: true},
+	}, map[string]interface{}{})
+	if err := d.Set("base_path", `C:\vhds\checkpointed\os.vhdx`); err != nil {
+		t.Fatal(err)
+	}
+
+	if !DiffSuppressVmHardDiskPath("path", `C:\vhds\checkpointed\Snapshots\3F2A.avhdx`, `C:\vhds\checkpointed\os.vhdx`, d) {
+		t.Errorf("Expected the base disk of the checkpoints not to be a change")
+	}
+
+	if DiffSuppressVmHardDiskPath("path", `C:\vhds\checkpointed\Snapshots\3F2A.avhdx`, `C:\vhds\other.vhdx`, d) {
+		t.Errorf("Expected another disk to be a change")
+	}
+}
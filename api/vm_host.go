@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type VMMigrationAuthenticationType int
+
+const (
+	VMMigrationAuthenticationType_CredSSP  VMMigrationAuthenticationType = 0
+	VMMigrationAuthenticationType_Kerberos VMMigrationAuthenticationType = 1
+)
+
+var VMMigrationAuthenticationType_name = map[VMMigrationAuthenticationType]string{
+	VMMigrationAuthenticationType_CredSSP:  "CredSSP",
+	VMMigrationAuthenticationType_Kerberos: "Kerberos",
+}
+
+var VMMigrationAuthenticationType_value = map[string]VMMigrationAuthenticationType{
+	"credssp":  VMMigrationAuthenticationType_CredSSP,
+	"kerberos": VMMigrationAuthenticationType_Kerberos,
+}
+
+func (x VMMigrationAuthenticationType) String() string {
+	return VMMigrationAuthenticationType_name[x]
+}
+
+func ToVMMigrationAuthenticationType(x string) VMMigrationAuthenticationType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMMigrationAuthenticationType(integerValue)
+	}
+
+	return VMMigrationAuthenticationType_value[strings.ToLower(x)]
+}
+
+func (d *VMMigrationAuthenticationType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMMigrationAuthenticationType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMMigrationAuthenticationType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMMigrationAuthenticationType(s)
+	return nil
+}
+
+type VMMigrationPerformance int
+
+const (
+	VMMigrationPerformance_TCPIP       VMMigrationPerformance = 0
+	VMMigrationPerformance_Compression VMMigrationPerformance = 1
+	VMMigrationPerformance_SMB         VMMigrationPerformance = 2
+)
+
+var VMMigrationPerformance_name = map[VMMigrationPerformance]string{
+	VMMigrationPerformance_TCPIP:       "TCPIP",
+	VMMigrationPerformance_Compression: "Compression",
+	VMMigrationPerformance_SMB:         "SMB",
+}
+
+var VMMigrationPerformance_value = map[string]VMMigrationPerformance{
+	"tcpip":       VMMigrationPerformance_TCPIP,
+	"compression": VMMigrationPerformance_Compression,
+	"smb":         VMMigrationPerformance_SMB,
+}
+
+func (x VMMigrationPerformance) String() string {
+	return VMMigrationPerformance_name[x]
+}
+
+func ToVMMigrationPerformance(x string) VMMigrationPerformance {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMMigrationPerformance(integerValue)
+	}
+
+	return VMMigrationPerformance_value[strings.ToLower(x)]
+}
+
+func (d *VMMigrationPerformance) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMMigrationPerformance) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMMigrationPerformance(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMMigrationPerformance(s)
+	return nil
+}
+
+type VmHost struct {
+	ComputerName                      string
+	VirtualHardDiskPath               string
+	VirtualMachinePath                string
+	NumaSpanningEnabled               bool
+	EnableEnhancedSessionMode         bool
+	ResourceMeteringSaveIntervalHours int
+	MacAddressMinimum                 string
+	MacAddressMaximum                 string
+}
+
+type VmHostNumaNode struct {
+	NodeId                int
+	LogicalProcessorCount int
+	MemoryTotalBytes      int64
+	MemoryAvailableBytes  int64
+}
+
+func FlattenVmHostNumaNodes(numaNodes []VmHostNumaNode) []interface{} {
+	flattenedNumaNodes := make([]interface{}, 0)
+
+	for _, numaNode := range numaNodes {
+		flattenedNumaNode := make(map[string]interface{})
+		flattenedNumaNode["node_id"] = numaNode.NodeId
+		flattenedNumaNode["logical_processor_count"] = numaNode.LogicalProcessorCount
+		flattenedNumaNode["memory_total_bytes"] = numaNode.MemoryTotalBytes
+		flattenedNumaNode["memory_available_bytes"] = numaNode.MemoryAvailableBytes
+		flattenedNumaNodes = append(flattenedNumaNodes, flattenedNumaNode)
+	}
+
+	return flattenedNumaNodes
+}
+
+type VmHostInfo struct {
+	ComputerName          string
+	OsName                string
+	OsVersion             string
+	OsBuildNumber         string
+	HypervVersion         string
+	DefaultVmVersion      string
+	LogicalProcessorCount int
+	MemoryCapacityBytes   int64
+	MemoryAvailableBytes  int64
+	VirtualHardDiskPath   string
+	VirtualMachinePath    string
+	NumaSpanningEnabled   bool
+	NumaNodes             []VmHostNumaNode
+	IovSupport            bool
+	IovSupportReasons     []string
+}
+
+type VmHostLiveMigration struct {
+	VirtualMachineMigrationEnabled            bool
+	VirtualMachineMigrationAuthenticationType VMMigrationAuthenticationType
+	MaximumVirtualMachineMigrations           int
+	VirtualMachineMigrationPerformanceOption  VMMigrationPerformance
+	UseAnyNetworkForMigration                 bool
+	MigrationNetworks                         []string
+}
+
+type HypervVmHostClient interface {
+	GetVMHost(ctx context.Context) (result VmHost, err error)
+	UpdateVMHost(
+		ctx context.Context,
+		virtualHardDiskPath string,
+		virtualMachinePath string,
+		numaSpanningEnabled bool,
+		enableEnhancedSessionMode bool,
+		resourceMeteringSaveIntervalHours int,
+		macAddressMinimum string,
+		macAddressMaximum string,
+	) (err error)
+	GetVMHostInfo(ctx context.Context) (result VmHostInfo, err error)
+	GetVMHostLiveMigration(ctx context.Context) (result VmHostLiveMigration, err error)
+	UpdateVMHostLiveMigration(
+		ctx context.Context,
+		virtualMachineMigrationEnabled bool,
+		virtualMachineMigrationAuthenticationType VMMigrationAuthenticationType,
+		maximumVirtualMachineMigrations int,
+		virtualMachineMigrationPerformanceOption VMMigrationPerformance,
+		useAnyNetworkForMigration bool,
+		migrationNetworks []string,
+	) (err error)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+)
+
+type VmInfoNetworkAdapter struct {
+	Name        string
+	SwitchName  string
+	MacAddress  string
+	IpAddresses []string
+}
+
+type VmInfoIntegrationService struct {
+	Name                     string
+	Enabled                  bool
+	PrimaryStatusDescription string
+}
+
+type VmInfo struct {
+	Name                string
+	Id                  string
+	Generation          int
+	Version             string
+	State               VmState
+	Path                string
+	Notes               string
+	ProcessorCount      int64
+	DynamicMemory       bool
+	MemoryStartupBytes  int64
+	MemoryAssignedBytes int64
+	UptimeSeconds       int64
+	Heartbeat           string
+	NetworkAdapters     []VmInfoNetworkAdapter
+	HardDiskDrivePaths  []string
+	IntegrationServices []VmInfoIntegrationService
+}
+
+func FlattenVmInfoNetworkAdapters(networkAdapters []VmInfoNetworkAdapter) []interface{} {
+	flattenedNetworkAdapters := make([]interface{}, 0)
+
+	for _, networkAdapter := range networkAdapters {
+		flattenedNetworkAdapter := make(map[string]interface{})
+		flattenedNetworkAdapter["name"] = networkAdapter.Name
+		flattenedNetworkAdapter["switch_name"] = networkAdapter.SwitchName
+		flattenedNetworkAdapter["mac_address"] = networkAdapter.MacAddress
+		flattenedNetworkAdapter["ip_addresses"] = networkAdapter.IpAddresses
+		flattenedNetworkAdapters = append(flattenedNetworkAdapters, flattenedNetworkAdapter)
+	}
+
+	return flattenedNetworkAdapters
+}
+
+func FlattenVmInfoIntegrationServices(integrationServices []VmInfoIntegrationService) []interface{} {
+	flattenedIntegrationServices := make([]interface{}, 0)
+
+	for _, integrationService := range integrationServices {
+		flattenedIntegrationService := make(map[string]interface{})
+		flattenedIntegrationService["name"] = integrationService.Name
+		flattenedIntegrationService["enabled"] = integrationService.Enabled
+		flattenedIntegrationService["status"] = integrationService.PrimaryStatusDescription
+		flattenedIntegrationServices = append(flattenedIntegrationServices, flattenedIntegrationService)
+	}
+
+	return flattenedIntegrationServices
+}
+
+func FlattenVmInfos(vmInfos *[]VmInfo) []interface{} {
+	if vmInfos == nil || len(*vmInfos) < 1 {
+		return nil
+	}
+
+	flattenedVmInfos := make([]interface{}, 0)
+
+	for _, vmInfo := range *vmInfos {
+		flattenedVmInfo := make(map[string]interface{})
+		flattenedVmInfo["name"] = vmInfo.Name
+		flattenedVmInfo["vm_id"] = vmInfo.Id
+		flattenedVmInfo["generation"] = vmInfo.Generation
+		flattenedVmInfo["version"] = vmInfo.Version
+		flattenedVmInfo["state"] = vmInfo.State.String()
+		flattenedVmInfo["path"] = vmInfo.Path
+		flattenedVmInfo["notes"] = vmInfo.Notes
+		flattenedVmInfo["processor_count"] = vmInfo.ProcessorCount
+		flattenedVmInfo["dynamic_memory"] = vmInfo.DynamicMemory
+		flattenedVmInfo["memory_startup_bytes"] = vmInfo.MemoryStartupBytes
+		flattenedVmInfo["memory_assigned_bytes"] = vmInfo.MemoryAssignedBytes
+		flattenedVmInfo["uptime_seconds"] = vmInfo.UptimeSeconds
+		flattenedVmInfo["heartbeat"] = vmInfo.Heartbeat
+		flattenedVmInfo["network_adapters"] = FlattenVmInfoNetworkAdapters(vmInfo.NetworkAdapters)
+		flattenedVmInfo["hard_disk_drive_paths"] = vmInfo.HardDiskDrivePaths
+		flattenedVmInfo["integration_services"] = FlattenVmInfoIntegrationServices(vmInfo.IntegrationServices)
+		flattenedVmInfos = append(flattenedVmInfos, flattenedVmInfo)
+	}
+
+	return flattenedVmInfos
+}
+
+type HypervVmInfoClient interface {
+	GetVmInfo(ctx context.Context, name string, id string) (result VmInfo, err error)
+	GetVmInfos(ctx context.Context) (result []VmInfo, err error)
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
@@ -120,9 +121,92 @@ type VmIntegrationService struct {
 	Enabled bool
 }
 
+type VmGuestIntegrationInfo struct {
+	VmName                     string
+	OsName                     string
+	OsVersion                  string
+	OsBuildNumber              string
+	FullyQualifiedDomainName   string
+	IntegrationServicesVersion string
+	IntegrationServices        []VmInfoIntegrationService
+}
+
+// VmTimeSyncIntegrationServiceId is the id of the integration service that synchronizes the clock of the guest with the
+// HyperV host machine. It is looked up by id as its name is localized.
+const VmTimeSyncIntegrationServiceId = "2497F4DE-E9FA-4204-80E4-4B75C46419C0"
+
+// VmKvpExchangeIntegrationServiceId is the id of the integration service the guest reports its fully qualified domain
+// name with
+const VmKvpExchangeIntegrationServiceId = "2A34B1C2-FD73-4043-8A5B-DD2159BC743F"
+
+const (
+	VmGuestTimeSyncPolicy_Enabled                  = "Enabled"
+	VmGuestTimeSyncPolicy_Disabled                 = "Disabled"
+	VmGuestTimeSyncPolicy_DisabledWhenDomainJoined = "DisabledWhenDomainJoined"
+)
+
+var VmGuestTimeSyncPolicy_value = map[string]string{
+	"enabled":                  VmGuestTimeSyncPolicy_Enabled,
+	"disabled":                 VmGuestTimeSyncPolicy_Disabled,
+	"disabledwhendomainjoined": VmGuestTimeSyncPolicy_DisabledWhenDomainJoined,
+}
+
+// VmGuestTimeSync is whether the time synchronization integration service of a virtual machine is enabled according to
+// its Policy. Domain joined guests get their time from the domain hierarchy, and the time synchronization integration
+// service fights with it, so DisabledWhenDomainJoined disables it once the guest reports a fully qualified domain name
+// with a domain. Enabled, FullyQualifiedDomainName, KvpExchangeEnabled and Status are read back from the virtual
+// machine.
+type VmGuestTimeSync struct {
+	VmName                   string
+	Policy                   string
+	Enabled                  bool
+	FullyQualifiedDomainName string
+	KvpExchangeEnabled       bool
+	Status                   string
+}
+
+func (t *VmGuestTimeSync) Validate() error {
+	if t.VmName == "" {
+		return fmt.Errorf("[ERROR][hyperv] vm name of guest time sync must be specified")
+	}
+
+	if _, found := VmGuestTimeSyncPolicy_value[strings.ToLower(t.Policy)]; !found {
+		return fmt.Errorf("[ERROR][hyperv] policy %s of guest time sync of vm %s must be one of %s, %s or %s", t.Policy, t.VmName, VmGuestTimeSyncPolicy_Enabled, VmGuestTimeSyncPolicy_Disabled, VmGuestTimeSyncPolicy_DisabledWhenDomainJoined)
+	}
+
+	// the guest reports its fully qualified domain name through the key value pair exchange integration service
+	if strings.EqualFold(t.Policy, VmGuestTimeSyncPolicy_DisabledWhenDomainJoined) && !t.KvpExchangeEnabled {
+		return fmt.Errorf("[ERROR][hyperv] policy %s of guest time sync of vm %s requires the key value pair exchange integration service to be enabled", t.Policy, t.VmName)
+	}
+
+	return nil
+}
+
+// DomainJoined is true when the guest reported a fully qualified domain name that includes a domain. It is false when
+// the guest has not reported one yet, e.g. because it is off or has not joined the domain.
+func (t *VmGuestTimeSync) DomainJoined() bool {
+	return strings.Contains(strings.Trim(t.FullyQualifiedDomainName, "."), ".")
+}
+
+// DesiredEnabled is whether the time synchronization integration service should be enabled according to the policy. A
+// guest that has not reported being domain joined keeps it enabled, so that its clock is right when it joins.
+func (t *VmGuestTimeSync) DesiredEnabled() bool {
+	switch strings.ToLower(t.Policy) {
+	case strings.ToLower(VmGuestTimeSyncPolicy_Disabled):
+		return false
+	case strings.ToLower(VmGuestTimeSyncPolicy_DisabledWhenDomainJoined):
+		return !t.DomainJoined()
+	default:
+		return true
+	}
+}
+
 type HypervVmIntegrationServiceClient interface {
 	GetVmIntegrationServices(ctx context.Context, vmName string) (result []VmIntegrationService, err error)
 	EnableVmIntegrationService(ctx context.Context, vmName string, name string) (err error)
 	DisableVmIntegrationService(ctx context.Context, vmName string, name string) (err error)
 	CreateOrUpdateVmIntegrationServices(ctx context.Context, vmName string, integrationServices []VmIntegrationService) (err error)
+	GetVmGuestIntegrationInfo(ctx context.Context, vmName string) (result VmGuestIntegrationInfo, err error)
+	GetVmGuestTimeSync(ctx context.Context, vmName string) (result VmGuestTimeSync, err error)
+	SetVmGuestTimeSyncEnabled(ctx context.Context, vmName string, enabled bool) (err error)
 }
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestVmGuestTimeSyncValidate(t *testing.T) {
+	timeSync := VmGuestTimeSync{VmName: "web", Policy: "disabledWhenDomainJoined", KvpExchangeEnabled: true}
+	if err := timeSync.Validate(); err != nil {
+		t.Errorf("Expected disabled when domain joined with key value pair exchange to be valid, got %s", err)
+	}
+
+	timeSync.KvpExchangeEnabled = false
+	if err := timeSync.Validate(); err == nil {
+		t.Errorf("Expected disabled when domain joined without key value pair exchange to be invalid")
+	}
+
+	timeSync.Policy = VmGuestTimeSyncPolicy_Disabled
+	if err := timeSync.Validate(); err != nil {
+		t.Errorf("Expected disabled without key value pair exchange to be valid, got %s", err)
+	}
+
+	timeSync.Policy = "Sometimes"
+	if err := timeSync.Validate(); err == nil {
+		t.Errorf("Expected an unknown policy to be invalid")
+	}
+
+	timeSync.Policy = VmGuestTimeSyncPolicy_Enabled
+	timeSync.VmName = ""
+	if err := timeSync.Validate(); err == nil {
+		t.Errorf("Expected a time sync without a vm name to be invalid")
+	}
+}
+
+func TestVmGuestTimeSyncDesiredEnabled(t *testing.T) {
+	cases := []struct {
+		policy                   string
+		fullyQualifiedDomainName string
+		expected                 bool
+	}{
+		{VmGuestTimeSyncPolicy_Enabled, "web.contoso.com", true},
+		{VmGuestTimeSyncPolicy_Disabled, "", false},
+		{VmGuestTimeSyncPolicy_DisabledWhenDomainJoined, "", true},
+		{VmGuestTimeSyncPolicy_DisabledWhenDomainJoined, "web", true},
+		{VmGuestTimeSyncPolicy_DisabledWhenDomainJoined, "web.", true},
+		{VmGuestTimeSyncPolicy_DisabledWhenDomainJoined, "web.contoso.com", false},
+	}
+
+	for _, c := range cases {
+		timeSync := VmGuestTimeSync{Policy: c.policy, FullyQualifiedDomainName: c.fullyQualifiedDomainName}
+		if actual := timeSync.DesiredEnabled(); actual != c.expected {
+			t.Errorf("Expected time sync of %q with policy %s to be enabled %t, got %t", c.fullyQualifiedDomainName, c.policy, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// AvmaKeyKvpItemName is the name of the key value pair the automatic virtual machine activation key of a virtual
+// machine is published to the guest as. A Windows guest finds it in the registry under
+// HKLM\SOFTWARE\Microsoft\Virtual Machine\External.
+const AvmaKeyKvpItemName = "AvmaKey"
+
+// DeletionProtectionKvpItemName is the name of the key value pair that marks a virtual machine as protected from
+// deletion on the HyperV host machine. The marker is kept with the virtual machine rather than in the state, so the
+// provider refuses to delete a virtual machine that is set to DeletionProtectionKvpItemValue no matter which
+// configuration destroys it.
+const DeletionProtectionKvpItemName = "TerraformDeletionProtection"
+
+const DeletionProtectionKvpItemValue = "true"
+
+// GuestHostNameKvpItemName and GuestDnsSuffixKvpItemName are the names of the key value pairs the desired host name
+// and primary dns suffix of a guest are published as, so that a domain join orchestrated elsewhere e.g. by a script in
+// the guest can rename the guest and register it in dns. A Windows guest finds them in the registry under
+// HKLM\SOFTWARE\Microsoft\Virtual Machine\External. They are published to the virtual machine, not to one of its
+// network adapters.
+const GuestHostNameKvpItemName = "GuestHostName"
+
+const GuestDnsSuffixKvpItemName = "GuestDnsSuffix"
+
+// GuestHostNameRegexp matches a dns label e.g. web-01, or no host name at all
+var GuestHostNameRegexp = regexp.MustCompile(`^([0-9A-Za-z]([0-9A-Za-z-]{0,61}[0-9A-Za-z])?)?$`)
+
+// GuestDnsSuffixRegexp matches a dns name of one or more labels e.g. corp.contoso.com, or no dns suffix at all
+var GuestDnsSuffixRegexp = regexp.MustCompile(`^([0-9A-Za-z]([0-9A-Za-z-]{0,61}[0-9A-Za-z])?(\.[0-9A-Za-z]([0-9A-Za-z-]{0,61}[0-9A-Za-z])?)*)?$`)
+
+// GuestDnsRegistration is the host name and primary dns suffix published to a guest
+type GuestDnsRegistration struct {
+	HostName  string
+	DnsSuffix string
+}
+
+// FullyQualifiedDomainName is the name the guest is expected to report once it took the host name and dns suffix
+func (r *GuestDnsRegistration) FullyQualifiedDomainName() string {
+	if r.HostName == "" || r.DnsSuffix == "" {
+		return r.HostName
+	}
+
+	return r.HostName + "." + r.DnsSuffix
+}
+
+// IsRegistered is true when the fully qualified domain name reported by the guest is the one it was asked to take. A
+// guest that was only given a dns suffix is registered once it reports a name with the suffix.
+func (r *GuestDnsRegistration) IsRegistered(fullyQualifiedDomainName string) bool {
+	fullyQualifiedDomainName = strings.TrimSuffix(fullyQualifiedDomainName, ".")
+	if fullyQualifiedDomainName == "" {
+		return false
+	}
+
+	if r.HostName == "" {
+		return r.DnsSuffix == "" || strings.HasSuffix(strings.ToLower(fullyQualifiedDomainName), "."+strings.ToLower(r.DnsSuffix))
+	}
+
+	return strings.EqualFold(fullyQualifiedDomainName, r.FullyQualifiedDomainName())
+}
+
+// AvmaKeyRegexp matches a product key e.g. the automatic virtual machine activation key of Windows Server 2022
+// Datacenter W3GNR-8DDXR-2TFRP-H8P33-DV9BG, or no key at all
+var AvmaKeyRegexp = regexp.MustCompile(`^([0-9A-Z]{5}(-[0-9A-Z]{5}){4})?$`)
+
+// HypervVmKvpClient publishes key value pairs from the HyperV host machine to the guest of a virtual machine through
+// the key value pair exchange integration service. Only the items published by the host are managed, the items
+// reported by the guest are read with GetVmGuestIntegrationInfo.
+type HypervVmKvpClient interface {
+	GetVmKvpHostItems(ctx context.Context, vmName string) (result map[string]string, err error)
+	SetVmKvpHostItem(ctx context.Context, vmName string, name string, value string) (err error)
+	RemoveVmKvpHostItem(ctx context.Context, vmName string, name string) (err error)
+}
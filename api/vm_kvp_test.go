@@ -0,0 +1,67 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestAvmaKeyRegexp(t *testing.T) {
+	for _, avmaKey := range []string{"W3GNR-8DDXR-2TFRP-H8P33-DV9BG", ""} {
+		if !AvmaKeyRegexp.MatchString(avmaKey) {
+			t.Errorf("Expected %q to be a valid avma key", avmaKey)
+		}
+	}
+
+	for _, avmaKey := range []string{"w3gnr-8ddxr-2tfrp-h8p33-dv9bg", "W3GNR-8DDXR-2TFRP-H8P33", "W3GNR8DDXR2TFRPH8P33DV9BG"} {
+		if AvmaKeyRegexp.MatchString(avmaKey) {
+			t.Errorf("Expected %q to be an invalid avma key", avmaKey)
+		}
+	}
+}
+
+func TestGuestDnsRegexps(t *testing.T) {
+	for _, hostName := range []string{"web-01", "WEB01", ""} {
+		if !GuestHostNameRegexp.MatchString(hostName) {
+			t.Errorf("Expected %q to be a valid host name", hostName)
+		}
+	}
+
+	for _, hostName := range []string{"-web", "web-", "web.contoso.com", "web_01"} {
+		if GuestHostNameRegexp.MatchString(hostName) {
+			t.Errorf("Expected %q to be an invalid host name", hostName)
+		}
+	}
+
+	for _, dnsSuffix := range []string{"corp.contoso.com", "contoso", ""} {
+		if !GuestDnsSuffixRegexp.MatchString(dnsSuffix) {
+			t.Errorf("Expected %q to be a valid dns suffix", dnsSuffix)
+		}
+	}
+
+	for _, dnsSuffix := range []string{".contoso.com", "corp..contoso.com", "corp.contoso.com."} {
+		if GuestDnsSuffixRegexp.MatchString(dnsSuffix) {
+			t.Errorf("Expected %q to be an invalid dns suffix", dnsSuffix)
+		}
+	}
+}
+
+func TestGuestDnsRegistrationIsRegistered(t *testing.T) {
+	cases := []struct {
+		registration             GuestDnsRegistration
+		fullyQualifiedDomainName string
+		expected                 bool
+	}{
+		{GuestDnsRegistration{HostName: "web", DnsSuffix: "corp.contoso.com"}, "WEB.corp.contoso.com", true},
+		{GuestDnsRegistration{HostName: "web", DnsSuffix: "corp.contoso.com"}, "web.corp.contoso.com.", true},
+		{GuestDnsRegistration{HostName: "web", DnsSuffix: "corp.contoso.com"}, "WIN-3K2L1.corp.contoso.com", false},
+		{GuestDnsRegistration{HostName: "web", DnsSuffix: "corp.contoso.com"}, "", false},
+		{GuestDnsRegistration{HostName: "web"}, "web", true},
+		{GuestDnsRegistration{DnsSuffix: "corp.contoso.com"}, "WIN-3K2L1.corp.contoso.com", true},
+		{GuestDnsRegistration{DnsSuffix: "corp.contoso.com"}, "WIN-3K2L1", false},
+	}
+
+	for _, c := range cases {
+		if actual := c.registration.IsRegistered(c.fullyQualifiedDomainName); actual != c.expected {
+			t.Errorf("Expected %+v to be registered as %q %t, got %t", c.registration, c.fullyQualifiedDomainName, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ParseVmManifest parses a machine specification document, e.g. one produced by a script that exports the inventory
+// of another hypervisor, into the blocks of child devices of a virtual machine. The document is a json object with the
+// names of the blocks as keys, each holding an array of devices with the same keys as the block. Keys that are left
+// out get the default of the block, so a device can be read back with the expand function of the block.
+func ParseVmManifest(manifestJson string, blockSchemas map[string]map[string]*schema.Schema) (result map[string][]interface{}, err error) {
+	result = make(map[string][]interface{})
+	if strings.TrimSpace(manifestJson) == "" {
+		return result, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(manifestJson)))
+	decoder.UseNumber()
+
+	var manifest map[string]interface{}
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("[ERROR][hyperv] manifest is not a json object: %s", err)
+	}
+
+	for blockName, value := range manifest {
+		blockSchema, ok := blockSchemas[blockName]
+		if !ok {
+			return nil, fmt.Errorf("[ERROR][hyperv] manifest has unknown key %q, valid keys are %s", blockName, strings.Join(sortedBlockNames(blockSchemas), ", "))
+		}
+
+		devices, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("[ERROR][hyperv] %s of manifest must be an array", blockName)
+		}
+
+		result[blockName] = make([]interface{}, 0, len(devices))
+		for i, device := range devices {
+			normalizedDevice, err := normalizeManifestObject(fmt.Sprintf("%s.%d", blockName, i), blockSchema, device)
+			if err != nil {
+				return nil, err
+			}
+			result[blockName] = append(result[blockName], normalizedDevice)
+		}
+	}
+
+	return result, nil
+}
+
+func sortedBlockNames(blockSchemas map[string]map[string]*schema.Schema) []string {
+	blockNames := make([]string, 0, len(blockSchemas))
+	for blockName := range blockSchemas {
+		blockNames = append(blockNames, blockName)
+	}
+	sort.Strings(blockNames)
+
+	return blockNames
+}
+
+func sortedSchemaKeys(objectSchema map[string]*schema.Schema) []string {
+	keys := make([]string, 0, len(objectSchema))
+	for key := range objectSchema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func normalizeManifestObject(path string, objectSchema map[string]*schema.Schema, value interface{}) (map[string]interface{}, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("[ERROR][hyperv] %s of manifest must be an object", path)
+	}
+
+	for key := range object {
+		s, ok := objectSchema[key]
+		if !ok {
+			return nil, fmt.Errorf("[ERROR][hyperv] %s of manifest has unknown key %q, valid keys are %s", path, key, strings.Join(sortedSchemaKeys(objectSchema), ", "))
+		}
+		if s.Computed && !s.Optional {
+			return nil, fmt.Errorf("[ERROR][hyperv] %s.%s of manifest is computed, so it can not be set", path, key)
+		}
+	}
+
+	result := make(map[string]interface{})
+	for key, s := range objectSchema {
+		v, ok := object[key]
+		if !ok || v == nil {
+			if s.Required {
+				return nil, fmt.Errorf("[ERROR][hyperv] %s.%s of manifest is required", path, key)
+			}
+			if s.Default != nil {
+				result[key] = s.Default
+			}
+			continue
+		}
+
+		normalizedValue, err := normalizeManifestValue(path+"."+key, s, v)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = normalizedValue
+	}
+
+	return result, nil
+}
+
+func normalizeManifestValue(path string, s *schema.Schema, value interface{}) (interface{}, error) {
+	switch s.Type {
+	case schema.TypeString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case schema.TypeBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case schema.TypeInt:
+		if v, ok := value.(json.Number); ok {
+			if i, err := v.Int64(); err == nil {
+				return int(i), nil
+			}
+		}
+	case schema.TypeFloat:
+		if v, ok := value.(json.Number); ok {
+			if f, err := v.Float64(); err == nil {
+				return f, nil
+			}
+		}
+	case schema.TypeMap:
+		if v, ok := value.(map[string]interface{}); ok {
+			result := make(map[string]interface{})
+			for key, element := range v {
+				element, ok := element.(string)
+				if !ok {
+					return nil, fmt.Errorf("[ERROR][hyperv] %s.%s of manifest must be a string", path, key)
+				}
+				result[key] = element
+			}
+			return result, nil
+		}
+	case schema.TypeList, schema.TypeSet:
+		if v, ok := value.([]interface{}); ok {
+			result := make([]interface{}, 0, len(v))
+			for i, element := range v {
+				elementPath := fmt.Sprintf("%s.%d", path, i)
+
+				var normalizedElement interface{}
+				var err error
+				switch elem := s.Elem.(type) {
+				case *schema.Resource:
+					normalizedElement, err = normalizeManifestObject(elementPath, elem.Schema, element)
+				case *schema.Schema:
+					normalizedElement, err = normalizeManifestValue(elementPath, elem, element)
+				default:
+					err = fmt.Errorf("[ERROR][hyperv] %s of manifest has an unsupported type", elementPath)
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				result = append(result, normalizedElement)
+			}
+			return result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("[ERROR][hyperv] %s of manifest must be a %s", path, strings.ToLower(strings.TrimPrefix(s.Type.String(), "Type")))
+}
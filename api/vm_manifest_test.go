@@ -0,0 +1,101 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var testVmManifestBlockSchemas = map[string]map[string]*schema.Schema{
+	"network_adaptors": {
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"switch_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Default:  "",
+		},
+		"vlan_id": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			Default:  0,
+		},
+		"dhcp_guard": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"ip_addresses": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"allowed_vlan_id_list": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
+			},
+		},
+	},
+}
+
+func TestParseVmManifest(t *testing.T) {
+	result, err := ParseVmManifest(`{"network_adaptors":[{"name":"wan","vlan_id":42,"allowed_vlan_id_list":[1,2]}]}`, testVmManifestBlockSchemas)
+	if err != nil {
+		t.Fatalf("Expected manifest to be parsed, got %s", err)
+	}
+
+	expected := map[string][]interface{}{
+		"network_adaptors": {
+			map[string]interface{}{
+				"name":                 "wan",
+				"switch_name":          "",
+				"vlan_id":              42,
+				"dhcp_guard":           false,
+				"allowed_vlan_id_list": []interface{}{1, 2},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, result)
+	}
+}
+
+func TestParseVmManifestEmpty(t *testing.T) {
+	result, err := ParseVmManifest("", testVmManifestBlockSchemas)
+	if err != nil {
+		t.Fatalf("Expected empty manifest to be parsed, got %s", err)
+	}
+
+	if len(result) != 0 {
+		t.Errorf("Expected no blocks for an empty manifest, got %#v", result)
+	}
+}
+
+func TestParseVmManifestInvalid(t *testing.T) {
+	cases := map[string]string{
+		"not an object":     `[]`,
+		"unknown block":     `{"floppy_drives":[]}`,
+		"block not a list":  `{"network_adaptors":{}}`,
+		"unknown key":       `{"network_adaptors":[{"name":"wan","mac":"00155D000000"}]}`,
+		"missing required":  `{"network_adaptors":[{"switch_name":"wan"}]}`,
+		"computed key":      `{"network_adaptors":[{"name":"wan","ip_addresses":["10.0.0.1"]}]}`,
+		"wrong type":        `{"network_adaptors":[{"name":"wan","vlan_id":"42"}]}`,
+		"fractional int":    `{"network_adaptors":[{"name":"wan","vlan_id":4.2}]}`,
+		"wrong element":     `{"network_adaptors":[{"name":"wan","allowed_vlan_id_list":["1"]}]}`,
+		"device not object": `{"network_adaptors":["wan"]}`,
+	}
+
+	for name, manifestJson := range cases {
+		if _, err := ParseVmManifest(manifestJson, testVmManifestBlockSchemas); err == nil {
+			t.Errorf("Expected %s manifest to be invalid", name)
+		}
+	}
+}
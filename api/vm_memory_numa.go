@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// VmMemoryNuma is how the memory of a virtual machine is laid out over the NUMA nodes of the HyperV host machine. A
+// MaximumAmountPerNumaNodeBytes of 0 means the size of the largest NUMA node of the host, and no PreferredNumaNodes
+// means the memory is allocated from whichever NUMA node the hypervisor picks.
+type VmMemoryNuma struct {
+	MaximumAmountPerNumaNodeBytes int64
+	PreferredNumaNodes            []int
+}
+
+// IsDefault is true when the virtual machine does not need its memory NUMA settings changed after it is created
+func (v *VmMemoryNuma) IsDefault() bool {
+	return v.MaximumAmountPerNumaNodeBytes == 0 && len(v.PreferredNumaNodes) == 0
+}
+
+func ExpandVmMemoryNuma(d *schema.ResourceData) VmMemoryNuma {
+	preferredNumaNodes := make([]int, 0)
+	if v, ok := d.GetOk("memory_preferred_numa_nodes"); ok {
+		for _, numaNode := range v.(*schema.Set).List() {
+			preferredNumaNodes = append(preferredNumaNodes, numaNode.(int))
+		}
+	}
+	sort.Ints(preferredNumaNodes)
+
+	return VmMemoryNuma{
+		MaximumAmountPerNumaNodeBytes: int64((d.Get("memory_maximum_amount_per_numa_node_bytes")).(int)),
+		PreferredNumaNodes:            preferredNumaNodes,
+	}
+}
+
+func DiffSuppressVmMemoryMaximumAmountPerNumaNodeBytes(key, old, new string, d *schema.ResourceData) bool {
+	log.Printf("[DEBUG] '[%s]' Comparing old value '[%v]' with new value '[%v]' ", key, old, new)
+	if new == "0" {
+		// We have not explicitly set a value, so allow any value as we are not tracking it
+		return true
+	}
+
+	return new == old
+}
+
+type HypervVmMemoryNumaClient interface {
+	GetVmMemoryNuma(ctx context.Context, vmName string) (result VmMemoryNuma, err error)
+	SetVmMemoryNuma(ctx context.Context, vmName string, vmMemoryNuma VmMemoryNuma) (err error)
+}
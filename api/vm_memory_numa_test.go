@@ -0,0 +1,41 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestVmMemoryNumaIsDefault(t *testing.T) {
+	cases := []struct {
+		vmMemoryNuma VmMemoryNuma
+		expected     bool
+	}{
+		{VmMemoryNuma{}, true},
+		{VmMemoryNuma{PreferredNumaNodes: []int{}}, true},
+		{VmMemoryNuma{MaximumAmountPerNumaNodeBytes: 4294967296}, false},
+		{VmMemoryNuma{PreferredNumaNodes: []int{1}}, false},
+	}
+
+	for _, c := range cases {
+		if actual := c.vmMemoryNuma.IsDefault(); actual != c.expected {
+			t.Errorf("Expected IsDefault of %+v to be %t, got %t", c.vmMemoryNuma, c.expected, actual)
+		}
+	}
+}
+
+func TestDiffSuppressVmMemoryMaximumAmountPerNumaNodeBytes(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		expected bool
+	}{
+		{"68719476736", "0", true},
+		{"4294967296", "4294967296", true},
+		{"68719476736", "4294967296", false},
+	}
+
+	for _, c := range cases {
+		if actual := DiffSuppressVmMemoryMaximumAmountPerNumaNodeBytes("memory_maximum_amount_per_numa_node_bytes", c.old, c.new, nil); actual != c.expected {
+			t.Errorf("Expected diff from %s to %s to be suppressed %t, got %t", c.old, c.new, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+)
+
+type VmMigration struct {
+	VmName                 string
+	DestinationHost        string
+	IncludeStorage         bool
+	DestinationStoragePath string
+}
+
+type VmMigrationStatus struct {
+	Exists       bool
+	ComputerName string
+	Path         string
+}
+
+type VmMigrationDestinationHost struct {
+	Reachable                            bool
+	Error                                string
+	SourceVirtualMachineMigrationEnabled bool
+}
+
+type HypervVmMigrationClient interface {
+	MoveVm(ctx context.Context, vmName string, destinationHost string, includeStorage bool, destinationStoragePath string) (err error)
+	MoveVmStorage(ctx context.Context, vmName string, destinationStoragePath string) (err error)
+	GetVmMigrationStatus(ctx context.Context, vmName string) (result VmMigrationStatus, err error)
+	TestVmMigrationDestinationHost(ctx context.Context, destinationHost string) (result VmMigrationDestinationHost, err error)
+}
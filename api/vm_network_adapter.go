@@ -139,6 +139,15 @@ func DiffSuppressVmStaticMacAddress(key, old, new string, d *schema.ResourceData
 	return new == old
 }
 
+// DiffSuppressVmDynamicMacAddress ignores that a network adapter which preserves its MAC address on recreate has a
+// static MAC address, as the MAC address it had before it was recreated is kept as its static MAC address
+func DiffSuppressVmDynamicMacAddress(key, old, new string, d *schema.ResourceData) bool {
+	preserveMacOnRecreateKey := strings.TrimSuffix(key, "dynamic_mac_address") + "preserve_mac_on_recreate"
+	preserveMacOnRecreate, ok := d.Get(preserveMacOnRecreateKey).(bool)
+
+	return ok && preserveMacOnRecreate && old == "false" && new == "true"
+}
+
 func ExpandNetworkAdapters(d *schema.ResourceData) ([]VmNetworkAdapter, error) {
 	expandedNetworkAdapters := make([]VmNetworkAdapter, 0)
 
@@ -151,66 +160,71 @@ func ExpandNetworkAdapters(d *schema.ResourceData) ([]VmNetworkAdapter, error) {
 				return nil, fmt.Errorf("[ERROR][hyperv] network_adaptors should be a Hash - was '%+v'", networkAdapter)
 			}
 
-			mandatoryFeatureIdSet := networkAdapter["mandatory_feature_id"].(*schema.Set).List()
-			mandatoryFeatureIds := make([]string, 0)
-			for _, mandatoryFeatureId := range mandatoryFeatureIdSet {
-				mandatoryFeatureIds = append(mandatoryFeatureIds, mandatoryFeatureId.(string))
-			}
+			expandedNetworkAdapters = append(expandedNetworkAdapters, ExpandNetworkAdapter(networkAdapter))
+		}
+	}
 
-			ipAddressesSet := networkAdapter["ip_addresses"].([]interface{})
-			ipAddresses := make([]string, 0)
-			for _, ipAddress := range ipAddressesSet {
-				ipAddresses = append(ipAddresses, ipAddress.(string))
-			}
+	return expandedNetworkAdapters, nil
+}
 
-			expandedNetworkAdapter := VmNetworkAdapter{
-				Name:                                   networkAdapter["name"].(string),
-				SwitchName:                             networkAdapter["switch_name"].(string),
-				ManagementOs:                           networkAdapter["management_os"].(bool),
-				IsLegacy:                               networkAdapter["is_legacy"].(bool),
-				DynamicMacAddress:                      networkAdapter["dynamic_mac_address"].(bool),
-				StaticMacAddress:                       networkAdapter["static_mac_address"].(string),
-				MacAddressSpoofing:                     ToOnOffState(networkAdapter["mac_address_spoofing"].(string)),
-				DhcpGuard:                              ToOnOffState(networkAdapter["dhcp_guard"].(string)),
-				RouterGuard:                            ToOnOffState(networkAdapter["router_guard"].(string)),
-				PortMirroring:                          ToPortMirroring(networkAdapter["port_mirroring"].(string)),
-				IeeePriorityTag:                        ToOnOffState(networkAdapter["ieee_priority_tag"].(string)),
-				VmqWeight:                              networkAdapter["vmq_weight"].(int),
-				IovQueuePairsRequested:                 networkAdapter["iov_queue_pairs_requested"].(int),
-				IovInterruptModeration:                 ToIovInterruptModerationValue(networkAdapter["iov_interrupt_moderation"].(string)),
-				IovWeight:                              networkAdapter["iov_weight"].(int),
-				IpsecOffloadMaximumSecurityAssociation: networkAdapter["ipsec_offload_maximum_security_association"].(int),
-				MaximumBandwidth:                       networkAdapter["maximum_bandwidth"].(int),
-				MinimumBandwidthAbsolute:               networkAdapter["minimum_bandwidth_absolute"].(int),
-				MinimumBandwidthWeight:                 networkAdapter["minimum_bandwidth_weight"].(int),
-				MandatoryFeatureId:                     mandatoryFeatureIds,
-				ResourcePoolName:                       networkAdapter["resource_pool_name"].(string),
-				TestReplicaPoolName:                    networkAdapter["test_replica_pool_name"].(string),
-				TestReplicaSwitchName:                  networkAdapter["test_replica_switch_name"].(string),
-				VirtualSubnetId:                        networkAdapter["virtual_subnet_id"].(int),
-				AllowTeaming:                           ToOnOffState(networkAdapter["allow_teaming"].(string)),
-				NotMonitoredInCluster:                  networkAdapter["not_monitored_in_cluster"].(bool),
-				StormLimit:                             networkAdapter["storm_limit"].(int),
-				DynamicIpAddressLimit:                  networkAdapter["dynamic_ip_address_limit"].(int),
-				DeviceNaming:                           ToOnOffState(networkAdapter["device_naming"].(string)),
-				FixSpeed10G:                            ToOnOffState(networkAdapter["fix_speed_10g"].(string)),
-				PacketDirectNumProcs:                   networkAdapter["packet_direct_num_procs"].(int),
-				PacketDirectModerationCount:            networkAdapter["packet_direct_moderation_count"].(int),
-				PacketDirectModerationInterval:         networkAdapter["packet_direct_moderation_interval"].(int),
-				VrssEnabled:                            networkAdapter["vrss_enabled"].(bool),
-				VmmqEnabled:                            networkAdapter["vmmq_enabled"].(bool),
-				VmmqQueuePairs:                         networkAdapter["vmmq_queue_pairs"].(int),
-				VlanAccess:                             networkAdapter["vlan_access"].(bool),
-				VlanId:                                 networkAdapter["vlan_id"].(int),
-				WaitForIps:                             networkAdapter["wait_for_ips"].(bool),
-				IpAddresses:                            ipAddresses,
-			}
+func ExpandNetworkAdapter(networkAdapter map[string]interface{}) VmNetworkAdapter {
+	mandatoryFeatureIdSet := networkAdapter["mandatory_feature_id"].(*schema.Set).List()
+	mandatoryFeatureIds := make([]string, 0)
+	for _, mandatoryFeatureId := range mandatoryFeatureIdSet {
+		mandatoryFeatureIds = append(mandatoryFeatureIds, mandatoryFeatureId.(string))
+	}
 
-			expandedNetworkAdapters = append(expandedNetworkAdapters, expandedNetworkAdapter)
-		}
+	ipAddressesSet := networkAdapter["ip_addresses"].([]interface{})
+	ipAddresses := make([]string, 0)
+	for _, ipAddress := range ipAddressesSet {
+		ipAddresses = append(ipAddresses, ipAddress.(string))
 	}
 
-	return expandedNetworkAdapters, nil
+	expandedNetworkAdapter := VmNetworkAdapter{
+		Name:                                   networkAdapter["name"].(string),
+		SwitchName:                             networkAdapter["switch_name"].(string),
+		ManagementOs:                           networkAdapter["management_os"].(bool),
+		IsLegacy:                               networkAdapter["is_legacy"].(bool),
+		DynamicMacAddress:                      networkAdapter["dynamic_mac_address"].(bool),
+		StaticMacAddress:                       networkAdapter["static_mac_address"].(string),
+		PreserveMacOnRecreate:                  networkAdapter["preserve_mac_on_recreate"].(bool),
+		MacAddressSpoofing:                     ToOnOffState(networkAdapter["mac_address_spoofing"].(string)),
+		DhcpGuard:                              ToOnOffState(networkAdapter["dhcp_guard"].(string)),
+		RouterGuard:                            ToOnOffState(networkAdapter["router_guard"].(string)),
+		PortMirroring:                          ToPortMirroring(networkAdapter["port_mirroring"].(string)),
+		IeeePriorityTag:                        ToOnOffState(networkAdapter["ieee_priority_tag"].(string)),
+		VmqWeight:                              networkAdapter["vmq_weight"].(int),
+		IovQueuePairsRequested:                 networkAdapter["iov_queue_pairs_requested"].(int),
+		IovInterruptModeration:                 ToIovInterruptModerationValue(networkAdapter["iov_interrupt_moderation"].(string)),
+		IovWeight:                              networkAdapter["iov_weight"].(int),
+		IpsecOffloadMaximumSecurityAssociation: networkAdapter["ipsec_offload_maximum_security_association"].(int),
+		MaximumBandwidth:                       networkAdapter["maximum_bandwidth"].(int),
+		MinimumBandwidthAbsolute:               networkAdapter["minimum_bandwidth_absolute"].(int),
+		MinimumBandwidthWeight:                 networkAdapter["minimum_bandwidth_weight"].(int),
+		MandatoryFeatureId:                     mandatoryFeatureIds,
+		ResourcePoolName:                       networkAdapter["resource_pool_name"].(string),
+		TestReplicaPoolName:                    networkAdapter["test_replica_pool_name"].(string),
+		TestReplicaSwitchName:                  networkAdapter["test_replica_switch_name"].(string),
+		VirtualSubnetId:                        networkAdapter["virtual_subnet_id"].(int),
+		AllowTeaming:                           ToOnOffState(networkAdapter["allow_teaming"].(string)),
+		NotMonitoredInCluster:                  networkAdapter["not_monitored_in_cluster"].(bool),
+		StormLimit:                             networkAdapter["storm_limit"].(int),
+		DynamicIpAddressLimit:                  networkAdapter["dynamic_ip_address_limit"].(int),
+		DeviceNaming:                           ToOnOffState(networkAdapter["device_naming"].(string)),
+		FixSpeed10G:                            ToOnOffState(networkAdapter["fix_speed_10g"].(string)),
+		PacketDirectNumProcs:                   networkAdapter["packet_direct_num_procs"].(int),
+		PacketDirectModerationCount:            networkAdapter["packet_direct_moderation_count"].(int),
+		PacketDirectModerationInterval:         networkAdapter["packet_direct_moderation_interval"].(int),
+		VrssEnabled:                            networkAdapter["vrss_enabled"].(bool),
+		VmmqEnabled:                            networkAdapter["vmmq_enabled"].(bool),
+		VmmqQueuePairs:                         networkAdapter["vmmq_queue_pairs"].(int),
+		VlanAccess:                             networkAdapter["vlan_access"].(bool),
+		VlanId:                                 networkAdapter["vlan_id"].(int),
+		WaitForIps:                             networkAdapter["wait_for_ips"].(bool),
+		IpAddresses:                            ipAddresses,
+	}
+
+	return expandedNetworkAdapter
 }
 
 func FlattenMandatoryFeatureIds(mandatoryFeatureIdStrings []string) *schema.Set {
@@ -234,54 +248,119 @@ func FlattenNetworkAdapters(networkAdapters *[]VmNetworkAdapter) []interface{} {
 
 	flattenedNetworkAdapters := make([]interface{}, 0)
 	for _, networkAdapter := range *networkAdapters {
-		flattenedNetworkAdapter := make(map[string]interface{})
-		flattenedNetworkAdapter["name"] = networkAdapter.Name
-		flattenedNetworkAdapter["switch_name"] = networkAdapter.SwitchName
-		flattenedNetworkAdapter["management_os"] = networkAdapter.ManagementOs
-		flattenedNetworkAdapter["is_legacy"] = networkAdapter.IsLegacy
-		flattenedNetworkAdapter["dynamic_mac_address"] = networkAdapter.DynamicMacAddress
-		flattenedNetworkAdapter["static_mac_address"] = networkAdapter.StaticMacAddress
-		flattenedNetworkAdapter["mac_address_spoofing"] = networkAdapter.MacAddressSpoofing.String()
-		flattenedNetworkAdapter["dhcp_guard"] = networkAdapter.DhcpGuard.String()
-		flattenedNetworkAdapter["router_guard"] = networkAdapter.RouterGuard.String()
-		flattenedNetworkAdapter["port_mirroring"] = networkAdapter.PortMirroring.String()
-		flattenedNetworkAdapter["ieee_priority_tag"] = networkAdapter.IeeePriorityTag.String()
-		flattenedNetworkAdapter["vmq_weight"] = networkAdapter.VmqWeight
-		flattenedNetworkAdapter["iov_queue_pairs_requested"] = networkAdapter.IovQueuePairsRequested
-		flattenedNetworkAdapter["iov_interrupt_moderation"] = networkAdapter.IovInterruptModeration.String()
-		flattenedNetworkAdapter["iov_weight"] = networkAdapter.IovWeight
-		flattenedNetworkAdapter["ipsec_offload_maximum_security_association"] = networkAdapter.IpsecOffloadMaximumSecurityAssociation
-		flattenedNetworkAdapter["maximum_bandwidth"] = networkAdapter.MaximumBandwidth
-		flattenedNetworkAdapter["minimum_bandwidth_absolute"] = networkAdapter.MinimumBandwidthAbsolute
-		flattenedNetworkAdapter["minimum_bandwidth_weight"] = networkAdapter.MinimumBandwidthWeight
-		flattenedNetworkAdapter["mandatory_feature_id"] = FlattenMandatoryFeatureIds(networkAdapter.MandatoryFeatureId)
-		flattenedNetworkAdapter["resource_pool_name"] = networkAdapter.ResourcePoolName
-		flattenedNetworkAdapter["test_replica_pool_name"] = networkAdapter.TestReplicaPoolName
-		flattenedNetworkAdapter["test_replica_switch_name"] = networkAdapter.TestReplicaSwitchName
-		flattenedNetworkAdapter["virtual_subnet_id"] = networkAdapter.VirtualSubnetId
-		flattenedNetworkAdapter["allow_teaming"] = networkAdapter.AllowTeaming.String()
-		flattenedNetworkAdapter["not_monitored_in_cluster"] = networkAdapter.NotMonitoredInCluster
-		flattenedNetworkAdapter["storm_limit"] = networkAdapter.StormLimit
-		flattenedNetworkAdapter["dynamic_ip_address_limit"] = networkAdapter.DynamicIpAddressLimit
-		flattenedNetworkAdapter["device_naming"] = networkAdapter.DeviceNaming.String()
-		flattenedNetworkAdapter["fix_speed_10g"] = networkAdapter.FixSpeed10G.String()
-		flattenedNetworkAdapter["packet_direct_num_procs"] = networkAdapter.PacketDirectNumProcs
-		flattenedNetworkAdapter["packet_direct_moderation_count"] = networkAdapter.PacketDirectModerationCount
-		flattenedNetworkAdapter["packet_direct_moderation_interval"] = networkAdapter.PacketDirectModerationInterval
-		flattenedNetworkAdapter["vrss_enabled"] = networkAdapter.VrssEnabled
-		flattenedNetworkAdapter["vmmq_enabled"] = networkAdapter.VmmqEnabled
-		flattenedNetworkAdapter["vmmq_queue_pairs"] = networkAdapter.VmmqQueuePairs
-		flattenedNetworkAdapter["vlan_access"] = networkAdapter.VlanAccess
-		flattenedNetworkAdapter["vlan_id"] = networkAdapter.VlanId
-		flattenedNetworkAdapter["wait_for_ips"] = networkAdapter.WaitForIps
-		flattenedNetworkAdapter["ip_addresses"] = networkAdapter.IpAddresses
-
-		flattenedNetworkAdapters = append(flattenedNetworkAdapters, flattenedNetworkAdapter)
+		flattenedNetworkAdapters = append(flattenedNetworkAdapters, FlattenNetworkAdapter(networkAdapter))
 	}
 
 	return flattenedNetworkAdapters
 }
 
+func FlattenNetworkAdapter(networkAdapter VmNetworkAdapter) map[string]interface{} {
+	flattenedNetworkAdapter := make(map[string]interface{})
+	flattenedNetworkAdapter["name"] = networkAdapter.Name
+	flattenedNetworkAdapter["switch_name"] = networkAdapter.SwitchName
+	flattenedNetworkAdapter["management_os"] = networkAdapter.ManagementOs
+	flattenedNetworkAdapter["is_legacy"] = networkAdapter.IsLegacy
+	flattenedNetworkAdapter["dynamic_mac_address"] = networkAdapter.DynamicMacAddress
+	flattenedNetworkAdapter["static_mac_address"] = networkAdapter.StaticMacAddress
+	flattenedNetworkAdapter["mac_address_spoofing"] = networkAdapter.MacAddressSpoofing.String()
+	flattenedNetworkAdapter["dhcp_guard"] = networkAdapter.DhcpGuard.String()
+	flattenedNetworkAdapter["router_guard"] = networkAdapter.RouterGuard.String()
+	flattenedNetworkAdapter["port_mirroring"] = networkAdapter.PortMirroring.String()
+	flattenedNetworkAdapter["ieee_priority_tag"] = networkAdapter.IeeePriorityTag.String()
+	flattenedNetworkAdapter["vmq_weight"] = networkAdapter.VmqWeight
+	flattenedNetworkAdapter["iov_queue_pairs_requested"] = networkAdapter.IovQueuePairsRequested
+	flattenedNetworkAdapter["iov_interrupt_moderation"] = networkAdapter.IovInterruptModeration.String()
+	flattenedNetworkAdapter["iov_weight"] = networkAdapter.IovWeight
+	flattenedNetworkAdapter["ipsec_offload_maximum_security_association"] = networkAdapter.IpsecOffloadMaximumSecurityAssociation
+	flattenedNetworkAdapter["maximum_bandwidth"] = networkAdapter.MaximumBandwidth
+	flattenedNetworkAdapter["minimum_bandwidth_absolute"] = networkAdapter.MinimumBandwidthAbsolute
+	flattenedNetworkAdapter["minimum_bandwidth_weight"] = networkAdapter.MinimumBandwidthWeight
+	flattenedNetworkAdapter["mandatory_feature_id"] = FlattenMandatoryFeatureIds(networkAdapter.MandatoryFeatureId)
+	flattenedNetworkAdapter["resource_pool_name"] = networkAdapter.ResourcePoolName
+	flattenedNetworkAdapter["test_replica_pool_name"] = networkAdapter.TestReplicaPoolName
+	flattenedNetworkAdapter["test_replica_switch_name"] = networkAdapter.TestReplicaSwitchName
+	flattenedNetworkAdapter["virtual_subnet_id"] = networkAdapter.VirtualSubnetId
+	flattenedNetworkAdapter["allow_teaming"] = networkAdapter.AllowTeaming.String()
+	flattenedNetworkAdapter["not_monitored_in_cluster"] = networkAdapter.NotMonitoredInCluster
+	flattenedNetworkAdapter["storm_limit"] = networkAdapter.StormLimit
+	flattenedNetworkAdapter["dynamic_ip_address_limit"] = networkAdapter.DynamicIpAddressLimit
+	flattenedNetworkAdapter["device_naming"] = networkAdapter.DeviceNaming.String()
+	flattenedNetworkAdapter["fix_speed_10g"] = networkAdapter.FixSpeed10G.String()
+	flattenedNetworkAdapter["packet_direct_num_procs"] = networkAdapter.PacketDirectNumProcs
+	flattenedNetworkAdapter["packet_direct_moderation_count"] = networkAdapter.PacketDirectModerationCount
+	flattenedNetworkAdapter["packet_direct_moderation_interval"] = networkAdapter.PacketDirectModerationInterval
+	flattenedNetworkAdapter["vrss_enabled"] = networkAdapter.VrssEnabled
+	flattenedNetworkAdapter["vmmq_enabled"] = networkAdapter.VmmqEnabled
+	flattenedNetworkAdapter["vmmq_queue_pairs"] = networkAdapter.VmmqQueuePairs
+	flattenedNetworkAdapter["vlan_access"] = networkAdapter.VlanAccess
+	flattenedNetworkAdapter["vlan_id"] = networkAdapter.VlanId
+	flattenedNetworkAdapter["wait_for_ips"] = networkAdapter.WaitForIps
+	flattenedNetworkAdapter["ip_addresses"] = networkAdapter.IpAddresses
+
+	return flattenedNetworkAdapter
+}
+
+// MatchNetworkAdapters returns, for each desired network adapter, the index of the current network adapter with the
+// same name or -1 if the network adapter has to be created
+func MatchNetworkAdapters(currentNetworkAdapters []VmNetworkAdapter, desiredNetworkAdapters []VmNetworkAdapter) []int {
+	return matchDevices(len(currentNetworkAdapters), len(desiredNetworkAdapters), func(currentIndex int, desiredIndex int) bool {
+		return strings.EqualFold(currentNetworkAdapters[currentIndex].Name, desiredNetworkAdapters[desiredIndex].Name)
+	})
+}
+
+// OrderNetworkAdapters returns the network adapters in the order of the configured network adapters they match
+func OrderNetworkAdapters(networkAdapters []VmNetworkAdapter, configuredNetworkAdapters []VmNetworkAdapter) []VmNetworkAdapter {
+	orderedNetworkAdapters := make([]VmNetworkAdapter, 0, len(networkAdapters))
+	for _, index := range orderDevices(len(networkAdapters), MatchNetworkAdapters(networkAdapters, configuredNetworkAdapters)) {
+		orderedNetworkAdapters = append(orderedNetworkAdapters, networkAdapters[index])
+	}
+
+	return orderedNetworkAdapters
+}
+
+// RecreateNetworkAdapter returns the network adapter to create in place of the current network adapter. When it has a
+// dynamic MAC address and preserves its MAC address on recreate, the MAC address of the current network adapter is
+// used as its static MAC address, so that a Linux guest sees the same network interface e.g. eth0 instead of a new
+// one e.g. eth1, and its netplan configuration still applies. The current network adapter has to be removed first.
+func RecreateNetworkAdapter(currentNetworkAdapter VmNetworkAdapter, networkAdapter VmNetworkAdapter) VmNetworkAdapter {
+	if !networkAdapter.PreserveMacOnRecreate || !networkAdapter.DynamicMacAddress || networkAdapter.StaticMacAddress != "" {
+		return networkAdapter
+	}
+
+	// a dynamic MAC address is only assigned once the virtual machine is started
+	if currentNetworkAdapter.StaticMacAddress == "" {
+		return networkAdapter
+	}
+
+	networkAdapter.DynamicMacAddress = false
+	networkAdapter.StaticMacAddress = currentNetworkAdapter.StaticMacAddress
+
+	return networkAdapter
+}
+
+// CheckMacAddressSpoofingForNestedVirtualization returns an error naming the network adapters connected to a switch
+// without MAC address spoofing, when the virtualization extensions of the processor are exposed to the virtual machine.
+// Nested virtual machines send packets with their own MAC addresses, which HyperV drops unless MAC address spoofing is
+// on, so their networking is broken in a way that is hard to diagnose.
+func CheckMacAddressSpoofingForNestedVirtualization(exposeVirtualizationExtensions bool, networkAdapters []VmNetworkAdapter) error {
+	if !exposeVirtualizationExtensions {
+		return nil
+	}
+
+	names := make([]string, 0)
+	for _, networkAdapter := range networkAdapters {
+		if networkAdapter.SwitchName != "" && networkAdapter.MacAddressSpoofing != OnOffState_On {
+			names = append(names, networkAdapter.Name)
+		}
+	}
+
+	if len(names) > 0 {
+		return fmt.Errorf("[ERROR][hyperv] network adapters %s must have mac_address_spoofing = \"On\" as expose_virtualization_extensions is true, otherwise nested virtual machines can not use the network", strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
 type VmNetworkAdapterWaitForIp struct {
 	Name       string
 	WaitForIps bool
@@ -296,6 +375,7 @@ type VmNetworkAdapter struct {
 	IsLegacy                               bool
 	DynamicMacAddress                      bool
 	StaticMacAddress                       string
+	PreserveMacOnRecreate                  bool
 	MacAddressSpoofing                     OnOffState
 	DhcpGuard                              OnOffState
 	RouterGuard                            OnOffState
@@ -361,6 +441,16 @@ func ExpandVmNetworkAdapterWaitForIps(d *schema.ResourceData) ([]VmNetworkAdapte
 	return expandVmNetworkAdapterWaitForIps, waitForIpsTimeout, waitForIpsPollPeriod, nil
 }
 
+func IsWaitingForVmNetworkAdapterIps(vmNetworkAdaptersWaitForIps []VmNetworkAdapterWaitForIp) bool {
+	for _, vmNetworkAdapterWaitForIps := range vmNetworkAdaptersWaitForIps {
+		if vmNetworkAdapterWaitForIps.WaitForIps {
+			return true
+		}
+	}
+
+	return false
+}
+
 type HypervVmNetworkAdapterClient interface {
 	CreateVmNetworkAdapter(
 		ctx context.Context,
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+)
+
+type VmNetworkAdapterTeamMapping struct {
+	ManagementOs               bool
+	VmName                     string
+	SwitchName                 string
+	NetworkAdapterName         string
+	PhysicalNetworkAdapterName string
+}
+
+type HypervVmNetworkAdapterTeamMappingClient interface {
+	CreateOrUpdateVmNetworkAdapterTeamMapping(
+		ctx context.Context,
+		managementOs bool,
+		vmName string,
+		switchName string,
+		networkAdapterName string,
+		physicalNetworkAdapterName string,
+	) (err error)
+	GetVmNetworkAdapterTeamMapping(
+		ctx context.Context,
+		managementOs bool,
+		vmName string,
+		switchName string,
+		networkAdapterName string,
+	) (result VmNetworkAdapterTeamMapping, err error)
+	DeleteVmNetworkAdapterTeamMapping(
+		ctx context.Context,
+		managementOs bool,
+		vmName string,
+		switchName string,
+		networkAdapterName string,
+	) (err error)
+}
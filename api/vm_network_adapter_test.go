@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -34,3 +35,100 @@ func TestDeserializeVmNetworkAdapter(t *testing.T) {
 		t.Errorf("Unable to deserialize vmNetworkAdapter: %s", err.Error())
 	}
 }
+
+func TestMatchVmNetworkAdapters(t *testing.T) {
+	currentNetworkAdapters := []VmNetworkAdapter{
+		{Name: "wan"},
+		{Name: "LAN"},
+		{Name: "management"},
+	}
+	desiredNetworkAdapters := []VmNetworkAdapter{
+		{Name: "lan"},
+		{Name: "wan"},
+		{Name: "storage"},
+	}
+
+	matches := MatchNetworkAdapters(currentNetworkAdapters, desiredNetworkAdapters)
+	expectedMatches := []int{1, 0, 2}
+	for i, expectedMatch := range expectedMatches {
+		if matches[i] != expectedMatch {
+			t.Errorf("Expected network adapter %d to match %d but matched %d", i, expectedMatch, matches[i])
+		}
+	}
+
+	unmatched := UnmatchedDevices(len(currentNetworkAdapters), MatchNetworkAdapters(currentNetworkAdapters, desiredNetworkAdapters[:2]))
+	if len(unmatched) != 1 || unmatched[0] != 2 {
+		t.Errorf("Expected only network adapter 2 to be unmatched but was %v", unmatched)
+	}
+}
+
+func TestOrderVmNetworkAdapters(t *testing.T) {
+	networkAdapters := []VmNetworkAdapter{
+		{Name: "wan"},
+		{Name: "lan"},
+		{Name: "management"},
+	}
+	configuredNetworkAdapters := []VmNetworkAdapter{
+		{Name: "lan"},
+		{Name: "wan"},
+	}
+
+	orderedNetworkAdapters := OrderNetworkAdapters(networkAdapters, configuredNetworkAdapters)
+	expectedNames := []string{"lan", "wan", "management"}
+	if len(orderedNetworkAdapters) != len(expectedNames) {
+		t.Fatalf("Expected %d network adapters but got %d", len(expectedNames), len(orderedNetworkAdapters))
+	}
+
+	for i, expectedName := range expectedNames {
+		if orderedNetworkAdapters[i].Name != expectedName {
+			t.Errorf("Expected network adapter %d to be %s but was %s", i, expectedName, orderedNetworkAdapters[i].Name)
+		}
+	}
+}
+
+func TestCheckMacAddressSpoofingForNestedVirtualization(t *testing.T) {
+	networkAdapters := []VmNetworkAdapter{
+		{Name: "wan", SwitchName: "External", MacAddressSpoofing: OnOffState_Off},
+		{Name: "lan", SwitchName: "Internal", MacAddressSpoofing: OnOffState_On},
+		{Name: "disconnected", SwitchName: "", MacAddressSpoofing: OnOffState_Off},
+	}
+
+	err := CheckMacAddressSpoofingForNestedVirtualization(false, networkAdapters)
+	if err != nil {
+		t.Errorf("Expected no error without nested virtualization but was %s", err)
+	}
+
+	err = CheckMacAddressSpoofingForNestedVirtualization(true, networkAdapters)
+	if err == nil || !strings.Contains(err.Error(), "wan") || strings.Contains(err.Error(), "lan") || strings.Contains(err.Error(), "disconnected") {
+		t.Errorf("Expected an error for wan only but was %v", err)
+	}
+
+	err = CheckMacAddressSpoofingForNestedVirtualization(true, networkAdapters[1:])
+	if err != nil {
+		t.Errorf("Expected no error when mac address spoofing is on but was %s", err)
+	}
+}
+
+func TestRecreateNetworkAdapter(t *testing.T) {
+	currentNetworkAdapter := VmNetworkAdapter{Name: "eth0", DynamicMacAddress: true, StaticMacAddress: "00155D010203"}
+
+	networkAdapter := RecreateNetworkAdapter(currentNetworkAdapter, VmNetworkAdapter{Name: "eth0", IsLegacy: true, DynamicMacAddress: true, PreserveMacOnRecreate: true})
+	if networkAdapter.DynamicMacAddress || networkAdapter.StaticMacAddress != "00155D010203" {
+		t.Errorf("Expected the MAC address 00155D010203 to be preserved but was dynamic %t static %q", networkAdapter.DynamicMacAddress, networkAdapter.StaticMacAddress)
+	}
+
+	networkAdapter = RecreateNetworkAdapter(currentNetworkAdapter, VmNetworkAdapter{Name: "eth0", IsLegacy: true, DynamicMacAddress: true})
+	if !networkAdapter.DynamicMacAddress || networkAdapter.StaticMacAddress != "" {
+		t.Errorf("Expected a new dynamic MAC address without preserve_mac_on_recreate but was dynamic %t static %q", networkAdapter.DynamicMacAddress, networkAdapter.StaticMacAddress)
+	}
+
+	networkAdapter = RecreateNetworkAdapter(currentNetworkAdapter, VmNetworkAdapter{Name: "eth0", StaticMacAddress: "00155D040506", PreserveMacOnRecreate: true})
+	if networkAdapter.StaticMacAddress != "00155D040506" {
+		t.Errorf("Expected the configured static MAC address 00155D040506 but was %q", networkAdapter.StaticMacAddress)
+	}
+
+	networkAdapter = RecreateNetworkAdapter(VmNetworkAdapter{Name: "eth0", DynamicMacAddress: true}, VmNetworkAdapter{Name: "eth0", DynamicMacAddress: true, PreserveMacOnRecreate: true})
+	if !networkAdapter.DynamicMacAddress || networkAdapter.StaticMacAddress != "" {
+		t.Errorf("Expected a dynamic MAC address when none was assigned yet but was dynamic %t static %q", networkAdapter.DynamicMacAddress, networkAdapter.StaticMacAddress)
+	}
+}
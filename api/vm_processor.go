@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -21,12 +22,25 @@ func DefaultVmProcessors() (interface{}, error) {
 		MaximumCountPerNumaSocket:                    0,
 		EnableHostResourceProtection:                 false,
 		ExposeVirtualizationExtensions:               false,
+		ResourcePoolName:                             "",
+		EnableLegacyApicMode:                         false,
+		L3CacheWays:                                  0,
+		Perfmon:                                      []string{},
 	}
 
 	result = append(result, vmProcessor)
 	return result, nil
 }
 
+// VmProcessorPerfmon_value are the performance monitoring features of the host processor that can be exposed to the
+// guest, for monitoring agents and profilers running in the virtual machine
+var VmProcessorPerfmon_value = map[string]string{
+	"pmu":  "pmu",
+	"lbr":  "lbr",
+	"pebs": "pebs",
+	"ipt":  "ipt",
+}
+
 func DiffSuppressVmProcessorMaximumCountPerNumaNode(key, old, new string, d *schema.ResourceData) bool {
 	log.Printf("[DEBUG] '[%s]' Comparing old value '[%v]' with new value '[%v]' ", key, old, new)
 	if new == "0" {
@@ -71,6 +85,16 @@ func ExpandVmProcessors(d *schema.ResourceData) ([]VmProcessor, error) {
 				MaximumCountPerNumaSocket:                    int32(processor["maximum_count_per_numa_socket"].(int)),
 				EnableHostResourceProtection:                 processor["enable_host_resource_protection"].(bool),
 				ExposeVirtualizationExtensions:               processor["expose_virtualization_extensions"].(bool),
+				ResourcePoolName:                             processor["resource_pool_name"].(string),
+				EnableLegacyApicMode:                         processor["enable_legacy_apic_mode"].(bool),
+				L3CacheWays:                                  int32(processor["l3_cache_ways"].(int)),
+				Perfmon:                                      make([]string, 0),
+			}
+
+			if perfmon, ok := processor["perfmon"].(*schema.Set); ok {
+				for _, feature := range perfmon.List() {
+					expandedVmProcessor.Perfmon = append(expandedVmProcessor.Perfmon, strings.ToLower(feature.(string)))
+				}
 			}
 
 			expandedVmProcessors = append(expandedVmProcessors, expandedVmProcessor)
@@ -99,6 +123,10 @@ func FlattenVmProcessors(vmProcessors *[]VmProcessor) []interface{} {
 		flattenedVmProcessor["maximum_count_per_numa_socket"] = vmProcessor.MaximumCountPerNumaSocket
 		flattenedVmProcessor["enable_host_resource_protection"] = vmProcessor.EnableHostResourceProtection
 		flattenedVmProcessor["expose_virtualization_extensions"] = vmProcessor.ExposeVirtualizationExtensions
+		flattenedVmProcessor["resource_pool_name"] = vmProcessor.ResourcePoolName
+		flattenedVmProcessor["enable_legacy_apic_mode"] = vmProcessor.EnableLegacyApicMode
+		flattenedVmProcessor["l3_cache_ways"] = vmProcessor.L3CacheWays
+		flattenedVmProcessor["perfmon"] = vmProcessor.Perfmon
 		flattenedVmProcessors = append(flattenedVmProcessors, flattenedVmProcessor)
 	}
 
@@ -117,6 +145,10 @@ type VmProcessor struct {
 	MaximumCountPerNumaSocket                    int32
 	EnableHostResourceProtection                 bool
 	ExposeVirtualizationExtensions               bool
+	ResourcePoolName                             string
+	EnableLegacyApicMode                         bool
+	L3CacheWays                                  int32
+	Perfmon                                      []string
 }
 
 type HypervVmProcessorClient interface {
@@ -133,6 +165,10 @@ type HypervVmProcessorClient interface {
 		maximumCountPerNumaSocket int32,
 		enableHostResourceProtection bool,
 		exposeVirtualizationExtensions bool,
+		resourcePoolName string,
+		enableLegacyApicMode bool,
+		l3CacheWays int32,
+		perfmon []string,
 	) (err error)
 	GetVmProcessors(ctx context.Context, vmName string) (result []VmProcessor, err error)
 	CreateOrUpdateVmProcessors(ctx context.Context, vmName string, vmProcessors []VmProcessor) (err error)
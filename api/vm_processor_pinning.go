@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CpuGroupCapFull is the cpu cap of a cpu group that lets its virtual processors use all of the capacity of its
+// logical processors
+const CpuGroupCapFull = 65536
+
+// VmProcessorPinning pins the virtual processors of a virtual machine to LogicalProcessors of the HyperV host machine
+// with a cpu group. CpuCapPercent limits the virtual processors to a percentage of the capacity of those logical
+// processors. CpuGroupsPath is the path of the CpuGroups.exe tool that manages cpu groups. CpuGroupId is read back
+// from the virtual machine, it is empty when the virtual machine is not in a cpu group.
+type VmProcessorPinning struct {
+	VmName            string
+	LogicalProcessors []int
+	CpuCapPercent     int
+	CpuGroupsPath     string
+	CpuGroupId        string
+}
+
+func (p *VmProcessorPinning) Validate(logicalProcessorCount int) error {
+	if p.VmName == "" {
+		return fmt.Errorf("[ERROR][hyperv] vm name of processor pinning must be specified")
+	}
+
+	if len(p.LogicalProcessors) == 0 {
+		return fmt.Errorf("[ERROR][hyperv] logical processors of processor pinning of vm %s must be specified", p.VmName)
+	}
+
+	for _, logicalProcessor := range p.LogicalProcessors {
+		if logicalProcessor < 0 || (logicalProcessorCount > 0 && logicalProcessor >= logicalProcessorCount) {
+			return fmt.Errorf("[ERROR][hyperv] logical processor %d of processor pinning of vm %s must be between 0 and %d", logicalProcessor, p.VmName, logicalProcessorCount-1)
+		}
+	}
+
+	if p.CpuCapPercent < 1 || p.CpuCapPercent > 100 {
+		return fmt.Errorf("[ERROR][hyperv] cpu cap percent of processor pinning of vm %s must be between 1 and 100", p.VmName)
+	}
+
+	return nil
+}
+
+// CpuCap is the cap of the cpu group as CpuGroups.exe expects it, where CpuGroupCapFull is all of the capacity
+func (p *VmProcessorPinning) CpuCap() int {
+	return p.CpuCapPercent * CpuGroupCapFull / 100
+}
+
+// GroupAffinity is the list of logical processors of the cpu group as CpuGroups.exe expects it e.g. 0,1,16,17
+func (p *VmProcessorPinning) GroupAffinity() string {
+	logicalProcessors := append([]int{}, p.LogicalProcessors...)
+	sort.Ints(logicalProcessors)
+
+	parts := make([]string, 0, len(logicalProcessors))
+	for _, logicalProcessor := range logicalProcessors {
+		parts = append(parts, strconv.Itoa(logicalProcessor))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// DesiredCpuGroupId is the id of the cpu group the virtual machine is pinned with. The affinity of a cpu group can not
+// be changed while virtual machines are in it, so the id is derived from the virtual machine and its logical
+// processors and pinning it to other logical processors moves it to a new cpu group.
+func (p *VmProcessorPinning) DesiredCpuGroupId() string {
+	sum := sha1.Sum([]byte(strings.ToLower(p.VmName) + "|" + p.GroupAffinity()))
+	hash := hex.EncodeToString(sum[:])
+
+	return strings.ToUpper(fmt.Sprintf("%s-%s-%s-%s-%s", hash[0:8], hash[8:12], hash[12:16], hash[16:20], hash[20:32]))
+}
+
+type HypervVmProcessorPinningClient interface {
+	CreateOrUpdateVmProcessorPinning(ctx context.Context, vmProcessorPinning VmProcessorPinning) (err error)
+	GetVmProcessorPinning(ctx context.Context, vmName string, cpuGroupsPath string) (result VmProcessorPinning, err error)
+	DeleteVmProcessorPinning(ctx context.Context, vmName string, cpuGroupsPath string) (err error)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestVmProcessorPinningValidate(t *testing.T) {
+	vmProcessorPinning := VmProcessorPinning{VmName: "rt", LogicalProcessors: []int{2, 3}, CpuCapPercent: 100}
+	if err := vmProcessorPinning.Validate(8); err != nil {
+		t.Errorf("Expected pinning to logical processors of the host to be valid, got %s", err)
+	}
+
+	if err := vmProcessorPinning.Validate(2); err == nil {
+		t.Errorf("Expected pinning to logical processors the host does not have to be invalid")
+	}
+
+	vmProcessorPinning.CpuCapPercent = 0
+	if err := vmProcessorPinning.Validate(8); err == nil {
+		t.Errorf("Expected a cpu cap of 0 percent to be invalid")
+	}
+
+	vmProcessorPinning = VmProcessorPinning{VmName: "rt", CpuCapPercent: 100}
+	if err := vmProcessorPinning.Validate(8); err == nil {
+		t.Errorf("Expected pinning without logical processors to be invalid")
+	}
+}
+
+func TestVmProcessorPinningCpuCap(t *testing.T) {
+	cases := []struct {
+		cpuCapPercent int
+		expected      int
+	}{
+		{100, CpuGroupCapFull},
+		{50, 32768},
+		{1, 655},
+	}
+
+	for _, c := range cases {
+		vmProcessorPinning := VmProcessorPinning{CpuCapPercent: c.cpuCapPercent}
+		if actual := vmProcessorPinning.CpuCap(); actual != c.expected {
+			t.Errorf("Expected cpu cap %d for %d percent, got %d", c.expected, c.cpuCapPercent, actual)
+		}
+	}
+}
+
+func TestVmProcessorPinningDesiredCpuGroupId(t *testing.T) {
+	vmProcessorPinning := VmProcessorPinning{VmName: "rt", LogicalProcessors: []int{17, 16, 1, 0}}
+
+	if actual := vmProcessorPinning.GroupAffinity(); actual != "0,1,16,17" {
+		t.Errorf("Expected sorted group affinity, got %s", actual)
+	}
+
+	cpuGroupId := vmProcessorPinning.DesiredCpuGroupId()
+	if len(cpuGroupId) != 36 || cpuGroupId[8] != '-' || cpuGroupId[23] != '-' {
+		t.Errorf("Expected a guid, got %s", cpuGroupId)
+	}
+
+	samePinning := VmProcessorPinning{VmName: "RT", LogicalProcessors: []int{0, 1, 16, 17}}
+	if samePinning.DesiredCpuGroupId() != cpuGroupId {
+		t.Errorf("Expected the cpu group id not to depend on the order of the logical processors or the case of the vm name")
+	}
+
+	otherPinning := VmProcessorPinning{VmName: "rt", LogicalProcessors: []int{0, 1}}
+	if otherPinning.DesiredCpuGroupId() == cpuGroupId {
+		t.Errorf("Expected other logical processors to have another cpu group id")
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type VMReplicationAuthenticationType int
+
+const (
+	VMReplicationAuthenticationType_Kerberos    VMReplicationAuthenticationType = 1
+	VMReplicationAuthenticationType_Certificate VMReplicationAuthenticationType = 2
+)
+
+var VMReplicationAuthenticationType_name = map[VMReplicationAuthenticationType]string{
+	VMReplicationAuthenticationType_Kerberos:    "Kerberos",
+	VMReplicationAuthenticationType_Certificate: "Certificate",
+}
+
+var VMReplicationAuthenticationType_value = map[string]VMReplicationAuthenticationType{
+	"kerberos":    VMReplicationAuthenticationType_Kerberos,
+	"certificate": VMReplicationAuthenticationType_Certificate,
+}
+
+func (x VMReplicationAuthenticationType) String() string {
+	return VMReplicationAuthenticationType_name[x]
+}
+
+func ToVMReplicationAuthenticationType(x string) VMReplicationAuthenticationType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMReplicationAuthenticationType(integerValue)
+	}
+
+	return VMReplicationAuthenticationType_value[strings.ToLower(x)]
+}
+
+func (d *VMReplicationAuthenticationType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMReplicationAuthenticationType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMReplicationAuthenticationType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMReplicationAuthenticationType(s)
+	return nil
+}
+
+type VMReplicationServerAuthenticationType int
+
+const (
+	VMReplicationServerAuthenticationType_Kerberos               VMReplicationServerAuthenticationType = 1
+	VMReplicationServerAuthenticationType_Certificate            VMReplicationServerAuthenticationType = 2
+	VMReplicationServerAuthenticationType_CertificateAndKerberos VMReplicationServerAuthenticationType = 3
+)
+
+var VMReplicationServerAuthenticationType_name = map[VMReplicationServerAuthenticationType]string{
+	VMReplicationServerAuthenticationType_Kerberos:               "Kerberos",
+	VMReplicationServerAuthenticationType_Certificate:            "Certificate",
+	VMReplicationServerAuthenticationType_CertificateAndKerberos: "CertificateAndKerberos",
+}
+
+var VMReplicationServerAuthenticationType_value = map[string]VMReplicationServerAuthenticationType{
+	"kerberos":               VMReplicationServerAuthenticationType_Kerberos,
+	"certificate":            VMReplicationServerAuthenticationType_Certificate,
+	"certificateandkerberos": VMReplicationServerAuthenticationType_CertificateAndKerberos,
+}
+
+func (x VMReplicationServerAuthenticationType) String() string {
+	return VMReplicationServerAuthenticationType_name[x]
+}
+
+func ToVMReplicationServerAuthenticationType(x string) VMReplicationServerAuthenticationType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMReplicationServerAuthenticationType(integerValue)
+	}
+
+	return VMReplicationServerAuthenticationType_value[strings.ToLower(x)]
+}
+
+func (d *VMReplicationServerAuthenticationType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMReplicationServerAuthenticationType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMReplicationServerAuthenticationType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMReplicationServerAuthenticationType(s)
+	return nil
+}
+
+type VmReplication struct {
+	VmName                      string
+	ReplicaServerName           string
+	ReplicaServerPort           int
+	AuthenticationType          VMReplicationAuthenticationType
+	CertificateThumbprint       string
+	ReplicationFrequencySec     int
+	RecoveryHistory             int
+	VssSnapshotFrequencyHour    int
+	CompressionEnabled          bool
+	InitialReplicationStartTime string
+	PrimaryServerName           string
+	State                       string
+	Health                      string
+}
+
+type VmReplicationServer struct {
+	ReplicationEnabled              bool
+	AllowedAuthenticationType       VMReplicationServerAuthenticationType
+	KerberosAuthenticationPort      int
+	CertificateAuthenticationPort   int
+	CertificateThumbprint           string
+	ReplicationAllowedFromAnyServer bool
+	DefaultStorageLocation          string
+}
+
+type VmReplicationAuthorizationEntry struct {
+	AllowedPrimaryServer   string
+	ReplicaStorageLocation string
+	TrustGroup             string
+}
+
+type HypervVmReplicationClient interface {
+	CreateVMReplication(
+		ctx context.Context,
+		vmName string,
+		replicaServerName string,
+		replicaServerPort int,
+		authenticationType VMReplicationAuthenticationType,
+		certificateThumbprint string,
+		replicationFrequencySec int,
+		recoveryHistory int,
+		vssSnapshotFrequencyHour int,
+		compressionEnabled bool,
+		initialReplicationStartTime string,
+	) (err error)
+	GetVMReplication(ctx context.Context, vmName string) (result VmReplication, err error)
+	UpdateVMReplication(
+		ctx context.Context,
+		vmName string,
+		replicaServerName string,
+		replicaServerPort int,
+		authenticationType VMReplicationAuthenticationType,
+		certificateThumbprint string,
+		replicationFrequencySec int,
+		recoveryHistory int,
+		vssSnapshotFrequencyHour int,
+		compressionEnabled bool,
+	) (err error)
+	DeleteVMReplication(ctx context.Context, vmName string) (err error)
+	GetVMReplicationServer(ctx context.Context) (result VmReplicationServer, err error)
+	UpdateVMReplicationServer(
+		ctx context.Context,
+		replicationEnabled bool,
+		allowedAuthenticationType VMReplicationServerAuthenticationType,
+		kerberosAuthenticationPort int,
+		certificateAuthenticationPort int,
+		certificateThumbprint string,
+		replicationAllowedFromAnyServer bool,
+		defaultStorageLocation string,
+	) (err error)
+	CreateVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string, replicaStorageLocation string, trustGroup string) (err error)
+	GetVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string) (result VmReplicationAuthorizationEntry, err error)
+	UpdateVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string, replicaStorageLocation string, trustGroup string) (err error)
+	DeleteVMReplicationAuthorizationEntry(ctx context.Context, allowedPrimaryServer string) (err error)
+}
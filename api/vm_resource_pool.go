@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type VMResourcePoolType int
+
+const (
+	VMResourcePoolType_Memory                 VMResourcePoolType = 0
+	VMResourcePoolType_Processor              VMResourcePoolType = 1
+	VMResourcePoolType_Ethernet               VMResourcePoolType = 2
+	VMResourcePoolType_VHD                    VMResourcePoolType = 3
+	VMResourcePoolType_ISO                    VMResourcePoolType = 4
+	VMResourcePoolType_VFD                    VMResourcePoolType = 5
+	VMResourcePoolType_FibreChannelPort       VMResourcePoolType = 6
+	VMResourcePoolType_FibreChannelConnection VMResourcePoolType = 7
+	VMResourcePoolType_PciExpress             VMResourcePoolType = 8
+)
+
+var VMResourcePoolType_name = map[VMResourcePoolType]string{
+	VMResourcePoolType_Memory:                 "Memory",
+	VMResourcePoolType_Processor:              "Processor",
+	VMResourcePoolType_Ethernet:               "Ethernet",
+	VMResourcePoolType_VHD:                    "VHD",
+	VMResourcePoolType_ISO:                    "ISO",
+	VMResourcePoolType_VFD:                    "VFD",
+	VMResourcePoolType_FibreChannelPort:       "FibreChannelPort",
+	VMResourcePoolType_FibreChannelConnection: "FibreChannelConnection",
+	VMResourcePoolType_PciExpress:             "PciExpress",
+}
+
+var VMResourcePoolType_value = map[string]VMResourcePoolType{
+	"memory":                 VMResourcePoolType_Memory,
+	"processor":              VMResourcePoolType_Processor,
+	"ethernet":               VMResourcePoolType_Ethernet,
+	"vhd":                    VMResourcePoolType_VHD,
+	"iso":                    VMResourcePoolType_ISO,
+	"vfd":                    VMResourcePoolType_VFD,
+	"fibrechannelport":       VMResourcePoolType_FibreChannelPort,
+	"fibrechannelconnection": VMResourcePoolType_FibreChannelConnection,
+	"pciexpress":             VMResourcePoolType_PciExpress,
+}
+
+func (x VMResourcePoolType) String() string {
+	return VMResourcePoolType_name[x]
+}
+
+func ToVMResourcePoolType(x string) VMResourcePoolType {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMResourcePoolType(integerValue)
+	}
+
+	return VMResourcePoolType_value[strings.ToLower(x)]
+}
+
+func (d *VMResourcePoolType) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMResourcePoolType) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMResourcePoolType(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMResourcePoolType(s)
+	return nil
+}
+
+type VmResourcePool struct {
+	Name                    string
+	ResourcePoolType        VMResourcePoolType
+	Paths                   []string
+	ResourceMeteringEnabled bool
+}
+
+type HypervVmResourcePoolClient interface {
+	CreateVmResourcePool(ctx context.Context, name string, resourcePoolType VMResourcePoolType, paths []string, resourceMeteringEnabled bool) (err error)
+	GetVmResourcePool(ctx context.Context, name string, resourcePoolType VMResourcePoolType) (result VmResourcePool, err error)
+	UpdateVmResourcePool(ctx context.Context, name string, resourcePoolType VMResourcePoolType, paths []string, resourceMeteringEnabled bool) (err error)
+	DeleteVmResourcePool(ctx context.Context, name string, resourcePoolType VMResourcePoolType) (err error)
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type VmScheduledTaskAction int
+
+const (
+	VmScheduledTaskAction_Checkpoint  VmScheduledTaskAction = 0
+	VmScheduledTaskAction_OptimizeVhd VmScheduledTaskAction = 1
+	VmScheduledTaskAction_Start       VmScheduledTaskAction = 2
+	VmScheduledTaskAction_Stop        VmScheduledTaskAction = 3
+	VmScheduledTaskAction_Script      VmScheduledTaskAction = 4
+)
+
+var VmScheduledTaskAction_name = map[VmScheduledTaskAction]string{
+	VmScheduledTaskAction_Checkpoint:  "Checkpoint",
+	VmScheduledTaskAction_OptimizeVhd: "OptimizeVhd",
+	VmScheduledTaskAction_Start:       "Start",
+	VmScheduledTaskAction_Stop:        "Stop",
+	VmScheduledTaskAction_Script:      "Script",
+}
+
+var VmScheduledTaskAction_value = map[string]VmScheduledTaskAction{
+	"checkpoint":  VmScheduledTaskAction_Checkpoint,
+	"optimizevhd": VmScheduledTaskAction_OptimizeVhd,
+	"start":       VmScheduledTaskAction_Start,
+	"stop":        VmScheduledTaskAction_Stop,
+	"script":      VmScheduledTaskAction_Script,
+}
+
+func (x VmScheduledTaskAction) String() string {
+	return VmScheduledTaskAction_name[x]
+}
+
+func ToVmScheduledTaskAction(x string) VmScheduledTaskAction {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VmScheduledTaskAction(integerValue)
+	}
+	return VmScheduledTaskAction_value[strings.ToLower(x)]
+}
+
+func (d *VmScheduledTaskAction) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VmScheduledTaskAction) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VmScheduledTaskAction(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVmScheduledTaskAction(s)
+	return nil
+}
+
+type VmScheduledTaskFrequency int
+
+const (
+	VmScheduledTaskFrequency_Daily     VmScheduledTaskFrequency = 0
+	VmScheduledTaskFrequency_Weekly    VmScheduledTaskFrequency = 1
+	VmScheduledTaskFrequency_AtStartup VmScheduledTaskFrequency = 2
+)
+
+var VmScheduledTaskFrequency_name = map[VmScheduledTaskFrequency]string{
+	VmScheduledTaskFrequency_Daily:     "Daily",
+	VmScheduledTaskFrequency_Weekly:    "Weekly",
+	VmScheduledTaskFrequency_AtStartup: "AtStartup",
+}
+
+var VmScheduledTaskFrequency_value = map[string]VmScheduledTaskFrequency{
+	"daily":     VmScheduledTaskFrequency_Daily,
+	"weekly":    VmScheduledTaskFrequency_Weekly,
+	"atstartup": VmScheduledTaskFrequency_AtStartup,
+}
+
+func (x VmScheduledTaskFrequency) String() string {
+	return VmScheduledTaskFrequency_name[x]
+}
+
+func ToVmScheduledTaskFrequency(x string) VmScheduledTaskFrequency {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VmScheduledTaskFrequency(integerValue)
+	}
+	return VmScheduledTaskFrequency_value[strings.ToLower(x)]
+}
+
+func (d *VmScheduledTaskFrequency) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VmScheduledTaskFrequency) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VmScheduledTaskFrequency(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVmScheduledTaskFrequency(s)
+	return nil
+}
+
+// DaysOfWeek_value are the days a weekly scheduled task can run on, keyed by their lower case name
+var DaysOfWeek_value = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// VmScheduledTask is a scheduled task on the HyperV host machine that runs an action against a virtual machine. At is
+// the local time of the host in 24 hour HH:mm format the task runs at, it is ignored by tasks that run at startup.
+// CheckpointRetention is the number of checkpoints taken by the task that are kept, older ones are removed. Script is
+// the body of a PowerShell script block that is called with the name of the virtual machine as -VmName. LastRunTime,
+// NextRunTime and LastTaskResult are read back from the task scheduler.
+type VmScheduledTask struct {
+	VmName              string
+	Name                string
+	Action              VmScheduledTaskAction
+	Script              string
+	CheckpointRetention int32
+	Frequency           VmScheduledTaskFrequency
+	At                  string
+	DaysOfWeek          []string
+	Enabled             bool
+	LastRunTime         string
+	NextRunTime         string
+	LastTaskResult      int64
+}
+
+func (t *VmScheduledTask) Validate() error {
+	if t.VmName == "" {
+		return fmt.Errorf("[ERROR][hyperv] vm name of scheduled task must be specified")
+	}
+
+	if t.Name == "" {
+		return fmt.Errorf("[ERROR][hyperv] name of scheduled task of vm %s must be specified", t.VmName)
+	}
+
+	if t.Action == VmScheduledTaskAction_Script && strings.TrimSpace(t.Script) == "" {
+		return fmt.Errorf("[ERROR][hyperv] script of scheduled task %s of vm %s must be specified for action %s", t.Name, t.VmName, t.Action.String())
+	}
+
+	if t.Action != VmScheduledTaskAction_Script && t.Script != "" {
+		return fmt.Errorf("[ERROR][hyperv] script of scheduled task %s of vm %s can only be specified for action %s", t.Name, t.VmName, VmScheduledTaskAction_Script.String())
+	}
+
+	if t.Action == VmScheduledTaskAction_Checkpoint && t.CheckpointRetention < 1 {
+		return fmt.Errorf("[ERROR][hyperv] checkpoint retention of scheduled task %s of vm %s must be at least 1", t.Name, t.VmName)
+	}
+
+	if t.Frequency != VmScheduledTaskFrequency_AtStartup {
+		if _, err := time.Parse("15:04", t.At); err != nil {
+			return fmt.Errorf("[ERROR][hyperv] at of scheduled task %s of vm %s must be a time in HH:mm format - %s", t.Name, t.VmName, t.At)
+		}
+	}
+
+	if t.Frequency == VmScheduledTaskFrequency_Weekly && len(t.DaysOfWeek) == 0 {
+		return fmt.Errorf("[ERROR][hyperv] days of week of scheduled task %s of vm %s must be specified for frequency %s", t.Name, t.VmName, t.Frequency.String())
+	}
+
+	if t.Frequency != VmScheduledTaskFrequency_Weekly && len(t.DaysOfWeek) > 0 {
+		return fmt.Errorf("[ERROR][hyperv] days of week of scheduled task %s of vm %s can only be specified for frequency %s", t.Name, t.VmName, VmScheduledTaskFrequency_Weekly.String())
+	}
+
+	for _, dayOfWeek := range t.DaysOfWeek {
+		if _, ok := DaysOfWeek_value[strings.ToLower(dayOfWeek)]; !ok {
+			return fmt.Errorf("[ERROR][hyperv] days of week of scheduled task %s of vm %s has an unknown day - %s", t.Name, t.VmName, dayOfWeek)
+		}
+	}
+
+	return nil
+}
+
+// VmScheduledTaskName is the name of the scheduled task of a virtual machine on the HyperV host machine. Names of
+// virtual machines can hold characters that are not valid in task names, so the name is derived from a hash.
+func VmScheduledTaskName(vmName string, name string) string {
+	hash := sha1.Sum([]byte(strings.ToLower(vmName + "|" + name)))
+	return "vm-task-" + hex.EncodeToString(hash[:])[:16]
+}
+
+type HypervVmScheduledTaskClient interface {
+	CreateOrUpdateVmScheduledTask(ctx context.Context, vmScheduledTask VmScheduledTask) (err error)
+	GetVmScheduledTask(ctx context.Context, vmName string, name string) (result VmScheduledTask, err error)
+	DeleteVmScheduledTask(ctx context.Context, vmName string, name string) (err error)
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVmScheduledTaskName(t *testing.T) {
+	taskName := VmScheduledTaskName("web", "nightly-checkpoint")
+
+	if !strings.HasPrefix(taskName, "vm-task-") || len(taskName) != len("vm-task-")+16 {
+		t.Errorf("Unexpected task name %s", taskName)
+	}
+
+	if VmScheduledTaskName("WEB", "Nightly-Checkpoint") != taskName {
+		t.Errorf("Expected names to be compared without case")
+	}
+
+	if VmScheduledTaskName("web", "weekly-optimize") == taskName {
+		t.Errorf("Expected different names to have different task names")
+	}
+}
+
+func TestVmScheduledTaskValidate(t *testing.T) {
+	valid := VmScheduledTask{
+		VmName:              "web",
+		Name:                "nightly-checkpoint",
+		Action:              VmScheduledTaskAction_Checkpoint,
+		CheckpointRetention: 7,
+		Frequency:           VmScheduledTaskFrequency_Daily,
+		At:                  "02:00",
+		Enabled:             true,
+	}
+
+	cases := []struct {
+		name   string
+		modify func(t *VmScheduledTask)
+		valid  bool
+	}{
+		{"daily checkpoint", func(t *VmScheduledTask) {}, true},
+		{"missing vm name", func(t *VmScheduledTask) { t.VmName = "" }, false},
+		{"missing name", func(t *VmScheduledTask) { t.Name = "" }, false},
+		{"no checkpoints retained", func(t *VmScheduledTask) { t.CheckpointRetention = 0 }, false},
+		{"invalid at", func(t *VmScheduledTask) { t.At = "25:00" }, false},
+		{"at startup ignores at", func(t *VmScheduledTask) { t.Frequency = VmScheduledTaskFrequency_AtStartup; t.At = "" }, true},
+		{"weekly without days", func(t *VmScheduledTask) { t.Frequency = VmScheduledTaskFrequency_Weekly }, false},
+		{"weekly with days", func(t *VmScheduledTask) {
+			t.Frequency = VmScheduledTaskFrequency_Weekly
+			t.DaysOfWeek = []string{"Saturday", "sunday"}
+		}, true},
+		{"weekly with unknown day", func(t *VmScheduledTask) {
+			t.Frequency = VmScheduledTaskFrequency_Weekly
+			t.DaysOfWeek = []string{"Caturday"}
+		}, false},
+		{"daily with days", func(t *VmScheduledTask) { t.DaysOfWeek = []string{"Monday"} }, false},
+		{"script without script", func(t *VmScheduledTask) { t.Action = VmScheduledTaskAction_Script }, false},
+		{"script", func(t *VmScheduledTask) {
+			t.Action = VmScheduledTaskAction_Script
+			t.Script = "param($VmName) Get-VM -Name $VmName"
+		}, true},
+		{"script for other action", func(t *VmScheduledTask) { t.Script = "Get-VM" }, false},
+	}
+
+	for _, c := range cases {
+		task := valid
+		c.modify(&task)
+
+		err := task.Validate()
+		if c.valid && err != nil {
+			t.Errorf("Expected %s to be valid, got %s", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("Expected %s to be invalid", c.name)
+		}
+	}
+}
@@ -142,8 +142,35 @@ func (d *VmState) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// Settled returns the state the virtual machine is transitioning to, so that a virtual machine that is starting or
+// stopping is not reported as having drifted from the desired state
+func (x VmState) Settled() VmState {
+	switch x {
+	case VmState_Starting, VmState_Resuming, VmState_Reset, VmState_ForceReboot:
+		return VmState_Running
+	case VmState_Stopping, VmState_ForceShutdown:
+		return VmState_Off
+	case VmState_Saving:
+		return VmState_Saved
+	case VmState_FastSaving:
+		return VmState_FastSaved
+	case VmState_Pausing:
+		return VmState_Paused
+	default:
+		return x
+	}
+}
+
 type VmStatus struct {
-	State VmState
+	Id                       string
+	State                    VmState
+	Status                   string
+	UptimeSeconds            int64
+	CpuUsage                 int
+	MemoryAssigned           int64
+	MemoryDemand             int64
+	Heartbeat                string
+	IntegrationServicesState string
 }
 
 func ExpandVmStateWaitForState(d *schema.ResourceData) (uint32, uint32, error) {
@@ -162,4 +189,10 @@ type HypervVmStatusClient interface {
 		pollPeriod uint32,
 		state VmState,
 	) (err error)
+	// TurnOffVm turns the virtual machine off immediately, like pulling its power cord, rather than shutting down its
+	// guest operating system
+	TurnOffVm(ctx context.Context, vmName string, timeout uint32, pollPeriod uint32) (err error)
+	// WaitForVmHeartbeat waits until the guest operating system of the virtual machine reports a heartbeat, i.e. it has
+	// booted far enough to run its integration services
+	WaitForVmHeartbeat(ctx context.Context, vmName string, timeout uint32, pollPeriod uint32) (err error)
 }
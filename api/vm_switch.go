@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"log"
 	"strconv"
 	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 type VMSwitchBandwidthMode int
@@ -123,20 +126,179 @@ func (d *VMSwitchType) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+type VMSwitchLoadBalancingAlgorithm int
+
+const (
+	VMSwitchLoadBalancingAlgorithm_HyperVPort VMSwitchLoadBalancingAlgorithm = 4
+	VMSwitchLoadBalancingAlgorithm_Dynamic    VMSwitchLoadBalancingAlgorithm = 5
+)
+
+var VMSwitchLoadBalancingAlgorithm_name = map[VMSwitchLoadBalancingAlgorithm]string{
+	VMSwitchLoadBalancingAlgorithm_HyperVPort: "HyperVPort",
+	VMSwitchLoadBalancingAlgorithm_Dynamic:    "Dynamic",
+}
+
+var VMSwitchLoadBalancingAlgorithm_value = map[string]VMSwitchLoadBalancingAlgorithm{
+	"hypervport": VMSwitchLoadBalancingAlgorithm_HyperVPort,
+	"dynamic":    VMSwitchLoadBalancingAlgorithm_Dynamic,
+}
+
+func (x VMSwitchLoadBalancingAlgorithm) String() string {
+	return VMSwitchLoadBalancingAlgorithm_name[x]
+}
+
+func ToVMSwitchLoadBalancingAlgorithm(x string) VMSwitchLoadBalancingAlgorithm {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMSwitchLoadBalancingAlgorithm(integerValue)
+	}
+
+	return VMSwitchLoadBalancingAlgorithm_value[strings.ToLower(x)]
+}
+
+func (d *VMSwitchLoadBalancingAlgorithm) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMSwitchLoadBalancingAlgorithm) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMSwitchLoadBalancingAlgorithm(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMSwitchLoadBalancingAlgorithm(s)
+	return nil
+}
+
+type VMSwitchTeamingMode int
+
+const (
+	VMSwitchTeamingMode_Static            VMSwitchTeamingMode = 0
+	VMSwitchTeamingMode_SwitchIndependent VMSwitchTeamingMode = 1
+	VMSwitchTeamingMode_Lacp              VMSwitchTeamingMode = 2
+)
+
+var VMSwitchTeamingMode_name = map[VMSwitchTeamingMode]string{
+	VMSwitchTeamingMode_Static:            "Static",
+	VMSwitchTeamingMode_SwitchIndependent: "SwitchIndependent",
+	VMSwitchTeamingMode_Lacp:              "Lacp",
+}
+
+var VMSwitchTeamingMode_value = map[string]VMSwitchTeamingMode{
+	"static":            VMSwitchTeamingMode_Static,
+	"switchindependent": VMSwitchTeamingMode_SwitchIndependent,
+	"lacp":              VMSwitchTeamingMode_Lacp,
+}
+
+func (x VMSwitchTeamingMode) String() string {
+	return VMSwitchTeamingMode_name[x]
+}
+
+func ToVMSwitchTeamingMode(x string) VMSwitchTeamingMode {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return VMSwitchTeamingMode(integerValue)
+	}
+
+	return VMSwitchTeamingMode_value[strings.ToLower(x)]
+}
+
+func (d *VMSwitchTeamingMode) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *VMSwitchTeamingMode) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = VMSwitchTeamingMode(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToVMSwitchTeamingMode(s)
+	return nil
+}
+
+func DiffSuppressVmSwitchBandwidthReservationMode(key, old, new string, d *schema.ResourceData) bool {
+	log.Printf("[DEBUG] '[%s]' Comparing old value '[%v]' with new value '[%v]' ", key, old, new)
+	if strings.EqualFold(old, new) {
+		return true
+	}
+
+	if ToVMSwitchBandwidthMode(new) == VMSwitchBandwidthMode_Default {
+		// Hyper-V resolves Default to Weight, or None if the switch is IOV-enabled
+		resolvedMode := VMSwitchBandwidthMode_Weight
+		if d.Get("enable_iov").(bool) {
+			resolvedMode = VMSwitchBandwidthMode_None
+		}
+
+		return ToVMSwitchBandwidthMode(old) == resolvedMode
+	}
+
+	return false
+}
+
+func FlattenVmSwitches(vmSwitches *[]VmSwitch) []interface{} {
+	if vmSwitches == nil || len(*vmSwitches) < 1 {
+		return nil
+	}
+
+	flattenedVmSwitches := make([]interface{}, 0)
+
+	for _, vmSwitch := range *vmSwitches {
+		flattenedVmSwitch := make(map[string]interface{})
+		flattenedVmSwitch["name"] = vmSwitch.Name
+		flattenedVmSwitch["switch_id"] = vmSwitch.Id
+		flattenedVmSwitch["notes"] = vmSwitch.Notes
+		flattenedVmSwitch["switch_type"] = vmSwitch.SwitchType.String()
+		flattenedVmSwitch["allow_management_os"] = vmSwitch.AllowManagementOS
+		flattenedVmSwitch["net_adapter_names"] = vmSwitch.NetAdapterNames
+		flattenedVmSwitch["enable_embedded_teaming"] = vmSwitch.EmbeddedTeamingEnabled
+		flattenedVmSwitch["enable_iov"] = vmSwitch.IovEnabled
+		flattenedVmSwitch["minimum_bandwidth_mode"] = vmSwitch.BandwidthReservationMode.String()
+		flattenedVmSwitches = append(flattenedVmSwitches, flattenedVmSwitch)
+	}
+
+	return flattenedVmSwitches
+}
+
+// DefaultVMSwitchId is the well known id of the built-in "Default Switch". Its name is localized, so it should be looked up by id.
+const DefaultVMSwitchId = "c08cb7b8-9b3c-408e-8e30-5e16a3aeb444"
+
 type VmSwitchExists struct {
 	Exists bool
 }
 
 type VmSwitch struct {
+	Id                                  string
 	Name                                string
 	Notes                               string
 	AllowManagementOS                   bool
+	ManagementOsVlanId                  int
 	EmbeddedTeamingEnabled              bool
 	IovEnabled                          bool
 	PacketDirectEnabled                 bool
 	BandwidthReservationMode            VMSwitchBandwidthMode
 	SwitchType                          VMSwitchType
 	NetAdapterNames                     []string
+	LoadBalancingAlgorithm              VMSwitchLoadBalancingAlgorithm
+	TeamingMode                         VMSwitchTeamingMode
 	DefaultFlowMinimumBandwidthAbsolute int64
 	DefaultFlowMinimumBandwidthWeight   int64
 	DefaultQueueVmmqEnabled             bool
@@ -144,6 +306,23 @@ type VmSwitch struct {
 	DefaultQueueVrssEnabled             bool
 }
 
+type VmSwitchNetAdapterIovSupport struct {
+	Name         string
+	SriovSupport string
+}
+
+type VmSwitchIovSupport struct {
+	HostIovSupport        bool
+	HostIovSupportReasons []string
+	NetAdapters           []VmSwitchNetAdapterIovSupport
+}
+
+// VmSwitchNetworkAdapter is a network adapter of a virtual machine that is connected to a switch
+type VmSwitchNetworkAdapter struct {
+	VmName string
+	Name   string
+}
+
 type HypervVmSwitchClient interface {
 	VMSwitchExists(ctx context.Context, name string) (result VmSwitchExists, err error)
 	CreateVMSwitch(
@@ -151,12 +330,35 @@ type HypervVmSwitchClient interface {
 		name string,
 		notes string,
 		allowManagementOS bool,
+		managementOsVlanId int,
+		embeddedTeamingEnabled bool,
+		iovEnabled bool,
+		packetDirectEnabled bool,
+		bandwidthReservationMode VMSwitchBandwidthMode,
+		switchType VMSwitchType,
+		netAdapterNames []string,
+		loadBalancingAlgorithm VMSwitchLoadBalancingAlgorithm,
+		teamingMode VMSwitchTeamingMode,
+		defaultFlowMinimumBandwidthAbsolute int64,
+		defaultFlowMinimumBandwidthWeight int64,
+		defaultQueueVmmqEnabled bool,
+		defaultQueueVmmqQueuePairs int32,
+		defaultQueueVrssEnabled bool,
+	) (err error)
+	RecreateVMSwitch(
+		ctx context.Context,
+		name string,
+		notes string,
+		allowManagementOS bool,
+		managementOsVlanId int,
 		embeddedTeamingEnabled bool,
 		iovEnabled bool,
 		packetDirectEnabled bool,
 		bandwidthReservationMode VMSwitchBandwidthMode,
 		switchType VMSwitchType,
 		netAdapterNames []string,
+		loadBalancingAlgorithm VMSwitchLoadBalancingAlgorithm,
+		teamingMode VMSwitchTeamingMode,
 		defaultFlowMinimumBandwidthAbsolute int64,
 		defaultFlowMinimumBandwidthWeight int64,
 		defaultQueueVmmqEnabled bool,
@@ -164,17 +366,22 @@ type HypervVmSwitchClient interface {
 		defaultQueueVrssEnabled bool,
 	) (err error)
 	GetVMSwitch(ctx context.Context, name string) (result VmSwitch, err error)
+	GetVMSwitches(ctx context.Context) (result []VmSwitch, err error)
+	GetDefaultVMSwitch(ctx context.Context) (result VmSwitch, err error)
 	UpdateVMSwitch(
 		ctx context.Context,
 		name string,
 		notes string,
 		allowManagementOS bool,
+		managementOsVlanId int,
 		// embeddedTeamingEnabled bool,
 		// iovEnabled bool,
 		// packetDirectEnabled bool,
 		// bandwidthReservationMode VMSwitchBandwidthMode,
 		switchType VMSwitchType,
 		netAdapterNames []string,
+		loadBalancingAlgorithm VMSwitchLoadBalancingAlgorithm,
+		teamingMode VMSwitchTeamingMode,
 		defaultFlowMinimumBandwidthAbsolute int64,
 		defaultFlowMinimumBandwidthWeight int64,
 		defaultQueueVmmqEnabled bool,
@@ -182,4 +389,7 @@ type HypervVmSwitchClient interface {
 		defaultQueueVrssEnabled bool,
 	) (err error)
 	DeleteVMSwitch(ctx context.Context, name string) (err error)
+	GetVMSwitchNetworkAdapters(ctx context.Context, name string) (result []VmSwitchNetworkAdapter, err error)
+	DisconnectVMSwitchNetworkAdapters(ctx context.Context, name string) (err error)
+	GetVMSwitchIovSupport(ctx context.Context, netAdapterNames []string) (result VmSwitchIovSupport, err error)
 }
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+)
+
+// VmSwitchPacketCaptureExtensionName is the name of the switch extension that captures the packets of a switch for
+// pktmon and netsh trace
+const VmSwitchPacketCaptureExtensionName = "Microsoft NDIS Capture"
+
+func FlattenVmSwitchExtensions(vmSwitchExtensions *[]VmSwitchExtension) []interface{} {
+	if vmSwitchExtensions == nil || len(*vmSwitchExtensions) < 1 {
+		return nil
+	}
+
+	flattenedVmSwitchExtensions := make([]interface{}, 0)
+
+	for _, vmSwitchExtension := range *vmSwitchExtensions {
+		flattenedVmSwitchExtension := make(map[string]interface{})
+		flattenedVmSwitchExtension["switch_name"] = vmSwitchExtension.SwitchName
+		flattenedVmSwitchExtension["name"] = vmSwitchExtension.Name
+		flattenedVmSwitchExtension["id"] = vmSwitchExtension.Id
+		flattenedVmSwitchExtension["vendor"] = vmSwitchExtension.Vendor
+		flattenedVmSwitchExtension["version"] = vmSwitchExtension.Version
+		flattenedVmSwitchExtension["extension_type"] = vmSwitchExtension.ExtensionType
+		flattenedVmSwitchExtension["enabled"] = vmSwitchExtension.Enabled
+		flattenedVmSwitchExtension["running"] = vmSwitchExtension.Running
+		flattenedVmSwitchExtensions = append(flattenedVmSwitchExtensions, flattenedVmSwitchExtension)
+	}
+
+	return flattenedVmSwitchExtensions
+}
+
+type VmSwitchExtension struct {
+	SwitchName    string
+	Name          string
+	Id            string
+	Vendor        string
+	Version       string
+	ExtensionType string
+	Enabled       bool
+	Running       bool
+}
+
+type HypervVmSwitchExtensionClient interface {
+	GetVMSwitchExtension(ctx context.Context, switchName string, name string) (result VmSwitchExtension, err error)
+	GetVMSwitchExtensions(ctx context.Context, switchName string) (result []VmSwitchExtension, err error)
+	GetAllVMSwitchExtensions(ctx context.Context) (result []VmSwitchExtension, err error)
+	UpdateVMSwitchExtension(ctx context.Context, switchName string, name string, enabled bool) (err error)
+}
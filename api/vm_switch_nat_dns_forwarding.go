@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+)
+
+// VmSwitchNatDnsForwarding is the Windows DNS Server role on the HyperV host machine answering the queries of the
+// virtual machines on an internal or NAT switch, by forwarding them to Forwarders. The DNS server listens on the
+// SwitchAddresses of the host network adapter of the switch, so the guests use the gateway address of the NAT as their
+// DNS server. When Forwarders is empty the DNS servers the HyperV host machine uses itself are the forwarders, like
+// internet connection sharing does.
+type VmSwitchNatDnsForwarding struct {
+	SwitchName        string
+	SwitchAddresses   []string
+	ListenAddresses   []string
+	Forwarders        []string
+	ForwardingTimeout int
+}
+
+// IsListeningOnSwitch returns whether the DNS server answers queries on the host network adapter of the switch. A DNS
+// server without listen addresses listens on all the addresses of the HyperV host machine.
+func (f *VmSwitchNatDnsForwarding) IsListeningOnSwitch() bool {
+	if len(f.SwitchAddresses) == 0 {
+		return false
+	}
+
+	if len(f.ListenAddresses) == 0 {
+		return true
+	}
+
+	for _, switchAddress := range f.SwitchAddresses {
+		for _, listenAddress := range f.ListenAddresses {
+			if switchAddress == listenAddress {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type HypervVmSwitchNatDnsForwardingClient interface {
+	CreateOrUpdateVmSwitchNatDnsForwarding(ctx context.Context, vmSwitchNatDnsForwarding VmSwitchNatDnsForwarding) (err error)
+	GetVmSwitchNatDnsForwarding(ctx context.Context, switchName string) (result VmSwitchNatDnsForwarding, err error)
+	DeleteVmSwitchNatDnsForwarding(ctx context.Context, switchName string) (err error)
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestVmSwitchNatDnsForwardingIsListeningOnSwitch(t *testing.T) {
+	tests := []struct {
+		switchAddresses []string
+		listenAddresses []string
+		expected        bool
+	}{
+		{[]string{"192.168.100.1"}, []string{"192.168.100.1"}, true},
+		{[]string{"192.168.100.1"}, []string{"127.0.0.1", "192.168.100.1"}, true},
+		{[]string{"192.168.100.1"}, []string{"192.168.200.1"}, false},
+		{[]string{"192.168.100.1"}, []string{}, true},
+		{[]string{}, []string{}, false},
+	}
+
+	for _, test := range tests {
+		vmSwitchNatDnsForwarding := VmSwitchNatDnsForwarding{
+			SwitchAddresses: test.switchAddresses,
+			ListenAddresses: test.listenAddresses,
+		}
+
+		if actual := vmSwitchNatDnsForwarding.IsListeningOnSwitch(); actual != test.expected {
+			t.Errorf("Expected listening on switch with addresses %v for listen addresses %v to be %t but was %t", test.switchAddresses, test.listenAddresses, test.expected, actual)
+		}
+	}
+}
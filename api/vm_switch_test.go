@@ -55,3 +55,63 @@ func TestDeserializeVmSwitch(t *testing.T) {
 		t.Errorf("Unable to deserialize vm switch: %s", err.Error())
 	}
 }
+
+func TestDeserializeVmSwitchTeam(t *testing.T) {
+	var vmSwitchJson = `
+{
+    "BandwidthReservationMode":  2,
+    "NetAdapterNames":  [
+                            "NIC1",
+                            "NIC2"
+                        ],
+    "Name":  "test",
+    "SwitchType":  2,
+    "EmbeddedTeamingEnabled":  true,
+    "LoadBalancingAlgorithm":  "HyperVPort",
+    "TeamingMode":  "SwitchIndependent"
+}
+`
+
+	var vmSwitch VmSwitch
+	err := json.Unmarshal([]byte(vmSwitchJson), &vmSwitch)
+
+	if err != nil {
+		t.Errorf("Unable to deserialize vm switch: %s", err.Error())
+	}
+
+	if vmSwitch.LoadBalancingAlgorithm != VMSwitchLoadBalancingAlgorithm_HyperVPort {
+		t.Errorf("Expected load balancing algorithm %s, got %s", VMSwitchLoadBalancingAlgorithm_HyperVPort, vmSwitch.LoadBalancingAlgorithm)
+	}
+
+	if vmSwitch.TeamingMode != VMSwitchTeamingMode_SwitchIndependent {
+		t.Errorf("Expected teaming mode %s, got %s", VMSwitchTeamingMode_SwitchIndependent, vmSwitch.TeamingMode)
+	}
+}
+
+func TestDeserializeVmSwitchIovSupport(t *testing.T) {
+	var vmSwitchIovSupportJson = `
+{
+    "HostIovSupport":  true,
+    "HostIovSupportReasons":  [
+
+                              ],
+    "NetAdapters":  [
+                        {
+                            "Name":  "NIC1",
+                            "SriovSupport":  "Supported"
+                        }
+                    ]
+}
+`
+
+	var vmSwitchIovSupport VmSwitchIovSupport
+	err := json.Unmarshal([]byte(vmSwitchIovSupportJson), &vmSwitchIovSupport)
+
+	if err != nil {
+		t.Errorf("Unable to deserialize vm switch iov support: %s", err.Error())
+	}
+
+	if len(vmSwitchIovSupport.NetAdapters) != 1 || vmSwitchIovSupport.NetAdapters[0].SriovSupport != "Supported" {
+		t.Errorf("Unexpected net adapters iov support: %+v", vmSwitchIovSupport.NetAdapters)
+	}
+}
@@ -36,3 +36,85 @@ func TestDeserializeVm(t *testing.T) {
 		t.Errorf("Unable to deserialize vm: %s", err.Error())
 	}
 }
+
+func TestVmStateSettled(t *testing.T) {
+	settledStates := map[VmState]VmState{
+		VmState_Running:          VmState_Running,
+		VmState_Starting:         VmState_Running,
+		VmState_Resuming:         VmState_Running,
+		VmState_Off:              VmState_Off,
+		VmState_Stopping:         VmState_Off,
+		VmState_Saving:           VmState_Saved,
+		VmState_Pausing:          VmState_Paused,
+		VmState_StartingCritical: VmState_StartingCritical,
+	}
+
+	for state, expectedState := range settledStates {
+		if state.Settled() != expectedState {
+			t.Errorf("Expected %s to settle as %s but was %s", state, expectedState, state.Settled())
+		}
+	}
+}
+
+func TestDeserializeVmStatus(t *testing.T) {
+	var vmStatusJson = `
+{
+    "State":  2,
+    "Status":  "Operating normally",
+    "UptimeSeconds":  3600,
+    "CpuUsage":  4,
+    "MemoryAssigned":  2147483648,
+    "MemoryDemand":  1073741824
+}
+`
+
+	var vmStatus VmStatus
+	err := json.Unmarshal([]byte(vmStatusJson), &vmStatus)
+	if err != nil {
+		t.Errorf("Unable to deserialize vmStatus: %s", err.Error())
+	}
+
+	if vmStatus.State != VmState_Running || vmStatus.UptimeSeconds != 3600 || vmStatus.MemoryDemand != 1073741824 {
+		t.Errorf("Unexpected vmStatus: %+v", vmStatus)
+	}
+}
+
+func TestGetVmResizeMethod(t *testing.T) {
+	tests := []struct {
+		generation            int
+		dynamicMemory         bool
+		oldMemoryStartupBytes int64
+		newMemoryStartupBytes int64
+		processorCountChanged bool
+		expected              string
+	}{
+		{2, false, 1073741824, 1073741824, false, VmResizeMethod_None},
+		{2, false, 1073741824, 2147483648, false, VmResizeMethod_HotAdd},
+		{2, false, 2147483648, 1073741824, false, VmResizeMethod_Offline},
+		{2, true, 1073741824, 2147483648, false, VmResizeMethod_Offline},
+		{1, false, 1073741824, 2147483648, false, VmResizeMethod_Offline},
+		{2, false, 1073741824, 1073741824, true, VmResizeMethod_Offline},
+		{2, false, 1073741824, 2147483648, true, VmResizeMethod_Offline},
+	}
+
+	for _, test := range tests {
+		actual := GetVmResizeMethod(test.generation, test.dynamicMemory, test.oldMemoryStartupBytes, test.newMemoryStartupBytes, test.processorCountChanged)
+		if actual != test.expected {
+			t.Errorf("Expected resize method %s for %+v but was %s", test.expected, test, actual)
+		}
+	}
+}
+
+func TestCheckGuestStateIsolation(t *testing.T) {
+	if err := CheckGuestStateIsolation(GuestStateIsolationType_TrustedLaunch, 2); err != nil {
+		t.Errorf("Expected trusted launch to be valid for a generation 2 vm: %s", err)
+	}
+
+	if err := CheckGuestStateIsolation(GuestStateIsolationType_Disabled, 1); err != nil {
+		t.Errorf("Expected no guest state isolation to be valid for a generation 1 vm: %s", err)
+	}
+
+	if err := CheckGuestStateIsolation(GuestStateIsolationType_TrustedLaunch, 1); err == nil {
+		t.Errorf("Expected an error for trusted launch on a generation 1 vm")
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// VmTpmStateBackupSettings describes where the key protector of a virtual machine with a virtual TPM is exported to.
+// The key protector is encrypted for the guardian of the HyperV host machine, so a virtual machine can only be started
+// on another host, or on the same host after it is reinstalled, when the certificates of the guardian are exported
+// too. GuardianPath is the folder the certificates are exported to as pfx files protected with GuardianPassword.
+type VmTpmStateBackupSettings struct {
+	VmName           string
+	Path             string
+	GuardianPath     string
+	GuardianPassword string
+}
+
+func (s *VmTpmStateBackupSettings) Validate() error {
+	if s.VmName == "" {
+		return fmt.Errorf("[ERROR][hyperv] vm name of tpm state backup must be specified")
+	}
+
+	if s.Path == "" {
+		return fmt.Errorf("[ERROR][hyperv] path of tpm state backup of vm %s must be specified", s.VmName)
+	}
+
+	if s.GuardianPath != "" && s.GuardianPassword == "" {
+		return fmt.Errorf("[ERROR][hyperv] guardian password of tpm state backup of vm %s must be specified to export the certificates of the guardian", s.VmName)
+	}
+
+	return nil
+}
+
+// VmTpmStateBackup is the key protector of a virtual machine compared to its backup. KeyProtectorHash is empty when the
+// virtual machine no longer exists, BackupKeyProtectorHash is empty when the backup file does not exist and
+// GuardianBackupExists is false when the pfx files of either certificate of the guardian are missing.
+type VmTpmStateBackup struct {
+	VmName                       string
+	TpmEnabled                   bool
+	KeyProtectorHash             string
+	BackupKeyProtectorHash       string
+	GuardianName                 string
+	GuardianSigningThumbprint    string
+	GuardianEncryptionThumbprint string
+	GuardianBackupExists         bool
+}
+
+// IsUpToDate is true when the backup holds the current key protector of the virtual machine, or the virtual machine no
+// longer exists and the backup is all that is left of its key protector
+func (b *VmTpmStateBackup) IsUpToDate(guardianPath string) bool {
+	if b.BackupKeyProtectorHash == "" {
+		return false
+	}
+
+	if b.KeyProtectorHash != "" && b.KeyProtectorHash != b.BackupKeyProtectorHash {
+		return false
+	}
+
+	return guardianPath == "" || b.GuardianBackupExists
+}
+
+type HypervVmTpmStateBackupClient interface {
+	CreateOrUpdateVmTpmStateBackup(ctx context.Context, settings VmTpmStateBackupSettings) (err error)
+	GetVmTpmStateBackup(ctx context.Context, vmName string, path string, guardianPath string) (result VmTpmStateBackup, err error)
+	DeleteVmTpmStateBackup(ctx context.Context, path string, guardianPath string) (err error)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestVmTpmStateBackupSettingsValidate(t *testing.T) {
+	settings := VmTpmStateBackupSettings{VmName: "web", Path: `C:\Backup\web.kp`}
+	if err := settings.Validate(); err != nil {
+		t.Errorf("Expected settings without a guardian backup to be valid, got %s", err)
+	}
+
+	settings.GuardianPath = `C:\Backup\Guardian`
+	if err := settings.Validate(); err == nil {
+		t.Errorf("Expected a guardian backup without a password to be invalid")
+	}
+
+	settings.GuardianPassword = "P@ssw0rd"
+	if err := settings.Validate(); err != nil {
+		t.Errorf("Expected settings with a guardian backup and a password to be valid, got %s", err)
+	}
+
+	settings.Path = ""
+	if err := settings.Validate(); err == nil {
+		t.Errorf("Expected settings without a path to be invalid")
+	}
+}
+
+func TestVmTpmStateBackupIsUpToDate(t *testing.T) {
+	cases := []struct {
+		name         string
+		backup       VmTpmStateBackup
+		guardianPath string
+		expected     bool
+	}{
+		{"current", VmTpmStateBackup{KeyProtectorHash: "AB", BackupKeyProtectorHash: "AB"}, "", true},
+		{"missing", VmTpmStateBackup{KeyProtectorHash: "AB"}, "", false},
+		{"stale", VmTpmStateBackup{KeyProtectorHash: "CD", BackupKeyProtectorHash: "AB"}, "", false},
+		{"vm removed", VmTpmStateBackup{BackupKeyProtectorHash: "AB"}, "", true},
+		{"guardian missing", VmTpmStateBackup{KeyProtectorHash: "AB", BackupKeyProtectorHash: "AB"}, `C:\Backup\Guardian`, false},
+		{"guardian exported", VmTpmStateBackup{KeyProtectorHash: "AB", BackupKeyProtectorHash: "AB", GuardianBackupExists: true}, `C:\Backup\Guardian`, true},
+	}
+
+	for _, c := range cases {
+		if actual := c.backup.IsUpToDate(c.guardianPath); actual != c.expected {
+			t.Errorf("Expected %s backup to be up to date %t, got %t", c.name, c.expected, actual)
+		}
+	}
+}
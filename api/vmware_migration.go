@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type DiskConverter int
+
+const (
+	DiskConverter_Auto     DiskConverter = 0
+	DiskConverter_QemuImg  DiskConverter = 1
+	DiskConverter_StarWind DiskConverter = 2
+)
+
+var DiskConverter_name = map[DiskConverter]string{
+	DiskConverter_Auto:     "Auto",
+	DiskConverter_QemuImg:  "QemuImg",
+	DiskConverter_StarWind: "StarWind",
+}
+
+var DiskConverter_value = map[string]DiskConverter{
+	"auto":     DiskConverter_Auto,
+	"qemuimg":  DiskConverter_QemuImg,
+	"starwind": DiskConverter_StarWind,
+}
+
+func (x DiskConverter) String() string {
+	return DiskConverter_name[x]
+}
+
+func ToDiskConverter(x string) DiskConverter {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return DiskConverter(integerValue)
+	}
+	return DiskConverter_value[strings.ToLower(x)]
+}
+
+func (d *DiskConverter) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *DiskConverter) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = DiskConverter(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToDiskConverter(s)
+	return nil
+}
+
+// VmwareMigrationSettings describes a virtual machine of VMware to migrate to HyperV. SourcePath is either an ova, an
+// ovf or a vmdk, the disks of the virtual machine are converted to dynamic vhdx files in DestinationPath. The settings
+// of a vmdk are read from the vmx next to it when there is one.
+type VmwareMigrationSettings struct {
+	SourcePath      string
+	DestinationPath string
+	Converter       DiskConverter
+	ConverterPath   string
+}
+
+func (s *VmwareMigrationSettings) Validate() error {
+	extension := strings.ToLower(filepath.Ext(strings.ReplaceAll(s.SourcePath, "\\", "/")))
+	if extension != ".ova" && extension != ".ovf" && extension != ".vmdk" {
+		return fmt.Errorf("[ERROR][hyperv] source path of vmware migration must be an ova, ovf or vmdk - was %s", s.SourcePath)
+	}
+
+	if s.Converter == DiskConverter_Auto && s.ConverterPath != "" {
+		return fmt.Errorf("[ERROR][hyperv] converter of vmware migration %s must be specified to use converter path %s", s.SourcePath, s.ConverterPath)
+	}
+
+	return nil
+}
+
+// VmwareMigration is a virtual machine of VMware that has been migrated. Firmware is Bios or Uefi, and HardDiskPaths
+// holds the converted disks in the order of the disks of the source, the first one being the boot disk.
+type VmwareMigration struct {
+	Name                string
+	Firmware            string
+	ProcessorCount      int64
+	MemoryStartupBytes  int64
+	NetworkAdapterNames []string
+	HardDiskPaths       []string
+}
+
+// Generation is the generation of the HyperV virtual machine that boots with the firmware of the source
+func (m *VmwareMigration) Generation() int {
+	if strings.EqualFold(m.Firmware, "Uefi") {
+		return 2
+	}
+
+	return 1
+}
+
+// ManifestJson returns a machine specification document for the manifest_json of hyperv_machine_instance with the
+// converted disks and the network adapters of the source. A generation 1 virtual machine can only boot from an ide
+// controller, so its first disk is attached to it and the other disks to the scsi controller.
+func (m *VmwareMigration) ManifestJson(switchName string) (string, error) {
+	generation := m.Generation()
+
+	hardDiskDrives := make([]map[string]interface{}, 0, len(m.HardDiskPaths))
+	for i, hardDiskPath := range m.HardDiskPaths {
+		controllerType := ControllerType_Scsi
+		controllerLocation := i
+		if generation == 1 {
+			if i == 0 {
+				controllerType = ControllerType_Ide
+			} else {
+				controllerLocation = i - 1
+			}
+		}
+
+		hardDiskDrives = append(hardDiskDrives, map[string]interface{}{
+			"controller_type":     controllerType.String(),
+			"controller_number":   0,
+			"controller_location": controllerLocation,
+			"path":                hardDiskPath,
+		})
+	}
+
+	networkAdapters := make([]map[string]interface{}, 0, len(m.NetworkAdapterNames))
+	for _, networkAdapterName := range m.NetworkAdapterNames {
+		networkAdapters = append(networkAdapters, map[string]interface{}{
+			"name":        networkAdapterName,
+			"switch_name": switchName,
+		})
+	}
+
+	manifestJson, err := json.Marshal(map[string]interface{}{
+		"hard_disk_drives": hardDiskDrives,
+		"network_adaptors": networkAdapters,
+	})
+	if err != nil {
+		return "", fmt.Errorf("[ERROR][hyperv] unable to create manifest of migrated vm %s: %s", m.Name, err)
+	}
+
+	return string(manifestJson), nil
+}
+
+type HypervVmwareMigrationClient interface {
+	CreateVmwareMigration(ctx context.Context, settings VmwareMigrationSettings) (result VmwareMigration, err error)
+	GetVmwareMigrationHardDiskPaths(ctx context.Context, hardDiskPaths []string) (result []string, err error)
+	DeleteVmwareMigration(ctx context.Context, hardDiskPaths []string) (err error)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestVmwareMigrationSettingsValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings VmwareMigrationSettings
+		valid    bool
+	}{
+		{"ova", VmwareMigrationSettings{SourcePath: `C:\Vmware\web.ova`}, true},
+		{"ovf", VmwareMigrationSettings{SourcePath: `C:\Vmware\web\web.OVF`}, true},
+		{"vmdk", VmwareMigrationSettings{SourcePath: `C:\Vmware.d\web.vmdk`, Converter: DiskConverter_QemuImg, ConverterPath: `C:\qemu\qemu-img.exe`}, true},
+		{"vmx", VmwareMigrationSettings{SourcePath: `C:\Vmware\web.vmx`}, false},
+		{"auto converter path", VmwareMigrationSettings{SourcePath: `C:\Vmware\web.ova`, ConverterPath: `C:\qemu\qemu-img.exe`}, false},
+	}
+
+	for _, c := range cases {
+		if err := c.settings.Validate(); (err == nil) != c.valid {
+			t.Errorf("Expected %s settings to be valid %t, got %v", c.name, c.valid, err)
+		}
+	}
+}
+
+func TestVmwareMigrationManifestJson(t *testing.T) {
+	cases := []struct {
+		name           string
+		migration      VmwareMigration
+		hardDiskDrives []interface{}
+	}{
+		{
+			"bios",
+			VmwareMigration{Name: "web", Firmware: "Bios", HardDiskPaths: []string{`C:\Vhds\web-disk1.vhdx`, `C:\Vhds\web-disk2.vhdx`}},
+			[]interface{}{
+				map[string]interface{}{"controller_type": "Ide", "controller_number": 0.0, "controller_location": 0.0, "path": `C:\Vhds\web-disk1.vhdx`},
+				map[string]interface{}{"controller_type": "Scsi", "controller_number": 0.0, "controller_location": 0.0, "path": `C:\Vhds\web-disk2.vhdx`},
+			},
+		},
+		{
+			"uefi",
+			VmwareMigration{Name: "web", Firmware: "Uefi", HardDiskPaths: []string{`C:\Vhds\web-disk1.vhdx`, `C:\Vhds\web-disk2.vhdx`}},
+			[]interface{}{
+				map[string]interface{}{"controller_type": "Scsi", "controller_number": 0.0, "controller_location": 0.0, "path": `C:\Vhds\web-disk1.vhdx`},
+				map[string]interface{}{"controller_type": "Scsi", "controller_number": 0.0, "controller_location": 1.0, "path": `C:\Vhds\web-disk2.vhdx`},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c.migration.NetworkAdapterNames = []string{"Network adapter 1"}
+
+		manifestJson, err := c.migration.ManifestJson("wan")
+		if err != nil {
+			t.Fatalf("Expected %s manifest to be created, got %s", c.name, err)
+		}
+
+		var manifest map[string]interface{}
+		if err := json.Unmarshal([]byte(manifestJson), &manifest); err != nil {
+			t.Fatalf("Expected %s manifest to be json, got %s", c.name, err)
+		}
+
+		if !reflect.DeepEqual(manifest["hard_disk_drives"], c.hardDiskDrives) {
+			t.Errorf("Expected %s hard disk drives %#v, got %#v", c.name, c.hardDiskDrives, manifest["hard_disk_drives"])
+		}
+
+		expectedNetworkAdapters := []interface{}{map[string]interface{}{"name": "Network adapter 1", "switch_name": "wan"}}
+		if !reflect.DeepEqual(manifest["network_adaptors"], expectedNetworkAdapters) {
+			t.Errorf("Expected %s network adapters %#v, got %#v", c.name, expectedNetworkAdapters, manifest["network_adaptors"])
+		}
+	}
+}
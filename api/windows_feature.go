@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type WindowsFeatureInstallState int
+
+const (
+	WindowsFeatureInstallState_Available        WindowsFeatureInstallState = 0
+	WindowsFeatureInstallState_Installed        WindowsFeatureInstallState = 1
+	WindowsFeatureInstallState_InstallPending   WindowsFeatureInstallState = 2
+	WindowsFeatureInstallState_Removed          WindowsFeatureInstallState = 3
+	WindowsFeatureInstallState_UninstallPending WindowsFeatureInstallState = 4
+)
+
+var WindowsFeatureInstallState_name = map[WindowsFeatureInstallState]string{
+	WindowsFeatureInstallState_Available:        "Available",
+	WindowsFeatureInstallState_Installed:        "Installed",
+	WindowsFeatureInstallState_InstallPending:   "InstallPending",
+	WindowsFeatureInstallState_Removed:          "Removed",
+	WindowsFeatureInstallState_UninstallPending: "UninstallPending",
+}
+
+var WindowsFeatureInstallState_value = map[string]WindowsFeatureInstallState{
+	"available":        WindowsFeatureInstallState_Available,
+	"installed":        WindowsFeatureInstallState_Installed,
+	"installpending":   WindowsFeatureInstallState_InstallPending,
+	"removed":          WindowsFeatureInstallState_Removed,
+	"uninstallpending": WindowsFeatureInstallState_UninstallPending,
+}
+
+func (x WindowsFeatureInstallState) String() string {
+	return WindowsFeatureInstallState_name[x]
+}
+
+func ToWindowsFeatureInstallState(x string) WindowsFeatureInstallState {
+	if integerValue, err := strconv.Atoi(x); err == nil {
+		return WindowsFeatureInstallState(integerValue)
+	}
+
+	return WindowsFeatureInstallState_value[strings.ToLower(x)]
+}
+
+func (d *WindowsFeatureInstallState) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString(`"`)
+	buffer.WriteString(d.String())
+	buffer.WriteString(`"`)
+	return buffer.Bytes(), nil
+}
+
+func (d *WindowsFeatureInstallState) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		var i int
+		err2 := json.Unmarshal(b, &i)
+		if err2 == nil {
+			*d = WindowsFeatureInstallState(i)
+			return nil
+		}
+
+		return err
+	}
+	*d = ToWindowsFeatureInstallState(s)
+	return nil
+}
+
+// WindowsFeature is a role or feature of Windows Server, or an optional feature of a Windows client, on the HyperV
+// host machine. The install states of optional features are mapped to the install states of Windows Server features,
+// e.g. Enabled is Installed and DisabledWithPayloadRemoved is Removed.
+type WindowsFeature struct {
+	Name          string
+	DisplayName   string
+	InstallState  WindowsFeatureInstallState
+	RestartNeeded bool
+}
+
+// Installed is true when the feature is installed or will be installed by the next restart of the HyperV host machine
+func (f *WindowsFeature) Installed() bool {
+	return f.InstallState == WindowsFeatureInstallState_Installed || f.InstallState == WindowsFeatureInstallState_InstallPending
+}
+
+type HypervWindowsFeatureClient interface {
+	InstallWindowsFeature(ctx context.Context, name string, includeAllSubFeatures bool, includeManagementTools bool, source string) (result WindowsFeature, err error)
+	GetWindowsFeature(ctx context.Context, name string) (result WindowsFeature, err error)
+	UninstallWindowsFeature(ctx context.Context, name string) (result WindowsFeature, err error)
+	RestartHost(ctx context.Context, timeout uint32, pollPeriod uint32) (err error)
+}
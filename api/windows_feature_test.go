@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDeserializeWindowsFeature(t *testing.T) {
+	var windowsFeatureJson = `
+{
+    "Name":  "Hyper-V",
+    "DisplayName":  "Hyper-V",
+    "InstallState":  "InstallPending",
+    "RestartNeeded":  true
+}
+`
+
+	var windowsFeature WindowsFeature
+	err := json.Unmarshal([]byte(windowsFeatureJson), &windowsFeature)
+	if err != nil {
+		t.Errorf("Unable to deserialize windows feature: %s", err.Error())
+	}
+
+	if windowsFeature.InstallState != WindowsFeatureInstallState_InstallPending || !windowsFeature.RestartNeeded {
+		t.Errorf("Unexpected windows feature: %+v", windowsFeature)
+	}
+
+	if !windowsFeature.Installed() {
+		t.Errorf("Expected a windows feature pending install to be installed")
+	}
+
+	windowsFeature.InstallState = WindowsFeatureInstallState_UninstallPending
+	if windowsFeature.Installed() {
+		t.Errorf("Expected a windows feature pending uninstall not to be installed")
+	}
+}
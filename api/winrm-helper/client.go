@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
+	"reflect"
+	"sync"
 	"text/template"
 
 	pool "github.com/jolestar/go-commons-pool/v2"
 	"github.com/masterzen/winrm"
+	"github.com/taliesins/terraform-provider-hyperv/api"
 	"github.com/taliesins/terraform-provider-hyperv/powershell"
 )
 
@@ -21,80 +23,163 @@ func New(clientConfig *ClientConfig) (*Provider, error) {
 }
 
 type ClientConfig struct {
-	WinRmClientPool  *pool.ObjectPool
-	ElevatedUser     string
-	ElevatedPassword string
-	Vars             string
+	WinRmClientPool                          *pool.ObjectPool
+	ElevatedUser                             string
+	ElevatedPassword                         string
+	Vars                                     string
+	ScriptModule                             bool
+	ScriptModuleSigningCertificateThumbprint string
+
+	scriptModuleMutex sync.Mutex
+	scriptModule      *stagedScriptModule
 }
 
-func (c *ClientConfig) RunFireAndForgetScript(ctx context.Context, script *template.Template, args interface{}) error {
-	var scriptRendered bytes.Buffer
-	err := script.Execute(&scriptRendered, args)
+// runScript runs a rendered script on the HyperV host machine. Only loggedScript, the script rendered without its
+// secrets, is logged. A script that was not run because the staged script module was changed is run once more after
+// the script module is staged again.
+func (c *ClientConfig) runScript(ctx context.Context, description string, script string, loggedScript string) (exitStatus int, stdout string, stderr string, err error) {
+	for attempt := 0; ; attempt++ {
+		command, err := c.prepareScript(ctx, script)
 
-	if err != nil {
-		return err
+		if err != nil {
+			return 0, "", "", err
+		}
+
+		winrmClient, err := c.WinRmClientPool.BorrowObject(ctx)
+
+		if err != nil {
+			return 0, "", "", err
+		}
+
+		log.Printf("[DEBUG] Running %s:\n%s\n", description, loggedScript)
+
+		exitStatus, stdout, stderr, err = powershell.RunPowershell(winrmClient.(*winrm.Client), c.ElevatedUser, c.ElevatedPassword, c.Vars, command)
+
+		err2 := c.WinRmClientPool.ReturnObject(ctx, winrmClient)
+
+		if err != nil {
+			return 0, "", "", err
+		}
+
+		if err2 != nil {
+			return 0, "", "", err2
+		}
+
+		if attempt > 0 || !c.scriptModuleChanged(stdout) {
+			return exitStatus, stdout, stderr, nil
+		}
 	}
+}
 
-	command := scriptRendered.String()
+// sensitiveValue replaces the secrets of a script in the rendering that is logged
+const sensitiveValue = "(sensitive value)"
 
-	winrmClient, err := c.WinRmClientPool.BorrowObject(ctx)
+// renderScript renders a script together with the rendering that is logged, in which the string fields of args tagged
+// with sensitive:"true" are replaced by sensitiveValue. Secrets like passwords, pfx files and answer files have to be
+// passed in fields of their own, so that they do not end up in the debug log or in the error of a failed script.
+func renderScript(script *template.Template, args interface{}) (scriptRendered string, loggedScriptRendered string, err error) {
+	var scriptBuffer bytes.Buffer
+	err = script.Execute(&scriptBuffer, args)
 
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
-	log.Printf("[DEBUG] Running fire and forget script:\n%s\n", command)
-
-	_, _, _, err = powershell.RunPowershell(winrmClient.(*winrm.Client), c.ElevatedUser, c.ElevatedPassword, c.Vars, command)
+	loggedArgs, sensitive := redactScriptArgs(args)
+	if !sensitive {
+		return scriptBuffer.String(), scriptBuffer.String(), nil
+	}
 
-	err2 := c.WinRmClientPool.ReturnObject(ctx, winrmClient)
+	var loggedScriptBuffer bytes.Buffer
+	err = script.Execute(&loggedScriptBuffer, loggedArgs)
 
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
-	if err2 != nil {
-		return err2
+	return scriptBuffer.String(), loggedScriptBuffer.String(), nil
+}
+
+// redactScriptArgs returns a copy of args with the string fields tagged with sensitive:"true" replaced by
+// sensitiveValue, and whether any field was replaced
+func redactScriptArgs(args interface{}) (interface{}, bool) {
+	value := reflect.ValueOf(args)
+	if value.Kind() != reflect.Struct {
+		return args, false
 	}
 
-	return nil
+	redactedArgs := reflect.New(value.Type()).Elem()
+	redactedArgs.Set(value)
+
+	sensitive := false
+	for i := 0; i < value.NumField(); i++ {
+		field := redactedArgs.Field(i)
+		if value.Type().Field(i).Tag.Get("sensitive") != "true" || field.Kind() != reflect.String || !field.CanSet() || field.String() == "" {
+			continue
+		}
+
+		field.SetString(sensitiveValue)
+		sensitive = true
+	}
+
+	return redactedArgs.Interface(), sensitive
 }
 
-func (c *ClientConfig) RunScriptWithResult(ctx context.Context, script *template.Template, args interface{}, result interface{}) (err error) {
-	var scriptRendered bytes.Buffer
-	err = script.Execute(&scriptRendered, args)
+func (c *ClientConfig) RunFireAndForgetScript(ctx context.Context, script *template.Template, args interface{}) error {
+	scriptRendered, loggedScriptRendered, err := renderScript(script, args)
 
 	if err != nil {
 		return err
 	}
 
-	command := scriptRendered.String()
-
-	winrmClient, err := c.WinRmClientPool.BorrowObject(ctx)
+	exitStatus, stdout, stderr, err := c.runScript(ctx, "fire and forget script", scriptRendered, loggedScriptRendered)
 
 	if err != nil {
 		return err
 	}
 
-	log.Printf("[DEBUG] Running script with result:\n%s\n", command)
+	_, err = unwrapScriptEnvelope(stdout)
+	if err != nil {
+		return scriptError(script.Name(), exitStatus, stdout, stderr, err, loggedScriptRendered)
+	}
+
+	return nil
+}
+
+// scriptError keeps the error record of a failed script matchable with errors.Is while adding the name of the script
+// and the details of the run. The script itself is only logged, as it can be large and would bury the error record.
+// The script is the rendering without secrets, as the error is shown to the user.
+func scriptError(operation string, exitStatus int, stdout string, stderr string, err error, script string) error {
+	if scriptErr, ok := err.(*api.ScriptError); ok {
+		scriptErr.Operation = operation
+		log.Printf("[DEBUG] Script %s failed:\n%s\ncommand:%s", operation, scriptErr, script)
+		return fmt.Errorf("%w\nexitStatus:%d\nstdErr:%s", scriptErr, exitStatus, stderr)
+	}
 
-	exitStatus, stdout, stderr, err := powershell.RunPowershell(winrmClient.(*winrm.Client), c.ElevatedUser, c.ElevatedPassword, c.Vars, command)
+	return fmt.Errorf("%s: exitStatus:%d\nstdOut:%s\nstdErr:%s\nerr:%s\ncommand:%s", operation, exitStatus, stdout, stderr, err, script)
+}
 
-	err2 := c.WinRmClientPool.ReturnObject(ctx, winrmClient)
+func (c *ClientConfig) RunScriptWithResult(ctx context.Context, script *template.Template, args interface{}, result interface{}) (err error) {
+	scriptRendered, loggedScriptRendered, err := renderScript(script, args)
 
 	if err != nil {
 		return err
 	}
 
-	if err2 != nil {
-		return err2
+	exitStatus, stdout, stderr, err := c.runScript(ctx, "script with result", scriptRendered, loggedScriptRendered)
+
+	if err != nil {
+		return err
 	}
 
-	stdout = strings.TrimSpace(stdout)
+	scriptResult, err := unwrapScriptEnvelope(stdout)
+	if err != nil {
+		return scriptError(script.Name(), exitStatus, stdout, stderr, err, loggedScriptRendered)
+	}
 
-	err = json.Unmarshal([]byte(stdout), &result)
+	err = json.Unmarshal([]byte(scriptResult), &result)
 	if err != nil {
-		return fmt.Errorf("exitStatus:%d\nstdOut:%s\nstdErr:%s\nerr:%s\ncommand:%s", exitStatus, stdout, stderr, err, command)
+		return fmt.Errorf("exitStatus:%d\nstdOut:%s\nstdErr:%s\nerr:%s\ncommand:%s", exitStatus, scriptResult, stderr, err, loggedScriptRendered)
 	}
 
 	return nil
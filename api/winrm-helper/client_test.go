@@ -0,0 +1,58 @@
+package winrm_helper
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+type renderScriptArgs struct {
+	Hostname       string
+	Password       string `sensitive:"true"`
+	UnattendBase64 string `sensitive:"true"`
+}
+
+var renderScriptTemplate = template.Must(template.New("RenderScript").Parse(`
+$hostname = '{{.Hostname}}'
+$password = '{{.Password}}'
+$unattendBase64 = '{{.UnattendBase64}}'
+`))
+
+func TestRenderScript(t *testing.T) {
+	script, loggedScript, err := renderScript(renderScriptTemplate, renderScriptArgs{
+		Hostname: "hyperv01",
+		Password: "P@ssw0rd",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(script, "$password = 'P@ssw0rd'") {
+		t.Fatalf("expected script to contain the password, got %s", script)
+	}
+
+	if strings.Contains(loggedScript, "P@ssw0rd") {
+		t.Fatalf("expected logged script not to contain the password, got %s", loggedScript)
+	}
+
+	if !strings.Contains(loggedScript, "$hostname = 'hyperv01'") || !strings.Contains(loggedScript, "$password = '"+sensitiveValue+"'") {
+		t.Fatalf("expected logged script to only replace the password, got %s", loggedScript)
+	}
+
+	if !strings.Contains(loggedScript, "$unattendBase64 = ''") {
+		t.Fatalf("expected logged script to keep empty secrets empty, got %s", loggedScript)
+	}
+}
+
+func TestRenderScriptWithoutSecrets(t *testing.T) {
+	script, loggedScript, err := renderScript(renderScriptTemplate, renderScriptArgs{
+		Hostname: "hyperv01",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if script != loggedScript {
+		t.Fatalf("expected logged script to be the script, got %s", loggedScript)
+	}
+}
@@ -0,0 +1,191 @@
+package winrm_helper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	scriptResultBeginMarker = "#TERRAFORM-PROVIDER-HYPERV-RESULT-BEGIN#"
+	scriptResultEndMarker   = "#TERRAFORM-PROVIDER-HYPERV-RESULT-END#"
+
+	// Kept below the width of a console so that lines are never wrapped, regardless of how the output is captured
+	scriptResultChunkSize = 76
+)
+
+// MaxScriptResultSize is the maximum size in bytes of the decompressed result of a script
+var MaxScriptResultSize int64 = 256 * 1024 * 1024
+
+type scriptResultFunctionsArgs struct {
+	BeginMarker string
+	EndMarker   string
+	ChunkSize   int
+}
+
+// scriptResultFunctionsTemplate defines the function that writes the output of a script gzip compressed and base64
+// encoded in short lines between markers, so that large results are neither truncated nor wrapped and can be
+// reassembled reliably, and the function that runs a script and returns its output or error record in an envelope.
+var scriptResultFunctionsTemplate = template.Must(template.New("ScriptResultFunctions").Parse(`
+function Write-TerraformProviderHypervResult {
+	param(
+		[Parameter(ValueFromPipeline=$true)][string]$Result
+	)
+
+	$resultBytes = [System.Text.Encoding]::UTF8.GetBytes($Result)
+	$resultStream = New-Object System.IO.MemoryStream
+	$gzipStream = New-Object System.IO.Compression.GZipStream($resultStream, [System.IO.Compression.CompressionMode]::Compress)
+	$gzipStream.Write($resultBytes, 0, $resultBytes.Length)
+	$gzipStream.Close()
+	$resultBase64 = [System.Convert]::ToBase64String($resultStream.ToArray())
+
+	Write-Output '{{.BeginMarker}}'
+	for ($i = 0; $i -lt $resultBase64.Length; $i += {{.ChunkSize}}) {
+		Write-Output $resultBase64.Substring($i, [Math]::Min({{.ChunkSize}}, $resultBase64.Length - $i))
+	}
+	Write-Output '{{.EndMarker}}'
+}
+
+function Invoke-TerraformProviderHypervScriptBlock {
+	param(
+		[Parameter(Mandatory=$true)][ScriptBlock]$ScriptBlock
+	)
+
+	$envelope = @{}
+	try {
+		$envelope.Result = & $ScriptBlock | Out-String
+	} catch {
+		$envelope.Error = @{
+			Category="$($_.CategoryInfo.Category)";
+			Message=$_.Exception.Message;
+			HResult=$_.Exception.HResult;
+			ExceptionType=$_.Exception.GetType().FullName;
+			ScriptLineNumber=$_.InvocationInfo.ScriptLineNumber;
+			ScriptLine="$($_.InvocationInfo.Line)".Trim();
+			ScriptStackTrace=$_.ScriptStackTrace;
+		}
+	}
+
+	ConvertTo-Json -InputObject $envelope -Compress | Write-TerraformProviderHypervResult
+}
+`))
+
+// scriptEnvelope is what every script returns, either the output of the script or the error record that made it fail
+type scriptEnvelope struct {
+	Result string
+	Error  *api.ScriptError
+}
+
+type scriptWithResultArgs struct {
+	ScriptResultFunctions string
+	Script                string
+}
+
+var scriptWithResultTemplate = template.Must(template.New("ScriptWithResult").Parse(`
+{{.ScriptResultFunctions}}
+
+Invoke-TerraformProviderHypervScriptBlock -ScriptBlock {
+{{.Script}}
+}
+`))
+
+func renderScriptResultFunctions() (string, error) {
+	var scriptResultFunctionsRendered bytes.Buffer
+	err := scriptResultFunctionsTemplate.Execute(&scriptResultFunctionsRendered, scriptResultFunctionsArgs{
+		BeginMarker: scriptResultBeginMarker,
+		EndMarker:   scriptResultEndMarker,
+		ChunkSize:   scriptResultChunkSize,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return scriptResultFunctionsRendered.String(), nil
+}
+
+func wrapScriptWithResult(script string) (string, error) {
+	scriptResultFunctions, err := renderScriptResultFunctions()
+	if err != nil {
+		return "", err
+	}
+
+	var scriptRendered bytes.Buffer
+	err = scriptWithResultTemplate.Execute(&scriptRendered, scriptWithResultArgs{
+		ScriptResultFunctions: scriptResultFunctions,
+		Script:                script,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return scriptRendered.String(), nil
+}
+
+// unwrapScriptResult reassembles the output written by scriptWithResultTemplate. Output without markers is returned
+// as is.
+func unwrapScriptResult(stdout string) (string, error) {
+	beginIndex := strings.Index(stdout, scriptResultBeginMarker)
+	if beginIndex < 0 {
+		return strings.TrimSpace(stdout), nil
+	}
+
+	encodedResult := stdout[beginIndex+len(scriptResultBeginMarker):]
+	endIndex := strings.Index(encodedResult, scriptResultEndMarker)
+	if endIndex < 0 {
+		return "", fmt.Errorf("result is incomplete as it does not end with %s", scriptResultEndMarker)
+	}
+
+	encodedResult = strings.Join(strings.Fields(encodedResult[:endIndex]), "")
+
+	compressedResult, err := base64.StdEncoding.DecodeString(encodedResult)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode result: %s", err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compressedResult))
+	if err != nil {
+		return "", fmt.Errorf("unable to decompress result: %s", err)
+	}
+	defer gzipReader.Close()
+
+	result, err := io.ReadAll(io.LimitReader(gzipReader, MaxScriptResultSize+1))
+	if err != nil {
+		return "", fmt.Errorf("unable to decompress result: %s", err)
+	}
+
+	if int64(len(result)) > MaxScriptResultSize {
+		return "", fmt.Errorf("result is larger than the maximum of %d bytes", MaxScriptResultSize)
+	}
+
+	return strings.TrimSpace(string(result)), nil
+}
+
+// unwrapScriptEnvelope reassembles the envelope written by scriptWithResultTemplate and returns the output of the
+// script, or its error record as an *api.ScriptError.
+func unwrapScriptEnvelope(stdout string) (string, error) {
+	result, err := unwrapScriptResult(stdout)
+	if err != nil {
+		return "", err
+	}
+
+	var envelope scriptEnvelope
+	err = json.Unmarshal([]byte(result), &envelope)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse result envelope: %s", err)
+	}
+
+	if envelope.Error != nil {
+		return "", envelope.Error
+	}
+
+	return strings.TrimSpace(envelope.Result), nil
+}
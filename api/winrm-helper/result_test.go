@@ -0,0 +1,120 @@
+package winrm_helper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func encodeScriptResult(t *testing.T, result string) string {
+	var compressedResult bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressedResult)
+	if _, err := gzipWriter.Write([]byte(result)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedResult := base64.StdEncoding.EncodeToString(compressedResult.Bytes())
+
+	lines := []string{"WARNING: some noise", scriptResultBeginMarker}
+	for i := 0; i < len(encodedResult); i += scriptResultChunkSize {
+		end := i + scriptResultChunkSize
+		if end > len(encodedResult) {
+			end = len(encodedResult)
+		}
+		lines = append(lines, encodedResult[i:end])
+	}
+	lines = append(lines, scriptResultEndMarker)
+
+	return strings.Join(lines, "\r\n")
+}
+
+func TestUnwrapScriptResult(t *testing.T) {
+	expected := `[{"Name":"` + strings.Repeat("web_server_g2", 1000) + `"}]`
+
+	result, err := unwrapScriptResult(encodeScriptResult(t, expected+"\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != expected {
+		t.Errorf("Expected %q but got %q", expected, result)
+	}
+}
+
+func TestUnwrapScriptResultWithoutMarkers(t *testing.T) {
+	result, err := unwrapScriptResult("  {}\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != "{}" {
+		t.Errorf("Expected %q but got %q", "{}", result)
+	}
+}
+
+func TestUnwrapScriptResultIncomplete(t *testing.T) {
+	encodedResult := encodeScriptResult(t, "{}")
+	encodedResult = encodedResult[:strings.Index(encodedResult, scriptResultEndMarker)]
+
+	_, err := unwrapScriptResult(encodedResult)
+	if err == nil {
+		t.Errorf("Expected an error for an incomplete result")
+	}
+}
+
+func TestUnwrapScriptResultTooLarge(t *testing.T) {
+	maxScriptResultSize := MaxScriptResultSize
+	MaxScriptResultSize = 10
+	defer func() { MaxScriptResultSize = maxScriptResultSize }()
+
+	_, err := unwrapScriptResult(encodeScriptResult(t, `{"Name":"web_server_g2"}`))
+	if err == nil {
+		t.Errorf("Expected an error for a result that is too large")
+	}
+}
+
+func TestWrapScriptWithResult(t *testing.T) {
+	script, err := wrapScriptWithResult(`"{}"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(script, "Invoke-TerraformProviderHypervScriptBlock -ScriptBlock {\n\"{}\"\n}") {
+		t.Errorf("Expected script to be wrapped: %s", script)
+	}
+}
+
+func TestUnwrapScriptEnvelope(t *testing.T) {
+	result, err := unwrapScriptEnvelope(encodeScriptResult(t, `{"Result":"{\"Name\":\"web_server_g2\"}\r\n"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result != `{"Name":"web_server_g2"}` {
+		t.Errorf("Expected %q but got %q", `{"Name":"web_server_g2"}`, result)
+	}
+}
+
+func TestUnwrapScriptEnvelopeWithError(t *testing.T) {
+	_, err := unwrapScriptEnvelope(encodeScriptResult(t, `{"Error":{"Category":"ObjectNotFound","Message":"Unable to find a virtual machine with name 'web_server_g2'.","HResult":-2146233087,"ExceptionType":"Microsoft.HyperV.PowerShell.VirtualizationException"}}`))
+	if !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("Expected a not found error but got %v", err)
+	}
+
+	err = scriptError("GetVm", 0, "", "", err, "Get-VM")
+	if !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("Expected the not found error to be kept but got %v", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), "GetVm: Unable to find a virtual machine") {
+		t.Errorf("Expected the error to start with the operation and message but got %v", err)
+	}
+}
@@ -0,0 +1,318 @@
+package winrm_helper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/masterzen/winrm"
+	"github.com/taliesins/terraform-provider-hyperv/powershell"
+)
+
+type scriptModuleArgs struct {
+	ScriptResultFunctions string
+}
+
+// scriptModuleTemplate is the helper module that is staged on the HyperV host machine once, so that every operation
+// only needs to send a short function call with its gzip compressed script.
+var scriptModuleTemplate = template.Must(template.New("ScriptModule").Parse(`
+{{.ScriptResultFunctions}}
+
+function Invoke-TerraformProviderHypervScript {
+	param(
+		[Parameter(Mandatory=$true)][string]$EncodedScript
+	)
+
+	$compressedScript = [System.Convert]::FromBase64String($EncodedScript)
+	$compressedStream = New-Object System.IO.MemoryStream(,$compressedScript)
+	$gzipStream = New-Object System.IO.Compression.GZipStream($compressedStream, [System.IO.Compression.CompressionMode]::Decompress)
+	$reader = New-Object System.IO.StreamReader($gzipStream, [System.Text.Encoding]::UTF8)
+	$script = [ScriptBlock]::Create($reader.ReadToEnd())
+	$reader.Close()
+
+	Invoke-TerraformProviderHypervScriptBlock -ScriptBlock $script
+}
+
+Export-ModuleMember -Function Invoke-TerraformProviderHypervScript, Invoke-TerraformProviderHypervScriptBlock, Write-TerraformProviderHypervResult
+`))
+
+type stageScriptModuleArgs struct {
+	FileName                     string
+	ContentBase64                string
+	ContentHash                  string
+	SigningCertificateThumbprint string
+}
+
+// stageScriptModuleTemplate stages the script module in a directory that only Administrators and SYSTEM can write to,
+// as every script imports it with the credentials of the provider. A module that is already staged is only kept when
+// it is owned by Administrators or SYSTEM and its content is the expected content, otherwise it is staged again.
+var stageScriptModuleTemplate = template.Must(template.New("StageScriptModule").Parse(`
+$ErrorActionPreference = 'Stop'
+$scriptModuleDirectory = Join-Path $env:ProgramData 'terraform-provider-hyperv\script-modules'
+$scriptModulePath = Join-Path $scriptModuleDirectory '{{.FileName}}'
+$content = [System.Text.Encoding]::UTF8.GetString([System.Convert]::FromBase64String('{{.ContentBase64}}'))
+$signingCertificateThumbprint = '{{.SigningCertificateThumbprint}}'
+
+$administratorsSid = New-Object System.Security.Principal.SecurityIdentifier('S-1-5-32-544')
+$systemSid = New-Object System.Security.Principal.SecurityIdentifier('S-1-5-18')
+$trustedOwners = @($administratorsSid.Value, $systemSid.Value)
+
+if (Test-Path -LiteralPath $scriptModuleDirectory) {
+	if ((Get-Item -LiteralPath $scriptModuleDirectory -Force).Attributes -band [System.IO.FileAttributes]::ReparsePoint) {
+		throw "Script module directory must not be a link - $scriptModuleDirectory"
+	}
+} else {
+	New-Item -ItemType Directory -Force -Path $scriptModuleDirectory | Out-Null
+}
+
+#the directory is created under ProgramData where every user can create files, so inheritance is turned off
+$directoryAcl = New-Object System.Security.AccessControl.DirectorySecurity
+$directoryAcl.SetOwner($administratorsSid)
+$directoryAcl.SetAccessRuleProtection($true, $false)
+@($administratorsSid, $systemSid) | %{
+	$directoryAcl.AddAccessRule((New-Object System.Security.AccessControl.FileSystemAccessRule($_, 'FullControl', 'ContainerInherit, ObjectInherit', 'None', 'Allow')))
+}
+Set-Acl -LiteralPath $scriptModuleDirectory -AclObject $directoryAcl
+
+function Test-ScriptModule {
+	if (!(Test-Path -LiteralPath $scriptModulePath -PathType Leaf)) {
+		return $false
+	}
+
+	if ($trustedOwners -notcontains (Get-Acl -LiteralPath $scriptModulePath).GetOwner([System.Security.Principal.SecurityIdentifier]).Value) {
+		return $false
+	}
+
+	if ($signingCertificateThumbprint) {
+		$signature = Get-AuthenticodeSignature -FilePath $scriptModulePath
+		return ($signature.Status -eq 'Valid') -and ($signature.SignerCertificate.Thumbprint -eq $signingCertificateThumbprint) -and [System.IO.File]::ReadAllText($scriptModulePath).StartsWith($content)
+	}
+
+	return (Get-FileHash -LiteralPath $scriptModulePath -Algorithm SHA256).Hash -eq '{{.ContentHash}}'
+}
+
+if (!(Test-ScriptModule)) {
+	$stagingPath = "$scriptModulePath.$([System.Guid]::NewGuid()).tmp"
+	[System.IO.File]::WriteAllText($stagingPath, $content, (New-Object System.Text.UTF8Encoding($true)))
+
+	if ($signingCertificateThumbprint) {
+		$signingCertificate = Get-ChildItem -Path Cert:\LocalMachine\My, Cert:\CurrentUser\My -CodeSigningCert | ?{ $_.Thumbprint -eq $signingCertificateThumbprint } | Select -First 1
+		if (!$signingCertificate) {
+			Remove-Item -Path $stagingPath -Force
+			throw "Code signing certificate does not exist - $signingCertificateThumbprint"
+		}
+
+		$signature = Set-AuthenticodeSignature -FilePath $stagingPath -Certificate $signingCertificate
+		if ($signature.Status -ne 'Valid') {
+			Remove-Item -Path $stagingPath -Force
+			throw "Unable to sign script module - $($signature.StatusMessage)"
+		}
+	}
+
+	$fileAcl = Get-Acl -LiteralPath $stagingPath
+	$fileAcl.SetOwner($administratorsSid)
+	Set-Acl -LiteralPath $stagingPath -AclObject $fileAcl
+
+	Move-Item -Path $stagingPath -Destination $scriptModulePath -Force
+}
+
+$scriptModule = @{
+	Path=$scriptModulePath;
+	Hash=(Get-FileHash -LiteralPath $scriptModulePath -Algorithm SHA256).Hash;
+}
+
+ConvertTo-Json -InputObject $scriptModule
+`))
+
+// scriptModuleChangedMarker is written instead of running the script when the staged script module is not the module
+// that was staged, so that it is staged again before the script is run
+const scriptModuleChangedMarker = "#TERRAFORM-PROVIDER-HYPERV-SCRIPT-MODULE-CHANGED#"
+
+// stagedScriptModule is the script module on the HyperV host machine and the hash of its content when it was staged
+type stagedScriptModule struct {
+	Path string
+	Hash string
+}
+
+type invokeScriptModuleArgs struct {
+	ScriptModulePath          string
+	ScriptModuleHash          string
+	ScriptModuleChangedMarker string
+	EncodedScript             string
+}
+
+// invokeScriptModuleTemplate checks the hash of the script module before every import, so a module that was replaced
+// after it was staged is never imported
+var invokeScriptModuleTemplate = template.Must(template.New("InvokeScriptModule").Parse(`$scriptModulePath = '{{.ScriptModulePath}}'
+if ((Get-FileHash -LiteralPath $scriptModulePath -Algorithm SHA256 -ErrorAction SilentlyContinue).Hash -ne '{{.ScriptModuleHash}}') {
+	'{{.ScriptModuleChangedMarker}}'
+	return
+}
+Import-Module $scriptModulePath -DisableNameChecking
+Invoke-TerraformProviderHypervScript -EncodedScript '{{.EncodedScript}}'
+`))
+
+func renderScriptModule() (string, error) {
+	scriptResultFunctions, err := renderScriptResultFunctions()
+	if err != nil {
+		return "", err
+	}
+
+	var scriptModuleRendered bytes.Buffer
+	err = scriptModuleTemplate.Execute(&scriptModuleRendered, scriptModuleArgs{
+		ScriptResultFunctions: scriptResultFunctions,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return scriptModuleRendered.String(), nil
+}
+
+// scriptModuleFileName is versioned by the content of the module and the certificate used to sign it, so that a
+// changed module is staged next to the existing one instead of replacing it while it is in use
+func scriptModuleFileName(scriptModule string, signingCertificateThumbprint string) string {
+	hash := sha256.Sum256([]byte(scriptModule + signingCertificateThumbprint))
+	return fmt.Sprintf("TerraformProviderHyperv-%x.psm1", hash[:8])
+}
+
+// scriptModuleContentHash is the hash of the unsigned script module as it is written on the HyperV host machine, i.e.
+// UTF8 with a byte order mark
+func scriptModuleContentHash(scriptModule string) string {
+	hash := sha256.Sum256([]byte("\ufeff" + scriptModule))
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+func encodeScript(script string) (string, error) {
+	var compressedScript bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressedScript)
+
+	_, err := gzipWriter.Write([]byte(script))
+	if err != nil {
+		return "", err
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(compressedScript.Bytes()), nil
+}
+
+func invokeScriptModule(scriptModule stagedScriptModule, script string) (string, error) {
+	encodedScript, err := encodeScript(script)
+	if err != nil {
+		return "", err
+	}
+
+	var commandRendered bytes.Buffer
+	err = invokeScriptModuleTemplate.Execute(&commandRendered, invokeScriptModuleArgs{
+		ScriptModulePath:          strings.Replace(scriptModule.Path, `'`, `''`, -1),
+		ScriptModuleHash:          scriptModule.Hash,
+		ScriptModuleChangedMarker: scriptModuleChangedMarker,
+		EncodedScript:             encodedScript,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return commandRendered.String(), nil
+}
+
+func (c *ClientConfig) stageScriptModule(ctx context.Context) (scriptModule *stagedScriptModule, err error) {
+	scriptModuleContent, err := renderScriptModule()
+	if err != nil {
+		return nil, err
+	}
+
+	var stageScriptModuleRendered bytes.Buffer
+	err = stageScriptModuleTemplate.Execute(&stageScriptModuleRendered, stageScriptModuleArgs{
+		FileName:                     scriptModuleFileName(scriptModuleContent, c.ScriptModuleSigningCertificateThumbprint),
+		ContentBase64:                base64.StdEncoding.EncodeToString([]byte(scriptModuleContent)),
+		ContentHash:                  scriptModuleContentHash(scriptModuleContent),
+		SigningCertificateThumbprint: strings.Replace(c.ScriptModuleSigningCertificateThumbprint, `'`, `''`, -1),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	command := stageScriptModuleRendered.String()
+
+	winrmClient, err := c.WinRmClientPool.BorrowObject(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] Staging script module:\n%s\n", command)
+
+	exitStatus, stdout, stderr, err := powershell.RunPowershell(winrmClient.(*winrm.Client), c.ElevatedUser, c.ElevatedPassword, c.Vars, command)
+
+	err2 := c.WinRmClientPool.ReturnObject(ctx, winrmClient)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err2 != nil {
+		return nil, err2
+	}
+
+	err = json.Unmarshal([]byte(strings.TrimSpace(stdout)), &scriptModule)
+	if err != nil || scriptModule == nil || scriptModule.Path == "" || scriptModule.Hash == "" {
+		return nil, fmt.Errorf("unable to stage script module\nexitStatus:%d\nstdOut:%s\nstdErr:%s\nerr:%v", exitStatus, stdout, stderr, err)
+	}
+
+	log.Printf("[INFO] Staged script module at %s with hash %s", scriptModule.Path, scriptModule.Hash)
+
+	return scriptModule, nil
+}
+
+// prepareScript turns a rendered script into the command to run on the HyperV host machine. When the script module
+// is used, the module is staged the first time a script is run and the command only invokes it.
+func (c *ClientConfig) prepareScript(ctx context.Context, script string) (string, error) {
+	if !c.ScriptModule {
+		return wrapScriptWithResult(script)
+	}
+
+	c.scriptModuleMutex.Lock()
+	if c.scriptModule == nil {
+		scriptModule, err := c.stageScriptModule(ctx)
+		if err != nil {
+			c.scriptModuleMutex.Unlock()
+			return "", fmt.Errorf("unable to stage script module: %s", err)
+		}
+		c.scriptModule = scriptModule
+	}
+	scriptModule := *c.scriptModule
+	c.scriptModuleMutex.Unlock()
+
+	return invokeScriptModule(scriptModule, script)
+}
+
+// scriptModuleChanged reports whether a script was not run because the staged script module was changed after it was
+// staged. The script module is then staged again the next time a script is prepared.
+func (c *ClientConfig) scriptModuleChanged(stdout string) bool {
+	if !c.ScriptModule || strings.TrimSpace(stdout) != scriptModuleChangedMarker {
+		return false
+	}
+
+	log.Printf("[WARN] Script module was changed after it was staged, staging it again")
+
+	c.scriptModuleMutex.Lock()
+	c.scriptModule = nil
+	c.scriptModuleMutex.Unlock()
+
+	return true
+}
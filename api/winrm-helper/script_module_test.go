@@ -0,0 +1,111 @@
+package winrm_helper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestInvokeScriptModule(t *testing.T) {
+	script := "$ErrorActionPreference = 'Stop'\n" + strings.Repeat("Get-VM -Name 'web_server_g2'\n", 100)
+
+	command, err := invokeScriptModule(stagedScriptModule{
+		Path: `C:\ProgramData\terraform-provider-hyperv\script-modules\it's.psm1`,
+		Hash: "0123456789ABCDEF",
+	}, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(command, `$scriptModulePath = 'C:\ProgramData\terraform-provider-hyperv\script-modules\it''s.psm1'`) {
+		t.Fatalf("expected command to import the script module, got %s", command)
+	}
+
+	if !strings.Contains(command, `-ne '0123456789ABCDEF'`) || strings.Index(command, "Get-FileHash") > strings.Index(command, "Import-Module") {
+		t.Fatalf("expected command to check the hash of the script module before importing it, got %s", command)
+	}
+
+	if len(command) >= len(script) {
+		t.Fatalf("expected command to be smaller than the script, got %d >= %d", len(command), len(script))
+	}
+
+	encodedScript := strings.Split(command, "-EncodedScript '")[1]
+	encodedScript = encodedScript[:strings.Index(encodedScript, "'")]
+
+	compressedScript, err := base64.StdEncoding.DecodeString(encodedScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compressedScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decodedScript, err := io.ReadAll(gzipReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(decodedScript) != script {
+		t.Fatalf("expected %q, got %q", script, string(decodedScript))
+	}
+}
+
+func TestScriptModuleFileName(t *testing.T) {
+	scriptModule, err := renderScriptModule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(scriptModule, "function Write-TerraformProviderHypervResult") {
+		t.Fatalf("expected script module to contain the result functions, got %s", scriptModule)
+	}
+
+	unsigned := scriptModuleFileName(scriptModule, "")
+	signed := scriptModuleFileName(scriptModule, "0123456789ABCDEF")
+
+	if unsigned == signed {
+		t.Fatalf("expected signed and unsigned script modules to be staged separately, got %s", unsigned)
+	}
+
+	if unsigned != scriptModuleFileName(scriptModule, "") {
+		t.Fatalf("expected script module file name to be stable")
+	}
+}
+
+func TestScriptModuleChanged(t *testing.T) {
+	c := &ClientConfig{
+		ScriptModule: true,
+		scriptModule: &stagedScriptModule{Path: `C:\ProgramData\terraform-provider-hyperv\script-modules\module.psm1`, Hash: "0123456789ABCDEF"},
+	}
+
+	if c.scriptModuleChanged("#TERRAFORM-PROVIDER-HYPERV-RESULT-BEGIN#\n") {
+		t.Fatalf("expected output of a script not to be treated as a changed script module")
+	}
+
+	if c.scriptModule == nil {
+		t.Fatalf("expected script module to be kept")
+	}
+
+	if !c.scriptModuleChanged(scriptModuleChangedMarker + "\r\n") {
+		t.Fatalf("expected marker to be treated as a changed script module")
+	}
+
+	if c.scriptModule != nil {
+		t.Fatalf("expected script module to be staged again")
+	}
+}
+
+func TestScriptModuleContentHash(t *testing.T) {
+	// sha256 of the UTF8 byte order mark followed by the module, in upper case as reported by Get-FileHash
+	expected := "7709C2877AAFEA9FF19AAC8B48E8EF46BCC39EBC5A661BDF203842DA9472EDA7"
+	actual := scriptModuleContentHash("module")
+
+	if actual != expected {
+		t.Fatalf("expected %s, got %s", expected, actual)
+	}
+}
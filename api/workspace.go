@@ -0,0 +1,13 @@
+package api
+
+import (
+	"context"
+)
+
+// HypervWorkspaceClient manages the workspace directory on the HyperV host machine that scripts use for their
+// temporary files. Every provider run gets its own workspace and every script gets its own directory inside of it,
+// so scripts running in parallel do not overwrite each other's files.
+type HypervWorkspaceClient interface {
+	CreateWorkspace(ctx context.Context) (err error)
+	DeleteWorkspace(ctx context.Context) (err error)
+}
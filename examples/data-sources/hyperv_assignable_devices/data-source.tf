@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_assignable_devices" "gpus" {
+  class = "Display"
+  state = "Dismounted"
+}
+
+output "gpu_location_paths" {
+  value = data.hyperv_assignable_devices.gpus.location_paths
+}
@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_default_switch" "default" {
+}
+
+output "hyperv_default_switch" {
+  value = data.hyperv_default_switch.default.name
+}
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_gpu_partitionable_devices" "nvidia" {
+  friendly_name_regex          = "NVIDIA"
+  minimum_available_vram_bytes = 4294967296
+}
+
+output "gpu_instance_paths" {
+  value = data.hyperv_gpu_partitionable_devices.nvidia.names
+}
@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_host" "current" {
+}
+
+resource "hyperv_machine_instance" "web_server" {
+  name                 = "web_server_g2"
+  generation           = 2
+  processor_count      = min(4, data.hyperv_host.current.logical_processor_count)
+  memory_startup_bytes = 2147483648
+}
+
+output "hyperv_host_memory_available_bytes" {
+  value = data.hyperv_host.current.memory_available_bytes
+}
@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_host_numa_topology" "host" {
+}
+
+resource "hyperv_machine_instance" "database" {
+  name            = "database"
+  generation      = 2
+  processor_count = data.hyperv_host_numa_topology.host.logical_processors_per_node
+
+  # keep the memory of the guest on the NUMA node its processors run on
+  memory_maximum_amount_per_numa_node_bytes = data.hyperv_host_numa_topology.host.memory_per_node_bytes
+  memory_preferred_numa_nodes               = [data.hyperv_host_numa_topology.host.nodes[0].node_id]
+
+  vm_processor {
+    maximum_count_per_numa_node = data.hyperv_host_numa_topology.host.logical_processors_per_node
+  }
+}
@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_integration_services" "web_server" {
+  vm_name = "web_server_g2"
+}
+
+locals {
+  guest_service_interface_enabled = contains(
+    [for s in data.hyperv_integration_services.web_server.integration_services : s.name if s.enabled],
+    "Guest Service Interface"
+  )
+}
+
+output "web_server_os" {
+  value = "${data.hyperv_integration_services.web_server.os_name} ${data.hyperv_integration_services.web_server.os_version}"
+}
@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_iso_catalog" "ubuntu" {
+  directory = "\\\\server\\share\\iso"
+  recurse   = true
+  name      = "ubuntu-22.04"
+}
+
+resource "hyperv_vm_dvd_drive" "web_server" {
+  vm_name             = "web_server_g2"
+  controller_number   = 0
+  controller_location = 1
+  path                = data.hyperv_iso_catalog.ubuntu.latest_path
+}
@@ -144,7 +144,7 @@ resource "hyperv_machine_instance" "default" {
     controller_number   = "0"
     controller_location = "1"
     path                = ""
-    #path                = "c:/iso/windows-server-2016.iso"
+    #path                = "C:\\Iso\\windows-server-2016.iso"
     resource_pool_name = ""
   }
 
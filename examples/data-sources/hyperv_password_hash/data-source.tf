@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+variable "ubuntu_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "random_string" "ubuntu_password_salt" {
+  length  = 16
+  special = false
+}
+
+data "hyperv_password_hash" "ubuntu" {
+  password = var.ubuntu_password
+  salt     = random_string.ubuntu_password_salt.result
+}
+
+output "ubuntu_password_hash" {
+  value = data.hyperv_password_hash.ubuntu.sha512_crypt
+}
@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_physical_disks" "passthrough" {
+  bus_type           = "SAS"
+  offline_only       = true
+  minimum_size_bytes = 107374182400 #100GB
+}
+
+resource "hyperv_vhd" "web_server_g2_vhd" {
+  path        = "c:\\web_server\\web_server_g2.vhdx" #Needs to be absolute path
+  source_disk = data.hyperv_physical_disks.passthrough.numbers[0]
+}
@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_physical_network_adapters" "uplinks" {
+  name    = "Ethernet*"
+  up_only = true
+}
+
+resource "hyperv_network_switch" "external" {
+  name                = "External"
+  switch_type         = "External"
+  allow_management_os = true
+  net_adapter_names   = [data.hyperv_physical_network_adapters.uplinks.names[0]]
+}
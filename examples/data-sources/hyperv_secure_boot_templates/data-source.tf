@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_secure_boot_templates" "linux" {
+  name = "MicrosoftUEFICertificateAuthority"
+}
+
+output "secure_boot_templates" {
+  value = data.hyperv_secure_boot_templates.linux.names
+}
+
+resource "hyperv_machine_instance" "linux" {
+  name       = "linux"
+  generation = 2
+
+  vm_firmware {
+    enable_secure_boot   = "On"
+    secure_boot_template = data.hyperv_secure_boot_templates.linux.name
+  }
+}
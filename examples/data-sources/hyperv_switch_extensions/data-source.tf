@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_switch_extensions" "capture" {
+  name_regex = "^Microsoft NDIS Capture$"
+}
+
+resource "hyperv_vswitch_extension" "capture" {
+  for_each = toset(data.hyperv_switch_extensions.capture.switch_names)
+
+  switch_name = each.value
+  name        = "Microsoft NDIS Capture"
+  enabled     = true
+}
@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vhd_parent_chain" "web_server" {
+  path = "c:\\web_server\\web_server_g2.vhdx"
+
+  lifecycle {
+    postcondition {
+      condition     = self.valid
+      error_message = "The differencing chain of web_server_g2.vhdx is broken, missing: ${join(", ", self.missing_paths)}"
+    }
+  }
+}
@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vm" "domain_controller" {
+  name = "domain_controller"
+}
+
+output "domain_controller_ip_addresses" {
+  value = flatten(data.hyperv_vm.domain_controller.network_adapters[*].ip_addresses)
+}
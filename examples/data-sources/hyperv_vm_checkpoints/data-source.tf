@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vm_checkpoints" "web_server" {
+  vm_name    = "web_server_g2"
+  name_regex = "^pre-upgrade"
+}
+
+output "web_server_latest_checkpoint" {
+  value = data.hyperv_vm_checkpoints.web_server.latest_checkpoint_name
+}
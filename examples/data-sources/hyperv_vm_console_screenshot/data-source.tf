@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vm_console_screenshot" "web_server" {
+  vm_name = "web_server_g2"
+}
+
+resource "local_file" "web_server_console" {
+  filename       = "${path.module}/web_server_console.png"
+  content_base64 = data.hyperv_vm_console_screenshot.web_server.png_base64
+}
@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vm_events" "web_server" {
+  vm_names      = ["web_server_g2"]
+  level         = "Error"
+  since_minutes = 30
+}
+
+output "web_server_healthy" {
+  value = length(data.hyperv_vm_events.web_server.events) == 0
+}
@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vm_generation_support" "this" {
+}
+
+output "maximum_vm_version" {
+  value = data.hyperv_vm_generation_support.this.maximum_vm_version
+}
+
+resource "hyperv_machine_instance" "linux" {
+  name       = "linux"
+  generation = contains(data.hyperv_vm_generation_support.this.generations, 2) ? 2 : 1
+
+  dynamic "vm_firmware" {
+    for_each = contains(data.hyperv_vm_generation_support.this.secure_boot_templates, "MicrosoftUEFICertificateAuthority") ? [1] : []
+    content {
+      enable_secure_boot   = "On"
+      secure_boot_template = "MicrosoftUEFICertificateAuthority"
+    }
+  }
+}
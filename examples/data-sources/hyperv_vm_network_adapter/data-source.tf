@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vm_network_adapter" "web_server" {
+  vm_name             = "web_server_g2"
+  name                = "wan"
+  wait_for_ip         = true
+  wait_for_ip_timeout = 600
+}
+
+output "web_server_ip_addresses" {
+  value = data.hyperv_vm_network_adapter.web_server.ip_addresses
+}
+
+output "web_server_ipv6_addresses" {
+  value = data.hyperv_vm_network_adapter.web_server.ipv6_addresses
+}
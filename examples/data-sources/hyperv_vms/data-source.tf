@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vms" "running_web_servers" {
+  name_regex  = "^web_server"
+  state       = "Running"
+  notes_regex = "role=web"
+  switch_name = "DMZ"
+}
+
+output "running_web_server_names" {
+  value = data.hyperv_vms.running_web_servers.names
+}
@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vswitch" "uplink" {
+  name = "Uplink"
+}
+
+output "hyperv_vswitch" {
+  value = data.hyperv_vswitch.uplink
+}
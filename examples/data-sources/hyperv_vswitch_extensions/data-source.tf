@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vswitch_extensions" "dmz" {
+  switch_name = "DMZ"
+}
+
+output "hyperv_vswitch_extensions" {
+  value = data.hyperv_vswitch_extensions.dmz.extensions
+}
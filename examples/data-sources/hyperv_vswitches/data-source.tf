@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+data "hyperv_vswitches" "external" {
+  switch_type = "External"
+  name_regex  = "^Uplink"
+}
+
+output "hyperv_vswitches" {
+  value = data.hyperv_vswitches.external.names
+}
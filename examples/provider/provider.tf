@@ -13,6 +13,15 @@ provider "hyperv" {
   key_path        = ""
   script_path     = "C:/Temp/terraform_%RAND%.cmd"
   timeout         = "30s"
+
+  features {
+    machine_instance {
+      graceful_shutdown           = true
+      start_after_update          = true
+      delete_vhds_on_destroy      = false
+      prevent_deletion_if_running = false
+    }
+  }
 }
 
 # Create a switch
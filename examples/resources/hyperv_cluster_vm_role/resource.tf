@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_machine_instance" "web_server" {
+  name         = "web_server_g2"
+  generation   = 2
+  cluster_name = "hyperv-cluster"
+}
+
+resource "hyperv_cluster_vm_role" "web_server" {
+  vm_name          = hyperv_machine_instance.web_server.name
+  preferred_owners = [hyperv_machine_instance.web_server.cluster_node]
+  priority         = "High"
+}
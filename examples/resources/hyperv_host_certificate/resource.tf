@@ -0,0 +1,40 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+# Connect over HTTP to bootstrap the WinRM HTTPS listener of a freshly installed host
+provider "hyperv" {
+  alias    = "bootstrap"
+  host     = "hyperv01.contoso.com"
+  port     = 5985
+  https    = false
+  use_ntlm = true
+}
+
+resource "hyperv_host_certificate" "hyperv01" {
+  provider                  = hyperv.bootstrap
+  hostname                  = "hyperv01.contoso.com"
+  subject_alternative_names = ["hyperv01"]
+  validity_years            = 5
+  port                      = 5986
+  firewall_rule             = true
+  firewall_remote_addresses = ["10.0.0.0/24"]
+}
+
+resource "local_file" "hyperv01_certificate" {
+  filename = "${path.module}/hyperv01.pem"
+  content  = hyperv_host_certificate.hyperv01.certificate_pem
+}
+
+# Manage everything else over HTTPS, trusting the self-signed certificate
+provider "hyperv" {
+  host        = "hyperv01.contoso.com"
+  port        = hyperv_host_certificate.hyperv01.port
+  https       = true
+  cacert_path = local_file.hyperv01_certificate.filename
+}
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_host_live_migration_settings" "host" {
+  enabled                            = true
+  authentication_type                = "Kerberos"
+  maximum_virtual_machine_migrations = 4
+  performance_option                 = "SMB"
+  use_any_network_for_migration      = false
+  migration_networks                 = ["192.168.10.0/24"]
+}
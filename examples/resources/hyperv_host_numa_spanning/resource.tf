@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+# keeps every virtual machine within one NUMA node and 4 GB of memory for the root partition on a host dedicated to
+# latency sensitive virtual machines
+resource "hyperv_host_numa_spanning" "host" {
+  numa_spanning_enabled = false
+  memory_reserve_mb     = 4096
+}
+
+output "host_restart_pending" {
+  value = hyperv_host_numa_spanning.host.restart_pending
+}
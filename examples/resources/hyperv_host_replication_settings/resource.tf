@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_host_replication_settings" "replica" {
+  replication_enabled                 = true
+  allowed_authentication_type         = "Kerberos"
+  kerberos_authentication_port        = 80
+  replication_allowed_from_any_server = true
+  default_storage_location            = "D:\\Hyper-V\\Replica"
+}
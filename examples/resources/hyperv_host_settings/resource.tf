@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_host_settings" "host" {
+  virtual_hard_disk_path                = "D:\\Hyper-V\\Virtual Hard Disks"
+  virtual_machine_path                  = "D:\\Hyper-V"
+  numa_spanning_enabled                 = false
+  enable_enhanced_session_mode          = true
+  resource_metering_save_interval_hours = 1
+  mac_address_minimum                   = "00155D010000"
+  mac_address_maximum                   = "00155D01FFFF"
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_iso_library" "images" {
+  name            = "images"
+  description     = "Curated installation media"
+  cache_directory = "D:\\IsoCache"
+
+  iso {
+    name     = "ubuntu-22.04"
+    path     = "\\\\fileserver\\iso\\ubuntu-22.04.3-live-server-amd64.iso"
+    checksum = "a4acfda10b18da50e2ec50ccaf860d7f20b389df8765611142305c0e911d16fd"
+  }
+
+  iso {
+    name          = "windows-server-2022"
+    path          = "D:\\Iso\\windows_server_2022.iso"
+    checksum      = "4f1457c4fe14ce48c9b2324924f33ca4f0470475e6da851b39ccbf98f44e7852"
+    checksum_type = "SHA256"
+  }
+}
@@ -33,25 +33,34 @@ resource "hyperv_vhd" "web_server_g2_vhd" {
 }
 
 resource "hyperv_machine_instance" "default" {
-  name                                    = "WebServer"
-  generation                              = 2
-  automatic_critical_error_action         = "Pause"
-  automatic_critical_error_action_timeout = 30
-  automatic_start_action                  = "StartIfRunning"
-  automatic_start_delay                   = 0
-  automatic_stop_action                   = "Save"
-  checkpoint_type                         = "Production"
-  guest_controlled_cache_types            = false
-  high_memory_mapped_io_space             = 536870912
-  lock_on_disconnect                      = "Off"
-  low_memory_mapped_io_space              = 134217728
-  memory_maximum_bytes                    = 1099511627776
-  memory_minimum_bytes                    = 536870912
-  memory_startup_bytes                    = 536870912
-  notes                                   = ""
-  processor_count                         = 1
-  smart_paging_file_path                  = "C:\\ProgramData\\Microsoft\\Windows\\Hyper-V"
-  snapshot_file_location                  = "C:\\ProgramData\\Microsoft\\Windows\\Hyper-V"
+  name                                      = "WebServer"
+  generation                                = 2
+  guest_state_isolation_type                = "Disabled"
+  automatic_critical_error_action           = "Pause"
+  automatic_critical_error_action_timeout   = 30
+  automatic_start_action                    = "StartIfRunning"
+  automatic_start_delay                     = 0
+  automatic_stop_action                     = "Save"
+  avma_key                                  = ""
+  checkpoint_type                           = "Production"
+  checkpoint_before_update                  = false
+  checkpoint_before_update_retention        = 0
+  delete_vhds_on_destroy                    = false
+  delete_seed_iso_on_destroy                = true
+  deletion_protection                       = false
+  guest_controlled_cache_types              = false
+  high_memory_mapped_io_space               = 536870912
+  lock_on_disconnect                        = "Off"
+  low_memory_mapped_io_space                = 134217728
+  memory_maximum_bytes                      = 1099511627776
+  memory_minimum_bytes                      = 536870912
+  memory_startup_bytes                      = 536870912
+  memory_maximum_amount_per_numa_node_bytes = 0
+  memory_preferred_numa_nodes               = []
+  notes                                     = ""
+  processor_count                           = 1
+  smart_paging_file_path                    = "C:\\ProgramData\\Microsoft\\Windows\\Hyper-V"
+  snapshot_file_location                    = "C:\\ProgramData\\Microsoft\\Windows\\Hyper-V"
   #dynamic_memory                         = false
   static_memory = true
   state         = "Running"
@@ -74,6 +83,13 @@ resource "hyperv_machine_instance" "default" {
     }
   }
 
+  # Boot from the network the first time the machine instance is started e.g. for a PXE install
+  #boot_from_network {
+  #  network_adapter_name = "wan"
+  #  disable_secure_boot  = false
+  #  first_boot_timeout   = 1200
+  #}
+
   # Configure processor
   vm_processor {
     compatibility_for_migration_enabled               = false
@@ -143,7 +159,7 @@ resource "hyperv_machine_instance" "default" {
     controller_number   = "0"
     controller_location = "1"
     path                = ""
-    #path                = "c:/iso/windows-server-2016.iso"
+    #path                = "C:\\Iso\\windows-server-2016.iso"
     resource_pool_name = ""
   }
 
@@ -162,3 +178,49 @@ resource "hyperv_machine_instance" "default" {
     override_cache_attributes       = "Default"
   }
 }
+
+# Create a virtual machine with the devices declared in a machine specification document, usually read with
+# file() from the inventory exported from another hypervisor
+resource "hyperv_machine_instance" "migrated_server" {
+  name       = "MigratedServer"
+  generation = 2
+
+  manifest_json = jsonencode({
+    network_adaptors = [
+      {
+        name        = "wan"
+        switch_name = "wan"
+      }
+    ]
+    hard_disk_drives = [
+      {
+        controller_type     = "Scsi"
+        controller_number   = 0
+        controller_location = 0
+        path                = "C:\\VMs\\migrated_server.vhdx"
+      }
+    ]
+  })
+}
+
+# Create a virtual machine that is provisioned with cloud-init from a seed iso
+resource "hyperv_machine_instance" "linux_server" {
+  name       = "LinuxServer"
+  generation = 2
+
+  cloud_init {
+    path      = "C:\\Iso\\linux_server-cidata.iso"
+    user_data = <<-EOT
+      #cloud-config
+      hostname: linux-server
+      package_update: true
+    EOT
+  }
+
+  hard_disk_drives {
+    controller_type     = "Scsi"
+    controller_number   = "0"
+    controller_location = "0"
+    path                = "C:\\VMs\\linux_server.vhdx"
+  }
+}
@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_network_switch" "nat" {
+  name        = "NAT"
+  switch_type = "Internal"
+}
+
+resource "hyperv_nat" "nat" {
+  name                                 = "NAT"
+  internal_ip_interface_address_prefix = "192.168.100.0/24"
+  switch_name                          = hyperv_network_switch.nat.name
+  gateway_address                      = "192.168.100.1"
+}
@@ -0,0 +1,16 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_nat_network" "lab" {
+  name = "Lab"
+  cidr = "192.168.100.0/24"
+}
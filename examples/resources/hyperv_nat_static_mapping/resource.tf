@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_network_switch" "nat" {
+  name        = "NAT"
+  switch_type = "Internal"
+}
+
+resource "hyperv_nat" "nat" {
+  name                                 = "NAT"
+  internal_ip_interface_address_prefix = "192.168.100.0/24"
+  switch_name                          = hyperv_network_switch.nat.name
+}
+
+resource "hyperv_nat_static_mapping" "rdp" {
+  nat_name            = hyperv_nat.nat.name
+  protocol            = "TCP"
+  external_ip_address = "0.0.0.0"
+  external_port       = 50001
+  internal_ip_address = "192.168.100.10"
+  internal_port       = 3389
+}
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_network_switch" "set" {
+  name                    = "SET"
+  switch_type             = "External"
+  allow_management_os     = true
+  enable_embedded_teaming = true
+  net_adapter_names       = ["NIC1", "NIC2"]
+}
+
+resource "hyperv_network_adapter_team_mapping" "management_os" {
+  management_os                 = true
+  switch_name                   = hyperv_network_switch.set.name
+  network_adapter_name          = "SMB1"
+  physical_network_adapter_name = "NIC1"
+}
+
+resource "hyperv_network_adapter_team_mapping" "web_server" {
+  vm_name                       = "web_server"
+  network_adapter_name          = "wan"
+  physical_network_adapter_name = "NIC2"
+}
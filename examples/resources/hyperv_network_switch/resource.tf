@@ -25,4 +25,5 @@ resource "hyperv_network_switch" "default" {
   default_queue_vmmq_enabled              = false
   default_queue_vmmq_queue_pairs          = 16
   default_queue_vrss_enabled              = false
+  enable_packet_capture                   = false
 }
\ No newline at end of file
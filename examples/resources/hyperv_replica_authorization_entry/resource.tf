@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_host_replication_settings" "replica" {
+  replication_enabled         = true
+  allowed_authentication_type = "Kerberos"
+}
+
+resource "hyperv_replica_authorization_entry" "primary" {
+  allowed_primary_server   = "hyperv-primary.contoso.com"
+  replica_storage_location = "D:\\Hyper-V\\Replica"
+  trust_group              = "DEFAULT"
+
+  depends_on = [hyperv_host_replication_settings.replica]
+}
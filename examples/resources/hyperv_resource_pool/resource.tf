@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_resource_pool" "tenant_a_memory" {
+  name                      = "TenantA"
+  pool_type                 = "Memory"
+  resource_metering_enabled = true
+}
+
+resource "hyperv_resource_pool" "tenant_a_processor" {
+  name                      = "TenantA"
+  pool_type                 = "Processor"
+  resource_metering_enabled = true
+}
+
+resource "hyperv_resource_pool" "tenant_a_vhd" {
+  name                      = "TenantA"
+  pool_type                 = "VHD"
+  paths                     = ["D:\\Hyper-V\\TenantA\\Virtual Hard Disks"]
+  resource_metering_enabled = true
+}
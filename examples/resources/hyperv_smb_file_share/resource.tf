@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_smb_file_share" "vms" {
+  name                   = "VMs"
+  path                   = "C:\\ClusterStorage\\Volume1\\Shares\\VMs"
+  description            = "Virtual machine storage"
+  scope_name             = "SOFS01"
+  continuously_available = true
+
+  full_access = [
+    "BUILTIN\\Administrators",
+    "CONTOSO\\HV01$",
+    "CONTOSO\\HV02$",
+  ]
+}
@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_storage_qos_policy" "gold" {
+  name         = "Gold"
+  policy_type  = "Dedicated"
+  minimum_iops = 500
+  maximum_iops = 5000
+}
+
+resource "hyperv_machine_instance" "web_server" {
+  name = "web_server_g2"
+
+  hard_disk_drives {
+    controller_type     = "Scsi"
+    controller_number   = 0
+    controller_location = 0
+    path                = "C:\\ClusterStorage\\Volume1\\web_server\\web_server_g2.vhdx"
+    qos_policy_id       = hyperv_storage_qos_policy.gold.policy_id
+  }
+}
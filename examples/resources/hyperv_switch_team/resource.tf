@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_switch_team" "uplink" {
+  name                     = "Uplink"
+  team_members             = ["NIC1", "NIC2"]
+  teaming_mode             = "SwitchIndependent"
+  load_balancing_algorithm = "HyperVPort"
+}
+
+resource "hyperv_network_switch" "external" {
+  name                = "External"
+  switch_type         = "External"
+  allow_management_os = true
+  net_adapter_names   = [hyperv_switch_team.uplink.net_adapter_name]
+}
@@ -14,6 +14,7 @@ resource "hyperv_vhd" "web_server_vhd" {
   path = "c:\\web_server\\web_server_g2.vhdx"
   #source               = ""
   #source_vm            = ""
+  #source_vm_disk_index = -1
   #source_disk          = 0
   vhd_type = "Dynamic"
   #parent_path          = ""
@@ -21,4 +22,57 @@ resource "hyperv_vhd" "web_server_vhd" {
   #block_size           = 0
   #logical_sector_size  = 0
   #physical_sector_size = 0
-}
\ No newline at end of file
+}
+
+variable "unattend" {
+  type      = string
+  sensitive = true
+}
+
+resource "hyperv_vhd" "windows_server_vhd" {
+  path = "c:\\web_server\\windows_server_2022_g2.vhdx"
+  size = 42949672960 #40GB
+
+  # refuse to delete the golden image the differencing disks depend on, until this is set to false
+  deletion_protection = true
+
+  windows_image {
+    iso_path     = "c:\\iso\\windows_server_2022.iso"
+    image_name   = "Windows Server 2022 Standard (Desktop Experience)"
+    unattend     = var.unattend
+    driver_paths = ["c:\\drivers"]
+    generation   = 2
+  }
+
+  timeouts {
+    create = "60m"
+  }
+}
+
+resource "hyperv_vhd" "web_server_differencing_vhd" {
+  path        = "c:\\web_server\\web_server_differencing_g2.vhdx"
+  vhd_type    = "Differencing"
+  parent_path = hyperv_vhd.windows_server_vhd.path
+
+  # re-link the differencing disk when the golden image is moved, instead of recreating it
+  repair_parent_path = true
+
+  # fail the plan when the chain of the differencing disk is broken
+  validate_health = true
+}
+
+# download the golden image again when a new image is published at the url
+resource "hyperv_vhd" "ubuntu_golden_vhd" {
+  path                 = "c:\\web_server\\ubuntu_golden_g2.vhdx"
+  source               = "https://images.contoso.com/ubuntu/ubuntu-22.04-server-g2.vhdx"
+  track_source_version = true
+}
+
+resource "hyperv_vhd" "ubuntu_differencing_vhd" {
+  path        = "c:\\web_server\\ubuntu_differencing_g2.vhdx"
+  vhd_type    = "Differencing"
+  parent_path = hyperv_vhd.ubuntu_golden_vhd.path
+
+  # recreate the differencing disk from the new golden image
+  parent_image_version = hyperv_vhd.ubuntu_golden_vhd.image_version
+}
@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_virtual_machine_path" "web01" {
+  root_path = "D:\\Hyper-V"
+  name      = "web01"
+}
+
+resource "hyperv_vhd" "web01" {
+  path = "${hyperv_virtual_machine_path.web01.virtual_hard_disks_path}\\web01.vhdx"
+  size = 10737418240
+}
+
+resource "hyperv_machine_instance" "web01" {
+  name                   = hyperv_virtual_machine_path.web01.name
+  path                   = hyperv_virtual_machine_path.web01.root_path
+  snapshot_file_location = hyperv_virtual_machine_path.web01.snapshots_path
+  generation             = 2
+
+  hard_disk_drives {
+    controller_type     = "Scsi"
+    controller_number   = 0
+    controller_location = 0
+    path                = hyperv_vhd.web01.path
+  }
+}
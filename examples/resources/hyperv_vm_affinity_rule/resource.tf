@@ -0,0 +1,30 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_machine_instance" "web_server" {
+  count        = 2
+  name         = "web_server_${count.index}"
+  generation   = 2
+  cluster_name = "hyperv-cluster"
+}
+
+resource "hyperv_cluster_vm_role" "web_server" {
+  count   = 2
+  vm_name = hyperv_machine_instance.web_server[count.index].name
+}
+
+resource "hyperv_vm_affinity_rule" "web_servers" {
+  name         = "web_servers"
+  rule_type    = "AntiAffinity"
+  cluster_name = "hyperv-cluster"
+  vm_names     = hyperv_cluster_vm_role.web_server[*].vm_name
+}
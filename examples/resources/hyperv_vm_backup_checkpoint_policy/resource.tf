@@ -0,0 +1,19 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_backup_checkpoint_policy" "web_server" {
+  vm_name                       = "WebServer"
+  checkpoint_type               = "ProductionOnly"
+  automatic_checkpoints_enabled = false
+  backup_integration_enabled    = true
+  reference_point_retention     = 2
+}
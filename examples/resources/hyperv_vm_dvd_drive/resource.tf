@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_dvd_drive" "install" {
+  vm_name             = "web"
+  controller_number   = 0
+  controller_location = 1
+  path                = "C:\\Iso\\install.iso"
+}
+
+resource "hyperv_vm_dvd_drive" "ubuntu" {
+  vm_name             = "build"
+  controller_number   = 0
+  controller_location = 1
+  iso_library         = hyperv_iso_library.images.name
+  iso_name            = "ubuntu-22.04"
+}
@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_group" "web_application" {
+  name       = "web_application"
+  group_type = "VMCollectionType"
+  vm_members = ["web_server_g2", "database_server_g2"]
+}
+
+resource "hyperv_vm_group" "applications" {
+  name             = "applications"
+  group_type       = "ManagementCollectionType"
+  vm_group_members = [hyperv_vm_group.web_application.name]
+}
@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vhd" "data" {
+  path = "C:\\VirtualMachines\\web-data.vhdx"
+  size = 10737418240
+}
+
+resource "hyperv_vm_hard_disk_drive" "data" {
+  vm_name             = "web"
+  controller_type     = "Scsi"
+  controller_number   = 0
+  controller_location = 1
+  path                = hyperv_vhd.data.path
+}
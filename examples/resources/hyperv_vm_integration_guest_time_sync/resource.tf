@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+# time sync keeps the clock right until the guest joins the domain, the domain hierarchy takes over after that
+resource "hyperv_vm_integration_guest_time_sync" "web_server" {
+  vm_name = "WebServer"
+  policy  = "DisabledWhenDomainJoined"
+}
@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_migration" "web_storage" {
+  vm_name                  = "web"
+  destination_storage_path = "E:\\Hyper-V\\web"
+}
+
+resource "hyperv_vm_migration" "db_host" {
+  vm_name                  = "db"
+  destination_host         = "hyperv02.contoso.com"
+  include_storage          = true
+  destination_storage_path = "D:\\Hyper-V\\db"
+}
@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_network_adapter" "wan" {
+  vm_name     = "web"
+  name        = "wan"
+  switch_name = "Default Switch"
+}
+
+# keeps the MAC address of eth0 when the network adapter is created again, so the netplan configuration of the linux
+# guest still applies
+resource "hyperv_vm_network_adapter" "eth0" {
+  vm_name                  = "linux"
+  name                     = "eth0"
+  switch_name              = "Default Switch"
+  preserve_mac_on_recreate = true
+}
+
+# publishes the name the guest is expected to register in dns, so that it can pick it up from the KVP exchange
+# integration service, and reports whether it did
+resource "hyperv_vm_network_adapter" "lan" {
+  vm_name          = "web"
+  name             = "lan"
+  switch_name      = "Default Switch"
+  guest_host_name  = "web01"
+  guest_dns_suffix = "corp.example.com"
+}
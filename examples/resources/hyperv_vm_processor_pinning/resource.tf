@@ -0,0 +1,18 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_processor_pinning" "realtime" {
+  vm_name            = "RealTimeServer"
+  logical_processors = [2, 3]
+  cpu_cap_percent    = 100
+  cpu_groups_path    = "C:\\Tools\\CpuGroups.exe"
+}
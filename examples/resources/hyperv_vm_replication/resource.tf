@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_replication" "web" {
+  vm_name                        = "web"
+  replica_server_name            = "hyperv-dr.contoso.com"
+  replica_server_port            = 80
+  authentication_type            = "Kerberos"
+  replication_frequency_sec      = 300
+  recovery_history               = 4
+  vss_snapshot_frequency_hour    = 4
+  compression_enabled            = true
+  initial_replication_start_time = "2024-01-01T23:00:00Z"
+}
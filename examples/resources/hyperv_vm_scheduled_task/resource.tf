@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vm_scheduled_task" "nightly_checkpoint" {
+  vm_name              = "WebServer"
+  name                 = "nightly-checkpoint"
+  action               = "Checkpoint"
+  checkpoint_retention = 7
+  at                   = "02:00"
+}
+
+resource "hyperv_vm_scheduled_task" "weekly_optimize_vhd" {
+  vm_name      = "WebServer"
+  name         = "weekly-optimize-vhd"
+  action       = "OptimizeVhd"
+  frequency    = "Weekly"
+  days_of_week = ["Sunday"]
+  at           = "04:00"
+}
+
+resource "hyperv_vm_scheduled_task" "export_config" {
+  vm_name = "WebServer"
+  name    = "export-config"
+  action  = "Script"
+  script  = <<-EOT
+    param($VmName)
+    Get-VM -Name $VmName | Select-Object -Property * | ConvertTo-Json | Set-Content -Path "D:\Reports\$VmName.json"
+  EOT
+}
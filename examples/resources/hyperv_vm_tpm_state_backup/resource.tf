@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+variable "guardian_backup_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "hyperv_vm_tpm_state_backup" "web_server" {
+  vm_name                  = "WebServer"
+  path                     = "\\\\backup\\hyperv\\web_server.kp"
+  guardian_backup_path     = "\\\\backup\\hyperv\\guardian"
+  guardian_backup_password = var.guardian_backup_password
+}
@@ -0,0 +1,26 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_vmware_migration" "web_server" {
+  source_path      = "C:\\Vmware\\web_server.ova"
+  destination_path = "C:\\Hyper-V\\Virtual Hard Disks\\web_server"
+  switch_name      = "wan"
+}
+
+resource "hyperv_machine_instance" "web_server" {
+  name                 = hyperv_vmware_migration.web_server.name
+  generation           = hyperv_vmware_migration.web_server.generation
+  processor_count      = hyperv_vmware_migration.web_server.processor_count
+  memory_startup_bytes = hyperv_vmware_migration.web_server.memory_startup_bytes
+  static_memory        = true
+  manifest_json        = hyperv_vmware_migration.web_server.manifest_json
+}
@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_network_switch" "dmz" {
+  name = "DMZ"
+}
+
+resource "hyperv_vswitch_extension" "capture" {
+  switch_name = hyperv_network_switch.dmz.name
+  name        = "Microsoft NDIS Capture"
+  enabled     = true
+}
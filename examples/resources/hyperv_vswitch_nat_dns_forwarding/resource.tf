@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_nat_network" "lab" {
+  name            = "Lab"
+  cidr            = "192.168.100.0/24"
+  gateway_address = "192.168.100.1"
+}
+
+resource "hyperv_vswitch_nat_dns_forwarding" "lab" {
+  switch_name = hyperv_nat_network.lab.switch_name
+  forwarders  = ["1.1.1.1", "8.8.8.8"]
+}
+
+resource "hyperv_dhcp_server_scope" "lab" {
+  name        = "Lab"
+  start_range = "192.168.100.100"
+  end_range   = "192.168.100.200"
+  subnet_mask = "255.255.255.0"
+  router      = hyperv_nat_network.lab.gateway_address
+  dns_servers = hyperv_vswitch_nat_dns_forwarding.lab.listen_addresses
+  switch_name = hyperv_nat_network.lab.switch_name
+}
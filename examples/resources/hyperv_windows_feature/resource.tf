@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    hyperv = {
+      source  = "taliesins/hyperv"
+      version = ">= 1.0.3"
+    }
+  }
+}
+
+provider "hyperv" {
+}
+
+resource "hyperv_windows_feature" "hyperv" {
+  name                     = "Hyper-V"
+  include_all_sub_features = false
+  include_management_tools = true
+  restart                  = true
+  restart_timeout          = 1800
+}
+
+resource "hyperv_windows_feature" "data_deduplication" {
+  name = "FS-Data-Deduplication"
+}
+
+resource "hyperv_network_switch" "lan" {
+  name = "lan"
+
+  depends_on = [hyperv_windows_feature.hyperv]
+}
@@ -50,7 +50,7 @@ resource "hyperv_machine_instance" "web_server_g1" {
   dvd_drives {
     controller_number   = 0
     controller_location = 1
-    #path                = "ubuntu.iso"
+    #path                = "C:\\Iso\\ubuntu.iso"
   }
 }
 
@@ -95,6 +95,6 @@ resource "hyperv_machine_instance" "web_server_g2" {
   dvd_drives {
     controller_number   = 0
     controller_location = 1
-    #path                = "ubuntu.iso"
+    #path                = "C:\\Iso\\ubuntu.iso"
   }
 }
\ No newline at end of file
@@ -2,11 +2,13 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/taliesins/terraform-provider-hyperv/api"
@@ -41,8 +43,12 @@ type Config struct {
 	Cert          []byte
 	Key           []byte
 
-	ScriptPath string
-	Timeout    string
+	ScriptPath    string
+	Timeout       string
+	WorkspacePath string
+
+	StageScriptModule                        bool
+	ScriptModuleSigningCertificateThumbprint string
 }
 
 // HypervWinRmClient() returns a new client for configuring hyperv.
@@ -68,7 +74,11 @@ func (c *Config) Client() (comm api.Client, err error) {
 		"  Key: %t\n"+
 
 		"  ScriptPath: %s\n"+
-		"  Timeout: %s",
+		"  Timeout: %s\n"+
+		"  WorkspacePath: %s\n"+
+
+		"  StageScriptModule: %t\n"+
+		"  ScriptModuleSigningCertificateThumbprint: %s",
 		c.Host,
 		c.Port,
 		c.User,
@@ -86,6 +96,9 @@ func (c *Config) Client() (comm api.Client, err error) {
 		c.Key != nil,
 		c.ScriptPath,
 		c.Timeout,
+		c.WorkspacePath,
+		c.StageScriptModule,
+		c.ScriptModuleSigningCertificateThumbprint,
 	)
 
 	hyperVProvider, err := getHypervProvider(c)
@@ -220,17 +233,75 @@ func getHypervProvider(config *Config) (hypervProvider *api.Provider, err error)
 	winRmClientPool.Config.TimeBetweenEvictionRuns = 10 * time.Second
 
 	winrmHelperProvider, err := winrm_helper.New(&winrm_helper.ClientConfig{
-		WinRmClientPool:  winRmClientPool,
-		Vars:             "",
-		ElevatedUser:     config.User,
-		ElevatedPassword: config.Password,
+		WinRmClientPool:                          winRmClientPool,
+		Vars:                                     "",
+		ElevatedUser:                             config.User,
+		ElevatedPassword:                         config.Password,
+		ScriptModule:                             config.StageScriptModule,
+		ScriptModuleSigningCertificateThumbprint: config.ScriptModuleSigningCertificateThumbprint,
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return hyperv_winrm.New(&hyperv_winrm.ClientConfig{
-		WinRmClient: winrmHelperProvider.Client,
+	runId, err := newRunId()
+	if err != nil {
+		return nil, err
+	}
+
+	hypervProvider, err = hyperv_winrm.New(&hyperv_winrm.ClientConfig{
+		WinRmClient:       winrmHelperProvider.Client,
+		WorkspaceRootPath: strings.TrimRight(strings.ReplaceAll(config.WorkspacePath, "/", `\`), `\`),
+		RunId:             runId,
 	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = hypervProvider.Client.CreateWorkspace(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create workspace in %s: %s", config.WorkspacePath, err)
+	}
+
+	registerWorkspaceClient(hypervProvider.Client)
+
+	return hypervProvider, nil
+}
+
+func newRunId() (string, error) {
+	random := make([]byte, 4)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%x", time.Now().UTC().Format("20060102T150405Z"), random), nil
+}
+
+var workspaceClientsMutex sync.Mutex
+var workspaceClients []api.HypervWorkspaceClient
+
+func registerWorkspaceClient(workspaceClient api.HypervWorkspaceClient) {
+	workspaceClientsMutex.Lock()
+	defer workspaceClientsMutex.Unlock()
+
+	workspaceClients = append(workspaceClients, workspaceClient)
+}
+
+// Cleanup removes the workspaces created on the HyperV host machines during this provider run. It is called once
+// Terraform has stopped the provider, so it is best effort as Terraform kills the provider shortly after.
+func Cleanup() {
+	workspaceClientsMutex.Lock()
+	defer workspaceClientsMutex.Unlock()
+
+	for _, workspaceClient := range workspaceClients {
+		err := workspaceClient.DeleteWorkspace(context.Background())
+		if err != nil {
+			log.Printf("[WARN][hyperv] unable to delete workspace: %s", err)
+		}
+	}
+
+	workspaceClients = nil
 }
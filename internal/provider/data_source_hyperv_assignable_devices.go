@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadAssignableDevicesTimeout = 2 * time.Minute
+)
+
+func dataSourceHyperVAssignableDevices() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the PCI devices of the HyperV host machine that can be used with Discrete Device Assignment (DDA), including their location paths and whether they are mounted on the host, dismounted and available for assignment, or assigned to a virtual machine.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadAssignableDevicesTimeout),
+		},
+		ReadContext: datasourceHyperVAssignableDevicesRead,
+		Schema: map[string]*schema.Schema{
+			"friendly_name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: IsValidRegExp(),
+				Description:      "A regular expression that the friendly name of a device must match to be returned.",
+			},
+			"class": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return devices of this device class e.g. `Display`, `Net`, `SCSIAdapter`.",
+			},
+			"state": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringKeyInMap(api.AssignableDeviceState_value, true),
+				Description:      "Only return devices in this state. Valid values to use are `Host`, `Dismounted` and `Assigned`.",
+			},
+			"location_paths": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The location paths of the devices that matched the filters.",
+			},
+			"devices": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The devices that matched the filters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The plug and play instance id of the device.",
+						},
+						"friendly_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The friendly name of the device.",
+						},
+						"class": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The device class of the device.",
+						},
+						"location_path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The location path of the device. This is the value used to dismount the device from the HyperV host machine and to assign it to a virtual machine.",
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The state of the device. `Host` when the device is mounted on the HyperV host machine, `Dismounted` when it is dismounted and available for assignment, `Assigned` when it is assigned to a virtual machine.",
+						},
+						"vm_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the virtual machine the device is assigned to. Empty unless `state` is `Assigned`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVAssignableDevicesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv assignable devices: %#v", d)
+	c := meta.(api.Client)
+
+	friendlyNameRegex := (d.Get("friendly_name_regex")).(string)
+	class := (d.Get("class")).(string)
+	state := (d.Get("state")).(string)
+
+	var friendlyNameRegexp *regexp.Regexp
+	if friendlyNameRegex != "" {
+		var err error
+		friendlyNameRegexp, err = regexp.Compile(friendlyNameRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	assignableDevices, err := c.GetAssignableDevices(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved assignable devices: %+v", assignableDevices)
+
+	filteredAssignableDevices := make([]api.AssignableDevice, 0)
+	locationPaths := make([]string, 0)
+	for _, assignableDevice := range assignableDevices {
+		if friendlyNameRegexp != nil && !friendlyNameRegexp.MatchString(assignableDevice.FriendlyName) {
+			continue
+		}
+
+		if class != "" && !strings.EqualFold(assignableDevice.Class, class) {
+			continue
+		}
+
+		if state != "" && assignableDevice.State != api.ToAssignableDeviceState(state) {
+			continue
+		}
+
+		filteredAssignableDevices = append(filteredAssignableDevices, assignableDevice)
+		locationPaths = append(locationPaths, assignableDevice.LocationPath)
+	}
+
+	if err := d.Set("location_paths", locationPaths); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("devices", api.FlattenAssignableDevices(&filteredAssignableDevices)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", strings.ToLower(class), strings.ToLower(state), friendlyNameRegex))
+
+	log.Printf("[INFO][hyperv][read] read hyperv assignable devices: %#v", d)
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVDefaultSwitch() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the built-in `Default Switch` that is created by Hyper-V on client versions of Windows. The switch is looked up by its well known id, as its name differs by locale and build, so configurations remain portable across machines.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadNetworkSwitchTimeout),
+		},
+		ReadContext: datasourceHyperVDefaultSwitchRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the default switch.",
+			},
+			"switch_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the default switch.",
+			},
+			"switch_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the default switch.",
+			},
+		},
+	}
+}
+
+func datasourceHyperVDefaultSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv default switch: %#v", d)
+	c := meta.(api.Client)
+
+	s, err := c.GetDefaultVMSwitch(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved default switch: %+v", s)
+
+	if s.Name == "" {
+		return diag.Errorf("[ERROR][hyperv][read] Default switch does not exist on this host - %s", api.DefaultVMSwitchId)
+	}
+
+	if err := d.Set("name", s.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switch_id", s.Id); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switch_type", s.SwitchType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(s.Id)
+
+	log.Printf("[INFO][hyperv][read] read hyperv default switch: %#v", d)
+
+	return nil
+}
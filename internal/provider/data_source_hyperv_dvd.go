@@ -18,9 +18,10 @@ func dataSourceHyperVDvd() *schema.Resource {
 		ReadContext: datasourceHyperVDvdRead,
 		Schema: map[string]*schema.Schema{
 			"path": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Path to the existing virtual hard disk file(s) that is being created or being copied to. If a filename or relative path is specified, the virtual hard disk path is calculated relative to the current working directory. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "Path to the existing virtual hard disk file(s) that is being created or being copied to. The path must be absolute, either on a drive e.g. `C:\\VMs\\web.vhdx` or on a share e.g. `\\\\server\\share\\web.vhdx`. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
 			},
 			"source": {
 				Type:     schema.TypeString,
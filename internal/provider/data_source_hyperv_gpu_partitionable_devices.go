@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadGpuPartitionableDevicesTimeout = 1 * time.Minute
+)
+
+func dataSourceHyperVGpuPartitionableDevices() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the GPUs of the HyperV host machine that support partitioning (GPU-P), including their partition counts and video memory, so that GPU partitions can be assigned to virtual machines based on discovered data.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadGpuPartitionableDevicesTimeout),
+		},
+		ReadContext: datasourceHyperVGpuPartitionableDevicesRead,
+		Schema: map[string]*schema.Schema{
+			"friendly_name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: IsValidRegExp(),
+				Description:      "A regular expression that the friendly name of a GPU must match to be returned e.g. `NVIDIA`.",
+			},
+			"minimum_available_vram_bytes": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Only return GPUs with at least this many bytes of video memory that has not been assigned to partitions.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The instance paths of the GPUs that matched the filters.",
+			},
+			"gpus": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The GPUs that matched the filters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The instance path of the GPU. This is the value to use as the instance path when assigning a GPU partition to a virtual machine.",
+						},
+						"friendly_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The friendly name of the GPU.",
+						},
+						"partition_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of partitions the GPU is currently configured for.",
+						},
+						"valid_partition_counts": {
+							Type:        schema.TypeList,
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+							Computed:    true,
+							Description: "The partition counts the GPU supports.",
+						},
+						"total_vram_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The total amount of video memory of the GPU in bytes.",
+						},
+						"available_vram_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The amount of video memory of the GPU that has not been assigned to partitions in bytes.",
+						},
+						"min_partition_vram_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The minimum amount of video memory that can be assigned to a partition in bytes.",
+						},
+						"max_partition_vram_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The maximum amount of video memory that can be assigned to a partition in bytes.",
+						},
+						"optimal_partition_vram_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The optimal amount of video memory to assign to a partition in bytes.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVGpuPartitionableDevicesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv gpu partitionable devices: %#v", d)
+	c := meta.(api.Client)
+
+	friendlyNameRegex := (d.Get("friendly_name_regex")).(string)
+	minimumAvailableVramBytes := int64((d.Get("minimum_available_vram_bytes")).(int))
+
+	var friendlyNameRegexp *regexp.Regexp
+	if friendlyNameRegex != "" {
+		var err error
+		friendlyNameRegexp, err = regexp.Compile(friendlyNameRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	partitionableGpus, err := c.GetPartitionableGpus(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved gpu partitionable devices: %+v", partitionableGpus)
+
+	filteredPartitionableGpus := make([]api.PartitionableGpu, 0)
+	names := make([]string, 0)
+	for _, partitionableGpu := range partitionableGpus {
+		if friendlyNameRegexp != nil && !friendlyNameRegexp.MatchString(partitionableGpu.FriendlyName) {
+			continue
+		}
+
+		if partitionableGpu.AvailableVramBytes < minimumAvailableVramBytes {
+			continue
+		}
+
+		filteredPartitionableGpus = append(filteredPartitionableGpus, partitionableGpu)
+		names = append(names, partitionableGpu.Name)
+	}
+
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("gpus", api.FlattenPartitionableGpus(&filteredPartitionableGpus)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%d", friendlyNameRegex, minimumAvailableVramBytes))
+
+	log.Printf("[INFO][hyperv][read] read hyperv gpu partitionable devices: %#v", d)
+
+	return nil
+}
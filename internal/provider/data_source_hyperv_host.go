@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVHost() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the HyperV host machine, such as its version, capacity and settings, e.g. for capacity checks and conditional configuration.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadHostSettingsTimeout),
+		},
+		ReadContext: datasourceHyperVHostRead,
+		Schema: map[string]*schema.Schema{
+			"computer_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The computer name of the HyperV host machine.",
+			},
+			"os_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the operating system of the HyperV host machine.",
+			},
+			"os_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the operating system of the HyperV host machine e.g. `10.0.20348`.",
+			},
+			"os_build_number": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The build number of the operating system of the HyperV host machine e.g. `20348`.",
+			},
+			"hyperv_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the Hyper-V virtual machine management service.",
+			},
+			"default_vm_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The configuration version new virtual machines are created with by default.",
+			},
+			"logical_processor_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of logical processors of the HyperV host machine.",
+			},
+			"memory_capacity_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The total amount of memory of the HyperV host machine.",
+			},
+			"memory_available_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The amount of memory of the HyperV host machine that is currently available.",
+			},
+			"virtual_hard_disk_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The default folder to store virtual hard disks in.",
+			},
+			"virtual_machine_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The default folder to store virtual machine configuration files in.",
+			},
+			"numa_spanning_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies whether virtual machines can use resources from more than one NUMA node.",
+			},
+			"numa_nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The NUMA nodes of the HyperV host machine.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"node_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The identifier of the NUMA node.",
+						},
+						"logical_processor_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of logical processors in the NUMA node.",
+						},
+						"memory_total_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The total amount of memory in the NUMA node.",
+						},
+						"memory_available_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The amount of memory in the NUMA node that is currently available.",
+						},
+					},
+				},
+			},
+			"iov_support": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies whether the HyperV host machine supports single-root I/O virtualization (SR-IOV).",
+			},
+			"iov_support_reasons": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The reasons the HyperV host machine does not support single-root I/O virtualization (SR-IOV).",
+			},
+			"live_migration_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies whether live migration of virtual machines is enabled on the HyperV host machine.",
+			},
+			"live_migration_authentication_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of authentication used for live migrations.",
+			},
+			"maximum_virtual_machine_migrations": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The maximum number of live migrations that can be performed at the same time.",
+			},
+			"live_migration_performance_option": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The performance option used for live migrations.",
+			},
+			"use_any_network_for_migration": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies whether any available network can be used for live migrations.",
+			},
+			"migration_networks": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The subnets that can be used for live migrations, in order of priority.",
+			},
+		},
+	}
+}
+
+func datasourceHyperVHostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv host: %#v", d)
+	c := meta.(api.Client)
+
+	vmHost, err := c.GetVMHostInfo(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host: %+v", vmHost)
+
+	liveMigration, err := c.GetVMHostLiveMigration(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host live migration: %+v", liveMigration)
+
+	if err := d.Set("computer_name", vmHost.ComputerName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("os_name", vmHost.OsName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("os_version", vmHost.OsVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("os_build_number", vmHost.OsBuildNumber); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("hyperv_version", vmHost.HypervVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("default_vm_version", vmHost.DefaultVmVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("logical_processor_count", vmHost.LogicalProcessorCount); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_capacity_bytes", vmHost.MemoryCapacityBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_available_bytes", vmHost.MemoryAvailableBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("virtual_hard_disk_path", vmHost.VirtualHardDiskPath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("virtual_machine_path", vmHost.VirtualMachinePath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("numa_spanning_enabled", vmHost.NumaSpanningEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("numa_nodes", api.FlattenVmHostNumaNodes(vmHost.NumaNodes)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("iov_support", vmHost.IovSupport); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("iov_support_reasons", vmHost.IovSupportReasons); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("live_migration_enabled", liveMigration.VirtualMachineMigrationEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("live_migration_authentication_type", liveMigration.VirtualMachineMigrationAuthenticationType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("maximum_virtual_machine_migrations", liveMigration.MaximumVirtualMachineMigrations); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("live_migration_performance_option", liveMigration.VirtualMachineMigrationPerformanceOption.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("use_any_network_for_migration", liveMigration.UseAnyNetworkForMigration); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("migration_networks", liveMigration.MigrationNetworks); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmHost.ComputerName)
+
+	log.Printf("[INFO][hyperv][read] read hyperv host: %#v", d)
+
+	return nil
+}
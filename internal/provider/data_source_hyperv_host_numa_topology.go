@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVHostNumaTopology() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the NUMA topology of the HyperV host machine, so that the NUMA settings of virtual machines e.g. `maximum_count_per_numa_node` of `vm_processor` can be computed from the actual host topology.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadHostSettingsTimeout),
+		},
+		ReadContext: datasourceHyperVHostNumaTopologyRead,
+		Schema: map[string]*schema.Schema{
+			"numa_spanning_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies whether virtual machines can use resources from more than one NUMA node.",
+			},
+			"node_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of NUMA nodes of the HyperV host machine.",
+			},
+			"logical_processors_per_node": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of logical processors per NUMA node. When the nodes differ this is the smallest number, so that the value fits in every node.",
+			},
+			"memory_per_node_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The amount of memory per NUMA node in bytes. When the nodes differ this is the smallest amount, so that the value fits in every node.",
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The NUMA nodes of the HyperV host machine.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"node_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The identifier of the NUMA node.",
+						},
+						"logical_processor_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of logical processors in the NUMA node.",
+						},
+						"memory_total_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The total amount of memory in the NUMA node.",
+						},
+						"memory_available_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The amount of memory in the NUMA node that is currently available.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVHostNumaTopologyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv host numa topology: %#v", d)
+	c := meta.(api.Client)
+
+	vmHost, err := c.GetVMHostInfo(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host numa topology: %+v", vmHost.NumaNodes)
+
+	logicalProcessorsPerNode := 0
+	memoryPerNodeBytes := int64(0)
+	for i, numaNode := range vmHost.NumaNodes {
+		if i == 0 || numaNode.LogicalProcessorCount < logicalProcessorsPerNode {
+			logicalProcessorsPerNode = numaNode.LogicalProcessorCount
+		}
+		if i == 0 || numaNode.MemoryTotalBytes < memoryPerNodeBytes {
+			memoryPerNodeBytes = numaNode.MemoryTotalBytes
+		}
+	}
+
+	if err := d.Set("numa_spanning_enabled", vmHost.NumaSpanningEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("node_count", len(vmHost.NumaNodes)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("logical_processors_per_node", logicalProcessorsPerNode); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_per_node_bytes", memoryPerNodeBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("nodes", api.FlattenVmHostNumaNodes(vmHost.NumaNodes)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmHost.ComputerName)
+
+	log.Printf("[INFO][hyperv][read] read hyperv host numa topology: %#v", d)
+
+	return nil
+}
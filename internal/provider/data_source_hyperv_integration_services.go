@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVIntegrationServices() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the integration services of a virtual machine and the operating system reported by the guest through key value pair (KVP) exchange. The guest values are empty when the virtual machine is not running or the key value pair exchange integration service is not available in the guest.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadMachineInstanceTimeout),
+		},
+		ReadContext: datasourceHyperVIntegrationServicesRead,
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the virtual machine.",
+			},
+			"os_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the operating system reported by the guest e.g. `Windows Server 2022 Datacenter`.",
+			},
+			"os_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the operating system reported by the guest e.g. `10.0.20348`.",
+			},
+			"os_build_number": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The build number of the operating system reported by the guest.",
+			},
+			"fully_qualified_domain_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The fully qualified domain name reported by the guest.",
+			},
+			"integration_services_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the integration services reported by the guest.",
+			},
+			"integration_services": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The integration services of the virtual machine.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the integration service.",
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the integration service is enabled.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the integration service e.g. `OK`, `No Contact`.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVIntegrationServicesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv integration services: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+
+	guestIntegrationInfo, err := c.GetVmGuestIntegrationInfo(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved integration services: %+v", guestIntegrationInfo)
+
+	if err := d.Set("os_name", guestIntegrationInfo.OsName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("os_version", guestIntegrationInfo.OsVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("os_build_number", guestIntegrationInfo.OsBuildNumber); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("fully_qualified_domain_name", guestIntegrationInfo.FullyQualifiedDomainName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("integration_services_version", guestIntegrationInfo.IntegrationServicesVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("integration_services", api.FlattenVmInfoIntegrationServices(guestIntegrationInfo.IntegrationServices)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmName)
+
+	log.Printf("[INFO][hyperv][read] read hyperv integration services: %#v", d)
+
+	return nil
+}
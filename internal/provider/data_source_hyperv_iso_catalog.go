@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadIsoCatalogTimeout = 10 * time.Minute
+)
+
+func dataSourceHyperVIsoCatalog() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get the iso files in a directory or share of the HyperV host machine, so that dvd drives can select an image by its logical name e.g. the latest `ubuntu-22.04` instead of a hardcoded path. The checksum of an iso is calculated the first time it is found and kept under `%ProgramData%\\terraform-provider-hyperv\\iso-catalog-checksums` until the iso is written to again, so the first read of a directory with large isos takes a while.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadIsoCatalogTimeout),
+		},
+		ReadContext: datasourceHyperVIsoCatalogRead,
+		Schema: map[string]*schema.Schema{
+			"directory": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "Specifies the directory on the HyperV host machine to look for iso files in, either on a drive e.g. `C:\\Iso` or on a share e.g. `\\\\server\\share\\iso`.",
+			},
+			"recurse": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether the subdirectories of `directory` are searched too.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies the logical name of the isos to return. An iso is named after it when its file name without extension is the logical name, or starts with it followed by `-`, `_`, `.` or a space, so `ubuntu-22.04` returns `ubuntu-22.04.3-live-server-amd64.iso` but not `ubuntu-22.040.iso`. Reading fails when no iso is named after it. All isos are returned when it is not specified.",
+			},
+			"checksum_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.IsoChecksumType_value["sha256"],
+				ValidateDiagFunc: stringKeyInMap(api.IsoChecksumType_value, true),
+				Description:      "Specifies the algorithm the checksums of the isos are calculated with. Valid values to use are `SHA1`, `SHA256`, `SHA384`, `SHA512`, `MD5`.",
+			},
+			"latest_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the latest iso, empty when no iso was found.",
+			},
+			"latest_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path of the latest iso e.g. for `path` of a `hyperv_vm_dvd_drive`, empty when no iso was found.",
+			},
+			"latest_checksum": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The checksum of the latest iso e.g. for `checksum` of an iso of a `hyperv_iso_library`, empty when no iso was found.",
+			},
+			"isos": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The isos that were found, latest first. Isos are ordered by their names with the numbers in them compared as numbers, so that `ubuntu-22.04.10` is later than `ubuntu-22.04.9`, and isos with the same name by when they were last written.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The file name of the iso without its extension.",
+						},
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The path of the iso.",
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The size of the iso in bytes.",
+						},
+						"checksum": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The checksum of the iso in upper case hexadecimal.",
+						},
+						"checksum_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The algorithm the checksum was calculated with.",
+						},
+						"last_write_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "When the iso was last written, in UTC.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVIsoCatalogRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv iso catalog: %#v", d)
+	c := meta.(api.Client)
+
+	directory := (d.Get("directory")).(string)
+	recurse := (d.Get("recurse")).(bool)
+	name := (d.Get("name")).(string)
+	checksumType := api.IsoChecksumType_value[strings.ToLower((d.Get("checksum_type")).(string))]
+
+	isos, err := c.GetIsoCatalog(ctx, directory, recurse, name, checksumType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved iso catalog: %+v", isos)
+
+	selectedIsos := api.SelectIsoCatalogIsos(isos, name)
+	if name != "" && len(selectedIsos) == 0 {
+		return diag.Errorf("[ERROR][hyperv][read] no iso named after %s was found in %s", name, directory)
+	}
+
+	latestIso := api.IsoCatalogIso{}
+	if len(selectedIsos) > 0 {
+		latestIso = selectedIsos[0]
+	}
+
+	if err := d.Set("latest_name", latestIso.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("latest_path", latestIso.Path); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("latest_checksum", latestIso.Checksum); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("isos", api.FlattenIsoCatalogIsos(selectedIsos)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%t/%s/%s", strings.ToLower(directory), recurse, strings.ToLower(name), checksumType))
+
+	log.Printf("[INFO][hyperv][read] read hyperv iso catalog: %#v", d)
+
+	return nil
+}
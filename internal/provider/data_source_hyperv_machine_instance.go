@@ -64,6 +64,12 @@ func dataSourceHyperVMachineInstance() *schema.Resource {
 				Description:      "Specifies the generation, as an integer, for the virtual machine. Valid values to use are `1`, `2`.",
 			},
 
+			"guest_state_isolation_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the guest state of the virtual machine is isolated from the HyperV host machine, `Disabled` when it is not isolated.",
+			},
+
 			"automatic_critical_error_action": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -167,6 +173,26 @@ func dataSourceHyperVMachineInstance() *schema.Resource {
 				Description: "Specifies the amount of memory that the virtual machine is to be allocated upon startup. (If the virtual machine does not use dynamic memory, then this is the static amount of memory to be allocated.)",
 			},
 
+			"memory_resource_pool_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies the name of the memory resource pool the virtual machine is to be associated with, see `hyperv_resource_pool`. When empty the virtual machine uses the default resource pool.",
+			},
+
+			"memory_maximum_amount_per_numa_node_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The maximum amount of memory per virtual NUMA node of the virtual machine.",
+			},
+
+			"memory_preferred_numa_nodes": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "The ids of the NUMA nodes of the HyperV host machine the memory of the virtual machine is preferably allocated from, empty when the hypervisor picks the NUMA nodes.",
+			},
+
 			"notes": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -328,6 +354,34 @@ func dataSourceHyperVMachineInstance() *schema.Resource {
 							Default:     false,
 							Description: "Specifies whether the hypervisor should expose the presence of virtualization extensions to the virtual machine, which enables support for nested virtualization.",
 						},
+						"resource_pool_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "Specifies the name of the processor resource pool the virtual machine is to be associated with, see `hyperv_resource_pool`. When empty the virtual machine uses the default resource pool.",
+						},
+						"enable_legacy_apic_mode": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Specifies whether the virtual machine uses the legacy xAPIC mode instead of x2APIC, for guest operating systems that do not support x2APIC. Requires Windows Server 2019 or later.",
+						},
+
+						"l3_cache_ways": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          0,
+							ValidateDiagFunc: IntBetween(0, 64),
+							Description:      "Specifies the number of L3 cache ways the virtual machine is limited to, to stop it from evicting the cache of other virtual machines. If value is 0 then the virtual machine is not limited. Requires Windows Server 2019 or later.",
+						},
+
+						"perfmon": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: stringKeyInMap(api.VmProcessorPerfmon_value, false)},
+							Set:         schema.HashString,
+							Description: "Specifies the performance monitoring features of the host processor that are exposed to the virtual machine, so monitoring agents and profilers in the guest can use the hardware performance counters. The virtual machine can not be live migrated to a host without the features. Valid values to use are `pmu` (performance monitoring unit), `lbr` (last branch record), `pebs` (precise event based sampling), `ipt` (Intel processor trace). Requires Windows Server 2019 or later.",
+						},
 					},
 				},
 			},
@@ -658,6 +712,11 @@ func dataSourceHyperVMachineInstance() *schema.Resource {
 							DiffSuppressFunc: api.DiffSuppressVmHardDiskPath,
 							Description:      "Specifies the full path of the hard disk drive file to be added.",
 						},
+						"base_path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The path of the hard disk drive file the checkpoints of the virtual machine are based on. When the virtual machine has checkpoints, `path` is the automatic differencing disk the virtual machine writes to.",
+						},
 						"disk_number": {
 							Type:        schema.TypeInt,
 							Optional:    true,
@@ -692,7 +751,7 @@ func dataSourceHyperVMachineInstance() *schema.Resource {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Default:     "00000000-0000-0000-0000-000000000000",
-							Description: "Specifies the unique ID for a storage QoS policy that this cmdlet associates with the hard disk drive. If value is 00000000-0000-0000-0000-000000000000 then qos policy id is ignored.",
+							Description: "Specifies the unique ID for a storage QoS policy that this cmdlet associates with the hard disk drive, see `hyperv_storage_qos_policy`. Can not be used together with `minimum_iops` or `maximum_iops`. If value is 00000000-0000-0000-0000-000000000000 then qos policy id is ignored.",
 						},
 						"override_cache_attributes": {
 							Type:             schema.TypeString,
@@ -860,7 +919,7 @@ func dataSourceHyperVMachineInstance() *schema.Resource {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Default:     "MicrosoftWindows",
-							Description: "Specifies the name of the secure boot template. If secure boot is enabled, you must have a valid secure boot template for the guest operating system to start. Example values to use are `MicrosoftWindows`,`MicrosoftUEFICertificateAuthority`, `OpenSourceShieldedVM`.",
+							Description: "Specifies the name of the secure boot template. If secure boot is enabled, you must have a valid secure boot template for the guest operating system to start. Example values to use are `MicrosoftWindows`,`MicrosoftUEFICertificateAuthority`, `OpenSourceShieldedVM`. The templates available on the HyperV host machine can be looked up with the `hyperv_secure_boot_templates` data source.",
 						},
 
 						"preferred_network_boot_protocol": {
@@ -868,7 +927,7 @@ func dataSourceHyperVMachineInstance() *schema.Resource {
 							Optional:         true,
 							Default:          api.IPProtocolPreference_name[api.IPProtocolPreference_IPv4],
 							ValidateDiagFunc: stringKeyInMap(api.IPProtocolPreference_value, true),
-							Description:      "Specifies the IP protocol version to use during a network boot. Valid values to use are `IPv4`, `IPv6`.",
+							Description:      "Specifies the IP protocol version to use during a network boot, e.g. when booting from a network adapter with `boot_from_network`. Use `IPv6` to PXE boot on an IPv6-only provisioning network, the network must then hand out the boot file with DHCPv6 as the firmware does not fall back to IPv4. Valid values to use are `IPv4`, `IPv6`.",
 						},
 
 						"console_mode": {
@@ -905,64 +964,41 @@ func datasourceHyperVMachineInstanceRead(ctx context.Context, d *schema.Resource
 		return diag.Errorf("[ERROR][hyperv][read] name argument is required")
 	}
 
-	vm, err := client.GetVm(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	var vmFirmwares []api.VmFirmware
-	if vm.Generation > 1 {
-		vmFirmwares, err = client.GetVmFirmwares(ctx, name)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-	} else {
-		vmFirmwares = client.GetNoVmFirmwares(ctx)
-	}
-
-	vmProcessors, err := client.GetVmProcessors(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	integrationServices, err := client.GetVmIntegrationServices(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	dvdDrives, err := client.GetVmDvdDrives(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	hardDiskDrives, err := client.GetVmHardDiskDrives(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	vmState, err := client.GetVmStatus(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
 	networkAdaptersWaitForIps, waitForIpsTimeout, waitForIpsPollPeriod, err := api.ExpandVmNetworkAdapterWaitForIps(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	err = client.WaitForVmNetworkAdaptersIps(ctx, name, waitForIpsTimeout, waitForIpsPollPeriod, networkAdaptersWaitForIps)
+	vmWithDevices, err := client.GetVmWithDevices(ctx, name, networkAdaptersWaitForIps)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	networkAdapters, err := client.GetVmNetworkAdapters(ctx, name, networkAdaptersWaitForIps)
-	if err != nil {
-		return diag.FromErr(err)
+	vm := vmWithDevices.Vm
+	vmFirmwares := vmWithDevices.VmFirmwares
+	vmProcessors := vmWithDevices.VmProcessors
+	integrationServices := vmWithDevices.IntegrationServices
+	dvdDrives := vmWithDevices.DvdDrives
+	hardDiskDrives := vmWithDevices.HardDiskDrives
+	vmState := vmWithDevices.VmStatus
+	networkAdapters := vmWithDevices.NetworkAdapters
+
+	// ips are only read again once they are available, everything else has already been read in one go
+	if api.IsWaitingForVmNetworkAdapterIps(networkAdaptersWaitForIps) {
+		err = client.WaitForVmNetworkAdaptersIps(ctx, name, waitForIpsTimeout, waitForIpsPollPeriod, networkAdaptersWaitForIps)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		networkAdapters, err = client.GetVmNetworkAdapters(ctx, name, networkAdaptersWaitForIps)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	log.Printf("[INFO][hyperv][read] retrieved vm: %+v", vm)
 
-	if vm.Name != name {
+	if !strings.EqualFold(vm.Name, name) {
 		log.Printf("[INFO][hyperv][read] unable to read hyperv machine as it does not exist: %#v", name)
 		return nil
 	}
@@ -1029,6 +1065,9 @@ func datasourceHyperVMachineInstanceRead(ctx context.Context, d *schema.Resource
 	if err := d.Set("generation", vm.Generation); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("guest_state_isolation_type", vm.GuestStateIsolationType.String()); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("automatic_critical_error_action", vm.AutomaticCriticalErrorAction.String()); err != nil {
 		return diag.FromErr(err)
 	}
@@ -1071,6 +1110,15 @@ func datasourceHyperVMachineInstanceRead(ctx context.Context, d *schema.Resource
 	if err := d.Set("memory_startup_bytes", vm.MemoryStartupBytes); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("memory_resource_pool_name", vm.MemoryResourcePoolName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_maximum_amount_per_numa_node_bytes", vmWithDevices.VmMemoryNuma.MaximumAmountPerNumaNodeBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_preferred_numa_nodes", vmWithDevices.VmMemoryNuma.PreferredNumaNodes); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("notes", vm.Notes); err != nil {
 		return diag.FromErr(err)
 	}
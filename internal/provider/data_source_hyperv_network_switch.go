@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -37,6 +38,12 @@ func dataSourceHyperVNetworkSwitch() *schema.Resource {
 				Description: "Specifies if the HyperV host machine will have access to network switch when created. It provides this access via a virtual adaptor, so you will need to either configure static ips on the virtual adaptor or configure a dhcp on a machine connected to the network switch. This is tied to the switch type used: `internal=true`;`private=false`;`external=true or false`.",
 			},
 
+			"management_os_vlan_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Specifies the access VLAN of the virtual adaptor the HyperV host machine uses to access the network switch. `0` means untagged.",
+			},
+
 			"enable_embedded_teaming": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -85,6 +92,18 @@ func dataSourceHyperVNetworkSwitch() *schema.Resource {
 				Description: " Specifies the name of the network adapter to be bound to the switch. ",
 			},
 
+			"load_balancing_algorithm": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Specifies the load balancing algorithm that the switch embedded teaming (SET) team uses to distribute traffic across the network adapters in `net_adapter_names`. Valid values are `HyperVPort`, `Dynamic`.",
+			},
+
+			"teaming_mode": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Specifies the teaming mode of the switch embedded teaming (SET) team.",
+			},
+
 			"default_flow_minimum_bandwidth_absolute": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -144,7 +163,7 @@ func datasourceHyperVNetworkSwitchRead(ctx context.Context, d *schema.ResourceDa
 
 	log.Printf("[INFO][hyperv][read] retrieved network switch: %+v", s)
 
-	if s.Name != switchName {
+	if !strings.EqualFold(s.Name, switchName) {
 		log.Printf("[INFO][hyperv][read] unable to read hyperv switch as it does not exist: %#v", switchName)
 		return nil
 	}
@@ -209,6 +228,9 @@ func datasourceHyperVNetworkSwitchRead(ctx context.Context, d *schema.ResourceDa
 	if err := d.Set("allow_management_os", s.AllowManagementOS); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("management_os_vlan_id", s.ManagementOsVlanId); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("enable_embedded_teaming", s.EmbeddedTeamingEnabled); err != nil {
 		return diag.FromErr(err)
 	}
@@ -227,6 +249,14 @@ func datasourceHyperVNetworkSwitchRead(ctx context.Context, d *schema.ResourceDa
 	if err := d.Set("net_adapter_names", s.NetAdapterNames); err != nil {
 		return diag.FromErr(err)
 	}
+	if s.EmbeddedTeamingEnabled {
+		if err := d.Set("load_balancing_algorithm", s.LoadBalancingAlgorithm.String()); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("teaming_mode", s.TeamingMode.String()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	if err := d.Set("default_flow_minimum_bandwidth_absolute", s.DefaultFlowMinimumBandwidthAbsolute); err != nil {
 		return diag.FromErr(err)
 	}
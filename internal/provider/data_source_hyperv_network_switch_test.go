@@ -16,7 +16,7 @@ func TestHyperVDataSourceNetworkSwitch(t *testing.T) {
 		t.Skip("skipping test in short mode.")
 	}
 
-	name := fmt.Sprintf("wan_%d", randInt())
+	name := testAccName("wan")
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadPasswordHashTimeout = 1 * time.Minute
+)
+
+// the characters of a salt are limited to the alphabet of the SHA-512 crypt format
+var passwordHashSaltRegexp = regexp.MustCompile(`^[./0-9A-Za-z]{1,16}$`)
+
+func dataSourceHyperVPasswordHash() *schema.Resource {
+	return &schema.Resource{
+		Description: "Hash a password in the SHA-512 crypt (`$6$`) format that cloud-init and `/etc/shadow` expect for the passwords of linux users, so that the password itself does not have to be put in user data or a seed iso. The password is hashed by the provider, it is not sent to the HyperV host machine, and only a hash of it is stored in the state.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadPasswordHashTimeout),
+		},
+		ReadContext: datasourceHyperVPasswordHashRead,
+		Schema: map[string]*schema.Schema{
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				StateFunc:   api.HashSensitiveValue,
+				Description: "Specifies the password to hash.",
+			},
+			"salt": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: StringMatch(passwordHashSaltRegexp, "expected a salt of 1 to 16 letters, digits, periods and slashes"),
+				Description:      "Specifies the salt of the hash, 1 to 16 letters, digits, periods and slashes. The same password and salt always give the same hash, so use a random salt that is kept in the state e.g. the `result` of a `random_string` resource with `special = false`.",
+			},
+			"rounds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          api.Sha512CryptDefaultRounds,
+				ValidateDiagFunc: IntBetween(api.Sha512CryptMinimumRounds, api.Sha512CryptMaximumRounds),
+				Description:      "Specifies the number of rounds of hashing, more rounds make the hash slower to brute force.",
+			},
+			"sha512_crypt": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The password hashed in the SHA-512 crypt format e.g. `$6$saltstring$svn8UoSVapNtMuq1ukKS4t...`.",
+			},
+		},
+	}
+}
+
+func datasourceHyperVPasswordHashRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv password hash")
+
+	password := api.GetSensitiveValue(d, "password")
+	salt := (d.Get("salt")).(string)
+	rounds := (d.Get("rounds")).(int)
+
+	sha512Crypt := api.Sha512Crypt(password, salt, rounds)
+
+	if err := d.Set("sha512_crypt", sha512Crypt); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(api.HashSensitiveValue(sha512Crypt))
+
+	log.Printf("[INFO][hyperv][read] read hyperv password hash")
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadPhysicalDisksTimeout = 1 * time.Minute
+)
+
+func dataSourceHyperVPhysicalDisks() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the physical disks of the HyperV host machine, so that the disk number used by `source_disk` of `hyperv_vhd` or by a pass-through hard disk drive can be selected by its properties.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadPhysicalDisksTimeout),
+		},
+		ReadContext: datasourceHyperVPhysicalDisksRead,
+		Schema: map[string]*schema.Schema{
+			"friendly_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "*",
+				Description: "Specifies the friendly name of the disks to return. Wildcards are supported e.g. `Samsung*`.",
+			},
+			"bus_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Only return disks attached to this type of bus e.g. `NVMe`, `SATA`, `SAS`, `iSCSI`, `USB`.",
+			},
+			"offline_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return disks that are offline. A disk must be offline to be used as a pass-through disk.",
+			},
+			"minimum_size_bytes": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     0,
+				Description: "Only return disks with a size of at least this many bytes.",
+			},
+			"numbers": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Computed:    true,
+				Description: "The numbers of the disks that matched the filters.",
+			},
+			"disks": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The disks that matched the filters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"number": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of the disk.",
+						},
+						"friendly_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The friendly name of the disk.",
+						},
+						"serial_number": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The serial number of the disk.",
+						},
+						"unique_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the disk.",
+						},
+						"size_bytes": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The size of the disk.",
+						},
+						"bus_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of bus the disk is attached to.",
+						},
+						"partition_style": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The partition style of the disk e.g. `GPT`, `MBR`, `RAW`.",
+						},
+						"operational_status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The operational status of the disk.",
+						},
+						"is_offline": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the disk is offline.",
+						},
+						"is_read_only": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the disk is read only.",
+						},
+						"is_boot": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the HyperV host machine boots from the disk.",
+						},
+						"is_system": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the disk contains the system partition of the HyperV host machine.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVPhysicalDisksRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv physical disks: %#v", d)
+	c := meta.(api.Client)
+
+	friendlyName := (d.Get("friendly_name")).(string)
+	busType := (d.Get("bus_type")).(string)
+	offlineOnly := (d.Get("offline_only")).(bool)
+	minimumSizeBytes := int64((d.Get("minimum_size_bytes")).(int))
+
+	disks, err := c.GetPhysicalDisks(ctx, friendlyName, offlineOnly)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved physical disks: %+v", disks)
+
+	filteredDisks := make([]api.Disk, 0)
+	numbers := make([]int, 0)
+	for _, disk := range disks {
+		if busType != "" && !strings.EqualFold(disk.BusType, busType) {
+			continue
+		}
+
+		if disk.SizeBytes < minimumSizeBytes {
+			continue
+		}
+
+		filteredDisks = append(filteredDisks, disk)
+		numbers = append(numbers, disk.Number)
+	}
+
+	if err := d.Set("numbers", numbers); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("disks", api.FlattenDisks(&filteredDisks)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%t/%d", friendlyName, strings.ToLower(busType), offlineOnly, minimumSizeBytes))
+
+	log.Printf("[INFO][hyperv][read] read hyperv physical disks: %#v", d)
+
+	return nil
+}
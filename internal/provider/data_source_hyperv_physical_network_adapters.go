@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadPhysicalNetworkAdaptersTimeout = 1 * time.Minute
+)
+
+func dataSourceHyperVPhysicalNetworkAdapters() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the physical network adapters of the HyperV host machine, so that the network adapters used by an external switch can be selected dynamically.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadPhysicalNetworkAdaptersTimeout),
+		},
+		ReadContext: datasourceHyperVPhysicalNetworkAdaptersRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "*",
+				Description: "Specifies the name of the network adapters to return. Wildcards are supported e.g. `Ethernet*`.",
+			},
+			"up_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only return network adapters that have a status of `Up`.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of the network adapters that matched the filters.",
+			},
+			"network_adapters": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The network adapters that matched the filters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the network adapter.",
+						},
+						"interface_description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The interface description of the network adapter.",
+						},
+						"interface_index": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The interface index of the network adapter.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the network adapter e.g. `Up`, `Disconnected`, `Disabled`.",
+						},
+						"mac_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The MAC address of the network adapter.",
+						},
+						"link_speed": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The link speed of the network adapter e.g. `10 Gbps`.",
+						},
+						"link_speed_bits_per_second": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The link speed of the network adapter in bits per second.",
+						},
+						"sriov_supported": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the network adapter supports SR-IOV.",
+						},
+						"rdma_capable": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the network adapter supports RDMA.",
+						},
+						"rdma_enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if RDMA is enabled on the network adapter.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVPhysicalNetworkAdaptersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv physical network adapters: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+	upOnly := (d.Get("up_only")).(bool)
+
+	netAdapters, err := c.GetPhysicalNetAdapters(ctx, name, upOnly)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved physical network adapters: %+v", netAdapters)
+
+	names := make([]string, 0)
+	for _, netAdapter := range netAdapters {
+		names = append(names, netAdapter.Name)
+	}
+
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("network_adapters", api.FlattenNetAdapters(&netAdapters)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%t", name, upOnly))
+
+	log.Printf("[INFO][hyperv][read] read hyperv physical network adapters: %#v", d)
+
+	return nil
+}
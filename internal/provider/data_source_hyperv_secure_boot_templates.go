@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVSecureBootTemplates() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the secure boot templates available on the HyperV host machine. The available templates vary across Windows versions, so this can be used to look up or validate the `secure_boot_template` of a machine instance instead of hardcoding it.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadHostSettingsTimeout),
+		},
+		ReadContext: datasourceHyperVSecureBootTemplatesRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the name of a secure boot template to look up e.g. `MicrosoftUEFICertificateAuthority`. The data source fails if the template is not available on the HyperV host machine. The match is case insensitive.",
+			},
+			"template_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the secure boot template specified by `name`.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of all secure boot templates available on the HyperV host machine.",
+			},
+			"templates": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "All secure boot templates available on the HyperV host machine.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the secure boot template.",
+						},
+						"template_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the secure boot template.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The description of the secure boot template.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVSecureBootTemplatesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv secure boot templates: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+
+	secureBootTemplates, err := c.GetSecureBootTemplates(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved secure boot templates: %+v", secureBootTemplates)
+
+	templateId := ""
+	names := make([]string, 0)
+	for _, secureBootTemplate := range secureBootTemplates {
+		names = append(names, secureBootTemplate.Name)
+		if name != "" && strings.EqualFold(secureBootTemplate.Name, name) {
+			templateId = secureBootTemplate.Id
+		}
+	}
+
+	if name != "" && templateId == "" {
+		return diag.Errorf("Secure boot template does not exist - %s. Available templates are: %s", name, strings.Join(names, ", "))
+	}
+
+	if err := d.Set("template_id", templateId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("templates", api.FlattenSecureBootTemplates(&secureBootTemplates)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if name != "" {
+		d.SetId(name)
+	} else {
+		d.SetId("secure_boot_templates")
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv secure boot templates: %#v", d)
+
+	return nil
+}
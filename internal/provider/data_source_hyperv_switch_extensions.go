@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVSwitchExtensions() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the extensions available on all existing virtual network switches, optionally filtered by switch, extension name or extension type. Can be used to drive `hyperv_vswitch_extension` resources from discovered data.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadVSwitchExtensionTimeout),
+		},
+		ReadContext: datasourceHyperVSwitchExtensionsRead,
+		Schema: map[string]*schema.Schema{
+			"switch_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return extensions available on the virtual network switch with this name.",
+			},
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: IsValidRegExp(),
+				Description:      "A regular expression that the name of an extension must match to be returned.",
+			},
+			"extension_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return extensions of this type e.g. `Capture`, `Filter`, `Forwarding`.",
+			},
+			"switch_names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of the switches that have at least one extension that matched the filters.",
+			},
+			"extensions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The extensions that matched the filters, one entry per switch and extension.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"switch_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the virtual network switch the extension is available on.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the switch extension.",
+						},
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the switch extension.",
+						},
+						"vendor": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vendor of the switch extension.",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The version of the switch extension.",
+						},
+						"extension_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the switch extension.",
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the switch extension is enabled.",
+						},
+						"running": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the switch extension is running.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVSwitchExtensionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv switch extensions: %#v", d)
+	c := meta.(api.Client)
+
+	switchName := (d.Get("switch_name")).(string)
+	nameRegex := (d.Get("name_regex")).(string)
+	extensionType := (d.Get("extension_type")).(string)
+
+	var nameRegexp *regexp.Regexp
+	if nameRegex != "" {
+		var err error
+		nameRegexp, err = regexp.Compile(nameRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var extensions []api.VmSwitchExtension
+	var err error
+	if switchName != "" {
+		extensions, err = c.GetVMSwitchExtensions(ctx, switchName)
+	} else {
+		extensions, err = c.GetAllVMSwitchExtensions(ctx)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved switch extensions: %+v", extensions)
+
+	filteredExtensions := make([]api.VmSwitchExtension, 0)
+	switchNames := make([]string, 0)
+	for _, extension := range extensions {
+		if nameRegexp != nil && !nameRegexp.MatchString(extension.Name) {
+			continue
+		}
+
+		if extensionType != "" && !strings.EqualFold(extension.ExtensionType, extensionType) {
+			continue
+		}
+
+		filteredExtensions = append(filteredExtensions, extension)
+		if len(switchNames) < 1 || switchNames[len(switchNames)-1] != extension.SwitchName {
+			switchNames = append(switchNames, extension.SwitchName)
+		}
+	}
+
+	if err := d.Set("switch_names", switchNames); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("extensions", api.FlattenVmSwitchExtensions(&filteredExtensions)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", switchName, strings.ToLower(extensionType), nameRegex))
+
+	log.Printf("[INFO][hyperv][read] read hyperv switch extensions: %#v", d)
+
+	return nil
+}
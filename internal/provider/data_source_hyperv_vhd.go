@@ -18,9 +18,10 @@ func dataSourceHyperVVhd() *schema.Resource {
 		ReadContext: datasourceHyperVVhdRead,
 		Schema: map[string]*schema.Schema{
 			"path": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Path to the existing virtual hard disk file(s) that is being created or being copied to. If a filename or relative path is specified, the virtual hard disk path is calculated relative to the current working directory. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "Path to the existing virtual hard disk file(s) that is being created or being copied to. The path must be absolute, either on a drive e.g. `C:\\VMs\\web.vhdx` or on a share e.g. `\\\\server\\share\\web.vhdx`. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
 			},
 			"source": {
 				Type:     schema.TypeString,
@@ -123,6 +124,16 @@ func dataSourceHyperVVhd() *schema.Resource {
 				Computed:    true,
 				Description: "",
 			},
+			"healthy": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the virtual hard disk passes validation with `Test-VHD`, which for a differencing disk includes that all of its parents exist and match it.",
+			},
+			"health_error_message": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Why the virtual hard disk failed validation when it is not `healthy`.",
+			},
 		},
 	}
 }
@@ -162,6 +173,14 @@ func datasourceHyperVVhdRead(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
+	if err := d.Set("healthy", vhd.Healthy); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("health_error_message", vhd.HealthErrorMessage); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(path)
 
 	log.Printf("[INFO][hyperv][read] read hyperv vhd: %#v", d)
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVVhdParentChain() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the chain of parent disks of a differencing virtual hard disk, including whether any of them are missing or fail validation (`Test-VHD`), so that differencing chains can be validated before they are used.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadVhdTimeout),
+		},
+		ReadContext: datasourceHyperVVhdParentChainRead,
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "Path to the existing virtual hard disk file to start the chain from.",
+			},
+			"parent_paths": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The paths of the parent disks, ordered from the immediate parent to the base disk.",
+			},
+			"base_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path of the last disk in the chain. This is `path` when the disk is not a differencing disk.",
+			},
+			"missing_paths": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The paths of the disks in the chain that do not exist.",
+			},
+			"valid": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies if every disk in the chain exists and passes validation. A differencing disk whose parent has been modified or replaced fails validation.",
+			},
+			"chain": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The disks in the chain, starting with the disk at `path` and ending with the base disk.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The path of the virtual hard disk.",
+						},
+						"parent_path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The path of the parent of the virtual hard disk. Empty when the disk is not a differencing disk.",
+						},
+						"vhd_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the virtual hard disk.",
+						},
+						"vhd_format": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The format of the virtual hard disk.",
+						},
+						"exists": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the virtual hard disk exists.",
+						},
+						"valid": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the virtual hard disk passes validation.",
+						},
+						"error_message": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The reason the virtual hard disk is missing or fails validation.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVVhdParentChainRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vhd parent chain: %#v", d)
+	c := meta.(api.Client)
+
+	path := (d.Get("path")).(string)
+
+	vhdChain, err := c.GetVhdParentChain(ctx, path)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vhd parent chain: %+v", vhdChain)
+
+	parentPaths := make([]string, 0)
+	missingPaths := make([]string, 0)
+	basePath := path
+	valid := len(vhdChain) > 0
+	for i, vhdChainEntry := range vhdChain {
+		if i > 0 {
+			parentPaths = append(parentPaths, vhdChainEntry.Path)
+		}
+		if !vhdChainEntry.Exists {
+			missingPaths = append(missingPaths, vhdChainEntry.Path)
+		}
+		if !vhdChainEntry.Exists || !vhdChainEntry.Valid {
+			valid = false
+		}
+		basePath = vhdChainEntry.Path
+	}
+
+	if err := d.Set("parent_paths", parentPaths); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("base_path", basePath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("missing_paths", missingPaths); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("valid", valid); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("chain", api.FlattenVhdChainEntries(&vhdChain)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(path)
+
+	log.Printf("[INFO][hyperv][read] read hyperv vhd parent chain: %#v", d)
+
+	return nil
+}
@@ -5,7 +5,6 @@ package provider
 
 import (
 	"fmt"
-	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -17,9 +16,7 @@ func TestHyperVDataSourceVhd(t *testing.T) {
 		t.Skip("skipping test in short mode.")
 	}
 
-	//tempDirectory := os.TempDir() uses short name ;<
-	tempDirectory, _ := filepath.Abs(".")
-	path, _ := filepath.Abs(filepath.Join(tempDirectory, fmt.Sprintf("testhypervdatasourcevhd_%d.vhdx", randInt())))
+	path := testAccPath("testhypervdatasourcevhd.vhdx")
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVVm() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about an existing virtual machine, so that virtual machines that are not managed by Terraform can be referenced.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadMachineInstanceTimeout),
+		},
+		ReadContext: datasourceHyperVVmRead,
+		Schema: dataSourceHyperVVmSchema(map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "vm_id"},
+				Description:  "Specifies the name of the virtual machine.",
+			},
+			"vm_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "vm_id"},
+				Description:  "Specifies the unique identifier (GUID) of the virtual machine.",
+			},
+		}),
+	}
+}
+
+// dataSourceHyperVVmSchema adds the computed attributes of a virtual machine to the given schema, so that they are the same for a single virtual machine and a list of virtual machines.
+func dataSourceHyperVVmSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for k, v := range map[string]*schema.Schema{
+		"generation": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The generation of the virtual machine.",
+		},
+		"version": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The configuration version of the virtual machine.",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The current state of the virtual machine e.g. `Running`, `Off`, `Saved`, `Paused`.",
+		},
+		"path": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The path of the virtual machine.",
+		},
+		"notes": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The note associated with the virtual machine.",
+		},
+		"processor_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The number of virtual processors of the virtual machine.",
+		},
+		"dynamic_memory": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "Specifies if the virtual machine has dynamic memory enabled.",
+		},
+		"memory_startup_bytes": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The amount of memory the virtual machine is allocated upon startup.",
+		},
+		"memory_assigned_bytes": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The amount of memory currently assigned to the virtual machine.",
+		},
+		"uptime_seconds": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The number of seconds the virtual machine has been running for.",
+		},
+		"heartbeat": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The status of the heartbeat integration service of the virtual machine e.g. `OkApplicationsHealthy`, `NoContact`.",
+		},
+		"network_adapters": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The network adapters of the virtual machine.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The name of the network adapter.",
+					},
+					"switch_name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The name of the switch the network adapter is connected to.",
+					},
+					"mac_address": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The MAC address of the network adapter.",
+					},
+					"ip_addresses": {
+						Type:        schema.TypeList,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+						Computed:    true,
+						Description: "The IP addresses reported by the guest for the network adapter.",
+					},
+				},
+			},
+		},
+		"hard_disk_drive_paths": {
+			Type:        schema.TypeList,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Computed:    true,
+			Description: "The paths of the virtual hard disks attached to the virtual machine.",
+		},
+		"integration_services": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The integration services of the virtual machine.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The name of the integration service.",
+					},
+					"enabled": {
+						Type:        schema.TypeBool,
+						Computed:    true,
+						Description: "Specifies if the integration service is enabled.",
+					},
+					"status": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The status of the integration service e.g. `OK`, `No Contact`.",
+					},
+				},
+			},
+		},
+	} {
+		s[k] = v
+	}
+
+	return s
+}
+
+func datasourceHyperVVmRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+	vmId := (d.Get("vm_id")).(string)
+
+	vm, err := c.GetVmInfo(ctx, name, vmId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm: %+v", vm)
+
+	if vm.Id == "" {
+		if vmId != "" {
+			return diag.Errorf("[ERROR][hyperv][read] VM does not exist - %s", vmId)
+		}
+		return diag.Errorf("[ERROR][hyperv][read] VM does not exist - %s", name)
+	}
+
+	if err := d.Set("name", vm.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vm_id", vm.Id); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("generation", vm.Generation); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("version", vm.Version); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("state", vm.State.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("path", vm.Path); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("notes", vm.Notes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("processor_count", vm.ProcessorCount); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("dynamic_memory", vm.DynamicMemory); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_startup_bytes", vm.MemoryStartupBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_assigned_bytes", vm.MemoryAssignedBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("uptime_seconds", vm.UptimeSeconds); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("heartbeat", vm.Heartbeat); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("network_adapters", api.FlattenVmInfoNetworkAdapters(vm.NetworkAdapters)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("hard_disk_drive_paths", vm.HardDiskDrivePaths); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("integration_services", api.FlattenVmInfoIntegrationServices(vm.IntegrationServices)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vm.Id)
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm: %#v", d)
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmCheckpointsTimeout = 1 * time.Minute
+)
+
+func dataSourceHyperVVmCheckpoints() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the checkpoints of a virtual machine, e.g. to reference the latest checkpoint for a restore or a cleanup policy.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadVmCheckpointsTimeout),
+		},
+		ReadContext: datasourceHyperVVmCheckpointsRead,
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the virtual machine.",
+			},
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: IsValidRegExp(),
+				Description:      "A regular expression that the name of a checkpoint must match to be returned.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of the checkpoints that matched the filters, oldest first.",
+			},
+			"latest_checkpoint_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the most recently created checkpoint that matched the filters.",
+			},
+			"latest_checkpoint_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the most recently created checkpoint that matched the filters.",
+			},
+			"checkpoints": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The checkpoints that matched the filters, oldest first.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the checkpoint.",
+						},
+						"checkpoint_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the checkpoint.",
+						},
+						"checkpoint_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the checkpoint e.g. `Standard`, `Recovery`, `Replica`.",
+						},
+						"creation_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time the checkpoint was created, in RFC3339 format.",
+						},
+						"parent_checkpoint_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the parent checkpoint. Empty for a root checkpoint.",
+						},
+						"parent_checkpoint_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the parent checkpoint. Empty for a root checkpoint.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVVmCheckpointsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm checkpoints: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	nameRegex := (d.Get("name_regex")).(string)
+
+	var nameRegexp *regexp.Regexp
+	if nameRegex != "" {
+		var err error
+		nameRegexp, err = regexp.Compile(nameRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	vmCheckpoints, err := c.GetVmCheckpoints(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm checkpoints: %+v", vmCheckpoints)
+
+	filteredVmCheckpoints := make([]api.VmCheckpoint, 0)
+	names := make([]string, 0)
+	for _, vmCheckpoint := range vmCheckpoints {
+		if nameRegexp != nil && !nameRegexp.MatchString(vmCheckpoint.Name) {
+			continue
+		}
+
+		filteredVmCheckpoints = append(filteredVmCheckpoints, vmCheckpoint)
+		names = append(names, vmCheckpoint.Name)
+	}
+
+	latestCheckpointName := ""
+	latestCheckpointId := ""
+	if len(filteredVmCheckpoints) > 0 {
+		latestCheckpointName = filteredVmCheckpoints[len(filteredVmCheckpoints)-1].Name
+		latestCheckpointId = filteredVmCheckpoints[len(filteredVmCheckpoints)-1].Id
+	}
+
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("latest_checkpoint_name", latestCheckpointName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("latest_checkpoint_id", latestCheckpointId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("checkpoints", api.FlattenVmCheckpoints(&filteredVmCheckpoints)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", vmName, nameRegex))
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm checkpoints: %#v", d)
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmConsoleScreenshotTimeout = 1 * time.Minute
+)
+
+func dataSourceHyperVVmConsoleScreenshot() *schema.Resource {
+	return &schema.Resource{
+		Description: "Capture the console screen of a running virtual machine as a png image, e.g. to debug boot failures from CI where there is no access to the HyperV manager. A new screenshot is captured every time the data source is read.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadVmConsoleScreenshotTimeout),
+		},
+		ReadContext: datasourceHyperVVmConsoleScreenshotRead,
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the virtual machine.",
+			},
+			"width": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				RequiredWith:     []string{"height"},
+				ValidateDiagFunc: IntBetween(1, 7680),
+				Description:      "Specifies the width of the screenshot in pixels, the screen is scaled to fit. Defaults to the horizontal resolution the guest is using.",
+			},
+			"height": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				RequiredWith:     []string{"width"},
+				ValidateDiagFunc: IntBetween(1, 4320),
+				Description:      "Specifies the height of the screenshot in pixels, the screen is scaled to fit. Defaults to the vertical resolution the guest is using.",
+			},
+			"png_base64": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The screenshot as a base64 encoded png image. It can be written to a file with the `content_base64` argument of the `local_file` resource.",
+			},
+		},
+	}
+}
+
+func datasourceHyperVVmConsoleScreenshotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm console screenshot: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	width := (d.Get("width")).(int)
+	height := (d.Get("height")).(int)
+
+	vmConsoleScreenshot, err := c.GetVmConsoleScreenshot(ctx, vmName, width, height)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm console screenshot of %dx%d", vmConsoleScreenshot.Width, vmConsoleScreenshot.Height)
+
+	if err := d.Set("width", vmConsoleScreenshot.Width); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("height", vmConsoleScreenshot.Height); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("png_base64", vmConsoleScreenshot.Png); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmConsoleScreenshot.VmName)
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm console screenshot: %s", vmName)
+
+	return nil
+}
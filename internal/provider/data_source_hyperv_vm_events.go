@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmEventsTimeout = 2 * time.Minute
+)
+
+func dataSourceHyperVVmEvents() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get the recent events the HyperV host machine logged about virtual machines, e.g. a worker process that crashed or a virtual machine that failed to start, to verify the health of virtual machines after an apply. Only events of virtual machines that still exist on the host are returned. The events are reported as warnings of the plan or apply unless `warn` is `false`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadVmEventsTimeout),
+		},
+		ReadContext: datasourceHyperVVmEventsRead,
+		Schema: map[string]*schema.Schema{
+			"vm_names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Specifies the names of the virtual machines to get the events of. When empty the events of all virtual machines are returned.",
+			},
+			"log_names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: fmt.Sprintf("Specifies the event logs to query. When empty `%s` are queried.", strings.Join(api.DefaultVmEventLogNames, "` and `")),
+			},
+			"level": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VmEventLevel_name[api.VmEventLevel_Error],
+				ValidateDiagFunc: stringKeyInMap(api.VmEventLevel_value, true),
+				Description:      "Specifies the least severe level of the events to return. Valid values to use are `Critical`, `Error`, `Warning` and `Information`.",
+			},
+			"since_minutes": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          60,
+				ValidateDiagFunc: IntBetween(1, 43200),
+				Description:      "Specifies how many minutes back in time events are returned.",
+			},
+			"max_events": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          100,
+				ValidateDiagFunc: IntBetween(1, 1000),
+				Description:      "Specifies how many of the newest events are returned at most.",
+			},
+			"warn": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether each event is reported as a warning of the plan or apply.",
+			},
+			"events": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The events that matched the filters, newest first.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vm_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the virtual machine the event is about.",
+						},
+						"vm_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the virtual machine the event is about.",
+						},
+						"log_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the event log the event was logged in.",
+						},
+						"provider_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the provider that logged the event.",
+						},
+						"event_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The id of the event e.g. `18590` when a virtual machine crashed.",
+						},
+						"level": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The level of the event e.g. `Critical` or `Error`.",
+						},
+						"time_created": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time the event was logged, in RFC3339 format.",
+						},
+						"message": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The message of the event.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVVmEventsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm events: %#v", d)
+	c := meta.(api.Client)
+
+	vmNames := make([]string, 0)
+	for _, vmName := range (d.Get("vm_names")).([]interface{}) {
+		vmNames = append(vmNames, vmName.(string))
+	}
+
+	logNames := make([]string, 0)
+	for _, logName := range (d.Get("log_names")).([]interface{}) {
+		logNames = append(logNames, logName.(string))
+	}
+
+	query := api.VmEventQuery{
+		VmNames:      vmNames,
+		LogNames:     logNames,
+		Level:        api.ToVmEventLevel((d.Get("level")).(string)),
+		SinceMinutes: int32((d.Get("since_minutes")).(int)),
+		MaxEvents:    int32((d.Get("max_events")).(int)),
+	}
+
+	vmEvents, err := c.GetVmEvents(ctx, query)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm events: %+v", vmEvents)
+
+	if err := d.Set("events", api.FlattenVmEvents(&vmEvents)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", strings.Join(vmNames, ","), query.Level.String(), strings.Join(logNames, ",")))
+
+	var diags diag.Diagnostics
+	if (d.Get("warn")).(bool) {
+		for _, vmEvent := range vmEvents {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("vm %s has a recent %s event %d", vmEvent.VmName, strings.ToLower(vmEvent.Level.String()), vmEvent.EventId),
+				Detail:   fmt.Sprintf("Vm: %s\nLog: %s\nProvider: %s\nTime: %s\nMessage: %s", vmEvent.VmName, vmEvent.LogName, vmEvent.ProviderName, vmEvent.TimeCreated, vmEvent.Message),
+			})
+		}
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm events: %#v", d)
+
+	return diags
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVVmGenerationSupport() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get the generations, secure boot templates, guest state isolation types and configuration versions of virtual machines the HyperV host machine supports, so a module can adapt to the Windows Server version of the host, e.g. use the highest configuration version on a Windows Server 2025 host while still working on a Windows Server 2016 host.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadHostSettingsTimeout),
+		},
+		ReadContext: datasourceHyperVVmGenerationSupportRead,
+		Schema: map[string]*schema.Schema{
+			"generations": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Computed:    true,
+				Description: "The generations of virtual machines the HyperV host machine can create e.g. `[1, 2]`.",
+			},
+			"secure_boot_templates": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of the secure boot templates available to generation 2 virtual machines e.g. `MicrosoftWindows` and `MicrosoftUEFICertificateAuthority`.",
+			},
+			"guest_state_isolation_types": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The guest state isolation types generation 2 virtual machines can be created with e.g. `TrustedLaunch`, which is empty before Windows Server 2025.",
+			},
+			"supported_vm_versions": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The configuration versions of virtual machines the HyperV host machine supports e.g. `9.0`.",
+			},
+			"default_vm_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The configuration version new virtual machines are created with by default.",
+			},
+			"maximum_vm_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The highest configuration version of virtual machines the HyperV host machine supports e.g. `9.0` on Windows Server 2019, `10.0` on Windows Server 2022 and `12.0` on Windows Server 2025.",
+			},
+		},
+	}
+}
+
+func datasourceHyperVVmGenerationSupportRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm generation support: %#v", d)
+	c := meta.(api.Client)
+
+	hostCapabilities, err := c.GetHostCapabilities(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host capabilities: %+v", hostCapabilities)
+
+	if err := d.Set("generations", hostCapabilities.SupportedGenerations()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("secure_boot_templates", hostCapabilities.SecureBootTemplates); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("guest_state_isolation_types", hostCapabilities.GuestStateIsolationTypes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("supported_vm_versions", hostCapabilities.SupportedVmVersions); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("default_vm_version", hostCapabilities.DefaultVmVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("maximum_vm_version", hostCapabilities.MaximumVmVersion()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hostCapabilities.ComputerName)
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm generation support: %#v", d)
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmNetworkAdapterTimeout = 10 * time.Minute
+)
+
+func dataSourceHyperVVmNetworkAdapter() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about a network adapter of a virtual machine, such as the IP addresses reported by the guest, e.g. to register the virtual machine with DNS or a load balancer.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadVmNetworkAdapterTimeout),
+		},
+		ReadContext: datasourceHyperVVmNetworkAdapterRead,
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the virtual machine.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the network adapter.",
+			},
+			"wait_for_ip": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether to wait until the guest reports an IP address for the network adapter. IPv6 link local addresses do not count, so an IPv6 only guest is waited for until it has an address it can be reached on. Only waits while the virtual machine is running.",
+			},
+			"wait_for_ip_timeout": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          300,
+				ValidateDiagFunc: IntBetween(1, 3600),
+				Description:      "The maximum number of seconds to wait for an IP address when `wait_for_ip` is `true`.",
+			},
+			"wait_for_ip_poll_period": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          2,
+				ValidateDiagFunc: IntBetween(1, 60),
+				Description:      "The number of seconds to wait between checks for an IP address when `wait_for_ip` is `true`.",
+			},
+			"mac_address": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The MAC address of the network adapter.",
+			},
+			"dynamic_mac_address": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies if the MAC address of the network adapter is dynamically assigned.",
+			},
+			"switch_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the switch the network adapter is connected to.",
+			},
+			"vlan_access": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies if the network adapter is in VLAN access mode.",
+			},
+			"vlan_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The VLAN id of the network adapter when it is in VLAN access mode.",
+			},
+			"ip_addresses": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The IP addresses reported by the guest for the network adapter.",
+			},
+			"ipv4_addresses": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The IPv4 addresses reported by the guest for the network adapter.",
+			},
+			"ipv6_addresses": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The IPv6 addresses reported by the guest for the network adapter, without the link local addresses that can not be used to reach the guest from other networks.",
+			},
+		},
+	}
+}
+
+func datasourceHyperVVmNetworkAdapterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm network adapter: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	name := (d.Get("name")).(string)
+	waitForIp := (d.Get("wait_for_ip")).(bool)
+	waitForIpTimeout := uint32((d.Get("wait_for_ip_timeout")).(int))
+	waitForIpPollPeriod := uint32((d.Get("wait_for_ip_poll_period")).(int))
+
+	if waitForIp {
+		err := c.WaitForVmNetworkAdaptersIps(ctx, vmName, waitForIpTimeout, waitForIpPollPeriod, []api.VmNetworkAdapterWaitForIp{
+			{
+				Name:       name,
+				WaitForIps: true,
+			},
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	networkAdapters, err := c.GetVmNetworkAdapters(ctx, vmName, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm network adapters: %+v", networkAdapters)
+
+	var networkAdapter *api.VmNetworkAdapter
+	for i := range networkAdapters {
+		if strings.EqualFold(networkAdapters[i].Name, name) {
+			networkAdapter = &networkAdapters[i]
+			break
+		}
+	}
+
+	if networkAdapter == nil {
+		return diag.Errorf("[ERROR][hyperv][read] Network adapter does not exist - %s on %s", name, vmName)
+	}
+
+	if err := d.Set("mac_address", networkAdapter.StaticMacAddress); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("dynamic_mac_address", networkAdapter.DynamicMacAddress); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switch_name", networkAdapter.SwitchName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vlan_access", networkAdapter.VlanAccess); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vlan_id", networkAdapter.VlanId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("ip_addresses", networkAdapter.IpAddresses); err != nil {
+		return diag.FromErr(err)
+	}
+	ipv4Addresses, ipv6Addresses := api.SplitIpAddresses(networkAdapter.IpAddresses)
+	if err := d.Set("ipv4_addresses", ipv4Addresses); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("ipv6_addresses", ipv6Addresses); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", vmName, name))
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm network adapter: %#v", d)
+
+	return nil
+}
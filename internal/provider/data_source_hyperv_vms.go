@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVVms() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the existing virtual machines that match the filters, so that they can be used with `count` or `for_each`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadMachineInstanceTimeout),
+		},
+		ReadContext: datasourceHyperVVmsRead,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: IsValidRegExp(),
+				Description:      "A regular expression that the name of a virtual machine must match to be returned.",
+			},
+			"state": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringKeyInMap(api.VmState_value, true),
+				Description:      "Only return virtual machines in this state e.g. `Running`, `Off`, `Saved`, `Paused`.",
+			},
+			"notes_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: IsValidRegExp(),
+				Description:      "A regular expression that the notes of a virtual machine must match to be returned, e.g. to select virtual machines by a tag kept in the notes.",
+			},
+			"switch_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return virtual machines with a network adapter connected to this switch.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of the virtual machines that matched the filters.",
+			},
+			"vms": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The virtual machines that matched the filters.",
+				Elem: &schema.Resource{
+					Schema: dataSourceHyperVVmSchema(map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the virtual machine.",
+						},
+						"vm_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier (GUID) of the virtual machine.",
+						},
+					}),
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVVmsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vms: %#v", d)
+	c := meta.(api.Client)
+
+	nameRegex := (d.Get("name_regex")).(string)
+	state := (d.Get("state")).(string)
+	notesRegex := (d.Get("notes_regex")).(string)
+	switchName := (d.Get("switch_name")).(string)
+
+	var nameRegexp *regexp.Regexp
+	if nameRegex != "" {
+		var err error
+		nameRegexp, err = regexp.Compile(nameRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var notesRegexp *regexp.Regexp
+	if notesRegex != "" {
+		var err error
+		notesRegexp, err = regexp.Compile(notesRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	vms, err := c.GetVmInfos(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vms: %+v", vms)
+
+	filteredVms := make([]api.VmInfo, 0)
+	names := make([]string, 0)
+	for _, vm := range vms {
+		if nameRegexp != nil && !nameRegexp.MatchString(vm.Name) {
+			continue
+		}
+
+		if state != "" && vm.State != api.ToVmState(state) {
+			continue
+		}
+
+		if notesRegexp != nil && !notesRegexp.MatchString(vm.Notes) {
+			continue
+		}
+
+		if switchName != "" && !vmInfoConnectedToSwitch(vm, switchName) {
+			continue
+		}
+
+		filteredVms = append(filteredVms, vm)
+		names = append(names, vm.Name)
+	}
+
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vms", api.FlattenVmInfos(&filteredVms)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", strings.ToLower(state), strings.ToLower(switchName), nameRegex, notesRegex))
+
+	log.Printf("[INFO][hyperv][read] read hyperv vms: %#v", d)
+
+	return nil
+}
+
+func vmInfoConnectedToSwitch(vm api.VmInfo, switchName string) bool {
+	for _, networkAdapter := range vm.NetworkAdapters {
+		if strings.EqualFold(networkAdapter.SwitchName, switchName) {
+			return true
+		}
+	}
+
+	return false
+}
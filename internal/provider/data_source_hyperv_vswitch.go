@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVVSwitch() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about an existing virtual network switch, so that virtual machines can be connected to switches that are not managed by Terraform.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadNetworkSwitchTimeout),
+		},
+		ReadContext: datasourceHyperVVSwitchRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the switch.",
+			},
+			"switch_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the switch.",
+			},
+			"notes": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The note associated with the switch.",
+			},
+			"switch_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the switch. Valid values are `Internal`, `Private` and `External`.",
+			},
+			"allow_management_os": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies if the HyperV host machine has access to the switch.",
+			},
+			"net_adapter_names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of the network adapters the switch is bound to.",
+			},
+			"enable_embedded_teaming": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies if the switch is a switch embedded teaming (SET) switch.",
+			},
+			"enable_iov": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies if IO virtualization is enabled on the switch.",
+			},
+			"minimum_bandwidth_mode": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Specifies how minimum bandwidth is configured on the switch. Valid values are `Absolute`, `Default`, `None`, `Weight`.",
+			},
+		},
+	}
+}
+
+func datasourceHyperVVSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vswitch: %#v", d)
+	c := meta.(api.Client)
+
+	switchName := ""
+
+	if v, ok := d.GetOk("name"); ok {
+		switchName = v.(string)
+	} else {
+		return diag.Errorf("[ERROR][hyperv][read] name argument is required")
+	}
+
+	s, err := c.GetVMSwitch(ctx, switchName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vswitch: %+v", s)
+
+	if !strings.EqualFold(s.Name, switchName) {
+		return diag.Errorf("[ERROR][hyperv][read] Switch does not exist - %s", switchName)
+	}
+
+	if err := d.Set("name", s.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switch_id", s.Id); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("notes", s.Notes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switch_type", s.SwitchType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("allow_management_os", s.AllowManagementOS); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("net_adapter_names", s.NetAdapterNames); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enable_embedded_teaming", s.EmbeddedTeamingEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enable_iov", s.IovEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("minimum_bandwidth_mode", s.BandwidthReservationMode.String()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(s.Name)
+
+	log.Printf("[INFO][hyperv][read] read hyperv vswitch: %#v", d)
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVVSwitchExtensions() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about the extensions available on an existing virtual network switch.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadVSwitchExtensionTimeout),
+		},
+		ReadContext: datasourceHyperVVSwitchExtensionsRead,
+		Schema: map[string]*schema.Schema{
+			"switch_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the virtual network switch.",
+			},
+			"extensions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The extensions available on the virtual network switch.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"switch_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the virtual network switch the extension is available on.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the switch extension.",
+						},
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the switch extension.",
+						},
+						"vendor": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The vendor of the switch extension.",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The version of the switch extension.",
+						},
+						"extension_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the switch extension.",
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the switch extension is enabled.",
+						},
+						"running": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the switch extension is running.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVVSwitchExtensionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv switch extensions: %#v", d)
+	c := meta.(api.Client)
+
+	switchName := ""
+
+	if v, ok := d.GetOk("switch_name"); ok {
+		switchName = v.(string)
+	} else {
+		return diag.Errorf("[ERROR][hyperv][read] switch_name argument is required")
+	}
+
+	extensions, err := c.GetVMSwitchExtensions(ctx, switchName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved switch extensions: %+v", extensions)
+
+	if err := d.Set("extensions", api.FlattenVmSwitchExtensions(&extensions)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(switchName)
+
+	log.Printf("[INFO][hyperv][read] read hyperv switch extensions: %#v", d)
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func dataSourceHyperVVSwitches() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get information about all existing virtual network switches, optionally filtered by name or switch type.",
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(ReadNetworkSwitchTimeout),
+		},
+		ReadContext: datasourceHyperVVSwitchesRead,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: IsValidRegExp(),
+				Description:      "A regular expression that the name of a switch must match to be returned.",
+			},
+			"switch_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringKeyInMap(api.VMSwitchType_value, true),
+				Description:      "Only return switches of this type. Valid values to use are `Internal`, `Private` and `External`.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+				Description: "The names of the switches that matched the filters.",
+			},
+			"switches": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The switches that matched the filters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the switch.",
+						},
+						"switch_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unique identifier of the switch.",
+						},
+						"notes": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The note associated with the switch.",
+						},
+						"switch_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the switch.",
+						},
+						"allow_management_os": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the HyperV host machine has access to the switch.",
+						},
+						"net_adapter_names": {
+							Type:        schema.TypeList,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Computed:    true,
+							Description: "The names of the network adapters the switch is bound to.",
+						},
+						"enable_embedded_teaming": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if the switch is a switch embedded teaming (SET) switch.",
+						},
+						"enable_iov": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Specifies if IO virtualization is enabled on the switch.",
+						},
+						"minimum_bandwidth_mode": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Specifies how minimum bandwidth is configured on the switch.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func datasourceHyperVVSwitchesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vswitches: %#v", d)
+	c := meta.(api.Client)
+
+	nameRegex := (d.Get("name_regex")).(string)
+	switchType := (d.Get("switch_type")).(string)
+
+	var nameRegexp *regexp.Regexp
+	if nameRegex != "" {
+		var err error
+		nameRegexp, err = regexp.Compile(nameRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	vmSwitches, err := c.GetVMSwitches(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vswitches: %+v", vmSwitches)
+
+	filteredVmSwitches := make([]api.VmSwitch, 0)
+	names := make([]string, 0)
+	for _, vmSwitch := range vmSwitches {
+		if nameRegexp != nil && !nameRegexp.MatchString(vmSwitch.Name) {
+			continue
+		}
+
+		if switchType != "" && vmSwitch.SwitchType != api.ToVMSwitchType(switchType) {
+			continue
+		}
+
+		filteredVmSwitches = append(filteredVmSwitches, vmSwitch)
+		names = append(names, vmSwitch.Name)
+	}
+
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switches", api.FlattenVmSwitches(&filteredVmSwitches)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", strings.ToLower(switchType), nameRegex))
+
+	log.Printf("[INFO][hyperv][read] read hyperv vswitches: %#v", d)
+
+	return nil
+}
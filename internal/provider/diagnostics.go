@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withOperationDiagnostics wraps the functions of a resource or data source, so that its errors name the resource
+// and the operation that failed. Errors of failed scripts span multiple lines, so only their first line is kept as
+// the summary and the error record becomes the detail.
+func withOperationDiagnostics(resourceType string, resource *schema.Resource) {
+	resource.CreateContext = operationDiagnostics(resourceType, "create", resource.CreateContext)
+	resource.ReadContext = operationDiagnostics(resourceType, "read", resource.ReadContext)
+	resource.UpdateContext = operationDiagnostics(resourceType, "update", resource.UpdateContext)
+	resource.DeleteContext = operationDiagnostics(resourceType, "delete", resource.DeleteContext)
+}
+
+func operationDiagnostics(resourceType string, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		diags := f(ctx, d, meta)
+
+		for i := range diags {
+			if diags[i].Severity != diag.Error {
+				continue
+			}
+
+			summary, detail, _ := strings.Cut(diags[i].Summary, "\n")
+			if diags[i].Detail != "" {
+				detail = strings.TrimSpace(detail + "\n" + diags[i].Detail)
+			}
+
+			diags[i].Summary = summary
+			diags[i].Detail = strings.TrimSpace(fmt.Sprintf("Resource: %s\nId: %s\nOperation: %s\n\n%s", resourceType, d.Id(), operation, detail))
+		}
+
+		return diags
+	}
+}
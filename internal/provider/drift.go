@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+// providerMeta is passed to every resource and data source. It embeds the client, so resources keep using
+// meta.(api.Client).
+type providerMeta struct {
+	api.Client
+	DriftDetection bool
+	Features       providerFeatures
+}
+
+// withDriftDetection wraps the read of a resource, so that when drift detection is enabled every attribute managed
+// by Terraform that was changed outside of Terraform is reported as a warning.
+func withDriftDetection(resourceType string, resource *schema.Resource) {
+	read := resource.ReadContext
+	if read == nil {
+		return
+	}
+
+	resource.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		providerMeta, ok := meta.(*providerMeta)
+		if !ok || !providerMeta.DriftDetection || d.Id() == "" {
+			return read(ctx, d, meta)
+		}
+
+		id := d.Id()
+		before := d.State()
+
+		diags := read(ctx, d, meta)
+		if diags.HasError() {
+			return diags
+		}
+
+		return append(diags, driftDiagnostics(resourceType, resource.Schema, id, before, d.State())...)
+	}
+}
+
+func driftDiagnostics(resourceType string, schemaMap map[string]*schema.Schema, id string, before *terraform.InstanceState, after *terraform.InstanceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// a resource that is being imported only has its id in the state, so there is nothing to compare yet
+	if before == nil || len(before.Attributes) <= 1 {
+		return diags
+	}
+
+	if after == nil {
+		log.Printf("[WARN][hyperv][drift] %s %s was deleted outside of Terraform", resourceType, id)
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s %s was deleted outside of Terraform", resourceType, id),
+			Detail:   fmt.Sprintf("Resource: %s\nId: %s", resourceType, id),
+		})
+	}
+
+	keys := make([]string, 0)
+	for key := range before.Attributes {
+		keys = append(keys, key)
+	}
+	for key := range after.Attributes {
+		if _, ok := before.Attributes[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		stateValue, inState := before.Attributes[key]
+		actualValue, inActual := after.Attributes[key]
+		if stateValue == actualValue && inState == inActual {
+			continue
+		}
+
+		if !isManagedAttribute(schemaMap, key) {
+			continue
+		}
+
+		log.Printf("[WARN][hyperv][drift] %s %s attribute %s changed outside of Terraform from %q to %q", resourceType, id, key, stateValue, actualValue)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s %s attribute %s was changed outside of Terraform", resourceType, id, key),
+			Detail:   fmt.Sprintf("Resource: %s\nId: %s\nAttribute: %s\nState: %q\nActual: %q", resourceType, id, key, stateValue, actualValue),
+		})
+	}
+
+	return diags
+}
+
+// isManagedAttribute returns whether the flattened attribute key, e.g. network_adaptors.0.name, is set by the
+// configuration rather than only computed by the provider
+func isManagedAttribute(schemaMap map[string]*schema.Schema, key string) bool {
+	if key == "id" {
+		return false
+	}
+
+	parts := strings.Split(key, ".")
+	for i := 0; i < len(parts); i++ {
+		attributeSchema, ok := schemaMap[parts[i]]
+		if !ok {
+			return false
+		}
+
+		if attributeSchema.Computed && !attributeSchema.Optional && !attributeSchema.Required {
+			return false
+		}
+
+		resource, ok := attributeSchema.Elem.(*schema.Resource)
+		if !ok {
+			return true
+		}
+
+		// skip the index of the block, or stop at the count of blocks
+		if i+1 < len(parts) {
+			if _, err := strconv.Atoi(parts[i+1]); err == nil {
+				i++
+			} else {
+				return true
+			}
+		}
+
+		schemaMap = resource.Schema
+	}
+
+	return true
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// providerFeatures are the opt-in behaviours set in the features block of the provider, they apply to every resource
+// of a type rather than having to be set on each resource.
+type providerFeatures struct {
+	MachineInstance machineInstanceFeatures
+}
+
+type machineInstanceFeatures struct {
+	GracefulShutdown         bool
+	StartAfterUpdate         bool
+	DeleteVhdsOnDestroy      bool
+	PreventDeletionIfRunning bool
+}
+
+func defaultProviderFeatures() providerFeatures {
+	return providerFeatures{
+		MachineInstance: machineInstanceFeatures{
+			GracefulShutdown:         true,
+			StartAfterUpdate:         true,
+			DeleteVhdsOnDestroy:      false,
+			PreventDeletionIfRunning: false,
+		},
+	}
+}
+
+func providerFeaturesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Opt-in behaviours that apply to every resource of a type, so that policies like how virtual machines are shut down are set in one place.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"machine_instance": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "Behaviours of `hyperv_machine_instance` resources.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"graceful_shutdown": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     true,
+								Description: "Specifies whether the guest operating system of a machine instance is shut down when it has to be turned off to apply an update or to be destroyed. When `false` it is turned off immediately, like pulling its power cord, which is faster but may lose data the guest has not written yet.",
+							},
+							"start_after_update": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     true,
+								Description: "Specifies whether a machine instance that had to be turned off to apply an update is brought back to its `state` afterwards. When `false` it is left off, e.g. to start it in a maintenance window, and the next plan starts it unless `state` is changed to `Off`.",
+							},
+							"delete_vhds_on_destroy": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     false,
+								Description: "Specifies whether the virtual hard disks attached to a machine instance, together with the ones of its checkpoints, are deleted when it is destroyed. It is the default for `delete_vhds_on_destroy` of machine instances that do not set it. Do not enable it for machine instances whose virtual hard disks are managed by `hyperv_vhd` resources.",
+							},
+							"prevent_deletion_if_running": {
+								Type:        schema.TypeBool,
+								Optional:    true,
+								Default:     false,
+								Description: "Specifies whether destroying a machine instance that is running fails, so that it has to be turned off with `state = \"Off\"` first.",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandProviderFeatures(d *schema.ResourceData) providerFeatures {
+	features := defaultProviderFeatures()
+
+	for _, rawFeatures := range (d.Get("features")).([]interface{}) {
+		if rawFeatures == nil {
+			continue
+		}
+
+		for _, rawMachineInstance := range rawFeatures.(map[string]interface{})["machine_instance"].([]interface{}) {
+			if rawMachineInstance == nil {
+				continue
+			}
+
+			machineInstance := rawMachineInstance.(map[string]interface{})
+			features.MachineInstance.GracefulShutdown = machineInstance["graceful_shutdown"].(bool)
+			features.MachineInstance.StartAfterUpdate = machineInstance["start_after_update"].(bool)
+			features.MachineInstance.DeleteVhdsOnDestroy = machineInstance["delete_vhds_on_destroy"].(bool)
+			features.MachineInstance.PreventDeletionIfRunning = machineInstance["prevent_deletion_if_running"].(bool)
+		}
+	}
+
+	return features
+}
+
+// getProviderFeatures returns the features of the provider, or the defaults when the provider was not configured
+func getProviderFeatures(meta interface{}) providerFeatures {
+	providerMeta, ok := meta.(*providerMeta)
+	if !ok {
+		return defaultProviderFeatures()
+	}
+
+	return providerMeta.Features
+}
@@ -48,6 +48,16 @@ const (
 
 	// DefaultTimeout is used if there is no timeout given
 	DefaultTimeoutString = "30s"
+
+	// DefaultWorkspacePath is used as the directory to create the workspace of each provider run in
+	// if not provided otherwise.
+	DefaultWorkspacePath = "C:/Temp/terraform-provider-hyperv"
+
+	DefaultStageScriptModule = false
+
+	DefaultDriftDetection = false
+
+	DefaultScriptModuleSigningCertificateThumbprint = ""
 )
 
 func init() {
@@ -81,6 +91,7 @@ func New(version string, commit string) func() *schema.Provider {
 				"password": {
 					Type:        schema.TypeString,
 					Optional:    true,
+					Sensitive:   true,
 					DefaultFunc: schema.EnvDefaultFunc("HYPERV_PASSWORD", ""),
 					Description: "The password associated with the username to use for HyperV api calls. It can also be sourced from the `HYPERV_PASSWORD` environment variable`.",
 				},
@@ -189,22 +200,118 @@ func New(version string, commit string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("HYPERV_TIMEOUT", DefaultTimeoutString),
 					Description: "The timeout to wait for the connection to become available for HyperV api calls. Should be provided as a string like 30s or 5m. Can also be sourced from the `HYPERV_TIMEOUT` environment variable otherwise defaults to `30s`.",
 				},
+
+				"workspace_path": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HYPERV_WORKSPACE_PATH", DefaultWorkspacePath),
+					Description: "The directory on the HyperV host machine in which a workspace is created for each provider run. Scripts store their temporary files in the workspace, and it is removed when the provider exits. Workspaces left behind by provider runs that were killed are removed after a day. Can also be sourced from the `HYPERV_WORKSPACE_PATH` environment variable otherwise defaults to `C:/Temp/terraform-provider-hyperv`.",
+				},
+
+				"drift_detection": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HYPERV_DRIFT_DETECTION", DefaultDriftDetection),
+					Description: "Report a warning for every attribute set by the configuration that was changed outside of Terraform, and for every resource that was deleted outside of Terraform, when resources are refreshed. Combine with `terraform plan -refresh-only` to audit HyperV host machines without changing them. Can also be set via setting the `HYPERV_DRIFT_DETECTION` environment variable to `true` otherwise defaults to `false`.",
+				},
+
+				"stage_script_module": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HYPERV_STAGE_SCRIPT_MODULE", DefaultStageScriptModule),
+					Description: "Stage a helper PowerShell module on the HyperV host machine the first time it is used, under `%ProgramData%\\terraform-provider-hyperv\\script-modules` which only Administrators and SYSTEM can write to, and send each HyperV api call as a short gzip compressed function call to it instead of the full script. This reduces the size of every WinRM payload. The hash of the module is checked before every import, and a module that was changed on the host is staged again. Can also be set via setting the `HYPERV_STAGE_SCRIPT_MODULE` environment variable to `true` otherwise defaults to `false`.",
+				},
+
+				"script_module_signing_certificate_thumbprint": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("HYPERV_SCRIPT_MODULE_SIGNING_CERTIFICATE_THUMBPRINT", DefaultScriptModuleSigningCertificateThumbprint),
+					Description: "The thumbprint of a code signing certificate in the `LocalMachine\\My` or `CurrentUser\\My` certificate store of the HyperV host machine used to sign the staged helper PowerShell module, for hosts with an `AllSigned` execution policy. Only used when `stage_script_module` is `true`. Can also be sourced from the `HYPERV_SCRIPT_MODULE_SIGNING_CERTIFICATE_THUMBPRINT` environment variable otherwise defaults to empty string.",
+				},
+
+				"features": providerFeaturesSchema(),
 			},
 
 			ResourcesMap: map[string]*schema.Resource{
-				"hyperv_network_switch":   resourceHyperVNetworkSwitch(),
-				"hyperv_machine_instance": resourceHyperVMachineInstance(),
-				"hyperv_vhd":              resourceHyperVVhd(),
-				"hyperv_dvd":              resourceHyperVDvd(),
+				"hyperv_network_switch":                 resourceHyperVNetworkSwitch(),
+				"hyperv_network_adapter_team_mapping":   resourceHyperVNetworkAdapterTeamMapping(),
+				"hyperv_machine_instance":               resourceHyperVMachineInstance(),
+				"hyperv_vhd":                            resourceHyperVVhd(),
+				"hyperv_dvd":                            resourceHyperVDvd(),
+				"hyperv_vswitch_extension":              resourceHyperVVSwitchExtension(),
+				"hyperv_nat":                            resourceHyperVNat(),
+				"hyperv_nat_static_mapping":             resourceHyperVNatStaticMapping(),
+				"hyperv_nat_network":                    resourceHyperVNatNetwork(),
+				"hyperv_host_settings":                  resourceHyperVHostSettings(),
+				"hyperv_host_certificate":               resourceHyperVHostCertificate(),
+				"hyperv_windows_feature":                resourceHyperVWindowsFeature(),
+				"hyperv_host_live_migration_settings":   resourceHyperVHostLiveMigrationSettings(),
+				"hyperv_host_replication_settings":      resourceHyperVHostReplicationSettings(),
+				"hyperv_vm_replication":                 resourceHyperVVmReplication(),
+				"hyperv_replica_authorization_entry":    resourceHyperVReplicaAuthorizationEntry(),
+				"hyperv_vm_migration":                   resourceHyperVVmMigration(),
+				"hyperv_cluster_vm_role":                resourceHyperVClusterVmRole(),
+				"hyperv_vm_group":                       resourceHyperVVmGroup(),
+				"hyperv_resource_pool":                  resourceHyperVResourcePool(),
+				"hyperv_storage_qos_policy":             resourceHyperVStorageQosPolicy(),
+				"hyperv_vm_hard_disk_drive":             resourceHyperVVmHardDiskDrive(),
+				"hyperv_vm_dvd_drive":                   resourceHyperVVmDvdDrive(),
+				"hyperv_vm_network_adapter":             resourceHyperVVmNetworkAdapter(),
+				"hyperv_iso_library":                    resourceHyperVIsoLibrary(),
+				"hyperv_smb_file_share":                 resourceHyperVSmbFileShare(),
+				"hyperv_virtual_machine_path":           resourceHyperVVirtualMachinePath(),
+				"hyperv_switch_team":                    resourceHyperVSwitchTeam(),
+				"hyperv_dhcp_server_scope":              resourceHyperVDhcpServerScope(),
+				"hyperv_vswitch_nat_dns_forwarding":     resourceHyperVVSwitchNatDnsForwarding(),
+				"hyperv_vm_tpm_state_backup":            resourceHyperVVmTpmStateBackup(),
+				"hyperv_vmware_migration":               resourceHyperVVmwareMigration(),
+				"hyperv_vm_scheduled_task":              resourceHyperVVmScheduledTask(),
+				"hyperv_vm_backup_checkpoint_policy":    resourceHyperVVmBackupCheckpointPolicy(),
+				"hyperv_vm_processor_pinning":           resourceHyperVVmProcessorPinning(),
+				"hyperv_vm_affinity_rule":               resourceHyperVVmAffinityRule(),
+				"hyperv_vm_integration_guest_time_sync": resourceHyperVVmIntegrationGuestTimeSync(),
+				"hyperv_host_numa_spanning":             resourceHyperVHostNumaSpanning(),
 			},
 			DataSourcesMap: map[string]*schema.Resource{
-				"hyperv_network_switch":   dataSourceHyperVNetworkSwitch(),
-				"hyperv_machine_instance": dataSourceHyperVMachineInstance(),
-				"hyperv_vhd":              dataSourceHyperVVhd(),
+				"hyperv_network_switch":            dataSourceHyperVNetworkSwitch(),
+				"hyperv_machine_instance":          dataSourceHyperVMachineInstance(),
+				"hyperv_vhd":                       dataSourceHyperVVhd(),
+				"hyperv_vswitch_extensions":        dataSourceHyperVVSwitchExtensions(),
+				"hyperv_vswitch":                   dataSourceHyperVVSwitch(),
+				"hyperv_vswitches":                 dataSourceHyperVVSwitches(),
+				"hyperv_default_switch":            dataSourceHyperVDefaultSwitch(),
+				"hyperv_physical_network_adapters": dataSourceHyperVPhysicalNetworkAdapters(),
+				"hyperv_vm":                        dataSourceHyperVVm(),
+				"hyperv_vms":                       dataSourceHyperVVms(),
+				"hyperv_host":                      dataSourceHyperVHost(),
+				"hyperv_vm_network_adapter":        dataSourceHyperVVmNetworkAdapter(),
+				"hyperv_physical_disks":            dataSourceHyperVPhysicalDisks(),
+				"hyperv_vm_checkpoints":            dataSourceHyperVVmCheckpoints(),
+				"hyperv_switch_extensions":         dataSourceHyperVSwitchExtensions(),
+				"hyperv_secure_boot_templates":     dataSourceHyperVSecureBootTemplates(),
+				"hyperv_gpu_partitionable_devices": dataSourceHyperVGpuPartitionableDevices(),
+				"hyperv_assignable_devices":        dataSourceHyperVAssignableDevices(),
+				"hyperv_integration_services":      dataSourceHyperVIntegrationServices(),
+				"hyperv_host_numa_topology":        dataSourceHyperVHostNumaTopology(),
+				"hyperv_vhd_parent_chain":          dataSourceHyperVVhdParentChain(),
+				"hyperv_vm_console_screenshot":     dataSourceHyperVVmConsoleScreenshot(),
+				"hyperv_password_hash":             dataSourceHyperVPasswordHash(),
+				"hyperv_vm_generation_support":     dataSourceHyperVVmGenerationSupport(),
+				"hyperv_vm_events":                 dataSourceHyperVVmEvents(),
+				"hyperv_iso_catalog":               dataSourceHyperVIsoCatalog(),
 				//"hyperv_dvd":              dataSourceHyperVDvd(),
 			},
 		}
 
+		for resourceType, resource := range provider.ResourcesMap {
+			withDriftDetection(resourceType, resource)
+			withOperationDiagnostics(resourceType, resource)
+		}
+
+		for dataSourceType, dataSource := range provider.DataSourcesMap {
+			withOperationDiagnostics(dataSourceType, dataSource)
+		}
+
 		provider.ConfigureContextFunc = configure(version, commit, provider)
 
 		return provider
@@ -262,26 +369,29 @@ func configure(version string, commit string, provider *schema.Provider) func(co
 		}
 
 		config := Config{
-			Version:          version,
-			Commit:           commit,
-			TerraformVersion: terraformVersion,
-			User:             resourceData.Get("user").(string),
-			Password:         resourceData.Get("password").(string),
-			Host:             resourceData.Get("host").(string),
-			Port:             resourceData.Get("port").(int),
-			HTTPS:            resourceData.Get("https").(bool),
-			CACert:           cacert,
-			Cert:             cert,
-			Key:              key,
-			Insecure:         resourceData.Get("insecure").(bool),
-			NTLM:             resourceData.Get("use_ntlm").(bool),
-			KrbRealm:         resourceData.Get("kerberos_realm").(string),
-			KrbSpn:           resourceData.Get("kerberos_service_principal_name").(string),
-			KrbConfig:        resourceData.Get("kerberos_config").(string),
-			KrbCCache:        resourceData.Get("kerberos_credential_cache").(string),
-			TLSServerName:    resourceData.Get("tls_server_name").(string),
-			ScriptPath:       resourceData.Get("script_path").(string),
-			Timeout:          resourceData.Get("timeout").(string),
+			Version:                                  version,
+			Commit:                                   commit,
+			TerraformVersion:                         terraformVersion,
+			User:                                     resourceData.Get("user").(string),
+			Password:                                 resourceData.Get("password").(string),
+			Host:                                     resourceData.Get("host").(string),
+			Port:                                     resourceData.Get("port").(int),
+			HTTPS:                                    resourceData.Get("https").(bool),
+			CACert:                                   cacert,
+			Cert:                                     cert,
+			Key:                                      key,
+			Insecure:                                 resourceData.Get("insecure").(bool),
+			NTLM:                                     resourceData.Get("use_ntlm").(bool),
+			KrbRealm:                                 resourceData.Get("kerberos_realm").(string),
+			KrbSpn:                                   resourceData.Get("kerberos_service_principal_name").(string),
+			KrbConfig:                                resourceData.Get("kerberos_config").(string),
+			KrbCCache:                                resourceData.Get("kerberos_credential_cache").(string),
+			TLSServerName:                            resourceData.Get("tls_server_name").(string),
+			ScriptPath:                               resourceData.Get("script_path").(string),
+			Timeout:                                  resourceData.Get("timeout").(string),
+			WorkspacePath:                            resourceData.Get("workspace_path").(string),
+			StageScriptModule:                        resourceData.Get("stage_script_module").(bool),
+			ScriptModuleSigningCertificateThumbprint: resourceData.Get("script_module_signing_certificate_thumbprint").(string),
 		}
 
 		client, err := config.Client()
@@ -289,6 +399,10 @@ func configure(version string, commit string, provider *schema.Provider) func(co
 			return nil, diag.FromErr(err)
 		}
 
-		return client, diags
+		return &providerMeta{
+			Client:         client,
+			DriftDetection: resourceData.Get("drift_detection").(bool),
+			Features:       expandProviderFeatures(resourceData),
+		}, diags
 	}
 }
@@ -4,14 +4,25 @@
 package provider
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/taliesins/terraform-provider-hyperv/api"
 )
 
+// testAccNamePrefix is used for the names of everything created by the acceptance tests, so that the sweepers can
+// clean up anything that was left behind on the HyperV host machine by a failed run
+const testAccNamePrefix = "tfacc"
+
 var (
 	// these will be set by the goreleaser configuration
 	// to appropriate values for the compiled binary
@@ -30,6 +41,10 @@ var providerFactories = map[string]func() (*schema.Provider, error){
 	},
 }
 
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
 func TestProvider(t *testing.T) {
 	if err := New(version, commit)().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
@@ -42,6 +57,75 @@ func testAccPreCheck(t *testing.T) {
 	// function.
 }
 
+// testAccPreCheckEnv skips tests that need more than a single HyperV host machine, like a cluster or a replica server,
+// unless the environment variables describing them are set
+func testAccPreCheckEnv(t *testing.T, names ...string) {
+	for _, name := range names {
+		if os.Getenv(name) == "" {
+			t.Skipf("skipping test as %s is not set", name)
+		}
+	}
+}
+
+// testAccProviderClient returns a client configured from the same environment variables as the provider, it is used
+// to check that resources are destroyed and by the sweepers
+func testAccProviderClient() (api.Client, error) {
+	provider := New(version, commit)()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	if diags.HasError() {
+		return nil, fmt.Errorf("unable to configure provider: %v", diags)
+	}
+
+	return provider.Meta().(api.Client), nil
+}
+
+// testAccCheckDestroy checks that none of the resources of the resource type in the state still exist on the HyperV
+// host machine
+func testAccCheckDestroy(resourceType string, exists func(ctx context.Context, c api.Client, id string) (bool, error)) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		c, err := testAccProviderClient()
+		if err != nil {
+			return err
+		}
+
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+
+			found, err := exists(ctx, c, rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+
+			if found {
+				return fmt.Errorf("%s %s still exists", resourceType, rs.Primary.ID)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccName(name string) string {
+	return fmt.Sprintf("%s_%s_%d", testAccNamePrefix, name, randInt())
+}
+
+// testAccDirectory returns the directory the tests are run from, as the tests are run on the HyperV host machine the
+// files created by them are stored there
+func testAccDirectory() string {
+	//tempDirectory := os.TempDir() uses short name ;<
+	tempDirectory, _ := filepath.Abs(".")
+	return tempDirectory
+}
+
+func testAccPath(fileName string) string {
+	extension := filepath.Ext(fileName)
+	path, _ := filepath.Abs(filepath.Join(testAccDirectory(), fmt.Sprintf("%s_%s_%d%s", testAccNamePrefix, strings.TrimSuffix(fileName, extension), randInt(), extension)))
+	return path
+}
+
 func escapeForHcl(value string) string {
 	return strings.ReplaceAll(value, "\\", "\\\\")
 }
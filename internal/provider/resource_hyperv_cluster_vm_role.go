@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadClusterVmRoleTimeout   = 2 * time.Minute
+	CreateClusterVmRoleTimeout = 10 * time.Minute
+	UpdateClusterVmRoleTimeout = 2 * time.Minute
+	DeleteClusterVmRoleTimeout = 5 * time.Minute
+)
+
+func resourceHyperVClusterVmRole() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to make a virtual machine on a failover clustered HyperV host machine highly available by adding it to the cluster as a virtual machine role. The virtual machine files must be on storage that is available to all nodes of the cluster, such as a cluster shared volume.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadClusterVmRoleTimeout),
+			Create: schema.DefaultTimeout(CreateClusterVmRoleTimeout),
+			Update: schema.DefaultTimeout(UpdateClusterVmRoleTimeout),
+			Delete: schema.DefaultTimeout(DeleteClusterVmRoleTimeout),
+		},
+		CreateContext: resourceHyperVClusterVmRoleCreate,
+		ReadContext:   resourceHyperVClusterVmRoleRead,
+		UpdateContext: resourceHyperVClusterVmRoleUpdate,
+		DeleteContext: resourceHyperVClusterVmRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine to make highly available.",
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the cluster role. Defaults to the name of the virtual machine.",
+			},
+			"preferred_owners": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Specifies the cluster nodes that are preferred to own the virtual machine role, in order of preference. When empty any node in the cluster can own the role.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"priority": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.ClusterGroupPriority_name[api.ClusterGroupPriority_Medium],
+				ValidateDiagFunc: stringKeyInMap(api.ClusterGroupPriority_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the priority the cluster uses when starting and placing the virtual machine role. Valid values to use are `High`, `Medium`, `Low`, `NoAutoStart`.",
+			},
+			"owner_node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The cluster node that currently owns the virtual machine role.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The current state of the virtual machine role e.g. `Online`, `Offline`, `Failed`.",
+			},
+		},
+	}
+}
+
+func expandClusterVmRolePreferredOwners(d *schema.ResourceData) []string {
+	preferredOwners := []string{}
+	if raw, ok := d.GetOk("preferred_owners"); ok {
+		for _, v := range raw.([]interface{}) {
+			preferredOwners = append(preferredOwners, v.(string))
+		}
+	}
+
+	return preferredOwners
+}
+
+func resourceHyperVClusterVmRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv cluster vm role: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	name := (d.Get("name")).(string)
+	preferredOwners := expandClusterVmRolePreferredOwners(d)
+	priority := api.ToClusterGroupPriority((d.Get("priority")).(string))
+
+	if d.IsNewResource() {
+		roleName := name
+		if roleName == "" {
+			roleName = vmName
+		}
+
+		existing, err := c.GetClusterVmRole(ctx, roleName)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", roleName, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", roleName, "hyperv_cluster_vm_role", "hyperv_cluster_vm_role", roleName))
+		}
+	}
+
+	clusterVmRole, err := c.CreateClusterVmRole(ctx, vmName, name, preferredOwners, priority)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(clusterVmRole.Name)
+	log.Printf("[INFO][hyperv][create] created hyperv cluster vm role: %#v", d)
+
+	return resourceHyperVClusterVmRoleRead(ctx, d, meta)
+}
+
+func resourceHyperVClusterVmRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv cluster vm role: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	clusterVmRole, err := c.GetClusterVmRole(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved cluster vm role: %+v", clusterVmRole)
+
+	if clusterVmRole.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv cluster vm role as it does not exist: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("vm_name", clusterVmRole.VmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("name", clusterVmRole.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("preferred_owners", api.PreferConfiguredCase(d, "preferred_owners", clusterVmRole.PreferredOwners)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("priority", clusterVmRole.Priority.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("owner_node", clusterVmRole.OwnerNode); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("state", clusterVmRole.State); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv cluster vm role: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVClusterVmRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv cluster vm role: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+	preferredOwners := expandClusterVmRolePreferredOwners(d)
+	priority := api.ToClusterGroupPriority((d.Get("priority")).(string))
+
+	err := c.UpdateClusterVmRole(ctx, name, preferredOwners, priority)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv cluster vm role: %#v", d)
+
+	return resourceHyperVClusterVmRoleRead(ctx, d, meta)
+}
+
+func resourceHyperVClusterVmRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv cluster vm role: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	err := c.DeleteClusterVmRole(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv cluster vm role: %#v", d)
+	return nil
+}
@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceClusterVmRole(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	clusterName := os.Getenv("HYPERV_TEST_CLUSTER_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_CLUSTER_NAME")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceClusterVmRoleConfig(name, clusterName, "High"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_cluster_vm_role.this", "vm_name", name),
+					resource.TestCheckResourceAttr("hyperv_cluster_vm_role.this", "priority", "High"),
+					resource.TestCheckResourceAttrSet("hyperv_cluster_vm_role.this", "owner_node"),
+				),
+			},
+			{
+				Config: testHyperVResourceClusterVmRoleConfig(name, clusterName, "Medium"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_cluster_vm_role.this", "priority", "Medium"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceClusterVmRoleConfig(name string, clusterName string, priority string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name         = "%s"
+	generation   = 2
+	state        = "Off"
+	cluster_name = "%s"
+}
+
+resource "hyperv_cluster_vm_role" "this" {
+	vm_name  = hyperv_machine_instance.this.name
+	priority = "%s"
+}
+	`, escapeForHcl(name), escapeForHcl(clusterName), priority)
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadDhcpServerScopeTimeout   = 1 * time.Minute
+	CreateDhcpServerScopeTimeout = 10 * time.Minute
+	UpdateDhcpServerScopeTimeout = 2 * time.Minute
+	DeleteDhcpServerScopeTimeout = 2 * time.Minute
+)
+
+func resourceHyperVDhcpServerScope() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a scope of the Windows DHCP Server role on the HyperV host machine, so virtual machines on internal and NAT switches get their addresses without any external infrastructure. The DHCP Server role is installed when the first scope is created, which requires Windows Server. The ID is the scope id, which is the network address of the scope e.g. `192.168.100.0`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadDhcpServerScopeTimeout),
+			Create: schema.DefaultTimeout(CreateDhcpServerScopeTimeout),
+			Update: schema.DefaultTimeout(UpdateDhcpServerScopeTimeout),
+			Delete: schema.DefaultTimeout(DeleteDhcpServerScopeTimeout),
+		},
+		CreateContext: resourceHyperVDhcpServerScopeCreate,
+		ReadContext:   resourceHyperVDhcpServerScopeRead,
+		UpdateContext: resourceHyperVDhcpServerScopeUpdate,
+		DeleteContext: resourceHyperVDhcpServerScopeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the name of the scope.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies a description of the scope.",
+			},
+			"start_range": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the first IP address of the range handed out by the scope e.g. `192.168.100.100`.",
+			},
+			"end_range": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the last IP address of the range handed out by the scope e.g. `192.168.100.200`.",
+			},
+			"subnet_mask": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the subnet mask of the scope e.g. `255.255.255.0`. Together with `start_range` it determines the scope id.",
+			},
+			"lease_duration_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          691200,
+				ValidateDiagFunc: IntBetween(60, 31536000),
+				Description:      "Specifies how long, in seconds, a lease of an IP address lasts. Defaults to 8 days.",
+			},
+			"router": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the default gateway handed out by the scope, e.g. the `gateway_address` of a `hyperv_nat_network`.",
+			},
+			"dns_servers": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: IsIPAddress()},
+				Description: "Specifies the DNS servers handed out by the scope.",
+			},
+			"dns_domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies the DNS domain name handed out by the scope e.g. `lab.local`.",
+			},
+			"switch_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the internal switch whose host network adapter the DHCP server is bound to, e.g. the `switch_name` of a `hyperv_nat_network`. The binding is left in place when the scope is destroyed, as other scopes may use it.",
+			},
+			"scope_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The scope id, which is the network address of the scope.",
+			},
+		},
+	}
+}
+
+func expandDhcpServerScope(d *schema.ResourceData) api.DhcpServerScope {
+	dnsServers := make([]string, 0)
+	for _, dnsServer := range (d.Get("dns_servers")).([]interface{}) {
+		dnsServers = append(dnsServers, dnsServer.(string))
+	}
+
+	return api.DhcpServerScope{
+		Name:                 (d.Get("name")).(string),
+		Description:          (d.Get("description")).(string),
+		StartRange:           (d.Get("start_range")).(string),
+		EndRange:             (d.Get("end_range")).(string),
+		SubnetMask:           (d.Get("subnet_mask")).(string),
+		LeaseDurationSeconds: int64((d.Get("lease_duration_seconds")).(int)),
+		Router:               (d.Get("router")).(string),
+		DnsServers:           dnsServers,
+		DnsDomain:            (d.Get("dns_domain")).(string),
+		SwitchName:           (d.Get("switch_name")).(string),
+	}
+}
+
+func resourceHyperVDhcpServerScopeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv dhcp server scope: %#v", d)
+	c := meta.(api.Client)
+
+	dhcpServerScope := expandDhcpServerScope(d)
+
+	scopeId, err := api.NetworkAddress(dhcpServerScope.StartRange, dhcpServerScope.SubnetMask)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetDhcpServerScope(ctx, scopeId)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", scopeId, err))
+		}
+
+		if existing.ScopeId != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", scopeId, "hyperv_dhcp_server_scope", "hyperv_dhcp_server_scope", scopeId))
+		}
+	}
+
+	created, err := c.CreateDhcpServerScope(ctx, dhcpServerScope)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(created.ScopeId)
+	log.Printf("[INFO][hyperv][create] created hyperv dhcp server scope: %#v", d)
+
+	return resourceHyperVDhcpServerScopeRead(ctx, d, meta)
+}
+
+func resourceHyperVDhcpServerScopeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv dhcp server scope: %#v", d)
+	c := meta.(api.Client)
+
+	scopeId := d.Id()
+
+	dhcpServerScope, err := c.GetDhcpServerScope(ctx, scopeId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved dhcp server scope: %+v", dhcpServerScope)
+
+	if dhcpServerScope.ScopeId == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv dhcp server scope as it does not exist: %#v", scopeId)
+		d.SetId("")
+		return nil
+	}
+
+	switchName := (d.Get("switch_name")).(string)
+	if !dhcpServerScope.IsBoundToSwitch(switchName) {
+		switchName = ""
+	}
+
+	if err := d.Set("name", dhcpServerScope.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("description", dhcpServerScope.Description); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("start_range", dhcpServerScope.StartRange); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("end_range", dhcpServerScope.EndRange); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("subnet_mask", dhcpServerScope.SubnetMask); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("lease_duration_seconds", dhcpServerScope.LeaseDurationSeconds); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("router", dhcpServerScope.Router); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("dns_servers", dhcpServerScope.DnsServers); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("dns_domain", dhcpServerScope.DnsDomain); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switch_name", switchName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("scope_id", dhcpServerScope.ScopeId); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv dhcp server scope: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVDhcpServerScopeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv dhcp server scope: %#v", d)
+	c := meta.(api.Client)
+
+	dhcpServerScope := expandDhcpServerScope(d)
+	dhcpServerScope.ScopeId = d.Id()
+
+	err := c.UpdateDhcpServerScope(ctx, dhcpServerScope)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv dhcp server scope: %#v", d)
+
+	return resourceHyperVDhcpServerScopeRead(ctx, d, meta)
+}
+
+func resourceHyperVDhcpServerScopeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv dhcp server scope: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteDhcpServerScope(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv dhcp server scope: %#v", d)
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+var testAccCheckHyperVDhcpServerScopeDestroy = testAccCheckDestroy("hyperv_dhcp_server_scope", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	dhcpServerScope, err := c.GetDhcpServerScope(ctx, id)
+	return dhcpServerScope.ScopeId != "", err
+})
+
+func TestHyperVResourceDhcpServerScope(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("lab")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_HOST_SETTINGS")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVDhcpServerScopeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceDhcpServerScopeConfig(name, "192.168.251.200"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_dhcp_server_scope.this", "scope_id", "192.168.251.0"),
+					resource.TestCheckResourceAttr("hyperv_dhcp_server_scope.this", "router", "192.168.251.1"),
+					resource.TestCheckResourceAttr("hyperv_dhcp_server_scope.this", "switch_name", name),
+				),
+			},
+			{
+				Config: testHyperVResourceDhcpServerScopeConfig(name, "192.168.251.150"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_dhcp_server_scope.this", "end_range", "192.168.251.150"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceDhcpServerScopeConfig(name string, endRange string) string {
+	return fmt.Sprintf(`
+resource "hyperv_nat_network" "this" {
+	name            = "%s"
+	cidr            = "192.168.251.0/24"
+	gateway_address = "192.168.251.1"
+}
+
+resource "hyperv_dhcp_server_scope" "this" {
+	name        = "%s"
+	start_range = "192.168.251.100"
+	end_range   = "%s"
+	subnet_mask = "255.255.255.0"
+	router      = hyperv_nat_network.this.gateway_address
+	switch_name = hyperv_nat_network.this.switch_name
+}
+	`, escapeForHcl(name), escapeForHcl(name), endRange)
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"log"
 	"path"
 	"strings"
@@ -20,8 +21,9 @@ const (
 )
 
 func resourceHyperVDvd() *schema.Resource {
-	return &schema.Resource{
-		Description: "This Hyper-V resource allows you to manage VHDs.",
+	resource := &schema.Resource{
+		Description:   "This Hyper-V resource allows you to manage VHDs.",
+		SchemaVersion: 1,
 		Timeouts: &schema.ResourceTimeout{
 			Read:   schema.DefaultTimeout(ReadDvdTimeout),
 			Create: schema.DefaultTimeout(CreateDvdTimeout),
@@ -55,13 +57,83 @@ func resourceHyperVDvd() *schema.Resource {
 
 					return false
 				},
-				Description: "Path to the new iso that is being created or being copied to. If a filename or relative path is specified, the new virtual hard disk path is calculated relative to the current working directory. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "Path to the new iso that is being created or being copied to. The path must be absolute, either on a drive e.g. `C:\\Iso\\seed.iso` or on a share e.g. `\\\\server\\share\\seed.iso`. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
 			},
 			"ip": {
+				ForceNew:      true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Default:       "",
+				ConflictsWith: []string{"addresses"},
+				Description:   "Specifies the static IPv4 address of the guest. It gets a /16 prefix, and the default gateway `172.16.1.254` and name server `172.16.14.27` unless `gateway4` or `nameservers` are specified. Use `addresses` instead for other prefixes and IPv6.",
+			},
+			"addresses": {
+				ForceNew:      true,
+				Type:          schema.TypeList,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: IsCIDR()},
+				ConflictsWith: []string{"ip"},
+				Description:   "Specifies the static addresses of the guest in CIDR notation e.g. `172.16.1.10/16` or `fd00::10/64`. IPv4 and IPv6 addresses can be mixed for a dual stack guest.",
+			},
+			"gateway4": {
+				ForceNew:         true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the IPv4 default gateway of the guest. It requires an IPv4 address in `addresses`.",
+			},
+			"gateway6": {
+				ForceNew:         true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the IPv6 default gateway of the guest. It requires an IPv6 address in `addresses`. Leave it empty to use the router advertisements of the network.",
+			},
+			"dhcp4": {
 				ForceNew:    true,
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "This field is mutually exclusive with the fields `source_vm`, `parent_path`, `source_disk`. This value can be a url or a path (including wildcards). Box, Zip and 7z files will automatically be expanded. The destination folder will be the directory portion of the path. If expanded files have a folder called `Virtual Machines`, then the `Virtual Machines` folder will be used instead of the entire archive contents. ",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Specifies whether the guest gets an IPv4 address with DHCP. Defaults to `false`.",
+			},
+			"dhcp6": {
+				ForceNew:    true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Specifies whether the guest gets an IPv6 address with DHCPv6. Defaults to `false`.",
+			},
+			"nameservers": {
+				ForceNew:    true,
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: IsIPAddress()},
+				Description: "Specifies the IPv4 or IPv6 addresses of the name servers of the guest.",
+			},
+			"ephemeral": {
+				ForceNew: true,
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vm_name": {
+							ForceNew:    true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Specifies the name of the virtual machine that is provisioned from the iso.",
+						},
+						"timeout": {
+							ForceNew:    true,
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     3600,
+							Description: "The amount of time in seconds to wait for the first heartbeat of the virtual machine. The iso is deleted once the timeout has passed, even if the virtual machine has not reported a heartbeat.",
+						},
+					},
+				},
+				Description: "Marks the iso as ephemeral, so secrets in it like password hashes do not stay on the HyperV host machine after provisioning. A scheduled task on the HyperV host machine detaches the iso from all dvd drives and deletes it once the virtual machine reports its first heartbeat, which needs the `Heartbeat` integration service to be enabled. The deleted iso is not created again, dvd drives that reference it should ignore changes to their `path`.",
 			},
 			"exists": {
 				Type:        schema.TypeBool,
@@ -70,6 +142,45 @@ func resourceHyperVDvd() *schema.Resource {
 			},
 		},
 	}
+
+	// the attributes added since the state was written by a provider that did not version the schema
+	resource.StateUpgraders = []schema.StateUpgrader{
+		stateUpgraderV0("hyperv_dvd", resource,
+			"addresses",
+			"dhcp4",
+			"dhcp6",
+			"ephemeral",
+			"gateway4",
+			"gateway6",
+			"nameservers",
+		),
+	}
+
+	return resource
+}
+
+func expandDvdNetworkConfig(d *schema.ResourceData) (api.DvdNetworkConfig, error) {
+	addresses := make([]string, 0)
+	for _, address := range (d.Get("addresses")).([]interface{}) {
+		addresses = append(addresses, address.(string))
+	}
+
+	nameservers := make([]string, 0)
+	for _, nameserver := range (d.Get("nameservers")).([]interface{}) {
+		nameservers = append(nameservers, nameserver.(string))
+	}
+
+	networkConfig := api.DvdNetworkConfig{
+		Ip:          (d.Get("ip")).(string),
+		Addresses:   addresses,
+		Gateway4:    (d.Get("gateway4")).(string),
+		Gateway6:    (d.Get("gateway6")).(string),
+		Dhcp4:       (d.Get("dhcp4")).(bool),
+		Dhcp6:       (d.Get("dhcp6")).(bool),
+		Nameservers: nameservers,
+	}
+
+	return networkConfig, networkConfig.Normalize().Validate()
 }
 
 func resourceHyperVDvdCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -77,14 +188,32 @@ func resourceHyperVDvdCreate(ctx context.Context, d *schema.ResourceData, meta i
 	c := meta.(api.Client)
 
 	path := (d.Get("path")).(string)
-	ip := (d.Get("ip")).(string)
 
-	err := c.CreateDvd(ctx, path, ip)
+	networkConfig, err := expandDvdNetworkConfig(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
+	err = c.RequireIsoTools(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	err = c.CreateDvd(ctx, path, networkConfig)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if v, ok := d.GetOk("ephemeral"); ok {
+		ephemeral := v.([]interface{})[0].(map[string]interface{})
+
+		err = c.CreateEphemeralDvdWatcher(ctx, path, ephemeral["vm_name"].(string), uint32(ephemeral["timeout"].(int)))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	d.SetId(path)
 	log.Printf("[INFO][hyperv][create] created hyperv vhd: %#v", d)
 
@@ -105,8 +234,12 @@ func resourceHyperVDvdRead(ctx context.Context, d *schema.ResourceData, meta int
 
 	log.Printf("[INFO][hyperv][read] retrieved dvd: %+v", dvd)
 
-	if err := d.Set("path", dvd.Path); err != nil {
-		return diag.FromErr(err)
+	// an ephemeral dvd is expected to be deleted once the vm has booted, it must not be created again
+	_, ephemeral := d.GetOk("ephemeral")
+	if dvd.Path != "" || !ephemeral {
+		if err := d.Set("path", dvd.Path); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	if dvd.Path == "" {
@@ -135,7 +268,8 @@ func resourceHyperVDvdDelete(ctx context.Context, d *schema.ResourceData, meta i
 
 	err := c.DeleteDvd(ctx, path)
 
-	if err != nil {
+	// the directory of the file is already gone, so there is nothing left to delete
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
 		return diag.FromErr(err)
 	}
 
@@ -0,0 +1,119 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceDvd(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	path := testAccPath("seed.iso")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceDvdConfig(path, "172.16.1.10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "path", path),
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "exists", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceDvdEphemeral(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	path := testAccPath("ephemeral_seed.iso")
+	vmName := testAccName("ephemeral")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceDvdEphemeralConfig(path, "172.16.1.10", vmName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "path", path),
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "exists", "true"),
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "ephemeral.0.vm_name", vmName),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceDvdDualStack(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	path := testAccPath("dual_stack_seed.iso")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceDvdDualStackConfig(path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "path", path),
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "exists", "true"),
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "addresses.#", "2"),
+					resource.TestCheckResourceAttr("hyperv_dvd.this", "gateway6", "fd00::1"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceDvdConfig(path string, ip string) string {
+	return fmt.Sprintf(`
+resource "hyperv_dvd" "this" {
+	path = "%s"
+	ip   = "%s"
+}
+	`, escapeForHcl(path), ip)
+}
+
+func testHyperVResourceDvdEphemeralConfig(path string, ip string, vmName string) string {
+	return fmt.Sprintf(`
+resource "hyperv_dvd" "this" {
+	path = "%s"
+	ip   = "%s"
+
+	ephemeral {
+		vm_name = "%s"
+		timeout = 600
+	}
+}
+	`, escapeForHcl(path), ip, vmName)
+}
+
+func testHyperVResourceDvdDualStackConfig(path string) string {
+	return fmt.Sprintf(`
+resource "hyperv_dvd" "this" {
+	path        = "%s"
+	addresses   = ["172.16.1.10/16", "fd00::10/64"]
+	gateway4    = "172.16.1.254"
+	gateway6    = "fd00::1"
+	nameservers = ["172.16.14.27", "fd00::53"]
+}
+	`, escapeForHcl(path))
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestResourceHyperVDvdPlanFromPreviousState checks that a dvd created by a provider without the network settings
+// added since is not replaced when the same configuration is planned
+func TestResourceHyperVDvdPlanFromPreviousState(t *testing.T) {
+	path := `C:\Iso\web_server-cidata.iso`
+	state := &terraform.InstanceState{
+		ID: path,
+		Attributes: map[string]string{
+			"id":     path,
+			"path":   path,
+			"ip":     "172.16.1.10",
+			"exists": "true",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"path": path,
+		"ip":   "172.16.1.10",
+	})
+
+	diff, err := resourceHyperVDvd().Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil && diff.RequiresNew() {
+		t.Fatalf("expected dvd not to be replaced, got %#v", diff.Attributes)
+	}
+
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expected no changes for dvd, got %#v", diff.Attributes)
+	}
+}
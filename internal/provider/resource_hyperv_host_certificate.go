@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadHostCertificateTimeout   = 1 * time.Minute
+	CreateHostCertificateTimeout = 5 * time.Minute
+	UpdateHostCertificateTimeout = 5 * time.Minute
+	DeleteHostCertificateTimeout = 2 * time.Minute
+)
+
+func resourceHyperVHostCertificate() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to bootstrap a freshly installed HyperV host machine for WinRM over HTTPS. It creates a self-signed certificate, or imports a certificate issued by a certificate authority, in the `LocalMachine\\My` certificate store and configures the WinRM HTTPS listener and the firewall rule that allows connections to it. As the provider only talks to the HyperV host machine over WinRM, use it from a provider that connects over HTTP (`https = false`, e.g. with `use_ntlm = true`) and switch the other resources to a provider that connects over HTTPS once it has been created, trusting `certificate_pem` with `cacert_path` for a self-signed certificate. Do not manage the listener of the connection that the provider itself uses, as replacing or deleting the listener drops that connection.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadHostCertificateTimeout),
+			Create: schema.DefaultTimeout(CreateHostCertificateTimeout),
+			Update: schema.DefaultTimeout(UpdateHostCertificateTimeout),
+			Delete: schema.DefaultTimeout(DeleteHostCertificateTimeout),
+		},
+		CreateContext: resourceHyperVHostCertificateCreate,
+		ReadContext:   resourceHyperVHostCertificateRead,
+		UpdateContext: resourceHyperVHostCertificateUpdate,
+		DeleteContext: resourceHyperVHostCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"hostname": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the host name clients use to connect to the WinRM HTTPS listener e.g. the fully qualified domain name of the HyperV host machine. It is the subject of a self-signed certificate and must match the subject of an imported certificate.",
+			},
+			"subject_alternative_names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Specifies other DNS names clients use to connect to the WinRM HTTPS listener, e.g. the short name of the HyperV host machine. Only used for a self-signed certificate.",
+			},
+			"validity_years": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          api.HostCertificateDefaultValidityYears,
+				ValidateDiagFunc: IntBetween(1, 50),
+				Description:      "Specifies the number of years a self-signed certificate is valid for. It is only used when the certificate is created.",
+			},
+			"pfx": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				StateFunc:   api.HashSensitiveValue,
+				Description: "Specifies the base64 encoded pfx of a certificate issued by a certificate authority, including its private key, e.g. `filebase64(\"hyperv01.pfx\")`. When empty a self-signed certificate is created. Only a hash of it is stored in the state.",
+			},
+			"pfx_password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				StateFunc:    api.HashSensitiveValue,
+				RequiredWith: []string{"pfx"},
+				Description:  "Specifies the password of `pfx`. Only a hash of it is stored in the state.",
+			},
+			"port": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          api.HostCertificateDefaultPort,
+				ValidateDiagFunc: IntBetween(1, 65535),
+				Description:      "Specifies the port of the WinRM HTTPS listener.",
+			},
+			"firewall_rule": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether to create an inbound firewall rule that allows connections to `port`. Set it to `false` when the firewall is managed in another way e.g. by group policy.",
+			},
+			"firewall_remote_addresses": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Specifies the addresses that are allowed to connect to the WinRM HTTPS listener, e.g. `10.0.0.0/24` or `LocalSubnet`. When empty connections are allowed from any address.",
+			},
+			"thumbprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The thumbprint of the certificate, which is also the id of the resource.",
+			},
+			"subject": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The subject of the certificate.",
+			},
+			"issuer": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The issuer of the certificate, the same as the subject for a self-signed certificate.",
+			},
+			"not_after": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the certificate expires in UTC e.g. `2030-01-31T12:00:00Z`.",
+			},
+			"certificate_pem": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The public certificate in the PEM format, e.g. to write to the file of `cacert_path` so that the provider trusts a self-signed certificate.",
+			},
+		},
+	}
+}
+
+func expandHostCertificateSettings(d *schema.ResourceData) (api.HostCertificateSettings, error) {
+	subjectAlternativeNames := make([]string, 0)
+	for _, subjectAlternativeName := range (d.Get("subject_alternative_names")).([]interface{}) {
+		subjectAlternativeNames = append(subjectAlternativeNames, subjectAlternativeName.(string))
+	}
+
+	firewallRemoteAddresses := make([]string, 0)
+	for _, firewallRemoteAddress := range (d.Get("firewall_remote_addresses")).([]interface{}) {
+		firewallRemoteAddresses = append(firewallRemoteAddresses, firewallRemoteAddress.(string))
+	}
+
+	settings := api.HostCertificateSettings{
+		Hostname:                (d.Get("hostname")).(string),
+		SubjectAlternativeNames: subjectAlternativeNames,
+		ValidityYears:           (d.Get("validity_years")).(int),
+		PfxBase64:               api.GetSensitiveValue(d, "pfx"),
+		PfxPassword:             api.GetSensitiveValue(d, "pfx_password"),
+		Port:                    (d.Get("port")).(int),
+		FirewallRule:            (d.Get("firewall_rule")).(bool),
+		FirewallRemoteAddresses: firewallRemoteAddresses,
+	}
+
+	return settings, settings.Validate()
+}
+
+func resourceHyperVHostCertificateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv host certificate: %#v", d)
+	c := meta.(api.Client)
+
+	settings, err := expandHostCertificateSettings(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	hostCertificate, err := c.CreateOrUpdateHostCertificate(ctx, "", settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hostCertificate.Thumbprint)
+	log.Printf("[INFO][hyperv][create] created hyperv host certificate: %#v", d)
+
+	return resourceHyperVHostCertificateRead(ctx, d, meta)
+}
+
+func resourceHyperVHostCertificateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv host certificate: %#v", d)
+	c := meta.(api.Client)
+
+	thumbprint := d.Id()
+
+	hostCertificate, err := c.GetHostCertificate(ctx, thumbprint)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host certificate: %s %s", hostCertificate.Thumbprint, hostCertificate.Subject)
+
+	if hostCertificate.Thumbprint == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv host certificate as it does not exist: %#v", thumbprint)
+		d.SetId("")
+		return nil
+	}
+
+	certificatePem, err := hostCertificate.Pem()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// a listener that was removed outside of Terraform shows as a change of port, so that it is created again
+	hostname := (d.Get("hostname")).(string)
+	if hostCertificate.Port != 0 {
+		hostname = hostCertificate.Hostname
+	}
+
+	if hostCertificate.SelfSigned {
+		subjectAlternativeNames := make([]string, 0)
+		for _, dnsName := range hostCertificate.DnsNames {
+			if !strings.EqualFold(dnsName, hostname) {
+				subjectAlternativeNames = append(subjectAlternativeNames, dnsName)
+			}
+		}
+
+		if err := d.Set("subject_alternative_names", subjectAlternativeNames); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set("hostname", hostname); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("port", hostCertificate.Port); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("firewall_rule", hostCertificate.FirewallRule); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("firewall_remote_addresses", hostCertificate.FirewallRemoteAddresses); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("thumbprint", hostCertificate.Thumbprint); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("subject", hostCertificate.Subject); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("issuer", hostCertificate.Issuer); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("not_after", hostCertificate.NotAfter); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("certificate_pem", certificatePem); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv host certificate: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVHostCertificateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv host certificate: %#v", d)
+	c := meta.(api.Client)
+
+	settings, err := expandHostCertificateSettings(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	_, err = c.CreateOrUpdateHostCertificate(ctx, d.Id(), settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv host certificate: %#v", d)
+
+	return resourceHyperVHostCertificateRead(ctx, d, meta)
+}
+
+func resourceHyperVHostCertificateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv host certificate: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteHostCertificate(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv host certificate: %#v", d)
+	return nil
+}
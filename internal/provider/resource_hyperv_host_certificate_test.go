@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceHostCertificate(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	hostname := fmt.Sprintf("%s.contoso.com", strings.ReplaceAll(testAccName("host"), "_", "-"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_HOST_SETTINGS")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceHostCertificateConfig(hostname, 15986),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_host_certificate.this", "port", "15986"),
+					resource.TestCheckResourceAttr("hyperv_host_certificate.this", "firewall_rule", "true"),
+					resource.TestCheckResourceAttr("hyperv_host_certificate.this", "subject_alternative_names.#", "1"),
+					resource.TestCheckResourceAttr("hyperv_host_certificate.this", "subject", "CN="+hostname),
+					resource.TestCheckResourceAttrPair("hyperv_host_certificate.this", "thumbprint", "hyperv_host_certificate.this", "id"),
+					resource.TestCheckResourceAttrSet("hyperv_host_certificate.this", "certificate_pem"),
+				),
+			},
+			{
+				Config: testHyperVResourceHostCertificateConfig(hostname, 15987),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_host_certificate.this", "port", "15987"),
+					resource.TestCheckResourceAttr("hyperv_host_certificate.this", "firewall_remote_addresses.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceHostCertificateConfig(hostname string, port int) string {
+	return fmt.Sprintf(`
+resource "hyperv_host_certificate" "this" {
+	hostname                  = "%s"
+	subject_alternative_names = ["%s"]
+	port                      = %d
+	firewall_remote_addresses = ["LocalSubnet"]
+}
+	`, escapeForHcl(hostname), escapeForHcl(strings.Split(hostname, ".")[0]), port)
+}
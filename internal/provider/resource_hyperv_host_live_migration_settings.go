@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadHostLiveMigrationSettingsTimeout   = 1 * time.Minute
+	CreateHostLiveMigrationSettingsTimeout = 2 * time.Minute
+	UpdateHostLiveMigrationSettingsTimeout = 2 * time.Minute
+	DeleteHostLiveMigrationSettingsTimeout = 1 * time.Minute
+)
+
+func resourceHyperVHostLiveMigrationSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage the live migration settings of the HyperV host machine. There is only one instance of the live migration settings, so only one of these resources should be declared per host. Destroying the resource leaves the live migration settings unchanged.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadHostLiveMigrationSettingsTimeout),
+			Create: schema.DefaultTimeout(CreateHostLiveMigrationSettingsTimeout),
+			Update: schema.DefaultTimeout(UpdateHostLiveMigrationSettingsTimeout),
+			Delete: schema.DefaultTimeout(DeleteHostLiveMigrationSettingsTimeout),
+		},
+		CreateContext: resourceHyperVHostLiveMigrationSettingsCreate,
+		ReadContext:   resourceHyperVHostLiveMigrationSettingsRead,
+		UpdateContext: resourceHyperVHostLiveMigrationSettingsUpdate,
+		DeleteContext: resourceHyperVHostLiveMigrationSettingsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether live migration of virtual machines is enabled on the HyperV host machine.",
+			},
+			"authentication_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VMMigrationAuthenticationType_name[api.VMMigrationAuthenticationType_CredSSP],
+				ValidateDiagFunc: stringKeyInMap(api.VMMigrationAuthenticationType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the type of authentication used for live migrations. `Kerberos` requires constrained delegation to be configured in Active Directory. Valid values to use are `CredSSP`, `Kerberos`.",
+			},
+			"maximum_virtual_machine_migrations": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          2,
+				ValidateDiagFunc: IntBetween(1, 512),
+				Description:      "Should be a value between `1` and `512`. Specifies the maximum number of live migrations that can be performed at the same time on the HyperV host machine.",
+			},
+			"performance_option": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VMMigrationPerformance_name[api.VMMigrationPerformance_Compression],
+				ValidateDiagFunc: stringKeyInMap(api.VMMigrationPerformance_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the performance option to use for live migrations. Valid values to use are `TCPIP`, `Compression`, `SMB`.",
+			},
+			"use_any_network_for_migration": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether any available network on the HyperV host machine can be used for live migrations. When `false` only the networks in `migration_networks` are used.",
+			},
+			"migration_networks": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Specifies the subnets, in CIDR notation e.g. `192.168.10.0/24`, that can be used for live migrations, in order of priority. Only used when `use_any_network_for_migration` is `false`.",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: IsCIDR(),
+				},
+			},
+		},
+	}
+}
+
+func resourceHyperVHostLiveMigrationSettingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv host live migration settings: %#v", d)
+	c := meta.(api.Client)
+
+	vmHost, err := c.GetVMHost(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	diags := resourceHyperVHostLiveMigrationSettingsApply(ctx, d, c)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(vmHost.ComputerName)
+	log.Printf("[INFO][hyperv][create] created hyperv host live migration settings: %#v", d)
+
+	return resourceHyperVHostLiveMigrationSettingsRead(ctx, d, meta)
+}
+
+func resourceHyperVHostLiveMigrationSettingsApply(ctx context.Context, d *schema.ResourceData, c api.Client) diag.Diagnostics {
+	enabled := (d.Get("enabled")).(bool)
+	authenticationType := api.ToVMMigrationAuthenticationType((d.Get("authentication_type")).(string))
+	maximumVirtualMachineMigrations := (d.Get("maximum_virtual_machine_migrations")).(int)
+	performanceOption := api.ToVMMigrationPerformance((d.Get("performance_option")).(string))
+	useAnyNetworkForMigration := (d.Get("use_any_network_for_migration")).(bool)
+	migrationNetworks := []string{}
+	if raw, ok := d.GetOk("migration_networks"); ok {
+		for _, v := range raw.([]interface{}) {
+			migrationNetworks = append(migrationNetworks, v.(string))
+		}
+	}
+
+	if enabled && !useAnyNetworkForMigration && len(migrationNetworks) < 1 {
+		return diag.Errorf("[ERROR][hyperv] Must specify MigrationNetworks if UseAnyNetworkForMigration is false")
+	}
+
+	err := c.UpdateVMHostLiveMigration(ctx, enabled, authenticationType, maximumVirtualMachineMigrations, performanceOption, useAnyNetworkForMigration, migrationNetworks)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceHyperVHostLiveMigrationSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv host live migration settings: %#v", d)
+	c := meta.(api.Client)
+
+	liveMigration, err := c.GetVMHostLiveMigration(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host live migration settings: %+v", liveMigration)
+
+	if err := d.Set("enabled", liveMigration.VirtualMachineMigrationEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("authentication_type", liveMigration.VirtualMachineMigrationAuthenticationType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("maximum_virtual_machine_migrations", liveMigration.MaximumVirtualMachineMigrations); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("performance_option", liveMigration.VirtualMachineMigrationPerformanceOption.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("use_any_network_for_migration", liveMigration.UseAnyNetworkForMigration); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("migration_networks", liveMigration.MigrationNetworks); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv host live migration settings: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVHostLiveMigrationSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv host live migration settings: %#v", d)
+	c := meta.(api.Client)
+
+	diags := resourceHyperVHostLiveMigrationSettingsApply(ctx, d, c)
+	if diags.HasError() {
+		return diags
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv host live migration settings: %#v", d)
+
+	return resourceHyperVHostLiveMigrationSettingsRead(ctx, d, meta)
+}
+
+func resourceHyperVHostLiveMigrationSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv host live migration settings: %#v", d)
+
+	// live migration settings always exist, so they are left as they are and only removed from the state
+	d.SetId("")
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv host live migration settings: %#v", d)
+	return nil
+}
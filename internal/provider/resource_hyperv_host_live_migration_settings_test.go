@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceHostLiveMigrationSettings(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_HOST_SETTINGS")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceHostLiveMigrationSettingsConfig(2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_host_live_migration_settings.this", "enabled", "true"),
+					resource.TestCheckResourceAttr("hyperv_host_live_migration_settings.this", "maximum_virtual_machine_migrations", "2"),
+				),
+			},
+			{
+				Config: testHyperVResourceHostLiveMigrationSettingsConfig(3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_host_live_migration_settings.this", "maximum_virtual_machine_migrations", "3"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceHostLiveMigrationSettingsConfig(maximumVirtualMachineMigrations int) string {
+	return fmt.Sprintf(`
+resource "hyperv_host_live_migration_settings" "this" {
+	enabled                            = true
+	authentication_type                = "Kerberos"
+	maximum_virtual_machine_migrations = %d
+	use_any_network_for_migration      = true
+}
+	`, maximumVirtualMachineMigrations)
+}
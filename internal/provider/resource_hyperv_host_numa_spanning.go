@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadHostNumaSpanningTimeout   = 1 * time.Minute
+	CreateHostNumaSpanningTimeout = 2 * time.Minute
+	UpdateHostNumaSpanningTimeout = 2 * time.Minute
+	DeleteHostNumaSpanningTimeout = 1 * time.Minute
+)
+
+func resourceHyperVHostNumaSpanning() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage how the HyperV host machine hands out memory to virtual machines, e.g. to keep latency sensitive virtual machines within one NUMA node on a host dedicated to them. NUMA spanning only applies once the Hyper-V virtual machine management service is restarted and the memory reserve of the root partition once the HyperV host machine is restarted, the provider does neither, `restart_pending` tells when a restart is still needed. Changes made outside of Terraform are applied already as far as the provider can tell. There is only one instance of these settings, so only one of these resources should be declared per host, and `numa_spanning_enabled` should not be set in `hyperv_host_settings` as well. Destroying the resource leaves the settings unchanged. The ID is the name of the HyperV host machine.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadHostNumaSpanningTimeout),
+			Create: schema.DefaultTimeout(CreateHostNumaSpanningTimeout),
+			Update: schema.DefaultTimeout(UpdateHostNumaSpanningTimeout),
+			Delete: schema.DefaultTimeout(DeleteHostNumaSpanningTimeout),
+		},
+		CreateContext: resourceHyperVHostNumaSpanningCreate,
+		ReadContext:   resourceHyperVHostNumaSpanningRead,
+		UpdateContext: resourceHyperVHostNumaSpanningUpdate,
+		DeleteContext: resourceHyperVHostNumaSpanningDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"computer_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the HyperV host machine.",
+			},
+			"numa_spanning_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether virtual machines on the HyperV host machine can use memory from more than one NUMA node. When `false` a virtual machine that does not fit in the free memory of one NUMA node does not start, rather than run with the slower memory of another NUMA node.",
+			},
+			"memory_reserve_mb": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, api.MemoryReserveMaximumMb),
+				Description:      "Specifies the memory in megabytes that is kept for the root partition of the HyperV host machine and can not be used by virtual machines. It is the `MemoryReserve` value under `HKLM\\SOFTWARE\\Microsoft\\Windows NT\\CurrentVersion\\Virtualization`. `0` removes the value, so that Hyper-V computes the reserve from the memory of the HyperV host machine.",
+			},
+			"restart_pending": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the HyperV host machine has to be restarted to apply `numa_spanning_enabled` or `memory_reserve_mb`. A change to `numa_spanning_enabled` alone is also applied by restarting the `vmms` service.",
+			},
+		},
+	}
+}
+
+func expandHostNumaSpanning(d *schema.ResourceData) (api.HostNumaSpanning, error) {
+	hostNumaSpanning := api.HostNumaSpanning{
+		NumaSpanningEnabled: (d.Get("numa_spanning_enabled")).(bool),
+		MemoryReserveMb:     (d.Get("memory_reserve_mb")).(int),
+	}
+
+	return hostNumaSpanning, hostNumaSpanning.Validate()
+}
+
+func resourceHyperVHostNumaSpanningCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv host numa spanning: %#v", d)
+	c := meta.(api.Client)
+
+	hostNumaSpanning, err := expandHostNumaSpanning(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.UpdateHostNumaSpanning(ctx, hostNumaSpanning)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing, err := c.GetHostNumaSpanning(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(existing.ComputerName)
+	log.Printf("[INFO][hyperv][create] created hyperv host numa spanning: %#v", d)
+
+	return resourceHyperVHostNumaSpanningRead(ctx, d, meta)
+}
+
+func resourceHyperVHostNumaSpanningRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv host numa spanning: %#v", d)
+	c := meta.(api.Client)
+
+	hostNumaSpanning, err := c.GetHostNumaSpanning(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host numa spanning: %+v", hostNumaSpanning)
+
+	if hostNumaSpanning.RestartPending() {
+		log.Printf("[WARN][hyperv][read] hyperv host machine %s has to be restarted to apply its numa spanning settings", hostNumaSpanning.ComputerName)
+	}
+
+	if err := d.Set("computer_name", hostNumaSpanning.ComputerName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("numa_spanning_enabled", hostNumaSpanning.NumaSpanningEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_reserve_mb", hostNumaSpanning.MemoryReserveMb); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("restart_pending", hostNumaSpanning.RestartPending()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv host numa spanning: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVHostNumaSpanningUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv host numa spanning: %#v", d)
+	c := meta.(api.Client)
+
+	hostNumaSpanning, err := expandHostNumaSpanning(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.UpdateHostNumaSpanning(ctx, hostNumaSpanning)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv host numa spanning: %#v", d)
+
+	return resourceHyperVHostNumaSpanningRead(ctx, d, meta)
+}
+
+func resourceHyperVHostNumaSpanningDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv host numa spanning: %#v", d)
+
+	// the settings of the host always exist, so they are left as they are and only removed from the state
+	d.SetId("")
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv host numa spanning: %#v", d)
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceHostNumaSpanning(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceHostNumaSpanningConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("hyperv_host_numa_spanning.this", "numa_spanning_enabled", "data.hyperv_host_numa_topology.this", "numa_spanning_enabled"),
+					resource.TestCheckResourceAttrSet("hyperv_host_numa_spanning.this", "computer_name"),
+					resource.TestCheckResourceAttr("hyperv_host_numa_spanning.this", "restart_pending", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceHostNumaSpanningConfig() string {
+	return `
+data "hyperv_host_numa_topology" "this" {
+}
+
+# numa spanning is left as the HyperV host machine has it, so that it does not have to be restarted
+resource "hyperv_host_numa_spanning" "this" {
+	numa_spanning_enabled = data.hyperv_host_numa_topology.this.numa_spanning_enabled
+}
+	`
+}
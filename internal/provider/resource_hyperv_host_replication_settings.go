@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadHostReplicationSettingsTimeout   = 1 * time.Minute
+	CreateHostReplicationSettingsTimeout = 2 * time.Minute
+	UpdateHostReplicationSettingsTimeout = 2 * time.Minute
+	DeleteHostReplicationSettingsTimeout = 1 * time.Minute
+)
+
+func resourceHyperVHostReplicationSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to configure the HyperV host machine as a Hyper-V Replica server, so that it can receive replication of virtual machines from primary servers. There is only one instance of the replication settings, so only one of these resources should be declared per host. Destroying the resource leaves the replication settings unchanged.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadHostReplicationSettingsTimeout),
+			Create: schema.DefaultTimeout(CreateHostReplicationSettingsTimeout),
+			Update: schema.DefaultTimeout(UpdateHostReplicationSettingsTimeout),
+			Delete: schema.DefaultTimeout(DeleteHostReplicationSettingsTimeout),
+		},
+		CreateContext: resourceHyperVHostReplicationSettingsCreate,
+		ReadContext:   resourceHyperVHostReplicationSettingsRead,
+		UpdateContext: resourceHyperVHostReplicationSettingsUpdate,
+		DeleteContext: resourceHyperVHostReplicationSettingsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"replication_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether the HyperV host machine is enabled as a replica server.",
+			},
+			"allowed_authentication_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VMReplicationServerAuthenticationType_name[api.VMReplicationServerAuthenticationType_Kerberos],
+				ValidateDiagFunc: stringKeyInMap(api.VMReplicationServerAuthenticationType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies which authentication types the replica server accepts. Valid values to use are `Kerberos`, `Certificate`, `CertificateAndKerberos`.",
+			},
+			"kerberos_authentication_port": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          80,
+				ValidateDiagFunc: IntBetween(1, 65535),
+				Description:      "Specifies the port that the replica server listens on for Kerberos authenticated replication traffic.",
+			},
+			"certificate_authentication_port": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          443,
+				ValidateDiagFunc: IntBetween(1, 65535),
+				Description:      "Specifies the port that the replica server listens on for certificate authenticated replication traffic.",
+			},
+			"certificate_thumbprint": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the thumbprint of the certificate the replica server uses for certificate authentication. Required when `allowed_authentication_type` is `Certificate` or `CertificateAndKerberos`.",
+			},
+			"replication_allowed_from_any_server": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether replication is accepted from any primary server. When `false` replication is only accepted from the primary servers that have an authorization entry.",
+			},
+			"default_storage_location": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the default folder to store replica virtual machines in. Required when `replication_allowed_from_any_server` is `true`.",
+			},
+		},
+	}
+}
+
+func resourceHyperVHostReplicationSettingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv host replication settings: %#v", d)
+	c := meta.(api.Client)
+
+	vmHost, err := c.GetVMHost(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	diags := resourceHyperVHostReplicationSettingsApply(ctx, d, c)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(vmHost.ComputerName)
+	log.Printf("[INFO][hyperv][create] created hyperv host replication settings: %#v", d)
+
+	return resourceHyperVHostReplicationSettingsRead(ctx, d, meta)
+}
+
+func resourceHyperVHostReplicationSettingsApply(ctx context.Context, d *schema.ResourceData, c api.Client) diag.Diagnostics {
+	replicationEnabled := (d.Get("replication_enabled")).(bool)
+	allowedAuthenticationType := api.ToVMReplicationServerAuthenticationType((d.Get("allowed_authentication_type")).(string))
+	kerberosAuthenticationPort := (d.Get("kerberos_authentication_port")).(int)
+	certificateAuthenticationPort := (d.Get("certificate_authentication_port")).(int)
+	certificateThumbprint := (d.Get("certificate_thumbprint")).(string)
+	replicationAllowedFromAnyServer := (d.Get("replication_allowed_from_any_server")).(bool)
+	defaultStorageLocation := (d.Get("default_storage_location")).(string)
+
+	if allowedAuthenticationType != api.VMReplicationServerAuthenticationType_Kerberos && certificateThumbprint == "" {
+		return diag.Errorf("[ERROR][hyperv] Must specify CertificateThumbprint if AllowedAuthenticationType includes certificate")
+	}
+
+	if replicationAllowedFromAnyServer && defaultStorageLocation == "" {
+		return diag.Errorf("[ERROR][hyperv] Must specify DefaultStorageLocation if ReplicationAllowedFromAnyServer is true")
+	}
+
+	err := c.UpdateVMReplicationServer(ctx, replicationEnabled, allowedAuthenticationType, kerberosAuthenticationPort, certificateAuthenticationPort, certificateThumbprint, replicationAllowedFromAnyServer, defaultStorageLocation)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceHyperVHostReplicationSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv host replication settings: %#v", d)
+	c := meta.(api.Client)
+
+	vmReplicationServer, err := c.GetVMReplicationServer(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host replication settings: %+v", vmReplicationServer)
+
+	if err := d.Set("replication_enabled", vmReplicationServer.ReplicationEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("allowed_authentication_type", vmReplicationServer.AllowedAuthenticationType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("kerberos_authentication_port", vmReplicationServer.KerberosAuthenticationPort); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("certificate_authentication_port", vmReplicationServer.CertificateAuthenticationPort); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("certificate_thumbprint", vmReplicationServer.CertificateThumbprint); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("replication_allowed_from_any_server", vmReplicationServer.ReplicationAllowedFromAnyServer); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("default_storage_location", vmReplicationServer.DefaultStorageLocation); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv host replication settings: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVHostReplicationSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv host replication settings: %#v", d)
+	c := meta.(api.Client)
+
+	diags := resourceHyperVHostReplicationSettingsApply(ctx, d, c)
+	if diags.HasError() {
+		return diags
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv host replication settings: %#v", d)
+
+	return resourceHyperVHostReplicationSettingsRead(ctx, d, meta)
+}
+
+func resourceHyperVHostReplicationSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv host replication settings: %#v", d)
+
+	// replication settings always exist, so they are left as they are and only removed from the state
+	d.SetId("")
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv host replication settings: %#v", d)
+	return nil
+}
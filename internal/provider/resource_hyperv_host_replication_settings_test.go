@@ -0,0 +1,49 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceHostReplicationSettings(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	path := testAccPath("replica")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_HOST_SETTINGS")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceHostReplicationSettingsConfig(path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_host_replication_settings.this", "replication_enabled", "true"),
+					resource.TestCheckResourceAttr("hyperv_host_replication_settings.this", "default_storage_location", path),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceHostReplicationSettingsConfig(path string) string {
+	return fmt.Sprintf(`
+resource "hyperv_host_replication_settings" "this" {
+	replication_enabled                 = true
+	allowed_authentication_type         = "Kerberos"
+	kerberos_authentication_port        = 80
+	replication_allowed_from_any_server = true
+	default_storage_location            = "%s"
+}
+	`, escapeForHcl(path))
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadHostSettingsTimeout   = 1 * time.Minute
+	CreateHostSettingsTimeout = 2 * time.Minute
+	UpdateHostSettingsTimeout = 2 * time.Minute
+	DeleteHostSettingsTimeout = 1 * time.Minute
+)
+
+var hostSettingsMacAddressRegexp = regexp.MustCompile(`^[0-9A-Fa-f]{12}$`)
+
+func resourceHyperVHostSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage the settings of the HyperV host machine. There is only one instance of the host settings, so only one of these resources should be declared per host. Settings that are not specified are left as they are, and destroying the resource leaves the host settings unchanged.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadHostSettingsTimeout),
+			Create: schema.DefaultTimeout(CreateHostSettingsTimeout),
+			Update: schema.DefaultTimeout(UpdateHostSettingsTimeout),
+			Delete: schema.DefaultTimeout(DeleteHostSettingsTimeout),
+		},
+		CreateContext: resourceHyperVHostSettingsCreate,
+		ReadContext:   resourceHyperVHostSettingsRead,
+		UpdateContext: resourceHyperVHostSettingsUpdate,
+		DeleteContext: resourceHyperVHostSettingsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"computer_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the HyperV host machine.",
+			},
+			"virtual_hard_disk_path": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the default folder to store virtual hard disks on the HyperV host machine.",
+			},
+			"virtual_machine_path": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the default folder to store virtual machine configuration files on the HyperV host machine.",
+			},
+			"numa_spanning_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Specifies whether virtual machines on the HyperV host machine can use resources from more than one NUMA node. It only applies once the Hyper-V virtual machine management service is restarted, use `hyperv_host_numa_spanning` instead to be told when a restart is still needed.",
+			},
+			"enable_enhanced_session_mode": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Specifies whether users can use enhanced mode when they connect to virtual machines on the HyperV host machine by using Virtual Machine Connection.",
+			},
+			"resource_metering_save_interval_hours": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: IntBetween(1, 8760),
+				Description:      "Should be a value between `1` and `8760`. Specifies how often, in hours, the HyperV host machine saves the data that tracks resource usage.",
+			},
+			"mac_address_minimum": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: StringMatch(hostSettingsMacAddressRegexp, "expected a MAC address of 12 hexadecimal digits without separators"),
+				Description:      "Specifies the minimum MAC address of the range of dynamic MAC addresses that can be assigned to virtual machines on the HyperV host machine e.g. `00155D010000`.",
+			},
+			"mac_address_maximum": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: StringMatch(hostSettingsMacAddressRegexp, "expected a MAC address of 12 hexadecimal digits without separators"),
+				Description:      "Specifies the maximum MAC address of the range of dynamic MAC addresses that can be assigned to virtual machines on the HyperV host machine e.g. `00155D01FFFF`.",
+			},
+		},
+	}
+}
+
+// getHostSettingsBool returns the configured value for key, or current if the value has not been configured.
+func getHostSettingsBool(d *schema.ResourceData, key string, current bool) bool {
+	v := d.GetRawConfig().GetAttr(key)
+	if v.IsNull() || !v.IsKnown() {
+		return current
+	}
+
+	return v.True()
+}
+
+func resourceHyperVHostSettingsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv host settings: %#v", d)
+	c := meta.(api.Client)
+
+	vmHost, err := c.GetVMHost(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	virtualHardDiskPath := (d.Get("virtual_hard_disk_path")).(string)
+	virtualMachinePath := (d.Get("virtual_machine_path")).(string)
+	numaSpanningEnabled := getHostSettingsBool(d, "numa_spanning_enabled", vmHost.NumaSpanningEnabled)
+	enableEnhancedSessionMode := getHostSettingsBool(d, "enable_enhanced_session_mode", vmHost.EnableEnhancedSessionMode)
+	resourceMeteringSaveIntervalHours := (d.Get("resource_metering_save_interval_hours")).(int)
+	macAddressMinimum := (d.Get("mac_address_minimum")).(string)
+	macAddressMaximum := (d.Get("mac_address_maximum")).(string)
+
+	err = c.UpdateVMHost(ctx, virtualHardDiskPath, virtualMachinePath, numaSpanningEnabled, enableEnhancedSessionMode, resourceMeteringSaveIntervalHours, macAddressMinimum, macAddressMaximum)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmHost.ComputerName)
+	log.Printf("[INFO][hyperv][create] created hyperv host settings: %#v", d)
+
+	return resourceHyperVHostSettingsRead(ctx, d, meta)
+}
+
+func resourceHyperVHostSettingsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv host settings: %#v", d)
+	c := meta.(api.Client)
+
+	vmHost, err := c.GetVMHost(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved host settings: %+v", vmHost)
+
+	if err := d.Set("computer_name", vmHost.ComputerName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("virtual_hard_disk_path", vmHost.VirtualHardDiskPath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("virtual_machine_path", vmHost.VirtualMachinePath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("numa_spanning_enabled", vmHost.NumaSpanningEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enable_enhanced_session_mode", vmHost.EnableEnhancedSessionMode); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("resource_metering_save_interval_hours", vmHost.ResourceMeteringSaveIntervalHours); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mac_address_minimum", vmHost.MacAddressMinimum); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("mac_address_maximum", vmHost.MacAddressMaximum); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv host settings: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVHostSettingsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv host settings: %#v", d)
+	c := meta.(api.Client)
+
+	virtualHardDiskPath := (d.Get("virtual_hard_disk_path")).(string)
+	virtualMachinePath := (d.Get("virtual_machine_path")).(string)
+	numaSpanningEnabled := (d.Get("numa_spanning_enabled")).(bool)
+	enableEnhancedSessionMode := (d.Get("enable_enhanced_session_mode")).(bool)
+	resourceMeteringSaveIntervalHours := (d.Get("resource_metering_save_interval_hours")).(int)
+	macAddressMinimum := (d.Get("mac_address_minimum")).(string)
+	macAddressMaximum := (d.Get("mac_address_maximum")).(string)
+
+	err := c.UpdateVMHost(ctx, virtualHardDiskPath, virtualMachinePath, numaSpanningEnabled, enableEnhancedSessionMode, resourceMeteringSaveIntervalHours, macAddressMinimum, macAddressMaximum)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv host settings: %#v", d)
+
+	return resourceHyperVHostSettingsRead(ctx, d, meta)
+}
+
+func resourceHyperVHostSettingsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv host settings: %#v", d)
+
+	// host settings always exist, so they are left as they are and only removed from the state
+	d.SetId("")
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv host settings: %#v", d)
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceHostSettings(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceHostSettingsConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("hyperv_host_settings.this", "virtual_hard_disk_path", "data.hyperv_host.this", "virtual_hard_disk_path"),
+					resource.TestCheckResourceAttrSet("hyperv_host_settings.this", "computer_name"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceHostSettingsConfig() string {
+	return `
+data "hyperv_host" "this" {
+}
+
+# every setting is optional and computed, so the current settings of the HyperV host machine are left as they are
+resource "hyperv_host_settings" "this" {
+}
+	`
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadIsoLibraryTimeout   = 1 * time.Minute
+	CreateIsoLibraryTimeout = 30 * time.Minute
+	UpdateIsoLibraryTimeout = 30 * time.Minute
+	DeleteIsoLibraryTimeout = 1 * time.Minute
+)
+
+// the name of an iso library is used as the file name of its catalog on the HyperV host machine
+var isoLibraryNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+var isoChecksumRegexp = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+func resourceHyperVIsoLibrary() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a catalog of iso files on the HyperV host machine, so that dvd drives can reference an iso by name instead of by path. The checksum of every iso is verified when the catalog is created or updated. The catalog is stored in `%ProgramData%\\terraform-provider-hyperv\\iso-libraries` on the HyperV host machine, destroying the resource removes the catalog but leaves the iso files as they are. When the same isos are used by vms on many HyperV host machines, declare a library with a `cache_directory` for each host machine, e.g. with a provider alias per host machine, and keep the isos on a share: each iso is copied from the share to every host machine once and verified by checksum, so the isos do not have to be staged on the host machines by hand.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadIsoLibraryTimeout),
+			Create: schema.DefaultTimeout(CreateIsoLibraryTimeout),
+			Update: schema.DefaultTimeout(UpdateIsoLibraryTimeout),
+			Delete: schema.DefaultTimeout(DeleteIsoLibraryTimeout),
+		},
+		CreateContext: resourceHyperVIsoLibraryCreate,
+		ReadContext:   resourceHyperVIsoLibraryRead,
+		UpdateContext: resourceHyperVIsoLibraryUpdate,
+		DeleteContext: resourceHyperVIsoLibraryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: StringMatch(isoLibraryNameRegexp, "expected a name of letters, digits, underscores, hyphens and periods"),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the iso library. Dvd drives reference the library by this name.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies a description of the iso library.",
+			},
+			"cache_directory": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies a directory on the HyperV host machine to copy the isos of the library to, e.g. `C:\\IsoCache`. Copies are named by checksum, so an iso is only copied once however many libraries and dvd drives use it, and a copy is only used once its checksum has been verified. Dvd drives that reference an iso of the library insert the copy instead of the iso at `path`. Copies are left in the directory when the library is destroyed, as other libraries may use them. When empty the isos are used where they are.",
+			},
+			"iso": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The iso files in the library.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+							Description:      "Specifies the name dvd drives use to reference the iso e.g. `ubuntu-22.04`. Names must be unique in the library.",
+						},
+						"path": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: IsWindowsPath(),
+							DiffSuppressFunc: api.DiffSuppressWindowsPath,
+							Description:      "Specifies the path to the iso file on the HyperV host machine, either on a drive e.g. `C:\\Iso\\ubuntu-22.04.iso` or on a share e.g. `\\\\server\\share\\ubuntu-22.04.iso`.",
+						},
+						"checksum": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: StringMatch(isoChecksumRegexp, "expected a checksum of hexadecimal digits"),
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+							Description:      "Specifies the checksum of the iso file in hexadecimal. Creating or updating the library fails if the iso file does not match it.",
+						},
+						"checksum_type": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          api.IsoChecksumType_value["sha256"],
+							ValidateDiagFunc: stringKeyInMap(api.IsoChecksumType_value, true),
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+							Description:      "Specifies the algorithm of the checksum. Valid values to use are `SHA1`, `SHA256`, `SHA384`, `SHA512`, `MD5`.",
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The size of the iso file in bytes.",
+						},
+						"cached_path": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The path of the copy of the iso in `cache_directory`, empty when the library does not cache its isos.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceHyperVIsoLibraryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv iso library: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+
+	if d.IsNewResource() {
+		existing, err := c.GetIsoLibrary(ctx, name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", name, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", name, "hyperv_iso_library", "hyperv_iso_library", name))
+		}
+	}
+
+	diags := resourceHyperVIsoLibraryApply(ctx, d, c, name)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO][hyperv][create] created hyperv iso library: %#v", d)
+
+	return resourceHyperVIsoLibraryRead(ctx, d, meta)
+}
+
+func resourceHyperVIsoLibraryApply(ctx context.Context, d *schema.ResourceData, c api.Client, name string) diag.Diagnostics {
+	description := (d.Get("description")).(string)
+	cacheDirectory := (d.Get("cache_directory")).(string)
+	isos, err := api.ExpandIsoLibraryIsos(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.CreateOrUpdateIsoLibrary(ctx, name, description, cacheDirectory, isos)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceHyperVIsoLibraryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv iso library: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	isoLibrary, err := c.GetIsoLibrary(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved iso library: %+v", isoLibrary)
+
+	if isoLibrary.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv iso library as it does not exist: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", isoLibrary.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("description", isoLibrary.Description); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cache_directory", isoLibrary.CacheDirectory); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("iso", api.FlattenIsoLibraryIsos(isoLibrary.Isos)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv iso library: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVIsoLibraryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv iso library: %#v", d)
+	c := meta.(api.Client)
+
+	diags := resourceHyperVIsoLibraryApply(ctx, d, c, d.Id())
+	if diags.HasError() {
+		return diags
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv iso library: %#v", d)
+
+	return resourceHyperVIsoLibraryRead(ctx, d, meta)
+}
+
+func resourceHyperVIsoLibraryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv iso library: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteIsoLibrary(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv iso library: %#v", d)
+	return nil
+}
@@ -0,0 +1,120 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+var testAccCheckHyperVIsoLibraryDestroy = testAccCheckDestroy("hyperv_iso_library", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	isoLibrary, err := c.GetIsoLibrary(ctx, id)
+	return isoLibrary.Name != "", err
+})
+
+func TestHyperVResourceIsoLibrary(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("library")
+	path := testAccPath("library.iso")
+	content := []byte("terraform-provider-hyperv iso library acceptance test")
+
+	err := os.WriteFile(path, content, 0644)
+	if err != nil {
+		t.Fatalf("unable to write iso %s: %s", path, err)
+	}
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	checksum := sha256.Sum256(content)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVIsoLibraryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceIsoLibraryConfig(name, path, hex.EncodeToString(checksum[:]), ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "iso.#", "1"),
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "iso.0.name", "seed"),
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "iso.0.checksum_type", "SHA256"),
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "iso.0.size", fmt.Sprint(len(content))),
+				),
+			},
+			{
+				ResourceName:      "hyperv_iso_library.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestHyperVResourceIsoLibraryCache(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("cache")
+	path := testAccPath("cache.iso")
+	cacheDirectory := testAccPath("iso-cache")
+	content := []byte("terraform-provider-hyperv iso library cache acceptance test")
+
+	err := os.WriteFile(path, content, 0644)
+	if err != nil {
+		t.Fatalf("unable to write iso %s: %s", path, err)
+	}
+	t.Cleanup(func() {
+		_ = os.Remove(path)
+		_ = os.RemoveAll(cacheDirectory)
+	})
+
+	checksum := sha256.Sum256(content)
+	cachedPath := filepath.Join(cacheDirectory, strings.ToUpper(hex.EncodeToString(checksum[:]))+".iso")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVIsoLibraryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceIsoLibraryConfig(name, path, hex.EncodeToString(checksum[:]), cacheDirectory),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "cache_directory", cacheDirectory),
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "iso.0.cached_path", cachedPath),
+					resource.TestCheckResourceAttr("hyperv_iso_library.this", "iso.0.size", fmt.Sprint(len(content))),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceIsoLibraryConfig(name string, path string, checksum string, cacheDirectory string) string {
+	return fmt.Sprintf(`
+resource "hyperv_iso_library" "this" {
+	name            = "%s"
+	cache_directory = "%s"
+
+	iso {
+		name     = "seed"
+		path     = "%s"
+		checksum = "%s"
+	}
+}
+	`, escapeForHcl(name), escapeForHcl(cacheDirectory), escapeForHcl(path), checksum)
+}
@@ -2,8 +2,10 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -24,8 +26,9 @@ const (
 )
 
 func resourceHyperVMachineInstance() *schema.Resource {
-	return &schema.Resource{
-		Description: "This Hyper-V resource allows you to manage virtual machine instances.",
+	resource := &schema.Resource{
+		Description:   "This Hyper-V resource allows you to manage virtual machine instances.",
+		SchemaVersion: 1,
 		Timeouts: &schema.ResourceTimeout{
 			Read:   schema.DefaultTimeout(ReadMachineInstanceTimeout),
 			Create: schema.DefaultTimeout(CreateMachineInstanceTimeout),
@@ -36,14 +39,16 @@ func resourceHyperVMachineInstance() *schema.Resource {
 		ReadContext:   resourceHyperVMachineInstanceRead,
 		UpdateContext: resourceHyperVMachineInstanceUpdate,
 		DeleteContext: resourceHyperVMachineInstanceDelete,
+		CustomizeDiff: customizeDiffForMachineInstance,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Specifies the name of the new virtual machine.",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the new virtual machine.",
 			},
 
 			"path": {
@@ -74,7 +79,28 @@ func resourceHyperVMachineInstance() *schema.Resource {
 
 					return false
 				},
-				Description: "The path of the virtual machine.",
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "The path of the virtual machine.",
+			},
+
+			"cluster_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the failover cluster the HyperV host machine belongs to. When set, the cluster node that is up with the most available memory and the online cluster shared volume with the most free space are chosen when the virtual machine is created. If `path` is not set, the virtual machine is placed on the chosen cluster shared volume.",
+			},
+
+			"cluster_node": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The cluster node chosen for the virtual machine when `cluster_name` is set. Use it as a preferred owner in `hyperv_cluster_vm_role`.",
+			},
+
+			"cluster_shared_volume_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The path of the cluster shared volume chosen for the virtual machine when `cluster_name` is set. Use it to place the virtual machine's VHDs on the same volume.",
 			},
 
 			"generation": {
@@ -86,11 +112,22 @@ func resourceHyperVMachineInstance() *schema.Resource {
 				Description:      "Specifies the generation, as an integer, for the virtual machine. Valid values to use are `1`, `2`.",
 			},
 
+			"guest_state_isolation_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.GuestStateIsolationType_name[api.GuestStateIsolationType_Disabled],
+				ForceNew:         true,
+				ValidateDiagFunc: stringKeyInMap(api.GuestStateIsolationType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies how the guest state of the virtual machine is isolated from the HyperV host machine, for confidential guests. `TrustedLaunch` runs a paravisor in the virtual machine that protects its firmware, secure boot and TPM state, `VBS`, `SNP` and `TDX` isolate it with virtualization based security or the memory encryption of AMD SEV-SNP or Intel TDX processors. It needs a generation 2 virtual machine on Windows Server 2025 or later, use `hyperv_vm_generation_support` to find the types the HyperV host machine supports, and it can only be chosen when the virtual machine is created. Valid values to use are `Disabled`, `TrustedLaunch`, `VBS`, `SNP`, `TDX`.",
+			},
+
 			"automatic_critical_error_action": {
 				Type:             schema.TypeString,
 				Optional:         true,
 				Default:          api.CriticalErrorAction_name[api.CriticalErrorAction_Pause],
 				ValidateDiagFunc: stringKeyInMap(api.CriticalErrorAction_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 				Description:      "Specifies the action to take when the VM encounters a critical error, and exceeds the timeout duration specified by the AutomaticCriticalErrorActionTimeout cmdlet. Valid values to use are `Pause`, `None`.",
 			},
 
@@ -106,6 +143,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 				Optional:         true,
 				Default:          api.StartAction_name[api.StartAction_StartIfRunning],
 				ValidateDiagFunc: stringKeyInMap(api.StartAction_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 				Description:      "Specifies the action the virtual machine is to take upon start. Valid values to use are `Nothing`, `StartIfRunning`, `Start`.",
 			},
 
@@ -121,17 +159,69 @@ func resourceHyperVMachineInstance() *schema.Resource {
 				Optional:         true,
 				Default:          api.StopAction_name[api.StopAction_Save],
 				ValidateDiagFunc: stringKeyInMap(api.StopAction_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 				Description:      "Specifies the action the virtual machine is to take when the virtual machine host shuts down. Valid values to use are `TurnOff`, `Save`, `ShutDown`.",
 			},
 
+			"avma_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateDiagFunc: StringMatch(api.AvmaKeyRegexp, "expected a product key of five groups of five letters and digits"),
+				Description:      "Specifies the automatic virtual machine activation (AVMA) key of the Windows Server edition of the guest e.g. `W3GNR-8DDXR-2TFRP-H8P33-DV9BG` for Windows Server 2022 Datacenter. The HyperV host machine must run an activated Datacenter edition of Windows Server. The key is published to the guest with key value pair exchange as `AvmaKey` under `HKLM\\SOFTWARE\\Microsoft\\Virtual Machine\\External`, so the `Key-Value Pair Exchange` integration service must be enabled. A vhd created with `windows_image` installs the key when Windows setup completes, so the guest activates without a manual step. Other guests have to install it themselves e.g. with `slmgr.vbs /ipk`.",
+			},
+
 			"checkpoint_type": {
 				Type:             schema.TypeString,
 				Optional:         true,
 				Default:          api.CheckpointType_name[api.CheckpointType_Production],
 				ValidateDiagFunc: stringKeyInMap(api.CheckpointType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 				Description:      "Allows you to configure the type of checkpoints created by Hyper-V. If `Disabled` is specified, block creation of checkpoints. If `Standard` is specified, create standard checkpoints. If `Production` is specified, create production checkpoints if supported by guest operating system. Otherwise, create standard checkpoints. If `ProductionOnly` is specified, create production checkpoints if supported by guest operating system. Otherwise, the operation fails. Valid values to use are `Disabled`, `Standard`, `Production`, `ProductionOnly`.",
 			},
 
+			"checkpoint_before_update": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether to take a checkpoint of the virtual machine before applying an update that turns it off, e.g. a change to `vm_firmware`, `hard_disk_drives` or `vm_processor`, so the virtual machine can be restored if the update breaks it. The checkpoint is taken with the `checkpoint_type` of the virtual machine, so use `Production` or `ProductionOnly` for a checkpoint that is consistent for the applications in the guest operating system. Checkpoints are named `terraform-before-update-<time>`.",
+			},
+
+			"checkpoint_before_update_retention": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, 50),
+				Description:      "Specifies how many of the checkpoints taken by `checkpoint_before_update` to keep. After an update has been applied successfully the older ones are removed, so that only this many of the newest are kept. Checkpoints taken by hand are never removed. `0` keeps all of them.",
+			},
+
+			"last_update_checkpoint_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the last checkpoint taken by `checkpoint_before_update`.",
+			},
+
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether the machine instance is protected from being destroyed. The protection is recorded on the virtual machine as the `TerraformDeletionProtection` key value pair published to the guest, so the provider refuses to delete it even when it is destroyed by another configuration or its state was lost. Set it to `false` and apply before destroying the machine instance.",
+			},
+
+			"delete_vhds_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Specifies whether the virtual hard disks attached to the machine instance, together with the ones of its checkpoints, are deleted when it is destroyed, instead of being left behind on the HyperV host machine. Parents of differencing disks are kept, as other virtual machines may be based on them. Do not enable it when the virtual hard disks are managed by `hyperv_vhd` resources. Defaults to `delete_vhds_on_destroy` in the `features` block of the provider.",
+			},
+
+			"delete_seed_iso_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether the seed iso created for `cloud_init` is deleted when the machine instance is destroyed. Keep it to investigate how a machine instance was provisioned.",
+			},
+
 			"dynamic_memory": {
 				Type:         schema.TypeBool,
 				Optional:     true,
@@ -159,6 +249,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 				Optional:         true,
 				Default:          api.OnOffState_name[api.OnOffState_Off],
 				ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 				Description:      "Specifies whether virtual machine connection in basic mode locks the console after a user disconnects. Valid values to use are `On`, `Off`.",
 			},
 
@@ -190,6 +281,29 @@ func resourceHyperVMachineInstance() *schema.Resource {
 				Description: "Specifies the amount of memory that the virtual machine is to be allocated upon startup. (If the virtual machine does not use dynamic memory, then this is the static amount of memory to be allocated.)",
 			},
 
+			"memory_resource_pool_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the memory resource pool the virtual machine is to be associated with, see `hyperv_resource_pool`. When empty the virtual machine uses the default resource pool.",
+			},
+
+			"memory_maximum_amount_per_numa_node_bytes": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0, // Dynamic value
+				DiffSuppressFunc: api.DiffSuppressVmMemoryMaximumAmountPerNumaNodeBytes,
+				Description:      "Specifies the maximum amount of memory per virtual NUMA node of the virtual machine, which together with `maximum_count_per_numa_node` of `vm_processor` shapes the NUMA topology the guest sees. When `0` the size of the largest NUMA node of the HyperV host machine is used, see `hyperv_host_numa_topology`.",
+			},
+
+			"memory_preferred_numa_nodes": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt, ValidateDiagFunc: IntBetween(0, 63)},
+				Description: "Specifies the ids of the NUMA nodes of the HyperV host machine the memory of the virtual machine is preferably allocated from, so a latency sensitive guest can be pinned to the NUMA node its processors run on, see `hyperv_host_numa_topology`. The memory only stays on these NUMA nodes when NUMA spanning is disabled on the HyperV host machine. When empty the hypervisor picks the NUMA nodes.",
+			},
+
 			"notes": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -205,17 +319,21 @@ func resourceHyperVMachineInstance() *schema.Resource {
 			},
 
 			"smart_paging_file_path": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     `C:\ProgramData\Microsoft\Windows\Hyper-V`,
-				Description: "Specifies the folder in which the Smart Paging file is to be stored.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          `C:\ProgramData\Microsoft\Windows\Hyper-V`,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the folder in which the Smart Paging file is to be stored.",
 			},
 
 			"snapshot_file_location": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     `C:\ProgramData\Microsoft\Windows\Hyper-V`,
-				Description: "Specifies the folder in which the virtual machine is to store its snapshot files.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          `C:\ProgramData\Microsoft\Windows\Hyper-V`,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the folder in which the virtual machine is to store its snapshot files.",
 			},
 
 			"static_memory": {
@@ -240,7 +358,68 @@ func resourceHyperVMachineInstance() *schema.Resource {
 				Optional:         true,
 				Default:          api.VmState_name[api.VmState_Running],
 				ValidateDiagFunc: stringKeyInMap(api.VmState_SettableValue, true),
-				Description:      "Valid values to use are `Running`, `Off`. Specifies if the machine instance will be running or off.",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Valid values to use are `Running`, `Off`. Specifies if the machine instance will be running or off. A machine instance that is still starting or stopping is read as the state it is transitioning to, use `current_state` for the state it is actually in.",
+			},
+
+			"vm_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the machine instance assigned by HyperV. It stays the same when the machine instance is renamed, and is the id that the `hyperv_vm` data source, event logs and backup software refer to it by.",
+			},
+
+			"current_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state the machine instance is currently in, e.g. `Running`, `Off`, `Starting`, `Saved` or `Paused`.",
+			},
+
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operational status of the machine instance as reported by HyperV, e.g. `Operating normally`.",
+			},
+
+			"uptime_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of seconds the machine instance has been running for.",
+			},
+
+			"heartbeat": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The heartbeat of the guest operating system as reported by the `Heartbeat` integration service, e.g. `OkApplicationsHealthy`, `OkApplicationsUnknown`, `LostCommunication` or `NoContact`. Empty when the machine instance is not running.",
+			},
+
+			"integration_services_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Whether the integration services of the guest operating system are current, e.g. `Up to date` or `Update required`. Empty when the guest does not report them.",
+			},
+
+			"cpu_usage": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The percentage of the processor capacity of the HyperV host machine used by the machine instance.",
+			},
+
+			"memory_assigned_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The amount of memory currently assigned to the machine instance in bytes. This changes over time when `dynamic_memory` is enabled.",
+			},
+
+			"memory_demand_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The amount of memory the guest operating system of the machine instance currently demands in bytes.",
+			},
+
+			"resize_method": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the planned changes to `memory_startup_bytes` and `processor_count` will be applied. `None` when neither changes, `HotAdd` when memory is added while the machine instance keeps running, which HyperV only supports for a generation 2 machine instance with static memory, and `Offline` when the machine instance has to be turned off for the changes, e.g. to change the processor count. A hot add the guest operating system does not support falls back to an offline update.",
 			},
 
 			"wait_for_state_timeout": {
@@ -350,387 +529,123 @@ func resourceHyperVMachineInstance() *schema.Resource {
 							Type:        schema.TypeBool,
 							Optional:    true,
 							Default:     false,
-							Description: "Specifies whether the hypervisor should expose the presence of virtualization extensions to the virtual machine, which enables support for nested virtualization.",
-						},
-					},
-				},
-				Description: "",
-			},
-
-			"network_adaptors": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Specifies the name for the virtual network adapter.",
-						},
-						"switch_name": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "",
-							ForceNew:    false,
-							Description: "Specifies the name of the virtual switch to connect to the new network adapter. If the switch name is not unique, then the operation fails.",
-						},
-						"management_os": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "Specifies the virtual network adapter in the management operating system to be configured.",
-						},
-						"is_legacy": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							ForceNew:    true,
-							Description: "Specifies whether the virtual network adapter is the legacy type.",
-						},
-						"dynamic_mac_address": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     true,
-							Description: "Assigns a dynamically generated MAC address to the virtual network adapter.",
+							Description: "Specifies whether the hypervisor should expose the presence of virtualization extensions to the virtual machine, which enables support for nested virtualization. When `true` every network adapter connected to a switch must have `mac_address_spoofing` set to `On`, so nested virtual machines can use the network.",
 						},
-						"static_mac_address": {
+						"resource_pool_name": {
 							Type:             schema.TypeString,
 							Optional:         true,
 							Default:          "",
-							DiffSuppressFunc: api.DiffSuppressVmStaticMacAddress,
-							Description:      "Assigns a specific a MAC addresss to the virtual network adapter.",
-						},
-						"mac_address_spoofing": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.OnOffState_name[api.OnOffState_Off],
-							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
-							Description:      "Specifies whether virtual machines may change the source MAC address in outgoing packets to one not assigned to them. On allows the virtual machine to use a different MAC address. Off only allows the virtual machine to use the MAC address assigned to it. Valid values to use are `On`, `Off`.",
-						},
-						"dhcp_guard": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.OnOffState_name[api.OnOffState_Off],
-							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
-							Description:      "Specifies whether to drop DHCP messages from a virtual machine claiming to be a DHCP server. Valid values to use are `On`, `Off`.",
-						},
-						"router_guard": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.OnOffState_name[api.OnOffState_Off],
-							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
-							Description:      "Specifies whether to drop Router Advertisement and Redirection messages from unauthorized virtual machines. If On is specified, such messages are dropped. If Off is specified, such messages are sent. Valid values to use are `On`, `Off`.",
-						},
-						"port_mirroring": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.PortMirroring_name[api.PortMirroring_None],
-							ValidateDiagFunc: stringKeyInMap(api.PortMirroring_value, true),
-							Description:      "Specifies the port mirroring mode for the network adapter to be configured. If a virtual network adapter is configured as Source, every packet it sends or receives is copied and forwarded to a virtual network adapter configured to receive the packets. If a virtual network adapter is configured as Destination, it receives copied packets from the source virtual network adapter. The source and destination virtual network adapters must be connected to the same virtual switch. Specify None to disable the feature. Valid values to use are `None`, `Source`, `Destination`.",
-						},
-						"ieee_priority_tag": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.OnOffState_name[api.OnOffState_Off],
-							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
-							Description:      "Specifies whether IEEE 802.1p tagged packets from the virtual machine should be trusted. If it is on, the IEEE 802.1p tagged packets will be let go as is. If it is off, the priority value is reset to 0. Valid values to use are `On`, `Off`.",
-						},
-						"vmq_weight": {
-							Type:             schema.TypeInt,
-							Optional:         true,
-							Default:          100,
-							ValidateDiagFunc: IntBetween(0, 100),
-							Description:      "Specifies whether virtual machine queue (VMQ) is to be enabled on the virtual network adapter. The relative weight describes the affinity of the virtual network adapter to use VMQ. Specify 0 to disable VMQ on the virtual network adapter. Valid values to use are between `1` to `100`.",
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+							Description:      "Specifies the name of the processor resource pool the virtual machine is to be associated with, see `hyperv_resource_pool`. When empty the virtual machine uses the default resource pool.",
 						},
-						"iov_queue_pairs_requested": {
-							Type:             schema.TypeInt,
-							Optional:         true,
-							Default:          1,
-							ValidateDiagFunc: IntBetween(1, 4294967295),
-							Description:      "Specifies the number of hardware queue pairs to be allocated to an SR-IOV virtual function. If receive-side scaling (RSS) is required, and if the physical network adapter that binds to the virtual switch supports RSS on SR-IOV virtual functions, then more than one queue pair is required. Valid values to use are between `1` to `4294967295`.",
-						},
-						"iov_interrupt_moderation": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.IovInterruptModerationValue_name[api.IovInterruptModerationValue_Off],
-							ValidateDiagFunc: stringKeyInMap(api.IovInterruptModerationValue_value, true),
-							Description:      "Specifies the interrupt moderation value for a single-root I/O virtualization (SR-IOV) virtual function assigned to a virtual network adapter. If Default is chosen, the value is determined by the physical network adapter vendor's setting. If Adaptive is chosen, the interrupt moderation rate will be based on the runtime traffic pattern. Valid values to use are `Default`, `Adaptive`, `Off`, `Low `, `Medium`, `High`.",
-						},
-						"iov_weight": {
-							Type:             schema.TypeInt,
-							Optional:         true,
-							Default:          100,
-							ValidateDiagFunc: IntBetween(0, 100),
-							Description:      "Specifies whether single-root I/O virtualization (SR-IOV) is to be enabled on this virtual network adapter. The relative weight sets the affinity of the virtual network adapter to the assigned SR-IOV virtual function. Specify 0 to disable SR-IOV on the virtual network adapter. Valid values to use are between `0` to `100`.",
-						},
-						"ipsec_offload_maximum_security_association": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     512,
-							Description: "Specifies the maximum number of security associations that can be offloaded to the physical network adapter that is bound to the virtual switch and that supports IPSec Task Offload. Specify zero to disable the feature.",
-						},
-						"maximum_bandwidth": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Specifies the maximum bandwidth, in bits per second, for the virtual network adapter. The specified value is rounded to the nearest multiple of eight. Specify zero to disable the feature.",
-						},
-						"minimum_bandwidth_absolute": {
-							Type:        schema.TypeInt,
+						"enable_legacy_apic_mode": {
+							Type:        schema.TypeBool,
 							Optional:    true,
-							Default:     0,
-							Description: "Specifies the minimum bandwidth, in bits per second, for the virtual network adapter. The specified value is rounded to the nearest multiple of eight. A value larger than 100 Mbps is recommended.",
+							Default:     false,
+							Description: "Specifies whether the virtual machine uses the legacy xAPIC mode instead of x2APIC, for guest operating systems that do not support x2APIC. Requires Windows Server 2019 or later.",
 						},
-						"minimum_bandwidth_weight": {
+
+						"l3_cache_ways": {
 							Type:             schema.TypeInt,
 							Optional:         true,
 							Default:          0,
-							ValidateDiagFunc: IntBetween(0, 100),
-							Description:      "Specifies the minimum bandwidth, in terms of relative weight, for the virtual network adapter. The weight describes how much bandwidth to provide to the virtual network adapter relative to other virtual network adapters connected to the same virtual switch. Specify 0 to disable the feature. Valid values to use are between `0` to `100`.",
+							ValidateDiagFunc: IntBetween(0, 64),
+							Description:      "Specifies the number of L3 cache ways the virtual machine is limited to, to stop it from evicting the cache of other virtual machines. If value is 0 then the virtual machine is not limited. Requires Windows Server 2019 or later.",
 						},
-						"mandatory_feature_id": {
+
+						"perfmon": {
 							Type:        schema.TypeSet,
 							Optional:    true,
-							Elem:        &schema.Schema{Type: schema.TypeString},
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: stringKeyInMap(api.VmProcessorPerfmon_value, false)},
 							Set:         schema.HashString,
-							Description: "Specifies the unique identifiers of the virtual switch extension features that are required for this virtual network adapter to operate.",
-						},
-						"resource_pool_name": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "",
-							Description: "Specifies the name of the resource pool.",
-						},
-						"test_replica_pool_name": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "",
-							Description: "This parameter applies only to virtual machines that are enabled for replication. It specifies the name of the network resource pool that will be used by this virtual network adapter when its virtual machine is created during a test failover.",
-						},
-						"test_replica_switch_name": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "",
-							Description: "This parameter applies only to virtual machines that are enabled for replication. It specifies the name of the virtual switch to which the virtual network adapter should be connected when its virtual machine is created during a test failover.",
-						},
-						"virtual_subnet_id": {
-							Type:             schema.TypeInt,
-							Optional:         true,
-							Default:          0,
-							ValidateDiagFunc: ValueOrIntBetween(0, 4096, 16777215),
-							Description:      "Specifies the virtual subnet ID to use with Hyper-V Network Virtualization. Use 0 to clear this parameter. Valid values to use are `0` or between `4096` to `16777215` (2^24 - 1).",
-						},
-						"allow_teaming": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.OnOffState_name[api.OnOffState_On],
-							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
-							Description:      "Specifies whether the virtual network adapter can be teamed with other network adapters connected to the same virtual switch. Valid values to use are `On`, `Off`.",
-						},
-						"not_monitored_in_cluster": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "Indicates whether to not monitor the network adapter if the virtual machine that it belongs to is part of a cluster. By default, network adapters for clustered virtual machines are monitored.",
-						},
-						"storm_limit": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Specifies the number of broadcast, multicast, and unknown unicast packets per second a virtual machine is allowed to send through the specified virtual network adapter. Broadcast, multicast, and unknown unicast packets beyond the limit during that one second interval are dropped. A value of zero (0) means there is no limit.",
-						},
-						"dynamic_ip_address_limit": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Specifies the dynamic IP address limit.",
-						},
-						"device_naming": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.OnOffState_name[api.OnOffState_Off],
-							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
-							Description:      "Specifies whether this adapter uses device naming. Valid values to use are `On`, `Off`.",
-						},
-						"fix_speed_10g": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.OnOffState_name[api.OnOffState_Off],
-							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
-							Description:      "Specifies whether the adapter uses fix speed of 10G. Valid values to use are `On`, `Off`.",
-						},
-						"packet_direct_num_procs": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Specifies the number of processors to use for virtual switch processing inside of the host.",
-						},
-						"packet_direct_moderation_count": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Specifies the number of packets to wait for before signaling an interrupt.",
-						},
-						"packet_direct_moderation_interval": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "Specifies the amount of time, in milliseconds, to wait before signaling an interrupt after a packet arrives.",
-						},
-						"vrss_enabled": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     true,
-							Description: "Should Virtual Receive Side Scaling be enabled. This configuration allows the load from a virtual network adapter to be distributed across multiple virtual processors in a virtual machine (VM), allowing the VM to process more network traffic more rapidly than it can with a single logical processor.",
-						},
-						"vmmq_enabled": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "Should Virtual Machine Multi-Queue be enabled. With set to true multiple queues are allocated to a single VM with each queue affinitized to a core in the VM.",
-						},
-						"vmmq_queue_pairs": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     16,
-							Description: "The number of Virtual Machine Multi-Queues to create for this VM.",
-						},
-						"vlan_access": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     false,
-							Description: "",
-						},
-						"vlan_id": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     0,
-							Description: "",
-						},
-						"wait_for_ips": {
-							Type:        schema.TypeBool,
-							Optional:    true,
-							Default:     true,
-							Description: "Wait for the network card to be assigned an ip address.",
-						},
-						"ip_addresses": {
-							Type:        schema.TypeList,
-							Computed:    true,
-							Elem:        &schema.Schema{Type: schema.TypeString},
-							Description: "The current list of IP addresses on this machine. If HyperV integration tools is not running on the virtual machine, or if the VM is powered off, or has not been assigned an ip address, this list will be empty.",
+							Description: "Specifies the performance monitoring features of the host processor that are exposed to the virtual machine, so monitoring agents and profilers in the guest can use the hardware performance counters. The virtual machine can not be live migrated to a host without the features. Valid values to use are `pmu` (performance monitoring unit), `lbr` (last branch record), `pebs` (precise event based sampling), `ipt` (Intel processor trace). Requires Windows Server 2019 or later.",
 						},
 					},
 				},
 				Description: "",
 			},
 
+			"network_adaptors": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: vmNetworkAdapterSchema(),
+				},
+				Description: "The network adapters of the virtual machine. Network adapters are matched to the adapters of the virtual machine by `name`, so reordering them does not replace them.",
+			},
+
 			"dvd_drives": {
 				Type:     schema.TypeList,
 				Optional: true,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"controller_number": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Specifies the number of the controller to which the DVD drive is to be added.",
-						},
-						"controller_location": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Specifies the number of the location on the controller at which the DVD drive is to be added.",
-						},
-						"path": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "",
-							Description: "Specifies the full path to the virtual hard disk file or physical hard disk volume for the added DVD drive.",
-						},
-						"resource_pool_name": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "",
-							Description: "Specifies the friendly name of the ISO resource pool to which this DVD drive is to be associated.",
-						},
-					},
+					Schema: vmDvdDriveSchema(),
 				},
-				Description: "",
+				Description: "The dvd drives of the virtual machine. Dvd drives are matched to the drives of the virtual machine by `controller_number` and `controller_location`, so reordering them does not replace them.",
 			},
 
 			"hard_disk_drives": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Elem: &schema.Resource{
+					Schema: vmHardDiskDriveSchema(),
+				},
+				Description: "The hard disk drives of the virtual machine. Hard disk drives are matched to the drives of the virtual machine by `controller_type`, `controller_number` and `controller_location`, so reordering them does not replace them. Hard disk drives that are only added to or removed from a scsi controller the virtual machine already has are hot plugged while it runs, other changes turn it off to apply them.",
+			},
+
+			"cloud_init": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"controller_type": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.ControllerType_name[api.ControllerType_Scsi],
-							ValidateDiagFunc: stringKeyInMap(api.ControllerType_value, true),
-							Description:      "Specifies the type of the controller to which the hard disk drive is to be added. Valid values to use are `Ide`, `Scsi`.",
-						},
-						"controller_number": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Specifies the number of the controller to which the hard disk drive is to be added.",
-						},
-						"controller_location": {
-							Type:        schema.TypeInt,
-							Required:    true,
-							Description: "Specifies the number of the location on the controller at which the hard disk drive is to be added.",
-						},
 						"path": {
 							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          "",
-							DiffSuppressFunc: api.DiffSuppressVmHardDiskPath,
-							Description:      "Specifies the full path of the hard disk drive file to be added.",
+							Required:         true,
+							ValidateDiagFunc: IsWindowsPath(),
+							DiffSuppressFunc: api.DiffSuppressWindowsPath,
+							Description:      "Specifies the path of the seed iso on the HyperV host machine e.g. `C:\\Iso\\web_server-cidata.iso`. The seed iso is deleted together with the virtual machine.",
 						},
-						"disk_number": {
-							Type:        schema.TypeInt,
-							Optional:    true,
-							Default:     MaxUint32,
-							Description: "Specifies the disk number of the offline physical hard drive to be connected as a passthrough disk. If value is 4294967295 then disk number is ignored.",
+						"user_data": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							StateFunc:   api.HashSensitiveValue,
+							Description: "Specifies the user data of cloud-init e.g. a `#cloud-config` document. Only a hash of it is stored in the state.",
 						},
-						"resource_pool_name": {
+						"meta_data": {
 							Type:        schema.TypeString,
 							Optional:    true,
-							Default:     "Primordial",
-							Description: "Specifies the friendly name of the resource pool to which this virtual hard disk is to be associated.",
+							Default:     "",
+							Description: "Specifies the meta data of cloud-init. When empty the `instance-id` and `local-hostname` are the name of the virtual machine. cloud-init only provisions a virtual machine once per `instance-id`, so a seed that changes is only applied again when its `instance-id` changes.",
 						},
-						"support_persistent_reservations": {
-							Type:        schema.TypeBool,
+						"network_config": {
+							Type:        schema.TypeString,
 							Optional:    true,
-							Default:     false,
-							Description: "Indicates that the hard disk supports SCSI persistent reservation semantics. Specify this parameter when the hard disk is a shared disk that is used by multiple virtual machines.",
+							Default:     "",
+							Description: "Specifies the network configuration of cloud-init. When empty the network configuration is left out of the seed and cloud-init uses dhcp.",
 						},
-						"maximum_iops": {
+						"controller_number": {
 							Type:        schema.TypeInt,
 							Optional:    true,
 							Default:     0,
-							Description: "Specifies the maximum normalized I/O operations per second (IOPS) for the hard disk. Hyper-V calculates normalized IOPS as the total size of I/O per second divided by 8 KB. If value is 0 then iops is ignored.",
+							Description: "Specifies the number of the controller of the dvd drive the seed iso is attached to. The dvd drive is attached to an ide controller of a generation 1 virtual machine and to a scsi controller of a generation 2 virtual machine.",
 						},
-						"minimum_iops": {
+						"controller_location": {
 							Type:        schema.TypeInt,
 							Optional:    true,
-							Default:     0,
-							Description: "Specifies the minimum normalized I/O operations per second (IOPS) for the hard disk. Hyper-V calculates normalized IOPS as the total size of I/O per second divided by 8 KB. If maximum iops value is 0 then iops is ignored.",
-						},
-						"qos_policy_id": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Default:     "00000000-0000-0000-0000-000000000000",
-							Description: "Specifies the unique ID for a storage QoS policy that this cmdlet associates with the hard disk drive. If value is 00000000-0000-0000-0000-000000000000 then qos policy id is ignored.",
-						},
-						"override_cache_attributes": {
-							Type:             schema.TypeString,
-							Optional:         true,
-							Default:          api.CacheAttributes_name[api.CacheAttributes_Default],
-							ValidateDiagFunc: stringKeyInMap(api.CacheAttributes_value, true),
-							Description:      "With Default it is equivalent of WriteCacheDisabled. With WriteCacheEnabled write I/O is acknowledged as written before it is committed to stable media. If your internal disks, DAS, SAN, or NAS has a battery backup system that can guarantee clean cache flushes on a power outage, write caching is generally safe. Internal batteries that report their status and/or automatically disable caching are best. UPS-backed systems are sometimes OK, but they are not foolproof. With WriteCacheAndFUAEnabled write I/O is committed to stable media BEFORE the I/O is acknowledged as written. With WriteCacheDisabled when I/O is written it is acknowledged as written as there is no cache in between. Valid values to use are `Default`, `WriteCacheEnabled`, `WriteCacheAndFUAEnabled`, `WriteCacheDisabled`.",
+							Default:     1,
+							Description: "Specifies the location on the controller of the dvd drive the seed iso is attached to. It can not be the location of a drive in `dvd_drives` or `hard_disk_drives`.",
 						},
 					},
 				},
-				Description: "",
+				Description: "Provisions the virtual machine with cloud-init. A NoCloud seed iso is built from the user data, meta data and network configuration, and attached to a dvd drive that is managed with the virtual machine and kept out of `dvd_drives`. The seed iso is built again when the block changes. It needs `oscdimg` of the Windows ADK deployment tools on the HyperV host machine.",
+			},
+
+			"manifest_json": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies a machine specification document that declares the child devices of the virtual machine in bulk, e.g. the inventory of a virtual machine exported from another hypervisor and read with `file()`. It is a json object with the keys `network_adaptors`, `dvd_drives` and `hard_disk_drives`, each holding an array of devices with the same keys as the block of the same name. Keys that are left out get the default of the block. The devices are created together with the virtual machine, and the devices of a block are updated when they change in the document. A block that is declared in the document can not be declared in the configuration too. As the document owns its devices, they are not read back into the state, so changes made to them outside of Terraform are not detected.",
 			},
 
 			"vm_firmware": {
@@ -750,7 +665,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 										Required:         true,
 										ValidateDiagFunc: stringKeyInMap(api.Gen2BootType_value, true),
 										DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
-											if newValue == "" || oldValue == newValue {
+											if newValue == "" || strings.EqualFold(oldValue, newValue) {
 												return true
 											}
 											return false
@@ -769,7 +684,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 												return true
 											}
 
-											if newValue == "" || oldValue == newValue {
+											if newValue == "" || strings.EqualFold(oldValue, newValue) {
 												return true
 											}
 
@@ -788,7 +703,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 												return true
 											}
 
-											if newValue == "" || oldValue == newValue {
+											if newValue == "" || strings.EqualFold(oldValue, newValue) {
 												return true
 											}
 											return false
@@ -888,6 +803,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 							Optional:         true,
 							Default:          api.OnOffState_name[api.OnOffState_On],
 							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 							Description:      "Specifies whether to enable secure boot. Valid values to use are `On`, `Off`.",
 						},
 
@@ -895,7 +811,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Default:     "MicrosoftWindows",
-							Description: "Specifies the name of the secure boot template. If secure boot is enabled, you must have a valid secure boot template for the guest operating system to start. Example values to use are `MicrosoftWindows`,`MicrosoftUEFICertificateAuthority`, `OpenSourceShieldedVM`.",
+							Description: "Specifies the name of the secure boot template. If secure boot is enabled, you must have a valid secure boot template for the guest operating system to start. Example values to use are `MicrosoftWindows`,`MicrosoftUEFICertificateAuthority`, `OpenSourceShieldedVM`. The templates available on the HyperV host machine can be looked up with the `hyperv_secure_boot_templates` data source.",
 						},
 
 						"preferred_network_boot_protocol": {
@@ -903,7 +819,8 @@ func resourceHyperVMachineInstance() *schema.Resource {
 							Optional:         true,
 							Default:          api.IPProtocolPreference_name[api.IPProtocolPreference_IPv4],
 							ValidateDiagFunc: stringKeyInMap(api.IPProtocolPreference_value, true),
-							Description:      "Specifies the IP protocol version to use during a network boot. Valid values to use are `IPv4`, `IPv6`.",
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+							Description:      "Specifies the IP protocol version to use during a network boot, e.g. when booting from a network adapter with `boot_from_network`. Use `IPv6` to PXE boot on an IPv6-only provisioning network, the network must then hand out the boot file with DHCPv6 as the firmware does not fall back to IPv4. Valid values to use are `IPv4`, `IPv6`.",
 						},
 
 						"console_mode": {
@@ -911,6 +828,7 @@ func resourceHyperVMachineInstance() *schema.Resource {
 							Optional:         true,
 							Default:          api.ConsoleModeType_name[api.ConsoleModeType_Default],
 							ValidateDiagFunc: stringKeyInMap(api.ConsoleModeType_value, true),
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 							Description:      "Specifies the console mode type for the virtual machine. This parameter allows a virtual machine to run without graphical user interface. Valid values to use are `Default`, `COM1`, `COM2`, `None`.",
 						},
 
@@ -919,14 +837,269 @@ func resourceHyperVMachineInstance() *schema.Resource {
 							Optional:         true,
 							Default:          api.OnOffState_name[api.OnOffState_Off],
 							ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+							DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 							Description:      "Specifies the behavior of the virtual machine after a start failure. For a value of On, if the virtual machine fails to start correctly from a device, the virtual machine is paused. Valid values to use are `On`, `Off`.",
 						},
 					},
 				},
 				Description: "",
 			},
+
+			"boot_from_network": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network_adapter_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Specifies the name of the network adapter in `network_adaptors` to boot from first.",
+						},
+						"disable_secure_boot": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Specifies whether to turn secure boot off for the first boot, for PXE environments whose boot loader is not signed. Secure boot is turned back on as configured in `vm_firmware` after the first boot, which needs the machine instance to be restarted.",
+						},
+						"first_boot_timeout": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     1200,
+							Description: "The amount of time in seconds to wait for the first boot, i.e. until the guest operating system installed from the network reports a heartbeat. The create timeout of the machine instance must be longer.",
+						},
+					},
+				},
+				Description: "Boots a generation 2 machine instance from a network adapter the first time it is started, e.g. for a PXE install. The network adapter is moved to the front of the boot order of the firmware before the machine instance is started, and the boot order configured in `vm_firmware` is reverted once the guest operating system reports a heartbeat, so the machine instance boots from its disks from then on. The `Heartbeat` integration service must be enabled. It is only used when the machine instance is created, so `state` must be `Running` and creating the machine instance waits for the first boot.",
+			},
 		},
 	}
+
+	// the attributes added since the state was written by a provider that did not version the schema
+	resource.StateUpgraders = []schema.StateUpgrader{
+		stateUpgraderV0("hyperv_machine_instance", resource,
+			"avma_key",
+			"boot_from_network",
+			"checkpoint_before_update",
+			"checkpoint_before_update_retention",
+			"cloud_init",
+			"cluster_name",
+			"cluster_node",
+			"cluster_shared_volume_path",
+			"cpu_usage",
+			"current_state",
+			"delete_seed_iso_on_destroy",
+			"delete_vhds_on_destroy",
+			"deletion_protection",
+			"guest_state_isolation_type",
+			"hard_disk_drives.base_path",
+			"heartbeat",
+			"integration_services_state",
+			"last_update_checkpoint_id",
+			"manifest_json",
+			"memory_assigned_bytes",
+			"memory_demand_bytes",
+			"memory_maximum_amount_per_numa_node_bytes",
+			"memory_preferred_numa_nodes",
+			"memory_resource_pool_name",
+			"network_adaptors.preserve_mac_on_recreate",
+			"resize_method",
+			"status",
+			"uptime_seconds",
+			"vm_id",
+			"vm_processor.enable_legacy_apic_mode",
+			"vm_processor.l3_cache_ways",
+			"vm_processor.perfmon",
+			"vm_processor.resource_pool_name",
+		),
+	}
+
+	return resource
+}
+
+// setNetworkAdaptersPreserveMacOnRecreate sets preserve_mac_on_recreate of the flattened network adapters as it is
+// configured, as it is not a setting of the network adapter on the HyperV host machine
+func setNetworkAdaptersPreserveMacOnRecreate(flattenedNetworkAdapters []interface{}, configuredNetworkAdapters []api.VmNetworkAdapter) {
+	for _, flattenedNetworkAdapter := range flattenedNetworkAdapters {
+		flattenedNetworkAdapter := flattenedNetworkAdapter.(map[string]interface{})
+		flattenedNetworkAdapter["preserve_mac_on_recreate"] = false
+		for _, configuredNetworkAdapter := range configuredNetworkAdapters {
+			if strings.EqualFold(configuredNetworkAdapter.Name, flattenedNetworkAdapter["name"].(string)) {
+				flattenedNetworkAdapter["preserve_mac_on_recreate"] = configuredNetworkAdapter.PreserveMacOnRecreate
+				break
+			}
+		}
+	}
+}
+
+// resourceChanges is implemented by both schema.ResourceData and schema.ResourceDiff, so changes can be inspected the
+// same way while planning and while applying
+type resourceChanges interface {
+	Get(key string) interface{}
+	GetChange(key string) (interface{}, interface{})
+	HasChange(key string) bool
+}
+
+func getMachineInstanceResizeMethod(d resourceChanges) string {
+	generation := (d.Get("generation")).(int)
+	dynamicMemory := (d.Get("dynamic_memory")).(bool)
+	oldMemoryStartupBytes, newMemoryStartupBytes := d.GetChange("memory_startup_bytes")
+
+	return api.GetVmResizeMethod(generation, dynamicMemory, int64(oldMemoryStartupBytes.(int)), int64(newMemoryStartupBytes.(int)), d.HasChange("processor_count"))
+}
+
+// machineInstanceManifestBlockSchemas are the blocks of child devices that can be declared in manifest_json
+func machineInstanceManifestBlockSchemas() map[string]map[string]*schema.Schema {
+	return map[string]map[string]*schema.Schema{
+		"network_adaptors": vmNetworkAdapterSchema(),
+		"dvd_drives":       vmDvdDriveSchema(),
+		"hard_disk_drives": vmHardDiskDriveSchema(),
+	}
+}
+
+// getMachineInstanceManifestChanges returns the blocks whose devices differ between the old and the new manifest_json,
+// with the devices of the new manifest. A block that is no longer in the manifest gets the devices of the
+// configuration, so the devices the manifest declared are removed unless the configuration declares them instead.
+func getMachineInstanceManifestChanges(d *schema.ResourceData) (map[string][]interface{}, error) {
+	changes := make(map[string][]interface{})
+	if !d.HasChange("manifest_json") {
+		return changes, nil
+	}
+
+	oldManifestJson, newManifestJson := d.GetChange("manifest_json")
+	oldManifest, err := api.ParseVmManifest(oldManifestJson.(string), machineInstanceManifestBlockSchemas())
+	if err != nil {
+		return nil, err
+	}
+	newManifest, err := api.ParseVmManifest(newManifestJson.(string), machineInstanceManifestBlockSchemas())
+	if err != nil {
+		return nil, err
+	}
+
+	for blockName := range machineInstanceManifestBlockSchemas() {
+		oldDevices, oldOk := oldManifest[blockName]
+		newDevices, newOk := newManifest[blockName]
+		if !oldOk && !newOk || reflect.DeepEqual(oldDevices, newDevices) {
+			continue
+		}
+
+		if newOk {
+			changes[blockName] = newDevices
+		} else {
+			changes[blockName] = (d.Get(blockName)).([]interface{})
+		}
+	}
+
+	return changes, nil
+}
+
+func customizeDiffForMachineInstance(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	manifest, err := api.ParseVmManifest((diff.Get("manifest_json")).(string), machineInstanceManifestBlockSchemas())
+	if err != nil {
+		return err
+	}
+
+	for blockName := range manifest {
+		if len((diff.Get(blockName)).([]interface{})) > 0 {
+			return fmt.Errorf("[ERROR][hyperv] %s is declared in manifest_json, so it can not be declared in the configuration too", blockName)
+		}
+	}
+
+	exposeVirtualizationExtensions := false
+	for _, vmProcessor := range (diff.Get("vm_processor")).([]interface{}) {
+		if vmProcessor, ok := vmProcessor.(map[string]interface{}); ok && vmProcessor["expose_virtualization_extensions"].(bool) {
+			exposeVirtualizationExtensions = true
+		}
+	}
+
+	networkAdapters := make([]api.VmNetworkAdapter, 0)
+	for _, networkAdapter := range (diff.Get("network_adaptors")).([]interface{}) {
+		if networkAdapter, ok := networkAdapter.(map[string]interface{}); ok {
+			networkAdapters = append(networkAdapters, api.ExpandNetworkAdapter(networkAdapter))
+		}
+	}
+
+	err = api.CheckMacAddressSpoofingForNestedVirtualization(exposeVirtualizationExtensions, networkAdapters)
+	if err != nil {
+		return err
+	}
+
+	// changing the type of a network adapter replaces the virtual machine, unless the network adapter is created again
+	// with the MAC address it had
+	if diff.Id() != "" {
+		for i, networkAdapter := range networkAdapters {
+			isLegacyKey := fmt.Sprintf("network_adaptors.%d.is_legacy", i)
+			if diff.HasChange(isLegacyKey) && !networkAdapter.PreserveMacOnRecreate {
+				if err := diff.ForceNew(isLegacyKey); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, cloudInit := range (diff.Get("cloud_init")).([]interface{}) {
+		cloudInit, ok := cloudInit.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		dvdDrives := make([]api.VmDvdDrive, 0)
+		for _, dvdDrive := range (diff.Get("dvd_drives")).([]interface{}) {
+			if dvdDrive, ok := dvdDrive.(map[string]interface{}); ok {
+				dvdDrives = append(dvdDrives, api.ExpandDvdDrive(dvdDrive))
+			}
+		}
+
+		hardDiskDrives, err := expandMachineInstanceHardDiskDrives((diff.Get("hard_disk_drives")).([]interface{}))
+		if err != nil {
+			return err
+		}
+
+		err = api.CheckVmCloudInit(&api.VmCloudInit{
+			ControllerNumber:   cloudInit["controller_number"].(int),
+			ControllerLocation: cloudInit["controller_location"].(int),
+		}, (diff.Get("generation")).(int), dvdDrives, hardDiskDrives)
+		if err != nil {
+			return err
+		}
+	}
+
+	if (diff.Get("checkpoint_before_update")).(bool) && api.ToCheckpointType((diff.Get("checkpoint_type")).(string)) == api.CheckpointType_Disabled {
+		return fmt.Errorf("[ERROR][hyperv] checkpoint_before_update requires checkpoint_type to allow checkpoints - was %s", api.CheckpointType_Disabled)
+	}
+
+	err = api.CheckGuestStateIsolation(api.ToGuestStateIsolationType((diff.Get("guest_state_isolation_type")).(string)), (diff.Get("generation")).(int))
+	if err != nil {
+		return err
+	}
+
+	// delete_vhds_on_destroy follows the features of the provider unless it is set on the machine instance
+	rawConfig := diff.GetRawConfig()
+	if !rawConfig.IsNull() && rawConfig.GetAttr("delete_vhds_on_destroy").IsNull() {
+		deleteVhdsOnDestroy := getProviderFeatures(i).MachineInstance.DeleteVhdsOnDestroy
+		if diff.Id() == "" || (diff.Get("delete_vhds_on_destroy")).(bool) != deleteVhdsOnDestroy {
+			err = diff.SetNew("delete_vhds_on_destroy", deleteVhdsOnDestroy)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if diff.Id() == "" {
+		bootFromNetwork, err := api.ExpandVmBootFromNetwork((diff.Get("boot_from_network")).([]interface{}))
+		if err != nil {
+			return err
+		}
+
+		err = api.CheckVmBootFromNetwork(bootFromNetwork, (diff.Get("generation")).(int), api.ToVmState((diff.Get("state")).(string)), networkAdapters)
+		if err != nil {
+			return err
+		}
+
+		return diff.SetNew("resize_method", api.VmResizeMethod_None)
+	}
+
+	return diff.SetNew("resize_method", getMachineInstanceResizeMethod(diff))
 }
 
 func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -968,11 +1141,13 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 	memoryMaximumBytes := int64((d.Get("memory_maximum_bytes")).(int))
 	memoryMinimumBytes := int64((d.Get("memory_minimum_bytes")).(int))
 	memoryStartupBytes := int64((d.Get("memory_startup_bytes")).(int))
+	memoryResourcePoolName := (d.Get("memory_resource_pool_name")).(string)
 	notes := (d.Get("notes")).(string)
 	processorCount := int64((d.Get("processor_count")).(int))
 	smartPagingFilePath := (d.Get("smart_paging_file_path")).(string)
 	snapshotFileLocation := (d.Get("snapshot_file_location")).(string)
 	staticMemory := (d.Get("static_memory")).(bool)
+	guestStateIsolationType := api.ToGuestStateIsolationType((d.Get("guest_state_isolation_type")).(string))
 	state := api.ToVmState((d.Get("state")).(string))
 
 	if dynamicMemory && staticMemory {
@@ -993,6 +1168,18 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
+	// the devices declared in the manifest are created in the same pass as the devices declared in the configuration
+	manifest, err := api.ParseVmManifest((d.Get("manifest_json")).(string), machineInstanceManifestBlockSchemas())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for blockName, devices := range manifest {
+		if err := d.Set(blockName, devices); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	networkAdapters, err := api.ExpandNetworkAdapters(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -1008,12 +1195,41 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
+	cloudInit, err := api.ExpandVmCloudInit(d, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = api.CheckVmCloudInit(cloudInit, generation, dvdDrives, hardDiskDrives)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if cloudInit != nil {
+		dvdDrives = append(dvdDrives, cloudInit.DvdDrive())
+	}
+
 	var vmFirmwares []api.VmFirmware
 	if generation > 1 {
 		vmFirmwares, err = api.ExpandVmFirmwares(d)
 		if err != nil {
 			return diag.FromErr(err)
 		}
+
+		err = requireVmFirmwaresSecureBootTemplates(ctx, client, vmFirmwares)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	bootFromNetwork, err := api.ExpandVmBootFromNetwork((d.Get("boot_from_network")).([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = api.CheckVmBootFromNetwork(bootFromNetwork, generation, state, networkAdapters)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	waitForStateTimeout, waitForStatePollPeriod, err := api.ExpandVmStateWaitForState(d)
@@ -1021,7 +1237,40 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	err = client.CreateVm(ctx, name, path, generation, automaticCriticalErrorAction, automaticCriticalErrorActionTimeout, automaticStartAction, automaticStartDelay, automaticStopAction, checkpointType, dynamicMemory, guestControlledCacheTypes, highMemoryMappedIoSpace, lockOnDisconnect, lowMemoryMappedIoSpace, memoryMaximumBytes, memoryMinimumBytes, memoryStartupBytes, notes, processorCount, smartPagingFilePath, snapshotFileLocation, staticMemory)
+	if clusterName := (d.Get("cluster_name")).(string); clusterName != "" {
+		placement, err := client.GetClusterPlacement(ctx, clusterName, memoryStartupBytes)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("[INFO][hyperv][create] chose cluster placement: %+v", placement)
+
+		if path == "" {
+			path = placement.SharedVolumePath
+		}
+
+		if err := d.Set("cluster_node", placement.Node); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("cluster_shared_volume_path", placement.SharedVolumePath); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = client.RequireGuestStateIsolationType(ctx, guestStateIsolationType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	avmaKey := (d.Get("avma_key")).(string)
+	if avmaKey != "" {
+		err = client.RequireAvma(ctx)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = client.CreateVm(ctx, name, path, generation, automaticCriticalErrorAction, automaticCriticalErrorActionTimeout, automaticStartAction, automaticStartDelay, automaticStopAction, checkpointType, dynamicMemory, guestControlledCacheTypes, highMemoryMappedIoSpace, lockOnDisconnect, lowMemoryMappedIoSpace, memoryMaximumBytes, memoryMinimumBytes, memoryStartupBytes, memoryResourcePoolName, notes, processorCount, smartPagingFilePath, snapshotFileLocation, staticMemory, guestStateIsolationType)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -1031,6 +1280,14 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
+	vmMemoryNuma := api.ExpandVmMemoryNuma(d)
+	if !vmMemoryNuma.IsDefault() {
+		err = client.SetVmMemoryNuma(ctx, name, vmMemoryNuma)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	err = client.CreateOrUpdateVmNetworkAdapters(ctx, name, networkAdapters)
 	if err != nil {
 		return diag.FromErr(err)
@@ -1041,6 +1298,28 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
+	// the key is published before the vm is started, so that it is there when Windows setup completes
+	if avmaKey != "" {
+		err = client.SetVmKvpHostItem(ctx, name, api.AvmaKeyKvpItemName, avmaKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if (d.Get("deletion_protection")).(bool) {
+		err = client.SetVmKvpHostItem(ctx, name, api.DeletionProtectionKvpItemName, api.DeletionProtectionKvpItemValue)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if cloudInit != nil {
+		err = client.CreateCloudInitDvd(ctx, *cloudInit)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	err = client.CreateOrUpdateVmDvdDrives(ctx, name, dvdDrives)
 	if err != nil {
 		return diag.FromErr(err)
@@ -1058,9 +1337,16 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 		}
 	}
 
-	err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, state)
-	if err != nil {
-		return diag.FromErr(err)
+	if bootFromNetwork != nil {
+		err = bootMachineInstanceFromNetwork(ctx, client, name, bootFromNetwork, waitForStateTimeout, waitForStatePollPeriod)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, state)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	d.SetId(name)
@@ -1069,77 +1355,115 @@ func resourceHyperVMachineInstanceCreate(ctx context.Context, d *schema.Resource
 	return resourceHyperVMachineInstanceRead(ctx, d, meta)
 }
 
-func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	log.Printf("[INFO][hyperv][read] reading hyperv machine: %#v", d)
-	client := meta.(api.Client)
+// bootMachineInstanceFromNetwork starts a new machine instance from a network adapter and reverts its firmware once it
+// has booted. The firmware is read back from the HyperV host machine, rather than taken from the configuration, so the
+// boot order HyperV created for the devices is reverted even when vm_firmware has no boot order.
+func bootMachineInstanceFromNetwork(ctx context.Context, client api.Client, name string, bootFromNetwork *api.VmBootFromNetwork, waitForStateTimeout uint32, waitForStatePollPeriod uint32) (err error) {
+	vmFirmware, err := client.GetVmFirmware(ctx, name)
+	if err != nil {
+		return err
+	}
 
-	name := d.Id()
+	// a dynamic mac address is only assigned when the vm is first started, so network adapters are matched by name alone
+	for i := range vmFirmware.BootOrders {
+		vmFirmware.BootOrders[i].MacAddress = ""
+	}
+
+	networkBootVmFirmware := api.NetworkBootVmFirmware(vmFirmware, bootFromNetwork.NetworkAdapterName, bootFromNetwork.DisableSecureBoot)
+	log.Printf("[INFO][hyperv][create] booting vm %s from network adapter %s with firmware: %+v", name, bootFromNetwork.NetworkAdapterName, networkBootVmFirmware)
 
-	vm, err := client.GetVm(ctx, name)
+	err = client.CreateOrUpdateVmFirmwares(ctx, name, []api.VmFirmware{networkBootVmFirmware})
 	if err != nil {
-		return diag.FromErr(err)
+		return err
 	}
 
-	if !d.IsNewResource() && vm.Name == "" {
-		d.SetId("")
-		return nil
+	err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, api.VmState_Running)
+	if err != nil {
+		return err
 	}
 
-	if err := d.Set("name", vm.Name); err != nil {
-		return diag.FromErr(err)
+	err = client.WaitForVmHeartbeat(ctx, name, bootFromNetwork.FirstBootTimeout, waitForStatePollPeriod)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO][hyperv][create] vm %s booted from network adapter %s, reverting firmware: %+v", name, bootFromNetwork.NetworkAdapterName, vmFirmware)
+
+	// secure boot can only be changed while the vm is off, the boot order can be changed while it is running
+	secureBootChanged := networkBootVmFirmware.EnableSecureBoot != vmFirmware.EnableSecureBoot
+	if secureBootChanged {
+		err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, api.VmState_Off)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.CreateOrUpdateVmFirmwares(ctx, name, []api.VmFirmware{vmFirmware})
+	if err != nil {
+		return err
+	}
+
+	if secureBootChanged {
+		err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, api.VmState_Running)
+		if err != nil {
+			return err
+		}
 	}
 
-	vmProcessors, err := client.GetVmProcessors(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	return nil
+}
+
+func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv machine: %#v", d)
+	client := meta.(api.Client)
+
+	name := d.Id()
 
-	integrationServices, err := client.GetVmIntegrationServices(ctx, name)
+	networkAdaptersWaitForIps, waitForIpsTimeout, waitForIpsPollPeriod, err := api.ExpandVmNetworkAdapterWaitForIps(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	dvdDrives, err := client.GetVmDvdDrives(ctx, name)
+	vmWithDevices, err := client.GetVmWithDevices(ctx, name, networkAdaptersWaitForIps)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	hardDiskDrives, err := client.GetVmHardDiskDrives(ctx, name)
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	vm := vmWithDevices.Vm
 
-	vmFirmwares := client.GetNoVmFirmwares(ctx)
-	if vm.Generation > 1 {
-		vmFirmwares, err = client.GetVmFirmwares(ctx, name)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	if !d.IsNewResource() && vm.Name == "" {
+		d.SetId("")
+		return nil
 	}
 
-	vmState, err := client.GetVmStatus(ctx, name)
-	if err != nil {
+	if err := d.Set("name", vm.Name); err != nil {
 		return diag.FromErr(err)
 	}
 
-	networkAdaptersWaitForIps, waitForIpsTimeout, waitForIpsPollPeriod, err := api.ExpandVmNetworkAdapterWaitForIps(d)
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	vmProcessors := vmWithDevices.VmProcessors
+	integrationServices := vmWithDevices.IntegrationServices
+	dvdDrives := vmWithDevices.DvdDrives
+	hardDiskDrives := vmWithDevices.HardDiskDrives
+	vmFirmwares := vmWithDevices.VmFirmwares
+	vmState := vmWithDevices.VmStatus
+	networkAdapters := vmWithDevices.NetworkAdapters
 
-	err = client.WaitForVmNetworkAdaptersIps(ctx, name, waitForIpsTimeout, waitForIpsPollPeriod, networkAdaptersWaitForIps)
-	if err != nil {
-		return diag.FromErr(err)
-	}
+	// ips are only read again once they are available, everything else has already been read in one go
+	if api.IsWaitingForVmNetworkAdapterIps(networkAdaptersWaitForIps) {
+		err = client.WaitForVmNetworkAdaptersIps(ctx, name, waitForIpsTimeout, waitForIpsPollPeriod, networkAdaptersWaitForIps)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 
-	networkAdapters, err := client.GetVmNetworkAdapters(ctx, name, networkAdaptersWaitForIps)
-	if err != nil {
-		return diag.FromErr(err)
+		networkAdapters, err = client.GetVmNetworkAdapters(ctx, name, networkAdaptersWaitForIps)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	log.Printf("[INFO][hyperv][read] retrieved vm: %+v", vm)
 
-	if vm.Name != name {
+	if !strings.EqualFold(vm.Name, name) {
 		log.Printf("[INFO][hyperv][read] unable to read hyperv machine as it does not exist: %#v", name)
 		return nil
 	}
@@ -1164,6 +1488,32 @@ func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceDa
 		return diag.Errorf("[DEBUG] Error setting integration_services error: %v", err)
 	}
 
+	// the dvd drive of the cloud-init seed is managed by cloud_init
+	cloudInit, err := api.ExpandVmCloudInit(d, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	dvdDrives = cloudInit.WithoutDvdDrive(dvdDrives)
+
+	// devices are kept in the order they are declared in, as HyperV does not always return them in the same order
+	configuredDvdDrives, err := api.ExpandDvdDrives(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	dvdDrives = api.OrderDvdDrives(dvdDrives, configuredDvdDrives)
+
+	configuredHardDiskDrives, err := api.ExpandHardDiskDrives(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	hardDiskDrives = api.OrderHardDiskDrives(hardDiskDrives, configuredHardDiskDrives)
+
+	configuredNetworkAdapters, err := api.ExpandNetworkAdapters(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	networkAdapters = api.OrderNetworkAdapters(networkAdapters, configuredNetworkAdapters)
+
 	flattenedDvdDrives := api.FlattenDvdDrives(&dvdDrives)
 	if err := d.Set("dvd_drives", flattenedDvdDrives); err != nil {
 		return diag.Errorf("[DEBUG] Error setting dvd_drives error: %v", err)
@@ -1179,12 +1529,25 @@ func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceDa
 	log.Printf("[INFO][hyperv][read] flattenedHardDiskDrives: %v", flattenedHardDiskDrives)
 
 	flattenedNetworkAdapters := api.FlattenNetworkAdapters(&networkAdapters)
+	setNetworkAdaptersPreserveMacOnRecreate(flattenedNetworkAdapters, configuredNetworkAdapters)
 	if err := d.Set("network_adaptors", flattenedNetworkAdapters); err != nil {
 		return diag.Errorf("[DEBUG] Error setting network_adaptors error: %v", err)
 	}
 	log.Printf("[INFO][hyperv][read] networkAdapters: %v", networkAdapters)
 	log.Printf("[INFO][hyperv][read] flattenedNetworkAdapters: %v", flattenedNetworkAdapters)
 
+	// the devices declared in the manifest are owned by it, so they are kept out of the state to match the configuration
+	manifest, err := api.ParseVmManifest((d.Get("manifest_json")).(string), machineInstanceManifestBlockSchemas())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for blockName := range manifest {
+		if err := d.Set(blockName, []interface{}{}); err != nil {
+			return diag.Errorf("[DEBUG] Error setting %s error: %v", blockName, err)
+		}
+	}
+
 	flattenedVmFirmwares := api.FlattenVmFirmwares(&vmFirmwares)
 	if err := d.Set("vm_firmware", flattenedVmFirmwares); err != nil {
 		return diag.Errorf("[DEBUG] Error setting vm_firmware error: %v", err)
@@ -1206,6 +1569,9 @@ func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceDa
 	if err := d.Set("generation", vm.Generation); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("guest_state_isolation_type", vm.GuestStateIsolationType.String()); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("automatic_critical_error_action", vm.AutomaticCriticalErrorAction.String()); err != nil {
 		return diag.FromErr(err)
 	}
@@ -1248,9 +1614,25 @@ func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceDa
 	if err := d.Set("memory_startup_bytes", vm.MemoryStartupBytes); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("memory_resource_pool_name", vm.MemoryResourcePoolName); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("notes", vm.Notes); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("avma_key", vmWithDevices.KvpHostItems[api.AvmaKeyKvpItemName]); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("deletion_protection", vmWithDevices.KvpHostItems[api.DeletionProtectionKvpItemName] == api.DeletionProtectionKvpItemValue); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_maximum_amount_per_numa_node_bytes", vmWithDevices.VmMemoryNuma.MaximumAmountPerNumaNodeBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_preferred_numa_nodes", vmWithDevices.VmMemoryNuma.PreferredNumaNodes); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("processor_count", vm.ProcessorCount); err != nil {
 		return diag.FromErr(err)
 	}
@@ -1263,7 +1645,39 @@ func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceDa
 	if err := d.Set("static_memory", vm.StaticMemory); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("state", vmState.State.String()); err != nil {
+	if err := d.Set("state", vmState.State.Settled().String()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// runtime values are only ever computed, so that they never show up as changes to the definition of the vm
+	if err := d.Set("vm_id", vmState.Id); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("current_state", vmState.State.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("status", vmState.Status); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("uptime_seconds", vmState.UptimeSeconds); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("heartbeat", vmState.Heartbeat); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("integration_services_state", vmState.IntegrationServicesState); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cpu_usage", vmState.CpuUsage); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_assigned_bytes", vmState.MemoryAssigned); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_demand_bytes", vmState.MemoryDemand); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("resize_method", api.VmResizeMethod_None); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -1275,12 +1689,33 @@ func resourceHyperVMachineInstanceRead(ctx context.Context, d *schema.ResourceDa
 func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO][hyperv][update] updating hyperv machine: %#v", d)
 	client := meta.(api.Client)
+	features := getProviderFeatures(meta)
 
 	name := d.Id()
 
 	generation := (d.Get("generation")).(int)
 
-	hasChangesThatRequireVmToBeOff := d.HasChange("automatic_critical_error_action") ||
+	manifestChanges, err := getMachineInstanceManifestChanges(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for blockName, devices := range manifestChanges {
+		if err := d.Set(blockName, devices); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// hard disk drives added to or removed from scsi controllers are hot plugged while the vm keeps running
+	hardDiskDrivesHotPluggable := false
+	if _, ok := manifestChanges["hard_disk_drives"]; !ok && d.HasChange("hard_disk_drives") {
+		hardDiskDrivesHotPluggable, err = getMachineInstanceHardDiskDrivesHotPluggable(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	hasOtherChangesThatRequireVmToBeOff := d.HasChange("automatic_critical_error_action") ||
 		d.HasChange("automatic_critical_error_action_timeout") ||
 		d.HasChange("automatic_start_action") ||
 		d.HasChange("automatic_start_delay") ||
@@ -1293,7 +1728,9 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		d.HasChange("low_memory_mapped_io_space") ||
 		d.HasChange("memory_maximum_bytes") ||
 		d.HasChange("memory_minimum_bytes") ||
-		d.HasChange("memory_startup_bytes") ||
+		d.HasChange("memory_resource_pool_name") ||
+		d.HasChange("memory_maximum_amount_per_numa_node_bytes") ||
+		d.HasChange("memory_preferred_numa_nodes") ||
 		d.HasChange("notes") ||
 		d.HasChange("processor_count") ||
 		d.HasChange("smart_paging_file_path") ||
@@ -1304,10 +1741,44 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		d.HasChange("integration_services") ||
 		d.HasChange("network_adaptors") ||
 		d.HasChange("dvd_drives") ||
-		d.HasChange("hard_disk_drives")
+		(d.HasChange("hard_disk_drives") && !hardDiskDrivesHotPluggable) ||
+		d.HasChange("cloud_init") ||
+		len(manifestChanges) > 0
+
+	// memory is hot added while the vm keeps running when nothing else requires it to be turned off, if the guest
+	// does not support it the memory is changed offline instead
+	memoryHotAdded := false
+	if !hasOtherChangesThatRequireVmToBeOff && getMachineInstanceResizeMethod(d) == api.VmResizeMethod_HotAdd {
+		memoryStartupBytes := int64((d.Get("memory_startup_bytes")).(int))
+		err := client.ResizeVmMemory(ctx, name, memoryStartupBytes)
+		if err != nil {
+			log.Printf("[INFO][hyperv][update] unable to hot add memory to hyperv machine %s, so it is updated offline instead: %s", name, err)
+		} else {
+			memoryHotAdded = true
+		}
+	}
+
+	hasChangesThatRequireVmToBeOff := hasOtherChangesThatRequireVmToBeOff ||
+		(d.HasChange("memory_startup_bytes") && !memoryHotAdded)
+
+	// the checkpoint is taken before the vm is turned off, so that a production checkpoint of a running vm is
+	// consistent for its applications
+	checkpointTaken := false
+	if hasChangesThatRequireVmToBeOff && (d.Get("checkpoint_before_update")).(bool) {
+		vmCheckpoint, err := client.CreateVmCheckpoint(ctx, name, api.UpdateCheckpointName(time.Now()))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("[INFO][hyperv][update] took checkpoint %s of hyperv machine %s before updating it", vmCheckpoint.Id, name)
+		if err := d.Set("last_update_checkpoint_id", vmCheckpoint.Id); err != nil {
+			return diag.FromErr(err)
+		}
+		checkpointTaken = true
+	}
 
 	if hasChangesThatRequireVmToBeOff {
-		err := turnOffVmIfOn(ctx, d, client, name)
+		err := turnOffVmIfOn(ctx, d, client, name, features.MachineInstance.GracefulShutdown)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -1326,7 +1797,8 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		d.HasChange("low_memory_mapped_io_space") ||
 		d.HasChange("memory_maximum_bytes") ||
 		d.HasChange("memory_minimum_bytes") ||
-		d.HasChange("memory_startup_bytes") ||
+		(d.HasChange("memory_startup_bytes") && !memoryHotAdded) ||
+		d.HasChange("memory_resource_pool_name") ||
 		d.HasChange("notes") ||
 		d.HasChange("processor_count") ||
 		d.HasChange("smart_paging_file_path") ||
@@ -1346,6 +1818,7 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		memoryMaximumBytes := int64((d.Get("memory_maximum_bytes")).(int))
 		memoryMinimumBytes := int64((d.Get("memory_minimum_bytes")).(int))
 		memoryStartupBytes := int64((d.Get("memory_startup_bytes")).(int))
+		memoryResourcePoolName := (d.Get("memory_resource_pool_name")).(string)
 		notes := (d.Get("notes")).(string)
 		processorCount := int64((d.Get("processor_count")).(int))
 		smartPagingFilePath := (d.Get("smart_paging_file_path")).(string)
@@ -1360,7 +1833,7 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 			return diag.Errorf("[ERROR][hyperv][update] Either dynamic or static memory must be selected i.e. static_memory=true and dynamic_memory=false")
 		}
 
-		err := client.UpdateVm(ctx, name, automaticCriticalErrorAction, automaticCriticalErrorActionTimeout, automaticStartAction, automaticStartDelay, automaticStopAction, checkpointType, dynamicMemory, guestControlledCacheTypes, highMemoryMappedIoSpace, lockOnDisconnect, lowMemoryMappedIoSpace, memoryMaximumBytes, memoryMinimumBytes, memoryStartupBytes, notes, processorCount, smartPagingFilePath, snapshotFileLocation, staticMemory)
+		err := client.UpdateVm(ctx, name, automaticCriticalErrorAction, automaticCriticalErrorActionTimeout, automaticStartAction, automaticStartDelay, automaticStopAction, checkpointType, dynamicMemory, guestControlledCacheTypes, highMemoryMappedIoSpace, lockOnDisconnect, lowMemoryMappedIoSpace, memoryMaximumBytes, memoryMinimumBytes, memoryStartupBytes, memoryResourcePoolName, notes, processorCount, smartPagingFilePath, snapshotFileLocation, staticMemory)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -1378,6 +1851,13 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		}
 	}
 
+	if d.HasChange("memory_maximum_amount_per_numa_node_bytes") || d.HasChange("memory_preferred_numa_nodes") {
+		err := client.SetVmMemoryNuma(ctx, name, api.ExpandVmMemoryNuma(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	if d.HasChange("integration_services") {
 		integrationServices, err := api.ExpandIntegrationServices(d)
 		if err != nil {
@@ -1392,7 +1872,41 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		}
 	}
 
-	if d.HasChange("network_adaptors") {
+	if d.HasChange("deletion_protection") {
+		if (d.Get("deletion_protection")).(bool) {
+			err := client.SetVmKvpHostItem(ctx, name, api.DeletionProtectionKvpItemName, api.DeletionProtectionKvpItemValue)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			err := client.RemoveVmKvpHostItem(ctx, name, api.DeletionProtectionKvpItemName)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if d.HasChange("avma_key") {
+		avmaKey := (d.Get("avma_key")).(string)
+		if avmaKey == "" {
+			err := client.RemoveVmKvpHostItem(ctx, name, api.AvmaKeyKvpItemName)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		} else {
+			err := client.RequireAvma(ctx)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			err = client.SetVmKvpHostItem(ctx, name, api.AvmaKeyKvpItemName, avmaKey)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if _, ok := manifestChanges["network_adaptors"]; ok || d.HasChange("network_adaptors") {
 		networkAdapters, err := api.ExpandNetworkAdapters(d)
 		if err != nil {
 			return diag.FromErr(err)
@@ -1404,19 +1918,61 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		}
 	}
 
-	if d.HasChange("dvd_drives") {
+	cloudInit, err := api.ExpandVmCloudInit(d, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if cloudInit != nil && d.HasChange("cloud_init") {
+		err = client.CreateCloudInitDvd(ctx, *cloudInit)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if _, ok := manifestChanges["dvd_drives"]; ok || d.HasChange("dvd_drives") || d.HasChange("cloud_init") {
 		dvdDrives, err := api.ExpandDvdDrives(d)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 
+		hardDiskDrives, err := api.ExpandHardDiskDrives(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = api.CheckVmCloudInit(cloudInit, generation, dvdDrives, hardDiskDrives)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if cloudInit != nil {
+			dvdDrives = append(dvdDrives, cloudInit.DvdDrive())
+		}
+
 		err = client.CreateOrUpdateVmDvdDrives(ctx, name, dvdDrives)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
-	if d.HasChange("hard_disk_drives") {
+	// the previous seed is deleted once it is no longer attached
+	if d.HasChange("cloud_init") {
+		oldCloudInits, _ := d.GetChange("cloud_init")
+		for _, oldCloudInit := range oldCloudInits.([]interface{}) {
+			oldCloudInit, ok := oldCloudInit.(map[string]interface{})
+			if !ok || (cloudInit != nil && strings.EqualFold(cloudInit.Path, oldCloudInit["path"].(string))) {
+				continue
+			}
+
+			err = client.DeleteDvd(ctx, oldCloudInit["path"].(string))
+			if err != nil && !errors.Is(err, api.ErrNotFound) {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if _, ok := manifestChanges["hard_disk_drives"]; ok || d.HasChange("hard_disk_drives") {
 		hardDiskDrives, err := api.ExpandHardDiskDrives(d)
 		if err != nil {
 			return diag.FromErr(err)
@@ -1434,13 +1990,20 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 			return diag.FromErr(err)
 		}
 
+		err = requireVmFirmwaresSecureBootTemplates(ctx, client, vmFirmwares)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
 		err = client.CreateOrUpdateVmFirmwares(ctx, name, vmFirmwares)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	}
 
-	if hasChangesThatRequireVmToBeOff || d.HasChange("state") {
+	if hasChangesThatRequireVmToBeOff && !d.HasChange("state") && !features.MachineInstance.StartAfterUpdate {
+		log.Printf("[INFO][hyperv][update] leaving hyperv machine %s turned off after the update as start_after_update is disabled", name)
+	} else if hasChangesThatRequireVmToBeOff || d.HasChange("state") {
 		waitForStateTimeout, waitForStatePollPeriod, err := api.ExpandVmStateWaitForState(d)
 		if err != nil {
 			return diag.FromErr(err)
@@ -1453,15 +2016,42 @@ func resourceHyperVMachineInstanceUpdate(ctx context.Context, d *schema.Resource
 		}
 	}
 
+	if checkpointTaken {
+		err := pruneMachineInstanceUpdateCheckpoints(ctx, client, name, (d.Get("checkpoint_before_update_retention")).(int))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Printf("[INFO][hyperv][update] updated hyperv machine: %#v", d)
 
 	return resourceHyperVMachineInstanceRead(ctx, d, meta)
 }
 
+// pruneMachineInstanceUpdateCheckpoints removes the oldest checkpoints taken before updates once an update has been
+// applied successfully, checkpoints taken by hand are left alone
+func pruneMachineInstanceUpdateCheckpoints(ctx context.Context, client api.Client, name string, retention int) (err error) {
+	vmCheckpoints, err := client.GetVmCheckpoints(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, vmCheckpoint := range api.UpdateCheckpointsToPrune(vmCheckpoints, retention) {
+		log.Printf("[INFO][hyperv][update] removing checkpoint %s of hyperv machine %s taken before an update: %s", vmCheckpoint.Id, name, vmCheckpoint.Name)
+		err = client.DeleteVmCheckpoint(ctx, name, vmCheckpoint.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func resourceHyperVMachineInstanceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO][hyperv][delete] deleting hyperv machine: %#v", d)
 
 	client := meta.(api.Client)
+	features := getProviderFeatures(meta)
 
 	name := d.Id()
 
@@ -1470,22 +2060,127 @@ func resourceHyperVMachineInstanceDelete(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	state := api.VmState_Off
-	err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, state)
+	// the protection is checked before the vm is turned off, both in the state and in the key value pair on the host in
+	// case it was set outside of Terraform or after the vm was last refreshed. The delete script checks it again.
+	if (d.Get("deletion_protection")).(bool) {
+		return diag.Errorf("[ERROR][hyperv][delete] hyperv machine %s is protected from deletion, set deletion_protection to false and apply before destroying it", name)
+	}
+
+	kvpHostItems, err := client.GetVmKvpHostItems(ctx, name)
+	if errors.Is(err, api.ErrNotFound) {
+		log.Printf("[INFO][hyperv][delete] hyperv machine no longer exists: %s", name)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if kvpHostItems[api.DeletionProtectionKvpItemName] == api.DeletionProtectionKvpItemValue {
+		return diag.Errorf("[ERROR][hyperv][delete] hyperv machine %s is protected from deletion by its %s key value pair on the HyperV host machine, set deletion_protection to false and apply before destroying it", name, api.DeletionProtectionKvpItemName)
+	}
+
+	if features.MachineInstance.PreventDeletionIfRunning {
+		vmStatus, err := client.GetVmStatus(ctx, name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if vmStatus.State == api.VmState_Running {
+			return diag.Errorf("[ERROR][hyperv][delete] hyperv machine %s is running and prevent_deletion_if_running is enabled in the features of the provider, set its state to Off before destroying it", name)
+		}
+	}
+
+	if features.MachineInstance.GracefulShutdown {
+		err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, api.VmState_Off)
+	} else {
+		err = client.TurnOffVm(ctx, name, waitForStateTimeout, waitForStatePollPeriod)
+	}
+	if errors.Is(err, api.ErrNotFound) {
+		log.Printf("[INFO][hyperv][delete] hyperv machine no longer exists: %s", name)
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// the virtual hard disks are read before the vm is deleted, as they can only be found through it
+	var hardDiskDrives []api.VmHardDiskDrive
+	if (d.Get("delete_vhds_on_destroy")).(bool) {
+		hardDiskDrives, err = client.GetVmHardDiskDrives(ctx, name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	err = client.DeleteVm(ctx, name)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	for _, hardDiskDrive := range hardDiskDrives {
+		if hardDiskDrive.Path == "" {
+			continue
+		}
+
+		log.Printf("[INFO][hyperv][delete] deleting virtual hard disk %s of hyperv machine %s", hardDiskDrive.Path, name)
+		err = client.DeleteVhdWithCheckpoints(ctx, hardDiskDrive.Path)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	cloudInit, err := api.ExpandVmCloudInit(d, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if cloudInit != nil && (d.Get("delete_seed_iso_on_destroy")).(bool) {
+		err = client.DeleteDvd(ctx, cloudInit.Path)
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Printf("[INFO][hyperv][delete] deleted hyperv machine: %#v", d)
 	return nil
 }
 
-func turnOffVmIfOn(ctx context.Context, data *schema.ResourceData, client api.Client, name string) (err error) {
+// getMachineInstanceHardDiskDrivesHotPluggable returns whether the change to hard_disk_drives can be applied while the
+// vm runs
+func getMachineInstanceHardDiskDrivesHotPluggable(d *schema.ResourceData) (bool, error) {
+	oldHardDiskDrives, newHardDiskDrives := d.GetChange("hard_disk_drives")
+
+	currentHardDiskDrives, err := expandMachineInstanceHardDiskDrives(oldHardDiskDrives.([]interface{}))
+	if err != nil {
+		return false, err
+	}
+
+	desiredHardDiskDrives, err := expandMachineInstanceHardDiskDrives(newHardDiskDrives.([]interface{}))
+	if err != nil {
+		return false, err
+	}
+
+	return api.HardDiskDrivesHotPluggable(currentHardDiskDrives, desiredHardDiskDrives), nil
+}
+
+func expandMachineInstanceHardDiskDrives(hardDiskDrives []interface{}) ([]api.VmHardDiskDrive, error) {
+	expandedHardDiskDrives := make([]api.VmHardDiskDrive, 0)
+	for _, hardDiskDrive := range hardDiskDrives {
+		if hardDiskDrive, ok := hardDiskDrive.(map[string]interface{}); ok {
+			expandedHardDiskDrive, err := api.ExpandHardDiskDrive(hardDiskDrive)
+			if err != nil {
+				return nil, err
+			}
+			expandedHardDiskDrives = append(expandedHardDiskDrives, expandedHardDiskDrive)
+		}
+	}
+
+	return expandedHardDiskDrives, nil
+}
+
+func turnOffVmIfOn(ctx context.Context, data *schema.ResourceData, client api.Client, name string, gracefulShutdown bool) (err error) {
 	vmState, err := client.GetVmStatus(ctx, name)
 	if err != nil {
 		return err
@@ -1500,7 +2195,11 @@ func turnOffVmIfOn(ctx context.Context, data *schema.ResourceData, client api.Cl
 				return err
 			}
 
-			err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, api.VmState_Off)
+			if gracefulShutdown {
+				err = client.UpdateVmStatus(ctx, name, waitForStateTimeout, waitForStatePollPeriod, api.VmState_Off)
+			} else {
+				err = client.TurnOffVm(ctx, name, waitForStateTimeout, waitForStatePollPeriod)
+			}
 			if err != nil {
 				return err
 			}
@@ -1531,3 +2230,18 @@ func turnOffVmIfOn(ctx context.Context, data *schema.ResourceData, client api.Cl
 	}
 	return nil
 }
+
+func requireVmFirmwaresSecureBootTemplates(ctx context.Context, client api.Client, vmFirmwares []api.VmFirmware) (err error) {
+	for _, vmFirmware := range vmFirmwares {
+		if vmFirmware.EnableSecureBoot != api.OnOffState_On || vmFirmware.SecureBootTemplate == "" {
+			continue
+		}
+
+		err = client.RequireSecureBootTemplate(ctx, vmFirmware.SecureBootTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func TestHyperVResourceMachineInstance(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceMachineInstanceConfig(name, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "generation", "2"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "processor_count", "1"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "state", "Off"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "current_state", "Off"),
+					resource.TestCheckResourceAttrSet("hyperv_machine_instance.this", "vm_id"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "uptime_seconds", "0"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "resize_method", "None"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "delete_vhds_on_destroy", "false"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "delete_seed_iso_on_destroy", "true"),
+				),
+			},
+			{
+				Config: testHyperVResourceMachineInstanceConfig(name, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "processor_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceMachineInstanceFeatures(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceMachineInstanceFeaturesConfig(name, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "current_state", "Running"),
+				),
+			},
+			{
+				// the vm is turned off to change its processors and left off, so the plan wants to start it again
+				Config:             testHyperVResourceMachineInstanceFeaturesConfig(name, 2),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "processor_count", "2"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "current_state", "Off"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceMachineInstanceDeletionProtection(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceMachineInstanceDeletionProtectionConfig(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testHyperVResourceMachineInstanceDeletionProtectionConfig(name, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("protected from deletion"),
+			},
+			{
+				Config: testHyperVResourceMachineInstanceDeletionProtectionConfig(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceMachineInstanceHardDiskDriveHotPlug(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	path := testAccPath("disk.vhdx")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceMachineInstanceHardDiskDriveHotPlugConfig(name, path, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "current_state", "Running"),
+				),
+			},
+			{
+				// start_after_update is disabled, so the vm would be left off if it had been turned off to add the disk
+				Config: testHyperVResourceMachineInstanceHardDiskDriveHotPlugConfig(name, path, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "hard_disk_drives.#", "1"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "current_state", "Running"),
+				),
+			},
+			{
+				Config: testHyperVResourceMachineInstanceHardDiskDriveHotPlugConfig(name, path, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "hard_disk_drives.#", "0"),
+					resource.TestCheckResourceAttr("hyperv_machine_instance.this", "current_state", "Running"),
+				),
+			},
+		},
+	})
+}
+
+var testAccCheckHyperVMachineInstanceDestroy = testAccCheckDestroy("hyperv_machine_instance", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	vmExists, err := c.VmExists(ctx, id)
+	return vmExists.Exists, err
+})
+
+func testHyperVResourceMachineInstanceConfig(name string, processorCount int) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name            = "%s"
+	generation      = 2
+	processor_count = %d
+	state           = "Off"
+}
+	`, escapeForHcl(name), processorCount)
+}
+
+func testHyperVResourceMachineInstanceFeaturesConfig(name string, processorCount int) string {
+	return fmt.Sprintf(`
+provider "hyperv" {
+	features {
+		machine_instance {
+			graceful_shutdown  = false
+			start_after_update = false
+		}
+	}
+}
+
+resource "hyperv_machine_instance" "this" {
+	name            = "%s"
+	generation      = 2
+	processor_count = %d
+	state           = "Running"
+}
+	`, escapeForHcl(name), processorCount)
+}
+
+func testHyperVResourceMachineInstanceDeletionProtectionConfig(name string, deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name                = "%s"
+	generation          = 2
+	state               = "Off"
+	deletion_protection = %t
+}
+	`, escapeForHcl(name), deletionProtection)
+}
+
+func testHyperVResourceMachineInstanceHardDiskDriveHotPlugConfig(name string, path string, hardDiskDrive bool) string {
+	hardDiskDrives := ""
+	if hardDiskDrive {
+		hardDiskDrives = `
+	hard_disk_drives {
+		controller_type     = "Scsi"
+		controller_number   = 0
+		controller_location = 1
+		path                = hyperv_vhd.this.path
+	}`
+	}
+
+	return fmt.Sprintf(`
+provider "hyperv" {
+	features {
+		machine_instance {
+			graceful_shutdown  = false
+			start_after_update = false
+		}
+	}
+}
+
+resource "hyperv_vhd" "this" {
+	path = "%s"
+	size = 4194304
+}
+
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Running"
+%s
+}
+	`, escapeForHcl(path), escapeForHcl(name), hardDiskDrives)
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadNatTimeout   = 1 * time.Minute
+	CreateNatTimeout = 2 * time.Minute
+	DeleteNatTimeout = 2 * time.Minute
+)
+
+func resourceHyperVNat() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a network address translation (NAT) object on the host. Combine it with an internal `hyperv_network_switch` so virtual machines on the switch can reach external networks via the host.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadNatTimeout),
+			Create: schema.DefaultTimeout(CreateNatTimeout),
+			Delete: schema.DefaultTimeout(DeleteNatTimeout),
+		},
+		CreateContext: resourceHyperVNatCreate,
+		ReadContext:   resourceHyperVNatRead,
+		DeleteContext: resourceHyperVNatDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the NAT object.",
+			},
+			"internal_ip_interface_address_prefix": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsCIDR(),
+				Description:      "Specifies the internal network, in CIDR notation, that is translated by the NAT object e.g. `192.168.0.0/24`.",
+			},
+			"switch_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of an internal switch whose management os network adapter (`vEthernet (<switch_name>)`) should be assigned the `gateway_address`. When not set, the host IP address needs to be configured outside of this resource.",
+			},
+			"gateway_address": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsIPAddress(),
+				RequiredWith: []string{
+					"switch_name",
+				},
+				Description: "Specifies the IP address to assign to the management os network adapter of `switch_name`. Virtual machines should use this as their default gateway. Defaults to the first address of `internal_ip_interface_address_prefix` when `switch_name` is set.",
+			},
+		},
+	}
+}
+
+func getNatGatewayAddress(internalIPInterfaceAddressPrefix string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(internalIPInterfaceAddressPrefix)
+	if err != nil {
+		return "", err
+	}
+
+	gatewayAddress := make(net.IP, len(ipNet.IP))
+	copy(gatewayAddress, ipNet.IP)
+	for i := len(gatewayAddress) - 1; i >= 0; i-- {
+		gatewayAddress[i]++
+		if gatewayAddress[i] != 0 {
+			break
+		}
+	}
+
+	if !ipNet.Contains(gatewayAddress) {
+		return "", fmt.Errorf("[ERROR][hyperv] unable to calculate a gateway address for %s", internalIPInterfaceAddressPrefix)
+	}
+
+	return gatewayAddress.String(), nil
+}
+
+func resourceHyperVNatCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv nat: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+	internalIPInterfaceAddressPrefix := (d.Get("internal_ip_interface_address_prefix")).(string)
+	switchName := (d.Get("switch_name")).(string)
+	gatewayAddress := (d.Get("gateway_address")).(string)
+
+	if d.IsNewResource() {
+		existing, err := c.GetNetNat(ctx, name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", name, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", name, "hyperv_nat", "hyperv_nat", name))
+		}
+	}
+
+	if switchName != "" && gatewayAddress == "" {
+		var err error
+		gatewayAddress, err = getNatGatewayAddress(internalIPInterfaceAddressPrefix)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if gatewayAddress != "" {
+		_, ipNet, err := net.ParseCIDR(internalIPInterfaceAddressPrefix)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if !ipNet.Contains(net.ParseIP(gatewayAddress)) {
+			return diag.Errorf("[ERROR][hyperv][create] gateway_address %s must be within internal_ip_interface_address_prefix %s", gatewayAddress, internalIPInterfaceAddressPrefix)
+		}
+	}
+
+	err := c.CreateNetNat(ctx, name, internalIPInterfaceAddressPrefix, switchName, gatewayAddress)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("gateway_address", gatewayAddress); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO][hyperv][create] created hyperv nat: %#v", d)
+
+	return resourceHyperVNatRead(ctx, d, meta)
+}
+
+func resourceHyperVNatRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv nat: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	netNat, err := c.GetNetNat(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved nat: %+v", netNat)
+
+	if !strings.EqualFold(netNat.Name, name) {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv nat as it does not exist: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", netNat.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("internal_ip_interface_address_prefix", netNat.InternalIPInterfaceAddressPrefix); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv nat: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVNatDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv nat: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+	switchName := (d.Get("switch_name")).(string)
+	gatewayAddress := (d.Get("gateway_address")).(string)
+
+	err := c.DeleteNetNat(ctx, name, switchName, gatewayAddress)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv nat: %#v", d)
+	return nil
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadNatNetworkTimeout   = 1 * time.Minute
+	CreateNatNetworkTimeout = 5 * time.Minute
+	DeleteNatNetworkTimeout = 2 * time.Minute
+)
+
+func resourceHyperVNatNetwork() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a NAT network in one go. It creates an internal virtual network switch, assigns the gateway address to the host network adapter of the switch and creates a network address translation (NAT) object for the network. Virtual machines connected to the switch can reach external networks via the gateway address.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadNatNetworkTimeout),
+			Create: schema.DefaultTimeout(CreateNatNetworkTimeout),
+			Delete: schema.DefaultTimeout(DeleteNatNetworkTimeout),
+		},
+		CreateContext: resourceHyperVNatNetworkCreate,
+		ReadContext:   resourceHyperVNatNetworkRead,
+		DeleteContext: resourceHyperVNatNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the NAT network. It is used as the name of both the internal switch and the NAT object.",
+			},
+			"cidr": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsCIDR(),
+				Description:      "Specifies the network, in CIDR notation, of the NAT network e.g. `192.168.100.0/24`.",
+			},
+			"gateway_address": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the IP address assigned to the host network adapter of the switch. Virtual machines should use this as their default gateway. Defaults to the first address of `cidr`.",
+			},
+			"switch_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the internal switch to connect virtual machine network adapters to.",
+			},
+		},
+	}
+}
+
+func resourceHyperVNatNetworkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv nat network: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+	cidr := (d.Get("cidr")).(string)
+	gatewayAddress := (d.Get("gateway_address")).(string)
+
+	if d.IsNewResource() {
+		existingSwitch, err := c.VMSwitchExists(ctx, name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", name, err))
+		}
+
+		existingNat, err := c.GetNetNat(ctx, name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", name, err))
+		}
+
+		if existingSwitch.Exists || existingNat.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", name, "hyperv_nat_network", "hyperv_nat_network", name))
+		}
+	}
+
+	if gatewayAddress == "" {
+		var err error
+		gatewayAddress, err = getNatGatewayAddress(cidr)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err := c.CreateVMSwitch(ctx, name, "", true, 0, false, false, false, api.VMSwitchBandwidthMode_None, api.VMSwitchType_Internal, []string{}, 0, api.VMSwitchTeamingMode_SwitchIndependent, 0, 0, false, 16, false)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.CreateNetNat(ctx, name, cidr, name, gatewayAddress)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("gateway_address", gatewayAddress); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO][hyperv][create] created hyperv nat network: %#v", d)
+
+	return resourceHyperVNatNetworkRead(ctx, d, meta)
+}
+
+func resourceHyperVNatNetworkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv nat network: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	vmSwitch, err := c.GetVMSwitch(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	netNat, err := c.GetNetNat(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved nat network switch: %+v nat: %+v", vmSwitch, netNat)
+
+	if !strings.EqualFold(vmSwitch.Name, name) || !strings.EqualFold(netNat.Name, name) {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv nat network as it does not exist: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cidr", netNat.InternalIPInterfaceAddressPrefix); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("switch_name", vmSwitch.Name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv nat network: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVNatNetworkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv nat network: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+	gatewayAddress := (d.Get("gateway_address")).(string)
+
+	err := c.DeleteNetNat(ctx, name, name, gatewayAddress)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.DeleteVMSwitch(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv nat network: %#v", d)
+	return nil
+}
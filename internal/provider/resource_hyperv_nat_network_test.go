@@ -0,0 +1,43 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceNatNetwork(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("nat")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceNatNetworkConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_nat_network.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_nat_network.this", "cidr", "192.168.233.0/24"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceNatNetworkConfig(name string) string {
+	return fmt.Sprintf(`
+resource "hyperv_nat_network" "this" {
+	name = "%s"
+	cidr = "192.168.233.0/24"
+}
+	`, escapeForHcl(name))
+}
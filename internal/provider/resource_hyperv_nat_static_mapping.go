@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadNatStaticMappingTimeout   = 1 * time.Minute
+	CreateNatStaticMappingTimeout = 2 * time.Minute
+	DeleteNatStaticMappingTimeout = 1 * time.Minute
+)
+
+var natStaticMappingIdFormat = []string{"<nat_name>", "<static_mapping_id>"}
+
+func resourceHyperVNatStaticMapping() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a static mapping (port forward) on a network address translation (NAT) object, so that traffic arriving on the host is forwarded to a virtual machine behind the NAT. It can be imported with an id in the format `<nat_name>|<static_mapping_id>` e.g. `lan|0`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadNatStaticMappingTimeout),
+			Create: schema.DefaultTimeout(CreateNatStaticMappingTimeout),
+			Delete: schema.DefaultTimeout(DeleteNatStaticMappingTimeout),
+		},
+		CreateContext: resourceHyperVNatStaticMappingCreate,
+		ReadContext:   resourceHyperVNatStaticMappingRead,
+		DeleteContext: resourceHyperVNatStaticMappingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"nat_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the NAT object to add the static mapping to.",
+			},
+			"protocol": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          api.NetNatProtocol_name[api.NetNatProtocol_TCP],
+				ValidateDiagFunc: stringKeyInMap(api.NetNatProtocol_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the protocol of the static mapping. Valid values to use are `TCP`, `UDP`.",
+			},
+			"external_ip_address": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "0.0.0.0",
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the external IP address of the host the static mapping listens on. Use `0.0.0.0` to listen on all addresses.",
+			},
+			"external_port": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IntBetween(1, 65535),
+				Description:      "Specifies the external port of the host the static mapping listens on.",
+			},
+			"internal_ip_address": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsIPAddress(),
+				Description:      "Specifies the IP address of the virtual machine that traffic is forwarded to.",
+			},
+			"internal_port": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IntBetween(1, 65535),
+				Description:      "Specifies the port of the virtual machine that traffic is forwarded to.",
+			},
+			"static_mapping_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The identifier Windows assigned to the static mapping.",
+			},
+		},
+	}
+}
+
+func getNatStaticMappingId(natName string, staticMappingId int) string {
+	return getVmDeviceId(natName, staticMappingId)
+}
+
+func parseNatStaticMappingId(id string) (natName string, staticMappingId int, err error) {
+	parts, err := parseVmDeviceId("nat static mapping", id, natStaticMappingIdFormat...)
+	if err != nil {
+		return "", 0, err
+	}
+
+	staticMappingId, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("[ERROR][hyperv] unable to parse nat static mapping id %q - expected format is `%s`", id, strings.Join(natStaticMappingIdFormat, vmDeviceIdSeparator))
+	}
+
+	return parts[0], staticMappingId, nil
+}
+
+func resourceHyperVNatStaticMappingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv nat static mapping: %#v", d)
+	c := meta.(api.Client)
+
+	natName := (d.Get("nat_name")).(string)
+	protocol := api.ToNetNatProtocol((d.Get("protocol")).(string))
+	externalIPAddress := (d.Get("external_ip_address")).(string)
+	externalPort := (d.Get("external_port")).(int)
+	internalIPAddress := (d.Get("internal_ip_address")).(string)
+	internalPort := (d.Get("internal_port")).(int)
+
+	staticMapping, err := c.CreateNetNatStaticMapping(ctx, natName, protocol, externalIPAddress, externalPort, internalIPAddress, internalPort)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getNatStaticMappingId(natName, staticMapping.StaticMappingId))
+	log.Printf("[INFO][hyperv][create] created hyperv nat static mapping: %#v", d)
+
+	return resourceHyperVNatStaticMappingRead(ctx, d, meta)
+}
+
+func resourceHyperVNatStaticMappingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv nat static mapping: %#v", d)
+	c := meta.(api.Client)
+
+	natName, staticMappingId, err := parseNatStaticMappingId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	staticMapping, err := c.GetNetNatStaticMapping(ctx, natName, staticMappingId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved nat static mapping: %+v", staticMapping)
+
+	if !strings.EqualFold(staticMapping.NatName, natName) {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv nat static mapping as it does not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("nat_name", staticMapping.NatName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("static_mapping_id", staticMapping.StaticMappingId); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("protocol", staticMapping.Protocol.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("external_ip_address", staticMapping.ExternalIPAddress); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("external_port", staticMapping.ExternalPort); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("internal_ip_address", staticMapping.InternalIPAddress); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("internal_port", staticMapping.InternalPort); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv nat static mapping: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVNatStaticMappingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv nat static mapping: %#v", d)
+	c := meta.(api.Client)
+
+	natName, staticMappingId, err := parseNatStaticMappingId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.DeleteNetNatStaticMapping(ctx, natName, staticMappingId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv nat static mapping: %#v", d)
+	return nil
+}
@@ -0,0 +1,65 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceNatStaticMapping(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("nat")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceNatStaticMappingConfig(name, 50001),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_nat_static_mapping.this", "nat_name", name),
+					resource.TestCheckResourceAttr("hyperv_nat_static_mapping.this", "external_port", "50001"),
+					resource.TestCheckResourceAttr("hyperv_nat_static_mapping.this", "internal_port", "3389"),
+				),
+			},
+			{
+				Config: testHyperVResourceNatStaticMappingConfig(name, 50002),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_nat_static_mapping.this", "external_port", "50002"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceNatStaticMappingConfig(name string, externalPort int) string {
+	return fmt.Sprintf(`
+resource "hyperv_network_switch" "this" {
+	name        = "%s"
+	switch_type = "Internal"
+}
+
+resource "hyperv_nat" "this" {
+	name                                 = "%s"
+	internal_ip_interface_address_prefix = "192.168.232.0/24"
+	switch_name                          = hyperv_network_switch.this.name
+}
+
+resource "hyperv_nat_static_mapping" "this" {
+	nat_name            = hyperv_nat.this.name
+	protocol            = "TCP"
+	external_ip_address = "0.0.0.0"
+	external_port       = %d
+	internal_ip_address = "192.168.232.10"
+	internal_port       = 3389
+}
+	`, escapeForHcl(name), escapeForHcl(name), externalPort)
+}
@@ -0,0 +1,51 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceNat(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("nat")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceNatConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_nat.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_nat.this", "internal_ip_interface_address_prefix", "192.168.231.0/24"),
+					resource.TestCheckResourceAttr("hyperv_nat.this", "gateway_address", "192.168.231.1"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceNatConfig(name string) string {
+	return fmt.Sprintf(`
+resource "hyperv_network_switch" "this" {
+	name        = "%s"
+	switch_type = "Internal"
+}
+
+resource "hyperv_nat" "this" {
+	name                                 = "%s"
+	internal_ip_interface_address_prefix = "192.168.231.0/24"
+	switch_name                          = hyperv_network_switch.this.name
+	gateway_address                      = "192.168.231.1"
+}
+	`, escapeForHcl(name), escapeForHcl(name))
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadNetworkAdapterTeamMappingTimeout   = 1 * time.Minute
+	CreateNetworkAdapterTeamMappingTimeout = 2 * time.Minute
+	UpdateNetworkAdapterTeamMappingTimeout = 2 * time.Minute
+	DeleteNetworkAdapterTeamMappingTimeout = 1 * time.Minute
+
+	networkAdapterTeamMappingManagementOsId = "ManagementOS"
+)
+
+var networkAdapterTeamMappingIdFormat = []string{"<vm_name or " + networkAdapterTeamMappingManagementOsId + ">", "<network_adapter_name>"}
+
+func resourceHyperVNetworkAdapterTeamMapping() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to affinitize a management os or virtual machine network adapter, connected to a switch embedded teaming (SET) switch, to a specific physical network adapter in the team. It can be imported with an id in the format `<vm_name>|<network_adapter_name>` e.g. `web|wan`, or `ManagementOS|<network_adapter_name>` for a management os network adapter.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadNetworkAdapterTeamMappingTimeout),
+			Create: schema.DefaultTimeout(CreateNetworkAdapterTeamMappingTimeout),
+			Update: schema.DefaultTimeout(UpdateNetworkAdapterTeamMappingTimeout),
+			Delete: schema.DefaultTimeout(DeleteNetworkAdapterTeamMappingTimeout),
+		},
+		CreateContext: resourceHyperVNetworkAdapterTeamMappingCreate,
+		ReadContext:   resourceHyperVNetworkAdapterTeamMappingRead,
+		UpdateContext: resourceHyperVNetworkAdapterTeamMappingUpdate,
+		DeleteContext: resourceHyperVNetworkAdapterTeamMappingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"management_os": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+				ConflictsWith: []string{
+					"vm_name",
+				},
+				Description: "Specifies that the network adapter belongs to the management operating system (host vNIC).",
+			},
+			"vm_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ConflictsWith: []string{
+					"management_os",
+				},
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine that the network adapter belongs to.",
+			},
+			"switch_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the switch embedded teaming (SET) switch the management os network adapter is connected to. Only used when `management_os` is `true`.",
+			},
+			"network_adapter_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual network adapter to affinitize.",
+			},
+			"physical_network_adapter_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the physical network adapter, which must be a member of the switch embedded teaming (SET) team, that traffic for the virtual network adapter should use.",
+			},
+		},
+	}
+}
+
+func getNetworkAdapterTeamMappingId(managementOs bool, vmName string, networkAdapterName string) string {
+	if managementOs {
+		vmName = networkAdapterTeamMappingManagementOsId
+	}
+
+	return getVmDeviceId(vmName, networkAdapterName)
+}
+
+func parseNetworkAdapterTeamMappingId(id string) (managementOs bool, vmName string, networkAdapterName string, err error) {
+	parts, err := parseVmDeviceId("network adapter team mapping", id, networkAdapterTeamMappingIdFormat...)
+	if err != nil {
+		return false, "", "", err
+	}
+
+	if parts[0] == networkAdapterTeamMappingManagementOsId {
+		return true, "", parts[1], nil
+	}
+
+	return false, parts[0], parts[1], nil
+}
+
+func resourceHyperVNetworkAdapterTeamMappingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv network adapter team mapping: %#v", d)
+	c := meta.(api.Client)
+
+	managementOs := (d.Get("management_os")).(bool)
+	vmName := (d.Get("vm_name")).(string)
+	switchName := (d.Get("switch_name")).(string)
+	networkAdapterName := (d.Get("network_adapter_name")).(string)
+	physicalNetworkAdapterName := (d.Get("physical_network_adapter_name")).(string)
+
+	if !managementOs && vmName == "" {
+		return diag.Errorf("[ERROR][hyperv][create] Either management_os must be true or vm_name must be specified")
+	}
+
+	if !managementOs && switchName != "" {
+		return diag.Errorf("[ERROR][hyperv][create] Unable to set switch_name unless management_os is true")
+	}
+
+	id := getNetworkAdapterTeamMappingId(managementOs, vmName, networkAdapterName)
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmNetworkAdapterTeamMapping(ctx, managementOs, vmName, switchName, networkAdapterName)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", id, err))
+		}
+
+		if existing.PhysicalNetworkAdapterName != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", id, "hyperv_network_adapter_team_mapping", "hyperv_network_adapter_team_mapping", id))
+		}
+	}
+
+	err := c.CreateOrUpdateVmNetworkAdapterTeamMapping(ctx, managementOs, vmName, switchName, networkAdapterName, physicalNetworkAdapterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+	log.Printf("[INFO][hyperv][create] created hyperv network adapter team mapping: %#v", d)
+
+	return resourceHyperVNetworkAdapterTeamMappingRead(ctx, d, meta)
+}
+
+func resourceHyperVNetworkAdapterTeamMappingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv network adapter team mapping: %#v", d)
+	c := meta.(api.Client)
+
+	managementOs, vmName, networkAdapterName, err := parseNetworkAdapterTeamMappingId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	switchName := (d.Get("switch_name")).(string)
+
+	teamMapping, err := c.GetVmNetworkAdapterTeamMapping(ctx, managementOs, vmName, switchName, networkAdapterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved network adapter team mapping: %+v", teamMapping)
+
+	if teamMapping.PhysicalNetworkAdapterName == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv network adapter team mapping as it does not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("management_os", managementOs); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vm_name", vmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if managementOs {
+		if err := d.Set("switch_name", teamMapping.SwitchName); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("network_adapter_name", networkAdapterName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("physical_network_adapter_name", teamMapping.PhysicalNetworkAdapterName); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv network adapter team mapping: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVNetworkAdapterTeamMappingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv network adapter team mapping: %#v", d)
+	c := meta.(api.Client)
+
+	managementOs, vmName, networkAdapterName, err := parseNetworkAdapterTeamMappingId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	switchName := (d.Get("switch_name")).(string)
+	physicalNetworkAdapterName := (d.Get("physical_network_adapter_name")).(string)
+
+	if d.HasChange("physical_network_adapter_name") {
+		err = c.CreateOrUpdateVmNetworkAdapterTeamMapping(ctx, managementOs, vmName, switchName, networkAdapterName, physicalNetworkAdapterName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv network adapter team mapping: %#v", d)
+
+	return resourceHyperVNetworkAdapterTeamMappingRead(ctx, d, meta)
+}
+
+func resourceHyperVNetworkAdapterTeamMappingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv network adapter team mapping: %#v", d)
+	c := meta.(api.Client)
+
+	managementOs, vmName, networkAdapterName, err := parseNetworkAdapterTeamMappingId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	switchName := (d.Get("switch_name")).(string)
+
+	err = c.DeleteVmNetworkAdapterTeamMapping(ctx, managementOs, vmName, switchName, networkAdapterName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv network adapter team mapping: %#v", d)
+	return nil
+}
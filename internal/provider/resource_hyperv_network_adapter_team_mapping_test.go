@@ -0,0 +1,59 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceNetworkAdapterTeamMapping(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("switch")
+	netAdapterName := os.Getenv("HYPERV_TEST_NET_ADAPTER_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_NET_ADAPTER_NAME")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVNetworkSwitchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceNetworkAdapterTeamMappingConfig(name, netAdapterName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_network_adapter_team_mapping.this", "switch_name", name),
+					resource.TestCheckResourceAttr("hyperv_network_adapter_team_mapping.this", "physical_network_adapter_name", netAdapterName),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceNetworkAdapterTeamMappingConfig(name string, netAdapterName string) string {
+	return fmt.Sprintf(`
+resource "hyperv_network_switch" "this" {
+	name                    = "%s"
+	switch_type             = "External"
+	allow_management_os     = true
+	enable_embedded_teaming = true
+	net_adapter_names       = ["%s"]
+}
+
+resource "hyperv_network_adapter_team_mapping" "this" {
+	management_os                 = true
+	switch_name                   = hyperv_network_switch.this.name
+	network_adapter_name          = hyperv_network_switch.this.name
+	physical_network_adapter_name = "%s"
+}
+	`, escapeForHcl(name), escapeForHcl(netAdapterName), escapeForHcl(netAdapterName))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -20,8 +21,9 @@ const (
 )
 
 func resourceHyperVNetworkSwitch() *schema.Resource {
-	return &schema.Resource{
-		Description: "This Hyper-V resource allows you to manage virtual network switches.",
+	resource := &schema.Resource{
+		Description:   "This Hyper-V resource allows you to manage virtual network switches.",
+		SchemaVersion: 1,
 		Timeouts: &schema.ResourceTimeout{
 			Read:   schema.DefaultTimeout(ReadNetworkSwitchTimeout),
 			Create: schema.DefaultTimeout(CreateNetworkSwitchTimeout),
@@ -37,9 +39,10 @@ func resourceHyperVNetworkSwitch() *schema.Resource {
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Specifies the name of the switch to be created.",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the switch to be created.",
 			},
 
 			"notes": {
@@ -56,11 +59,25 @@ func resourceHyperVNetworkSwitch() *schema.Resource {
 				Description: "Specifies if the HyperV host machine will have access to network switch when created. It provides this access via a virtual adaptor, so you will need to either configure static ips on the virtual adaptor or configure a dhcp on a machine connected to the network switch. This is tied to the switch type used: `internal=true`;`private=false`;`external=true or false`.",
 			},
 
+			"management_os_vlan_id": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: ValueOrIntBetween(0, 1, 4094),
+				Description:      "Should be a value of `0` or between `1` to `4094`. Specifies the access VLAN of the virtual adaptor the HyperV host machine uses to access the network switch. Can only be set when `allow_management_os` is `true`. When the switch is created with a VLAN the virtual adaptor is tagged before it is connected, so the host does not lose connectivity when the switch is bound to a tagged uplink. Use `0` for untagged traffic.",
+			},
+
+			"recreate_in_place": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Changes to `enable_embedded_teaming`, `enable_iov`, `enable_packet_direct` and `minimum_bandwidth_mode` can only be made by recreating the switch. By default Terraform destroys and then creates the switch in separate operations, which severs the connection to the HyperV host machine part way through when it is managed over the network adaptor bound to the switch. When `true` the switch is replaced by a single remote script that removes the switch, creates it again, restores the static ip addresses, default routes and dns servers of the management os virtual adaptor and reconnects the virtual machine network adapters that were connected to the switch.",
+			},
+
 			"enable_embedded_teaming": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				ForceNew:    true,
 				Description: "Specifies if the HyperV host machine will enable teaming for network switch when created. It allows NIC teaming so that you could support scenarios such as redundant links.",
 			},
 
@@ -68,15 +85,13 @@ func resourceHyperVNetworkSwitch() *schema.Resource {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				ForceNew:    true,
-				Description: "Specifies if the HyperV host machine will enable IO virtualization for network switch when created. If your hardware supports it, it enables the virtual machine to talk directly to the NIC.",
+				Description: "Specifies if the HyperV host machine will enable IO virtualization for network switch when created. If your hardware supports it, it enables the virtual machine to talk directly to the NIC. During plan the host and the network adapters in `net_adapter_names` are checked for SR-IOV support.",
 			},
 
 			"enable_packet_direct": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				ForceNew:    true,
 				Description: "Specifies if the HyperV host machine will enable packet direct path for network switch when created. Increases packet throughoutput and reduces the network latency between vms on the switch.",
 			},
 
@@ -85,8 +100,8 @@ func resourceHyperVNetworkSwitch() *schema.Resource {
 				Optional:         true,
 				Default:          api.VMSwitchBandwidthMode_name[api.VMSwitchBandwidthMode_None],
 				ValidateDiagFunc: stringKeyInMap(api.VMSwitchBandwidthMode_value, true),
-				ForceNew:         true,
-				Description:      "Specifies how minimum bandwidth is to be configured on the virtual switch. If `Absolute` is specified, minimum bandwidth is bits per second. If `Weight` is specified, minimum bandwidth is a value ranging from `1` to `100`. If `None` is specified, minimum bandwidth is disabled on the switch – that is, users cannot configure it on any network adapter connected to the switch. If `Default` is specified, the system will set the mode to Weight, if the switch is not IOV-enabled, or `None` if the switch is IOV-enabled. Valid values to use are `Absolute`, `Default`, `None`, `Weight`.",
+				DiffSuppressFunc: api.DiffSuppressVmSwitchBandwidthReservationMode,
+				Description:      "Specifies how minimum bandwidth is to be configured on the virtual switch. If `Absolute` is specified, minimum bandwidth is bits per second. If `Weight` is specified, minimum bandwidth is a value ranging from `1` to `100`. If `None` is specified, minimum bandwidth is disabled on the switch – that is, users cannot configure it on any network adapter connected to the switch. If `Default` is specified, the system will set the mode to Weight, if the switch is not IOV-enabled, or `None` if the switch is IOV-enabled. Hyper-V only allows the minimum bandwidth mode to be set when the switch is created, so changing it will recreate the switch (see `recreate_in_place`). Valid values to use are `Absolute`, `Default`, `None`, `Weight`.",
 			},
 
 			"switch_type": {
@@ -94,6 +109,7 @@ func resourceHyperVNetworkSwitch() *schema.Resource {
 				Optional:         true,
 				Default:          api.VMSwitchType_name[api.VMSwitchType_Internal],
 				ValidateDiagFunc: stringKeyInMap(api.VMSwitchType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
 				Description:      "Specifies the type of the switch to be created. Valid values to use are `Internal`, `Private` and `External`.",
 			},
 
@@ -101,14 +117,33 @@ func resourceHyperVNetworkSwitch() *schema.Resource {
 				Type:        schema.TypeList,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Optional:    true,
-				Description: "Specifies the name of the network adapter to be bound to the switch to be created. ",
+				Description: "Specifies the name of the network adapter to be bound to the switch to be created. When `enable_embedded_teaming` is `true`, up to 8 network adapters can be specified to create a switch embedded teaming (SET) switch.",
+			},
+
+			"load_balancing_algorithm": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: stringKeyInMap(api.VMSwitchLoadBalancingAlgorithm_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the load balancing algorithm that the switch embedded teaming (SET) team uses to distribute traffic across the network adapters in `net_adapter_names`. Can only be set when `enable_embedded_teaming` is `true`. When not set the host default is used. Valid values to use are `HyperVPort`, `Dynamic`.",
+			},
+
+			"teaming_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VMSwitchTeamingMode_name[api.VMSwitchTeamingMode_SwitchIndependent],
+				ValidateDiagFunc: stringKeyInMap(api.VMSwitchTeamingMode_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the teaming mode of the switch embedded teaming (SET) team. Only used when `enable_embedded_teaming` is `true`. Switch embedded teaming only supports `SwitchIndependent`, any other value is rejected during plan when `enable_embedded_teaming` is `true`. Valid values to use are `SwitchIndependent`, `Static`, `Lacp`.",
 			},
 
 			"default_flow_minimum_bandwidth_absolute": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     0,
-				Description: "Specifies the minimum bandwidth, in bits per second, that is allocated to a special category called `default flow`. Any traffic sent by a virtual network adapter that is connected to this virtual switch and does not have minimum bandwidth allocated is filtered into this category. Specify a value for this parameter only if the minimum bandwidth mode on this virtual switch is absolute. By default, the virtual switch allocates 10% of the total bandwidth, which depends on the physical network adapter it binds to, to this category. For example, if a virtual switch binds to a 1 GbE network adapter, this special category can use at least 100 Mbps. If the value is not a multiple of 8, the value is rounded down to the nearest number that is a multiple of 8. For example, a value input as 1234567 is converted to 1234560.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: IsDivisibleBy(8),
+				Description:      "Specifies the minimum bandwidth, in bits per second, that is allocated to a special category called `default flow`. Any traffic sent by a virtual network adapter that is connected to this virtual switch and does not have minimum bandwidth allocated is filtered into this category. Specify a value for this parameter only if the minimum bandwidth mode on this virtual switch is absolute. By default, the virtual switch allocates 10% of the total bandwidth, which depends on the physical network adapter it binds to, to this category. For example, if a virtual switch binds to a 1 GbE network adapter, this special category can use at least 100 Mbps. When not set the value allocated by the virtual switch is kept, removing it from the configuration keeps the current value. The value must be a multiple of 8, as Hyper-V rounds it down to the nearest multiple of 8.",
 			},
 
 			"default_flow_minimum_bandwidth_weight": {
@@ -139,8 +174,108 @@ func resourceHyperVNetworkSwitch() *schema.Resource {
 				Default:     false,
 				Description: "Should Virtual Receive Side Scaling be enabled. This configuration allows the load from a virtual network adapter to be distributed across multiple virtual processors in a virtual machine (VM), allowing the VM to process more network traffic more rapidly than it can with a single logical processor.",
 			},
+
+			"enable_packet_capture": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether the `Microsoft NDIS Capture` extension is enabled on the switch, so that its packets can be captured with `pktmon` or `netsh trace`. Use the `hyperv_vswitch_extension` resource for any other extension, but not for this one as well.",
+			},
+
+			"force": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether the network adapters of virtual machines that are still connected to the switch are disconnected when the switch is destroyed. When `false` destroying a switch that virtual machines are connected to fails with the list of those virtual machines.",
+			},
 		},
+		CustomizeDiff: customizeDiffForNetworkSwitch,
+	}
+
+	// the attributes added since the state was written by a provider that did not version the schema
+	resource.StateUpgraders = []schema.StateUpgrader{
+		stateUpgraderV0("hyperv_network_switch", resource,
+			"enable_packet_capture",
+			"force",
+			"load_balancing_algorithm",
+			"management_os_vlan_id",
+			"recreate_in_place",
+			"teaming_mode",
+		),
+	}
+
+	return resource
+}
+
+// networkSwitchRecreateKeys are the attributes that Hyper-V only allows to be set when the switch is created.
+var networkSwitchRecreateKeys = []string{
+	"enable_embedded_teaming",
+	"enable_iov",
+	"enable_packet_direct",
+	"minimum_bandwidth_mode",
+}
+
+func customizeDiffForNetworkSwitch(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() != "" && !diff.Get("recreate_in_place").(bool) {
+		for _, key := range networkSwitchRecreateKeys {
+			if diff.HasChange(key) {
+				if err := diff.ForceNew(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if diff.Get("enable_embedded_teaming").(bool) && diff.NewValueKnown("teaming_mode") {
+		teamingMode := api.ToVMSwitchTeamingMode(diff.Get("teaming_mode").(string))
+		if teamingMode != api.VMSwitchTeamingMode_SwitchIndependent {
+			return fmt.Errorf("[ERROR][hyperv][plan] Unable to set TeamingMode to %s as switch embedded teaming only supports %s", teamingMode.String(), api.VMSwitchTeamingMode_SwitchIndependent.String())
+		}
+	}
+
+	iovEnabled := diff.Get("enable_iov").(bool)
+	if !iovEnabled {
+		return nil
+	}
+
+	if diff.Id() != "" && !diff.HasChange("enable_iov") && !diff.HasChange("net_adapter_names") {
+		return nil
+	}
+
+	if !diff.NewValueKnown("net_adapter_names") {
+		// network adapters will only be known during apply
+		return nil
+	}
+
+	netAdapterNames := []string{}
+	if raw, ok := diff.GetOk("net_adapter_names"); ok {
+		for _, v := range raw.([]interface{}) {
+			netAdapterNames = append(netAdapterNames, v.(string))
+		}
+	}
+
+	if len(netAdapterNames) < 1 {
+		return fmt.Errorf("[ERROR][hyperv][plan] Must specify NetAdapterNames if EnableIov is true")
+	}
+
+	c := meta.(api.Client)
+
+	iovSupport, err := c.GetVMSwitchIovSupport(ctx, netAdapterNames)
+	if err != nil {
+		return err
 	}
+
+	if !iovSupport.HostIovSupport {
+		return fmt.Errorf("[ERROR][hyperv][plan] Unable to set EnableIov to true as host does not support SR-IOV: %s", strings.Join(iovSupport.HostIovSupportReasons, "; "))
+	}
+
+	for _, netAdapter := range iovSupport.NetAdapters {
+		if netAdapter.SriovSupport != "Supported" {
+			return fmt.Errorf("[ERROR][hyperv][plan] Unable to set EnableIov to true as network adapter %q does not support SR-IOV: %s", netAdapter.Name, netAdapter.SriovSupport)
+		}
+	}
+
+	return nil
 }
 
 func resourceHyperVNetworkSwitchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -168,6 +303,7 @@ func resourceHyperVNetworkSwitchCreate(ctx context.Context, d *schema.ResourceDa
 
 	notes := (d.Get("notes")).(string)
 	allowManagementOS := (d.Get("allow_management_os")).(bool)
+	managementOsVlanId := (d.Get("management_os_vlan_id")).(int)
 	embeddedTeamingEnabled := (d.Get("enable_embedded_teaming")).(bool)
 	iovEnabled := (d.Get("enable_iov")).(bool)
 	packetDirectEnabled := (d.Get("enable_packet_direct")).(bool)
@@ -179,11 +315,14 @@ func resourceHyperVNetworkSwitchCreate(ctx context.Context, d *schema.ResourceDa
 			netAdapterNames = append(netAdapterNames, v.(string))
 		}
 	}
+	loadBalancingAlgorithm := api.ToVMSwitchLoadBalancingAlgorithm((d.Get("load_balancing_algorithm")).(string))
+	teamingMode := api.ToVMSwitchTeamingMode((d.Get("teaming_mode")).(string))
 	defaultFlowMinimumBandwidthAbsolute := int64((d.Get("default_flow_minimum_bandwidth_absolute")).(int))
 	defaultFlowMinimumBandwidthWeight := int64((d.Get("default_flow_minimum_bandwidth_weight")).(int))
 	defaultQueueVmmqEnabled := (d.Get("default_queue_vmmq_enabled")).(bool)
 	defaultQueueVmmqQueuePairs := int32((d.Get("default_queue_vmmq_queue_pairs")).(int))
 	defaultQueueVrssEnabled := (d.Get("default_queue_vrss_enabled")).(bool)
+	packetCaptureEnabled := (d.Get("enable_packet_capture")).(bool)
 
 	if switchType == api.VMSwitchType_Private {
 		if allowManagementOS {
@@ -207,6 +346,28 @@ func resourceHyperVNetworkSwitchCreate(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 
+	if !allowManagementOS && managementOsVlanId != 0 {
+		return diag.Errorf("[ERROR][hyperv][create] Unable to set ManagementOsVlanId unless AllowManagementOS is true")
+	}
+
+	if embeddedTeamingEnabled {
+		if switchType != api.VMSwitchType_External {
+			return diag.Errorf("[ERROR][hyperv][create] Unable to set EnableEmbeddedTeaming to true unless switch type is external")
+		}
+
+		if len(netAdapterNames) > 8 {
+			return diag.Errorf("[ERROR][hyperv][create] Switch embedded teaming supports a maximum of 8 NetAdapterNames")
+		}
+	} else {
+		if len(netAdapterNames) > 1 {
+			return diag.Errorf("[ERROR][hyperv][create] Must set EnableEmbeddedTeaming to true to bind more than one of NetAdapterNames to the switch")
+		}
+
+		if loadBalancingAlgorithm != 0 {
+			return diag.Errorf("[ERROR][hyperv][create] Unable to set LoadBalancingAlgorithm unless EnableEmbeddedTeaming is true")
+		}
+	}
+
 	if bandwidthReservationMode == api.VMSwitchBandwidthMode_Absolute {
 		if defaultFlowMinimumBandwidthWeight != 0 {
 			return diag.Errorf("[ERROR][hyperv][create] Unable to set DefaultFlowMinimumBandwidthWeight if bandwidth reservation mode is absolute")
@@ -234,12 +395,19 @@ func resourceHyperVNetworkSwitchCreate(ctx context.Context, d *schema.ResourceDa
 		return diag.Errorf("[ERROR][hyperv][create] defaultQueueVmmqQueuePairs must be greater then 0")
 	}
 
-	err := c.CreateVMSwitch(ctx, switchName, notes, allowManagementOS, embeddedTeamingEnabled, iovEnabled, packetDirectEnabled, bandwidthReservationMode, switchType, netAdapterNames, defaultFlowMinimumBandwidthAbsolute, defaultFlowMinimumBandwidthWeight, defaultQueueVmmqEnabled, defaultQueueVmmqQueuePairs, defaultQueueVrssEnabled)
+	err := c.CreateVMSwitch(ctx, switchName, notes, allowManagementOS, managementOsVlanId, embeddedTeamingEnabled, iovEnabled, packetDirectEnabled, bandwidthReservationMode, switchType, netAdapterNames, loadBalancingAlgorithm, teamingMode, defaultFlowMinimumBandwidthAbsolute, defaultFlowMinimumBandwidthWeight, defaultQueueVmmqEnabled, defaultQueueVmmqQueuePairs, defaultQueueVrssEnabled)
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if packetCaptureEnabled {
+		err = c.UpdateVMSwitchExtension(ctx, switchName, api.VmSwitchPacketCaptureExtensionName, packetCaptureEnabled)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	d.SetId(switchName)
 	log.Printf("[INFO][hyperv][create] created hyperv switch: %#v", d)
 
@@ -259,7 +427,7 @@ func resourceHyperVNetworkSwitchRead(ctx context.Context, d *schema.ResourceData
 
 	log.Printf("[INFO][hyperv][read] retrieved network switch: %+v", s)
 
-	if s.Name != name {
+	if !strings.EqualFold(s.Name, name) {
 		log.Printf("[INFO][hyperv][read] unable to read hyperv switch as it does not exist: %#v", name)
 		return nil
 	}
@@ -326,6 +494,9 @@ func resourceHyperVNetworkSwitchRead(ctx context.Context, d *schema.ResourceData
 	if err := d.Set("allow_management_os", s.AllowManagementOS); err != nil {
 		return diag.FromErr(err)
 	}
+	if err := d.Set("management_os_vlan_id", s.ManagementOsVlanId); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := d.Set("enable_embedded_teaming", s.EmbeddedTeamingEnabled); err != nil {
 		return diag.FromErr(err)
 	}
@@ -341,9 +512,17 @@ func resourceHyperVNetworkSwitchRead(ctx context.Context, d *schema.ResourceData
 	if err := d.Set("switch_type", s.SwitchType.String()); err != nil {
 		return diag.FromErr(err)
 	}
-	if err := d.Set("net_adapter_names", s.NetAdapterNames); err != nil {
+	if err := d.Set("net_adapter_names", api.PreferConfiguredCase(d, "net_adapter_names", s.NetAdapterNames)); err != nil {
 		return diag.FromErr(err)
 	}
+	if s.EmbeddedTeamingEnabled {
+		if err := d.Set("load_balancing_algorithm", s.LoadBalancingAlgorithm.String()); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("teaming_mode", s.TeamingMode.String()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	if err := d.Set("default_flow_minimum_bandwidth_absolute", s.DefaultFlowMinimumBandwidthAbsolute); err != nil {
 		return diag.FromErr(err)
 	}
@@ -360,6 +539,14 @@ func resourceHyperVNetworkSwitchRead(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 
+	packetCaptureExtension, err := c.GetVMSwitchExtension(ctx, name, api.VmSwitchPacketCaptureExtensionName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enable_packet_capture", packetCaptureExtension.Enabled); err != nil {
+		return diag.FromErr(err)
+	}
+
 	log.Printf("[INFO][hyperv][read] read hyperv switch: %#v", d)
 
 	return nil
@@ -372,9 +559,10 @@ func resourceHyperVNetworkSwitchUpdate(ctx context.Context, d *schema.ResourceDa
 	switchName := d.Id()
 	notes := (d.Get("notes")).(string)
 	allowManagementOS := (d.Get("allow_management_os")).(bool)
-	// embeddedTeamingEnabled := (d.Get("enable_embedded_teaming")).(bool)
+	managementOsVlanId := (d.Get("management_os_vlan_id")).(int)
+	embeddedTeamingEnabled := (d.Get("enable_embedded_teaming")).(bool)
 	iovEnabled := (d.Get("enable_iov")).(bool)
-	// packetDirectEnabled := (d.Get("enable_packet_direct")).(bool)
+	packetDirectEnabled := (d.Get("enable_packet_direct")).(bool)
 	bandwidthReservationMode := api.ToVMSwitchBandwidthMode((d.Get("minimum_bandwidth_mode")).(string))
 	switchType := api.ToVMSwitchType((d.Get("switch_type")).(string))
 	netAdapterNames := []string{}
@@ -383,11 +571,14 @@ func resourceHyperVNetworkSwitchUpdate(ctx context.Context, d *schema.ResourceDa
 			netAdapterNames = append(netAdapterNames, v.(string))
 		}
 	}
+	loadBalancingAlgorithm := api.ToVMSwitchLoadBalancingAlgorithm((d.Get("load_balancing_algorithm")).(string))
+	teamingMode := api.ToVMSwitchTeamingMode((d.Get("teaming_mode")).(string))
 	defaultFlowMinimumBandwidthAbsolute := int64((d.Get("default_flow_minimum_bandwidth_absolute")).(int))
 	defaultFlowMinimumBandwidthWeight := int64((d.Get("default_flow_minimum_bandwidth_weight")).(int))
 	defaultQueueVmmqEnabled := (d.Get("default_queue_vmmq_enabled")).(bool)
 	defaultQueueVmmqQueuePairs := int32((d.Get("default_queue_vmmq_queue_pairs")).(int))
 	defaultQueueVrssEnabled := (d.Get("default_queue_vrss_enabled")).(bool)
+	packetCaptureEnabled := (d.Get("enable_packet_capture")).(bool)
 
 	if switchType == api.VMSwitchType_Private {
 		if allowManagementOS {
@@ -411,6 +602,28 @@ func resourceHyperVNetworkSwitchUpdate(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 
+	if !allowManagementOS && managementOsVlanId != 0 {
+		return diag.Errorf("[ERROR][hyperv][update] Unable to set ManagementOsVlanId unless AllowManagementOS is true")
+	}
+
+	if embeddedTeamingEnabled {
+		if switchType != api.VMSwitchType_External {
+			return diag.Errorf("[ERROR][hyperv][update] Unable to set EnableEmbeddedTeaming to true unless switch type is external")
+		}
+
+		if len(netAdapterNames) > 8 {
+			return diag.Errorf("[ERROR][hyperv][update] Switch embedded teaming supports a maximum of 8 NetAdapterNames")
+		}
+	} else {
+		if len(netAdapterNames) > 1 {
+			return diag.Errorf("[ERROR][hyperv][update] Must set EnableEmbeddedTeaming to true to bind more than one of NetAdapterNames to the switch")
+		}
+
+		if loadBalancingAlgorithm != 0 {
+			return diag.Errorf("[ERROR][hyperv][update] Unable to set LoadBalancingAlgorithm unless EnableEmbeddedTeaming is true")
+		}
+	}
+
 	if bandwidthReservationMode == api.VMSwitchBandwidthMode_Absolute {
 		if defaultFlowMinimumBandwidthWeight != 0 {
 			return diag.Errorf("[ERROR][hyperv][update] Unable to set DefaultFlowMinimumBandwidthWeight if bandwidth reservation mode is absolute")
@@ -438,12 +651,27 @@ func resourceHyperVNetworkSwitchUpdate(ctx context.Context, d *schema.ResourceDa
 		return diag.Errorf("[ERROR][hyperv][update] defaultQueueVmmqQueuePairs must be greater then 0")
 	}
 
-	err := c.UpdateVMSwitch(ctx, switchName, notes, allowManagementOS, switchType, netAdapterNames, defaultFlowMinimumBandwidthAbsolute, defaultFlowMinimumBandwidthWeight, defaultQueueVmmqEnabled, defaultQueueVmmqQueuePairs, defaultQueueVrssEnabled)
+	var err error
+	recreated := (d.Get("recreate_in_place")).(bool) && d.HasChanges(networkSwitchRecreateKeys...)
+	if recreated {
+		log.Printf("[INFO][hyperv][update] recreating hyperv switch in place: %#v", switchName)
+		err = c.RecreateVMSwitch(ctx, switchName, notes, allowManagementOS, managementOsVlanId, embeddedTeamingEnabled, iovEnabled, packetDirectEnabled, bandwidthReservationMode, switchType, netAdapterNames, loadBalancingAlgorithm, teamingMode, defaultFlowMinimumBandwidthAbsolute, defaultFlowMinimumBandwidthWeight, defaultQueueVmmqEnabled, defaultQueueVmmqQueuePairs, defaultQueueVrssEnabled)
+	} else {
+		err = c.UpdateVMSwitch(ctx, switchName, notes, allowManagementOS, managementOsVlanId, switchType, netAdapterNames, loadBalancingAlgorithm, teamingMode, defaultFlowMinimumBandwidthAbsolute, defaultFlowMinimumBandwidthWeight, defaultQueueVmmqEnabled, defaultQueueVmmqQueuePairs, defaultQueueVrssEnabled)
+	}
 
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// a recreated switch starts with the extensions of a new switch
+	if d.HasChange("enable_packet_capture") || (recreated && packetCaptureEnabled) {
+		err = c.UpdateVMSwitchExtension(ctx, switchName, api.VmSwitchPacketCaptureExtensionName, packetCaptureEnabled)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Printf("[INFO][hyperv][update] updated hyperv switch: %#v", d)
 
 	return resourceHyperVNetworkSwitchRead(ctx, d, meta)
@@ -455,7 +683,31 @@ func resourceHyperVNetworkSwitchDelete(ctx context.Context, d *schema.ResourceDa
 	c := meta.(api.Client)
 
 	switchName := d.Id()
-	err := c.DeleteVMSwitch(ctx, switchName)
+	force := (d.Get("force")).(bool)
+
+	networkAdapters, err := c.GetVMSwitchNetworkAdapters(ctx, switchName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(networkAdapters) > 0 {
+		if !force {
+			connected := make([]string, 0)
+			for _, networkAdapter := range networkAdapters {
+				connected = append(connected, fmt.Sprintf("%s (%s)", networkAdapter.VmName, networkAdapter.Name))
+			}
+
+			return diag.Errorf("[ERROR][hyperv][delete] unable to delete switch %s as network adapters of virtual machines are still connected to it: %s. Disconnect them, or set force to true to disconnect them when the switch is destroyed", switchName, strings.Join(connected, ", "))
+		}
+
+		log.Printf("[INFO][hyperv][delete] disconnecting network adapters from switch %s: %+v", switchName, networkAdapters)
+		err = c.DisconnectVMSwitchNetworkAdapters(ctx, switchName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = c.DeleteVMSwitch(ctx, switchName)
 
 	if err != nil {
 		return diag.FromErr(err)
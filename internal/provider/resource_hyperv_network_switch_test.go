@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func TestHyperVResourceNetworkSwitch(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("switch")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVNetworkSwitchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceNetworkSwitchConfig(name, "created", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_network_switch.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_network_switch.this", "switch_type", "Internal"),
+					resource.TestCheckResourceAttr("hyperv_network_switch.this", "notes", "created"),
+					resource.TestCheckResourceAttr("hyperv_network_switch.this", "enable_packet_capture", "false"),
+				),
+			},
+			{
+				Config: testHyperVResourceNetworkSwitchConfig(name, "updated", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_network_switch.this", "notes", "updated"),
+					resource.TestCheckResourceAttr("hyperv_network_switch.this", "enable_packet_capture", "true"),
+				),
+			},
+		},
+	})
+}
+
+var testAccCheckHyperVNetworkSwitchDestroy = testAccCheckDestroy("hyperv_network_switch", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	vmSwitchExists, err := c.VMSwitchExists(ctx, id)
+	return vmSwitchExists.Exists, err
+})
+
+func testHyperVResourceNetworkSwitchConfig(name string, notes string, packetCaptureEnabled bool) string {
+	return fmt.Sprintf(`
+resource "hyperv_network_switch" "this" {
+	name                  = "%s"
+	switch_type           = "Internal"
+	notes                 = "%s"
+	enable_packet_capture = %t
+}
+	`, escapeForHcl(name), notes, packetCaptureEnabled)
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadReplicaAuthorizationEntryTimeout   = 1 * time.Minute
+	CreateReplicaAuthorizationEntryTimeout = 2 * time.Minute
+	UpdateReplicaAuthorizationEntryTimeout = 2 * time.Minute
+	DeleteReplicaAuthorizationEntryTimeout = 1 * time.Minute
+)
+
+func resourceHyperVReplicaAuthorizationEntry() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to authorize a primary server to replicate virtual machines to the HyperV host machine when it is configured as a replica server, see `hyperv_host_replication_settings`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadReplicaAuthorizationEntryTimeout),
+			Create: schema.DefaultTimeout(CreateReplicaAuthorizationEntryTimeout),
+			Update: schema.DefaultTimeout(UpdateReplicaAuthorizationEntryTimeout),
+			Delete: schema.DefaultTimeout(DeleteReplicaAuthorizationEntryTimeout),
+		},
+		CreateContext: resourceHyperVReplicaAuthorizationEntryCreate,
+		ReadContext:   resourceHyperVReplicaAuthorizationEntryRead,
+		UpdateContext: resourceHyperVReplicaAuthorizationEntryUpdate,
+		DeleteContext: resourceHyperVReplicaAuthorizationEntryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"allowed_primary_server": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the fully qualified domain name of the primary server that is allowed to replicate to the replica server. Wildcards are supported e.g. `*.contoso.com`.",
+			},
+			"replica_storage_location": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the folder to store the replica virtual machines from the primary server in.",
+			},
+			"trust_group": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "DEFAULT",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the trust group of the authorization entry. Primary servers in the same trust group can fail over virtual machines to each other's replicas.",
+			},
+		},
+	}
+}
+
+func resourceHyperVReplicaAuthorizationEntryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv replica authorization entry: %#v", d)
+	c := meta.(api.Client)
+
+	allowedPrimaryServer := (d.Get("allowed_primary_server")).(string)
+	replicaStorageLocation := (d.Get("replica_storage_location")).(string)
+	trustGroup := (d.Get("trust_group")).(string)
+
+	if d.IsNewResource() {
+		existing, err := c.GetVMReplicationAuthorizationEntry(ctx, allowedPrimaryServer)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", allowedPrimaryServer, err))
+		}
+
+		if existing.AllowedPrimaryServer != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", allowedPrimaryServer, "hyperv_replica_authorization_entry", "hyperv_replica_authorization_entry", allowedPrimaryServer))
+		}
+	}
+
+	err := c.CreateVMReplicationAuthorizationEntry(ctx, allowedPrimaryServer, replicaStorageLocation, trustGroup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(allowedPrimaryServer)
+	log.Printf("[INFO][hyperv][create] created hyperv replica authorization entry: %#v", d)
+
+	return resourceHyperVReplicaAuthorizationEntryRead(ctx, d, meta)
+}
+
+func resourceHyperVReplicaAuthorizationEntryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv replica authorization entry: %#v", d)
+	c := meta.(api.Client)
+
+	allowedPrimaryServer := d.Id()
+
+	entry, err := c.GetVMReplicationAuthorizationEntry(ctx, allowedPrimaryServer)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved replica authorization entry: %+v", entry)
+
+	if entry.AllowedPrimaryServer == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv replica authorization entry as it does not exist: %#v", allowedPrimaryServer)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("allowed_primary_server", entry.AllowedPrimaryServer); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("replica_storage_location", entry.ReplicaStorageLocation); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("trust_group", entry.TrustGroup); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv replica authorization entry: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVReplicaAuthorizationEntryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv replica authorization entry: %#v", d)
+	c := meta.(api.Client)
+
+	allowedPrimaryServer := d.Id()
+	replicaStorageLocation := (d.Get("replica_storage_location")).(string)
+	trustGroup := (d.Get("trust_group")).(string)
+
+	err := c.UpdateVMReplicationAuthorizationEntry(ctx, allowedPrimaryServer, replicaStorageLocation, trustGroup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv replica authorization entry: %#v", d)
+
+	return resourceHyperVReplicaAuthorizationEntryRead(ctx, d, meta)
+}
+
+func resourceHyperVReplicaAuthorizationEntryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv replica authorization entry: %#v", d)
+	c := meta.(api.Client)
+
+	allowedPrimaryServer := d.Id()
+
+	err := c.DeleteVMReplicationAuthorizationEntry(ctx, allowedPrimaryServer)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv replica authorization entry: %#v", d)
+	return nil
+}
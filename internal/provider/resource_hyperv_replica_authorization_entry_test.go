@@ -0,0 +1,56 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceReplicaAuthorizationEntry(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("primary")
+	path := testAccPath("replica")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_HOST_SETTINGS")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceReplicaAuthorizationEntryConfig(name, path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_replica_authorization_entry.this", "replica_storage_location", path),
+					resource.TestCheckResourceAttr("hyperv_replica_authorization_entry.this", "trust_group", "DEFAULT"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceReplicaAuthorizationEntryConfig(name string, path string) string {
+	return fmt.Sprintf(`
+resource "hyperv_host_replication_settings" "this" {
+	replication_enabled         = true
+	allowed_authentication_type = "Kerberos"
+}
+
+resource "hyperv_replica_authorization_entry" "this" {
+	allowed_primary_server   = "%s.contoso.com"
+	replica_storage_location = "%s"
+	trust_group              = "DEFAULT"
+
+	depends_on = [hyperv_host_replication_settings.this]
+}
+	`, escapeForHcl(strings.ReplaceAll(name, "_", "-")), escapeForHcl(path))
+}
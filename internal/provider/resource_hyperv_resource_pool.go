@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadResourcePoolTimeout   = 1 * time.Minute
+	CreateResourcePoolTimeout = 2 * time.Minute
+	UpdateResourcePoolTimeout = 2 * time.Minute
+	DeleteResourcePoolTimeout = 1 * time.Minute
+)
+
+var resourcePoolIdFormat = []string{"<pool_type>", "<name>"}
+
+func resourceHyperVResourcePool() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage resource pools on the HyperV host machine. Virtual machine memory, processors, network adapters, DVD drives and hard disk drives can be associated with a resource pool of the matching type by name, which is used for quotas and resource metering. It can be imported with an id in the format `<pool_type>|<name>` e.g. `Memory|tenants`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadResourcePoolTimeout),
+			Create: schema.DefaultTimeout(CreateResourcePoolTimeout),
+			Update: schema.DefaultTimeout(UpdateResourcePoolTimeout),
+			Delete: schema.DefaultTimeout(DeleteResourcePoolTimeout),
+		},
+		CreateContext: resourceHyperVResourcePoolCreate,
+		ReadContext:   resourceHyperVResourcePoolRead,
+		UpdateContext: resourceHyperVResourcePoolUpdate,
+		DeleteContext: resourceHyperVResourcePoolDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the resource pool.",
+			},
+			"pool_type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: stringKeyInMap(api.VMResourcePoolType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the type of the resource pool. Valid values to use are `Memory`, `Processor`, `Ethernet`, `VHD`, `ISO`, `VFD`, `FibreChannelPort`, `FibreChannelConnection`, `PciExpress`.",
+			},
+			"paths": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: IsWindowsPath()},
+				Set:         schema.HashString,
+				Description: "Specifies the folders that belong to the resource pool. Required for `VHD`, `ISO` and `VFD` resource pools and not valid for other pool types.",
+			},
+			"resource_metering_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether the resource usage of the resource pool is metered.",
+			},
+		},
+	}
+}
+
+func getResourcePoolId(resourcePoolType api.VMResourcePoolType, name string) string {
+	return getVmDeviceId(resourcePoolType.String(), name)
+}
+
+func parseResourcePoolId(id string) (resourcePoolType api.VMResourcePoolType, name string, err error) {
+	parts, err := parseVmDeviceId("resource pool", id, resourcePoolIdFormat...)
+	if err != nil {
+		return resourcePoolType, name, err
+	}
+
+	if _, ok := api.VMResourcePoolType_value[strings.ToLower(parts[0])]; !ok {
+		return resourcePoolType, name, fmt.Errorf("[ERROR][hyperv] resource pool id %q has an unknown pool type %q", id, parts[0])
+	}
+
+	return api.ToVMResourcePoolType(parts[0]), parts[1], nil
+}
+
+func expandResourcePoolPaths(d *schema.ResourceData) []string {
+	paths := []string{}
+	if raw, ok := d.GetOk("paths"); ok {
+		for _, v := range raw.(*schema.Set).List() {
+			paths = append(paths, v.(string))
+		}
+	}
+
+	return paths
+}
+
+func resourceHyperVResourcePoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv resource pool: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+	resourcePoolType := api.ToVMResourcePoolType((d.Get("pool_type")).(string))
+	paths := expandResourcePoolPaths(d)
+	resourceMeteringEnabled := (d.Get("resource_metering_enabled")).(bool)
+
+	switch resourcePoolType {
+	case api.VMResourcePoolType_VHD, api.VMResourcePoolType_ISO, api.VMResourcePoolType_VFD:
+		if len(paths) < 1 {
+			return diag.Errorf("[ERROR][hyperv][create] paths must be specified for a %s resource pool", resourcePoolType.String())
+		}
+	default:
+		if len(paths) > 0 {
+			return diag.Errorf("[ERROR][hyperv][create] paths can only be specified for a VHD, ISO or VFD resource pool")
+		}
+	}
+
+	id := getResourcePoolId(resourcePoolType, name)
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmResourcePool(ctx, name, resourcePoolType)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", id, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", id, "hyperv_resource_pool", "hyperv_resource_pool", id))
+		}
+	}
+
+	err := c.CreateVmResourcePool(ctx, name, resourcePoolType, paths, resourceMeteringEnabled)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+	log.Printf("[INFO][hyperv][create] created hyperv resource pool: %#v", d)
+
+	return resourceHyperVResourcePoolRead(ctx, d, meta)
+}
+
+func resourceHyperVResourcePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv resource pool: %#v", d)
+	c := meta.(api.Client)
+
+	resourcePoolType, name, err := parseResourcePoolId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resourcePool, err := c.GetVmResourcePool(ctx, name, resourcePoolType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved resource pool: %+v", resourcePool)
+
+	if resourcePool.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv resource pool as it does not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", resourcePool.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("pool_type", resourcePool.ResourcePoolType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("paths", api.PreferConfiguredCase(d, "paths", resourcePool.Paths)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("resource_metering_enabled", resourcePool.ResourceMeteringEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv resource pool: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVResourcePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv resource pool: %#v", d)
+	c := meta.(api.Client)
+
+	resourcePoolType, name, err := parseResourcePoolId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	paths := expandResourcePoolPaths(d)
+	resourceMeteringEnabled := (d.Get("resource_metering_enabled")).(bool)
+
+	err = c.UpdateVmResourcePool(ctx, name, resourcePoolType, paths, resourceMeteringEnabled)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv resource pool: %#v", d)
+
+	return resourceHyperVResourcePoolRead(ctx, d, meta)
+}
+
+func resourceHyperVResourcePoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv resource pool: %#v", d)
+	c := meta.(api.Client)
+
+	resourcePoolType, name, err := parseResourcePoolId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.DeleteVmResourcePool(ctx, name, resourcePoolType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv resource pool: %#v", d)
+	return nil
+}
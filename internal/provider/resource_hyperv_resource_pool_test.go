@@ -0,0 +1,51 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceResourcePool(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("pool")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceResourcePoolConfig(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_resource_pool.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_resource_pool.this", "pool_type", "Memory"),
+					resource.TestCheckResourceAttr("hyperv_resource_pool.this", "resource_metering_enabled", "true"),
+				),
+			},
+			{
+				Config: testHyperVResourceResourcePoolConfig(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_resource_pool.this", "resource_metering_enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceResourcePoolConfig(name string, resourceMeteringEnabled bool) string {
+	return fmt.Sprintf(`
+resource "hyperv_resource_pool" "this" {
+	name                      = "%s"
+	pool_type                 = "Memory"
+	resource_metering_enabled = %t
+}
+	`, escapeForHcl(name), resourceMeteringEnabled)
+}
@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadSmbFileShareTimeout   = 1 * time.Minute
+	CreateSmbFileShareTimeout = 5 * time.Minute
+	UpdateSmbFileShareTimeout = 5 * time.Minute
+	DeleteSmbFileShareTimeout = 2 * time.Minute
+)
+
+func resourceHyperVSmbFileShare() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage an SMB share on the HyperV host machine or on a scale-out file server, to provide shared storage for virtual machines and iso libraries. The access to the share is managed as a whole, any access to the share that is not configured is removed. Destroying the resource removes the share but leaves the folder and its files as they are.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadSmbFileShareTimeout),
+			Create: schema.DefaultTimeout(CreateSmbFileShareTimeout),
+			Update: schema.DefaultTimeout(UpdateSmbFileShareTimeout),
+			Delete: schema.DefaultTimeout(DeleteSmbFileShareTimeout),
+		},
+		CreateContext: resourceHyperVSmbFileShareCreate,
+		ReadContext:   resourceHyperVSmbFileShareRead,
+		UpdateContext: resourceHyperVSmbFileShareUpdate,
+		DeleteContext: resourceHyperVSmbFileShareDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the SMB share.",
+			},
+			"path": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the path of the folder to share e.g. `C:\\ClusterStorage\\Volume1\\Shares\\VMs`. The folder is created if it does not exist.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies a description of the SMB share.",
+			},
+			"scope_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the scale-out file server or clustered file server role to create the share on. When empty the share is created on the HyperV host machine. Imported shares are read from any scope unless `scope_name` is set.",
+			},
+			"continuously_available": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether the share is continuously available, so virtual machines keep their open files when the file server fails over. Only supported on a scale-out file server or clustered file server role.",
+			},
+			"encrypt_data": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether SMB encryption is required to access the share.",
+			},
+			"folder_enumeration_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.SmbShareFolderEnumerationMode_value["unrestricted"],
+				ValidateDiagFunc: stringKeyInMap(api.SmbShareFolderEnumerationMode_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies whether users see files and folders they do not have access to. Valid values to use are `Unrestricted`, `AccessBased`.",
+			},
+			"full_access": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Specifies the accounts that are granted full access to the share e.g. `BUILTIN\\Administrators` or the computer accounts of the HyperV host machines `CONTOSO\\HV01$`. Use the account names as Windows reports them, including the domain.",
+			},
+			"change_access": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Specifies the accounts that are granted change access to the share.",
+			},
+			"read_access": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Specifies the accounts that are granted read access to the share.",
+			},
+			"no_access": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Specifies the accounts that are denied access to the share.",
+			},
+		},
+	}
+}
+
+func expandSmbShareAccounts(d *schema.ResourceData, key string) []string {
+	accounts := []string{}
+	if raw, ok := d.GetOk(key); ok {
+		for _, v := range raw.(*schema.Set).List() {
+			accounts = append(accounts, v.(string))
+		}
+	}
+
+	return accounts
+}
+
+func expandSmbShare(d *schema.ResourceData) api.SmbShare {
+	return api.SmbShare{
+		Name:                  (d.Get("name")).(string),
+		Path:                  (d.Get("path")).(string),
+		Description:           (d.Get("description")).(string),
+		ScopeName:             (d.Get("scope_name")).(string),
+		ContinuouslyAvailable: (d.Get("continuously_available")).(bool),
+		EncryptData:           (d.Get("encrypt_data")).(bool),
+		FolderEnumerationMode: (d.Get("folder_enumeration_mode")).(string),
+		FullAccess:            expandSmbShareAccounts(d, "full_access"),
+		ChangeAccess:          expandSmbShareAccounts(d, "change_access"),
+		ReadAccess:            expandSmbShareAccounts(d, "read_access"),
+		NoAccess:              expandSmbShareAccounts(d, "no_access"),
+	}
+}
+
+func resourceHyperVSmbFileShareCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv smb file share: %#v", d)
+	c := meta.(api.Client)
+
+	smbShare := expandSmbShare(d)
+
+	if d.IsNewResource() {
+		existing, err := c.GetSmbShare(ctx, smbShare.ScopeName, smbShare.Name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", smbShare.Name, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", smbShare.Name, "hyperv_smb_file_share", "hyperv_smb_file_share", smbShare.Name))
+		}
+	}
+
+	err := c.CreateSmbShare(ctx, smbShare)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(smbShare.Name)
+	log.Printf("[INFO][hyperv][create] created hyperv smb file share: %#v", d)
+
+	return resourceHyperVSmbFileShareRead(ctx, d, meta)
+}
+
+func resourceHyperVSmbFileShareRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv smb file share: %#v", d)
+	c := meta.(api.Client)
+
+	scopeName := (d.Get("scope_name")).(string)
+	name := d.Id()
+
+	smbShare, err := c.GetSmbShare(ctx, scopeName, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved smb file share: %+v", smbShare)
+
+	if smbShare.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv smb file share as it does not exist: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", smbShare.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("path", smbShare.Path); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("description", smbShare.Description); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("scope_name", smbShare.ScopeName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("continuously_available", smbShare.ContinuouslyAvailable); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("encrypt_data", smbShare.EncryptData); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("folder_enumeration_mode", smbShare.FolderEnumerationMode); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("full_access", api.PreferConfiguredCase(d, "full_access", smbShare.FullAccess)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("change_access", api.PreferConfiguredCase(d, "change_access", smbShare.ChangeAccess)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("read_access", api.PreferConfiguredCase(d, "read_access", smbShare.ReadAccess)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("no_access", api.PreferConfiguredCase(d, "no_access", smbShare.NoAccess)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv smb file share: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVSmbFileShareUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv smb file share: %#v", d)
+	c := meta.(api.Client)
+
+	smbShare := expandSmbShare(d)
+	smbShare.Name = d.Id()
+
+	err := c.UpdateSmbShare(ctx, smbShare)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv smb file share: %#v", d)
+
+	return resourceHyperVSmbFileShareRead(ctx, d, meta)
+}
+
+func resourceHyperVSmbFileShareDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv smb file share: %#v", d)
+	c := meta.(api.Client)
+
+	scopeName := (d.Get("scope_name")).(string)
+
+	err := c.DeleteSmbShare(ctx, scopeName, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv smb file share: %#v", d)
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+var testAccCheckHyperVSmbFileShareDestroy = testAccCheckDestroy("hyperv_smb_file_share", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	smbShare, err := c.GetSmbShare(ctx, "", id)
+	return smbShare.Name != "", err
+})
+
+func TestHyperVResourceSmbFileShare(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("share")
+	path := testAccPath("share")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVSmbFileShareDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceSmbFileShareConfig(name, path, "Created"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_smb_file_share.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_smb_file_share.this", "full_access.#", "1"),
+					resource.TestCheckResourceAttr("hyperv_smb_file_share.this", "read_access.#", "0"),
+				),
+			},
+			{
+				Config: testHyperVResourceSmbFileShareConfig(name, path, "Updated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_smb_file_share.this", "description", "Updated"),
+				),
+			},
+			{
+				ResourceName:      "hyperv_smb_file_share.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testHyperVResourceSmbFileShareConfig(name string, path string, description string) string {
+	return fmt.Sprintf(`
+resource "hyperv_smb_file_share" "this" {
+	name        = "%s"
+	path        = "%s"
+	description = "%s"
+	full_access = ["BUILTIN\\Administrators"]
+}
+	`, escapeForHcl(name), escapeForHcl(path), escapeForHcl(description))
+}
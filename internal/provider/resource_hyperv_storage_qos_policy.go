@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadStorageQosPolicyTimeout   = 1 * time.Minute
+	CreateStorageQosPolicyTimeout = 2 * time.Minute
+	UpdateStorageQosPolicyTimeout = 2 * time.Minute
+	DeleteStorageQosPolicyTimeout = 1 * time.Minute
+)
+
+func resourceHyperVStorageQosPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a storage QoS policy on a failover cluster using cluster shared volumes or a scale-out file server. Hard disk drives of a virtual machine can reference the policy with `qos_policy_id`. The ID is the policy id, so imported policies are read from the HyperV host machine unless `cluster_name` is set.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadStorageQosPolicyTimeout),
+			Create: schema.DefaultTimeout(CreateStorageQosPolicyTimeout),
+			Update: schema.DefaultTimeout(UpdateStorageQosPolicyTimeout),
+			Delete: schema.DefaultTimeout(DeleteStorageQosPolicyTimeout),
+		},
+		CreateContext: resourceHyperVStorageQosPolicyCreate,
+		ReadContext:   resourceHyperVStorageQosPolicyRead,
+		UpdateContext: resourceHyperVStorageQosPolicyUpdate,
+		DeleteContext: resourceHyperVStorageQosPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"cluster_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the failover cluster or scale-out file server to manage the policy on. When empty the policy is managed on the cluster the HyperV host machine belongs to.",
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the storage QoS policy.",
+			},
+			"policy_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          api.StorageQosPolicyType_name[api.StorageQosPolicyType_Dedicated],
+				ValidateDiagFunc: stringKeyInMap(api.StorageQosPolicyType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the type of the storage QoS policy. A `Dedicated` policy applies the limits to each hard disk drive that uses it, an `Aggregated` policy shares the limits between all the hard disk drives that use it. Valid values to use are `Dedicated`, `Aggregated`.",
+			},
+			"minimum_iops": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, 1000000000),
+				Description:      "Specifies the minimum normalized I/O operations per second (IOPS) guaranteed by the policy. Normalized IOPS are the total size of I/O per second divided by 8 KB. If value is 0 then there is no minimum.",
+			},
+			"maximum_iops": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, 1000000000),
+				Description:      "Specifies the maximum normalized I/O operations per second (IOPS) allowed by the policy. Normalized IOPS are the total size of I/O per second divided by 8 KB. If value is 0 then there is no maximum.",
+			},
+			"maximum_io_bandwidth_bytes": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, 1000000000000),
+				Description:      "Specifies the maximum I/O bandwidth, in bytes per second, allowed by the policy. If value is 0 then there is no maximum.",
+			},
+			"policy_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the storage QoS policy, use it as the `qos_policy_id` of a hard disk drive.",
+			},
+		},
+	}
+}
+
+func resourceHyperVStorageQosPolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv storage qos policy: %#v", d)
+	c := meta.(api.Client)
+
+	clusterName := (d.Get("cluster_name")).(string)
+	name := (d.Get("name")).(string)
+	policyType := api.ToStorageQosPolicyType((d.Get("policy_type")).(string))
+	minimumIops := uint64((d.Get("minimum_iops")).(int))
+	maximumIops := uint64((d.Get("maximum_iops")).(int))
+	maximumIoBandwidthBytes := uint64((d.Get("maximum_io_bandwidth_bytes")).(int))
+
+	if maximumIops > 0 && minimumIops > maximumIops {
+		return diag.Errorf("[ERROR][hyperv][create] minimum_iops (%d) must not be greater than maximum_iops (%d)", minimumIops, maximumIops)
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetStorageQosPolicyByName(ctx, clusterName, name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", name, err))
+		}
+
+		if existing.PolicyId != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", existing.PolicyId, "hyperv_storage_qos_policy", "hyperv_storage_qos_policy", existing.PolicyId))
+		}
+	}
+
+	storageQosPolicy, err := c.CreateStorageQosPolicy(ctx, clusterName, name, policyType, minimumIops, maximumIops, maximumIoBandwidthBytes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(storageQosPolicy.PolicyId)
+	log.Printf("[INFO][hyperv][create] created hyperv storage qos policy: %#v", d)
+
+	return resourceHyperVStorageQosPolicyRead(ctx, d, meta)
+}
+
+func resourceHyperVStorageQosPolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv storage qos policy: %#v", d)
+	c := meta.(api.Client)
+
+	clusterName := (d.Get("cluster_name")).(string)
+	policyId := d.Id()
+
+	storageQosPolicy, err := c.GetStorageQosPolicy(ctx, clusterName, policyId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved storage qos policy: %+v", storageQosPolicy)
+
+	if storageQosPolicy.PolicyId == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv storage qos policy as it does not exist: %#v", policyId)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", storageQosPolicy.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("policy_type", storageQosPolicy.PolicyType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("minimum_iops", storageQosPolicy.MinimumIops); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("maximum_iops", storageQosPolicy.MaximumIops); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("maximum_io_bandwidth_bytes", storageQosPolicy.MaximumIoBandwidthBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("policy_id", storageQosPolicy.PolicyId); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv storage qos policy: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVStorageQosPolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv storage qos policy: %#v", d)
+	c := meta.(api.Client)
+
+	clusterName := (d.Get("cluster_name")).(string)
+	policyId := d.Id()
+	name := (d.Get("name")).(string)
+	minimumIops := uint64((d.Get("minimum_iops")).(int))
+	maximumIops := uint64((d.Get("maximum_iops")).(int))
+	maximumIoBandwidthBytes := uint64((d.Get("maximum_io_bandwidth_bytes")).(int))
+
+	if maximumIops > 0 && minimumIops > maximumIops {
+		return diag.Errorf("[ERROR][hyperv][update] minimum_iops (%d) must not be greater than maximum_iops (%d)", minimumIops, maximumIops)
+	}
+
+	err := c.UpdateStorageQosPolicy(ctx, clusterName, policyId, name, minimumIops, maximumIops, maximumIoBandwidthBytes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv storage qos policy: %#v", d)
+
+	return resourceHyperVStorageQosPolicyRead(ctx, d, meta)
+}
+
+func resourceHyperVStorageQosPolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv storage qos policy: %#v", d)
+	c := meta.(api.Client)
+
+	clusterName := (d.Get("cluster_name")).(string)
+	policyId := d.Id()
+
+	err := c.DeleteStorageQosPolicy(ctx, clusterName, policyId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv storage qos policy: %#v", d)
+	return nil
+}
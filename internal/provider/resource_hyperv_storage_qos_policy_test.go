@@ -0,0 +1,58 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceStorageQosPolicy(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("policy")
+	clusterName := os.Getenv("HYPERV_TEST_CLUSTER_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_CLUSTER_NAME")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceStorageQosPolicyConfig(name, clusterName, 1000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_storage_qos_policy.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_storage_qos_policy.this", "maximum_iops", "1000"),
+					resource.TestCheckResourceAttrSet("hyperv_storage_qos_policy.this", "policy_id"),
+				),
+			},
+			{
+				Config: testHyperVResourceStorageQosPolicyConfig(name, clusterName, 2000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_storage_qos_policy.this", "maximum_iops", "2000"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceStorageQosPolicyConfig(name string, clusterName string, maximumIops int) string {
+	return fmt.Sprintf(`
+resource "hyperv_storage_qos_policy" "this" {
+	cluster_name = "%s"
+	name         = "%s"
+	policy_type  = "Dedicated"
+	minimum_iops = 100
+	maximum_iops = %d
+}
+	`, escapeForHcl(clusterName), escapeForHcl(name), maximumIops)
+}
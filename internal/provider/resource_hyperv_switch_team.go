@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadSwitchTeamTimeout   = 1 * time.Minute
+	CreateSwitchTeamTimeout = 5 * time.Minute
+	UpdateSwitchTeamTimeout = 5 * time.Minute
+	DeleteSwitchTeamTimeout = 5 * time.Minute
+)
+
+func resourceHyperVSwitchTeam() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a load balancing and failover (LBFO) team of network adapters on the HyperV host machine. Bind an external `hyperv_network_switch` to the team with `net_adapter_name` on hosts that do not support switch embedded teaming (SET), e.g. Windows Server 2012 R2. Windows Server 2022 no longer allows switches to be bound to LBFO teams, use `enable_embedded_teaming` on the switch instead.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadSwitchTeamTimeout),
+			Create: schema.DefaultTimeout(CreateSwitchTeamTimeout),
+			Update: schema.DefaultTimeout(UpdateSwitchTeamTimeout),
+			Delete: schema.DefaultTimeout(DeleteSwitchTeamTimeout),
+		},
+		CreateContext: resourceHyperVSwitchTeamCreate,
+		ReadContext:   resourceHyperVSwitchTeamRead,
+		UpdateContext: resourceHyperVSwitchTeamUpdate,
+		DeleteContext: resourceHyperVSwitchTeamDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the team. The team network adapter the switch binds to gets the same name.",
+			},
+			"team_members": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				MaxItems:    32,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Specifies the names of the physical network adapters in the team. Members can be added and removed without recreating the team.",
+			},
+			"teaming_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VMSwitchTeamingMode_name[api.VMSwitchTeamingMode_SwitchIndependent],
+				ValidateDiagFunc: stringKeyInMap(api.VMSwitchTeamingMode_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the teaming mode of the team. `Static` and `Lacp` require the physical switch ports of the members to be configured as a team. Valid values to use are `SwitchIndependent`, `Static`, `Lacp`.",
+			},
+			"load_balancing_algorithm": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.NetLbfoTeamLoadBalancingAlgorithm_value["dynamic"],
+				ValidateDiagFunc: stringKeyInMap(api.NetLbfoTeamLoadBalancingAlgorithm_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the algorithm the team uses to distribute traffic across its members. Valid values to use are `Dynamic`, `HyperVPort`, `TransportPorts`, `IPAddresses`, `MacAddresses`.",
+			},
+			"net_adapter_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the team network adapter, use it in `net_adapter_names` of an external `hyperv_network_switch`.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the team, e.g. `Up` or `Degraded`.",
+			},
+		},
+	}
+}
+
+func expandNetLbfoTeam(d *schema.ResourceData) api.NetLbfoTeam {
+	teamMembers := []string{}
+	if raw, ok := d.GetOk("team_members"); ok {
+		for _, v := range raw.(*schema.Set).List() {
+			teamMembers = append(teamMembers, v.(string))
+		}
+	}
+
+	return api.NetLbfoTeam{
+		Name:                   (d.Get("name")).(string),
+		TeamMembers:            teamMembers,
+		TeamingMode:            api.ToVMSwitchTeamingMode((d.Get("teaming_mode")).(string)),
+		LoadBalancingAlgorithm: (d.Get("load_balancing_algorithm")).(string),
+	}
+}
+
+func resourceHyperVSwitchTeamCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv switch team: %#v", d)
+	c := meta.(api.Client)
+
+	netLbfoTeam := expandNetLbfoTeam(d)
+
+	if d.IsNewResource() {
+		existing, err := c.GetNetLbfoTeam(ctx, netLbfoTeam.Name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", netLbfoTeam.Name, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", netLbfoTeam.Name, "hyperv_switch_team", "hyperv_switch_team", netLbfoTeam.Name))
+		}
+	}
+
+	err := c.CreateNetLbfoTeam(ctx, netLbfoTeam)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(netLbfoTeam.Name)
+	log.Printf("[INFO][hyperv][create] created hyperv switch team: %#v", d)
+
+	return resourceHyperVSwitchTeamRead(ctx, d, meta)
+}
+
+func resourceHyperVSwitchTeamRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv switch team: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	netLbfoTeam, err := c.GetNetLbfoTeam(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved switch team: %+v", netLbfoTeam)
+
+	if netLbfoTeam.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv switch team as it does not exist: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", netLbfoTeam.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("team_members", api.PreferConfiguredCase(d, "team_members", netLbfoTeam.TeamMembers)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("teaming_mode", netLbfoTeam.TeamingMode.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("load_balancing_algorithm", netLbfoTeam.LoadBalancingAlgorithm); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("net_adapter_name", netLbfoTeam.NetAdapterName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("status", netLbfoTeam.Status); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv switch team: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVSwitchTeamUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv switch team: %#v", d)
+	c := meta.(api.Client)
+
+	netLbfoTeam := expandNetLbfoTeam(d)
+	netLbfoTeam.Name = d.Id()
+
+	err := c.UpdateNetLbfoTeam(ctx, netLbfoTeam)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv switch team: %#v", d)
+
+	return resourceHyperVSwitchTeamRead(ctx, d, meta)
+}
+
+func resourceHyperVSwitchTeamDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv switch team: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteNetLbfoTeam(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv switch team: %#v", d)
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+var testAccCheckHyperVSwitchTeamDestroy = testAccCheckDestroy("hyperv_switch_team", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	netLbfoTeam, err := c.GetNetLbfoTeam(ctx, id)
+	return netLbfoTeam.Name != "", err
+})
+
+func TestHyperVResourceSwitchTeam(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("team")
+	netAdapterName := os.Getenv("HYPERV_TEST_NET_ADAPTER_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_NET_ADAPTER_NAME")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVSwitchTeamDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceSwitchTeamConfig(name, netAdapterName, "Dynamic"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_switch_team.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_switch_team.this", "team_members.#", "1"),
+					resource.TestCheckResourceAttrSet("hyperv_switch_team.this", "net_adapter_name"),
+				),
+			},
+			{
+				Config: testHyperVResourceSwitchTeamConfig(name, netAdapterName, "HyperVPort"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_switch_team.this", "load_balancing_algorithm", "HyperVPort"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceSwitchTeamConfig(name string, netAdapterName string, loadBalancingAlgorithm string) string {
+	return fmt.Sprintf(`
+resource "hyperv_switch_team" "this" {
+	name                     = "%s"
+	team_members             = ["%s"]
+	load_balancing_algorithm = "%s"
+}
+	`, escapeForHcl(name), escapeForHcl(netAdapterName), loadBalancingAlgorithm)
+}
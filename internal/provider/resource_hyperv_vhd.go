@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -22,8 +23,9 @@ const (
 )
 
 func resourceHyperVVhd() *schema.Resource {
-	return &schema.Resource{
-		Description: "This Hyper-V resource allows you to manage VHDs.",
+	resource := &schema.Resource{
+		Description:   "This Hyper-V resource allows you to manage VHDs.",
+		SchemaVersion: 1,
 		Timeouts: &schema.ResourceTimeout{
 			Read:   schema.DefaultTimeout(ReadVhdTimeout),
 			Create: schema.DefaultTimeout(CreateVhdTimeout),
@@ -56,7 +58,8 @@ func resourceHyperVVhd() *schema.Resource {
 
 					return false
 				},
-				Description: "Path to the new virtual hard disk file(s) that is being created or being copied to. If a filename or relative path is specified, the new virtual hard disk path is calculated relative to the current working directory. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "Path to the new virtual hard disk file(s) that is being created or being copied to. The path must be absolute, either on a drive e.g. `C:\\VMs\\web.vhdx` or on a share e.g. `\\\\server\\share\\web.vhdx`. Depending on the source selected, the path will be used to determine where to copy source vhd/vhdx/vhds file to.",
 			},
 			"source": {
 				Type:     schema.TypeString,
@@ -65,8 +68,21 @@ func resourceHyperVVhd() *schema.Resource {
 					"source_vm",
 					"parent_path",
 					"source_disk",
+					"windows_image",
 				},
-				Description: "This field is mutually exclusive with the fields `source_vm`, `parent_path`, `source_disk`. This value can be a url or a path (including wildcards). Box, Zip and 7z files will automatically be expanded. The destination folder will be the directory portion of the path. If expanded files have a folder called `Virtual Machines`, then the `Virtual Machines` folder will be used instead of the entire archive contents. ",
+				Description: "This field is mutually exclusive with the fields `source_vm`, `parent_path`, `source_disk`, `windows_image`. This value can be a url or a path (including wildcards). Box, Zip and 7z files will automatically be expanded. The destination folder will be the directory portion of the path. If expanded files have a folder called `Virtual Machines`, then the `Virtual Machines` folder will be used instead of the entire archive contents. ",
+			},
+			"track_source_version": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				RequiredWith: []string{"source"},
+				Description:  "Specifies whether the image at a `source` url is tracked, so that golden images can be rotated by publishing a new image at the same url. Every plan asks the web server from the HyperV host machine for the ETag of the url, or its Last-Modified date when it has no ETag, and plans an update when it differs from `image_version`. The update downloads the image next to the virtual hard disk and only replaces it once the download completed. The virtual hard disk must not be in use by a running virtual machine when it is replaced. A plan is not stopped when the web server can not be reached.",
+			},
+			"image_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ETag, or Last-Modified date, of the image at the `source` url that the virtual hard disk was downloaded from when `track_source_version` is enabled.",
 			},
 			"source_vm": {
 				Type:     schema.TypeString,
@@ -75,8 +91,29 @@ func resourceHyperVVhd() *schema.Resource {
 					"source",
 					"parent_path",
 					"source_disk",
+					"windows_image",
 				},
-				Description: "This field is mutually exclusive with the fields `source`, `parent_path`, `source_disk`. This value is the name of the vm to copy the vhds from.",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "This field is mutually exclusive with the fields `source`, `parent_path`, `source_disk`, `windows_image`. This value is the name of the vm to copy the vhds from. All the vhds of the vm are copied unless `source_vm_disk_index` or `source_vm_controller_location` selects one of them.",
+			},
+			// a vm has at most 4 scsi controllers with 64 locations and 2 ide controllers with 2 locations
+			"source_vm_disk_index": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          -1,
+				RequiredWith:     []string{"source_vm"},
+				ConflictsWith:    []string{"source_vm_controller_location"},
+				ValidateDiagFunc: IntBetween(-1, 259),
+				Description:      "This field is mutually exclusive with the field `source_vm_controller_location`. Specifies the index of the hard disk drive of `source_vm` to copy, counting from `0` in the order the hard disk drives are attached to the vm i.e. by controller type, controller number and controller location. Only the vhd of the selected hard disk drive is copied to `path`, with its checkpoints merged, e.g. to clone the OS disk of a template vm. `-1` copies all the vhds of the vm.",
+			},
+			"source_vm_controller_location": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          -1,
+				RequiredWith:     []string{"source_vm"},
+				ConflictsWith:    []string{"source_vm_disk_index"},
+				ValidateDiagFunc: IntBetween(-1, 63),
+				Description:      "This field is mutually exclusive with the field `source_vm_disk_index`. Specifies the controller location of the hard disk drive of `source_vm` to copy. Only the vhd of the selected hard disk drive is copied to `path`, with its checkpoints merged. Copying fails when the vm has hard disk drives at the location on more than one controller, use `source_vm_disk_index` instead. `-1` copies all the vhds of the vm.",
 			},
 			"source_disk": {
 				Type:     schema.TypeInt,
@@ -85,8 +122,72 @@ func resourceHyperVVhd() *schema.Resource {
 					"source",
 					"source_vm",
 					"parent_path",
+					"windows_image",
+				},
+				Description: "This field is mutually exclusive with the fields `source`, `source_vm`, `parent_path`, `windows_image`. Specifies the physical disk to be used as the source for the virtual hard disk to be created.",
+			},
+			"windows_image": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				ConflictsWith: []string{
+					"source",
+					"source_vm",
+					"source_disk",
+					"parent_path",
+				},
+				Description: "This field is mutually exclusive with the fields `source`, `source_vm`, `source_disk`, `parent_path`. Builds the virtual hard disk from the install image of a Windows iso, so that golden Windows images can be built without Packer. The disk is partitioned for the generation of vm that will boot from it, the image is applied with DISM, drivers are injected and the unattend answer file is copied to `Windows\\Panther`. An applied image is generalized, so Windows is specialized with the answer file the first time a vm boots from the disk. Unless the image has its own `Windows\\Setup\\Scripts\\SetupComplete.cmd`, one is added that installs the `avma_key` of the `hyperv_machine_instance` when Windows setup completes. `size` must be specified and building an image usually takes longer than the default create timeout.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"iso_path": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: IsWindowsPath(),
+							DiffSuppressFunc: api.DiffSuppressWindowsPath,
+							Description:      "Specifies the path to the Windows iso on the HyperV host machine, either on a drive e.g. `C:\\Iso\\windows_server_2022.iso` or on a share e.g. `\\\\server\\share\\windows_server_2022.iso`. The image is read from `sources\\install.wim` or `sources\\install.esd` on the iso.",
+						},
+						"image_index": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							ConflictsWith: []string{
+								"windows_image.0.image_name",
+							},
+							Description: "This field is mutually exclusive with the field `image_name`. Specifies the index of the image to apply from the install image. The first image is applied when neither `image_index` or `image_name` is specified.",
+						},
+						"image_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ConflictsWith: []string{
+								"windows_image.0.image_index",
+							},
+							Description: "This field is mutually exclusive with the field `image_index`. Specifies the name of the image to apply from the install image e.g. `Windows Server 2022 Standard (Desktop Experience)`.",
+						},
+						"unattend": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Sensitive:   true,
+							StateFunc:   api.HashSensitiveValue,
+							Description: "Specifies the content of the unattend answer file, it must be well-formed xml. It usually contains passwords, so it is not shown in plans and only a hash of it is stored in the state.",
+						},
+						"driver_paths": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Specifies the paths on the HyperV host machine of drivers to inject into the image. A path can be a `.inf` file or a folder that is searched recursively for drivers.",
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								ValidateDiagFunc: IsWindowsPath(),
+							},
+						},
+						"generation": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Default:          2,
+							ValidateDiagFunc: IntInSlice([]int{1, 2}),
+							Description:      "Specifies the generation of the vms that will boot from the disk. Generation 2 vms boot from a GPT partitioned disk with UEFI, generation 1 vms from a MBR partitioned disk with BIOS. Valid values to use are `1`, `2`.",
+						},
+					},
 				},
-				Description: "This field is mutually exclusive with the fields `source`, `source_vm`, `parent_path`. Specifies the physical disk to be used as the source for the virtual hard disk to be created.",
 			},
 			"vhd_type": {
 				Type:             schema.TypeString,
@@ -97,7 +198,8 @@ func resourceHyperVVhd() *schema.Resource {
 					"source",
 					"source_vm",
 				},
-				Description: "This field is mutually exclusive with the fields `source`, `source_vm`, `parent_path`. Valid values to use are `Unknown`, `Fixed`, `Dynamic`, `Differencing`.",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "This field is mutually exclusive with the fields `source`, `source_vm`, `parent_path`. Valid values to use are `Unknown`, `Fixed`, `Dynamic`, `Differencing`.",
 			},
 			"parent_path": {
 				Type:     schema.TypeString,
@@ -107,11 +209,27 @@ func resourceHyperVVhd() *schema.Resource {
 					"source_vm",
 					"source_disk",
 					"size",
+					"windows_image",
 				},
 				DiffSuppressFunc: func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 					return strings.EqualFold(oldValue, newValue)
 				},
-				Description: "This field is mutually exclusive with the fields `source`, `source_vm`, `source_disk`, `size`. Specifies the path to the parent of the differencing disk to be created (this parameter may be specified only for the creation of a differencing disk).",
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "This field is mutually exclusive with the fields `source`, `source_vm`, `source_disk`, `size`, `windows_image`. Specifies the path to the parent of the differencing disk to be created (this parameter may be specified only for the creation of a differencing disk).",
+			},
+			"parent_image_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"parent_path"},
+				Description:  "Specifies the version of the image of the parent, e.g. `image_version` of the `hyperv_vhd` of the parent, so that the differencing disk is recreated from the new image when the parent is downloaded again. A differencing disk whose parent changed underneath it is broken, so leave it unset only when differencing disks are recreated in another way.",
+			},
+			"repair_parent_path": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				RequiredWith: []string{"parent_path"},
+				Description:  "Specifies whether an existing differencing disk is re-linked to its parent when `parent_path` changes, e.g. when a golden image is moved to another folder, instead of the differencing disk being recreated and losing its contents. The new parent must be the same virtual hard disk the differencing disk was created from, only moved or copied, and the differencing disk must not be in use by a running virtual machine.",
 			},
 			"size": {
 				Type:     schema.TypeInt,
@@ -190,13 +308,76 @@ func resourceHyperVVhd() *schema.Resource {
 				Computed:    true,
 				Description: "Does virtual disk exist.",
 			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether the virtual hard disk is protected from being deleted. The protection is recorded on the HyperV host machine under `%ProgramData%\\terraform-provider-hyperv\\vhd-deletion-protections`, so the provider refuses to delete it even when it is destroyed by another configuration, or by `delete_vhds_on_destroy` of a machine instance. Set it to `false` and apply before destroying the virtual hard disk.",
+			},
+			"validate_health": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether creating or refreshing the virtual hard disk fails when it is not `healthy`, so that a broken differencing chain or a corrupt file stops a plan instead of a virtual machine failing to boot from it.",
+			},
+			"healthy": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the virtual hard disk passes validation with `Test-VHD`, which for a differencing disk includes that all of its parents exist and match it. Use `hyperv_vhd_parent_chain` to find which disk of a chain is broken.",
+			},
+			"health_error_message": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Why the virtual hard disk failed validation when it is not `healthy`.",
+			},
 		},
 
 		CustomizeDiff: customizeDiffForVhd,
 	}
+
+	// the attributes added since the state was written by a provider that did not version the schema
+	resource.StateUpgraders = []schema.StateUpgrader{
+		stateUpgraderV0("hyperv_vhd", resource,
+			"deletion_protection",
+			"health_error_message",
+			"healthy",
+			"image_version",
+			"parent_image_version",
+			"repair_parent_path",
+			"source_vm_controller_location",
+			"source_vm_disk_index",
+			"track_source_version",
+			"validate_health",
+			"windows_image",
+		),
+	}
+
+	return resource
 }
 
 func customizeDiffForVhd(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if diff.Id() != "" && diff.HasChange("parent_path") && !diff.Get("repair_parent_path").(bool) {
+		// a differencing disk created from another parent has different contents
+		if err := diff.ForceNew("parent_path"); err != nil {
+			return err
+		}
+	}
+
+	source := diff.Get("source").(string)
+	if diff.Id() != "" && diff.Get("track_source_version").(bool) && !diff.HasChange("source") && api.IsVhdSourceUrl(source) {
+		c := i.(api.Client)
+
+		sourceVersion, err := c.GetVhdSourceVersion(ctx, source)
+		if err != nil {
+			// the image is checked again on the next plan
+			log.Printf("[WARN][hyperv][plan] unable to get version of source %s of vhd %s: %s", source, diff.Id(), err)
+		} else if imageVersion := sourceVersion.ImageVersion(); imageVersion != "" && imageVersion != diff.Get("image_version").(string) {
+			if err := diff.SetNewComputed("image_version"); err != nil {
+				return err
+			}
+		}
+	}
+
 	path := diff.Get("path").(string)
 
 	if _, err := os.Stat(path); err != nil {
@@ -238,6 +419,8 @@ func resourceHyperVVhdCreate(ctx context.Context, d *schema.ResourceData, meta i
 
 	source := (d.Get("source")).(string)
 	sourceVm := (d.Get("source_vm")).(string)
+	sourceVmDiskIndex := (d.Get("source_vm_disk_index")).(int)
+	sourceVmControllerLocation := (d.Get("source_vm_controller_location")).(int)
 	sourceDisk := (d.Get("source_disk")).(int)
 	vhdType := api.ToVhdType((d.Get("vhd_type")).(string))
 	parentPath := (d.Get("parent_path")).(string)
@@ -246,7 +429,19 @@ func resourceHyperVVhdCreate(ctx context.Context, d *schema.ResourceData, meta i
 	logicalSectorSize := uint32((d.Get("logical_sector_size")).(int))
 	physicalSectorSize := uint32((d.Get("physical_sector_size")).(int))
 
-	err := c.CreateOrUpdateVhd(ctx, path, source, sourceVm, sourceDisk, vhdType, parentPath, size, blockSize, logicalSectorSize, physicalSectorSize)
+	windowsImage, err := api.ExpandWindowsImage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the version is taken before the image is downloaded, so that an image published while it downloads is downloaded
+	// again on the next apply
+	imageVersion, err := getVhdImageVersion(ctx, c, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = resourceHyperVVhdCreateOrUpdate(ctx, c, path, source, sourceVm, sourceVmDiskIndex, sourceVmControllerLocation, sourceDisk, windowsImage, vhdType, parentPath, size, blockSize, logicalSectorSize, physicalSectorSize)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -261,12 +456,58 @@ func resourceHyperVVhdCreate(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
+	if (d.Get("deletion_protection")).(bool) {
+		err = c.SetVhdDeletionProtection(ctx, path, true)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set("image_version", imageVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(path)
 	log.Printf("[INFO][hyperv][create] created hyperv vhd: %#v", d)
 
 	return resourceHyperVVhdRead(ctx, d, meta)
 }
 
+// getVhdImageVersion returns the version of the image at the source url of the vhd, or an empty version when the
+// source is not tracked
+func getVhdImageVersion(ctx context.Context, c api.Client, d *schema.ResourceData) (string, error) {
+	source := (d.Get("source")).(string)
+	if !(d.Get("track_source_version")).(bool) || !api.IsVhdSourceUrl(source) {
+		return "", nil
+	}
+
+	sourceVersion, err := c.GetVhdSourceVersion(ctx, source)
+	if err != nil {
+		return "", err
+	}
+
+	return sourceVersion.ImageVersion(), nil
+}
+
+// resourceHyperVVhdCreateOrUpdate builds the vhd from a Windows iso when a windows image is specified, otherwise the vhd
+// is copied from its source or created empty
+func resourceHyperVVhdCreateOrUpdate(ctx context.Context, c api.Client, path string, source string, sourceVm string, sourceVmDiskIndex int, sourceVmControllerLocation int, sourceDisk int, windowsImage *api.WindowsImage, vhdType api.VhdType, parentPath string, size uint64, blockSize uint32, logicalSectorSize uint32, physicalSectorSize uint32) error {
+	if windowsImage == nil {
+		return c.CreateOrUpdateVhd(ctx, path, source, sourceVm, sourceVmDiskIndex, sourceVmControllerLocation, sourceDisk, vhdType, parentPath, size, blockSize, logicalSectorSize, physicalSectorSize)
+	}
+
+	if size == 0 {
+		return fmt.Errorf("[ERROR][hyperv] size must be specified to build vhd %s from windows image %s", path, windowsImage.IsoPath)
+	}
+
+	if vhdType != api.VhdType_Dynamic && vhdType != api.VhdType_Fixed {
+		return fmt.Errorf("[ERROR][hyperv] vhd_type must be Dynamic or Fixed to build vhd %s from windows image %s - was %s", path, windowsImage.IsoPath, vhdType)
+	}
+
+	return c.CreateVhdFromWindowsImage(ctx, path, *windowsImage, vhdType, size, blockSize, logicalSectorSize, physicalSectorSize)
+}
+
 func resourceHyperVVhdRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO][hyperv][read] reading hyperv vhd: %#v", d)
 	c := meta.(api.Client)
@@ -294,12 +535,24 @@ func resourceHyperVVhdRead(ctx context.Context, d *schema.ResourceData, meta int
 		if err := d.Set("exists", true); err != nil {
 			return diag.FromErr(err)
 		}
+
+		if err := d.Set("deletion_protection", vhd.DeletionProtection); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	if err := d.Set("vhd_type", api.VhdType_name[vhd.VhdType]); err != nil {
 		return diag.FromErr(err)
 	}
 
+	if err := d.Set("healthy", vhd.Healthy); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("health_error_message", vhd.HealthErrorMessage); err != nil {
+		return diag.FromErr(err)
+	}
+
 	if vhd.VhdType == api.VhdType_Differencing {
 		if err := d.Set("parent_path", vhd.ParentPath); err != nil {
 			return diag.FromErr(err)
@@ -322,6 +575,12 @@ func resourceHyperVVhdRead(ctx context.Context, d *schema.ResourceData, meta int
 		}
 	}
 
+	if (d.Get("validate_health")).(bool) {
+		if err := vhd.CheckHealth(); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Printf("[INFO][hyperv][read] read hyperv vhd: %#v", d)
 
 	return nil
@@ -335,6 +594,8 @@ func resourceHyperVVhdUpdate(ctx context.Context, d *schema.ResourceData, meta i
 
 	source := (d.Get("source")).(string)
 	sourceVm := (d.Get("source_vm")).(string)
+	sourceVmDiskIndex := (d.Get("source_vm_disk_index")).(int)
+	sourceVmControllerLocation := (d.Get("source_vm_controller_location")).(int)
 	sourceDisk := (d.Get("source_disk")).(int)
 	vhdType := api.ToVhdType((d.Get("vhd_type")).(string))
 	parentPath := (d.Get("parent_path")).(string)
@@ -343,11 +604,47 @@ func resourceHyperVVhdUpdate(ctx context.Context, d *schema.ResourceData, meta i
 	logicalSectorSize := uint32((d.Get("logical_sector_size")).(int))
 	physicalSectorSize := uint32((d.Get("physical_sector_size")).(int))
 
+	windowsImage, err := api.ExpandWindowsImage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	exists := (d.Get("exists")).(bool)
 
-	if !exists || d.HasChange("path") || d.HasChange("source") || d.HasChange("source_vm") || d.HasChange("source_disk") || d.HasChange("parent_path") {
+	imageVersion, err := getVhdImageVersion(ctx, c, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !exists || d.HasChange("path") || d.HasChange("source") || d.HasChange("source_vm") || d.HasChange("source_vm_disk_index") || d.HasChange("source_vm_controller_location") || d.HasChange("source_disk") || d.HasChange("windows_image") || d.HasChange("parent_path") {
 		// delete it as its changed
-		err := c.CreateOrUpdateVhd(ctx, path, source, sourceVm, sourceDisk, vhdType, parentPath, size, blockSize, logicalSectorSize, physicalSectorSize)
+		err := resourceHyperVVhdCreateOrUpdate(ctx, c, path, source, sourceVm, sourceVmDiskIndex, sourceVmControllerLocation, sourceDisk, windowsImage, vhdType, parentPath, size, blockSize, logicalSectorSize, physicalSectorSize)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// an existing vhd is left as is by create or update, so a tracked image is downloaded again when it was rotated. A vhd
+	// whose image was not tracked before is assumed to have the current image.
+	if exists && imageVersion != "" {
+		oldImageVersion, _ := d.GetChange("image_version")
+		if d.HasChange("source") || (oldImageVersion.(string) != "" && oldImageVersion.(string) != imageVersion) {
+			err := c.UpdateVhdFromSource(ctx, path, source)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if err := d.Set("image_version", imageVersion); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// a differencing disk that already exists is left as is by create or update, so it is re-linked to its new parent
+	if d.HasChange("parent_path") && (d.Get("repair_parent_path")).(bool) {
+		err := c.SetVhdParentPath(ctx, path, parentPath)
 
 		if err != nil {
 			return diag.FromErr(err)
@@ -365,6 +662,14 @@ func resourceHyperVVhdUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
+	if d.HasChange("deletion_protection") {
+		err := c.SetVhdDeletionProtection(ctx, path, (d.Get("deletion_protection")).(bool))
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Printf("[INFO][hyperv][update] updated hyperv vhd: %#v", d)
 
 	return resourceHyperVVhdRead(ctx, d, meta)
@@ -377,9 +682,14 @@ func resourceHyperVVhdDelete(ctx context.Context, d *schema.ResourceData, meta i
 
 	path := d.Id()
 
+	if (d.Get("deletion_protection")).(bool) {
+		return diag.Errorf("[ERROR][hyperv][delete] vhd %s is protected from deletion, set deletion_protection to false and apply before destroying it", path)
+	}
+
 	err := c.DeleteVhd(ctx, path)
 
-	if err != nil {
+	// the directory of the file is already gone, so there is nothing left to delete
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
 		return diag.FromErr(err)
 	}
 
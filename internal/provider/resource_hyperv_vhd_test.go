@@ -0,0 +1,287 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func TestHyperVResourceVhd(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	path := testAccPath("vhd.vhdx")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVVhdDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVhdConfig(path, 4194304),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "path", path),
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "size", "4194304"),
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "exists", "true"),
+				),
+			},
+			{
+				Config: testHyperVResourceVhdConfig(path, 8388608),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "size", "8388608"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceVhdDeletionProtection(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	path := testAccPath("protected.vhdx")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVVhdDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVhdDeletionProtectionConfig(path, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testHyperVResourceVhdDeletionProtectionConfig(path, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("protected from deletion"),
+			},
+			{
+				Config: testHyperVResourceVhdDeletionProtectionConfig(path, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceVhdWindowsImage(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	testAccPreCheckEnv(t, "HYPERV_TEST_WINDOWS_ISO")
+
+	path := testAccPath("windows.vhdx")
+	isoPath := os.Getenv("HYPERV_TEST_WINDOWS_ISO")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVVhdDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVhdWindowsImageConfig(path, isoPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "path", path),
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "size", "42949672960"),
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "exists", "true"),
+					resource.TestCheckResourceAttr("hyperv_vhd.this", "windows_image.0.generation", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceVhdSourceVmDisk(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("template")
+	osPath := testAccPath("template_os.vhdx")
+	dataPath := testAccPath("template_data.vhdx")
+	path := testAccPath("clone_os.vhdx")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVVhdDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVhdSourceVmDiskConfig(name, osPath, dataPath, path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vhd.clone", "path", path),
+					resource.TestCheckResourceAttr("hyperv_vhd.clone", "source_vm_disk_index", "0"),
+					resource.TestCheckResourceAttr("hyperv_vhd.clone", "size", "4194304"),
+					resource.TestCheckResourceAttr("hyperv_vhd.clone", "exists", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceVhdTrackSourceVersion(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	testAccPreCheckEnv(t, "HYPERV_TEST_VHD_URL")
+
+	path := testAccPath("golden.vhdx")
+	differencingPath := testAccPath("golden_differencing.vhdx")
+	source := os.Getenv("HYPERV_TEST_VHD_URL")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVVhdDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVhdTrackSourceVersionConfig(path, differencingPath, source),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vhd.golden", "exists", "true"),
+					resource.TestCheckResourceAttrSet("hyperv_vhd.golden", "image_version"),
+					resource.TestCheckResourceAttrPair("hyperv_vhd.differencing", "parent_image_version", "hyperv_vhd.golden", "image_version"),
+				),
+			},
+			{
+				// the image at the url did not change, so it is not downloaded again
+				Config:   testHyperVResourceVhdTrackSourceVersionConfig(path, differencingPath, source),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+var testAccCheckHyperVVhdDestroy = testAccCheckDestroy("hyperv_vhd", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	vhdExists, err := c.VhdExists(ctx, id)
+	return vhdExists.Exists, err
+})
+
+func testHyperVResourceVhdConfig(path string, size int) string {
+	return fmt.Sprintf(`
+resource "hyperv_vhd" "this" {
+	path = "%s"
+	size = %d
+}
+	`, escapeForHcl(path), size)
+}
+
+func testHyperVResourceVhdDeletionProtectionConfig(path string, deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "hyperv_vhd" "this" {
+	path                = "%s"
+	size                = 4194304
+	deletion_protection = %t
+}
+	`, escapeForHcl(path), deletionProtection)
+}
+
+func testHyperVResourceVhdTrackSourceVersionConfig(path string, differencingPath string, source string) string {
+	return fmt.Sprintf(`
+resource "hyperv_vhd" "golden" {
+	path                 = "%s"
+	source               = "%s"
+	track_source_version = true
+}
+
+resource "hyperv_vhd" "differencing" {
+	path                 = "%s"
+	vhd_type             = "Differencing"
+	parent_path          = hyperv_vhd.golden.path
+	parent_image_version = hyperv_vhd.golden.image_version
+}
+	`, escapeForHcl(path), escapeForHcl(source), escapeForHcl(differencingPath))
+}
+
+func testHyperVResourceVhdSourceVmDiskConfig(name string, osPath string, dataPath string, path string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "template" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_vhd" "os" {
+	path = "%s"
+	size = 4194304
+}
+
+resource "hyperv_vhd" "data" {
+	path = "%s"
+	size = 8388608
+}
+
+resource "hyperv_vm_hard_disk_drive" "os" {
+	vm_name             = hyperv_machine_instance.template.name
+	controller_type     = "Scsi"
+	controller_number   = 0
+	controller_location = 0
+	path                = hyperv_vhd.os.path
+}
+
+resource "hyperv_vm_hard_disk_drive" "data" {
+	vm_name             = hyperv_machine_instance.template.name
+	controller_type     = "Scsi"
+	controller_number   = 0
+	controller_location = 1
+	path                = hyperv_vhd.data.path
+}
+
+resource "hyperv_vhd" "clone" {
+	path                 = "%s"
+	source_vm            = hyperv_machine_instance.template.name
+	source_vm_disk_index = 0
+	size                 = 4194304
+
+	depends_on = [hyperv_vm_hard_disk_drive.os, hyperv_vm_hard_disk_drive.data]
+}
+	`, escapeForHcl(name), escapeForHcl(osPath), escapeForHcl(dataPath), escapeForHcl(path))
+}
+
+func testHyperVResourceVhdWindowsImageConfig(path string, isoPath string) string {
+	return fmt.Sprintf(`
+resource "hyperv_vhd" "this" {
+	path = "%s"
+	size = 42949672960
+
+	windows_image {
+		iso_path = "%s"
+		unattend = <<-EOT
+			<?xml version="1.0" encoding="utf-8"?>
+			<unattend xmlns="urn:schemas-microsoft-com:unattend">
+				<settings pass="oobeSystem">
+					<component name="Microsoft-Windows-Shell-Setup" processorArchitecture="amd64" publicKeyToken="31bf3856ad364e35" language="neutral" versionScope="nonSxS">
+						<OOBE>
+							<HideEULAPage>true</HideEULAPage>
+						</OOBE>
+					</component>
+				</settings>
+			</unattend>
+		EOT
+	}
+
+	timeouts {
+		create = "60m"
+	}
+}
+	`, escapeForHcl(path), escapeForHcl(isoPath))
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVirtualMachinePathTimeout   = 1 * time.Minute
+	CreateVirtualMachinePathTimeout = 2 * time.Minute
+	DeleteVirtualMachinePathTimeout = 10 * time.Minute
+)
+
+func resourceHyperVVirtualMachinePath() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage the folder of a virtual machine on the HyperV host machine, with the `Virtual Machines`, `Virtual Hard Disks` and `Snapshots` folders HyperV uses, so the virtual machine, its disks, checkpoints and seed isos live together. Use `root_path` as the `path` of the `hyperv_machine_instance` with the same name, `snapshots_path` as its `snapshot_file_location` and create its disks in `virtual_hard_disks_path`. Destroying the resource removes the folder and everything in it.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVirtualMachinePathTimeout),
+			Create: schema.DefaultTimeout(CreateVirtualMachinePathTimeout),
+			Delete: schema.DefaultTimeout(DeleteVirtualMachinePathTimeout),
+		},
+		CreateContext: resourceHyperVVirtualMachinePathCreate,
+		ReadContext:   resourceHyperVVirtualMachinePathRead,
+		DeleteContext: resourceHyperVVirtualMachinePathDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"root_path": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the folder that contains the folders of virtual machines e.g. `D:\\Hyper-V`.",
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine, which is the name of its folder in `root_path`.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The folder of the virtual machine.",
+			},
+			"virtual_machines_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The folder of the configuration files of the virtual machine.",
+			},
+			"virtual_hard_disks_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The folder of the virtual hard disks and seed isos of the virtual machine.",
+			},
+			"snapshots_path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The folder of the checkpoints of the virtual machine.",
+			},
+		},
+	}
+}
+
+func resourceHyperVVirtualMachinePathCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv virtual machine path: %#v", d)
+	c := meta.(api.Client)
+
+	rootPath := (d.Get("root_path")).(string)
+	name := (d.Get("name")).(string)
+	virtualMachinePath := api.NewVirtualMachinePath(rootPath, name)
+
+	err := c.CreateVirtualMachinePath(ctx, virtualMachinePath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(virtualMachinePath.Path)
+	log.Printf("[INFO][hyperv][create] created hyperv virtual machine path: %#v", d)
+
+	return resourceHyperVVirtualMachinePathRead(ctx, d, meta)
+}
+
+func resourceHyperVVirtualMachinePathRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv virtual machine path: %#v", d)
+	c := meta.(api.Client)
+
+	rootPath, name := api.SplitVirtualMachinePath(d.Id())
+	virtualMachinePath := api.NewVirtualMachinePath(rootPath, name)
+
+	exists, err := c.VirtualMachinePathExists(ctx, virtualMachinePath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !exists {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv virtual machine path as it does not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("root_path", rootPath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("name", name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("path", virtualMachinePath.Path); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("virtual_machines_path", virtualMachinePath.VirtualMachinesPath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("virtual_hard_disks_path", virtualMachinePath.VirtualHardDisksPath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("snapshots_path", virtualMachinePath.SnapshotsPath); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv virtual machine path: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVirtualMachinePathDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv virtual machine path: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteVirtualMachinePath(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv virtual machine path: %#v", d)
+	return nil
+}
@@ -0,0 +1,58 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+var testAccCheckHyperVVirtualMachinePathDestroy = testAccCheckDestroy("hyperv_virtual_machine_path", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	return c.VirtualMachinePathExists(ctx, api.NewVirtualMachinePath(api.SplitVirtualMachinePath(id)))
+})
+
+func TestHyperVResourceVirtualMachinePath(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	rootPath := testAccDirectory()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVVirtualMachinePathDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVirtualMachinePathConfig(rootPath, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_virtual_machine_path.this", "name", name),
+					resource.TestCheckResourceAttrSet("hyperv_virtual_machine_path.this", "virtual_machines_path"),
+					resource.TestCheckResourceAttrSet("hyperv_virtual_machine_path.this", "virtual_hard_disks_path"),
+					resource.TestCheckResourceAttrSet("hyperv_virtual_machine_path.this", "snapshots_path"),
+				),
+			},
+			{
+				ResourceName:      "hyperv_virtual_machine_path.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testHyperVResourceVirtualMachinePathConfig(rootPath string, name string) string {
+	return fmt.Sprintf(`
+resource "hyperv_virtual_machine_path" "this" {
+	root_path = "%s"
+	name      = "%s"
+}
+	`, escapeForHcl(rootPath), escapeForHcl(name))
+}
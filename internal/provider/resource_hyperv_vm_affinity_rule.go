@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmAffinityRuleTimeout   = 2 * time.Minute
+	CreateVmAffinityRuleTimeout = 5 * time.Minute
+	UpdateVmAffinityRuleTimeout = 5 * time.Minute
+	DeleteVmAffinityRuleTimeout = 5 * time.Minute
+)
+
+// the name of a vm affinity rule is used as an anti-affinity class name and as the file name of its record on the
+// HyperV host machine
+var vmAffinityRuleNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+var vmAffinityRuleIdFormat = []string{"<rule_type>", "<name>"}
+
+func resourceHyperVVmAffinityRule() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to keep the virtual machines of a highly available application tier on different hosts (anti-affinity) or to keep virtual machines that talk to each other a lot on the same host (affinity). When `cluster_name` is set the rule is handed to the failover cluster: an anti-affinity rule is set as an anti-affinity class name on the cluster groups of the virtual machines, an affinity rule is created as a cluster affinity rule which needs Windows Server 2022 or later. The virtual machines must already be clustered with `hyperv_cluster_vm_role`. When `cluster_name` is empty the HyperV host machine is standalone and can not move virtual machines, so creating or updating the rule fails when the virtual machines on the host break it. The rule is recorded in `%ProgramData%\\terraform-provider-hyperv\\vm-affinity-rules` on the HyperV host machine. Reading the rule warns when the current placement of the virtual machines breaks it, as the cluster only avoids breaking an anti-affinity rule when it has a choice. It can be imported with an id in the format `<rule_type>|<name>` e.g. `AntiAffinity|sql`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmAffinityRuleTimeout),
+			Create: schema.DefaultTimeout(CreateVmAffinityRuleTimeout),
+			Update: schema.DefaultTimeout(UpdateVmAffinityRuleTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmAffinityRuleTimeout),
+		},
+		CreateContext: resourceHyperVVmAffinityRuleCreate,
+		ReadContext:   resourceHyperVVmAffinityRuleRead,
+		UpdateContext: resourceHyperVVmAffinityRuleUpdate,
+		DeleteContext: resourceHyperVVmAffinityRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: StringMatch(vmAffinityRuleNameRegexp, "expected a name of letters, digits, underscores, hyphens and periods"),
+				Description:      "Specifies the name of the rule. Anti-affinity rules use it as the anti-affinity class name of the cluster groups.",
+			},
+			"rule_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          api.VmAffinityRuleType_name[api.VmAffinityRuleType_AntiAffinity],
+				ValidateDiagFunc: stringKeyInMap(api.VmAffinityRuleType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies whether the virtual machines must run on different hosts or on the same host. Valid values to use are `AntiAffinity`, `Affinity`.",
+			},
+			"cluster_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the failover cluster that enforces the rule. When empty the rule is enforced by the provider on the HyperV host machine only.",
+			},
+			"vm_names": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    2,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Specifies the names of the virtual machines the rule applies to.",
+			},
+			"vm_hosts": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The host each virtual machine of the rule runs on, keyed by the name of the virtual machine. The host is empty when the virtual machine could not be found.",
+			},
+			"satisfied": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the current placement of the virtual machines keeps to the rule.",
+			},
+		},
+	}
+}
+
+func getVmAffinityRuleId(ruleType api.VmAffinityRuleType, name string) string {
+	return getVmDeviceId(ruleType.String(), name)
+}
+
+func parseVmAffinityRuleId(id string) (ruleType api.VmAffinityRuleType, name string, err error) {
+	parts, err := parseVmDeviceId("vm affinity rule", id, vmAffinityRuleIdFormat...)
+	if err != nil {
+		return ruleType, name, err
+	}
+
+	if _, ok := api.VmAffinityRuleType_value[strings.ToLower(parts[0])]; !ok {
+		return ruleType, name, fmt.Errorf("[ERROR][hyperv] vm affinity rule id %q has an unknown rule type %q", id, parts[0])
+	}
+
+	return api.ToVmAffinityRuleType(parts[0]), parts[1], nil
+}
+
+func expandVmAffinityRule(d *schema.ResourceData) (api.VmAffinityRule, error) {
+	vmNames := []string{}
+	if raw, ok := d.GetOk("vm_names"); ok {
+		for _, v := range raw.(*schema.Set).List() {
+			vmNames = append(vmNames, v.(string))
+		}
+	}
+
+	vmAffinityRule := api.VmAffinityRule{
+		Name:        (d.Get("name")).(string),
+		RuleType:    api.ToVmAffinityRuleType((d.Get("rule_type")).(string)),
+		ClusterName: (d.Get("cluster_name")).(string),
+		VmNames:     vmNames,
+	}
+
+	return vmAffinityRule, vmAffinityRule.Validate()
+}
+
+func resourceHyperVVmAffinityRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm affinity rule: %#v", d)
+	c := meta.(api.Client)
+
+	vmAffinityRule, err := expandVmAffinityRule(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	id := getVmAffinityRuleId(vmAffinityRule.RuleType, vmAffinityRule.Name)
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmAffinityRule(ctx, vmAffinityRule.Name, vmAffinityRule.RuleType)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", id, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", id, "hyperv_vm_affinity_rule", "hyperv_vm_affinity_rule", id))
+		}
+	}
+
+	err = c.CreateOrUpdateVmAffinityRule(ctx, vmAffinityRule.Name, vmAffinityRule.RuleType, vmAffinityRule.ClusterName, vmAffinityRule.VmNames)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id)
+	log.Printf("[INFO][hyperv][create] created hyperv vm affinity rule: %#v", d)
+
+	return resourceHyperVVmAffinityRuleRead(ctx, d, meta)
+}
+
+func resourceHyperVVmAffinityRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm affinity rule: %#v", d)
+	c := meta.(api.Client)
+
+	ruleType, name, err := parseVmAffinityRuleId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	vmAffinityRule, err := c.GetVmAffinityRule(ctx, name, ruleType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm affinity rule: %+v", vmAffinityRule)
+
+	if vmAffinityRule.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm affinity rule as it does not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	vmHosts := make(map[string]interface{})
+	for _, placement := range vmAffinityRule.Placements {
+		vmHosts[placement.VmName] = placement.Host
+	}
+
+	violations := vmAffinityRule.Violations()
+
+	if err := d.Set("name", vmAffinityRule.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("rule_type", vmAffinityRule.RuleType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cluster_name", vmAffinityRule.ClusterName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vm_names", api.PreferConfiguredCase(d, "vm_names", vmAffinityRule.VmNames)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vm_hosts", vmHosts); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("satisfied", len(violations) == 0); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm affinity rule: %#v", d)
+
+	var diags diag.Diagnostics
+	for _, violation := range violations {
+		log.Printf("[WARN][hyperv][read] %s rule %s is broken: %s", vmAffinityRule.RuleType.String(), vmAffinityRule.Name, violation)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s rule %s is broken", vmAffinityRule.RuleType.String(), vmAffinityRule.Name),
+			Detail:   violation,
+		})
+	}
+
+	return diags
+}
+
+func resourceHyperVVmAffinityRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm affinity rule: %#v", d)
+	c := meta.(api.Client)
+
+	vmAffinityRule, err := expandVmAffinityRule(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.CreateOrUpdateVmAffinityRule(ctx, vmAffinityRule.Name, vmAffinityRule.RuleType, vmAffinityRule.ClusterName, vmAffinityRule.VmNames)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm affinity rule: %#v", d)
+
+	return resourceHyperVVmAffinityRuleRead(ctx, d, meta)
+}
+
+func resourceHyperVVmAffinityRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm affinity rule: %#v", d)
+	c := meta.(api.Client)
+
+	ruleType, name, err := parseVmAffinityRuleId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.DeleteVmAffinityRule(ctx, name, ruleType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm affinity rule: %#v", d)
+	return nil
+}
@@ -0,0 +1,108 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmAffinityRule(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("affinity")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmAffinityRuleConfig(name, "Affinity", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "rule_type", "Affinity"),
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "vm_names.#", "2"),
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "satisfied", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceVmAffinityRuleCluster(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("antiaffinity")
+	clusterName := os.Getenv("HYPERV_TEST_CLUSTER_NAME")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_CLUSTER_NAME")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmAffinityRuleConfig(name, "AntiAffinity", clusterName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "rule_type", "AntiAffinity"),
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "cluster_name", clusterName),
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "vm_names.#", "2"),
+					resource.TestCheckResourceAttr("hyperv_vm_affinity_rule.this", "vm_hosts.%", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmAffinityRuleConfig(name string, ruleType string, clusterName string) string {
+	clusterVmRoles := ""
+	vmNames := "[hyperv_machine_instance.first.name, hyperv_machine_instance.second.name]"
+	if clusterName != "" {
+		clusterVmRoles = `
+resource "hyperv_cluster_vm_role" "first" {
+	vm_name = hyperv_machine_instance.first.name
+}
+
+resource "hyperv_cluster_vm_role" "second" {
+	vm_name = hyperv_machine_instance.second.name
+}
+`
+		vmNames = "[hyperv_cluster_vm_role.first.vm_name, hyperv_cluster_vm_role.second.vm_name]"
+	}
+
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "first" {
+	name         = "%s_1"
+	generation   = 2
+	state        = "Off"
+	cluster_name = "%s"
+}
+
+resource "hyperv_machine_instance" "second" {
+	name         = "%s_2"
+	generation   = 2
+	state        = "Off"
+	cluster_name = "%s"
+}
+%s
+resource "hyperv_vm_affinity_rule" "this" {
+	name         = "%s"
+	rule_type    = "%s"
+	cluster_name = "%s"
+	vm_names     = %s
+}
+	`, escapeForHcl(name), escapeForHcl(clusterName), escapeForHcl(name), escapeForHcl(clusterName), clusterVmRoles, escapeForHcl(name), ruleType, escapeForHcl(clusterName), vmNames)
+}
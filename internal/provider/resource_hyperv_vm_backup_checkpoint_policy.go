@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmBackupCheckpointPolicyTimeout   = 1 * time.Minute
+	CreateVmBackupCheckpointPolicyTimeout = 5 * time.Minute
+	UpdateVmBackupCheckpointPolicyTimeout = 5 * time.Minute
+	DeleteVmBackupCheckpointPolicyTimeout = 1 * time.Minute
+)
+
+func resourceHyperVVmBackupCheckpointPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to declare what backup software needs of a virtual machine: the type of its checkpoints, the backup integration service that takes application consistent checkpoints through the volume shadow copy service of the guest, and how many resilient change tracking reference points are kept for incremental backups. It manages `checkpoint_type` of the virtual machine, so do not use it for a `hyperv_machine_instance` unless `checkpoint_type` and `integration_services` are in the `ignore_changes` of that machine instance. Destroying the resource leaves the settings of the virtual machine as they are. The ID is the name of the virtual machine.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmBackupCheckpointPolicyTimeout),
+			Create: schema.DefaultTimeout(CreateVmBackupCheckpointPolicyTimeout),
+			Update: schema.DefaultTimeout(UpdateVmBackupCheckpointPolicyTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmBackupCheckpointPolicyTimeout),
+		},
+		CreateContext: resourceHyperVVmBackupCheckpointPolicyCreate,
+		ReadContext:   resourceHyperVVmBackupCheckpointPolicyRead,
+		UpdateContext: resourceHyperVVmBackupCheckpointPolicyUpdate,
+		DeleteContext: resourceHyperVVmBackupCheckpointPolicyDelete,
+		CustomizeDiff: customizeDiffForVmBackupCheckpointPolicy,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine the policy applies to.",
+			},
+			"checkpoint_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.CheckpointType_name[api.CheckpointType_Production],
+				ValidateDiagFunc: stringKeyInMap(api.CheckpointType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the type of the checkpoints of the virtual machine, including the ones taken by backup software. `ProductionOnly` makes a backup fail rather than fall back to a checkpoint that is only crash consistent, it requires `backup_integration_enabled`. Valid values to use are `Disabled`, `Standard`, `Production`, `ProductionOnly`.",
+			},
+			"automatic_checkpoints_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Specifies whether Hyper-V takes a checkpoint every time the virtual machine is started. Backup software usually expects them to be disabled, as they grow the chain of differencing disks it has to merge.",
+			},
+			"backup_integration_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether the backup integration service, named `VSS` or `Backup (volume shadow copy)` depending on the version of Windows, is enabled, so that production checkpoints are consistent for the applications in the guest operating system.",
+			},
+			"reference_point_retention": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, 100),
+				Description:      "Specifies how many of the newest resilient change tracking reference points of the virtual machine are kept. Backup software creates a reference point with every backup to take the next one incrementally, older ones that it failed to remove are removed on the next apply. Make sure to keep at least as many as the backup software needs, as an incremental backup from a removed reference point falls back to a full backup. `0` leaves the reference points as they are.",
+			},
+			"backup_integration_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the backup integration service as reported by the guest e.g. `OK`, or `No Contact` when the guest does not run the integration services.",
+			},
+			"reference_point_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of resilient change tracking reference points of the virtual machine.",
+			},
+		},
+	}
+}
+
+// customizeDiffForVmBackupCheckpointPolicy plans an update when the virtual machine has more reference points than are
+// retained, so that the oldest ones are removed
+func customizeDiffForVmBackupCheckpointPolicy(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+
+	vmBackupCheckpointPolicy := api.VmBackupCheckpointPolicy{
+		ReferencePointRetention: int32((diff.Get("reference_point_retention")).(int)),
+		ReferencePointCount:     int32((diff.Get("reference_point_count")).(int)),
+	}
+	if vmBackupCheckpointPolicy.HasReferencePointsToPrune() {
+		return diff.SetNewComputed("reference_point_count")
+	}
+
+	return nil
+}
+
+func expandVmBackupCheckpointPolicy(d *schema.ResourceData) (api.VmBackupCheckpointPolicy, error) {
+	vmBackupCheckpointPolicy := api.VmBackupCheckpointPolicy{
+		VmName:                      (d.Get("vm_name")).(string),
+		CheckpointType:              api.ToCheckpointType((d.Get("checkpoint_type")).(string)),
+		AutomaticCheckpointsEnabled: (d.Get("automatic_checkpoints_enabled")).(bool),
+		BackupIntegrationEnabled:    (d.Get("backup_integration_enabled")).(bool),
+		ReferencePointRetention:     int32((d.Get("reference_point_retention")).(int)),
+	}
+
+	return vmBackupCheckpointPolicy, vmBackupCheckpointPolicy.Validate()
+}
+
+func resourceHyperVVmBackupCheckpointPolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm backup checkpoint policy: %#v", d)
+	c := meta.(api.Client)
+
+	vmBackupCheckpointPolicy, err := expandVmBackupCheckpointPolicy(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmBackupCheckpointPolicy(ctx, vmBackupCheckpointPolicy.VmName)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", vmBackupCheckpointPolicy.VmName, err))
+		}
+
+		if existing.VmName == "" {
+			return diag.FromErr(fmt.Errorf("[ERROR][hyperv] vm %s of backup checkpoint policy does not exist", vmBackupCheckpointPolicy.VmName))
+		}
+	}
+
+	err = c.CreateOrUpdateVmBackupCheckpointPolicy(ctx, vmBackupCheckpointPolicy)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmBackupCheckpointPolicy.VmName)
+	log.Printf("[INFO][hyperv][create] created hyperv vm backup checkpoint policy: %#v", d)
+
+	return resourceHyperVVmBackupCheckpointPolicyRead(ctx, d, meta)
+}
+
+func resourceHyperVVmBackupCheckpointPolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm backup checkpoint policy: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := d.Id()
+
+	vmBackupCheckpointPolicy, err := c.GetVmBackupCheckpointPolicy(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm backup checkpoint policy: %+v", vmBackupCheckpointPolicy)
+
+	if vmBackupCheckpointPolicy.VmName == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm backup checkpoint policy as vm does not exist: %#v", vmName)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("vm_name", vmBackupCheckpointPolicy.VmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("checkpoint_type", vmBackupCheckpointPolicy.CheckpointType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("automatic_checkpoints_enabled", vmBackupCheckpointPolicy.AutomaticCheckpointsEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("backup_integration_enabled", vmBackupCheckpointPolicy.BackupIntegrationEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("backup_integration_status", vmBackupCheckpointPolicy.BackupIntegrationStatus); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("reference_point_count", vmBackupCheckpointPolicy.ReferencePointCount); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm backup checkpoint policy: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmBackupCheckpointPolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm backup checkpoint policy: %#v", d)
+	c := meta.(api.Client)
+
+	vmBackupCheckpointPolicy, err := expandVmBackupCheckpointPolicy(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.CreateOrUpdateVmBackupCheckpointPolicy(ctx, vmBackupCheckpointPolicy)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm backup checkpoint policy: %#v", d)
+
+	return resourceHyperVVmBackupCheckpointPolicyRead(ctx, d, meta)
+}
+
+func resourceHyperVVmBackupCheckpointPolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm backup checkpoint policy: %#v", d)
+
+	// the virtual machine keeps its settings, there is nothing to restore them to
+	d.SetId("")
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm backup checkpoint policy: %#v", d)
+	return nil
+}
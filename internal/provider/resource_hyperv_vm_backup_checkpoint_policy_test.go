@@ -0,0 +1,69 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmBackupCheckpointPolicy(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("backup")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmBackupCheckpointPolicyConfig(name, "ProductionOnly", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_backup_checkpoint_policy.this", "checkpoint_type", "ProductionOnly"),
+					resource.TestCheckResourceAttr("hyperv_vm_backup_checkpoint_policy.this", "backup_integration_enabled", "true"),
+					resource.TestCheckResourceAttr("hyperv_vm_backup_checkpoint_policy.this", "reference_point_count", "0"),
+				),
+			},
+			{
+				Config: testHyperVResourceVmBackupCheckpointPolicyConfig(name, "Standard", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_backup_checkpoint_policy.this", "checkpoint_type", "Standard"),
+					resource.TestCheckResourceAttr("hyperv_vm_backup_checkpoint_policy.this", "backup_integration_enabled", "false"),
+				),
+			},
+			{
+				ResourceName:      "hyperv_vm_backup_checkpoint_policy.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmBackupCheckpointPolicyConfig(name string, checkpointType string, backupIntegrationEnabled bool) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+
+	lifecycle {
+		ignore_changes = [checkpoint_type, integration_services]
+	}
+}
+
+resource "hyperv_vm_backup_checkpoint_policy" "this" {
+	vm_name                    = hyperv_machine_instance.this.name
+	checkpoint_type            = "%s"
+	backup_integration_enabled = %t
+	reference_point_retention  = 2
+}
+	`, escapeForHcl(name), checkpointType, backupIntegrationEnabled)
+}
@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmDvdDriveTimeout   = 1 * time.Minute
+	CreateVmDvdDriveTimeout = 5 * time.Minute
+	UpdateVmDvdDriveTimeout = 5 * time.Minute
+	DeleteVmDvdDriveTimeout = 5 * time.Minute
+)
+
+var vmDvdDriveIdFormat = []string{"<vm_name>", "<controller_number>", "<controller_location>"}
+
+func resourceHyperVVmDvdDrive() *schema.Resource {
+	resourceSchema := vmDvdDriveSchema()
+	resourceSchema["controller_number"].ForceNew = true
+	resourceSchema["controller_location"].ForceNew = true
+	resourceSchema["vm_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "Specifies the name of the virtual machine to attach the DVD drive to.",
+	}
+	resourceSchema["path"].ConflictsWith = []string{"iso_library", "iso_name"}
+	resourceSchema["path"].Description = "Specifies the full path to the iso file for the added DVD drive. Leave it empty when the iso is referenced with `iso_library` and `iso_name`."
+	resourceSchema["iso_library"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Default:          "",
+		RequiredWith:     []string{"iso_name"},
+		DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+		Description:      "Specifies the name of the `hyperv_iso_library` that contains the iso to insert into the DVD drive.",
+	}
+	resourceSchema["iso_name"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		Default:          "",
+		RequiredWith:     []string{"iso_library"},
+		DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+		Description:      "Specifies the name of the iso in `iso_library` to insert into the DVD drive. The path of the iso is looked up in the library every time the DVD drive is created or updated, so changing the iso in the library changes the iso in the DVD drive. When the library has a `cache_directory` the copy of the iso on the HyperV host machine is inserted.",
+	}
+
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a single DVD drive attached to a virtual machine. Do not use it for a virtual machine that declares `dvd_drives` in `hyperv_machine_instance`, unless `dvd_drives` is in the `ignore_changes` of that machine instance. It can be imported with an id in the format `<vm_name>|<controller_number>|<controller_location>` e.g. `web|0|1`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmDvdDriveTimeout),
+			Create: schema.DefaultTimeout(CreateVmDvdDriveTimeout),
+			Update: schema.DefaultTimeout(UpdateVmDvdDriveTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmDvdDriveTimeout),
+		},
+		CreateContext: resourceHyperVVmDvdDriveCreate,
+		ReadContext:   resourceHyperVVmDvdDriveRead,
+		UpdateContext: resourceHyperVVmDvdDriveUpdate,
+		DeleteContext: resourceHyperVVmDvdDriveDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceHyperVVmDvdDriveImport,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+func getVmDvdDriveId(vmName string, controllerNumber int, controllerLocation int) string {
+	return getVmDeviceId(vmName, controllerNumber, controllerLocation)
+}
+
+func parseVmDvdDriveId(id string) (vmName string, controllerNumber int, controllerLocation int, err error) {
+	parts, err := parseVmDeviceId("vm dvd drive", id, vmDvdDriveIdFormat...)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	controllerNumber, numberErr := strconv.Atoi(parts[1])
+	controllerLocation, locationErr := strconv.Atoi(parts[2])
+	if numberErr != nil || locationErr != nil {
+		return "", 0, 0, fmt.Errorf("[ERROR][hyperv] unable to parse vm dvd drive id %q - expected format is `%s`", id, strings.Join(vmDvdDriveIdFormat, vmDeviceIdSeparator))
+	}
+
+	return parts[0], controllerNumber, controllerLocation, nil
+}
+
+// getVmDvdDrivePath returns the path of the iso to insert into the dvd drive, looking it up in the iso library when
+// the iso is referenced by name
+func getVmDvdDrivePath(ctx context.Context, c api.Client, d *schema.ResourceData, path string) (string, error) {
+	isoLibraryName := (d.Get("iso_library")).(string)
+	isoName := (d.Get("iso_name")).(string)
+	if isoLibraryName == "" || isoName == "" {
+		return path, nil
+	}
+
+	isoLibrary, err := c.GetIsoLibrary(ctx, isoLibraryName)
+	if err != nil {
+		return "", err
+	}
+
+	if isoLibrary.Name == "" {
+		return "", fmt.Errorf("[ERROR][hyperv] iso library %s does not exist", isoLibraryName)
+	}
+
+	iso, found := isoLibrary.Iso(isoName)
+	if !found {
+		return "", fmt.Errorf("[ERROR][hyperv] iso %s does not exist in iso library %s", isoName, isoLibraryName)
+	}
+
+	return iso.DvdDrivePath(), nil
+}
+
+func resourceHyperVVmDvdDriveImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	vmName, controllerNumber, controllerLocation, err := parseVmDvdDriveId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(getVmDvdDriveId(vmName, controllerNumber, controllerLocation))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceHyperVVmDvdDriveCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm dvd drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	dvdDrive := api.ExpandDvdDrive(vmDeviceFromResourceData(d, vmDvdDriveSchema()))
+
+	path, err := getVmDvdDrivePath(ctx, c, d, dvdDrive.Path)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	dvdDrive.Path = path
+
+	err = c.CreateVmDvdDrive(ctx, vmName, dvdDrive.ControllerNumber, dvdDrive.ControllerLocation, dvdDrive.Path, dvdDrive.ResourcePoolName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getVmDvdDriveId(vmName, dvdDrive.ControllerNumber, dvdDrive.ControllerLocation))
+	log.Printf("[INFO][hyperv][create] created hyperv vm dvd drive: %#v", d)
+
+	return resourceHyperVVmDvdDriveRead(ctx, d, meta)
+}
+
+func resourceHyperVVmDvdDriveRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm dvd drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, controllerNumber, controllerLocation, err := parseVmDvdDriveId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	dvdDrives, err := c.GetVmDvdDrives(ctx, vmName)
+	if err != nil {
+		if errors.Is(err, api.ErrNotFound) {
+			log.Printf("[INFO][hyperv][read] vm %s for dvd drive %s does not exist", vmName, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved dvd drives: %+v", dvdDrives)
+
+	for _, dvdDrive := range dvdDrives {
+		if dvdDrive.ControllerNumber != controllerNumber || dvdDrive.ControllerLocation != controllerLocation {
+			continue
+		}
+
+		if err := d.Set("vm_name", vmName); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := setVmDeviceResourceData(d, api.FlattenDvdDrive(dvdDrive)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		// an iso referenced from an iso library is kept out of path, unless the dvd drive no longer has the iso of
+		// the library in it, so that the dvd drive is updated when the iso in the library changes
+		isoLibraryPath, err := getVmDvdDrivePath(ctx, c, d, "")
+		if err != nil {
+			log.Printf("[INFO][hyperv][read] unable to look up iso of dvd drive %s in iso library: %s", d.Id(), err)
+		} else if isoLibraryPath != "" && api.EqualWindowsPaths(isoLibraryPath, dvdDrive.Path) {
+			if err := d.Set("path", ""); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		log.Printf("[INFO][hyperv][read] read hyperv vm dvd drive: %#v", d)
+		return nil
+	}
+
+	log.Printf("[INFO][hyperv][read] dvd drive %s does not exist", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceHyperVVmDvdDriveUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm dvd drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	dvdDrive := api.ExpandDvdDrive(vmDeviceFromResourceData(d, vmDvdDriveSchema()))
+
+	path, err := getVmDvdDrivePath(ctx, c, d, dvdDrive.Path)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	dvdDrive.Path = path
+
+	err = c.UpdateVmDvdDrive(ctx, vmName, dvdDrive.ControllerNumber, dvdDrive.ControllerLocation, dvdDrive.ControllerNumber, dvdDrive.ControllerLocation, dvdDrive.Path, dvdDrive.ResourcePoolName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm dvd drive: %#v", d)
+
+	return resourceHyperVVmDvdDriveRead(ctx, d, meta)
+}
+
+func resourceHyperVVmDvdDriveDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm dvd drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, controllerNumber, controllerLocation, err := parseVmDvdDriveId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.DeleteVmDvdDrive(ctx, vmName, controllerNumber, controllerLocation)
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm dvd drive: %#v", d)
+	return nil
+}
+
+func vmDvdDriveSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"controller_number": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Specifies the number of the controller to which the DVD drive is to be added.",
+		},
+		"controller_location": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Specifies the number of the location on the controller at which the DVD drive is to be added.",
+		},
+		"path": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			ValidateDiagFunc: IsWindowsPath(),
+			DiffSuppressFunc: api.DiffSuppressWindowsPath,
+			Description:      "Specifies the full path to the virtual hard disk file or physical hard disk volume for the added DVD drive.",
+		},
+		"resource_pool_name": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the friendly name of the ISO resource pool to which this DVD drive is to be associated.",
+		},
+	}
+}
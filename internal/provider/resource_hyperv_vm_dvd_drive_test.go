@@ -0,0 +1,59 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmDvdDrive(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	path := testAccPath("seed.iso")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmDvdDriveConfig(name, path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_dvd_drive.this", "vm_name", name),
+					resource.TestCheckResourceAttr("hyperv_vm_dvd_drive.this", "controller_location", "1"),
+					resource.TestCheckResourceAttr("hyperv_vm_dvd_drive.this", "path", path),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmDvdDriveConfig(name string, path string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_dvd" "this" {
+	path = "%s"
+	ip   = "172.16.1.10"
+}
+
+resource "hyperv_vm_dvd_drive" "this" {
+	vm_name             = hyperv_machine_instance.this.name
+	controller_number   = 0
+	controller_location = 1
+	path                = hyperv_dvd.this.path
+}
+	`, escapeForHcl(name), escapeForHcl(path))
+}
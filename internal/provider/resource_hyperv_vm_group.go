@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmGroupTimeout   = 1 * time.Minute
+	CreateVmGroupTimeout = 2 * time.Minute
+	UpdateVmGroupTimeout = 2 * time.Minute
+	DeleteVmGroupTimeout = 1 * time.Minute
+)
+
+func resourceHyperVVmGroup() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a group of virtual machines, so that they can be managed together e.g. checkpointing all the virtual machines of an application at the same time.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmGroupTimeout),
+			Create: schema.DefaultTimeout(CreateVmGroupTimeout),
+			Update: schema.DefaultTimeout(UpdateVmGroupTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmGroupTimeout),
+		},
+		CreateContext: resourceHyperVVmGroupCreate,
+		ReadContext:   resourceHyperVVmGroupRead,
+		UpdateContext: resourceHyperVVmGroupUpdate,
+		DeleteContext: resourceHyperVVmGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine group.",
+			},
+			"group_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          api.VMGroupType_name[api.VMGroupType_VMCollectionType],
+				ValidateDiagFunc: stringKeyInMap(api.VMGroupType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the type of the virtual machine group. A `VMCollectionType` group contains virtual machines, a `ManagementCollectionType` group contains other virtual machine groups. Valid values to use are `VMCollectionType`, `ManagementCollectionType`.",
+			},
+			"vm_members": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"vm_group_members"},
+				Description:   "Specifies the names of the virtual machines that are members of the group. Only valid for a `VMCollectionType` group.",
+			},
+			"vm_group_members": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"vm_members"},
+				Description:   "Specifies the names of the virtual machine groups that are members of the group. Only valid for a `ManagementCollectionType` group.",
+			},
+			"group_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the virtual machine group.",
+			},
+		},
+	}
+}
+
+func expandVmGroupMembers(d *schema.ResourceData, key string) []string {
+	members := []string{}
+	if raw, ok := d.GetOk(key); ok {
+		for _, v := range raw.(*schema.Set).List() {
+			members = append(members, v.(string))
+		}
+	}
+
+	return members
+}
+
+func resourceHyperVVmGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm group: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+	groupType := api.ToVMGroupType((d.Get("group_type")).(string))
+	vmMembers := expandVmGroupMembers(d, "vm_members")
+	vmGroupMembers := expandVmGroupMembers(d, "vm_group_members")
+
+	if groupType == api.VMGroupType_VMCollectionType && len(vmGroupMembers) > 0 {
+		return diag.Errorf("[ERROR][hyperv][create] vm_group_members can only be used with a ManagementCollectionType group")
+	}
+
+	if groupType == api.VMGroupType_ManagementCollectionType && len(vmMembers) > 0 {
+		return diag.Errorf("[ERROR][hyperv][create] vm_members can only be used with a VMCollectionType group")
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmGroup(ctx, name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", name, err))
+		}
+
+		if existing.Name != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", name, "hyperv_vm_group", "hyperv_vm_group", name))
+		}
+	}
+
+	err := c.CreateVmGroup(ctx, name, groupType, vmMembers, vmGroupMembers)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO][hyperv][create] created hyperv vm group: %#v", d)
+
+	return resourceHyperVVmGroupRead(ctx, d, meta)
+}
+
+func resourceHyperVVmGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm group: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	vmGroup, err := c.GetVmGroup(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm group: %+v", vmGroup)
+
+	if vmGroup.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm group as it does not exist: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", vmGroup.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("group_type", vmGroup.GroupType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vm_members", api.PreferConfiguredCase(d, "vm_members", vmGroup.VmMembers)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vm_group_members", api.PreferConfiguredCase(d, "vm_group_members", vmGroup.VmGroupMembers)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("group_id", vmGroup.Id); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm group: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm group: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+	vmMembers := expandVmGroupMembers(d, "vm_members")
+	vmGroupMembers := expandVmGroupMembers(d, "vm_group_members")
+
+	err := c.UpdateVmGroup(ctx, name, vmMembers, vmGroupMembers)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm group: %#v", d)
+
+	return resourceHyperVVmGroupRead(ctx, d, meta)
+}
+
+func resourceHyperVVmGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm group: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	err := c.DeleteVmGroup(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm group: %#v", d)
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmGroup(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("group")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmGroupConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_group.this", "name", name),
+					resource.TestCheckResourceAttr("hyperv_vm_group.this", "vm_members.#", "1"),
+					resource.TestCheckResourceAttrSet("hyperv_vm_group.this", "group_id"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmGroupConfig(name string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_vm_group" "this" {
+	name       = "%s"
+	group_type = "VMCollectionType"
+	vm_members = [hyperv_machine_instance.this.name]
+}
+	`, escapeForHcl(name), escapeForHcl(name))
+}
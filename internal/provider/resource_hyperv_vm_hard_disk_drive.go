@@ -0,0 +1,300 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmHardDiskDriveTimeout   = 1 * time.Minute
+	CreateVmHardDiskDriveTimeout = 5 * time.Minute
+	UpdateVmHardDiskDriveTimeout = 5 * time.Minute
+	DeleteVmHardDiskDriveTimeout = 5 * time.Minute
+)
+
+var vmHardDiskDriveIdFormat = []string{"<vm_name>", "<controller_type>", "<controller_number>", "<controller_location>"}
+
+func resourceHyperVVmHardDiskDrive() *schema.Resource {
+	resourceSchema := vmHardDiskDriveSchema()
+	resourceSchema["controller_type"].ForceNew = true
+	resourceSchema["controller_number"].ForceNew = true
+	resourceSchema["controller_location"].ForceNew = true
+	resourceSchema["vm_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "Specifies the name of the virtual machine to attach the hard disk drive to.",
+	}
+
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a single hard disk drive attached to a virtual machine. A hard disk drive on a scsi controller the virtual machine already has is added and removed while the virtual machine runs, one on an ide controller needs the virtual machine to be off. Do not use it for a virtual machine that declares `hard_disk_drives` in `hyperv_machine_instance`, unless `hard_disk_drives` is in the `ignore_changes` of that machine instance. It can be imported with an id in the format `<vm_name>|<controller_type>|<controller_number>|<controller_location>` e.g. `web|Scsi|0|1`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmHardDiskDriveTimeout),
+			Create: schema.DefaultTimeout(CreateVmHardDiskDriveTimeout),
+			Update: schema.DefaultTimeout(UpdateVmHardDiskDriveTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmHardDiskDriveTimeout),
+		},
+		CreateContext: resourceHyperVVmHardDiskDriveCreate,
+		ReadContext:   resourceHyperVVmHardDiskDriveRead,
+		UpdateContext: resourceHyperVVmHardDiskDriveUpdate,
+		DeleteContext: resourceHyperVVmHardDiskDriveDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceHyperVVmHardDiskDriveImport,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+func getVmHardDiskDriveId(vmName string, controllerType api.ControllerType, controllerNumber int32, controllerLocation int32) string {
+	return getVmDeviceId(vmName, controllerType.String(), controllerNumber, controllerLocation)
+}
+
+func parseVmHardDiskDriveId(id string) (vmName string, controllerType api.ControllerType, controllerNumber int32, controllerLocation int32, err error) {
+	parts, err := parseVmDeviceId("vm hard disk drive", id, vmHardDiskDriveIdFormat...)
+	if err != nil {
+		return "", controllerType, 0, 0, err
+	}
+
+	_, controllerTypeValid := api.ControllerType_value[strings.ToLower(parts[1])]
+	number, numberErr := strconv.ParseInt(parts[2], 10, 32)
+	location, locationErr := strconv.ParseInt(parts[3], 10, 32)
+	if !controllerTypeValid || numberErr != nil || locationErr != nil {
+		return "", controllerType, 0, 0, fmt.Errorf("[ERROR][hyperv] unable to parse vm hard disk drive id %q - expected format is `%s` where controller_type is `Ide` or `Scsi`", id, strings.Join(vmHardDiskDriveIdFormat, vmDeviceIdSeparator))
+	}
+
+	return parts[0], api.ToControllerType(parts[1]), int32(number), int32(location), nil
+}
+
+func resourceHyperVVmHardDiskDriveImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	vmName, controllerType, controllerNumber, controllerLocation, err := parseVmHardDiskDriveId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(getVmHardDiskDriveId(vmName, controllerType, controllerNumber, controllerLocation))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceHyperVVmHardDiskDriveCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm hard disk drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	hardDiskDrive, err := api.ExpandHardDiskDrive(vmDeviceFromResourceData(d, vmHardDiskDriveSchema()))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.CreateVmHardDiskDrive(
+		ctx,
+		vmName,
+		hardDiskDrive.ControllerType,
+		hardDiskDrive.ControllerNumber,
+		hardDiskDrive.ControllerLocation,
+		hardDiskDrive.Path,
+		hardDiskDrive.DiskNumber,
+		hardDiskDrive.ResourcePoolName,
+		hardDiskDrive.SupportPersistentReservations,
+		hardDiskDrive.MaximumIops,
+		hardDiskDrive.MinimumIops,
+		hardDiskDrive.QosPolicyId,
+		hardDiskDrive.OverrideCacheAttributes,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getVmHardDiskDriveId(vmName, hardDiskDrive.ControllerType, hardDiskDrive.ControllerNumber, hardDiskDrive.ControllerLocation))
+	log.Printf("[INFO][hyperv][create] created hyperv vm hard disk drive: %#v", d)
+
+	return resourceHyperVVmHardDiskDriveRead(ctx, d, meta)
+}
+
+func resourceHyperVVmHardDiskDriveRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm hard disk drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, controllerType, controllerNumber, controllerLocation, err := parseVmHardDiskDriveId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	hardDiskDrives, err := c.GetVmHardDiskDrives(ctx, vmName)
+	if err != nil {
+		if errors.Is(err, api.ErrNotFound) {
+			log.Printf("[INFO][hyperv][read] vm %s for hard disk drive %s does not exist", vmName, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved hard disk drives: %+v", hardDiskDrives)
+
+	for _, hardDiskDrive := range hardDiskDrives {
+		if hardDiskDrive.ControllerType != controllerType || hardDiskDrive.ControllerNumber != controllerNumber || hardDiskDrive.ControllerLocation != controllerLocation {
+			continue
+		}
+
+		if err := d.Set("vm_name", vmName); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := setVmDeviceResourceData(d, api.FlattenHardDiskDrive(hardDiskDrive)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("[INFO][hyperv][read] read hyperv vm hard disk drive: %#v", d)
+		return nil
+	}
+
+	log.Printf("[INFO][hyperv][read] hard disk drive %s does not exist", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceHyperVVmHardDiskDriveUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm hard disk drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	hardDiskDrive, err := api.ExpandHardDiskDrive(vmDeviceFromResourceData(d, vmHardDiskDriveSchema()))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.UpdateVmHardDiskDrive(
+		ctx,
+		vmName,
+		hardDiskDrive.ControllerNumber,
+		hardDiskDrive.ControllerLocation,
+		hardDiskDrive.ControllerType,
+		hardDiskDrive.ControllerNumber,
+		hardDiskDrive.ControllerLocation,
+		hardDiskDrive.Path,
+		hardDiskDrive.DiskNumber,
+		hardDiskDrive.ResourcePoolName,
+		hardDiskDrive.SupportPersistentReservations,
+		hardDiskDrive.MaximumIops,
+		hardDiskDrive.MinimumIops,
+		hardDiskDrive.QosPolicyId,
+		hardDiskDrive.OverrideCacheAttributes,
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm hard disk drive: %#v", d)
+
+	return resourceHyperVVmHardDiskDriveRead(ctx, d, meta)
+}
+
+func resourceHyperVVmHardDiskDriveDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm hard disk drive: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, controllerType, controllerNumber, controllerLocation, err := parseVmHardDiskDriveId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.DeleteVmHardDiskDrive(ctx, vmName, controllerType, controllerNumber, controllerLocation)
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm hard disk drive: %#v", d)
+	return nil
+}
+
+func vmHardDiskDriveSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"controller_type": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.ControllerType_name[api.ControllerType_Scsi],
+			ValidateDiagFunc: stringKeyInMap(api.ControllerType_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the type of the controller to which the hard disk drive is to be added. Valid values to use are `Ide`, `Scsi`.",
+		},
+		"controller_number": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Specifies the number of the controller to which the hard disk drive is to be added.",
+		},
+		"controller_location": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Specifies the number of the location on the controller at which the hard disk drive is to be added.",
+		},
+		"path": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			DiffSuppressFunc: api.DiffSuppressVmHardDiskPath,
+			ValidateDiagFunc: IsWindowsPath(),
+			Description:      "Specifies the full path of the hard disk drive file to be added.",
+		},
+		"base_path": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The path of the hard disk drive file the checkpoints of the virtual machine are based on. When the virtual machine has checkpoints, `path` is the automatic differencing disk the virtual machine writes to, and a configured `path` that is this file is left as it is.",
+		},
+		"disk_number": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     MaxUint32,
+			Description: "Specifies the disk number of the offline physical hard drive to be connected as a passthrough disk. If value is 4294967295 then disk number is ignored.",
+		},
+		"resource_pool_name": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "Primordial",
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the friendly name of the resource pool to which this virtual hard disk is to be associated.",
+		},
+		"support_persistent_reservations": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Indicates that the hard disk supports SCSI persistent reservation semantics. Specify this parameter when the hard disk is a shared disk that is used by multiple virtual machines.",
+		},
+		"maximum_iops": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the maximum normalized I/O operations per second (IOPS) for the hard disk. Hyper-V calculates normalized IOPS as the total size of I/O per second divided by 8 KB. If value is 0 then iops is ignored.",
+		},
+		"minimum_iops": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the minimum normalized I/O operations per second (IOPS) for the hard disk. Hyper-V calculates normalized IOPS as the total size of I/O per second divided by 8 KB. If maximum iops value is 0 then iops is ignored.",
+		},
+		"qos_policy_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "00000000-0000-0000-0000-000000000000",
+			Description: "Specifies the unique ID for a storage QoS policy that this cmdlet associates with the hard disk drive, see `hyperv_storage_qos_policy`. Can not be used together with `minimum_iops` or `maximum_iops`. If value is 00000000-0000-0000-0000-000000000000 then qos policy id is ignored.",
+		},
+		"override_cache_attributes": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.CacheAttributes_name[api.CacheAttributes_Default],
+			ValidateDiagFunc: stringKeyInMap(api.CacheAttributes_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "With Default it is equivalent of WriteCacheDisabled. With WriteCacheEnabled write I/O is acknowledged as written before it is committed to stable media. If your internal disks, DAS, SAN, or NAS has a battery backup system that can guarantee clean cache flushes on a power outage, write caching is generally safe. Internal batteries that report their status and/or automatically disable caching are best. UPS-backed systems are sometimes OK, but they are not foolproof. With WriteCacheAndFUAEnabled write I/O is committed to stable media BEFORE the I/O is acknowledged as written. With WriteCacheDisabled when I/O is written it is acknowledged as written as there is no cache in between. Valid values to use are `Default`, `WriteCacheEnabled`, `WriteCacheAndFUAEnabled`, `WriteCacheDisabled`.",
+		},
+	}
+}
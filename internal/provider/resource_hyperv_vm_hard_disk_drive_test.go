@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmHardDiskDrive(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	path := testAccPath("disk.vhdx")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmHardDiskDriveConfig(name, path, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_hard_disk_drive.this", "vm_name", name),
+					resource.TestCheckResourceAttr("hyperv_vm_hard_disk_drive.this", "path", path),
+					resource.TestCheckResourceAttr("hyperv_vm_hard_disk_drive.this", "maximum_iops", "0"),
+				),
+			},
+			{
+				Config: testHyperVResourceVmHardDiskDriveConfig(name, path, 1000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_hard_disk_drive.this", "maximum_iops", "1000"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmHardDiskDriveConfig(name string, path string, maximumIops int) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_vhd" "this" {
+	path = "%s"
+	size = 4194304
+}
+
+resource "hyperv_vm_hard_disk_drive" "this" {
+	vm_name             = hyperv_machine_instance.this.name
+	controller_type     = "Scsi"
+	controller_number   = 0
+	controller_location = 0
+	path                = hyperv_vhd.this.path
+	maximum_iops        = %d
+}
+	`, escapeForHcl(name), escapeForHcl(path), maximumIops)
+}
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmIntegrationGuestTimeSyncTimeout   = 1 * time.Minute
+	CreateVmIntegrationGuestTimeSyncTimeout = 2 * time.Minute
+	UpdateVmIntegrationGuestTimeSyncTimeout = 2 * time.Minute
+	DeleteVmIntegrationGuestTimeSyncTimeout = 1 * time.Minute
+)
+
+func resourceHyperVVmIntegrationGuestTimeSync() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to declare when the time synchronization integration service of a virtual machine is enabled. It fights with NTP and the time hierarchy of Active Directory in guests that get their time from elsewhere, so it can be disabled once the guest is domain joined. It manages the `Time Synchronization` integration service of the virtual machine, so do not use it for a `hyperv_machine_instance` unless `integration_services` is in the `ignore_changes` of that machine instance. Destroying the resource leaves the integration service as it is. The ID is the name of the virtual machine.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmIntegrationGuestTimeSyncTimeout),
+			Create: schema.DefaultTimeout(CreateVmIntegrationGuestTimeSyncTimeout),
+			Update: schema.DefaultTimeout(UpdateVmIntegrationGuestTimeSyncTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmIntegrationGuestTimeSyncTimeout),
+		},
+		CreateContext: resourceHyperVVmIntegrationGuestTimeSyncCreate,
+		ReadContext:   resourceHyperVVmIntegrationGuestTimeSyncRead,
+		UpdateContext: resourceHyperVVmIntegrationGuestTimeSyncUpdate,
+		DeleteContext: resourceHyperVVmIntegrationGuestTimeSyncDelete,
+		CustomizeDiff: customizeDiffForVmIntegrationGuestTimeSync,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine whose time synchronization is managed.",
+			},
+			"policy": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VmGuestTimeSyncPolicy_Enabled,
+				ValidateDiagFunc: stringKeyInMap(api.VmGuestTimeSyncPolicy_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies when the time synchronization integration service is enabled. `DisabledWhenDomainJoined` keeps it enabled until the guest reports a fully qualified domain name with a domain through the key value pair exchange integration service, which has to be enabled, and disables it on the next apply after that. Valid values to use are `Enabled`, `Disabled`, `DisabledWhenDomainJoined`.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the time synchronization integration service is enabled. A change of it outside of Terraform is reverted.",
+			},
+			"fully_qualified_domain_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The fully qualified domain name reported by the guest, empty when the guest has not reported one e.g. because it is off.",
+			},
+			"domain_joined": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the fully qualified domain name reported by the guest includes a domain.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the time synchronization integration service as reported by the guest e.g. `OK`, or `No Contact` when the guest does not run the integration services.",
+			},
+		},
+	}
+}
+
+// customizeDiffForVmIntegrationGuestTimeSync plans an update when the time synchronization integration service is not
+// in the state of the policy, either because the policy changed, the guest joined a domain or it was changed outside
+// of Terraform
+func customizeDiffForVmIntegrationGuestTimeSync(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+
+	vmGuestTimeSync := api.VmGuestTimeSync{
+		Policy:                   (diff.Get("policy")).(string),
+		FullyQualifiedDomainName: (diff.Get("fully_qualified_domain_name")).(string),
+	}
+	if (diff.Get("enabled")).(bool) != vmGuestTimeSync.DesiredEnabled() {
+		return diff.SetNewComputed("enabled")
+	}
+
+	return nil
+}
+
+func applyVmIntegrationGuestTimeSync(ctx context.Context, c api.Client, d *schema.ResourceData) error {
+	vmName := (d.Get("vm_name")).(string)
+
+	vmGuestTimeSync, err := c.GetVmGuestTimeSync(ctx, vmName)
+	if err != nil {
+		return err
+	}
+
+	if vmGuestTimeSync.VmName == "" {
+		return fmt.Errorf("[ERROR][hyperv] vm %s of guest time sync does not exist", vmName)
+	}
+
+	vmGuestTimeSync.Policy = (d.Get("policy")).(string)
+	err = vmGuestTimeSync.Validate()
+	if err != nil {
+		return err
+	}
+
+	if vmGuestTimeSync.Enabled == vmGuestTimeSync.DesiredEnabled() {
+		return nil
+	}
+
+	return c.SetVmGuestTimeSyncEnabled(ctx, vmGuestTimeSync.VmName, vmGuestTimeSync.DesiredEnabled())
+}
+
+func resourceHyperVVmIntegrationGuestTimeSyncCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm integration guest time sync: %#v", d)
+	c := meta.(api.Client)
+
+	err := applyVmIntegrationGuestTimeSync(ctx, c, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId((d.Get("vm_name")).(string))
+	log.Printf("[INFO][hyperv][create] created hyperv vm integration guest time sync: %#v", d)
+
+	return resourceHyperVVmIntegrationGuestTimeSyncRead(ctx, d, meta)
+}
+
+func resourceHyperVVmIntegrationGuestTimeSyncRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm integration guest time sync: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := d.Id()
+
+	vmGuestTimeSync, err := c.GetVmGuestTimeSync(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm integration guest time sync: %+v", vmGuestTimeSync)
+
+	if vmGuestTimeSync.VmName == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm integration guest time sync as vm does not exist: %#v", vmName)
+		d.SetId("")
+		return nil
+	}
+
+	// an imported guest time sync has no policy yet
+	policy := (d.Get("policy")).(string)
+	if policy == "" {
+		policy = api.VmGuestTimeSyncPolicy_Enabled
+	}
+
+	if err := d.Set("vm_name", vmGuestTimeSync.VmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("policy", policy); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enabled", vmGuestTimeSync.Enabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("fully_qualified_domain_name", vmGuestTimeSync.FullyQualifiedDomainName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("domain_joined", vmGuestTimeSync.DomainJoined()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("status", vmGuestTimeSync.Status); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm integration guest time sync: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmIntegrationGuestTimeSyncUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm integration guest time sync: %#v", d)
+	c := meta.(api.Client)
+
+	err := applyVmIntegrationGuestTimeSync(ctx, c, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm integration guest time sync: %#v", d)
+
+	return resourceHyperVVmIntegrationGuestTimeSyncRead(ctx, d, meta)
+}
+
+func resourceHyperVVmIntegrationGuestTimeSyncDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm integration guest time sync: %#v", d)
+
+	// the virtual machine keeps its time synchronization integration service as it is, there is nothing to restore it to
+	d.SetId("")
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm integration guest time sync: %#v", d)
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmIntegrationGuestTimeSync(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("timesync")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmIntegrationGuestTimeSyncConfig(name, "Disabled"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_integration_guest_time_sync.this", "policy", "Disabled"),
+					resource.TestCheckResourceAttr("hyperv_vm_integration_guest_time_sync.this", "enabled", "false"),
+				),
+			},
+			{
+				// the vm is off, so the guest has not reported being domain joined
+				Config: testHyperVResourceVmIntegrationGuestTimeSyncConfig(name, "DisabledWhenDomainJoined"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_integration_guest_time_sync.this", "policy", "DisabledWhenDomainJoined"),
+					resource.TestCheckResourceAttr("hyperv_vm_integration_guest_time_sync.this", "domain_joined", "false"),
+					resource.TestCheckResourceAttr("hyperv_vm_integration_guest_time_sync.this", "enabled", "true"),
+				),
+			},
+			{
+				ResourceName:      "hyperv_vm_integration_guest_time_sync.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the policy can not be read back from the virtual machine
+				ImportStateVerifyIgnore: []string{"policy"},
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmIntegrationGuestTimeSyncConfig(name string, policy string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+
+	lifecycle {
+		ignore_changes = [integration_services]
+	}
+}
+
+resource "hyperv_vm_integration_guest_time_sync" "this" {
+	vm_name = hyperv_machine_instance.this.name
+	policy  = "%s"
+}
+	`, escapeForHcl(name), policy)
+}
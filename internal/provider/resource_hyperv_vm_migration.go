@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmMigrationTimeout   = 1 * time.Minute
+	CreateVmMigrationTimeout = 60 * time.Minute
+	DeleteVmMigrationTimeout = 1 * time.Minute
+)
+
+func resourceHyperVVmMigration() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to move a virtual machine's storage to a new path, or to live migrate a virtual machine to another HyperV host. The move is performed when the resource is created, and is performed again if the virtual machine is found to have moved back. Destroying the resource leaves the virtual machine where it is. Live migration to another host from a remote session requires Kerberos constrained delegation or CredSSP to be configured, see `hyperv_host_live_migration_settings`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmMigrationTimeout),
+			Create: schema.DefaultTimeout(CreateVmMigrationTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmMigrationTimeout),
+		},
+		CreateContext: resourceHyperVVmMigrationCreate,
+		ReadContext:   resourceHyperVVmMigrationRead,
+		DeleteContext: resourceHyperVVmMigrationDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine to move.",
+			},
+			"destination_host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				AtLeastOneOf: []string{
+					"destination_host",
+					"destination_storage_path",
+				},
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the HyperV host to live migrate the virtual machine to. During plan the HyperV host machine checks that the destination host is reachable over WinRM.",
+			},
+			"include_storage": {
+				Type:         schema.TypeBool,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      false,
+				RequiredWith: []string{"destination_host", "destination_storage_path"},
+				Description:  "Specifies whether the virtual machine's storage is moved to `destination_storage_path` on the destination host as part of the live migration. Only used when `destination_host` is set.",
+			},
+			"destination_storage_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				AtLeastOneOf: []string{
+					"destination_host",
+					"destination_storage_path",
+				},
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the folder to move the virtual machine's storage to. When `destination_host` is not set the storage is moved on the HyperV host machine. A UNC path e.g. `\\\\server\\share\\VMs` requires the `Kerberos` live migration authentication type with constrained delegation of the `cifs` service of the file server, as `CredSSP` only works when the user is logged on to the HyperV host machine.",
+			},
+			"computer_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the HyperV host the virtual machine was found on.",
+			},
+		},
+		CustomizeDiff: customizeDiffForVmMigration,
+	}
+}
+
+func customizeDiffForVmMigration(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("destination_host") {
+		// destination host will only be known during apply
+		return nil
+	}
+
+	destinationHost := diff.Get("destination_host").(string)
+	if destinationHost == "" || (diff.Id() != "" && !diff.HasChange("destination_host")) {
+		return nil
+	}
+
+	c := meta.(api.Client)
+
+	destination, err := c.TestVmMigrationDestinationHost(ctx, destinationHost)
+	if err != nil {
+		return err
+	}
+
+	if !destination.SourceVirtualMachineMigrationEnabled {
+		return fmt.Errorf("[ERROR][hyperv][plan] Unable to live migrate to %q as live migration is not enabled on the HyperV host machine", destinationHost)
+	}
+
+	if !destination.Reachable {
+		return fmt.Errorf("[ERROR][hyperv][plan] Unable to live migrate to %q as it is not reachable from the HyperV host machine: %s", destinationHost, destination.Error)
+	}
+
+	return nil
+}
+
+func resourceHyperVVmMigrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm migration: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	destinationHost := (d.Get("destination_host")).(string)
+	includeStorage := (d.Get("include_storage")).(bool)
+	destinationStoragePath := (d.Get("destination_storage_path")).(string)
+
+	var err error
+	if destinationHost != "" {
+		err = c.MoveVm(ctx, vmName, destinationHost, includeStorage, destinationStoragePath)
+	} else {
+		err = c.MoveVmStorage(ctx, vmName, destinationStoragePath)
+	}
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmName)
+	log.Printf("[INFO][hyperv][create] created hyperv vm migration: %#v", d)
+
+	return resourceHyperVVmMigrationRead(ctx, d, meta)
+}
+
+func resourceHyperVVmMigrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm migration: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := d.Id()
+	destinationHost := (d.Get("destination_host")).(string)
+	destinationStoragePath := (d.Get("destination_storage_path")).(string)
+
+	status, err := c.GetVmMigrationStatus(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm migration status: %+v", status)
+
+	if destinationHost != "" {
+		if status.Exists {
+			log.Printf("[INFO][hyperv][read] hyperv vm has moved back to the HyperV host machine, so it needs to be migrated again: %#v", vmName)
+			d.SetId("")
+			return nil
+		}
+
+		if err := d.Set("computer_name", destinationHost); err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		if !status.Exists {
+			log.Printf("[INFO][hyperv][read] unable to read hyperv vm migration as vm does not exist: %#v", vmName)
+			d.SetId("")
+			return nil
+		}
+
+		if !strings.EqualFold(strings.TrimRight(status.Path, "\\"), strings.TrimRight(destinationStoragePath, "\\")) {
+			log.Printf("[INFO][hyperv][read] hyperv vm storage is no longer at the destination storage path, so it needs to be moved again: %#v", vmName)
+			d.SetId("")
+			return nil
+		}
+
+		if err := d.Set("computer_name", status.ComputerName); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm migration: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmMigrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm migration: %#v", d)
+
+	// a migration can not be undone, so the virtual machine is left where it is and only removed from the state
+	d.SetId("")
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm migration: %#v", d)
+	return nil
+}
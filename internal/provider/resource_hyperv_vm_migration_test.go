@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmMigration(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	path := testAccPath("migrated")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmMigrationConfig(name, path),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_migration.this", "vm_name", name),
+					resource.TestCheckResourceAttr("hyperv_vm_migration.this", "destination_storage_path", path),
+					resource.TestCheckResourceAttrSet("hyperv_vm_migration.this", "computer_name"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmMigrationConfig(name string, path string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_vm_migration" "this" {
+	vm_name                  = hyperv_machine_instance.this.name
+	destination_storage_path = "%s"
+}
+	`, escapeForHcl(name), escapeForHcl(path))
+}
@@ -0,0 +1,790 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	CreateVmNetworkAdapterTimeout = 5 * time.Minute
+	UpdateVmNetworkAdapterTimeout = 5 * time.Minute
+	DeleteVmNetworkAdapterTimeout = 5 * time.Minute
+)
+
+var vmNetworkAdapterIdFormat = []string{"<vm_name>", "<name>"}
+
+func resourceHyperVVmNetworkAdapter() *schema.Resource {
+	resourceSchema := vmNetworkAdapterSchemaWithoutWaitForIps()
+	resourceSchema["vm_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "Specifies the name of the virtual machine to add the network adapter to.",
+	}
+	resourceSchema["guest_host_name"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateDiagFunc: StringMatch(api.GuestHostNameRegexp, "expected a dns label of letters, digits and hyphens"),
+		DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+		Description:      "Specifies the host name the guest should take e.g. `web-01`, to help a domain join that is orchestrated elsewhere. It is published to the guest with key value pair exchange as `GuestHostName` under `HKLM\\SOFTWARE\\Microsoft\\Virtual Machine\\External`, so the `Key-Value Pair Exchange` integration service must be enabled and the guest has to rename itself e.g. with a script run by the domain join. Key value pairs belong to the virtual machine, so only set it on one network adapter of a virtual machine.",
+	}
+	resourceSchema["guest_dns_suffix"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateDiagFunc: StringMatch(api.GuestDnsSuffixRegexp, "expected a dns name e.g. corp.contoso.com"),
+		DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+		Description:      "Specifies the primary dns suffix the guest should register in dns with e.g. `corp.contoso.com`. It is published to the guest like `guest_host_name`, as `GuestDnsSuffix`.",
+	}
+	resourceSchema["guest_fully_qualified_domain_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The fully qualified domain name reported by the guest through key value pair exchange, when `guest_host_name` or `guest_dns_suffix` is set. It is empty while the guest is off or does not run the integration services.",
+	}
+	resourceSchema["guest_dns_registered"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether `guest_fully_qualified_domain_name` is the name the guest was asked to take with `guest_host_name` and `guest_dns_suffix`, e.g. for a check that waits for a domain join.",
+	}
+
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to manage a single network adapter of a virtual machine. The network adapter is identified by its name, so the names of the network adapters of a virtual machine should be unique. Do not use it for a virtual machine that declares `network_adaptors` in `hyperv_machine_instance`, unless `network_adaptors` is in the `ignore_changes` of that machine instance. It can be imported with an id in the format `<vm_name>|<name>` e.g. `web|wan`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmNetworkAdapterTimeout),
+			Create: schema.DefaultTimeout(CreateVmNetworkAdapterTimeout),
+			Update: schema.DefaultTimeout(UpdateVmNetworkAdapterTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmNetworkAdapterTimeout),
+		},
+		CreateContext: resourceHyperVVmNetworkAdapterCreate,
+		ReadContext:   resourceHyperVVmNetworkAdapterRead,
+		UpdateContext: resourceHyperVVmNetworkAdapterUpdate,
+		DeleteContext: resourceHyperVVmNetworkAdapterDelete,
+		CustomizeDiff: customizeDiffForVmNetworkAdapter,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceHyperVVmNetworkAdapterImport,
+		},
+
+		Schema: resourceSchema,
+	}
+}
+
+// vmNetworkAdapterSchemaWithoutWaitForIps returns the network adapter schema without wait_for_ips, as waiting for ip
+// addresses is done by hyperv_machine_instance when it starts the virtual machine
+func vmNetworkAdapterSchemaWithoutWaitForIps() map[string]*schema.Schema {
+	resourceSchema := vmNetworkAdapterSchema()
+	delete(resourceSchema, "wait_for_ips")
+	return resourceSchema
+}
+
+func getVmNetworkAdapterId(vmName string, name string) string {
+	return getVmDeviceId(vmName, name)
+}
+
+func parseVmNetworkAdapterId(id string) (vmName string, name string, err error) {
+	parts, err := parseVmDeviceId("vm network adapter", id, vmNetworkAdapterIdFormat...)
+	if err != nil {
+		return "", "", err
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func resourceHyperVVmNetworkAdapterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseVmNetworkAdapterId(d.Id()); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// customizeDiffForVmNetworkAdapter replaces the network adapter when its name or type changes, unless it preserves its
+// MAC address on recreate, and checks that the network adapter has MAC address spoofing on when the virtual machine
+// exposes the virtualization extensions of its processor for nested virtualization. The check is done on every plan,
+// as the virtualization extensions can be exposed after the network adapter is created.
+func customizeDiffForVmNetworkAdapter(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() != "" && !(diff.Get("preserve_mac_on_recreate")).(bool) {
+		oldName, newName := diff.GetChange("name")
+		if !strings.EqualFold(oldName.(string), newName.(string)) {
+			if err := diff.ForceNew("name"); err != nil {
+				return err
+			}
+		}
+
+		if diff.HasChange("is_legacy") {
+			if err := diff.ForceNew("is_legacy"); err != nil {
+				return err
+			}
+		}
+	}
+
+	c, ok := meta.(api.Client)
+	if !ok || !diff.NewValueKnown("vm_name") || !diff.NewValueKnown("switch_name") {
+		return nil
+	}
+
+	vmName := (diff.Get("vm_name")).(string)
+	vmExists, err := checkVmNetworkAdapterNestedVirtualization(ctx, c, vmName, api.VmNetworkAdapter{
+		Name:               (diff.Get("name")).(string),
+		SwitchName:         (diff.Get("switch_name")).(string),
+		MacAddressSpoofing: api.ToOnOffState((diff.Get("mac_address_spoofing")).(string)),
+	})
+	if err != nil {
+		return err
+	}
+
+	if !vmExists {
+		log.Printf("[WARN][hyperv][plan] vm %s does not exist yet, so mac_address_spoofing of network adapter %s is checked for nested virtualization when it is created", vmName, (diff.Get("name")).(string))
+	}
+
+	return nil
+}
+
+// checkVmNetworkAdapterNestedVirtualization checks that the network adapter has MAC address spoofing on when the
+// virtual machine exposes the virtualization extensions of its processor for nested virtualization. vmExists is false
+// when the virtual machine does not exist yet, e.g. when it is created in the same plan, so there is nothing to check.
+func checkVmNetworkAdapterNestedVirtualization(ctx context.Context, c api.Client, vmName string, networkAdapter api.VmNetworkAdapter) (vmExists bool, err error) {
+	vm, err := c.GetVm(ctx, vmName)
+	if err != nil {
+		return false, err
+	}
+
+	if vm.Name == "" {
+		return false, nil
+	}
+
+	vmProcessors, err := c.GetVmProcessors(ctx, vmName)
+	if err != nil {
+		return true, err
+	}
+
+	exposeVirtualizationExtensions := false
+	for _, vmProcessor := range vmProcessors {
+		exposeVirtualizationExtensions = exposeVirtualizationExtensions || vmProcessor.ExposeVirtualizationExtensions
+	}
+
+	return true, api.CheckMacAddressSpoofingForNestedVirtualization(exposeVirtualizationExtensions, []api.VmNetworkAdapter{networkAdapter})
+}
+
+// setVmGuestDnsRegistration publishes the host name and dns suffix of the guest, or removes the ones that are no
+// longer set
+func setVmGuestDnsRegistration(ctx context.Context, c api.Client, vmName string, guestDnsRegistration api.GuestDnsRegistration) error {
+	kvpItems := map[string]string{
+		api.GuestHostNameKvpItemName:  guestDnsRegistration.HostName,
+		api.GuestDnsSuffixKvpItemName: guestDnsRegistration.DnsSuffix,
+	}
+
+	for name, value := range kvpItems {
+		var err error
+		if value == "" {
+			err = c.RemoveVmKvpHostItem(ctx, vmName, name)
+		} else {
+			err = c.SetVmKvpHostItem(ctx, vmName, name, value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func expandVmGuestDnsRegistration(d *schema.ResourceData) api.GuestDnsRegistration {
+	return api.GuestDnsRegistration{
+		HostName:  (d.Get("guest_host_name")).(string),
+		DnsSuffix: (d.Get("guest_dns_suffix")).(string),
+	}
+}
+
+func expandVmNetworkAdapterResourceData(d *schema.ResourceData) api.VmNetworkAdapter {
+	networkAdapter := vmDeviceFromResourceData(d, vmNetworkAdapterSchemaWithoutWaitForIps())
+	networkAdapter["wait_for_ips"] = false
+	return api.ExpandNetworkAdapter(networkAdapter)
+}
+
+// getVmNetworkAdapterByName returns the network adapter of the virtual machine with the name, or nil if there is
+// none, as the api addresses network adapters by their index
+func getVmNetworkAdapterByName(ctx context.Context, c api.Client, vmName string, name string) (*api.VmNetworkAdapter, error) {
+	networkAdapters, err := c.GetVmNetworkAdapters(ctx, vmName, []api.VmNetworkAdapterWaitForIp{})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved network adapters: %+v", networkAdapters)
+
+	for _, networkAdapter := range networkAdapters {
+		if strings.EqualFold(networkAdapter.Name, name) {
+			return &networkAdapter, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// createVmNetworkAdapter adds the network adapter to the virtual machine
+func createVmNetworkAdapter(ctx context.Context, c api.Client, vmName string, networkAdapter api.VmNetworkAdapter) error {
+	return c.CreateVmNetworkAdapter(
+		ctx,
+		vmName,
+		networkAdapter.Name,
+		networkAdapter.SwitchName,
+		networkAdapter.ManagementOs,
+		networkAdapter.IsLegacy,
+		networkAdapter.DynamicMacAddress,
+		networkAdapter.StaticMacAddress,
+		networkAdapter.MacAddressSpoofing,
+		networkAdapter.DhcpGuard,
+		networkAdapter.RouterGuard,
+		networkAdapter.PortMirroring,
+		networkAdapter.IeeePriorityTag,
+		networkAdapter.VmqWeight,
+		networkAdapter.IovQueuePairsRequested,
+		networkAdapter.IovInterruptModeration,
+		networkAdapter.IovWeight,
+		networkAdapter.IpsecOffloadMaximumSecurityAssociation,
+		networkAdapter.MaximumBandwidth,
+		networkAdapter.MinimumBandwidthAbsolute,
+		networkAdapter.MinimumBandwidthWeight,
+		networkAdapter.MandatoryFeatureId,
+		networkAdapter.ResourcePoolName,
+		networkAdapter.TestReplicaPoolName,
+		networkAdapter.TestReplicaSwitchName,
+		networkAdapter.VirtualSubnetId,
+		networkAdapter.AllowTeaming,
+		networkAdapter.NotMonitoredInCluster,
+		networkAdapter.StormLimit,
+		networkAdapter.DynamicIpAddressLimit,
+		networkAdapter.DeviceNaming,
+		networkAdapter.FixSpeed10G,
+		networkAdapter.PacketDirectNumProcs,
+		networkAdapter.PacketDirectModerationCount,
+		networkAdapter.PacketDirectModerationInterval,
+		networkAdapter.VrssEnabled,
+		networkAdapter.VmmqEnabled,
+		networkAdapter.VmmqQueuePairs,
+		networkAdapter.VlanAccess,
+		networkAdapter.VlanId,
+	)
+}
+
+func resourceHyperVVmNetworkAdapterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm network adapter: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	networkAdapter := expandVmNetworkAdapterResourceData(d)
+
+	// the virtual machine may not have existed when the network adapter was planned
+	_, err := checkVmNetworkAdapterNestedVirtualization(ctx, c, vmName, networkAdapter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = createVmNetworkAdapter(ctx, c, vmName, networkAdapter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	guestDnsRegistration := expandVmGuestDnsRegistration(d)
+	if guestDnsRegistration.HostName != "" || guestDnsRegistration.DnsSuffix != "" {
+		err = setVmGuestDnsRegistration(ctx, c, vmName, guestDnsRegistration)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(getVmNetworkAdapterId(vmName, networkAdapter.Name))
+	log.Printf("[INFO][hyperv][create] created hyperv vm network adapter: %#v", d)
+
+	return resourceHyperVVmNetworkAdapterRead(ctx, d, meta)
+}
+
+func resourceHyperVVmNetworkAdapterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm network adapter: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, name, err := parseVmNetworkAdapterId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	networkAdapter, err := getVmNetworkAdapterByName(ctx, c, vmName, name)
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		return diag.FromErr(err)
+	}
+
+	if networkAdapter == nil {
+		log.Printf("[INFO][hyperv][read] network adapter %s does not exist", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	flattenedNetworkAdapter := api.FlattenNetworkAdapter(*networkAdapter)
+	delete(flattenedNetworkAdapter, "wait_for_ips")
+
+	if err := d.Set("vm_name", vmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := setVmDeviceResourceData(d, flattenedNetworkAdapter); err != nil {
+		return diag.FromErr(err)
+	}
+
+	kvpHostItems, err := c.GetVmKvpHostItems(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	guestDnsRegistration := api.GuestDnsRegistration{
+		HostName:  kvpHostItems[api.GuestHostNameKvpItemName],
+		DnsSuffix: kvpHostItems[api.GuestDnsSuffixKvpItemName],
+	}
+
+	// the guest is only asked for its name when it was asked to take one
+	fullyQualifiedDomainName := ""
+	if guestDnsRegistration.HostName != "" || guestDnsRegistration.DnsSuffix != "" {
+		vmGuestIntegrationInfo, err := c.GetVmGuestIntegrationInfo(ctx, vmName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		fullyQualifiedDomainName = vmGuestIntegrationInfo.FullyQualifiedDomainName
+	}
+
+	if err := d.Set("guest_host_name", guestDnsRegistration.HostName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("guest_dns_suffix", guestDnsRegistration.DnsSuffix); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("guest_fully_qualified_domain_name", fullyQualifiedDomainName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("guest_dns_registered", guestDnsRegistration.IsRegistered(fullyQualifiedDomainName)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm network adapter: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmNetworkAdapterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm network adapter: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, name, err := parseVmNetworkAdapterId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	currentNetworkAdapter, err := getVmNetworkAdapterByName(ctx, c, vmName, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if currentNetworkAdapter == nil {
+		return diag.Errorf("[ERROR][hyperv] network adapter %s does not exist", d.Id())
+	}
+
+	networkAdapter := expandVmNetworkAdapterResourceData(d)
+
+	// the virtualization extensions of the virtual machine may have been exposed since the network adapter was planned
+	_, err = checkVmNetworkAdapterNestedVirtualization(ctx, c, vmName, networkAdapter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the guest dns registration is published to the virtual machine, so the network adapter is left as it is when
+	// only the guest dns registration changed
+	networkAdapterChanged := d.HasChangesExcept("guest_host_name", "guest_dns_suffix")
+
+	if networkAdapterChanged && d.HasChange("is_legacy") {
+		// the type of a network adapter can not be changed, so it is removed and created again
+		err = c.DeleteVmNetworkAdapter(ctx, vmName, currentNetworkAdapter.Index)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = createVmNetworkAdapter(ctx, c, vmName, api.RecreateNetworkAdapter(*currentNetworkAdapter, networkAdapter))
+	} else if networkAdapterChanged {
+		err = c.UpdateVmNetworkAdapter(
+			ctx,
+			vmName,
+			currentNetworkAdapter.Index,
+			networkAdapter.Name,
+			networkAdapter.SwitchName,
+			networkAdapter.ManagementOs,
+			networkAdapter.IsLegacy,
+			networkAdapter.DynamicMacAddress,
+			networkAdapter.StaticMacAddress,
+			networkAdapter.MacAddressSpoofing,
+			networkAdapter.DhcpGuard,
+			networkAdapter.RouterGuard,
+			networkAdapter.PortMirroring,
+			networkAdapter.IeeePriorityTag,
+			networkAdapter.VmqWeight,
+			networkAdapter.IovQueuePairsRequested,
+			networkAdapter.IovInterruptModeration,
+			networkAdapter.IovWeight,
+			networkAdapter.IpsecOffloadMaximumSecurityAssociation,
+			networkAdapter.MaximumBandwidth,
+			networkAdapter.MinimumBandwidthAbsolute,
+			networkAdapter.MinimumBandwidthWeight,
+			networkAdapter.MandatoryFeatureId,
+			networkAdapter.ResourcePoolName,
+			networkAdapter.TestReplicaPoolName,
+			networkAdapter.TestReplicaSwitchName,
+			networkAdapter.VirtualSubnetId,
+			networkAdapter.AllowTeaming,
+			networkAdapter.NotMonitoredInCluster,
+			networkAdapter.StormLimit,
+			networkAdapter.DynamicIpAddressLimit,
+			networkAdapter.DeviceNaming,
+			networkAdapter.FixSpeed10G,
+			networkAdapter.PacketDirectNumProcs,
+			networkAdapter.PacketDirectModerationCount,
+			networkAdapter.PacketDirectModerationInterval,
+			networkAdapter.VrssEnabled,
+			networkAdapter.VmmqEnabled,
+			networkAdapter.VmmqQueuePairs,
+			networkAdapter.VlanAccess,
+			networkAdapter.VlanId,
+		)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChanges("guest_host_name", "guest_dns_suffix") {
+		err = setVmGuestDnsRegistration(ctx, c, vmName, expandVmGuestDnsRegistration(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// a network adapter that preserves its MAC address on recreate is renamed instead of replaced
+	d.SetId(getVmNetworkAdapterId(vmName, networkAdapter.Name))
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm network adapter: %#v", d)
+
+	return resourceHyperVVmNetworkAdapterRead(ctx, d, meta)
+}
+
+func resourceHyperVVmNetworkAdapterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm network adapter: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, name, err := parseVmNetworkAdapterId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	networkAdapter, err := getVmNetworkAdapterByName(ctx, c, vmName, name)
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		return diag.FromErr(err)
+	}
+
+	if networkAdapter != nil {
+		err = c.DeleteVmNetworkAdapter(ctx, vmName, networkAdapter.Index)
+		if err != nil && !errors.Is(err, api.ErrNotFound) {
+			return diag.FromErr(err)
+		}
+	}
+
+	if (d.Get("guest_host_name")).(string) != "" || (d.Get("guest_dns_suffix")).(string) != "" {
+		err = setVmGuestDnsRegistration(ctx, c, vmName, api.GuestDnsRegistration{})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm network adapter: %#v", d)
+	return nil
+}
+
+func vmNetworkAdapterSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:             schema.TypeString,
+			Required:         true,
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the name for the virtual network adapter.",
+		},
+		"switch_name": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			ForceNew:         false,
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the name of the virtual switch to connect to the new network adapter. If the switch name is not unique, then the operation fails.",
+		},
+		"management_os": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Specifies the virtual network adapter in the management operating system to be configured.",
+		},
+		"is_legacy": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Specifies whether the virtual network adapter is the legacy type. The type of a network adapter can not be changed, so changing it replaces the network adapter, see `preserve_mac_on_recreate`.",
+		},
+		"dynamic_mac_address": {
+			Type:             schema.TypeBool,
+			Optional:         true,
+			Default:          true,
+			DiffSuppressFunc: api.DiffSuppressVmDynamicMacAddress,
+			Description:      "Assigns a dynamically generated MAC address to the virtual network adapter.",
+		},
+		"static_mac_address": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			DiffSuppressFunc: api.DiffSuppressVmStaticMacAddress,
+			Description:      "Assigns a specific a MAC addresss to the virtual network adapter.",
+		},
+		"preserve_mac_on_recreate": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Specifies whether the network adapter keeps its dynamic MAC address when it has to be created again. Linux guests name network interfaces by MAC address, so a new MAC address turns `eth0` into `eth1` and breaks the netplan configuration that refers to it. When `is_legacy` changes, the network adapter is created again with the MAC address it had as its static MAC address, instead of replacing the virtual machine of a `hyperv_machine_instance` or the `hyperv_vm_network_adapter`. When the `name` of a `hyperv_vm_network_adapter` changes, the network adapter is renamed instead of replaced. A network adapter replaced together with its virtual machine always gets a new MAC address.",
+		},
+		"mac_address_spoofing": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.OnOffState_name[api.OnOffState_Off],
+			ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies whether virtual machines may change the source MAC address in outgoing packets to one not assigned to them. On allows the virtual machine to use a different MAC address. Off only allows the virtual machine to use the MAC address assigned to it. Must be `On` for network adapters connected to a switch when the virtual machine exposes virtualization extensions for nested virtualization, as nested virtual machines use their own MAC addresses. Valid values to use are `On`, `Off`.",
+		},
+		"dhcp_guard": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.OnOffState_name[api.OnOffState_Off],
+			ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies whether to drop DHCP messages from a virtual machine claiming to be a DHCP server. Valid values to use are `On`, `Off`.",
+		},
+		"router_guard": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.OnOffState_name[api.OnOffState_Off],
+			ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies whether to drop Router Advertisement and Redirection messages from unauthorized virtual machines. If On is specified, such messages are dropped. If Off is specified, such messages are sent. Valid values to use are `On`, `Off`.",
+		},
+		"port_mirroring": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.PortMirroring_name[api.PortMirroring_None],
+			ValidateDiagFunc: stringKeyInMap(api.PortMirroring_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the port mirroring mode for the network adapter to be configured. If a virtual network adapter is configured as Source, every packet it sends or receives is copied and forwarded to a virtual network adapter configured to receive the packets. If a virtual network adapter is configured as Destination, it receives copied packets from the source virtual network adapter. The source and destination virtual network adapters must be connected to the same virtual switch. Specify None to disable the feature. Valid values to use are `None`, `Source`, `Destination`.",
+		},
+		"ieee_priority_tag": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.OnOffState_name[api.OnOffState_Off],
+			ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies whether IEEE 802.1p tagged packets from the virtual machine should be trusted. If it is on, the IEEE 802.1p tagged packets will be let go as is. If it is off, the priority value is reset to 0. Valid values to use are `On`, `Off`.",
+		},
+		"vmq_weight": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          100,
+			ValidateDiagFunc: IntBetween(0, 100),
+			Description:      "Specifies whether virtual machine queue (VMQ) is to be enabled on the virtual network adapter. The relative weight describes the affinity of the virtual network adapter to use VMQ. Specify 0 to disable VMQ on the virtual network adapter. Valid values to use are between `1` to `100`.",
+		},
+		"iov_queue_pairs_requested": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          1,
+			ValidateDiagFunc: IntBetween(1, 4294967295),
+			Description:      "Specifies the number of hardware queue pairs to be allocated to an SR-IOV virtual function. If receive-side scaling (RSS) is required, and if the physical network adapter that binds to the virtual switch supports RSS on SR-IOV virtual functions, then more than one queue pair is required. Valid values to use are between `1` to `4294967295`.",
+		},
+		"iov_interrupt_moderation": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.IovInterruptModerationValue_name[api.IovInterruptModerationValue_Off],
+			ValidateDiagFunc: stringKeyInMap(api.IovInterruptModerationValue_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the interrupt moderation value for a single-root I/O virtualization (SR-IOV) virtual function assigned to a virtual network adapter. If Default is chosen, the value is determined by the physical network adapter vendor's setting. If Adaptive is chosen, the interrupt moderation rate will be based on the runtime traffic pattern. Valid values to use are `Default`, `Adaptive`, `Off`, `Low `, `Medium`, `High`.",
+		},
+		"iov_weight": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          100,
+			ValidateDiagFunc: IntBetween(0, 100),
+			Description:      "Specifies whether single-root I/O virtualization (SR-IOV) is to be enabled on this virtual network adapter. The relative weight sets the affinity of the virtual network adapter to the assigned SR-IOV virtual function. Specify 0 to disable SR-IOV on the virtual network adapter. Valid values to use are between `0` to `100`.",
+		},
+		"ipsec_offload_maximum_security_association": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     512,
+			Description: "Specifies the maximum number of security associations that can be offloaded to the physical network adapter that is bound to the virtual switch and that supports IPSec Task Offload. Specify zero to disable the feature.",
+		},
+		"maximum_bandwidth": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the maximum bandwidth, in bits per second, for the virtual network adapter. The specified value is rounded to the nearest multiple of eight. Specify zero to disable the feature.",
+		},
+		"minimum_bandwidth_absolute": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the minimum bandwidth, in bits per second, for the virtual network adapter. The specified value is rounded to the nearest multiple of eight. A value larger than 100 Mbps is recommended.",
+		},
+		"minimum_bandwidth_weight": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          0,
+			ValidateDiagFunc: IntBetween(0, 100),
+			Description:      "Specifies the minimum bandwidth, in terms of relative weight, for the virtual network adapter. The weight describes how much bandwidth to provide to the virtual network adapter relative to other virtual network adapters connected to the same virtual switch. Specify 0 to disable the feature. Valid values to use are between `0` to `100`.",
+		},
+		"mandatory_feature_id": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+			Description: "Specifies the unique identifiers of the virtual switch extension features that are required for this virtual network adapter to operate.",
+		},
+		"resource_pool_name": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies the name of the resource pool.",
+		},
+		"test_replica_pool_name": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "This parameter applies only to virtual machines that are enabled for replication. It specifies the name of the network resource pool that will be used by this virtual network adapter when its virtual machine is created during a test failover.",
+		},
+		"test_replica_switch_name": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "",
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "This parameter applies only to virtual machines that are enabled for replication. It specifies the name of the virtual switch to which the virtual network adapter should be connected when its virtual machine is created during a test failover.",
+		},
+		"virtual_subnet_id": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          0,
+			ValidateDiagFunc: ValueOrIntBetween(0, 4096, 16777215),
+			Description:      "Specifies the virtual subnet ID to use with Hyper-V Network Virtualization. Use 0 to clear this parameter. Valid values to use are `0` or between `4096` to `16777215` (2^24 - 1).",
+		},
+		"allow_teaming": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.OnOffState_name[api.OnOffState_On],
+			ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies whether the virtual network adapter can be teamed with other network adapters connected to the same virtual switch. Valid values to use are `On`, `Off`.",
+		},
+		"not_monitored_in_cluster": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Indicates whether to not monitor the network adapter if the virtual machine that it belongs to is part of a cluster. By default, network adapters for clustered virtual machines are monitored.",
+		},
+		"storm_limit": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the number of broadcast, multicast, and unknown unicast packets per second a virtual machine is allowed to send through the specified virtual network adapter. Broadcast, multicast, and unknown unicast packets beyond the limit during that one second interval are dropped. A value of zero (0) means there is no limit.",
+		},
+		"dynamic_ip_address_limit": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the dynamic IP address limit.",
+		},
+		"device_naming": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.OnOffState_name[api.OnOffState_Off],
+			ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies whether this adapter uses device naming. Valid values to use are `On`, `Off`.",
+		},
+		"fix_speed_10g": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          api.OnOffState_name[api.OnOffState_Off],
+			ValidateDiagFunc: stringKeyInMap(api.OnOffState_value, true),
+			DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+			Description:      "Specifies whether the adapter uses fix speed of 10G. Valid values to use are `On`, `Off`.",
+		},
+		"packet_direct_num_procs": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the number of processors to use for virtual switch processing inside of the host.",
+		},
+		"packet_direct_moderation_count": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the number of packets to wait for before signaling an interrupt.",
+		},
+		"packet_direct_moderation_interval": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "Specifies the amount of time, in milliseconds, to wait before signaling an interrupt after a packet arrives.",
+		},
+		"vrss_enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Should Virtual Receive Side Scaling be enabled. This configuration allows the load from a virtual network adapter to be distributed across multiple virtual processors in a virtual machine (VM), allowing the VM to process more network traffic more rapidly than it can with a single logical processor.",
+		},
+		"vmmq_enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Should Virtual Machine Multi-Queue be enabled. With set to true multiple queues are allocated to a single VM with each queue affinitized to a core in the VM.",
+		},
+		"vmmq_queue_pairs": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     16,
+			Description: "The number of Virtual Machine Multi-Queues to create for this VM.",
+		},
+		"vlan_access": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "",
+		},
+		"vlan_id": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     0,
+			Description: "",
+		},
+		"wait_for_ips": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Wait for the network card to be assigned an ip address.",
+		},
+		"ip_addresses": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "The current list of IP addresses on this machine. If HyperV integration tools is not running on the virtual machine, or if the VM is powered off, or has not been assigned an ip address, this list will be empty.",
+		},
+	}
+}
@@ -0,0 +1,121 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmNetworkAdapter(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmNetworkAdapterConfig(name, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "vm_name", name),
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "name", "wan"),
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "switch_name", name),
+				),
+			},
+			{
+				Config: testHyperVResourceVmNetworkAdapterConfig(name, 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "vlan_id", "10"),
+				),
+			},
+		},
+	})
+}
+
+func TestHyperVResourceVmNetworkAdapterGuestDnsRegistration(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmNetworkAdapterGuestDnsRegistrationConfig(name, "web01"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "guest_host_name", "web01"),
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "guest_dns_suffix", "corp.example.com"),
+					// the virtual machine is off, so the guest can not have registered the name
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "guest_fully_qualified_domain_name", ""),
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "guest_dns_registered", "false"),
+				),
+			},
+			{
+				Config: testHyperVResourceVmNetworkAdapterGuestDnsRegistrationConfig(name, "web02"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_network_adapter.this", "guest_host_name", "web02"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmNetworkAdapterConfig(name string, vlanId int) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_network_switch" "this" {
+	name        = "%s"
+	switch_type = "Internal"
+}
+
+resource "hyperv_vm_network_adapter" "this" {
+	vm_name     = hyperv_machine_instance.this.name
+	name        = "wan"
+	switch_name = hyperv_network_switch.this.name
+	vlan_access = %t
+	vlan_id     = %d
+}
+	`, escapeForHcl(name), escapeForHcl(name), vlanId > 0, vlanId)
+}
+
+func testHyperVResourceVmNetworkAdapterGuestDnsRegistrationConfig(name string, guestHostName string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_network_switch" "this" {
+	name        = "%s"
+	switch_type = "Internal"
+}
+
+resource "hyperv_vm_network_adapter" "this" {
+	vm_name          = hyperv_machine_instance.this.name
+	name             = "wan"
+	switch_name      = hyperv_network_switch.this.name
+	guest_host_name  = "%s"
+	guest_dns_suffix = "corp.example.com"
+}
+	`, escapeForHcl(name), escapeForHcl(name), escapeForHcl(guestHostName))
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmProcessorPinningTimeout   = 1 * time.Minute
+	CreateVmProcessorPinningTimeout = 2 * time.Minute
+	UpdateVmProcessorPinningTimeout = 2 * time.Minute
+	DeleteVmProcessorPinningTimeout = 2 * time.Minute
+)
+
+const DefaultCpuGroupsPath = "CpuGroups.exe"
+
+func resourceHyperVVmProcessorPinning() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to pin the virtual processors of a virtual machine to logical processors of the HyperV host machine, e.g. to isolate a real-time workload on cores that no other virtual machine uses. The virtual machine is put in a cpu group of its own that is managed with `CpuGroups.exe`, which has to be downloaded from Microsoft to the HyperV host machine. Cpu groups are only available on Windows Server with the core or classic scheduler of the hypervisor, which is checked before the virtual machine is pinned. The virtual machine must be off when it is pinned to other logical processors or unpinned, a change to `cpu_cap_percent` is applied while it runs. The ID is the name of the virtual machine.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmProcessorPinningTimeout),
+			Create: schema.DefaultTimeout(CreateVmProcessorPinningTimeout),
+			Update: schema.DefaultTimeout(UpdateVmProcessorPinningTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmProcessorPinningTimeout),
+		},
+		CreateContext: resourceHyperVVmProcessorPinningCreate,
+		ReadContext:   resourceHyperVVmProcessorPinningRead,
+		UpdateContext: resourceHyperVVmProcessorPinningUpdate,
+		DeleteContext: resourceHyperVVmProcessorPinningDelete,
+		CustomizeDiff: customizeDiffForVmProcessorPinning,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine whose virtual processors are pinned.",
+			},
+			"logical_processors": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:             schema.TypeInt,
+					ValidateDiagFunc: IntBetween(0, 2047),
+				},
+				Description: "Specifies the indexes of the logical processors of the HyperV host machine the virtual processors run on e.g. `[2, 3]`. Logical processors are numbered node by node, so `logical_processors_per_node` of the `hyperv_host_numa_topology` data source helps to pick ones of the same NUMA node. The virtual machine can have more virtual processors than logical processors, they then share them.",
+			},
+			"cpu_cap_percent": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          100,
+				ValidateDiagFunc: IntBetween(1, 100),
+				Description:      "Specifies the percentage of the capacity of `logical_processors` the virtual processors can use together.",
+			},
+			"cpu_groups_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     DefaultCpuGroupsPath,
+				Description: "Specifies the path of `CpuGroups.exe` on the HyperV host machine. By default it is looked up in the path.",
+			},
+			"cpu_group_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the cpu group the virtual machine is in. A virtual machine that was moved to another cpu group outside of Terraform is moved back.",
+			},
+		},
+	}
+}
+
+// customizeDiffForVmProcessorPinning plans an update when the virtual machine is not in the cpu group of its logical
+// processors, either because they changed or because the virtual machine was moved out of it
+func customizeDiffForVmProcessorPinning(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if diff.Id() == "" {
+		return nil
+	}
+
+	vmProcessorPinning := api.VmProcessorPinning{
+		VmName:            (diff.Get("vm_name")).(string),
+		LogicalProcessors: expandVmProcessorPinningLogicalProcessors((diff.Get("logical_processors")).(*schema.Set)),
+	}
+	if (diff.Get("cpu_group_id")).(string) != vmProcessorPinning.DesiredCpuGroupId() {
+		return diff.SetNewComputed("cpu_group_id")
+	}
+
+	return nil
+}
+
+func expandVmProcessorPinningLogicalProcessors(logicalProcessorSet *schema.Set) []int {
+	logicalProcessors := make([]int, 0)
+	for _, logicalProcessor := range logicalProcessorSet.List() {
+		logicalProcessors = append(logicalProcessors, logicalProcessor.(int))
+	}
+
+	return logicalProcessors
+}
+
+func expandVmProcessorPinning(ctx context.Context, c api.Client, d *schema.ResourceData) (api.VmProcessorPinning, error) {
+	vmProcessorPinning := api.VmProcessorPinning{
+		VmName:            (d.Get("vm_name")).(string),
+		LogicalProcessors: expandVmProcessorPinningLogicalProcessors((d.Get("logical_processors")).(*schema.Set)),
+		CpuCapPercent:     (d.Get("cpu_cap_percent")).(int),
+		CpuGroupsPath:     (d.Get("cpu_groups_path")).(string),
+	}
+
+	err := c.RequireCpuGroups(ctx)
+	if err != nil {
+		return vmProcessorPinning, err
+	}
+
+	hostCapabilities, err := c.GetHostCapabilities(ctx)
+	if err != nil {
+		return vmProcessorPinning, err
+	}
+
+	return vmProcessorPinning, vmProcessorPinning.Validate(hostCapabilities.LogicalProcessorCount)
+}
+
+func resourceHyperVVmProcessorPinningCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm processor pinning: %#v", d)
+	c := meta.(api.Client)
+
+	vmProcessorPinning, err := expandVmProcessorPinning(ctx, c, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmProcessorPinning(ctx, vmProcessorPinning.VmName, vmProcessorPinning.CpuGroupsPath)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", vmProcessorPinning.VmName, err))
+		}
+
+		if existing.VmName != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", existing.VmName, "hyperv_vm_processor_pinning", "hyperv_vm_processor_pinning", existing.VmName))
+		}
+	}
+
+	err = c.CreateOrUpdateVmProcessorPinning(ctx, vmProcessorPinning)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmProcessorPinning.VmName)
+	log.Printf("[INFO][hyperv][create] created hyperv vm processor pinning: %#v", d)
+
+	return resourceHyperVVmProcessorPinningRead(ctx, d, meta)
+}
+
+func resourceHyperVVmProcessorPinningRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm processor pinning: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := d.Id()
+
+	// an imported processor pinning has no cpu groups path yet
+	cpuGroupsPath := (d.Get("cpu_groups_path")).(string)
+	if cpuGroupsPath == "" {
+		cpuGroupsPath = DefaultCpuGroupsPath
+	}
+
+	vmProcessorPinning, err := c.GetVmProcessorPinning(ctx, vmName, cpuGroupsPath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm processor pinning: %+v", vmProcessorPinning)
+
+	if vmProcessorPinning.VmName == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm processor pinning as it does not exist: %#v", vmName)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("vm_name", vmProcessorPinning.VmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cpu_groups_path", cpuGroupsPath); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("logical_processors", vmProcessorPinning.LogicalProcessors); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cpu_cap_percent", vmProcessorPinning.CpuCapPercent); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cpu_group_id", vmProcessorPinning.CpuGroupId); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm processor pinning: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmProcessorPinningUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm processor pinning: %#v", d)
+	c := meta.(api.Client)
+
+	vmProcessorPinning, err := expandVmProcessorPinning(ctx, c, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.CreateOrUpdateVmProcessorPinning(ctx, vmProcessorPinning)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm processor pinning: %#v", d)
+
+	return resourceHyperVVmProcessorPinningRead(ctx, d, meta)
+}
+
+func resourceHyperVVmProcessorPinningDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm processor pinning: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteVmProcessorPinning(ctx, d.Id(), (d.Get("cpu_groups_path")).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm processor pinning: %#v", d)
+	return nil
+}
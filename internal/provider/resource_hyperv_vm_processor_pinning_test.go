@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmProcessorPinning(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("pinning")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmProcessorPinningConfig(name, "0, 1", 100),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_processor_pinning.this", "logical_processors.#", "2"),
+					resource.TestCheckResourceAttrSet("hyperv_vm_processor_pinning.this", "cpu_group_id"),
+				),
+			},
+			{
+				Config: testHyperVResourceVmProcessorPinningConfig(name, "1", 50),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_processor_pinning.this", "logical_processors.#", "1"),
+					resource.TestCheckResourceAttr("hyperv_vm_processor_pinning.this", "cpu_cap_percent", "50"),
+				),
+			},
+			{
+				ResourceName:      "hyperv_vm_processor_pinning.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmProcessorPinningConfig(name string, logicalProcessors string, cpuCapPercent int) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_vm_processor_pinning" "this" {
+	vm_name            = hyperv_machine_instance.this.name
+	logical_processors = [%s]
+	cpu_cap_percent    = %d
+}
+	`, escapeForHcl(name), logicalProcessors, cpuCapPercent)
+}
@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmReplicationTimeout   = 1 * time.Minute
+	CreateVmReplicationTimeout = 5 * time.Minute
+	UpdateVmReplicationTimeout = 5 * time.Minute
+	DeleteVmReplicationTimeout = 2 * time.Minute
+)
+
+func resourceHyperVVmReplication() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to replicate a virtual machine to a Hyper-V Replica server. The replica server must be configured to accept replication, see `hyperv_host_replication_settings`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmReplicationTimeout),
+			Create: schema.DefaultTimeout(CreateVmReplicationTimeout),
+			Update: schema.DefaultTimeout(UpdateVmReplicationTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmReplicationTimeout),
+		},
+		CreateContext: resourceHyperVVmReplicationCreate,
+		ReadContext:   resourceHyperVVmReplicationRead,
+		UpdateContext: resourceHyperVVmReplicationUpdate,
+		DeleteContext: resourceHyperVVmReplicationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine to replicate.",
+			},
+			"replica_server_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the replica server that the virtual machine will be replicated to.",
+			},
+			"replica_server_port": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          80,
+				ValidateDiagFunc: IntBetween(1, 65535),
+				Description:      "Specifies the port on the replica server to use for replication traffic. This must match the port the replica server is listening on for the `authentication_type` used.",
+			},
+			"authentication_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VMReplicationAuthenticationType_name[api.VMReplicationAuthenticationType_Kerberos],
+				ValidateDiagFunc: stringKeyInMap(api.VMReplicationAuthenticationType_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the authentication type to use for replication. Valid values to use are `Kerberos`, `Certificate`.",
+			},
+			"certificate_thumbprint": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the thumbprint of the certificate to use for mutual authentication of the replication traffic. Required when `authentication_type` is `Certificate`.",
+			},
+			"replication_frequency_sec": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          300,
+				ValidateDiagFunc: IntInSlice([]int{30, 300, 900}),
+				Description:      "Specifies the frequency, in seconds, at which Hyper-V replicates changes to the replica server. Valid values to use are `30`, `300`, `900`.",
+			},
+			"recovery_history": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, 24),
+				Description:      "Should be a value between `0` and `24`. Specifies the number of additional recovery points to store on the replica server. Use `0` to only keep the latest recovery point.",
+			},
+			"vss_snapshot_frequency_hour": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: IntBetween(0, 12),
+				Description:      "Should be a value between `0` and `12`. Specifies the frequency, in hours, at which Volume Shadow Copy Service (VSS) snapshots are replicated. Can only be set when `recovery_history` is greater than `0`. Use `0` to disable application consistent recovery points.",
+			},
+			"compression_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether replication traffic is compressed.",
+			},
+			"initial_replication_start_time": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "Specifies when the initial replication over the network starts, in RFC 3339 format e.g. `2024-01-01T23:00:00Z`. When not set the initial replication starts immediately.",
+			},
+			"primary_server_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the primary server of the replication.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the replication e.g. `Replicating`, `WaitingForStartResynchronize`.",
+			},
+			"health": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The health of the replication e.g. `Normal`, `Warning`, `Critical`.",
+			},
+		},
+	}
+}
+
+func resourceHyperVVmReplicationValidate(operation string, authenticationType api.VMReplicationAuthenticationType, certificateThumbprint string, recoveryHistory int, vssSnapshotFrequencyHour int) diag.Diagnostics {
+	if authenticationType == api.VMReplicationAuthenticationType_Certificate && certificateThumbprint == "" {
+		return diag.Errorf("[ERROR][hyperv][%s] Must specify CertificateThumbprint if AuthenticationType is certificate", operation)
+	}
+
+	if recoveryHistory == 0 && vssSnapshotFrequencyHour != 0 {
+		return diag.Errorf("[ERROR][hyperv][%s] Unable to set VssSnapshotFrequencyHour unless RecoveryHistory is greater than 0", operation)
+	}
+
+	return nil
+}
+
+func resourceHyperVVmReplicationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm replication: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := (d.Get("vm_name")).(string)
+	replicaServerName := (d.Get("replica_server_name")).(string)
+	replicaServerPort := (d.Get("replica_server_port")).(int)
+	authenticationType := api.ToVMReplicationAuthenticationType((d.Get("authentication_type")).(string))
+	certificateThumbprint := (d.Get("certificate_thumbprint")).(string)
+	replicationFrequencySec := (d.Get("replication_frequency_sec")).(int)
+	recoveryHistory := (d.Get("recovery_history")).(int)
+	vssSnapshotFrequencyHour := (d.Get("vss_snapshot_frequency_hour")).(int)
+	compressionEnabled := (d.Get("compression_enabled")).(bool)
+	initialReplicationStartTime := (d.Get("initial_replication_start_time")).(string)
+
+	if diags := resourceHyperVVmReplicationValidate("create", authenticationType, certificateThumbprint, recoveryHistory, vssSnapshotFrequencyHour); diags.HasError() {
+		return diags
+	}
+
+	if initialReplicationStartTime != "" {
+		if _, err := time.Parse(time.RFC3339, initialReplicationStartTime); err != nil {
+			return diag.Errorf("[ERROR][hyperv][create] InitialReplicationStartTime must be in RFC 3339 format: %s", err)
+		}
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetVMReplication(ctx, vmName)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", vmName, err))
+		}
+
+		if existing.ReplicaServerName != "" {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", vmName, "hyperv_vm_replication", "hyperv_vm_replication", vmName))
+		}
+	}
+
+	err := c.CreateVMReplication(ctx, vmName, replicaServerName, replicaServerPort, authenticationType, certificateThumbprint, replicationFrequencySec, recoveryHistory, vssSnapshotFrequencyHour, compressionEnabled, initialReplicationStartTime)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(vmName)
+	log.Printf("[INFO][hyperv][create] created hyperv vm replication: %#v", d)
+
+	return resourceHyperVVmReplicationRead(ctx, d, meta)
+}
+
+func resourceHyperVVmReplicationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm replication: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := d.Id()
+
+	vmReplication, err := c.GetVMReplication(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm replication: %+v", vmReplication)
+
+	if vmReplication.ReplicaServerName == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm replication as it does not exist: %#v", vmName)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("vm_name", vmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("replica_server_name", vmReplication.ReplicaServerName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("replica_server_port", vmReplication.ReplicaServerPort); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("authentication_type", vmReplication.AuthenticationType.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("certificate_thumbprint", vmReplication.CertificateThumbprint); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("replication_frequency_sec", vmReplication.ReplicationFrequencySec); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("recovery_history", vmReplication.RecoveryHistory); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vss_snapshot_frequency_hour", vmReplication.VssSnapshotFrequencyHour); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("compression_enabled", vmReplication.CompressionEnabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("primary_server_name", vmReplication.PrimaryServerName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("state", vmReplication.State); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("health", vmReplication.Health); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm replication: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmReplicationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm replication: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := d.Id()
+	replicaServerName := (d.Get("replica_server_name")).(string)
+	replicaServerPort := (d.Get("replica_server_port")).(int)
+	authenticationType := api.ToVMReplicationAuthenticationType((d.Get("authentication_type")).(string))
+	certificateThumbprint := (d.Get("certificate_thumbprint")).(string)
+	replicationFrequencySec := (d.Get("replication_frequency_sec")).(int)
+	recoveryHistory := (d.Get("recovery_history")).(int)
+	vssSnapshotFrequencyHour := (d.Get("vss_snapshot_frequency_hour")).(int)
+	compressionEnabled := (d.Get("compression_enabled")).(bool)
+
+	if diags := resourceHyperVVmReplicationValidate("update", authenticationType, certificateThumbprint, recoveryHistory, vssSnapshotFrequencyHour); diags.HasError() {
+		return diags
+	}
+
+	err := c.UpdateVMReplication(ctx, vmName, replicaServerName, replicaServerPort, authenticationType, certificateThumbprint, replicationFrequencySec, recoveryHistory, vssSnapshotFrequencyHour, compressionEnabled)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm replication: %#v", d)
+
+	return resourceHyperVVmReplicationRead(ctx, d, meta)
+}
+
+func resourceHyperVVmReplicationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm replication: %#v", d)
+	c := meta.(api.Client)
+
+	vmName := d.Id()
+
+	err := c.DeleteVMReplication(ctx, vmName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm replication: %#v", d)
+	return nil
+}
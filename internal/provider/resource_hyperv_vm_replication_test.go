@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmReplication(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	replicaServerName := os.Getenv("HYPERV_TEST_REPLICA_SERVER")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_REPLICA_SERVER")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmReplicationConfig(name, replicaServerName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_replication.this", "vm_name", name),
+					resource.TestCheckResourceAttr("hyperv_vm_replication.this", "replica_server_name", replicaServerName),
+					resource.TestCheckResourceAttrSet("hyperv_vm_replication.this", "state"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmReplicationConfig(name string, replicaServerName string) string {
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_vm_replication" "this" {
+	vm_name             = hyperv_machine_instance.this.name
+	replica_server_name = "%s"
+	authentication_type = "Kerberos"
+}
+	`, escapeForHcl(name), escapeForHcl(replicaServerName))
+}
@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmScheduledTaskTimeout   = 1 * time.Minute
+	CreateVmScheduledTaskTimeout = 2 * time.Minute
+	UpdateVmScheduledTaskTimeout = 2 * time.Minute
+	DeleteVmScheduledTaskTimeout = 2 * time.Minute
+)
+
+var vmScheduledTaskIdFormat = []string{"<vm_name>", "<name>"}
+
+// vmScheduledTaskNameRegexp keeps the name usable as the prefix of the names of the checkpoints taken by the task
+var vmScheduledTaskNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+var vmScheduledTaskTimeRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+func resourceHyperVVmScheduledTask() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to register a scheduled task on the HyperV host machine that runs an action against a virtual machine, e.g. a nightly checkpoint or a weekly `Optimize-VHD`. The task runs as `SYSTEM` in the `\\terraform-provider-hyperv\\` folder of the task scheduler, and its definition is kept in `%ProgramData%\\terraform-provider-hyperv\\vm-scheduled-tasks`, which only Administrators and SYSTEM can write to. Files in it that are owned by anyone else are refused. A task that is unregistered outside of Terraform is registered again. Destroying the resource unregisters the task, checkpoints taken by it are kept. It can be imported with an id in the format `<vm_name>|<name>` e.g. `web|nightly-checkpoint`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmScheduledTaskTimeout),
+			Create: schema.DefaultTimeout(CreateVmScheduledTaskTimeout),
+			Update: schema.DefaultTimeout(UpdateVmScheduledTaskTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmScheduledTaskTimeout),
+		},
+		CreateContext: resourceHyperVVmScheduledTaskCreate,
+		ReadContext:   resourceHyperVVmScheduledTaskRead,
+		UpdateContext: resourceHyperVVmScheduledTaskUpdate,
+		DeleteContext: resourceHyperVVmScheduledTaskDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceHyperVVmScheduledTaskImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine the task runs against.",
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: StringMatch(vmScheduledTaskNameRegexp, "expected a name of letters, digits, underscores, hyphens and periods"),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the task, it must be unique for the virtual machine. Checkpoints taken by the task are named after it, followed by the time they were taken e.g. `nightly-checkpoint-20240131020000`.",
+			},
+			"action": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: stringKeyInMap(api.VmScheduledTaskAction_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies what the task does. Valid values to use are `Checkpoint` to take a checkpoint, `OptimizeVhd` to compact the virtual hard disks of the virtual machine while it is off, `Start` to start the virtual machine, `Stop` to shut it down and `Script` to run `script`.",
+			},
+			"script": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies the body of the PowerShell script block run by a task with the `Script` action. It is called with the name of the virtual machine as `-VmName`, so it should start with `param($VmName)`.",
+			},
+			"checkpoint_retention": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          7,
+				ValidateDiagFunc: IntBetween(1, 50),
+				Description:      "Specifies how many of the checkpoints taken by a task with the `Checkpoint` action are kept. Older ones are removed after a checkpoint is taken. Hyper-V allows at most 50 checkpoints per virtual machine.",
+			},
+			"frequency": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          api.VmScheduledTaskFrequency_name[api.VmScheduledTaskFrequency_Daily],
+				ValidateDiagFunc: stringKeyInMap(api.VmScheduledTaskFrequency_value, true),
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies when the task runs. Valid values to use are `Daily`, `Weekly` on `days_of_week` and `AtStartup` of the HyperV host machine.",
+			},
+			"at": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "02:00",
+				ValidateDiagFunc: StringMatch(vmScheduledTaskTimeRegexp, "expected a time in HH:mm format"),
+				Description:      "Specifies the local time of the HyperV host machine the task runs at, in 24 hour `HH:mm` format. It is ignored when `frequency` is `AtStartup`.",
+			},
+			"days_of_week": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: stringKeyInMap(api.DaysOfWeek_value, true),
+				},
+				Description: "Specifies the days a `Weekly` task runs on e.g. `Saturday`.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether the task runs. A disabled task stays registered.",
+			},
+			"last_run_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The UTC time the task last ran in RFC 3339 format, empty when it has not run yet.",
+			},
+			"next_run_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The UTC time the task runs next in RFC 3339 format, empty when it is disabled or runs at startup.",
+			},
+			"last_task_result": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The result code of the last run of the task, `0` when it succeeded.",
+			},
+		},
+	}
+}
+
+func getVmScheduledTaskId(vmName string, name string) string {
+	return getVmDeviceId(vmName, name)
+}
+
+func parseVmScheduledTaskId(id string) (vmName string, name string, err error) {
+	parts, err := parseVmDeviceId("vm scheduled task", id, vmScheduledTaskIdFormat...)
+	if err != nil {
+		return "", "", err
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func resourceHyperVVmScheduledTaskImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	vmName, name, err := parseVmScheduledTaskId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(getVmScheduledTaskId(vmName, name))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func expandVmScheduledTask(d *schema.ResourceData) (api.VmScheduledTask, error) {
+	daysOfWeek := make([]string, 0)
+	for _, dayOfWeek := range (d.Get("days_of_week")).(*schema.Set).List() {
+		daysOfWeek = append(daysOfWeek, dayOfWeek.(string))
+	}
+
+	vmScheduledTask := api.VmScheduledTask{
+		VmName:              (d.Get("vm_name")).(string),
+		Name:                (d.Get("name")).(string),
+		Action:              api.ToVmScheduledTaskAction((d.Get("action")).(string)),
+		Script:              (d.Get("script")).(string),
+		CheckpointRetention: int32((d.Get("checkpoint_retention")).(int)),
+		Frequency:           api.ToVmScheduledTaskFrequency((d.Get("frequency")).(string)),
+		At:                  (d.Get("at")).(string),
+		DaysOfWeek:          daysOfWeek,
+		Enabled:             (d.Get("enabled")).(bool),
+	}
+
+	return vmScheduledTask, vmScheduledTask.Validate()
+}
+
+func resourceHyperVVmScheduledTaskCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm scheduled task: %#v", d)
+	c := meta.(api.Client)
+
+	vmScheduledTask, err := expandVmScheduledTask(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmScheduledTask(ctx, vmScheduledTask.VmName, vmScheduledTask.Name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", vmScheduledTask.Name, err))
+		}
+
+		if existing.Name != "" {
+			id := getVmScheduledTaskId(vmScheduledTask.VmName, vmScheduledTask.Name)
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", id, "hyperv_vm_scheduled_task", "hyperv_vm_scheduled_task", id))
+		}
+	}
+
+	err = c.CreateOrUpdateVmScheduledTask(ctx, vmScheduledTask)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getVmScheduledTaskId(vmScheduledTask.VmName, vmScheduledTask.Name))
+	log.Printf("[INFO][hyperv][create] created hyperv vm scheduled task: %#v", d)
+
+	return resourceHyperVVmScheduledTaskRead(ctx, d, meta)
+}
+
+func resourceHyperVVmScheduledTaskRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm scheduled task: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, name, err := parseVmScheduledTaskId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	vmScheduledTask, err := c.GetVmScheduledTask(ctx, vmName, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm scheduled task: %+v", vmScheduledTask)
+
+	if vmScheduledTask.Name == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm scheduled task as it does not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("vm_name", vmScheduledTask.VmName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("name", vmScheduledTask.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("action", vmScheduledTask.Action.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("script", vmScheduledTask.Script); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("checkpoint_retention", vmScheduledTask.CheckpointRetention); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("frequency", vmScheduledTask.Frequency.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("at", vmScheduledTask.At); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("days_of_week", vmScheduledTask.DaysOfWeek); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enabled", vmScheduledTask.Enabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("last_run_time", vmScheduledTask.LastRunTime); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("next_run_time", vmScheduledTask.NextRunTime); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("last_task_result", vmScheduledTask.LastTaskResult); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm scheduled task: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmScheduledTaskUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm scheduled task: %#v", d)
+	c := meta.(api.Client)
+
+	vmScheduledTask, err := expandVmScheduledTask(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.CreateOrUpdateVmScheduledTask(ctx, vmScheduledTask)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm scheduled task: %#v", d)
+
+	return resourceHyperVVmScheduledTaskRead(ctx, d, meta)
+}
+
+func resourceHyperVVmScheduledTaskDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm scheduled task: %#v", d)
+	c := meta.(api.Client)
+
+	vmName, name, err := parseVmScheduledTaskId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = c.DeleteVmScheduledTask(ctx, vmName, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm scheduled task: %#v", d)
+	return nil
+}
@@ -0,0 +1,75 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVmScheduledTask(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("task")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVMachineInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmScheduledTaskConfig(name, "Daily", "02:00"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_scheduled_task.this", "action", "Checkpoint"),
+					resource.TestCheckResourceAttr("hyperv_vm_scheduled_task.this", "frequency", "Daily"),
+					resource.TestCheckResourceAttr("hyperv_vm_scheduled_task.this", "enabled", "true"),
+					resource.TestCheckResourceAttrSet("hyperv_vm_scheduled_task.this", "next_run_time"),
+				),
+			},
+			{
+				Config: testHyperVResourceVmScheduledTaskConfig(name, "Weekly", "04:30"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_scheduled_task.this", "frequency", "Weekly"),
+					resource.TestCheckResourceAttr("hyperv_vm_scheduled_task.this", "at", "04:30"),
+					resource.TestCheckResourceAttr("hyperv_vm_scheduled_task.this", "days_of_week.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "hyperv_vm_scheduled_task.this",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testHyperVResourceVmScheduledTaskConfig(name string, frequency string, at string) string {
+	daysOfWeek := ""
+	if frequency == "Weekly" {
+		daysOfWeek = `days_of_week = ["Sunday"]`
+	}
+
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+
+resource "hyperv_vm_scheduled_task" "this" {
+	vm_name              = hyperv_machine_instance.this.name
+	name                 = "nightly-checkpoint"
+	action               = "Checkpoint"
+	checkpoint_retention = 3
+	frequency            = "%s"
+	at                   = "%s"
+	%s
+}
+	`, escapeForHcl(name), frequency, at, daysOfWeek)
+}
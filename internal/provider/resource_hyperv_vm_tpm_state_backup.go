@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmTpmStateBackupTimeout   = 1 * time.Minute
+	CreateVmTpmStateBackupTimeout = 5 * time.Minute
+	UpdateVmTpmStateBackupTimeout = 5 * time.Minute
+	DeleteVmTpmStateBackupTimeout = 2 * time.Minute
+)
+
+func resourceHyperVVmTpmStateBackup() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to back up the key protector of a virtual machine with a virtual TPM, so that the virtual machine can still be started after it is migrated or restored to another HyperV host machine, or after the host is reinstalled. The key protector is exported to a file, and the certificates of the local `UntrustedGuardian` it is encrypted for can be exported as password protected pfx files. To restore, import the pfx files into the `Shielded VM Local Certificates` store of the new host and apply the key protector with `Set-VMKeyProtector -KeyProtector`. The backup is exported again when the key protector changes, and it is kept when the virtual machine is removed. Destroying the resource deletes the backup files. The ID is the path of the key protector file.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmTpmStateBackupTimeout),
+			Create: schema.DefaultTimeout(CreateVmTpmStateBackupTimeout),
+			Update: schema.DefaultTimeout(UpdateVmTpmStateBackupTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmTpmStateBackupTimeout),
+		},
+		CreateContext: resourceHyperVVmTpmStateBackupCreate,
+		ReadContext:   resourceHyperVVmTpmStateBackupRead,
+		UpdateContext: resourceHyperVVmTpmStateBackupUpdate,
+		DeleteContext: resourceHyperVVmTpmStateBackupDelete,
+		CustomizeDiff: customizeDiffForVmTpmStateBackup,
+
+		Schema: map[string]*schema.Schema{
+			"vm_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual machine whose key protector is backed up. Its virtual TPM must be enabled.",
+			},
+			"path": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the path of the file the key protector is exported to e.g. `\\\\backup\\share\\web_server.kp`. It should be on storage that outlives the HyperV host machine.",
+			},
+			"guardian_backup_path": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the folder the signing and encryption certificates of the `UntrustedGuardian` of the HyperV host machine are exported to, as `UntrustedGuardian-signing.pfx` and `UntrustedGuardian-encryption.pfx`. Without them the key protector can only be used on the host it was exported from. The certificates are shared by all the virtual machines of the host. When empty the certificates are not exported.",
+			},
+			"guardian_backup_password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				StateFunc:    api.HashSensitiveValue,
+				RequiredWith: []string{"guardian_backup_path"},
+				Description:  "Specifies the password that protects the pfx files of the guardian certificates. Only a hash of it is stored in the state.",
+			},
+			"up_to_date": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the backup holds the current key protector of the virtual machine and the certificates of the guardian are exported. A stale backup is exported again.",
+			},
+			"key_protector_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA256 hash of the backed up key protector.",
+			},
+			"guardian_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the guardian of the HyperV host machine, empty when the host has no local guardian.",
+			},
+			"guardian_signing_certificate_thumbprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The thumbprint of the signing certificate of the guardian.",
+			},
+			"guardian_encryption_certificate_thumbprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The thumbprint of the encryption certificate of the guardian.",
+			},
+		},
+	}
+}
+
+// customizeDiffForVmTpmStateBackup plans an update of a stale backup, so that the current key protector is exported
+// again over it
+func customizeDiffForVmTpmStateBackup(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if diff.Id() != "" && !diff.Get("up_to_date").(bool) {
+		return diff.SetNew("up_to_date", true)
+	}
+
+	return nil
+}
+
+func expandVmTpmStateBackupSettings(d *schema.ResourceData) (api.VmTpmStateBackupSettings, error) {
+	settings := api.VmTpmStateBackupSettings{
+		VmName:           (d.Get("vm_name")).(string),
+		Path:             (d.Get("path")).(string),
+		GuardianPath:     (d.Get("guardian_backup_path")).(string),
+		GuardianPassword: api.GetSensitiveValue(d, "guardian_backup_password"),
+	}
+
+	return settings, settings.Validate()
+}
+
+func resourceHyperVVmTpmStateBackupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vm tpm state backup: %#v", d)
+	c := meta.(api.Client)
+
+	settings, err := expandVmTpmStateBackupSettings(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmTpmStateBackup(ctx, settings.VmName, settings.Path, settings.GuardianPath)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", settings.Path, err))
+		}
+
+		if existing.BackupKeyProtectorHash != "" {
+			// the file may be the only backup of the key protector of another virtual machine, so it is not overwritten
+			return diag.FromErr(fmt.Errorf("A key protector backup already exists at %q - move it or choose another path for %q", settings.Path, "hyperv_vm_tpm_state_backup"))
+		}
+	}
+
+	err = c.CreateOrUpdateVmTpmStateBackup(ctx, settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(settings.Path)
+	log.Printf("[INFO][hyperv][create] created hyperv vm tpm state backup: %#v", d)
+
+	return resourceHyperVVmTpmStateBackupRead(ctx, d, meta)
+}
+
+func resourceHyperVVmTpmStateBackupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vm tpm state backup: %#v", d)
+	c := meta.(api.Client)
+
+	path := d.Id()
+	vmName := (d.Get("vm_name")).(string)
+	guardianPath := (d.Get("guardian_backup_path")).(string)
+
+	vmTpmStateBackup, err := c.GetVmTpmStateBackup(ctx, vmName, path, guardianPath)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vm tpm state backup: %+v", vmTpmStateBackup)
+
+	if vmTpmStateBackup.BackupKeyProtectorHash == "" {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vm tpm state backup as it does not exist: %#v", path)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("path", path); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("up_to_date", vmTpmStateBackup.IsUpToDate(guardianPath)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("key_protector_sha256", vmTpmStateBackup.BackupKeyProtectorHash); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("guardian_name", vmTpmStateBackup.GuardianName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("guardian_signing_certificate_thumbprint", vmTpmStateBackup.GuardianSigningThumbprint); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("guardian_encryption_certificate_thumbprint", vmTpmStateBackup.GuardianEncryptionThumbprint); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vm tpm state backup: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmTpmStateBackupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vm tpm state backup: %#v", d)
+	c := meta.(api.Client)
+
+	settings, err := expandVmTpmStateBackupSettings(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the certificates of the guardian are removed from the folder they are no longer exported to
+	if d.HasChange("guardian_backup_path") {
+		oldGuardianPath, _ := d.GetChange("guardian_backup_path")
+		if oldGuardianPath.(string) != "" {
+			err = c.DeleteVmTpmStateBackup(ctx, "", oldGuardianPath.(string))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	err = c.CreateOrUpdateVmTpmStateBackup(ctx, settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vm tpm state backup: %#v", d)
+
+	return resourceHyperVVmTpmStateBackupRead(ctx, d, meta)
+}
+
+func resourceHyperVVmTpmStateBackupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vm tpm state backup: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteVmTpmStateBackup(ctx, d.Id(), (d.Get("guardian_backup_path")).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vm tpm state backup: %#v", d)
+	return nil
+}
@@ -0,0 +1,146 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+	hyperv_winrm "github.com/taliesins/terraform-provider-hyperv/api/hyperv-winrm"
+)
+
+type setTestAccVmKeyProtectorArgs struct {
+	VmName string
+}
+
+// setTestAccVmKeyProtectorTemplate gives the virtual machine a new local key protector, which enables its virtual TPM
+// the first time and changes the key protector every time after
+var setTestAccVmKeyProtectorTemplate = template.Must(template.New("SetTestAccVmKeyProtector").Parse(`
+$ErrorActionPreference = 'Stop'
+Import-Module Hyper-V
+Set-VMKeyProtector -VMName '{{.VmName}}' -NewLocalKeyProtector
+Enable-VMTPM -VMName '{{.VmName}}'
+`))
+
+func TestHyperVResourceVmTpmStateBackup(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("vm")
+	path := testAccPath("key_protector.kp")
+	guardianPath := testAccPath("guardian")
+
+	// the hash of the key protector that was backed up first, so that the backup after the key protector changes can be
+	// told apart from it
+	var keyProtectorHash string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckDestroy("hyperv_vm_tpm_state_backup", func(ctx context.Context, c api.Client, id string) (bool, error) {
+				vmTpmStateBackup, err := c.GetVmTpmStateBackup(ctx, "", id, guardianPath)
+				return vmTpmStateBackup.BackupKeyProtectorHash != "" || vmTpmStateBackup.GuardianBackupExists, err
+			}),
+			testAccCheckHyperVMachineInstanceDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmTpmStateBackupConfig(name, "", ""),
+			},
+			{
+				PreConfig: testAccSetVmKeyProtector(t, name),
+				Config:    testHyperVResourceVmTpmStateBackupConfig(name, path, guardianPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_tpm_state_backup.this", "id", path),
+					resource.TestCheckResourceAttr("hyperv_vm_tpm_state_backup.this", "up_to_date", "true"),
+					resource.TestCheckResourceAttr("hyperv_vm_tpm_state_backup.this", "guardian_name", "UntrustedGuardian"),
+					resource.TestCheckResourceAttrSet("hyperv_vm_tpm_state_backup.this", "guardian_signing_certificate_thumbprint"),
+					resource.TestCheckResourceAttrSet("hyperv_vm_tpm_state_backup.this", "guardian_encryption_certificate_thumbprint"),
+					testAccCheckHyperVVmTpmStateBackupKeyProtector("hyperv_vm_tpm_state_backup.this", &keyProtectorHash, false),
+				),
+			},
+			{
+				// the changed key protector leaves the backup stale, so it is exported again
+				PreConfig: testAccSetVmKeyProtector(t, name),
+				Config:    testHyperVResourceVmTpmStateBackupConfig(name, path, guardianPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vm_tpm_state_backup.this", "up_to_date", "true"),
+					testAccCheckHyperVVmTpmStateBackupKeyProtector("hyperv_vm_tpm_state_backup.this", &keyProtectorHash, true),
+				),
+			},
+		},
+	})
+}
+
+// testAccSetVmKeyProtector gives the virtual machine a new key protector, as the provider does not manage the virtual
+// TPM of a virtual machine
+func testAccSetVmKeyProtector(t *testing.T, vmName string) func() {
+	return func() {
+		c, err := testAccProviderClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = c.(*hyperv_winrm.ClientConfig).WinRmClient.RunFireAndForgetScript(context.Background(), setTestAccVmKeyProtectorTemplate, setTestAccVmKeyProtectorArgs{
+			VmName: vmName,
+		})
+		if err != nil {
+			t.Fatalf("unable to set key protector of vm %s: %s", vmName, err)
+		}
+	}
+}
+
+// testAccCheckHyperVVmTpmStateBackupKeyProtector keeps the hash of the backed up key protector in keyProtectorHash, and
+// when changed is set checks that it differs from the hash that was kept before
+func testAccCheckHyperVVmTpmStateBackupKeyProtector(resourceName string, keyProtectorHash *string, changed bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		backupKeyProtectorHash := rs.Primary.Attributes["key_protector_sha256"]
+		if backupKeyProtectorHash == "" {
+			return fmt.Errorf("expected key protector of %s to be backed up", resourceName)
+		}
+
+		if changed && backupKeyProtectorHash == *keyProtectorHash {
+			return fmt.Errorf("expected changed key protector of %s to be exported again, got the previous backup %s", resourceName, backupKeyProtectorHash)
+		}
+
+		*keyProtectorHash = backupKeyProtectorHash
+		return nil
+	}
+}
+
+func testHyperVResourceVmTpmStateBackupConfig(name string, path string, guardianPath string) string {
+	vmTpmStateBackup := ""
+	if path != "" {
+		vmTpmStateBackup = fmt.Sprintf(`
+resource "hyperv_vm_tpm_state_backup" "this" {
+	vm_name                  = hyperv_machine_instance.this.name
+	path                     = "%s"
+	guardian_backup_path     = "%s"
+	guardian_backup_password = "P@ssw0rd"
+}
+`, escapeForHcl(path), escapeForHcl(guardianPath))
+	}
+
+	return fmt.Sprintf(`
+resource "hyperv_machine_instance" "this" {
+	name       = "%s"
+	generation = 2
+	state      = "Off"
+}
+%s
+	`, escapeForHcl(name), vmTpmStateBackup)
+}
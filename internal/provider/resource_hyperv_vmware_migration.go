@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVmwareMigrationTimeout   = 1 * time.Minute
+	CreateVmwareMigrationTimeout = 120 * time.Minute
+	UpdateVmwareMigrationTimeout = 1 * time.Minute
+	DeleteVmwareMigrationTimeout = 5 * time.Minute
+)
+
+func resourceHyperVVmwareMigration() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to migrate a virtual machine of VMware to a HyperV host machine. The disks of an ova, an ovf or a single vmdk are converted to dynamic vhdx files with `qemu-img` or StarWind V2V Converter, whichever is installed on the HyperV host machine, and the firmware, processors, memory and network adapters of the source are mapped to their HyperV equivalents. The result is a ready definition for `hyperv_machine_instance`: use `generation`, `processor_count` and `memory_startup_bytes` for the virtual machine, and `manifest_json` to attach the converted disks and network adapters. The settings of a vmdk are read from the vmx next to it. Destroying the resource deletes the converted disks, so the virtual machine should be destroyed first. The ID is the path of the source.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVmwareMigrationTimeout),
+			Create: schema.DefaultTimeout(CreateVmwareMigrationTimeout),
+			Update: schema.DefaultTimeout(UpdateVmwareMigrationTimeout),
+			Delete: schema.DefaultTimeout(DeleteVmwareMigrationTimeout),
+		},
+		CreateContext: resourceHyperVVmwareMigrationCreate,
+		ReadContext:   resourceHyperVVmwareMigrationRead,
+		UpdateContext: resourceHyperVVmwareMigrationUpdate,
+		DeleteContext: resourceHyperVVmwareMigrationDelete,
+		CustomizeDiff: customizeDiffForVmwareMigration,
+
+		Schema: map[string]*schema.Schema{
+			"source_path": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the path of the virtual machine of VMware to migrate on the HyperV host machine e.g. `C:\\Vmware\\web_server.ova`. Valid values to use are the path of an `ova`, an `ovf` or a `vmdk`.",
+			},
+			"destination_path": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: IsWindowsPath(),
+				DiffSuppressFunc: api.DiffSuppressWindowsPath,
+				Description:      "Specifies the folder the disks are converted to e.g. `C:\\Hyper-V\\Virtual Hard Disks`. A converted disk is named after the disk of the source with a `vhdx` extension, and the migration fails rather than overwrite a disk that already exists.",
+			},
+			"converter": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          api.DiskConverter_name[api.DiskConverter_Auto],
+				ValidateDiagFunc: stringKeyInMap(api.DiskConverter_value, true),
+				Description:      "Specifies the tool that converts the disks. Valid values to use are `Auto`, `QemuImg` and `StarWind`. `Auto` uses `qemu-img` when it is installed and StarWind V2V Converter otherwise.",
+			},
+			"converter_path": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          "",
+				ValidateDiagFunc: IsWindowsPath(),
+				Description:      "Specifies the path of the executable of the `converter` on the HyperV host machine, for when it is not installed in its default location or on the path e.g. `C:\\Tools\\qemu-img.exe`.",
+			},
+			"switch_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Specifies the name of the switch the network adapters in `manifest_json` are connected to. When empty the network adapters are not connected.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the virtual machine of VMware.",
+			},
+			"firmware": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The firmware of the virtual machine of VMware, `Bios` or `Uefi`.",
+			},
+			"generation": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The generation of the HyperV virtual machine that boots with the firmware of the virtual machine of VMware, `1` for `Bios` and `2` for `Uefi`.",
+			},
+			"processor_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of virtual processors of the virtual machine of VMware.",
+			},
+			"memory_startup_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The amount of memory of the virtual machine of VMware.",
+			},
+			"network_adapter_names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the network adapters of the virtual machine of VMware.",
+			},
+			"hard_disk_paths": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The paths of the converted disks, in the order of the disks of the virtual machine of VMware. The first one is the boot disk.",
+			},
+			"manifest_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A machine specification document for the `manifest_json` of `hyperv_machine_instance` with the converted disks and the network adapters. A generation 1 virtual machine boots from its ide controller, so the first disk is attached to it and the other disks to the scsi controller.",
+			},
+		},
+	}
+}
+
+// customizeDiffForVmwareMigration plans a new manifest_json when the switch of its network adapters changes
+func customizeDiffForVmwareMigration(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if diff.Id() != "" && diff.HasChange("switch_name") {
+		return diff.SetNewComputed("manifest_json")
+	}
+
+	return nil
+}
+
+func expandVmwareMigrationSettings(d *schema.ResourceData) (api.VmwareMigrationSettings, error) {
+	settings := api.VmwareMigrationSettings{
+		SourcePath:      (d.Get("source_path")).(string),
+		DestinationPath: (d.Get("destination_path")).(string),
+		Converter:       api.ToDiskConverter((d.Get("converter")).(string)),
+		ConverterPath:   (d.Get("converter_path")).(string),
+	}
+
+	return settings, settings.Validate()
+}
+
+func expandVmwareMigration(d *schema.ResourceData) api.VmwareMigration {
+	networkAdapterNames := make([]string, 0)
+	for _, networkAdapterName := range (d.Get("network_adapter_names")).([]interface{}) {
+		networkAdapterNames = append(networkAdapterNames, networkAdapterName.(string))
+	}
+
+	hardDiskPaths := make([]string, 0)
+	for _, hardDiskPath := range (d.Get("hard_disk_paths")).([]interface{}) {
+		hardDiskPaths = append(hardDiskPaths, hardDiskPath.(string))
+	}
+
+	return api.VmwareMigration{
+		Name:                (d.Get("name")).(string),
+		Firmware:            (d.Get("firmware")).(string),
+		ProcessorCount:      int64((d.Get("processor_count")).(int)),
+		MemoryStartupBytes:  int64((d.Get("memory_startup_bytes")).(int)),
+		NetworkAdapterNames: networkAdapterNames,
+		HardDiskPaths:       hardDiskPaths,
+	}
+}
+
+func resourceHyperVVmwareMigrationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vmware migration: %#v", d)
+	c := meta.(api.Client)
+
+	settings, err := expandVmwareMigrationSettings(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the converted disks are checked for while converting, as their names are only known once the source is read
+	vmwareMigration, err := c.CreateVmwareMigration(ctx, settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(settings.SourcePath)
+
+	if err := d.Set("name", vmwareMigration.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("firmware", vmwareMigration.Firmware); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("processor_count", vmwareMigration.ProcessorCount); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("memory_startup_bytes", vmwareMigration.MemoryStartupBytes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("network_adapter_names", vmwareMigration.NetworkAdapterNames); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("hard_disk_paths", vmwareMigration.HardDiskPaths); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][create] created hyperv vmware migration: %#v", d)
+
+	return resourceHyperVVmwareMigrationRead(ctx, d, meta)
+}
+
+func resourceHyperVVmwareMigrationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vmware migration: %#v", d)
+	c := meta.(api.Client)
+
+	// the source is not read again, as it is often removed once it has been migrated
+	vmwareMigration := expandVmwareMigration(d)
+
+	hardDiskPaths, err := c.GetVmwareMigrationHardDiskPaths(ctx, vmwareMigration.HardDiskPaths)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vmware migration hard disk paths: %+v", hardDiskPaths)
+
+	if len(hardDiskPaths) != len(vmwareMigration.HardDiskPaths) {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vmware migration as its converted disks do not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	manifestJson, err := vmwareMigration.ManifestJson((d.Get("switch_name")).(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("source_path", d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("generation", vmwareMigration.Generation()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("manifest_json", manifestJson); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vmware migration: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVmwareMigrationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vmware migration: %#v", d)
+
+	// only switch_name can change without converting the disks again, and it only changes manifest_json
+	log.Printf("[INFO][hyperv][update] updated hyperv vmware migration: %#v", d)
+
+	return resourceHyperVVmwareMigrationRead(ctx, d, meta)
+}
+
+func resourceHyperVVmwareMigrationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vmware migration: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteVmwareMigration(ctx, expandVmwareMigration(d).HardDiskPaths)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vmware migration: %#v", d)
+	return nil
+}
@@ -0,0 +1,132 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+func TestHyperVResourceVmwareMigration(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	switchName := testAccName("switch")
+	sourcePath := os.Getenv("HYPERV_TEST_VMWARE_SOURCE")
+	destinationPath := testAccPath("vmware")
+	t.Cleanup(func() { _ = os.RemoveAll(destinationPath) })
+
+	// the converted disks are only known from the state, so they are kept to check that destroy removes them
+	var hardDiskPaths []string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_VMWARE_SOURCE")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy: testAccCheckDestroy("hyperv_vmware_migration", func(ctx context.Context, c api.Client, id string) (bool, error) {
+			existingHardDiskPaths, err := c.GetVmwareMigrationHardDiskPaths(ctx, hardDiskPaths)
+			return len(existingHardDiskPaths) > 0, err
+		}),
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVmwareMigrationConfig(switchName, sourcePath, destinationPath, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vmware_migration.this", "source_path", sourcePath),
+					resource.TestCheckResourceAttr("hyperv_vmware_migration.this", "destination_path", destinationPath),
+					resource.TestCheckResourceAttrSet("hyperv_vmware_migration.this", "name"),
+					resource.TestCheckResourceAttrSet("hyperv_vmware_migration.this", "generation"),
+					resource.TestCheckResourceAttrSet("hyperv_vmware_migration.this", "processor_count"),
+					resource.TestCheckResourceAttrSet("hyperv_vmware_migration.this", "memory_startup_bytes"),
+					resource.TestCheckResourceAttrSet("hyperv_vmware_migration.this", "hard_disk_paths.0"),
+					resource.TestCheckResourceAttrSet("hyperv_vmware_migration.this", "manifest_json"),
+					testAccCheckHyperVVmwareMigrationHardDiskPaths("hyperv_vmware_migration.this", destinationPath, &hardDiskPaths),
+				),
+			},
+			{
+				Config: testHyperVResourceVmwareMigrationConfig(switchName, sourcePath, destinationPath, "hyperv_network_switch.this.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vmware_migration.this", "switch_name", switchName),
+					testAccCheckHyperVVmwareMigrationManifestSwitch("hyperv_vmware_migration.this", switchName),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckHyperVVmwareMigrationHardDiskPaths checks that the disks are converted to the destination path and
+// keeps their paths
+func testAccCheckHyperVVmwareMigrationHardDiskPaths(resourceName string, destinationPath string, hardDiskPaths *[]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		count, err := strconv.Atoi(rs.Primary.Attributes["hard_disk_paths.#"])
+		if err != nil {
+			return err
+		}
+
+		*hardDiskPaths = []string{}
+		for i := 0; i < count; i++ {
+			hardDiskPath := rs.Primary.Attributes[fmt.Sprintf("hard_disk_paths.%d", i)]
+			if !strings.HasPrefix(strings.ToLower(hardDiskPath), strings.ToLower(destinationPath)) {
+				return fmt.Errorf("expected disk %s to be converted to %s", hardDiskPath, destinationPath)
+			}
+
+			*hardDiskPaths = append(*hardDiskPaths, hardDiskPath)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckHyperVVmwareMigrationManifestSwitch checks that the network adapters in manifest_json are connected to
+// the switch
+func testAccCheckHyperVVmwareMigrationManifestSwitch(resourceName string, switchName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.Attributes["network_adapter_names.#"] != "0" && !strings.Contains(rs.Primary.Attributes["manifest_json"], fmt.Sprintf(`"switch_name":"%s"`, switchName)) {
+			return fmt.Errorf("expected network adapters of manifest_json to be connected to %s, got %s", switchName, rs.Primary.Attributes["manifest_json"])
+		}
+
+		return nil
+	}
+}
+
+func testHyperVResourceVmwareMigrationConfig(switchName string, sourcePath string, destinationPath string, switchNameReference string) string {
+	switchNameAttribute := ""
+	if switchNameReference != "" {
+		switchNameAttribute = fmt.Sprintf("switch_name      = %s", switchNameReference)
+	}
+
+	return fmt.Sprintf(`
+resource "hyperv_network_switch" "this" {
+	name        = "%s"
+	switch_type = "Private"
+}
+
+resource "hyperv_vmware_migration" "this" {
+	source_path      = "%s"
+	destination_path = "%s"
+	%s
+}
+	`, escapeForHcl(switchName), escapeForHcl(sourcePath), escapeForHcl(destinationPath), switchNameAttribute)
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVSwitchExtensionTimeout   = 1 * time.Minute
+	CreateVSwitchExtensionTimeout = 2 * time.Minute
+	UpdateVSwitchExtensionTimeout = 2 * time.Minute
+	DeleteVSwitchExtensionTimeout = 2 * time.Minute
+)
+
+var vSwitchExtensionIdFormat = []string{"<switch_name>", "<extension_name>"}
+
+func resourceHyperVVSwitchExtension() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to enable or disable an extension (e.g. `Microsoft NDIS Capture`, `Microsoft Azure VFP Switch Extension` or third-party firewalls) on a virtual network switch. When the resource is destroyed the extension is disabled. It can be imported with an id in the format `<switch_name>|<extension_name>` e.g. `lan|Microsoft NDIS Capture`.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVSwitchExtensionTimeout),
+			Create: schema.DefaultTimeout(CreateVSwitchExtensionTimeout),
+			Update: schema.DefaultTimeout(UpdateVSwitchExtensionTimeout),
+			Delete: schema.DefaultTimeout(DeleteVSwitchExtensionTimeout),
+		},
+		CreateContext: resourceHyperVVSwitchExtensionCreate,
+		ReadContext:   resourceHyperVVSwitchExtensionRead,
+		UpdateContext: resourceHyperVVSwitchExtensionUpdate,
+		DeleteContext: resourceHyperVVSwitchExtensionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"switch_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the virtual network switch the extension belongs to.",
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the switch extension.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies if the switch extension should be enabled on the virtual network switch.",
+			},
+			"extension_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique identifier of the switch extension.",
+			},
+			"vendor": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The vendor of the switch extension.",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the switch extension.",
+			},
+			"extension_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the switch extension. Possible values are `Capture`, `Filter`, `Forward` and `Monitoring`.",
+			},
+			"running": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Specifies if the switch extension is running.",
+			},
+		},
+	}
+}
+
+func getVSwitchExtensionId(switchName string, name string) string {
+	return getVmDeviceId(switchName, name)
+}
+
+func parseVSwitchExtensionId(id string) (switchName string, name string, err error) {
+	parts, err := parseVmDeviceId("switch extension", id, vSwitchExtensionIdFormat...)
+	if err != nil {
+		return "", "", err
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func resourceHyperVVSwitchExtensionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv switch extension: %#v", d)
+	c := meta.(api.Client)
+
+	switchName := (d.Get("switch_name")).(string)
+	name := (d.Get("name")).(string)
+	enabled := (d.Get("enabled")).(bool)
+
+	extension, err := c.GetVMSwitchExtension(ctx, switchName, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if extension.Name == "" {
+		return diag.Errorf("[ERROR][hyperv][create] switch extension %q is not available on switch %q", name, switchName)
+	}
+
+	err = c.UpdateVMSwitchExtension(ctx, switchName, name, enabled)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getVSwitchExtensionId(switchName, name))
+	log.Printf("[INFO][hyperv][create] created hyperv switch extension: %#v", d)
+
+	return resourceHyperVVSwitchExtensionRead(ctx, d, meta)
+}
+
+func resourceHyperVVSwitchExtensionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv switch extension: %#v", d)
+	c := meta.(api.Client)
+
+	switchName, name, err := parseVSwitchExtensionId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	extension, err := c.GetVMSwitchExtension(ctx, switchName, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved switch extension: %+v", extension)
+
+	if !strings.EqualFold(extension.Name, name) {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv switch extension as it does not exist: %#v", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("switch_name", switchName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("name", extension.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("enabled", extension.Enabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("extension_id", extension.Id); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("vendor", extension.Vendor); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("version", extension.Version); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("extension_type", extension.ExtensionType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("running", extension.Running); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv switch extension: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVSwitchExtensionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv switch extension: %#v", d)
+	c := meta.(api.Client)
+
+	switchName, name, err := parseVSwitchExtensionId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	enabled := (d.Get("enabled")).(bool)
+
+	if d.HasChange("enabled") {
+		err = c.UpdateVMSwitchExtension(ctx, switchName, name, enabled)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv switch extension: %#v", d)
+
+	return resourceHyperVVSwitchExtensionRead(ctx, d, meta)
+}
+
+func resourceHyperVVSwitchExtensionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv switch extension: %#v", d)
+	c := meta.(api.Client)
+
+	switchName, name, err := parseVSwitchExtensionId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	switchExists, err := c.VMSwitchExists(ctx, switchName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if switchExists.Exists {
+		err = c.UpdateVMSwitchExtension(ctx, switchName, name, false)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv switch extension: %#v", d)
+	return nil
+}
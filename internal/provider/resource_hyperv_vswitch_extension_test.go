@@ -0,0 +1,55 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceVSwitchExtension(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("switch")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVSwitchExtensionConfig(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vswitch_extension.this", "switch_name", name),
+					resource.TestCheckResourceAttr("hyperv_vswitch_extension.this", "enabled", "true"),
+				),
+			},
+			{
+				Config: testHyperVResourceVSwitchExtensionConfig(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vswitch_extension.this", "enabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVSwitchExtensionConfig(name string, enabled bool) string {
+	return fmt.Sprintf(`
+resource "hyperv_network_switch" "this" {
+	name        = "%s"
+	switch_type = "Internal"
+}
+
+resource "hyperv_vswitch_extension" "this" {
+	switch_name = hyperv_network_switch.this.name
+	name        = "Microsoft NDIS Capture"
+	enabled     = %t
+}
+	`, escapeForHcl(name), enabled)
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadVSwitchNatDnsForwardingTimeout   = 1 * time.Minute
+	CreateVSwitchNatDnsForwardingTimeout = 10 * time.Minute
+	UpdateVSwitchNatDnsForwardingTimeout = 2 * time.Minute
+	DeleteVSwitchNatDnsForwardingTimeout = 2 * time.Minute
+)
+
+func resourceHyperVVSwitchNatDnsForwarding() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to forward the DNS queries of the virtual machines on an internal or NAT switch with the Windows DNS Server role on the HyperV host machine, so guests resolve external names with the gateway address of the NAT as their DNS server, e.g. handed out with the `dns_servers` of a `hyperv_dhcp_server_scope`, without configuring a resolver in every guest. The DNS server only answers queries on the host network adapter of the switch. The DNS Server role is installed when it is missing, which requires Windows Server. The ID is the switch name.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadVSwitchNatDnsForwardingTimeout),
+			Create: schema.DefaultTimeout(CreateVSwitchNatDnsForwardingTimeout),
+			Update: schema.DefaultTimeout(UpdateVSwitchNatDnsForwardingTimeout),
+			Delete: schema.DefaultTimeout(DeleteVSwitchNatDnsForwardingTimeout),
+		},
+		CreateContext: resourceHyperVVSwitchNatDnsForwardingCreate,
+		ReadContext:   resourceHyperVVSwitchNatDnsForwardingRead,
+		UpdateContext: resourceHyperVVSwitchNatDnsForwardingUpdate,
+		DeleteContext: resourceHyperVVSwitchNatDnsForwardingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"switch_name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the internal switch whose virtual machines the DNS queries are forwarded for, e.g. the `switch_name` of a `hyperv_nat_network`. Its host network adapter must have an IPv4 address.",
+			},
+			"forwarders": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: IsIPAddress()},
+				Description: "Specifies the DNS servers the queries are forwarded to e.g. `1.1.1.1`. When not set the DNS servers the HyperV host machine uses itself are used, like internet connection sharing does. The forwarders are a setting of the DNS server, so they are shared by all the switches it forwards the queries of, and they are left in place when the resource is destroyed.",
+			},
+			"forwarding_timeout": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          3,
+				ValidateDiagFunc: IntBetween(1, 15),
+				Description:      "Specifies the number of seconds to wait for a forwarder to answer before the next one is asked.",
+			},
+			"listen_addresses": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IPv4 addresses of the host network adapter of the switch the DNS server answers queries on, i.e. the DNS server to use in the guests.",
+			},
+		},
+	}
+}
+
+func expandVSwitchNatDnsForwarding(d *schema.ResourceData) api.VmSwitchNatDnsForwarding {
+	forwarders := make([]string, 0)
+	for _, forwarder := range (d.Get("forwarders")).([]interface{}) {
+		forwarders = append(forwarders, forwarder.(string))
+	}
+
+	return api.VmSwitchNatDnsForwarding{
+		SwitchName:        (d.Get("switch_name")).(string),
+		Forwarders:        forwarders,
+		ForwardingTimeout: (d.Get("forwarding_timeout")).(int),
+	}
+}
+
+func resourceHyperVVSwitchNatDnsForwardingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv vswitch nat dns forwarding: %#v", d)
+	c := meta.(api.Client)
+
+	vmSwitchNatDnsForwarding := expandVSwitchNatDnsForwarding(d)
+	switchName := vmSwitchNatDnsForwarding.SwitchName
+
+	if d.IsNewResource() {
+		existing, err := c.GetVmSwitchNatDnsForwarding(ctx, switchName)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", switchName, err))
+		}
+
+		if existing.IsListeningOnSwitch() {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", switchName, "hyperv_vswitch_nat_dns_forwarding", "hyperv_vswitch_nat_dns_forwarding", switchName))
+		}
+	}
+
+	err := c.CreateOrUpdateVmSwitchNatDnsForwarding(ctx, vmSwitchNatDnsForwarding)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(switchName)
+	log.Printf("[INFO][hyperv][create] created hyperv vswitch nat dns forwarding: %#v", d)
+
+	return resourceHyperVVSwitchNatDnsForwardingRead(ctx, d, meta)
+}
+
+func resourceHyperVVSwitchNatDnsForwardingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv vswitch nat dns forwarding: %#v", d)
+	c := meta.(api.Client)
+
+	switchName := d.Id()
+
+	vmSwitchNatDnsForwarding, err := c.GetVmSwitchNatDnsForwarding(ctx, switchName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved vswitch nat dns forwarding: %+v", vmSwitchNatDnsForwarding)
+
+	if !vmSwitchNatDnsForwarding.IsListeningOnSwitch() {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv vswitch nat dns forwarding as the dns server does not listen on the switch: %#v", switchName)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("switch_name", vmSwitchNatDnsForwarding.SwitchName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("forwarders", vmSwitchNatDnsForwarding.Forwarders); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("forwarding_timeout", vmSwitchNatDnsForwarding.ForwardingTimeout); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("listen_addresses", vmSwitchNatDnsForwarding.SwitchAddresses); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv vswitch nat dns forwarding: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVVSwitchNatDnsForwardingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv vswitch nat dns forwarding: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.CreateOrUpdateVmSwitchNatDnsForwarding(ctx, expandVSwitchNatDnsForwarding(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][update] updated hyperv vswitch nat dns forwarding: %#v", d)
+
+	return resourceHyperVVSwitchNatDnsForwardingRead(ctx, d, meta)
+}
+
+func resourceHyperVVSwitchNatDnsForwardingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv vswitch nat dns forwarding: %#v", d)
+	c := meta.(api.Client)
+
+	err := c.DeleteVmSwitchNatDnsForwarding(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv vswitch nat dns forwarding: %#v", d)
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+var testAccCheckHyperVVSwitchNatDnsForwardingDestroy = testAccCheckDestroy("hyperv_vswitch_nat_dns_forwarding", func(ctx context.Context, c api.Client, id string) (bool, error) {
+	vmSwitchNatDnsForwarding, err := c.GetVmSwitchNatDnsForwarding(ctx, id)
+	return vmSwitchNatDnsForwarding.IsListeningOnSwitch(), err
+})
+
+func TestHyperVResourceVSwitchNatDnsForwarding(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	name := testAccName("lab")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_HOST_SETTINGS")
+		},
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testAccCheckHyperVVSwitchNatDnsForwardingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceVSwitchNatDnsForwardingConfig(name, 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vswitch_nat_dns_forwarding.this", "switch_name", name),
+					resource.TestCheckResourceAttr("hyperv_vswitch_nat_dns_forwarding.this", "forwarders.0", "1.1.1.1"),
+					resource.TestCheckResourceAttr("hyperv_vswitch_nat_dns_forwarding.this", "listen_addresses.0", "192.168.252.1"),
+				),
+			},
+			{
+				Config: testHyperVResourceVSwitchNatDnsForwardingConfig(name, 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_vswitch_nat_dns_forwarding.this", "forwarding_timeout", "5"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceVSwitchNatDnsForwardingConfig(name string, forwardingTimeout int) string {
+	return fmt.Sprintf(`
+resource "hyperv_nat_network" "this" {
+	name            = "%s"
+	cidr            = "192.168.252.0/24"
+	gateway_address = "192.168.252.1"
+}
+
+resource "hyperv_vswitch_nat_dns_forwarding" "this" {
+	switch_name        = hyperv_nat_network.this.switch_name
+	forwarders         = ["1.1.1.1"]
+	forwarding_timeout = %d
+}
+	`, escapeForHcl(name), forwardingTimeout)
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/taliesins/terraform-provider-hyperv/api"
+)
+
+const (
+	ReadWindowsFeatureTimeout   = 2 * time.Minute
+	CreateWindowsFeatureTimeout = 60 * time.Minute
+	UpdateWindowsFeatureTimeout = 2 * time.Minute
+	DeleteWindowsFeatureTimeout = 60 * time.Minute
+
+	// how often to check whether the HyperV host machine is back after it was restarted
+	windowsFeatureRestartPollPeriod = 10
+)
+
+func resourceHyperVWindowsFeature() *schema.Resource {
+	return &schema.Resource{
+		Description: "This Hyper-V resource allows you to install a role or feature on the HyperV host machine, e.g. `Hyper-V`, `Hyper-V-PowerShell` or `FS-Data-Deduplication`, so that preparing the host can be part of the same configuration as the virtual machines it runs. Windows Server roles and features are installed with `Install-WindowsFeature`, on a Windows client the optional feature is enabled with `Enable-WindowsOptionalFeature` instead, e.g. `Microsoft-Hyper-V-All`. When the change needs a restart the HyperV host machine is restarted and the resource waits for it to come back, so make the resources that need the feature depend on it. Features are installed and uninstalled one at a time together with the restart they need, and a restart that is already pending is waited for instead of restarting the host again.",
+		Timeouts: &schema.ResourceTimeout{
+			Read:   schema.DefaultTimeout(ReadWindowsFeatureTimeout),
+			Create: schema.DefaultTimeout(CreateWindowsFeatureTimeout),
+			Update: schema.DefaultTimeout(UpdateWindowsFeatureTimeout),
+			Delete: schema.DefaultTimeout(DeleteWindowsFeatureTimeout),
+		},
+		CreateContext: resourceHyperVWindowsFeatureCreate,
+		ReadContext:   resourceHyperVWindowsFeatureRead,
+		UpdateContext: resourceHyperVWindowsFeatureUpdate,
+		DeleteContext: resourceHyperVWindowsFeatureDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: api.DiffSuppressCaseInsensitive,
+				Description:      "Specifies the name of the role or feature to install e.g. `Hyper-V`. Use `Get-WindowsFeature` on Windows Server or `Get-WindowsOptionalFeature -Online` on a Windows client to list the names.",
+			},
+			"include_all_sub_features": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Specifies whether to install the sub features of the feature as well. On a Windows client it enables the features the optional feature depends on.",
+			},
+			"include_management_tools": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Specifies whether to install the management tools of the feature as well e.g. the Hyper-V PowerShell module and Hyper-V Manager for `Hyper-V`. Only used on Windows Server.",
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "Specifies where to find the files of a feature whose files were removed from the HyperV host machine, e.g. `D:\\sources\\sxs` of the installation media or `wim:D:\\sources\\install.wim:1`. When empty the files are downloaded from Windows Update.",
+			},
+			"restart": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies whether to restart the HyperV host machine when installing or uninstalling the feature needs a restart, and wait for it to come back. When `false` the change is completed by the next restart of the HyperV host machine, and `restart_pending` is `true` until then.",
+			},
+			"restart_timeout": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          1800,
+				ValidateDiagFunc: IntBetween(60, 14400),
+				Description:      "Specifies the number of seconds to wait for the HyperV host machine to come back after it was restarted.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The display name of the feature.",
+			},
+			"install_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The install state of the feature, `Installed` or `InstallPending` when it is installed by the next restart.",
+			},
+			"restart_pending": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the HyperV host machine has to be restarted to complete the installation of the feature.",
+			},
+		},
+	}
+}
+
+// windowsFeatureMutex serializes installing or uninstalling a feature together with the restart of the HyperV host
+// machine it needs, so that a feature is never installed while the host is restarted for another one
+var windowsFeatureMutex sync.Mutex
+
+func restartHostForWindowsFeature(ctx context.Context, c api.Client, d *schema.ResourceData, windowsFeature api.WindowsFeature) (err error) {
+	if !windowsFeature.RestartNeeded || !(d.Get("restart")).(bool) {
+		return nil
+	}
+
+	log.Printf("[INFO][hyperv] restarting hyperv host machine to complete change of windows feature %s", windowsFeature.Name)
+
+	return c.RestartHost(ctx, uint32((d.Get("restart_timeout")).(int)), windowsFeatureRestartPollPeriod)
+}
+
+func resourceHyperVWindowsFeatureCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][create] creating hyperv windows feature: %#v", d)
+	c := meta.(api.Client)
+
+	name := (d.Get("name")).(string)
+	includeAllSubFeatures := (d.Get("include_all_sub_features")).(bool)
+	includeManagementTools := (d.Get("include_management_tools")).(bool)
+	source := (d.Get("source")).(string)
+
+	if d.IsNewResource() {
+		existing, err := c.GetWindowsFeature(ctx, name)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("checking for existing %s: %+v", name, err))
+		}
+
+		if existing.Name == "" {
+			return diag.FromErr(fmt.Errorf("[ERROR][hyperv] windows feature %s does not exist on the HyperV host machine", name))
+		}
+
+		if existing.Installed() {
+			return diag.FromErr(fmt.Errorf("A resource with the ID %q already exists - to be managed via Terraform this resource needs to be imported into the State. Please see the resource documentation for %q for more information.\n terraform import %s.<resource name> %s", existing.Name, "hyperv_windows_feature", "hyperv_windows_feature", existing.Name))
+		}
+	}
+
+	windowsFeatureMutex.Lock()
+	defer windowsFeatureMutex.Unlock()
+
+	windowsFeature, err := c.InstallWindowsFeature(ctx, name, includeAllSubFeatures, includeManagementTools, source)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(windowsFeature.Name)
+
+	err = restartHostForWindowsFeature(ctx, c, d, windowsFeature)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][create] created hyperv windows feature: %#v", d)
+
+	return resourceHyperVWindowsFeatureRead(ctx, d, meta)
+}
+
+func resourceHyperVWindowsFeatureRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][read] reading hyperv windows feature: %#v", d)
+	c := meta.(api.Client)
+
+	name := d.Id()
+
+	windowsFeature, err := c.GetWindowsFeature(ctx, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] retrieved windows feature: %+v", windowsFeature)
+
+	if !windowsFeature.Installed() {
+		log.Printf("[INFO][hyperv][read] unable to read hyperv windows feature as it is not installed: %#v", name)
+		d.SetId("")
+		return nil
+	}
+
+	if err := d.Set("name", windowsFeature.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("display_name", windowsFeature.DisplayName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("install_state", windowsFeature.InstallState.String()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("restart_pending", windowsFeature.InstallState == api.WindowsFeatureInstallState_InstallPending); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][read] read hyperv windows feature: %#v", d)
+
+	return nil
+}
+
+func resourceHyperVWindowsFeatureUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][update] updating hyperv windows feature: %#v", d)
+
+	// only restart and restart_timeout can change, they are used the next time the feature is installed or uninstalled
+
+	log.Printf("[INFO][hyperv][update] updated hyperv windows feature: %#v", d)
+
+	return resourceHyperVWindowsFeatureRead(ctx, d, meta)
+}
+
+func resourceHyperVWindowsFeatureDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO][hyperv][delete] deleting hyperv windows feature: %#v", d)
+	c := meta.(api.Client)
+
+	windowsFeatureMutex.Lock()
+	defer windowsFeatureMutex.Unlock()
+
+	windowsFeature, err := c.UninstallWindowsFeature(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = restartHostForWindowsFeature(ctx, c, d, windowsFeature)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO][hyperv][delete] deleted hyperv windows feature: %#v", d)
+	return nil
+}
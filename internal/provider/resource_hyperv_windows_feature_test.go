@@ -0,0 +1,45 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestHyperVResourceWindowsFeature(t *testing.T) {
+	// Skip if -short flag exist
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckEnv(t, "HYPERV_TEST_HOST_SETTINGS")
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testHyperVResourceWindowsFeatureConfig("Telnet-Client"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hyperv_windows_feature.this", "name", "Telnet-Client"),
+					resource.TestCheckResourceAttr("hyperv_windows_feature.this", "install_state", "Installed"),
+					resource.TestCheckResourceAttr("hyperv_windows_feature.this", "restart_pending", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testHyperVResourceWindowsFeatureConfig(name string) string {
+	return fmt.Sprintf(`
+resource "hyperv_windows_feature" "this" {
+	name    = "%s"
+	restart = false
+}
+	`, escapeForHcl(name))
+}
@@ -3,7 +3,9 @@ package provider
 import (
 	"fmt"
 	"math"
+	"net"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/go-cty/cty"
@@ -164,3 +166,187 @@ func IsDivisibleBy(logicalSize int) schema.SchemaValidateDiagFunc {
 		return diags
 	}
 }
+
+func IsCIDR() schema.SchemaValidateDiagFunc {
+	return func(i interface{}, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+
+		v, ok := i.(string)
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected type of %s to be string", i),
+			})
+
+			return diags
+		}
+
+		if _, _, err := net.ParseCIDR(v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected %s to be a valid CIDR, got error %v", v, err),
+			})
+		}
+
+		return diags
+	}
+}
+
+func IsIPAddress() schema.SchemaValidateDiagFunc {
+	return func(i interface{}, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+
+		v, ok := i.(string)
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected type of %s to be string", i),
+			})
+
+			return diags
+		}
+
+		if ip := net.ParseIP(v); ip == nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected %s to be a valid IP address", v),
+			})
+		}
+
+		return diags
+	}
+}
+
+func IsValidRegExp() schema.SchemaValidateDiagFunc {
+	return func(i interface{}, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+
+		v, ok := i.(string)
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected type of %s to be string", i),
+			})
+
+			return diags
+		}
+
+		if _, err := regexp.Compile(v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected %s to be a valid regular expression, got error %v", v, err),
+			})
+		}
+
+		return diags
+	}
+}
+
+func StringMatch(r *regexp.Regexp, message string) schema.SchemaValidateDiagFunc {
+	return func(i interface{}, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+
+		v, ok := i.(string)
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected type of %s to be string", i),
+			})
+
+			return diags
+		}
+
+		if !r.MatchString(v) {
+			if message == "" {
+				message = fmt.Sprintf("expected value to match regular expression %q", r)
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("invalid value %q: %s", v, message),
+			})
+		}
+
+		return diags
+	}
+}
+
+// windowsPathInvalidCharacters are the characters that can not be part of a windows path, the colon is only valid
+// after the drive letter
+const windowsPathInvalidCharacters = `<>:"|?*`
+
+// IsWindowsPath validates an absolute windows path on the HyperV host machine, either on a drive e.g. `C:\VMs` or on
+// a share e.g. `\\server\share\VMs`, so mistakes are caught during plan rather than half way through an apply.
+// Empty values are valid so optional paths can be left unset.
+func IsWindowsPath() schema.SchemaValidateDiagFunc {
+	return func(i interface{}, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+
+		v, ok := i.(string)
+		if !ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("expected type of %s to be string", i),
+			})
+
+			return diags
+		}
+
+		if v == "" {
+			return diags
+		}
+
+		if err := validateWindowsPath(v); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("invalid windows path %q: %s", v, err),
+				AttributePath: path,
+			})
+		}
+
+		return diags
+	}
+}
+
+func validateWindowsPath(v string) error {
+	if strings.Contains(v, "/") {
+		return fmt.Errorf("use backslashes to separate the folders of the path e.g. `%s`", strings.ReplaceAll(v, "/", "\\"))
+	}
+
+	var rest string
+	switch {
+	case strings.HasPrefix(v, `\\`):
+		// a UNC path needs at least a server and a share e.g. \\server\share
+		parts := strings.SplitN(strings.TrimPrefix(v, `\\`), `\`, 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("a UNC path must start with the server and share e.g. `\\\\server\\share\\VMs`")
+		}
+		if parts[0] == "?" || parts[0] == "." {
+			return fmt.Errorf("device paths are not supported, use a drive or UNC path instead")
+		}
+		rest = strings.Join(parts, `\`)
+	case len(v) >= 3 && isDriveLetter(v[0]) && v[1] == ':' && v[2] == '\\':
+		rest = v[3:]
+	case len(v) == 2 && isDriveLetter(v[0]) && v[1] == ':':
+		return fmt.Errorf("a drive must be followed by a backslash e.g. `%s`", v+`\`)
+	default:
+		return fmt.Errorf("the path must be absolute, starting with a drive e.g. `C:\\VMs` or a UNC share e.g. `\\\\server\\share\\VMs`")
+	}
+
+	for _, character := range rest {
+		if character < 32 || strings.ContainsRune(windowsPathInvalidCharacters, character) {
+			return fmt.Errorf("the path contains the invalid character %q", character)
+		}
+	}
+
+	for _, folder := range strings.Split(rest, `\`) {
+		if strings.HasSuffix(folder, " ") || (strings.HasSuffix(folder, ".") && folder != "." && folder != "..") {
+			return fmt.Errorf("the folder or file name %q must not end with a space or a period", folder)
+		}
+	}
+
+	return nil
+}
+
+func isDriveLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// stateUpgraderV0 returns the upgrader of the state that was written before the schema of a resource was versioned.
+// addedAttributes are the attributes that were added to the resource since, with the names of the blocks they are
+// nested in separated by dots e.g. `network_adaptors.preserve_mac_on_recreate`. The state of version 0 is described by
+// the schema without them, and the upgrade sets the ones that have a default to it, so that upgrading the provider
+// does not plan a change for every attribute that was added.
+func stateUpgraderV0(resourceType string, resource *schema.Resource, addedAttributes ...string) schema.StateUpgrader {
+	schemaV0 := resource.Schema
+	for _, addedAttribute := range addedAttributes {
+		schemaV0 = withoutAttribute(schemaV0, strings.Split(addedAttribute, "."))
+	}
+
+	return schema.StateUpgrader{
+		Version: 0,
+		Type:    (&schema.Resource{Schema: schemaV0}).CoreConfigSchema().ImpliedType(),
+		Upgrade: func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			log.Printf("[INFO][hyperv][upgrade] upgrading state of %s from version 0: %#v", resourceType, rawState)
+
+			for _, addedAttribute := range addedAttributes {
+				setStateDefault(resource.Schema, rawState, strings.Split(addedAttribute, "."))
+			}
+
+			log.Printf("[INFO][hyperv][upgrade] upgraded state of %s from version 0: %#v", resourceType, rawState)
+			return rawState, nil
+		},
+	}
+}
+
+// withoutAttribute returns a copy of schemaMap without the attribute at path, the blocks on the path are copied too
+func withoutAttribute(schemaMap map[string]*schema.Schema, path []string) map[string]*schema.Schema {
+	attributeSchema, ok := schemaMap[path[0]]
+	if !ok {
+		return schemaMap
+	}
+
+	result := make(map[string]*schema.Schema, len(schemaMap))
+	for name, s := range schemaMap {
+		result[name] = s
+	}
+
+	if len(path) == 1 {
+		delete(result, path[0])
+		return result
+	}
+
+	block, ok := attributeSchema.Elem.(*schema.Resource)
+	if !ok {
+		return schemaMap
+	}
+
+	blockSchema := *attributeSchema
+	blockSchema.Elem = &schema.Resource{Schema: withoutAttribute(block.Schema, path[1:])}
+	result[path[0]] = &blockSchema
+
+	return result
+}
+
+// setStateDefault sets the attribute at path to its default when the state does not have it, in every block on the path
+func setStateDefault(schemaMap map[string]*schema.Schema, rawState map[string]interface{}, path []string) {
+	attributeSchema, ok := schemaMap[path[0]]
+	if !ok {
+		return
+	}
+
+	value, ok := rawState[path[0]]
+
+	if len(path) == 1 {
+		if (!ok || value == nil) && attributeSchema.Default != nil {
+			rawState[path[0]] = attributeSchema.Default
+		}
+
+		return
+	}
+
+	block, ok := attributeSchema.Elem.(*schema.Resource)
+	if !ok {
+		return
+	}
+
+	blocks, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, blockState := range blocks {
+		if blockState, ok := blockState.(map[string]interface{}); ok {
+			setStateDefault(block.Schema, blockState, path[1:])
+		}
+	}
+}
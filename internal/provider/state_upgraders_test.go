@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestStateUpgraderV0(t *testing.T) {
+	resource := resourceHyperVNetworkSwitch()
+	rawState := map[string]interface{}{
+		"id":                  "wan",
+		"name":                "wan",
+		"switch_type":         "External",
+		"allow_management_os": true,
+		"net_adapter_names":   []interface{}{"Ethernet"},
+	}
+
+	upgradedState, err := resource.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedState := map[string]interface{}{
+		"name":                  "wan",
+		"allow_management_os":   true,
+		"recreate_in_place":     false,
+		"force":                 false,
+		"enable_packet_capture": false,
+		"teaming_mode":          "SwitchIndependent",
+	}
+	for name, expected := range expectedState {
+		if upgradedState[name] != expected {
+			t.Errorf("expected %s to be %#v, got %#v", name, expected, upgradedState[name])
+		}
+	}
+
+	// attributes without a default are left for the read after the upgrade
+	if _, ok := upgradedState["load_balancing_algorithm"]; ok {
+		t.Errorf("expected load_balancing_algorithm not to be set, got %#v", upgradedState["load_balancing_algorithm"])
+	}
+}
+
+func TestStateUpgraderV0Blocks(t *testing.T) {
+	resource := resourceHyperVMachineInstance()
+	rawState := map[string]interface{}{
+		"id":   "web_server",
+		"name": "web_server",
+		"network_adaptors": []interface{}{
+			map[string]interface{}{"name": "wan", "switch_name": "wan"},
+			map[string]interface{}{"name": "lan", "switch_name": "lan"},
+		},
+		"vm_processor": []interface{}{
+			map[string]interface{}{"maximum_count_per_numa_node": 0},
+		},
+	}
+
+	upgradedState, err := resource.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, networkAdaptor := range upgradedState["network_adaptors"].([]interface{}) {
+		networkAdaptor := networkAdaptor.(map[string]interface{})
+		if networkAdaptor["preserve_mac_on_recreate"] != false {
+			t.Errorf("expected preserve_mac_on_recreate of %s to be false, got %#v", networkAdaptor["name"], networkAdaptor["preserve_mac_on_recreate"])
+		}
+	}
+
+	vmProcessor := upgradedState["vm_processor"].([]interface{})[0].(map[string]interface{})
+	if vmProcessor["enable_legacy_apic_mode"] != false || vmProcessor["l3_cache_ways"] != 0 {
+		t.Errorf("expected vm processor settings to be set to their defaults, got %#v", vmProcessor)
+	}
+
+	if upgradedState["checkpoint_before_update"] != false || upgradedState["delete_seed_iso_on_destroy"] != true || upgradedState["deletion_protection"] != false {
+		t.Errorf("expected machine instance settings to be set to their defaults, got %#v", upgradedState)
+	}
+}
+
+// TestStateUpgraderV0Type checks that the state of version 0 is described by the current schema without the added
+// attributes
+func TestStateUpgraderV0Type(t *testing.T) {
+	for resourceType, resource := range map[string]*schema.Resource{
+		"hyperv_vhd":              resourceHyperVVhd(),
+		"hyperv_dvd":              resourceHyperVDvd(),
+		"hyperv_machine_instance": resourceHyperVMachineInstance(),
+		"hyperv_network_switch":   resourceHyperVNetworkSwitch(),
+	} {
+		if resource.SchemaVersion != 1 || len(resource.StateUpgraders) != 1 {
+			t.Errorf("expected %s to upgrade state from version 0 to 1", resourceType)
+			continue
+		}
+
+		typeV0 := resource.StateUpgraders[0].Type
+		currentType := resource.CoreConfigSchema().ImpliedType()
+		if typeV0.Equals(currentType) {
+			t.Errorf("expected state of version 0 of %s to be described without the added attributes", resourceType)
+		}
+
+		for name := range currentType.AttributeTypes() {
+			if typeV0.HasAttribute(name) && !strings.HasPrefix(name, "timeouts") && !attributeTypeContains(currentType.AttributeType(name), typeV0.AttributeType(name)) {
+				t.Errorf("expected %s of version 0 of %s to be a subset of the current %s", name, resourceType, name)
+			}
+		}
+	}
+}
+
+// attributeTypeContains reports whether the attributes of previousType are all in currentType
+func attributeTypeContains(currentType cty.Type, previousType cty.Type) bool {
+	if currentType.IsListType() || currentType.IsSetType() {
+		if !previousType.IsListType() && !previousType.IsSetType() {
+			return false
+		}
+
+		return attributeTypeContains(currentType.ElementType(), previousType.ElementType())
+	}
+
+	if !currentType.IsObjectType() {
+		return currentType.Equals(previousType)
+	}
+
+	if !previousType.IsObjectType() {
+		return false
+	}
+
+	for name, attributeType := range previousType.AttributeTypes() {
+		if !currentType.HasAttribute(name) || !attributeTypeContains(currentType.AttributeType(name), attributeType) {
+			return false
+		}
+	}
+
+	return true
+}
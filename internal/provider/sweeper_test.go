@@ -0,0 +1,167 @@
+//go:build integration
+// +build integration
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// Sweepers remove everything left behind on the HyperV host machine by failed acceptance test runs, they only touch
+// things named with testAccNamePrefix. Run them with:
+//
+//	go test -tags integration ./internal/provider -v -sweep=local
+func init() {
+	resource.AddTestSweepers("hyperv_machine_instance", &resource.Sweeper{
+		Name: "hyperv_machine_instance",
+		F:    sweepMachineInstances,
+	})
+
+	resource.AddTestSweepers("hyperv_network_switch", &resource.Sweeper{
+		Name:         "hyperv_network_switch",
+		F:            sweepNetworkSwitches,
+		Dependencies: []string{"hyperv_machine_instance"},
+	})
+
+	resource.AddTestSweepers("hyperv_vhd", &resource.Sweeper{
+		Name:         "hyperv_vhd",
+		F:            sweepVhds,
+		Dependencies: []string{"hyperv_machine_instance"},
+	})
+
+	resource.AddTestSweepers("hyperv_dvd", &resource.Sweeper{
+		Name:         "hyperv_dvd",
+		F:            sweepDvds,
+		Dependencies: []string{"hyperv_machine_instance"},
+	})
+}
+
+func isSweepable(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), testAccNamePrefix)
+}
+
+func sweepMachineInstances(_ string) error {
+	ctx := context.Background()
+	c, err := testAccProviderClient()
+	if err != nil {
+		return err
+	}
+
+	vmInfos, err := c.GetVmInfos(ctx)
+	if err != nil {
+		return fmt.Errorf("[ERROR][hyperv][sweep] unable to list vms: %s", err)
+	}
+
+	for _, vmInfo := range vmInfos {
+		if !isSweepable(vmInfo.Name) {
+			continue
+		}
+
+		log.Printf("[INFO][hyperv][sweep] deleting vm: %s", vmInfo.Name)
+		err = c.DeleteVm(ctx, vmInfo.Name)
+		if err != nil {
+			return fmt.Errorf("[ERROR][hyperv][sweep] unable to delete vm %s: %s", vmInfo.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func sweepNetworkSwitches(_ string) error {
+	ctx := context.Background()
+	c, err := testAccProviderClient()
+	if err != nil {
+		return err
+	}
+
+	vmSwitches, err := c.GetVMSwitches(ctx)
+	if err != nil {
+		return fmt.Errorf("[ERROR][hyperv][sweep] unable to list switches: %s", err)
+	}
+
+	for _, vmSwitch := range vmSwitches {
+		if !isSweepable(vmSwitch.Name) {
+			continue
+		}
+
+		log.Printf("[INFO][hyperv][sweep] deleting switch: %s", vmSwitch.Name)
+		err = c.DisconnectVMSwitchNetworkAdapters(ctx, vmSwitch.Name)
+		if err != nil {
+			return fmt.Errorf("[ERROR][hyperv][sweep] unable to disconnect network adapters from switch %s: %s", vmSwitch.Name, err)
+		}
+
+		err = c.DeleteVMSwitch(ctx, vmSwitch.Name)
+		if err != nil {
+			return fmt.Errorf("[ERROR][hyperv][sweep] unable to delete switch %s: %s", vmSwitch.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// sweepablePaths returns the files created by the acceptance tests with testAccPath
+func sweepablePaths(extensions ...string) ([]string, error) {
+	paths := make([]string, 0)
+	for _, extension := range extensions {
+		matches, err := filepath.Glob(filepath.Join(testAccDirectory(), testAccNamePrefix+"_*"+extension))
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	return paths, nil
+}
+
+func sweepVhds(_ string) error {
+	ctx := context.Background()
+	c, err := testAccProviderClient()
+	if err != nil {
+		return err
+	}
+
+	paths, err := sweepablePaths(".vhd", ".vhdx", ".avhdx")
+	if err != nil {
+		return fmt.Errorf("[ERROR][hyperv][sweep] unable to list vhds: %s", err)
+	}
+
+	for _, path := range paths {
+		log.Printf("[INFO][hyperv][sweep] deleting vhd: %s", path)
+		err = c.DeleteVhd(ctx, path)
+		if err != nil {
+			return fmt.Errorf("[ERROR][hyperv][sweep] unable to delete vhd %s: %s", path, err)
+		}
+	}
+
+	return nil
+}
+
+func sweepDvds(_ string) error {
+	ctx := context.Background()
+	c, err := testAccProviderClient()
+	if err != nil {
+		return err
+	}
+
+	paths, err := sweepablePaths(".iso")
+	if err != nil {
+		return fmt.Errorf("[ERROR][hyperv][sweep] unable to list isos: %s", err)
+	}
+
+	for _, path := range paths {
+		log.Printf("[INFO][hyperv][sweep] deleting iso: %s", path)
+		err = c.DeleteDvd(ctx, path)
+		if err != nil {
+			return fmt.Errorf("[ERROR][hyperv][sweep] unable to delete iso %s: %s", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// vmDeviceIdSeparator separates the parts of the id of a virtual machine device. A pipe is used as it is far less
+// likely than a slash to be part of the name of a virtual machine.
+const vmDeviceIdSeparator = "|"
+
+func getVmDeviceId(parts ...interface{}) string {
+	stringParts := make([]string, 0)
+	for _, part := range parts {
+		stringParts = append(stringParts, fmt.Sprintf("%v", part))
+	}
+
+	return strings.Join(stringParts, vmDeviceIdSeparator)
+}
+
+// parseVmDeviceId splits the id of a virtual machine device into as many parts as the format has, so the error can
+// tell users what to pass to terraform import
+func parseVmDeviceId(resourceType string, id string, format ...string) ([]string, error) {
+	parts := strings.Split(id, vmDeviceIdSeparator)
+	if len(parts) == len(format) {
+		valid := true
+		for _, part := range parts {
+			if part == "" {
+				valid = false
+			}
+		}
+
+		if valid {
+			return parts, nil
+		}
+	}
+
+	return nil, fmt.Errorf("[ERROR][hyperv] unable to parse %s id %q - expected format is `%s`", resourceType, id, strings.Join(format, vmDeviceIdSeparator))
+}
+
+// vmDeviceFromResourceData returns the attributes of a virtual machine device resource in the same form as a block
+// of the device in hyperv_machine_instance, so both can be expanded the same way
+func vmDeviceFromResourceData(d *schema.ResourceData, schemaMap map[string]*schema.Schema) map[string]interface{} {
+	vmDevice := make(map[string]interface{})
+	for key := range schemaMap {
+		vmDevice[key] = d.Get(key)
+	}
+
+	return vmDevice
+}
+
+func setVmDeviceResourceData(d *schema.ResourceData, vmDevice map[string]interface{}) error {
+	for key, value := range vmDevice {
+		if err := d.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
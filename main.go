@@ -45,4 +45,6 @@ func main() {
 	}
 
 	plugin.Serve(opts)
+
+	provider.Cleanup()
 }